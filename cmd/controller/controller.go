@@ -53,6 +53,10 @@ func CmdMain() {
 		util.LogFatalAndExit(err, "failed to check permission")
 	}
 
+	var flowTrace *controller.FlowTraceHandler
+	if config.EnableFlowTrace {
+		flowTrace = controller.NewFlowTraceHandler(config)
+	}
 	go func() {
 		mux := http.NewServeMux()
 		if config.EnableMetrics {
@@ -65,6 +69,9 @@ func CmdMain() {
 			mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 			mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 		}
+		if flowTrace != nil {
+			mux.Handle("/api/v1/trace", flowTrace)
+		}
 
 		addr := "0.0.0.0"
 		if os.Getenv("ENABLE_BIND_LOCAL_IP") == "true" {
@@ -114,7 +121,7 @@ func CmdMain() {
 		RetryPeriod:   6 * time.Second,
 		Callbacks: leaderelection.LeaderCallbacks{
 			OnStartedLeading: func(ctx context.Context) {
-				controller.Run(ctx, config)
+				controller.Run(ctx, config, flowTrace)
 			},
 			OnStoppedLeading: func() {
 				select {
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeovn/kube-ovn/pkg/natgw"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+	"github.com/kubeovn/kube-ovn/versions"
+)
+
+func main() {
+	klog.Infof(versions.String())
+
+	port := pflag.Int32("port", natgw.DefaultPort, "The port nat gateway agent listens on.")
+	script := pflag.String("script", natgw.DefaultScript, "The script programming the nat gateway rules.")
+	iface := pflag.String("external-interface", natgw.DefaultInterface, "The external interface of the nat gateway.")
+	backend := pflag.String("firewall-backend", util.FirewallBackendIptables, "The backend programming the nat rules, iptables or nftables.")
+
+	klogFlags := flag.NewFlagSet("klog", flag.ExitOnError)
+	klog.InitFlags(klogFlags)
+
+	// Sync the glog and klog flags.
+	pflag.CommandLine.VisitAll(func(f1 *pflag.Flag) {
+		f2 := klogFlags.Lookup(f1.Name)
+		if f2 != nil {
+			value := f1.Value.String()
+			if err := f2.Value.Set(value); err != nil {
+				util.LogFatalAndExit(err, "failed to set flag")
+			}
+		}
+	})
+
+	pflag.CommandLine.AddGoFlagSet(klogFlags)
+	pflag.CommandLine.AddGoFlagSet(flag.CommandLine)
+	pflag.Parse()
+
+	if _, err := os.Stat(*script); err != nil {
+		util.LogFatalAndExit(err, "failed to find script %s", *script)
+	}
+
+	if *backend != util.FirewallBackendIptables && *backend != util.FirewallBackendNftables {
+		util.LogFatalAndExit(nil, "unsupported firewall backend %q", *backend)
+	}
+
+	agent := natgw.NewAgent(*script, *iface, *backend)
+	if err := natgw.RunServer(util.JoinHostPort("127.0.0.1", *port), agent); err != nil {
+		util.LogFatalAndExit(err, "failed to run nat gateway agent server")
+	}
+}
@@ -55,6 +55,12 @@ func CmdMain() {
 			}()
 		}
 	}
+	if config.Mode == "server" && config.EnableConnectivityCheck {
+		go pinger.StartConnectivityChecks(config)
+	}
+	if config.Mode == "server" && config.EnablePMTUCheck {
+		go pinger.StartPMTUChecks(config)
+	}
 	e := pinger.NewExporter(config)
 	pinger.StartPinger(config, e)
 }
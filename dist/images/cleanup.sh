@@ -142,7 +142,10 @@ kubectl delete --ignore-not-found crd \
   ovn-snat-rules.kubeovn.io \
   ovn-fips.kubeovn.io \
   ovn-eips.kubeovn.io \
-  qos-policies.kubeovn.io
+  qos-policies.kubeovn.io \
+  interconnections.kubeovn.io \
+  traffic-mirrors.kubeovn.io \
+  connectivity-checks.kubeovn.io
 
 # in case of ip not delete
 set +e
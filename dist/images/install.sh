@@ -21,6 +21,9 @@ CNI_CONFIG_PRIORITY=${CNI_CONFIG_PRIORITY:-01}
 ENABLE_LB_SVC=${ENABLE_LB_SVC:-false}
 ENABLE_NAT_GW=${ENABLE_NAT_GW:-false}
 ENABLE_KEEP_VM_IP=${ENABLE_KEEP_VM_IP:-true}
+ENABLE_LIVE_MIGRATION_OPTIMIZE=${ENABLE_LIVE_MIGRATION_OPTIMIZE:-true}
+ENABLE_OVN_BANDWIDTH_LIMIT=${ENABLE_OVN_BANDWIDTH_LIMIT:-false}
+ENABLE_OVSDB_CLIENT=${ENABLE_OVSDB_CLIENT:-false}
 ENABLE_ARP_DETECT_IP_CONFLICT=${ENABLE_ARP_DETECT_IP_CONFLICT:-true}
 NODE_LOCAL_DNS_IP=${NODE_LOCAL_DNS_IP:-}
 ENABLE_IC=${ENABLE_IC:-$(kubectl get node --show-labels | grep -qw "ovn.kubernetes.io/ic-gw" && echo true || echo false)}
@@ -35,6 +38,8 @@ IFACE=${IFACE:-}
 DPDK_TUNNEL_IFACE=${DPDK_TUNNEL_IFACE:-br-phy}
 ENABLE_BIND_LOCAL_IP=${ENABLE_BIND_LOCAL_IP:-true}
 ENABLE_TPROXY=${ENABLE_TPROXY:-false}
+FIREWALL_BACKEND=${FIREWALL_BACKEND:-iptables}
+ENABLE_LB_HEALTH_CHECK=${ENABLE_LB_HEALTH_CHECK:-false}
 OVS_VSCTL_CONCURRENCY=${OVS_VSCTL_CONCURRENCY:-100}
 ENABLE_COMPACT=${ENABLE_COMPACT:-false}
 
@@ -400,6 +405,15 @@ spec:
         - jsonPath: .spec.lanIp
           name: LanIP
           type: string
+        - jsonPath: .spec.replicas
+          name: Replicas
+          type: integer
+        - jsonPath: .spec.mode
+          name: Mode
+          type: string
+        - jsonPath: .status.activePod
+          name: ActivePod
+          type: string
       name: v1
       served: true
       storage: true
@@ -412,6 +426,34 @@ spec:
             status:
               type: object
               properties:
+                replicas:
+                  type: integer
+                mode:
+                  type: string
+                conntrack:
+                  type: object
+                  properties:
+                    max:
+                      type: integer
+                    tcpEstablishedTimeout:
+                      type: integer
+                    tcpTimeWaitTimeout:
+                      type: integer
+                    udpTimeout:
+                      type: integer
+                    udpStreamTimeout:
+                      type: integer
+                activePod:
+                  type: string
+                readyReplicas:
+                  type: array
+                  items:
+                    type: string
+                lastFailoverTime:
+                  type: string
+                  format: date-time
+                failoverDuration:
+                  type: string
                 externalSubnets:
                   items:
                     type: string
@@ -727,6 +769,32 @@ spec:
                   type: array
                 vpc:
                   type: string
+                replicas:
+                  type: integer
+                  minimum: 0
+                mode:
+                  type: string
+                  enum:
+                    - active-standby
+                    - ecmp
+                conntrack:
+                  type: object
+                  properties:
+                    max:
+                      type: integer
+                      minimum: 0
+                    tcpEstablishedTimeout:
+                      type: integer
+                      minimum: 0
+                    tcpTimeWaitTimeout:
+                      type: integer
+                      minimum: 0
+                    udpTimeout:
+                      type: integer
+                      minimum: 0
+                    udpStreamTimeout:
+                      type: integer
+                      minimum: 0
                 selector:
                   type: array
                   items:
@@ -1079,6 +1147,10 @@ spec:
                   type: string
                 qosPolicy:
                   type: string
+                hairpin:
+                  type: boolean
+                connLimit:
+                  type: integer
                 conditions:
                   type: array
                   items:
@@ -1111,6 +1183,13 @@ spec:
                   type: string
                 externalSubnet:
                   type: string
+                ipPool:
+                  type: string
+                hairpin:
+                  type: boolean
+                connLimit:
+                  type: integer
+                  minimum: 0
 ---
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -1194,6 +1273,10 @@ spec:
                   type: string
                 internalIp:
                   type: string
+                eipPool:
+                  type: string
+                natGwDp:
+                  type: string
 ---
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -1298,6 +1381,10 @@ spec:
                   type: string
                 internalPort:
                   type: string
+                eipPool:
+                  type: string
+                natGwDp:
+                  type: string
 ---
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -1381,6 +1468,10 @@ spec:
                   type: string
                 internalCIDR:
                   type: string
+                eipPool:
+                  type: string
+                natGwDp:
+                  type: string
 ---
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -1470,6 +1561,10 @@ spec:
                   type: string
                 macAddress:
                   type: string
+                ipPool:
+                  type: string
+                hairpin:
+                  type: boolean
 ---
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -1498,9 +1593,15 @@ spec:
       - jsonPath: .status.v4Eip
         name: V4Eip
         type: string
+      - jsonPath: .status.v6Eip
+        name: V6Eip
+        type: string
       - jsonPath: .status.v4Ip
         name: V4Ip
         type: string
+      - jsonPath: .status.v6Ip
+        name: V6Ip
+        type: string
       - jsonPath: .status.ready
         name: Ready
         type: boolean
@@ -1521,8 +1622,12 @@ spec:
                   type: boolean
                 v4Eip:
                   type: string
+                v6Eip:
+                  type: string
                 v4Ip:
                   type: string
+                v6Ip:
+                  type: string
                 vpc:
                   type: string
                 conditions:
@@ -1555,6 +1660,10 @@ spec:
                   type: string
                 v4Ip:
                   type: string
+                v6Ip:
+                  type: string
+                eipPool:
+                  type: string
 ---
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -1583,9 +1692,15 @@ spec:
       - jsonPath: .status.v4Eip
         name: V4Eip
         type: string
+      - jsonPath: .status.v6Eip
+        name: V6Eip
+        type: string
       - jsonPath: .status.v4IpCidr
         name: V4IpCidr
         type: string
+      - jsonPath: .status.v6IpCidr
+        name: V6IpCidr
+        type: string
       - jsonPath: .status.ready
         name: Ready
         type: boolean
@@ -1600,8 +1715,12 @@ spec:
                   type: boolean
                 v4Eip:
                   type: string
+                v6Eip:
+                  type: string
                 v4IpCidr:
                   type: string
+                v6IpCidr:
+                  type: string
                 vpc:
                   type: string
                 conditions:
@@ -1634,6 +1753,10 @@ spec:
                   type: string
                 v4IpCidr:
                   type: string
+                v6IpCidr:
+                  type: string
+                eipPool:
+                  type: string
 ---
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -1668,9 +1791,15 @@ spec:
         - jsonPath: .status.v4Eip
           name: V4Eip
           type: string
+        - jsonPath: .status.v6Eip
+          name: V6Eip
+          type: string
         - jsonPath: .status.v4Ip
           name: V4Ip
           type: string
+        - jsonPath: .status.v6Ip
+          name: V6Ip
+          type: string
         - jsonPath: .status.internalPort
           name: InternalPort
           type: string
@@ -1694,8 +1823,12 @@ spec:
                     type: boolean
                   v4Eip:
                     type: string
+                  v6Eip:
+                    type: string
                   v4Ip:
                     type: string
+                  v6Ip:
+                    type: string
                   vpc:
                     type: string
                   externalPort:
@@ -1742,6 +1875,10 @@ spec:
                     type: string
                   v4Ip:
                     type: string
+                  v6Ip:
+                    type: string
+                  eipPool:
+                    type: string
 ---
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -1826,6 +1963,20 @@ spec:
                         type: string
                     type: object
                   type: array
+                icPeerings:
+                  items:
+                    properties:
+                      remoteAz:
+                        type: string
+                      remoteVpc:
+                        type: string
+                      transitCIDR:
+                        type: string
+                    required:
+                      - remoteAz
+                      - remoteVpc
+                    type: object
+                  type: array
               type: object
             status:
               properties:
@@ -1870,6 +2021,25 @@ spec:
                   items:
                     type: string
                   type: array
+                icPeerings:
+                  items:
+                    properties:
+                      remoteAz:
+                        type: string
+                      remoteVpc:
+                        type: string
+                      transitSwitch:
+                        type: string
+                      nextHopIP:
+                        type: string
+                      learnedPrefixes:
+                        items:
+                          type: string
+                        type: array
+                      ready:
+                        type: boolean
+                    type: object
+                  type: array
                 tcpLoadBalancer:
                   type: string
                 tcpSessionLoadBalancer:
@@ -2748,6 +2918,10 @@ spec:
                   type: boolean
                 bindingType:
                   type: string
+                boundObjects:
+                  type: array
+                  items:
+                    type: string
                 bandwidthLimitRules:
                   type: array
                   items:
@@ -2769,6 +2943,10 @@ spec:
                         type: string
                       matchValue:
                         type: string
+                      protocol:
+                        type: string
+                      ports:
+                        type: string
                 conditions:
                   type: array
                   items:
@@ -2793,6 +2971,33 @@ spec:
                   type: boolean
                 bindingType:
                   type: string
+                selector:
+                  type: object
+                  properties:
+                    matchExpressions:
+                      type: array
+                      items:
+                        type: object
+                        properties:
+                          key:
+                            type: string
+                          operator:
+                            type: string
+                          values:
+                            type: array
+                            items:
+                              type: string
+                        required:
+                          - key
+                          - operator
+                    matchLabels:
+                      type: object
+                      additionalProperties:
+                        type: string
+                subnets:
+                  type: array
+                  items:
+                    type: string
                 bandwidthLimitRules:
                   type: array
                   items:
@@ -2814,11 +3019,540 @@ spec:
                         type: string
                       matchValue:
                         type: string
+                      protocol:
+                        type: string
+                      ports:
+                        type: string
+                    required:
+                      - name
+                  x-kubernetes-list-map-keys:
+                    - name
+                  x-kubernetes-list-type: map
+                dscpMarkingRules:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      name:
+                        type: string
+                      dscp:
+                        type: integer
+                        minimum: 0
+                        maximum: 63
+                      priority:
+                        type: integer
+                      direction:
+                        type: string
+                      matchType:
+                        type: string
+                      matchValue:
+                        type: string
+                      protocol:
+                        type: string
+                      ports:
+                        type: string
                     required:
                       - name
+                      - dscp
                   x-kubernetes-list-map-keys:
                     - name
                   x-kubernetes-list-type: map
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: interconnections.kubeovn.io
+spec:
+  group: kubeovn.io
+  names:
+    plural: interconnections
+    singular: interconnection
+    shortNames:
+      - ic
+    kind: InterConnection
+    listKind: InterConnectionList
+  scope: Cluster
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      subresources:
+        status: {}
+      additionalPrinterColumns:
+      - jsonPath: .spec.azName
+        name: AZ
+        type: string
+      - jsonPath: .spec.icDBHosts
+        name: ICDBHosts
+        type: string
+      - jsonPath: .spec.autoRoute
+        name: AutoRoute
+        type: boolean
+      - jsonPath: .status.conditions[?(@.type=="Ready")].status
+        name: Ready
+        type: string
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              required:
+                - azName
+                - icDBHosts
+                - gatewayNodes
+              properties:
+                azName:
+                  type: string
+                icDBHosts:
+                  type: array
+                  minItems: 1
+                  items:
+                    type: string
+                icNBPort:
+                  type: integer
+                  minimum: 1
+                  maximum: 65535
+                icSBPort:
+                  type: integer
+                  minimum: 1
+                  maximum: 65535
+                gatewayNodes:
+                  type: array
+                  minItems: 1
+                  items:
+                    type: string
+                tsCount:
+                  type: integer
+                  minimum: 0
+                  maximum: 156
+                autoRoute:
+                  type: boolean
+                routeImports:
+                  type: object
+                  additionalProperties:
+                    type: array
+                    items:
+                      type: string
+                routeTables:
+                  type: object
+                  additionalProperties:
+                    type: string
+            status:
+              type: object
+              properties:
+                gatewayChassis:
+                  type: array
+                  items:
+                    type: string
+                advertisedRoutes:
+                  type: array
+                  items:
+                    type: string
+                learnedRoutes:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      az:
+                        type: string
+                      prefix:
+                        type: string
+                      nextHop:
+                        type: string
+                      routeTable:
+                        type: string
+                conditions:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      type:
+                        type: string
+                      status:
+                        type: string
+                      reason:
+                        type: string
+                      message:
+                        type: string
+                      lastUpdateTime:
+                        type: string
+                      lastTransitionTime:
+                        type: string
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: traffic-mirrors.kubeovn.io
+spec:
+  group: kubeovn.io
+  names:
+    plural: traffic-mirrors
+    singular: traffic-mirror
+    shortNames:
+      - tm
+    kind: TrafficMirror
+    listKind: TrafficMirrorList
+  scope: Cluster
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      subresources:
+        status: {}
+      additionalPrinterColumns:
+      - jsonPath: .spec.type
+        name: Type
+        type: string
+      - jsonPath: .spec.remote
+        name: Remote
+        type: string
+      - jsonPath: .spec.direction
+        name: Direction
+        type: string
+      - jsonPath: .status.backend
+        name: Backend
+        type: string
+      - jsonPath: .status.conditions[?(@.type=="Ready")].status
+        name: Ready
+        type: string
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              required:
+                - selector
+                - direction
+                - type
+                - remote
+              properties:
+                namespaceSelector:
+                  type: object
+                  properties:
+                    matchExpressions:
+                      type: array
+                      items:
+                        type: object
+                        properties:
+                          key:
+                            type: string
+                          operator:
+                            type: string
+                          values:
+                            type: array
+                            items:
+                              type: string
+                        required:
+                          - key
+                          - operator
+                    matchLabels:
+                      type: object
+                      additionalProperties:
+                        type: string
+                selector:
+                  type: object
+                  properties:
+                    matchExpressions:
+                      type: array
+                      items:
+                        type: object
+                        properties:
+                          key:
+                            type: string
+                          operator:
+                            type: string
+                          values:
+                            type: array
+                            items:
+                              type: string
+                        required:
+                          - key
+                          - operator
+                    matchLabels:
+                      type: object
+                      additionalProperties:
+                        type: string
+                direction:
+                  type: string
+                  enum:
+                    - ingress
+                    - egress
+                    - both
+                type:
+                  type: string
+                  enum:
+                    - erspan
+                    - gre
+                    - vxlan
+                remote:
+                  type: string
+                key:
+                  type: integer
+                  minimum: 0
+                  maximum: 4294967295
+            status:
+              type: object
+              properties:
+                backend:
+                  type: string
+                nodes:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      node:
+                        type: string
+                      ports:
+                        type: array
+                        items:
+                          type: string
+                conditions:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      type:
+                        type: string
+                      status:
+                        type: string
+                      reason:
+                        type: string
+                      message:
+                        type: string
+                      lastUpdateTime:
+                        type: string
+                      lastTransitionTime:
+                        type: string
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: connectivity-checks.kubeovn.io
+spec:
+  group: kubeovn.io
+  names:
+    plural: connectivity-checks
+    singular: connectivity-check
+    shortNames:
+      - cc
+    kind: ConnectivityCheck
+    listKind: ConnectivityCheckList
+  scope: Cluster
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      subresources:
+        status: {}
+      additionalPrinterColumns:
+      - jsonPath: .spec.protocol
+        name: Protocol
+        type: string
+      - jsonPath: .spec.port
+        name: Port
+        type: integer
+      - jsonPath: .status.totalPairs
+        name: Pairs
+        type: integer
+      - jsonPath: .status.conditions[?(@.type=="Ready")].status
+        name: Ready
+        type: string
+      - jsonPath: .status.conditions[?(@.type=="Ready")].reason
+        name: Reason
+        type: string
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              required:
+                - destination
+                - protocol
+              properties:
+                source:
+                  type: object
+                  properties:
+                    nodeSelector:
+                      type: object
+                      properties:
+                        matchExpressions:
+                          type: array
+                          items:
+                            type: object
+                            properties:
+                              key:
+                                type: string
+                              operator:
+                                type: string
+                              values:
+                                type: array
+                                items:
+                                  type: string
+                            required:
+                              - key
+                              - operator
+                        matchLabels:
+                          type: object
+                          additionalProperties:
+                            type: string
+                destination:
+                  type: object
+                  properties:
+                    namespaceSelector:
+                      type: object
+                      properties:
+                        matchExpressions:
+                          type: array
+                          items:
+                            type: object
+                            properties:
+                              key:
+                                type: string
+                              operator:
+                                type: string
+                              values:
+                                type: array
+                                items:
+                                  type: string
+                            required:
+                              - key
+                              - operator
+                        matchLabels:
+                          type: object
+                          additionalProperties:
+                            type: string
+                    selector:
+                      type: object
+                      properties:
+                        matchExpressions:
+                          type: array
+                          items:
+                            type: object
+                            properties:
+                              key:
+                                type: string
+                              operator:
+                                type: string
+                              values:
+                                type: array
+                                items:
+                                  type: string
+                            required:
+                              - key
+                              - operator
+                        matchLabels:
+                          type: object
+                          additionalProperties:
+                            type: string
+                    subnets:
+                      type: array
+                      items:
+                        type: string
+                    services:
+                      type: array
+                      items:
+                        type: string
+                protocol:
+                  type: string
+                  enum:
+                    - icmp
+                    - tcp
+                    - udp
+                port:
+                  type: integer
+                  minimum: 0
+                  maximum: 65535
+                interval:
+                  type: integer
+                  minimum: 0
+                slo:
+                  type: object
+                  properties:
+                    maxLossPercent:
+                      type: integer
+                      minimum: 0
+                      maximum: 100
+                    maxLatency:
+                      type: integer
+                      minimum: 0
+            status:
+              type: object
+              properties:
+                nodes:
+                  type: object
+                  additionalProperties:
+                    type: object
+                    properties:
+                      lastCheckTime:
+                        type: string
+                      total:
+                        type: integer
+                      failing:
+                        type: integer
+                      latencyP50:
+                        type: string
+                      latencyP90:
+                        type: string
+                      latencyP99:
+                        type: string
+                      results:
+                        type: array
+                        items:
+                          type: object
+                          properties:
+                            destination:
+                              type: string
+                            target:
+                              type: string
+                            sent:
+                              type: integer
+                            received:
+                              type: integer
+                            latencyP50:
+                              type: string
+                            latencyP90:
+                              type: string
+                            latencyP99:
+                              type: string
+                            sloMet:
+                              type: boolean
+                            lastFailureTime:
+                              type: string
+                            lastFailureMessage:
+                              type: string
+                totalPairs:
+                  type: integer
+                failingPairs:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      source:
+                        type: string
+                      destination:
+                        type: string
+                      target:
+                        type: string
+                conditions:
+                  type: array
+                  items:
+                    type: object
+                    properties:
+                      type:
+                        type: string
+                      status:
+                        type: string
+                      reason:
+                        type: string
+                      message:
+                        type: string
+                      lastUpdateTime:
+                        type: string
+                      lastTransitionTime:
+                        type: string
 EOF
 
 cat <<EOF > ovn-ovs-sa.yaml
@@ -2931,6 +3665,12 @@ rules:
       - vpc-dnses/status
       - qos-policies
       - qos-policies/status
+      - interconnections
+      - interconnections/status
+      - traffic-mirrors
+      - traffic-mirrors/status
+      - connectivity-checks
+      - connectivity-checks/status
     verbs:
       - "*"
   - apiGroups:
@@ -2938,6 +3678,7 @@ rules:
     resources:
       - pods
       - pods/exec
+      - pods/portforward
       - namespaces
       - nodes
       - configmaps
@@ -2991,6 +3732,14 @@ rules:
       - get
       - list
       - watch
+  - apiGroups:
+      - discovery.k8s.io
+    resources:
+      - endpointslices
+    verbs:
+      - get
+      - list
+      - watch
   - apiGroups:
       - ""
     resources:
@@ -3013,6 +3762,23 @@ rules:
     verbs:
       - get
       - list
+  - apiGroups:
+      - "kubevirt.io"
+    resources:
+      - virtualmachineinstancemigrations
+    verbs:
+      - get
+      - list
+      - watch
+      - patch
+  - apiGroups:
+      - "apiextensions.k8s.io"
+    resources:
+      - customresourcedefinitions
+    resourceNames:
+      - virtualmachineinstancemigrations.kubevirt.io
+    verbs:
+      - get
 ---
 apiVersion: rbac.authorization.k8s.io/v1
 kind: ClusterRoleBinding
@@ -3051,6 +3817,7 @@ rules:
       - ovn-eips
       - ovn-eips/status
       - ips
+      - traffic-mirrors
     verbs:
       - get
       - list
@@ -3068,6 +3835,14 @@ rules:
       - list
       - patch
       - watch
+  - apiGroups:
+      - ""
+    resources:
+      - services
+    verbs:
+      - get
+      - list
+      - watch
   - apiGroups:
       - ""
     resources:
@@ -3114,6 +3889,7 @@ rules:
     verbs:
       - get
       - list
+      - watch
   - apiGroups:
       - ""
       - networking.k8s.io
@@ -3122,6 +3898,30 @@ rules:
       - daemonsets
     verbs:
       - get
+  - apiGroups:
+      - ""
+    resources:
+      - namespaces
+      - services
+    verbs:
+      - get
+      - list
+      - watch
+  - apiGroups:
+      - "kubeovn.io"
+    resources:
+      - connectivity-checks
+      - subnets
+    verbs:
+      - get
+      - list
+      - watch
+  - apiGroups:
+      - "kubeovn.io"
+    resources:
+      - connectivity-checks/status
+    verbs:
+      - patch
 ---
 apiVersion: rbac.authorization.k8s.io/v1
 kind: ClusterRoleBinding
@@ -3896,6 +4696,7 @@ metadata:
       kube-ovn vpc-nat common config
 data:
   image: $REGISTRY/$VPC_NAT_IMAGE:$VERSION
+  firewall-backend: $FIREWALL_BACKEND
 ---
 kind: ConfigMap
 apiVersion: v1
@@ -3987,6 +4788,8 @@ spec:
           - --log_file_max_size=0
           - --enable-lb-svc=$ENABLE_LB_SVC
           - --keep-vm-ip=$ENABLE_KEEP_VM_IP
+          - --enable-live-migration-optimize=$ENABLE_LIVE_MIGRATION_OPTIMIZE
+          - --enable-ovn-bandwidth-limit=$ENABLE_OVN_BANDWIDTH_LIMIT
           - --node-local-dns-ip=$NODE_LOCAL_DNS_IP
           env:
             - name: ENABLE_SSL
@@ -4114,6 +4917,8 @@ spec:
           - /kube-ovn/start-cniserver.sh
         args:
           - --enable-mirror=$ENABLE_MIRROR
+          - --enable-ovn-bandwidth-limit=$ENABLE_OVN_BANDWIDTH_LIMIT
+          - --enable-ovsdb-client=$ENABLE_OVSDB_CLIENT
           - --enable-arp-detect-ip-conflict=$ENABLE_ARP_DETECT_IP_CONFLICT
           - --encap-checksum=true
           - --service-cluster-ip-range=$SVC_CIDR
@@ -4128,6 +4933,8 @@ spec:
           - --log_file_max_size=0
           - --kubelet-dir=$KUBELET_DIR
           - --enable-tproxy=$ENABLE_TPROXY
+          - --firewall-backend=$FIREWALL_BACKEND
+          - --enable-lb-health-check=$ENABLE_LB_HEALTH_CHECK
           - --ovs-vsctl-concurrency=$OVS_VSCTL_CONCURRENCY
         securityContext:
           runAsUser: 0
@@ -4296,6 +5103,7 @@ spec:
           - --alsologtostderr=true
           - --log_file=/var/log/kube-ovn/kube-ovn-pinger.log
           - --log_file_max_size=0
+          - --tunnel-type=$TUNNEL_TYPE
           imagePullPolicy: $IMAGE_PULL_POLICY
           securityContext:
             runAsUser: 0
@@ -1,5 +1,11 @@
 #!/usr/bin/env bash
 
+HA_MODE_FILE=/var/run/kube-ovn-nat-gw-ha-mode
+HA_ARP_TABLE=kube_ovn_ha
+FIREWALL_BACKEND=${FIREWALL_BACKEND:-iptables}
+NFT_TABLE=kube-ovn-nat
+NFT_CONNLIMIT_TABLE=kube-ovn-connlimit
+
 function exec_cmd() {
     cmd=${@:1:${#}}
     $cmd
@@ -11,6 +17,14 @@ function exec_cmd() {
 }
 
 function check_inited() {
+    if [ "$FIREWALL_BACKEND" = "nftables" ]; then
+        nft list table ip $NFT_TABLE > /dev/null
+        if [ $? -ne 0 ]; then
+            echo "nat gateway not inited"
+            exit 1
+        fi
+        return
+    fi
     iptables-save -t nat | grep  SNAT_FILTER | grep SHARED_SNAT
     if [ $? -ne 0 ]; then
         echo "nat gateway not inited"
@@ -18,7 +32,63 @@ function check_inited() {
     fi
 }
 
+function init_nftables() {
+    # the rules are programmed by nat-gw-agent, which replaces the whole table atomically
+    nft -f - <<EOF
+table ip $NFT_TABLE {
+    chain exclusive-dnat {
+    }
+    chain exclusive-snat {
+    }
+    chain shared-dnat {
+    }
+    chain shared-snat {
+    }
+    chain hairpin-mark {
+    }
+    chain hairpin-snat {
+        oifname "eth0" meta mark & 0x4000 == 0x4000 masquerade
+    }
+    chain connlimit {
+        type filter hook forward priority filter; policy accept;
+    }
+    chain eip-connlimit {
+        type filter hook postrouting priority srcnat + 1; policy accept;
+    }
+    chain dnat-filter {
+        type nat hook prerouting priority dstnat; policy accept;
+        jump hairpin-mark
+        jump exclusive-dnat
+        jump shared-dnat
+    }
+    chain snat-filter {
+        type nat hook postrouting priority srcnat; policy accept;
+        jump hairpin-snat
+        jump exclusive-snat
+        jump shared-snat
+    }
+}
+EOF
+}
+
 function init() {
+    if [ "$FIREWALL_BACKEND" = "nftables" ]; then
+        # run once is enough
+        nft list table ip $NFT_TABLE > /dev/null 2>&1 && exit 0
+        ip link set net1 up
+        ip link set dev net1 arp off
+        exec_cmd "init_nftables"
+        for rule in $@
+        do
+            arr=(${rule//,/ })
+            cidr=${arr[0]}
+            nextHop=${arr[1]}
+
+            exec_cmd "ip route replace $cidr via $nextHop dev eth0"
+        done
+        return
+    fi
+
     # run once is enough
     iptables-save | grep DNAT_FILTER && exit 0
     # add static chain
@@ -50,6 +120,123 @@ function init() {
 
         exec_cmd "ip route replace $cidr via $nextHop dev eth0"
     done
+    ensure_hairpin_chains
+}
+
+function ensure_hairpin_chains() {
+    # the connections from the vpc to the eips are marked before dnat, and masqueraded
+    # when they are sent back to the vpc so that the replies return through the gateway
+    iptables -t nat -L HAIRPIN_MARK > /dev/null 2>&1 && return
+    exec_cmd "iptables -t nat -N HAIRPIN_MARK"
+    exec_cmd "iptables -t nat -N HAIRPIN_SNAT"
+    exec_cmd "iptables -t nat -A HAIRPIN_SNAT -o eth0 -m mark --mark 0x4000/0x4000 -j MASQUERADE"
+    exec_cmd "iptables -t nat -I DNAT_FILTER -j HAIRPIN_MARK"
+    exec_cmd "iptables -t nat -I SNAT_FILTER -j HAIRPIN_SNAT"
+}
+
+
+function ensure_connlimit_chain() {
+    # the new connections are limited when forwarded
+    iptables -t filter -L CONNLIMIT > /dev/null 2>&1 && return
+    exec_cmd "iptables -t filter -N CONNLIMIT"
+    exec_cmd "iptables -t filter -I FORWARD -j CONNLIMIT"
+}
+
+function set_conntrack() {
+    # make sure inited
+    check_inited
+    for rule in $@
+    do
+        arr=(${rule//,/ })
+        max=${arr[0]}
+        tcpEstablishedTimeout=${arr[1]}
+        tcpTimeWaitTimeout=${arr[2]}
+        udpTimeout=${arr[3]}
+        udpStreamTimeout=${arr[4]}
+        # the timeouts set to 0 keep the current values
+        if [ "$tcpEstablishedTimeout" -gt 0 ]; then
+            exec_cmd "sysctl -w net.netfilter.nf_conntrack_tcp_timeout_established=$tcpEstablishedTimeout"
+        fi
+        if [ "$tcpTimeWaitTimeout" -gt 0 ]; then
+            exec_cmd "sysctl -w net.netfilter.nf_conntrack_tcp_timeout_time_wait=$tcpTimeWaitTimeout"
+        fi
+        if [ "$udpTimeout" -gt 0 ]; then
+            exec_cmd "sysctl -w net.netfilter.nf_conntrack_udp_timeout=$udpTimeout"
+        fi
+        if [ "$udpStreamTimeout" -gt 0 ]; then
+            exec_cmd "sysctl -w net.netfilter.nf_conntrack_udp_timeout_stream=$udpStreamTimeout"
+        fi
+        if [ "$FIREWALL_BACKEND" = "nftables" ]; then
+            # the limit is programmed by nat-gw-agent
+            continue
+        fi
+
+        # nf_conntrack_max is read only in the network namespace of the pod,
+        # so the connections forwarded by the gateway are limited instead
+        ensure_connlimit_chain
+        iptables-save -t filter | grep "^-A CONNLIMIT" | grep -v "ctorigdst" | sed 's/-A //' | while read ruleMatch
+        do
+            exec_cmd "iptables -t filter -D $ruleMatch"
+        done
+        if [ "$max" -gt 0 ]; then
+            exec_cmd "iptables -t filter -A CONNLIMIT -m conntrack --ctstate NEW -m connlimit --connlimit-above $max --connlimit-mask 0 -j DROP"
+        fi
+    done
+}
+
+function ensure_eip_connlimit_chain() {
+    # the limits of the eips are applied after snat so that the connections snated to the eips
+    # are matched by the reply destination, which is not possible with iptables
+    nft list chain ip $NFT_CONNLIMIT_TABLE eip-connlimit > /dev/null 2>&1 && return
+    exec_cmd "nft add table ip $NFT_CONNLIMIT_TABLE"
+    exec_cmd "nft add chain ip $NFT_CONNLIMIT_TABLE eip-connlimit { type filter hook postrouting priority srcnat + 1; policy accept; }"
+}
+
+function del_eip_connlimit() {
+    local eip=$1
+    # the limit matching the original destination is left by the previous versions
+    ruleMatch=$(iptables-save -t filter | grep "^-A CONNLIMIT" | grep -w "ctorigdst $eip")
+    if [ "$?" -eq 0 ];then
+        ruleMatch=$(echo $ruleMatch | sed 's/-A //')
+        exec_cmd "iptables -t filter -D $ruleMatch"
+    fi
+    nft list chain ip $NFT_CONNLIMIT_TABLE connlimit-$eip > /dev/null 2>&1 || return
+    for handle in $(nft -a list chain ip $NFT_CONNLIMIT_TABLE eip-connlimit | grep -w "jump connlimit-$eip" | awk '{print $NF}')
+    do
+        exec_cmd "nft delete rule ip $NFT_CONNLIMIT_TABLE eip-connlimit handle $handle"
+    done
+    exec_cmd "nft flush chain ip $NFT_CONNLIMIT_TABLE connlimit-$eip"
+    exec_cmd "nft delete chain ip $NFT_CONNLIMIT_TABLE connlimit-$eip"
+}
+
+function add_connlimit() {
+    # make sure inited
+    check_inited
+    ensure_eip_connlimit_chain
+    for rule in $@
+    do
+        arr=(${rule//,/ })
+        eip=(${arr[0]//\// })
+        limit=${arr[1]}
+        # replace the old limit of the eip
+        del_eip_connlimit $eip
+        # the connections of the eip share the limit counted by the single rule in the chain of the eip
+        exec_cmd "nft add chain ip $NFT_CONNLIMIT_TABLE connlimit-$eip"
+        exec_cmd "nft add rule ip $NFT_CONNLIMIT_TABLE connlimit-$eip ct count over $limit counter drop comment \"connlimit,$eip,$limit\""
+        exec_cmd "nft add rule ip $NFT_CONNLIMIT_TABLE eip-connlimit ct state new ct original ip daddr $eip jump connlimit-$eip"
+        exec_cmd "nft add rule ip $NFT_CONNLIMIT_TABLE eip-connlimit ct state new ct reply ip daddr $eip jump connlimit-$eip"
+    done
+}
+
+function del_connlimit() {
+    # make sure inited
+    check_inited
+    for rule in $@
+    do
+        arr=(${rule//,/ })
+        eip=(${arr[0]//\// })
+        del_eip_connlimit $eip
+    done
 }
 
 
@@ -126,19 +313,123 @@ function add_eip() {
         arr=(${rule//,/ })
         eip=${arr[0]}
         eip_without_prefix=(${eip//\// })
-        eip_network=$(ipcalc -n $eip | awk -F '=' '{print $2}')
-        eip_prefix=$(ipcalc -p $eip | awk -F '=' '{print $2}')
         gateway=${arr[1]}
 
         exec_cmd "ip addr replace $eip dev net1"
-        ip link set dev net1 arp on
         # gw may lost, even if add_vpc_external_route add route successfully
-        exec_cmd "ip route replace default via $gateway dev net1"
+        if [ -n "$gateway" ]; then
+            exec_cmd "ip route replace default via $gateway dev net1"
+        fi
+        if [[ "$eip" =~ : ]]; then
+            # the ipv6 eip is announced by the duplicate address detection of the kernel
+            continue
+        fi
+        ip link set dev net1 arp on
         ip route | grep "default via $gateway dev net1"
-        exec_cmd "arping -I net1 -c 3 -D $eip_without_prefix"
+        if [ -f $HA_MODE_FILE ]; then
+            # the eip is held by all the replicas, only the active one announces it.
+            # in ecmp mode the new eip is ignored until the controller assigns it to a replica
+            if nft list set arp $HA_ARP_TABLE ignored > /dev/null 2>&1; then
+                exec_cmd "nft add element arp $HA_ARP_TABLE ignored { $eip_without_prefix }"
+            elif [ "$(cat /proc/sys/net/ipv4/conf/net1/arp_ignore)" -eq 0 ]; then
+                arping -I net1 -c 3 -U $eip_without_prefix
+            fi
+        else
+            exec_cmd "arping -I net1 -c 3 -D $eip_without_prefix"
+        fi
     done
 }
 
+function ha_init() {
+    # make sure inited
+    check_inited
+    for rule in $@
+    do
+        arr=(${rule//,/ })
+        mode=${arr[0]}
+        bfdPeer=${arr[1]}
+
+        echo $mode > $HA_MODE_FILE
+        # replicas start as standby, which never answer arp requests of the eips
+        exec_cmd "sysctl -w net.ipv4.conf.net1.arp_ignore=8"
+        exec_cmd "sysctl -w net.ipv4.conf.net1.arp_announce=2"
+        ip link set dev net1 arp on
+
+        # synchronize the conntrack entries between replicas, so the established
+        # connections survive the failover and the asymmetric ecmp paths
+        if ! pgrep -x conntrackd > /dev/null; then
+            localIp=$(ip -4 -o addr show dev eth0 | awk '{print $4}' | cut -d/ -f1 | head -n1)
+            mkdir -p /etc/conntrackd
+            cat > /etc/conntrackd/conntrackd.conf <<CONF
+Sync {
+    Mode NOTRACK {
+        DisableInternalCache on
+        DisableExternalCache on
+    }
+    Multicast {
+        IPv4_address 225.0.0.50
+        Group 3780
+        IPv4_interface $localIp
+        Interface eth0
+        Checksum on
+    }
+}
+General {
+    LockFile /var/lock/conntrackd.lock
+    UNIX {
+        Path /var/run/conntrackd.ctl
+    }
+    Filter From Kernelspace {
+        Protocol Accept {
+            TCP
+            UDP
+            ICMP
+        }
+    }
+}
+CONF
+            exec_cmd "conntrackd -d -C /etc/conntrackd/conntrackd.conf"
+        fi
+
+        if [ "$mode" == "ecmp" ]; then
+            # answer the bfd sessions of the vpc ecmp static routes
+            pgrep -x bfdd-beacon > /dev/null || exec_cmd "bfdd-beacon --listen=0.0.0.0"
+            bfdd-control status | grep -qw "$bfdPeer" || exec_cmd "bfdd-control allow $bfdPeer"
+        fi
+    done
+}
+
+function ha_active() {
+    # make sure inited
+    check_inited
+    # in ecmp mode the eips are shared out among the ready replicas,
+    # the eips owned by the other replicas are passed in and never answered
+    nft delete table arp $HA_ARP_TABLE > /dev/null 2>&1
+    if [ $# -ne 0 ]; then
+        exec_cmd "nft add table arp $HA_ARP_TABLE"
+        exec_cmd "nft add set arp $HA_ARP_TABLE ignored { type ipv4_addr; }"
+        exec_cmd "nft add chain arp $HA_ARP_TABLE input { type filter hook input priority 0; }"
+        exec_cmd "nft add rule arp $HA_ARP_TABLE input iifname net1 arp daddr ip @ignored drop"
+        exec_cmd "nft add element arp $HA_ARP_TABLE ignored { $(echo $@ | tr ' ' ',') }"
+    fi
+    exec_cmd "sysctl -w net.ipv4.conf.net1.arp_ignore=0"
+    # announce the eips by gratuitous arp to take over the traffic
+    for eip in $(ip -4 -o addr show dev net1 | awk '{print $4}' | cut -d/ -f1)
+    do
+        case " $@ " in
+            *" $eip "*) continue ;;
+        esac
+        arping -I net1 -c 3 -U $eip
+    done
+}
+
+function ha_standby() {
+    # make sure inited
+    check_inited
+    nft delete table arp $HA_ARP_TABLE > /dev/null 2>&1
+    exec_cmd "sysctl -w net.ipv4.conf.net1.arp_ignore=8"
+}
+
 function del_eip() {
     # make sure inited
     check_inited
@@ -186,6 +477,35 @@ function del_floating_ip() {
     done
 }
 
+function add_hairpin() {
+    # make sure inited
+    check_inited
+    ensure_hairpin_chains
+    for rule in $@
+    do
+        arr=(${rule//,/ })
+        eip=(${arr[0]//\// })
+        # check if already exist
+        iptables-save  | grep "HAIRPIN_MARK" | grep -w "\-d $eip/32" && continue
+        exec_cmd "iptables -t nat -A HAIRPIN_MARK -i eth0 -d $eip -j MARK --set-xmark 0x4000/0x4000"
+    done
+}
+
+function del_hairpin() {
+    # make sure inited
+    check_inited
+    for rule in $@
+    do
+        arr=(${rule//,/ })
+        eip=(${arr[0]//\// })
+        # check if already exist
+        iptables-save  | grep "HAIRPIN_MARK" | grep -w "\-d $eip/32"
+        if [ "$?" -eq 0 ];then
+            exec_cmd "iptables -t nat -D HAIRPIN_MARK -i eth0 -d $eip -j MARK --set-xmark 0x4000/0x4000"
+        fi
+    done
+}
+
 function add_snat() {
     # make sure inited
     check_inited
@@ -231,9 +551,15 @@ function add_dnat() {
         protocol=${arr[2]}
         internalIp=${arr[3]}
         internalPort=${arr[4]}
+        destination=$internalIp:$internalPort
+        if [[ "$dport" == *-* ]]; then
+            # a port range is mapped to the same ports
+            dport=${dport/-/:}
+            destination=$internalIp
+        fi
         # check if already exist
-        iptables-save  | grep "SHARED_DNAT" | grep -w "\-d $eip/32" | grep "p $protocol" | grep -w "dport $dport"| grep  -w "destination $internalIp:$internalPort"  && exit 0
-        exec_cmd "iptables -t nat -A SHARED_DNAT -p $protocol -d $eip --dport $dport -j DNAT --to-destination $internalIp:$internalPort"
+        iptables-save  | grep "SHARED_DNAT" | grep -w "\-d $eip/32" | grep "p $protocol" | grep -w "dport $dport"| grep  -w "destination $destination"  && continue
+        exec_cmd "iptables -t nat -A SHARED_DNAT -p $protocol -d $eip --dport $dport -j DNAT --to-destination $destination"
     done
 }
 
@@ -249,10 +575,15 @@ function del_dnat() {
         protocol=${arr[2]}
         internalIp=${arr[3]}
         internalPort=${arr[4]}
+        destination=$internalIp:$internalPort
+        if [[ "$dport" == *-* ]]; then
+            dport=${dport/-/:}
+            destination=$internalIp
+        fi
         # check if already exist
-        iptables-save  | grep "SHARED_DNAT" | grep -w "\-d $eip/32" | grep "p $protocol" | grep -w "dport $dport"| grep  -w "destination $internalIp:$internalPort"
+        iptables-save  | grep "SHARED_DNAT" | grep -w "\-d $eip/32" | grep "p $protocol" | grep -w "dport $dport"| grep  -w "destination $destination"
         if [ "$?" -eq 0 ];then
-          exec_cmd "iptables -t nat -D SHARED_DNAT -p $protocol -d $eip --dport $dport -j DNAT --to-destination $internalIp:$internalPort"
+          exec_cmd "iptables -t nat -D SHARED_DNAT -p $protocol -d $eip --dport $dport -j DNAT --to-destination $destination"
         fi
     done
 }
@@ -496,6 +827,38 @@ case $opt in
         echo "floating-ip-del $rules"
         del_floating_ip $rules
         ;;
+ hairpin-add)
+        echo "hairpin-add $rules"
+        add_hairpin $rules
+        ;;
+ hairpin-del)
+        echo "hairpin-del $rules"
+        del_hairpin $rules
+        ;;
+ conntrack-set)
+        echo "conntrack-set $rules"
+        set_conntrack $rules
+        ;;
+ connlimit-add)
+        echo "connlimit-add $rules"
+        add_connlimit $rules
+        ;;
+ connlimit-del)
+        echo "connlimit-del $rules"
+        del_connlimit $rules
+        ;;
+ ha-init)
+        echo "ha-init $rules"
+        ha_init $rules
+        ;;
+ ha-active)
+        echo "ha-active $rules"
+        ha_active $rules
+        ;;
+ ha-standby)
+        echo "ha-standby $rules"
+        ha_standby $rules
+        ;;
  get-iptables-version)
         echo "get-iptables-version $rules"
         get_iptables_version $rules
@@ -525,7 +888,7 @@ case $opt in
         qos_del $rules
         ;;
  *)
-        echo "Usage: $0 [init|subnet-route-add|subnet-route-del|eip-add|eip-del|floating-ip-add|floating-ip-del|dnat-add|dnat-del|snat-add|snat-del] ..."
+        echo "Usage: $0 [init|subnet-route-add|subnet-route-del|eip-add|eip-del|floating-ip-add|floating-ip-del|dnat-add|dnat-del|snat-add|snat-del|hairpin-add|hairpin-del|conntrack-set|connlimit-add|connlimit-del] ..."
         exit 1
         ;;
 esac
@@ -51,6 +51,7 @@ require (
 	k8s.io/pod-security-admission v0.29.2
 	k8s.io/sample-controller v0.29.2
 	k8s.io/utils v0.0.0-20240102154912-e7106e64919e
+	kubevirt.io/api v1.1.1
 	kubevirt.io/client-go v1.1.1
 	sigs.k8s.io/controller-runtime v0.17.2
 )
@@ -267,7 +268,6 @@ require (
 	k8s.io/kubelet v0.29.2 // indirect
 	k8s.io/legacy-cloud-providers v0.0.0 // indirect
 	k8s.io/mount-utils v0.0.0 // indirect
-	kubevirt.io/api v1.1.1 // indirect
 	kubevirt.io/containerized-data-importer-api v1.58.1 // indirect
 	kubevirt.io/controller-lifecycle-operator-sdk/api v0.0.0-20220329064328-f3cc58c6ed90 // indirect
 	moul.io/http2curl v1.0.0 // indirect
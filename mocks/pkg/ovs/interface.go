@@ -14,6 +14,8 @@ import (
 
 	v1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
 	ovs "github.com/kubeovn/kube-ovn/pkg/ovs"
+	ovnicnb "github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnicnb"
+	ovnicsb "github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnicsb"
 	ovnnb "github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
 	ovnsb "github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnsb"
 	util "github.com/kubeovn/kube-ovn/pkg/util"
@@ -75,17 +77,17 @@ func (mr *MockNBGlobalMockRecorder) SetAzName(azName any) *gomock.Call {
 }
 
 // SetICAutoRoute mocks base method.
-func (m *MockNBGlobal) SetICAutoRoute(enable bool, blackList []string) error {
+func (m *MockNBGlobal) SetICAutoRoute(enable, learn bool, blackList []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetICAutoRoute", enable, blackList)
+	ret := m.ctrl.Call(m, "SetICAutoRoute", enable, learn, blackList)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetICAutoRoute indicates an expected call of SetICAutoRoute.
-func (mr *MockNBGlobalMockRecorder) SetICAutoRoute(enable, blackList any) *gomock.Call {
+func (mr *MockNBGlobalMockRecorder) SetICAutoRoute(enable, learn, blackList any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetICAutoRoute", reflect.TypeOf((*MockNBGlobal)(nil).SetICAutoRoute), enable, blackList)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetICAutoRoute", reflect.TypeOf((*MockNBGlobal)(nil).SetICAutoRoute), enable, learn, blackList)
 }
 
 // SetLsCtSkipDstLportIPs mocks base method.
@@ -418,6 +420,20 @@ func (mr *MockLogicalRouterPortMockRecorder) LogicalRouterPortExists(lrpName any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogicalRouterPortExists", reflect.TypeOf((*MockLogicalRouterPort)(nil).LogicalRouterPortExists), lrpName)
 }
 
+// UpdateLogicalRouterPortNetworks mocks base method.
+func (m *MockLogicalRouterPort) UpdateLogicalRouterPortNetworks(lrpName string, networks []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLogicalRouterPortNetworks", lrpName, networks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLogicalRouterPortNetworks indicates an expected call of UpdateLogicalRouterPortNetworks.
+func (mr *MockLogicalRouterPortMockRecorder) UpdateLogicalRouterPortNetworks(lrpName, networks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLogicalRouterPortNetworks", reflect.TypeOf((*MockLogicalRouterPort)(nil).UpdateLogicalRouterPortNetworks), lrpName, networks)
+}
+
 // UpdateLogicalRouterPortOptions mocks base method.
 func (m *MockLogicalRouterPort) UpdateLogicalRouterPortOptions(lrpName string, options map[string]string) error {
 	m.ctrl.T.Helper()
@@ -959,6 +975,20 @@ func (mr *MockLogicalSwitchPortMockRecorder) LogicalSwitchPortExists(name any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogicalSwitchPortExists", reflect.TypeOf((*MockLogicalSwitchPort)(nil).LogicalSwitchPortExists), name)
 }
 
+// ResetLogicalSwitchPortMigrateOptions mocks base method.
+func (m *MockLogicalSwitchPort) ResetLogicalSwitchPortMigrateOptions(lspName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetLogicalSwitchPortMigrateOptions", lspName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetLogicalSwitchPortMigrateOptions indicates an expected call of ResetLogicalSwitchPortMigrateOptions.
+func (mr *MockLogicalSwitchPortMockRecorder) ResetLogicalSwitchPortMigrateOptions(lspName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetLogicalSwitchPortMigrateOptions", reflect.TypeOf((*MockLogicalSwitchPort)(nil).ResetLogicalSwitchPortMigrateOptions), lspName)
+}
+
 // SetLogicalSwitchPortArpProxy mocks base method.
 func (m *MockLogicalSwitchPort) SetLogicalSwitchPortArpProxy(lspName string, enableArpProxy bool) error {
 	m.ctrl.T.Helper()
@@ -987,6 +1017,20 @@ func (mr *MockLogicalSwitchPortMockRecorder) SetLogicalSwitchPortExternalIDs(lsp
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLogicalSwitchPortExternalIDs", reflect.TypeOf((*MockLogicalSwitchPort)(nil).SetLogicalSwitchPortExternalIDs), lspName, externalIDs)
 }
 
+// SetLogicalSwitchPortMigrateOptions mocks base method.
+func (m *MockLogicalSwitchPort) SetLogicalSwitchPortMigrateOptions(lspName, srcChassis, targetChassis string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLogicalSwitchPortMigrateOptions", lspName, srcChassis, targetChassis)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLogicalSwitchPortMigrateOptions indicates an expected call of SetLogicalSwitchPortMigrateOptions.
+func (mr *MockLogicalSwitchPortMockRecorder) SetLogicalSwitchPortMigrateOptions(lspName, srcChassis, targetChassis any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLogicalSwitchPortMigrateOptions", reflect.TypeOf((*MockLogicalSwitchPort)(nil).SetLogicalSwitchPortMigrateOptions), lspName, srcChassis, targetChassis)
+}
+
 // SetLogicalSwitchPortSecurity mocks base method.
 func (m *MockLogicalSwitchPort) SetLogicalSwitchPortSecurity(portSecurity bool, lspName, mac, ips, vips string) error {
 	m.ctrl.T.Helper()
@@ -1190,6 +1234,20 @@ func (mr *MockLoadBalancerMockRecorder) LoadBalancerAddVip(lbName, vip any, back
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBalancerAddVip", reflect.TypeOf((*MockLoadBalancer)(nil).LoadBalancerAddVip), varargs...)
 }
 
+// LoadBalancerAddVips mocks base method.
+func (m *MockLoadBalancer) LoadBalancerAddVips(lbName string, vips map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadBalancerAddVips", lbName, vips)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadBalancerAddVips indicates an expected call of LoadBalancerAddVips.
+func (mr *MockLoadBalancerMockRecorder) LoadBalancerAddVips(lbName, vips any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBalancerAddVips", reflect.TypeOf((*MockLoadBalancer)(nil).LoadBalancerAddVips), lbName, vips)
+}
+
 // LoadBalancerDeleteHealthCheck mocks base method.
 func (m *MockLoadBalancer) LoadBalancerDeleteHealthCheck(lbName, uuid string) error {
 	m.ctrl.T.Helper()
@@ -1218,6 +1276,25 @@ func (mr *MockLoadBalancerMockRecorder) LoadBalancerDeleteIPPortMapping(lbName,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBalancerDeleteIPPortMapping", reflect.TypeOf((*MockLoadBalancer)(nil).LoadBalancerDeleteIPPortMapping), lbName, vip)
 }
 
+// LoadBalancerDeleteIPPortMappingByIPs mocks base method.
+func (m *MockLoadBalancer) LoadBalancerDeleteIPPortMappingByIPs(lbName string, ips ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{lbName}
+	for _, a := range ips {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LoadBalancerDeleteIPPortMappingByIPs", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadBalancerDeleteIPPortMappingByIPs indicates an expected call of LoadBalancerDeleteIPPortMappingByIPs.
+func (mr *MockLoadBalancerMockRecorder) LoadBalancerDeleteIPPortMappingByIPs(lbName any, ips ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{lbName}, ips...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBalancerDeleteIPPortMappingByIPs", reflect.TypeOf((*MockLoadBalancer)(nil).LoadBalancerDeleteIPPortMappingByIPs), varargs...)
+}
+
 // LoadBalancerDeleteVip mocks base method.
 func (m *MockLoadBalancer) LoadBalancerDeleteVip(lbName, vip string, ignoreHealthCheck bool) error {
 	m.ctrl.T.Helper()
@@ -1232,6 +1309,20 @@ func (mr *MockLoadBalancerMockRecorder) LoadBalancerDeleteVip(lbName, vip, ignor
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBalancerDeleteVip", reflect.TypeOf((*MockLoadBalancer)(nil).LoadBalancerDeleteVip), lbName, vip, ignoreHealthCheck)
 }
 
+// LoadBalancerDeleteVips mocks base method.
+func (m *MockLoadBalancer) LoadBalancerDeleteVips(lbName string, vips []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadBalancerDeleteVips", lbName, vips)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadBalancerDeleteVips indicates an expected call of LoadBalancerDeleteVips.
+func (mr *MockLoadBalancerMockRecorder) LoadBalancerDeleteVips(lbName, vips any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBalancerDeleteVips", reflect.TypeOf((*MockLoadBalancer)(nil).LoadBalancerDeleteVips), lbName, vips)
+}
+
 // LoadBalancerExists mocks base method.
 func (m *MockLoadBalancer) LoadBalancerExists(lbName string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -1656,6 +1747,21 @@ func (mr *MockACLMockRecorder) DeleteAclsOps(parentName, parentType, direction,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAclsOps", reflect.TypeOf((*MockACL)(nil).DeleteAclsOps), parentName, parentType, direction, externalIDs)
 }
 
+// ListAcls mocks base method.
+func (m *MockACL) ListAcls(direction string, externalIDs map[string]string) ([]ovnnb.ACL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAcls", direction, externalIDs)
+	ret0, _ := ret[0].([]ovnnb.ACL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAcls indicates an expected call of ListAcls.
+func (mr *MockACLMockRecorder) ListAcls(direction, externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAcls", reflect.TypeOf((*MockACL)(nil).ListAcls), direction, externalIDs)
+}
+
 // SetACLLog mocks base method.
 func (m *MockACL) SetACLLog(pgName, protocol string, logEnable, isIngress bool) error {
 	m.ctrl.T.Helper()
@@ -1897,6 +2003,25 @@ func (mr *MockLogicalRouterStaticRouteMockRecorder) ClearLogicalRouterStaticRout
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearLogicalRouterStaticRoute", reflect.TypeOf((*MockLogicalRouterStaticRoute)(nil).ClearLogicalRouterStaticRoute), lrName)
 }
 
+// CreateLogicalRouterStaticRoutes mocks base method.
+func (m *MockLogicalRouterStaticRoute) CreateLogicalRouterStaticRoutes(lrName string, routes ...*ovnnb.LogicalRouterStaticRoute) error {
+	m.ctrl.T.Helper()
+	varargs := []any{lrName}
+	for _, a := range routes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateLogicalRouterStaticRoutes", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLogicalRouterStaticRoutes indicates an expected call of CreateLogicalRouterStaticRoutes.
+func (mr *MockLogicalRouterStaticRouteMockRecorder) CreateLogicalRouterStaticRoutes(lrName any, routes ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{lrName}, routes...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLogicalRouterStaticRoutes", reflect.TypeOf((*MockLogicalRouterStaticRoute)(nil).CreateLogicalRouterStaticRoutes), varargs...)
+}
+
 // DeleteLogicalRouterStaticRoute mocks base method.
 func (m *MockLogicalRouterStaticRoute) DeleteLogicalRouterStaticRoute(lrName string, routeTable, policy *string, ipPrefix, nextHop string) error {
 	m.ctrl.T.Helper()
@@ -2331,6 +2456,172 @@ func (mr *MockDHCPOptionsMockRecorder) UpdateDHCPOptions(subnet, mtu any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDHCPOptions", reflect.TypeOf((*MockDHCPOptions)(nil).UpdateDHCPOptions), subnet, mtu)
 }
 
+// MockQoS is a mock of QoS interface.
+type MockQoS struct {
+	ctrl     *gomock.Controller
+	recorder *MockQoSMockRecorder
+}
+
+// MockQoSMockRecorder is the mock recorder for MockQoS.
+type MockQoSMockRecorder struct {
+	mock *MockQoS
+}
+
+// NewMockQoS creates a new mock instance.
+func NewMockQoS(ctrl *gomock.Controller) *MockQoS {
+	mock := &MockQoS{ctrl: ctrl}
+	mock.recorder = &MockQoSMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQoS) EXPECT() *MockQoSMockRecorder {
+	return m.recorder
+}
+
+// AddQoS mocks base method.
+func (m *MockQoS) AddQoS(lsName, direction string, priority int, match string, action, bandwidth map[string]int, externalIDs map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddQoS", lsName, direction, priority, match, action, bandwidth, externalIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddQoS indicates an expected call of AddQoS.
+func (mr *MockQoSMockRecorder) AddQoS(lsName, direction, priority, match, action, bandwidth, externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddQoS", reflect.TypeOf((*MockQoS)(nil).AddQoS), lsName, direction, priority, match, action, bandwidth, externalIDs)
+}
+
+// DeleteQoSs mocks base method.
+func (m *MockQoS) DeleteQoSs(lsName string, externalIDs map[string]string, filter func(*ovnnb.QoS) bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteQoSs", lsName, externalIDs, filter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteQoSs indicates an expected call of DeleteQoSs.
+func (mr *MockQoSMockRecorder) DeleteQoSs(lsName, externalIDs, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQoSs", reflect.TypeOf((*MockQoS)(nil).DeleteQoSs), lsName, externalIDs, filter)
+}
+
+// ListQoSs mocks base method.
+func (m *MockQoS) ListQoSs(lsName string, externalIDs map[string]string) ([]*ovnnb.QoS, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQoSs", lsName, externalIDs)
+	ret0, _ := ret[0].([]*ovnnb.QoS)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQoSs indicates an expected call of ListQoSs.
+func (mr *MockQoSMockRecorder) ListQoSs(lsName, externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQoSs", reflect.TypeOf((*MockQoS)(nil).ListQoSs), lsName, externalIDs)
+}
+
+// MockMirror is a mock of Mirror interface.
+type MockMirror struct {
+	ctrl     *gomock.Controller
+	recorder *MockMirrorMockRecorder
+}
+
+// MockMirrorMockRecorder is the mock recorder for MockMirror.
+type MockMirrorMockRecorder struct {
+	mock *MockMirror
+}
+
+// NewMockMirror creates a new mock instance.
+func NewMockMirror(ctrl *gomock.Controller) *MockMirror {
+	mock := &MockMirror{ctrl: ctrl}
+	mock.recorder = &MockMirrorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMirror) EXPECT() *MockMirrorMockRecorder {
+	return m.recorder
+}
+
+// CreateOrUpdateMirror mocks base method.
+func (m *MockMirror) CreateOrUpdateMirror(name, filter, sink, mirrorType string, index int, externalIDs map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateMirror", name, filter, sink, mirrorType, index, externalIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrUpdateMirror indicates an expected call of CreateOrUpdateMirror.
+func (mr *MockMirrorMockRecorder) CreateOrUpdateMirror(name, filter, sink, mirrorType, index, externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateMirror", reflect.TypeOf((*MockMirror)(nil).CreateOrUpdateMirror), name, filter, sink, mirrorType, index, externalIDs)
+}
+
+// DeleteMirror mocks base method.
+func (m *MockMirror) DeleteMirror(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMirror", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMirror indicates an expected call of DeleteMirror.
+func (mr *MockMirrorMockRecorder) DeleteMirror(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMirror", reflect.TypeOf((*MockMirror)(nil).DeleteMirror), name)
+}
+
+// GetMirror mocks base method.
+func (m *MockMirror) GetMirror(name string, ignoreNotFound bool) (*ovnnb.Mirror, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMirror", name, ignoreNotFound)
+	ret0, _ := ret[0].(*ovnnb.Mirror)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMirror indicates an expected call of GetMirror.
+func (mr *MockMirrorMockRecorder) GetMirror(name, ignoreNotFound any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMirror", reflect.TypeOf((*MockMirror)(nil).GetMirror), name, ignoreNotFound)
+}
+
+// ListMirrors mocks base method.
+func (m *MockMirror) ListMirrors(externalIDs map[string]string) ([]ovnnb.Mirror, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMirrors", externalIDs)
+	ret0, _ := ret[0].([]ovnnb.Mirror)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMirrors indicates an expected call of ListMirrors.
+func (mr *MockMirrorMockRecorder) ListMirrors(externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMirrors", reflect.TypeOf((*MockMirror)(nil).ListMirrors), externalIDs)
+}
+
+// LogicalSwitchPortUpdateMirrors mocks base method.
+func (m *MockMirror) LogicalSwitchPortUpdateMirrors(lspName string, op ovsdb.Mutator, mirrorNames ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{lspName, op}
+	for _, a := range mirrorNames {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LogicalSwitchPortUpdateMirrors", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogicalSwitchPortUpdateMirrors indicates an expected call of LogicalSwitchPortUpdateMirrors.
+func (mr *MockMirrorMockRecorder) LogicalSwitchPortUpdateMirrors(lspName, op any, mirrorNames ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{lspName, op}, mirrorNames...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogicalSwitchPortUpdateMirrors", reflect.TypeOf((*MockMirror)(nil).LogicalSwitchPortUpdateMirrors), varargs...)
+}
+
 // MockNbClient is a mock of NbClient interface.
 type MockNbClient struct {
 	ctrl     *gomock.Controller
@@ -2415,6 +2706,20 @@ func (mr *MockNbClientMockRecorder) AddNat(lrName, natType, externalIP, logicalI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddNat", reflect.TypeOf((*MockNbClient)(nil).AddNat), lrName, natType, externalIP, logicalIP, logicalMac, port, options)
 }
 
+// AddQoS mocks base method.
+func (m *MockNbClient) AddQoS(lsName, direction string, priority int, match string, action, bandwidth map[string]int, externalIDs map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddQoS", lsName, direction, priority, match, action, bandwidth, externalIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddQoS indicates an expected call of AddQoS.
+func (mr *MockNbClientMockRecorder) AddQoS(lsName, direction, priority, match, action, bandwidth, externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddQoS", reflect.TypeOf((*MockNbClient)(nil).AddQoS), lsName, direction, priority, match, action, bandwidth, externalIDs)
+}
+
 // AddressSetUpdateAddress mocks base method.
 func (m *MockNbClient) AddressSetUpdateAddress(asName string, addresses ...string) error {
 	m.ctrl.T.Helper()
@@ -2641,6 +2946,25 @@ func (mr *MockNbClientMockRecorder) CreateLogicalRouterPort(lrName, lrpName, mac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLogicalRouterPort", reflect.TypeOf((*MockNbClient)(nil).CreateLogicalRouterPort), lrName, lrpName, mac, networks)
 }
 
+// CreateLogicalRouterStaticRoutes mocks base method.
+func (m *MockNbClient) CreateLogicalRouterStaticRoutes(lrName string, routes ...*ovnnb.LogicalRouterStaticRoute) error {
+	m.ctrl.T.Helper()
+	varargs := []any{lrName}
+	for _, a := range routes {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateLogicalRouterStaticRoutes", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLogicalRouterStaticRoutes indicates an expected call of CreateLogicalRouterStaticRoutes.
+func (mr *MockNbClientMockRecorder) CreateLogicalRouterStaticRoutes(lrName any, routes ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{lrName}, routes...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLogicalRouterStaticRoutes", reflect.TypeOf((*MockNbClient)(nil).CreateLogicalRouterStaticRoutes), varargs...)
+}
+
 // CreateLogicalSwitch mocks base method.
 func (m *MockNbClient) CreateLogicalSwitch(lsName, lrName, cidrBlock, gateway string, needRouter, randomAllocateGW bool) error {
 	m.ctrl.T.Helper()
@@ -2683,6 +3007,20 @@ func (mr *MockNbClientMockRecorder) CreateNodeACL(pgName, nodeIPStr, joinIPStr a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNodeACL", reflect.TypeOf((*MockNbClient)(nil).CreateNodeACL), pgName, nodeIPStr, joinIPStr)
 }
 
+// CreateOrUpdateMirror mocks base method.
+func (m *MockNbClient) CreateOrUpdateMirror(name, filter, sink, mirrorType string, index int, externalIDs map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrUpdateMirror", name, filter, sink, mirrorType, index, externalIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrUpdateMirror indicates an expected call of CreateOrUpdateMirror.
+func (mr *MockNbClientMockRecorder) CreateOrUpdateMirror(name, filter, sink, mirrorType, index, externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrUpdateMirror", reflect.TypeOf((*MockNbClient)(nil).CreateOrUpdateMirror), name, filter, sink, mirrorType, index, externalIDs)
+}
+
 // CreatePeerRouterPort mocks base method.
 func (m *MockNbClient) CreatePeerRouterPort(localRouter, remoteRouter, localRouterPortIP string) error {
 	m.ctrl.T.Helper()
@@ -3085,6 +3423,20 @@ func (mr *MockNbClientMockRecorder) DeleteLogicalSwitchPorts(externalIDs, filter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLogicalSwitchPorts", reflect.TypeOf((*MockNbClient)(nil).DeleteLogicalSwitchPorts), externalIDs, filter)
 }
 
+// DeleteMirror mocks base method.
+func (m *MockNbClient) DeleteMirror(name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMirror", name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMirror indicates an expected call of DeleteMirror.
+func (mr *MockNbClientMockRecorder) DeleteMirror(name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMirror", reflect.TypeOf((*MockNbClient)(nil).DeleteMirror), name)
+}
+
 // DeleteNat mocks base method.
 func (m *MockNbClient) DeleteNat(lrName, natType, externalIP, logicalIP string) error {
 	m.ctrl.T.Helper()
@@ -3127,6 +3479,20 @@ func (mr *MockNbClientMockRecorder) DeletePortGroup(pgName any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePortGroup", reflect.TypeOf((*MockNbClient)(nil).DeletePortGroup), pgName)
 }
 
+// DeleteQoSs mocks base method.
+func (m *MockNbClient) DeleteQoSs(lsName string, externalIDs map[string]string, filter func(*ovnnb.QoS) bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteQoSs", lsName, externalIDs, filter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteQoSs indicates an expected call of DeleteQoSs.
+func (mr *MockNbClientMockRecorder) DeleteQoSs(lsName, externalIDs, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteQoSs", reflect.TypeOf((*MockNbClient)(nil).DeleteQoSs), lsName, externalIDs, filter)
+}
+
 // DeleteSecurityGroup mocks base method.
 func (m *MockNbClient) DeleteSecurityGroup(sgName string) error {
 	m.ctrl.T.Helper()
@@ -3290,6 +3656,21 @@ func (mr *MockNbClientMockRecorder) GetLogicalSwitchPort(lspName, ignoreNotFound
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLogicalSwitchPort", reflect.TypeOf((*MockNbClient)(nil).GetLogicalSwitchPort), lspName, ignoreNotFound)
 }
 
+// GetMirror mocks base method.
+func (m *MockNbClient) GetMirror(name string, ignoreNotFound bool) (*ovnnb.Mirror, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMirror", name, ignoreNotFound)
+	ret0, _ := ret[0].(*ovnnb.Mirror)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMirror indicates an expected call of GetMirror.
+func (mr *MockNbClientMockRecorder) GetMirror(name, ignoreNotFound any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMirror", reflect.TypeOf((*MockNbClient)(nil).GetMirror), name, ignoreNotFound)
+}
+
 // GetNATByUUID mocks base method.
 func (m *MockNbClient) GetNATByUUID(uuid string) (*ovnnb.NAT, error) {
 	m.ctrl.T.Helper()
@@ -3335,6 +3716,21 @@ func (mr *MockNbClientMockRecorder) GetPortGroup(pgName, ignoreNotFound any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPortGroup", reflect.TypeOf((*MockNbClient)(nil).GetPortGroup), pgName, ignoreNotFound)
 }
 
+// ListAcls mocks base method.
+func (m *MockNbClient) ListAcls(direction string, externalIDs map[string]string) ([]ovnnb.ACL, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAcls", direction, externalIDs)
+	ret0, _ := ret[0].([]ovnnb.ACL)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAcls indicates an expected call of ListAcls.
+func (mr *MockNbClientMockRecorder) ListAcls(direction, externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAcls", reflect.TypeOf((*MockNbClient)(nil).ListAcls), direction, externalIDs)
+}
+
 // ListAddressSets mocks base method.
 func (m *MockNbClient) ListAddressSets(externalIDs map[string]string) ([]ovnnb.AddressSet, error) {
 	m.ctrl.T.Helper()
@@ -3560,6 +3956,21 @@ func (mr *MockNbClientMockRecorder) ListLogicalSwitchPortsWithLegacyExternalIDs(
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLogicalSwitchPortsWithLegacyExternalIDs", reflect.TypeOf((*MockNbClient)(nil).ListLogicalSwitchPortsWithLegacyExternalIDs))
 }
 
+// ListMirrors mocks base method.
+func (m *MockNbClient) ListMirrors(externalIDs map[string]string) ([]ovnnb.Mirror, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMirrors", externalIDs)
+	ret0, _ := ret[0].([]ovnnb.Mirror)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMirrors indicates an expected call of ListMirrors.
+func (mr *MockNbClientMockRecorder) ListMirrors(externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMirrors", reflect.TypeOf((*MockNbClient)(nil).ListMirrors), externalIDs)
+}
+
 // ListNats mocks base method.
 func (m *MockNbClient) ListNats(lrName, natType, logicalIP string, externalIDs map[string]string) ([]*ovnnb.NAT, error) {
 	m.ctrl.T.Helper()
@@ -3605,6 +4016,21 @@ func (mr *MockNbClientMockRecorder) ListPortGroups(externalIDs any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPortGroups", reflect.TypeOf((*MockNbClient)(nil).ListPortGroups), externalIDs)
 }
 
+// ListQoSs mocks base method.
+func (m *MockNbClient) ListQoSs(lsName string, externalIDs map[string]string) ([]*ovnnb.QoS, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQoSs", lsName, externalIDs)
+	ret0, _ := ret[0].([]*ovnnb.QoS)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQoSs indicates an expected call of ListQoSs.
+func (mr *MockNbClientMockRecorder) ListQoSs(lsName, externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQoSs", reflect.TypeOf((*MockNbClient)(nil).ListQoSs), lsName, externalIDs)
+}
+
 // ListUpBFDs mocks base method.
 func (m *MockNbClient) ListUpBFDs(dstIP string) ([]ovnnb.BFD, error) {
 	m.ctrl.T.Helper()
@@ -3667,6 +4093,20 @@ func (mr *MockNbClientMockRecorder) LoadBalancerAddVip(lbName, vip any, backends
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBalancerAddVip", reflect.TypeOf((*MockNbClient)(nil).LoadBalancerAddVip), varargs...)
 }
 
+// LoadBalancerAddVips mocks base method.
+func (m *MockNbClient) LoadBalancerAddVips(lbName string, vips map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadBalancerAddVips", lbName, vips)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadBalancerAddVips indicates an expected call of LoadBalancerAddVips.
+func (mr *MockNbClientMockRecorder) LoadBalancerAddVips(lbName, vips any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBalancerAddVips", reflect.TypeOf((*MockNbClient)(nil).LoadBalancerAddVips), lbName, vips)
+}
+
 // LoadBalancerDeleteHealthCheck mocks base method.
 func (m *MockNbClient) LoadBalancerDeleteHealthCheck(lbName, uuid string) error {
 	m.ctrl.T.Helper()
@@ -3695,6 +4135,25 @@ func (mr *MockNbClientMockRecorder) LoadBalancerDeleteIPPortMapping(lbName, vip
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBalancerDeleteIPPortMapping", reflect.TypeOf((*MockNbClient)(nil).LoadBalancerDeleteIPPortMapping), lbName, vip)
 }
 
+// LoadBalancerDeleteIPPortMappingByIPs mocks base method.
+func (m *MockNbClient) LoadBalancerDeleteIPPortMappingByIPs(lbName string, ips ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{lbName}
+	for _, a := range ips {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LoadBalancerDeleteIPPortMappingByIPs", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadBalancerDeleteIPPortMappingByIPs indicates an expected call of LoadBalancerDeleteIPPortMappingByIPs.
+func (mr *MockNbClientMockRecorder) LoadBalancerDeleteIPPortMappingByIPs(lbName any, ips ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{lbName}, ips...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBalancerDeleteIPPortMappingByIPs", reflect.TypeOf((*MockNbClient)(nil).LoadBalancerDeleteIPPortMappingByIPs), varargs...)
+}
+
 // LoadBalancerDeleteVip mocks base method.
 func (m *MockNbClient) LoadBalancerDeleteVip(lbName, vip string, ignoreHealthCheck bool) error {
 	m.ctrl.T.Helper()
@@ -3709,11 +4168,25 @@ func (mr *MockNbClientMockRecorder) LoadBalancerDeleteVip(lbName, vip, ignoreHea
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBalancerDeleteVip", reflect.TypeOf((*MockNbClient)(nil).LoadBalancerDeleteVip), lbName, vip, ignoreHealthCheck)
 }
 
-// LoadBalancerExists mocks base method.
-func (m *MockNbClient) LoadBalancerExists(lbName string) (bool, error) {
+// LoadBalancerDeleteVips mocks base method.
+func (m *MockNbClient) LoadBalancerDeleteVips(lbName string, vips []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoadBalancerExists", lbName)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "LoadBalancerDeleteVips", lbName, vips)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LoadBalancerDeleteVips indicates an expected call of LoadBalancerDeleteVips.
+func (mr *MockNbClientMockRecorder) LoadBalancerDeleteVips(lbName, vips any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBalancerDeleteVips", reflect.TypeOf((*MockNbClient)(nil).LoadBalancerDeleteVips), lbName, vips)
+}
+
+// LoadBalancerExists mocks base method.
+func (m *MockNbClient) LoadBalancerExists(lbName string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadBalancerExists", lbName)
+	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -3847,6 +4320,25 @@ func (mr *MockNbClientMockRecorder) LogicalSwitchPortExists(name any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogicalSwitchPortExists", reflect.TypeOf((*MockNbClient)(nil).LogicalSwitchPortExists), name)
 }
 
+// LogicalSwitchPortUpdateMirrors mocks base method.
+func (m *MockNbClient) LogicalSwitchPortUpdateMirrors(lspName string, op ovsdb.Mutator, mirrorNames ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{lspName, op}
+	for _, a := range mirrorNames {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "LogicalSwitchPortUpdateMirrors", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogicalSwitchPortUpdateMirrors indicates an expected call of LogicalSwitchPortUpdateMirrors.
+func (mr *MockNbClientMockRecorder) LogicalSwitchPortUpdateMirrors(lspName, op any, mirrorNames ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{lspName, op}, mirrorNames...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogicalSwitchPortUpdateMirrors", reflect.TypeOf((*MockNbClient)(nil).LogicalSwitchPortUpdateMirrors), varargs...)
+}
+
 // LogicalSwitchUpdateLoadBalancers mocks base method.
 func (m *MockNbClient) LogicalSwitchUpdateLoadBalancers(lsName string, op ovsdb.Mutator, lbNames ...string) error {
 	m.ctrl.T.Helper()
@@ -3988,6 +4480,20 @@ func (mr *MockNbClientMockRecorder) RemoveLogicalPatchPort(lspName, lrpName any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveLogicalPatchPort", reflect.TypeOf((*MockNbClient)(nil).RemoveLogicalPatchPort), lspName, lrpName)
 }
 
+// ResetLogicalSwitchPortMigrateOptions mocks base method.
+func (m *MockNbClient) ResetLogicalSwitchPortMigrateOptions(lspName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetLogicalSwitchPortMigrateOptions", lspName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetLogicalSwitchPortMigrateOptions indicates an expected call of ResetLogicalSwitchPortMigrateOptions.
+func (mr *MockNbClientMockRecorder) ResetLogicalSwitchPortMigrateOptions(lspName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetLogicalSwitchPortMigrateOptions", reflect.TypeOf((*MockNbClient)(nil).ResetLogicalSwitchPortMigrateOptions), lspName)
+}
+
 // SetACLLog mocks base method.
 func (m *MockNbClient) SetACLLog(pgName, protocol string, logEnable, isIngress bool) error {
 	m.ctrl.T.Helper()
@@ -4017,17 +4523,17 @@ func (mr *MockNbClientMockRecorder) SetAzName(azName any) *gomock.Call {
 }
 
 // SetICAutoRoute mocks base method.
-func (m *MockNbClient) SetICAutoRoute(enable bool, blackList []string) error {
+func (m *MockNbClient) SetICAutoRoute(enable, learn bool, blackList []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetICAutoRoute", enable, blackList)
+	ret := m.ctrl.Call(m, "SetICAutoRoute", enable, learn, blackList)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetICAutoRoute indicates an expected call of SetICAutoRoute.
-func (mr *MockNbClientMockRecorder) SetICAutoRoute(enable, blackList any) *gomock.Call {
+func (mr *MockNbClientMockRecorder) SetICAutoRoute(enable, learn, blackList any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetICAutoRoute", reflect.TypeOf((*MockNbClient)(nil).SetICAutoRoute), enable, blackList)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetICAutoRoute", reflect.TypeOf((*MockNbClient)(nil).SetICAutoRoute), enable, learn, blackList)
 }
 
 // SetLoadBalancerAffinityTimeout mocks base method.
@@ -4072,6 +4578,20 @@ func (mr *MockNbClientMockRecorder) SetLogicalSwitchPortExternalIDs(lspName, ext
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLogicalSwitchPortExternalIDs", reflect.TypeOf((*MockNbClient)(nil).SetLogicalSwitchPortExternalIDs), lspName, externalIDs)
 }
 
+// SetLogicalSwitchPortMigrateOptions mocks base method.
+func (m *MockNbClient) SetLogicalSwitchPortMigrateOptions(lspName, srcChassis, targetChassis string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLogicalSwitchPortMigrateOptions", lspName, srcChassis, targetChassis)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLogicalSwitchPortMigrateOptions indicates an expected call of SetLogicalSwitchPortMigrateOptions.
+func (mr *MockNbClientMockRecorder) SetLogicalSwitchPortMigrateOptions(lspName, srcChassis, targetChassis any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLogicalSwitchPortMigrateOptions", reflect.TypeOf((*MockNbClient)(nil).SetLogicalSwitchPortMigrateOptions), lspName, srcChassis, targetChassis)
+}
+
 // SetLogicalSwitchPortSecurity mocks base method.
 func (m *MockNbClient) SetLogicalSwitchPortSecurity(portSecurity bool, lspName, mac, ips, vips string) error {
 	m.ctrl.T.Helper()
@@ -4314,6 +4834,20 @@ func (mr *MockNbClientMockRecorder) UpdateIngressACLOps(pgName, asIngressName, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIngressACLOps", reflect.TypeOf((*MockNbClient)(nil).UpdateIngressACLOps), pgName, asIngressName, asExceptName, protocol, npp, logEnable, namedPortMap)
 }
 
+// UpdateLogicalRouterPortNetworks mocks base method.
+func (m *MockNbClient) UpdateLogicalRouterPortNetworks(lrpName string, networks []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLogicalRouterPortNetworks", lrpName, networks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLogicalRouterPortNetworks indicates an expected call of UpdateLogicalRouterPortNetworks.
+func (mr *MockNbClientMockRecorder) UpdateLogicalRouterPortNetworks(lrpName, networks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLogicalRouterPortNetworks", reflect.TypeOf((*MockNbClient)(nil).UpdateLogicalRouterPortNetworks), lrpName, networks)
+}
+
 // UpdateLogicalRouterPortOptions mocks base method.
 func (m *MockNbClient) UpdateLogicalRouterPortOptions(lrpName string, options map[string]string) error {
 	m.ctrl.T.Helper()
@@ -4590,6 +5124,312 @@ func (mr *MockSbClientMockRecorder) UpdateChassisTag(chassisName, nodeName any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChassisTag", reflect.TypeOf((*MockSbClient)(nil).UpdateChassisTag), chassisName, nodeName)
 }
 
+// MockIcNbClient is a mock of IcNbClient interface.
+type MockIcNbClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockIcNbClientMockRecorder
+}
+
+// MockIcNbClientMockRecorder is the mock recorder for MockIcNbClient.
+type MockIcNbClientMockRecorder struct {
+	mock *MockIcNbClient
+}
+
+// NewMockIcNbClient creates a new mock instance.
+func NewMockIcNbClient(ctrl *gomock.Controller) *MockIcNbClient {
+	mock := &MockIcNbClient{ctrl: ctrl}
+	mock.recorder = &MockIcNbClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIcNbClient) EXPECT() *MockIcNbClientMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockIcNbClient) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockIcNbClientMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIcNbClient)(nil).Close))
+}
+
+// CreateTransitSwitch mocks base method.
+func (m *MockIcNbClient) CreateTransitSwitch(tsName string, externalIDs map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransitSwitch", tsName, externalIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTransitSwitch indicates an expected call of CreateTransitSwitch.
+func (mr *MockIcNbClientMockRecorder) CreateTransitSwitch(tsName, externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransitSwitch", reflect.TypeOf((*MockIcNbClient)(nil).CreateTransitSwitch), tsName, externalIDs)
+}
+
+// DeleteTransitSwitch mocks base method.
+func (m *MockIcNbClient) DeleteTransitSwitch(tsName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTransitSwitch", tsName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTransitSwitch indicates an expected call of DeleteTransitSwitch.
+func (mr *MockIcNbClientMockRecorder) DeleteTransitSwitch(tsName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransitSwitch", reflect.TypeOf((*MockIcNbClient)(nil).DeleteTransitSwitch), tsName)
+}
+
+// GetEntityInfo mocks base method.
+func (m *MockIcNbClient) GetEntityInfo(entity any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntityInfo", entity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetEntityInfo indicates an expected call of GetEntityInfo.
+func (mr *MockIcNbClientMockRecorder) GetEntityInfo(entity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntityInfo", reflect.TypeOf((*MockIcNbClient)(nil).GetEntityInfo), entity)
+}
+
+// GetTransitSwitch mocks base method.
+func (m *MockIcNbClient) GetTransitSwitch(tsName string, ignoreNotFound bool) (*ovnicnb.TransitSwitch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransitSwitch", tsName, ignoreNotFound)
+	ret0, _ := ret[0].(*ovnicnb.TransitSwitch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransitSwitch indicates an expected call of GetTransitSwitch.
+func (mr *MockIcNbClientMockRecorder) GetTransitSwitch(tsName, ignoreNotFound any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransitSwitch", reflect.TypeOf((*MockIcNbClient)(nil).GetTransitSwitch), tsName, ignoreNotFound)
+}
+
+// ListTransitSwitches mocks base method.
+func (m *MockIcNbClient) ListTransitSwitches(filter func(*ovnicnb.TransitSwitch) bool) ([]ovnicnb.TransitSwitch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransitSwitches", filter)
+	ret0, _ := ret[0].([]ovnicnb.TransitSwitch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransitSwitches indicates an expected call of ListTransitSwitches.
+func (mr *MockIcNbClientMockRecorder) ListTransitSwitches(filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransitSwitches", reflect.TypeOf((*MockIcNbClient)(nil).ListTransitSwitches), filter)
+}
+
+// RemoveTransitSwitchExternalIDs mocks base method.
+func (m *MockIcNbClient) RemoveTransitSwitchExternalIDs(tsName string, keys ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{tsName}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RemoveTransitSwitchExternalIDs", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTransitSwitchExternalIDs indicates an expected call of RemoveTransitSwitchExternalIDs.
+func (mr *MockIcNbClientMockRecorder) RemoveTransitSwitchExternalIDs(tsName any, keys ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{tsName}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTransitSwitchExternalIDs", reflect.TypeOf((*MockIcNbClient)(nil).RemoveTransitSwitchExternalIDs), varargs...)
+}
+
+// SetTransitSwitchExternalIDs mocks base method.
+func (m *MockIcNbClient) SetTransitSwitchExternalIDs(tsName string, externalIDs map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTransitSwitchExternalIDs", tsName, externalIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTransitSwitchExternalIDs indicates an expected call of SetTransitSwitchExternalIDs.
+func (mr *MockIcNbClientMockRecorder) SetTransitSwitchExternalIDs(tsName, externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransitSwitchExternalIDs", reflect.TypeOf((*MockIcNbClient)(nil).SetTransitSwitchExternalIDs), tsName, externalIDs)
+}
+
+// Transact mocks base method.
+func (m *MockIcNbClient) Transact(method string, operations []ovsdb.Operation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transact", method, operations)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transact indicates an expected call of Transact.
+func (mr *MockIcNbClientMockRecorder) Transact(method, operations any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transact", reflect.TypeOf((*MockIcNbClient)(nil).Transact), method, operations)
+}
+
+// MockIcSbClient is a mock of IcSbClient interface.
+type MockIcSbClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockIcSbClientMockRecorder
+}
+
+// MockIcSbClientMockRecorder is the mock recorder for MockIcSbClient.
+type MockIcSbClientMockRecorder struct {
+	mock *MockIcSbClient
+}
+
+// NewMockIcSbClient creates a new mock instance.
+func NewMockIcSbClient(ctrl *gomock.Controller) *MockIcSbClient {
+	mock := &MockIcSbClient{ctrl: ctrl}
+	mock.recorder = &MockIcSbClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIcSbClient) EXPECT() *MockIcSbClientMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockIcSbClient) Close() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Close")
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockIcSbClientMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockIcSbClient)(nil).Close))
+}
+
+// DeleteAvailabilityZone mocks base method.
+func (m *MockIcSbClient) DeleteAvailabilityZone(azName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAvailabilityZone", azName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAvailabilityZone indicates an expected call of DeleteAvailabilityZone.
+func (mr *MockIcSbClientMockRecorder) DeleteAvailabilityZone(azName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAvailabilityZone", reflect.TypeOf((*MockIcSbClient)(nil).DeleteAvailabilityZone), azName)
+}
+
+// GetAvailabilityZone mocks base method.
+func (m *MockIcSbClient) GetAvailabilityZone(azName string, ignoreNotFound bool) (*ovnicsb.AvailabilityZone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailabilityZone", azName, ignoreNotFound)
+	ret0, _ := ret[0].(*ovnicsb.AvailabilityZone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailabilityZone indicates an expected call of GetAvailabilityZone.
+func (mr *MockIcSbClientMockRecorder) GetAvailabilityZone(azName, ignoreNotFound any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailabilityZone", reflect.TypeOf((*MockIcSbClient)(nil).GetAvailabilityZone), azName, ignoreNotFound)
+}
+
+// GetEntityInfo mocks base method.
+func (m *MockIcSbClient) GetEntityInfo(entity any) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntityInfo", entity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetEntityInfo indicates an expected call of GetEntityInfo.
+func (mr *MockIcSbClientMockRecorder) GetEntityInfo(entity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntityInfo", reflect.TypeOf((*MockIcSbClient)(nil).GetEntityInfo), entity)
+}
+
+// ListAvailabilityZones mocks base method.
+func (m *MockIcSbClient) ListAvailabilityZones() ([]ovnicsb.AvailabilityZone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAvailabilityZones")
+	ret0, _ := ret[0].([]ovnicsb.AvailabilityZone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAvailabilityZones indicates an expected call of ListAvailabilityZones.
+func (mr *MockIcSbClientMockRecorder) ListAvailabilityZones() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailabilityZones", reflect.TypeOf((*MockIcSbClient)(nil).ListAvailabilityZones))
+}
+
+// ListGateways mocks base method.
+func (m *MockIcSbClient) ListGateways(azUUID string) ([]ovnicsb.Gateway, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGateways", azUUID)
+	ret0, _ := ret[0].([]ovnicsb.Gateway)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGateways indicates an expected call of ListGateways.
+func (mr *MockIcSbClientMockRecorder) ListGateways(azUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGateways", reflect.TypeOf((*MockIcSbClient)(nil).ListGateways), azUUID)
+}
+
+// ListPortBindings mocks base method.
+func (m *MockIcSbClient) ListPortBindings(azUUID string) ([]ovnicsb.PortBinding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPortBindings", azUUID)
+	ret0, _ := ret[0].([]ovnicsb.PortBinding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPortBindings indicates an expected call of ListPortBindings.
+func (mr *MockIcSbClientMockRecorder) ListPortBindings(azUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPortBindings", reflect.TypeOf((*MockIcSbClient)(nil).ListPortBindings), azUUID)
+}
+
+// ListRoutes mocks base method.
+func (m *MockIcSbClient) ListRoutes(azUUID string) ([]ovnicsb.Route, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoutes", azUUID)
+	ret0, _ := ret[0].([]ovnicsb.Route)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoutes indicates an expected call of ListRoutes.
+func (mr *MockIcSbClientMockRecorder) ListRoutes(azUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoutes", reflect.TypeOf((*MockIcSbClient)(nil).ListRoutes), azUUID)
+}
+
+// Transact mocks base method.
+func (m *MockIcSbClient) Transact(method string, operations []ovsdb.Operation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transact", method, operations)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transact indicates an expected call of Transact.
+func (mr *MockIcSbClientMockRecorder) Transact(method, operations any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transact", reflect.TypeOf((*MockIcSbClient)(nil).Transact), method, operations)
+}
+
 // MockCommon is a mock of Common interface.
 type MockCommon struct {
 	ctrl     *gomock.Controller
@@ -4799,3 +5639,229 @@ func (mr *MockChassisMockRecorder) UpdateChassisTag(chassisName, nodeName any) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChassisTag", reflect.TypeOf((*MockChassis)(nil).UpdateChassisTag), chassisName, nodeName)
 }
+
+// MockTransitSwitch is a mock of TransitSwitch interface.
+type MockTransitSwitch struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransitSwitchMockRecorder
+}
+
+// MockTransitSwitchMockRecorder is the mock recorder for MockTransitSwitch.
+type MockTransitSwitchMockRecorder struct {
+	mock *MockTransitSwitch
+}
+
+// NewMockTransitSwitch creates a new mock instance.
+func NewMockTransitSwitch(ctrl *gomock.Controller) *MockTransitSwitch {
+	mock := &MockTransitSwitch{ctrl: ctrl}
+	mock.recorder = &MockTransitSwitchMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransitSwitch) EXPECT() *MockTransitSwitchMockRecorder {
+	return m.recorder
+}
+
+// CreateTransitSwitch mocks base method.
+func (m *MockTransitSwitch) CreateTransitSwitch(tsName string, externalIDs map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransitSwitch", tsName, externalIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTransitSwitch indicates an expected call of CreateTransitSwitch.
+func (mr *MockTransitSwitchMockRecorder) CreateTransitSwitch(tsName, externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransitSwitch", reflect.TypeOf((*MockTransitSwitch)(nil).CreateTransitSwitch), tsName, externalIDs)
+}
+
+// DeleteTransitSwitch mocks base method.
+func (m *MockTransitSwitch) DeleteTransitSwitch(tsName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTransitSwitch", tsName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTransitSwitch indicates an expected call of DeleteTransitSwitch.
+func (mr *MockTransitSwitchMockRecorder) DeleteTransitSwitch(tsName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransitSwitch", reflect.TypeOf((*MockTransitSwitch)(nil).DeleteTransitSwitch), tsName)
+}
+
+// GetTransitSwitch mocks base method.
+func (m *MockTransitSwitch) GetTransitSwitch(tsName string, ignoreNotFound bool) (*ovnicnb.TransitSwitch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransitSwitch", tsName, ignoreNotFound)
+	ret0, _ := ret[0].(*ovnicnb.TransitSwitch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransitSwitch indicates an expected call of GetTransitSwitch.
+func (mr *MockTransitSwitchMockRecorder) GetTransitSwitch(tsName, ignoreNotFound any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransitSwitch", reflect.TypeOf((*MockTransitSwitch)(nil).GetTransitSwitch), tsName, ignoreNotFound)
+}
+
+// ListTransitSwitches mocks base method.
+func (m *MockTransitSwitch) ListTransitSwitches(filter func(*ovnicnb.TransitSwitch) bool) ([]ovnicnb.TransitSwitch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransitSwitches", filter)
+	ret0, _ := ret[0].([]ovnicnb.TransitSwitch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransitSwitches indicates an expected call of ListTransitSwitches.
+func (mr *MockTransitSwitchMockRecorder) ListTransitSwitches(filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransitSwitches", reflect.TypeOf((*MockTransitSwitch)(nil).ListTransitSwitches), filter)
+}
+
+// RemoveTransitSwitchExternalIDs mocks base method.
+func (m *MockTransitSwitch) RemoveTransitSwitchExternalIDs(tsName string, keys ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{tsName}
+	for _, a := range keys {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RemoveTransitSwitchExternalIDs", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveTransitSwitchExternalIDs indicates an expected call of RemoveTransitSwitchExternalIDs.
+func (mr *MockTransitSwitchMockRecorder) RemoveTransitSwitchExternalIDs(tsName any, keys ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{tsName}, keys...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveTransitSwitchExternalIDs", reflect.TypeOf((*MockTransitSwitch)(nil).RemoveTransitSwitchExternalIDs), varargs...)
+}
+
+// SetTransitSwitchExternalIDs mocks base method.
+func (m *MockTransitSwitch) SetTransitSwitchExternalIDs(tsName string, externalIDs map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTransitSwitchExternalIDs", tsName, externalIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTransitSwitchExternalIDs indicates an expected call of SetTransitSwitchExternalIDs.
+func (mr *MockTransitSwitchMockRecorder) SetTransitSwitchExternalIDs(tsName, externalIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTransitSwitchExternalIDs", reflect.TypeOf((*MockTransitSwitch)(nil).SetTransitSwitchExternalIDs), tsName, externalIDs)
+}
+
+// MockAvailabilityZone is a mock of AvailabilityZone interface.
+type MockAvailabilityZone struct {
+	ctrl     *gomock.Controller
+	recorder *MockAvailabilityZoneMockRecorder
+}
+
+// MockAvailabilityZoneMockRecorder is the mock recorder for MockAvailabilityZone.
+type MockAvailabilityZoneMockRecorder struct {
+	mock *MockAvailabilityZone
+}
+
+// NewMockAvailabilityZone creates a new mock instance.
+func NewMockAvailabilityZone(ctrl *gomock.Controller) *MockAvailabilityZone {
+	mock := &MockAvailabilityZone{ctrl: ctrl}
+	mock.recorder = &MockAvailabilityZoneMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAvailabilityZone) EXPECT() *MockAvailabilityZoneMockRecorder {
+	return m.recorder
+}
+
+// DeleteAvailabilityZone mocks base method.
+func (m *MockAvailabilityZone) DeleteAvailabilityZone(azName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAvailabilityZone", azName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAvailabilityZone indicates an expected call of DeleteAvailabilityZone.
+func (mr *MockAvailabilityZoneMockRecorder) DeleteAvailabilityZone(azName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAvailabilityZone", reflect.TypeOf((*MockAvailabilityZone)(nil).DeleteAvailabilityZone), azName)
+}
+
+// GetAvailabilityZone mocks base method.
+func (m *MockAvailabilityZone) GetAvailabilityZone(azName string, ignoreNotFound bool) (*ovnicsb.AvailabilityZone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailabilityZone", azName, ignoreNotFound)
+	ret0, _ := ret[0].(*ovnicsb.AvailabilityZone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailabilityZone indicates an expected call of GetAvailabilityZone.
+func (mr *MockAvailabilityZoneMockRecorder) GetAvailabilityZone(azName, ignoreNotFound any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailabilityZone", reflect.TypeOf((*MockAvailabilityZone)(nil).GetAvailabilityZone), azName, ignoreNotFound)
+}
+
+// ListAvailabilityZones mocks base method.
+func (m *MockAvailabilityZone) ListAvailabilityZones() ([]ovnicsb.AvailabilityZone, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAvailabilityZones")
+	ret0, _ := ret[0].([]ovnicsb.AvailabilityZone)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAvailabilityZones indicates an expected call of ListAvailabilityZones.
+func (mr *MockAvailabilityZoneMockRecorder) ListAvailabilityZones() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAvailabilityZones", reflect.TypeOf((*MockAvailabilityZone)(nil).ListAvailabilityZones))
+}
+
+// ListGateways mocks base method.
+func (m *MockAvailabilityZone) ListGateways(azUUID string) ([]ovnicsb.Gateway, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGateways", azUUID)
+	ret0, _ := ret[0].([]ovnicsb.Gateway)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListGateways indicates an expected call of ListGateways.
+func (mr *MockAvailabilityZoneMockRecorder) ListGateways(azUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGateways", reflect.TypeOf((*MockAvailabilityZone)(nil).ListGateways), azUUID)
+}
+
+// ListPortBindings mocks base method.
+func (m *MockAvailabilityZone) ListPortBindings(azUUID string) ([]ovnicsb.PortBinding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPortBindings", azUUID)
+	ret0, _ := ret[0].([]ovnicsb.PortBinding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPortBindings indicates an expected call of ListPortBindings.
+func (mr *MockAvailabilityZoneMockRecorder) ListPortBindings(azUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPortBindings", reflect.TypeOf((*MockAvailabilityZone)(nil).ListPortBindings), azUUID)
+}
+
+// ListRoutes mocks base method.
+func (m *MockAvailabilityZone) ListRoutes(azUUID string) ([]ovnicsb.Route, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoutes", azUUID)
+	ret0, _ := ret[0].([]ovnicsb.Route)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoutes indicates an expected call of ListRoutes.
+func (mr *MockAvailabilityZoneMockRecorder) ListRoutes(azUUID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoutes", reflect.TypeOf((*MockAvailabilityZone)(nil).ListRoutes), azUUID)
+}
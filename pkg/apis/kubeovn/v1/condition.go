@@ -459,3 +459,192 @@ func (s *ProviderNetworkStatus) RemoveNodeConditions(node string) bool {
 	}
 	return changed
 }
+
+func (s *InterConnectionStatus) addCondition(ctype ConditionType, status corev1.ConditionStatus, reason, message string) {
+	now := metav1.Now()
+	s.Conditions = append(s.Conditions, InterConnectionCondition{
+		Type:               ctype,
+		LastUpdateTime:     now,
+		LastTransitionTime: now,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// setConditionValue updates or creates a new condition
+func (s *InterConnectionStatus) setConditionValue(ctype ConditionType, status corev1.ConditionStatus, reason, message string) {
+	c := s.GetCondition(ctype)
+	if c == nil {
+		s.addCondition(ctype, status, reason, message)
+		return
+	}
+	if c.Status == status && c.Reason == reason && c.Message == message {
+		return
+	}
+	now := metav1.Now()
+	c.LastUpdateTime = now
+	if c.Status != status {
+		c.LastTransitionTime = now
+	}
+	c.Status = status
+	c.Reason = reason
+	c.Message = message
+}
+
+// GetCondition get existing condition
+func (s *InterConnectionStatus) GetCondition(ctype ConditionType) *InterConnectionCondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == ctype {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition updates or creates a new condition
+func (s *InterConnectionStatus) SetCondition(ctype ConditionType, reason, message string) {
+	s.setConditionValue(ctype, corev1.ConditionTrue, reason, message)
+}
+
+// ClearCondition updates or creates a new condition
+func (s *InterConnectionStatus) ClearCondition(ctype ConditionType, reason, message string) {
+	s.setConditionValue(ctype, corev1.ConditionFalse, reason, message)
+}
+
+// IsConditionTrue - if condition is true
+func (s InterConnectionStatus) IsConditionTrue(ctype ConditionType) bool {
+	if c := s.GetCondition(ctype); c != nil {
+		return c.Status == corev1.ConditionTrue
+	}
+	return false
+}
+
+// IsReady returns true if ready condition is set
+func (s InterConnectionStatus) IsReady() bool { return s.IsConditionTrue(Ready) }
+
+func (s *TrafficMirrorStatus) addCondition(ctype ConditionType, status corev1.ConditionStatus, reason, message string) {
+	now := metav1.Now()
+	s.Conditions = append(s.Conditions, TrafficMirrorCondition{
+		Type:               ctype,
+		LastUpdateTime:     now,
+		LastTransitionTime: now,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// setConditionValue updates or creates a new condition
+func (s *TrafficMirrorStatus) setConditionValue(ctype ConditionType, status corev1.ConditionStatus, reason, message string) {
+	c := s.GetCondition(ctype)
+	if c == nil {
+		s.addCondition(ctype, status, reason, message)
+		return
+	}
+	if c.Status == status && c.Reason == reason && c.Message == message {
+		return
+	}
+	now := metav1.Now()
+	c.LastUpdateTime = now
+	if c.Status != status {
+		c.LastTransitionTime = now
+	}
+	c.Status = status
+	c.Reason = reason
+	c.Message = message
+}
+
+// GetCondition get existing condition
+func (s *TrafficMirrorStatus) GetCondition(ctype ConditionType) *TrafficMirrorCondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == ctype {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition updates or creates a new condition
+func (s *TrafficMirrorStatus) SetCondition(ctype ConditionType, reason, message string) {
+	s.setConditionValue(ctype, corev1.ConditionTrue, reason, message)
+}
+
+// ClearCondition updates or creates a new condition
+func (s *TrafficMirrorStatus) ClearCondition(ctype ConditionType, reason, message string) {
+	s.setConditionValue(ctype, corev1.ConditionFalse, reason, message)
+}
+
+// IsConditionTrue - if condition is true
+func (s TrafficMirrorStatus) IsConditionTrue(ctype ConditionType) bool {
+	if c := s.GetCondition(ctype); c != nil {
+		return c.Status == corev1.ConditionTrue
+	}
+	return false
+}
+
+// IsReady returns true if ready condition is set
+func (s TrafficMirrorStatus) IsReady() bool { return s.IsConditionTrue(Ready) }
+
+func (s *ConnectivityCheckStatus) addCondition(ctype ConditionType, status corev1.ConditionStatus, reason, message string) {
+	now := metav1.Now()
+	s.Conditions = append(s.Conditions, ConnectivityCheckCondition{
+		Type:               ctype,
+		LastUpdateTime:     now,
+		LastTransitionTime: now,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// setConditionValue updates or creates a new condition
+func (s *ConnectivityCheckStatus) setConditionValue(ctype ConditionType, status corev1.ConditionStatus, reason, message string) {
+	c := s.GetCondition(ctype)
+	if c == nil {
+		s.addCondition(ctype, status, reason, message)
+		return
+	}
+	if c.Status == status && c.Reason == reason && c.Message == message {
+		return
+	}
+	now := metav1.Now()
+	c.LastUpdateTime = now
+	if c.Status != status {
+		c.LastTransitionTime = now
+	}
+	c.Status = status
+	c.Reason = reason
+	c.Message = message
+}
+
+// GetCondition get existing condition
+func (s *ConnectivityCheckStatus) GetCondition(ctype ConditionType) *ConnectivityCheckCondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == ctype {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// SetCondition updates or creates a new condition
+func (s *ConnectivityCheckStatus) SetCondition(ctype ConditionType, reason, message string) {
+	s.setConditionValue(ctype, corev1.ConditionTrue, reason, message)
+}
+
+// ClearCondition updates or creates a new condition
+func (s *ConnectivityCheckStatus) ClearCondition(ctype ConditionType, reason, message string) {
+	s.setConditionValue(ctype, corev1.ConditionFalse, reason, message)
+}
+
+// IsConditionTrue - if condition is true
+func (s ConnectivityCheckStatus) IsConditionTrue(ctype ConditionType) bool {
+	if c := s.GetCondition(ctype); c != nil {
+		return c.Status == corev1.ConditionTrue
+	}
+	return false
+}
+
+// IsReady returns true if ready condition is set
+func (s ConnectivityCheckStatus) IsReady() bool { return s.IsConditionTrue(Ready) }
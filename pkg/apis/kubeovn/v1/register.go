@@ -71,6 +71,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&VpcDnsList{},
 		&QoSPolicy{},
 		&QoSPolicyList{},
+		&InterConnection{},
+		&InterConnectionList{},
+		&TrafficMirror{},
+		&TrafficMirrorList{},
+		&ConnectivityCheck{},
+		&ConnectivityCheckList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
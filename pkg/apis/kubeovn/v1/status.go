@@ -157,3 +157,23 @@ func (vns *VpcNatStatus) Bytes() ([]byte, error) {
 	klog.V(5).Info("status body", newStr)
 	return []byte(newStr), nil
 }
+
+func (ics *InterConnectionStatus) Bytes() ([]byte, error) {
+	bytes, err := json.Marshal(ics)
+	if err != nil {
+		return nil, err
+	}
+	newStr := fmt.Sprintf(`{"status": %s}`, string(bytes))
+	klog.V(5).Info("status body", newStr)
+	return []byte(newStr), nil
+}
+
+func (tms *TrafficMirrorStatus) Bytes() ([]byte, error) {
+	bytes, err := json.Marshal(tms)
+	if err != nil {
+		return nil, err
+	}
+	newStr := fmt.Sprintf(`{"status": %s}`, string(bytes))
+	klog.V(5).Info("status body", newStr)
+	return []byte(newStr), nil
+}
@@ -45,8 +45,11 @@ var (
 type QoSPolicyBindingType string
 
 const (
-	QoSBindingTypeEIP   QoSPolicyBindingType = "EIP"
-	QoSBindingTypeNatGw QoSPolicyBindingType = "NATGW"
+	QoSBindingTypeEIP       QoSPolicyBindingType = "EIP"
+	QoSBindingTypeNatGw     QoSPolicyBindingType = "NATGW"
+	QoSBindingTypePod       QoSPolicyBindingType = "POD"
+	QoSBindingTypeNamespace QoSPolicyBindingType = "NAMESPACE"
+	QoSBindingTypeSubnet    QoSPolicyBindingType = "SUBNET"
 )
 
 type QoSPolicyRuleDirection string
@@ -56,6 +59,15 @@ const (
 	DirectionEgress  QoSPolicyRuleDirection = "egress"
 )
 
+type VpcNatGwMode string
+
+const (
+	// VpcNatGwModeActiveStandby runs one active replica holding the eips, the standby replicas take over on failure
+	VpcNatGwModeActiveStandby VpcNatGwMode = "active-standby"
+	// VpcNatGwModeECMP spreads the vpc traffic across all replicas by bfd checked ecmp static routes
+	VpcNatGwModeECMP VpcNatGwMode = "ecmp"
+)
+
 type QoSPolicyRuleMatchType string
 
 const (
@@ -417,13 +429,14 @@ type Vpc struct {
 }
 
 type VpcSpec struct {
-	Namespaces           []string       `json:"namespaces,omitempty"`
-	StaticRoutes         []*StaticRoute `json:"staticRoutes,omitempty"`
-	PolicyRoutes         []*PolicyRoute `json:"policyRoutes,omitempty"`
-	VpcPeerings          []*VpcPeering  `json:"vpcPeerings,omitempty"`
-	EnableExternal       bool           `json:"enableExternal,omitempty"`
-	ExtraExternalSubnets []string       `json:"extraExternalSubnets,omitempty"`
-	EnableBfd            bool           `json:"enableBfd,omitempty"`
+	Namespaces           []string        `json:"namespaces,omitempty"`
+	StaticRoutes         []*StaticRoute  `json:"staticRoutes,omitempty"`
+	PolicyRoutes         []*PolicyRoute  `json:"policyRoutes,omitempty"`
+	VpcPeerings          []*VpcPeering   `json:"vpcPeerings,omitempty"`
+	ICPeerings           []*VpcICPeering `json:"icPeerings,omitempty"`
+	EnableExternal       bool            `json:"enableExternal,omitempty"`
+	ExtraExternalSubnets []string        `json:"extraExternalSubnets,omitempty"`
+	EnableBfd            bool            `json:"enableBfd,omitempty"`
 }
 
 type VpcPeering struct {
@@ -431,6 +444,28 @@ type VpcPeering struct {
 	LocalConnectIP string `json:"localConnectIP,omitempty"`
 }
 
+// VpcICPeering connects the vpc to a vpc in another availability zone through ovn-ic,
+// the remote vpc should be peered with this one in the remote availability zone
+type VpcICPeering struct {
+	RemoteAz  string `json:"remoteAz"`
+	RemoteVpc string `json:"remoteVpc"`
+	// TransitCIDR is the cidr of the transit switch connecting the vpcs,
+	// a /30 in 169.254.128.0/17 is derived from the vpc pair if empty
+	// +optional
+	TransitCIDR string `json:"transitCIDR,omitempty"`
+}
+
+type VpcICPeeringStatus struct {
+	RemoteAz      string `json:"remoteAz"`
+	RemoteVpc     string `json:"remoteVpc"`
+	TransitSwitch string `json:"transitSwitch"`
+	// NextHopIP is the address of the remote vpc router on the transit switch
+	NextHopIP string `json:"nextHopIP,omitempty"`
+	// LearnedPrefixes are the subnets of the remote vpc routed through the transit switch
+	LearnedPrefixes []string `json:"learnedPrefixes,omitempty"`
+	Ready           bool     `json:"ready"`
+}
+
 type RoutePolicy string
 
 const (
@@ -486,6 +521,9 @@ type VpcStatus struct {
 	EnableExternal          bool     `json:"enableExternal"`
 	ExtraExternalSubnets    []string `json:"extraExternalSubnets"`
 	EnableBfd               bool     `json:"enableBfd"`
+
+	// ICPeerings are maintained by ovn-ic-controller
+	ICPeerings []VpcICPeeringStatus `json:"icPeerings,omitempty"`
 }
 
 // VpcCondition describes the state of an object at a certain point.
@@ -523,6 +561,20 @@ type VpcNatSpec struct {
 	Tolerations     []corev1.Toleration `json:"tolerations"`
 	Affinity        corev1.Affinity     `json:"affinity"`
 	QoSPolicy       string              `json:"qosPolicy"`
+	Replicas        int32               `json:"replicas,omitempty"`
+	Mode            VpcNatGwMode        `json:"mode,omitempty"`
+	Conntrack       VpcNatConntrack     `json:"conntrack,omitempty"`
+}
+
+// VpcNatConntrack overrides the connection tracking of the nat gateway pods,
+// the timeouts are in seconds and the unset ones keep the kernel defaults
+type VpcNatConntrack struct {
+	// Max limits the concurrent connections forwarded by the nat gateway, 0 means unlimited
+	Max                   int32 `json:"max,omitempty"`
+	TCPEstablishedTimeout int32 `json:"tcpEstablishedTimeout,omitempty"`
+	TCPTimeWaitTimeout    int32 `json:"tcpTimeWaitTimeout,omitempty"`
+	UDPTimeout            int32 `json:"udpTimeout,omitempty"`
+	UDPStreamTimeout      int32 `json:"udpStreamTimeout,omitempty"`
 }
 
 type VpcNatStatus struct {
@@ -531,6 +583,17 @@ type VpcNatStatus struct {
 	Selector        []string            `json:"selector" patchStrategy:"merge"`
 	Tolerations     []corev1.Toleration `json:"tolerations" patchStrategy:"merge"`
 	Affinity        corev1.Affinity     `json:"affinity" patchStrategy:"merge"`
+	Replicas        int32               `json:"replicas" patchStrategy:"merge"`
+	Mode            VpcNatGwMode        `json:"mode" patchStrategy:"merge"`
+	Conntrack       VpcNatConntrack     `json:"conntrack" patchStrategy:"merge"`
+	// ActivePod is the replica which holds the eips
+	ActivePod string `json:"activePod" patchStrategy:"merge"`
+	// ReadyReplicas are the replicas which the vpc traffic is routed to
+	ReadyReplicas []string `json:"readyReplicas" patchStrategy:"merge"`
+	// LastFailoverTime is the time when the last failover finished
+	LastFailoverTime metav1.Time `json:"lastFailoverTime,omitempty" patchStrategy:"merge"`
+	// FailoverDuration is the time from the failure of the previous active replica to the takeover of the new one
+	FailoverDuration metav1.Duration `json:"failoverDuration,omitempty" patchStrategy:"merge"`
 }
 
 // +genclient
@@ -552,6 +615,9 @@ type IptablesEipSpec struct {
 	NatGwDp        string `json:"natGwDp"`
 	QoSPolicy      string `json:"qosPolicy"`
 	ExternalSubnet string `json:"externalSubnet"`
+	IPPool         string `json:"ipPool,omitempty"`    // ip pool of the external subnet to allocate the eip from
+	Hairpin        bool   `json:"hairpin,omitempty"`   // allow the clients in the vpc to access the fip and dnat of the eip
+	ConnLimit      int32  `json:"connLimit,omitempty"` // max concurrent connections to and snated to the eip, 0 means unlimited
 }
 
 // IptablesEIPCondition describes the state of an object at a certain point.
@@ -566,6 +632,8 @@ type IptablesEipStatus struct {
 	Redo      string `json:"redo" patchStrategy:"merge"`
 	Nat       string `json:"nat" patchStrategy:"merge"`
 	QoSPolicy string `json:"qosPolicy" patchStrategy:"merge"`
+	Hairpin   bool   `json:"hairpin" patchStrategy:"merge"`
+	ConnLimit int32  `json:"connLimit" patchStrategy:"merge"`
 	// Conditions represents the latest state of the object
 	// +optional
 	// +patchMergeKey=type
@@ -597,6 +665,8 @@ type IptablesFIPRule struct {
 type IptablesFIPRuleSpec struct {
 	EIP        string `json:"eip"`
 	InternalIP string `json:"internalIp"`
+	EipPool    string `json:"eipPool,omitempty"` // ip pool to allocate the eip from if eip is not set
+	NatGwDp    string `json:"natGwDp,omitempty"` // nat gateway of the allocated eip
 }
 
 // IptablesFIPRuleCondition describes the state of an object at a certain point.
@@ -644,6 +714,8 @@ type IptablesSnatRule struct {
 type IptablesSnatRuleSpec struct {
 	EIP          string `json:"eip"`
 	InternalCIDR string `json:"internalCIDR"`
+	EipPool      string `json:"eipPool,omitempty"` // ip pool to allocate the eip from if eip is not set
+	NatGwDp      string `json:"natGwDp,omitempty"` // nat gateway of the allocated eip
 }
 
 // IptablesSnatRuleCondition describes the state of an object at a certain point.
@@ -690,10 +762,12 @@ type IptablesDnatRule struct {
 }
 type IptablesDnatRuleSpec struct {
 	EIP          string `json:"eip"`
-	ExternalPort string `json:"externalPort"`
+	ExternalPort string `json:"externalPort"` // port, port range or list of them, e.g. 80,8000-8100
 	Protocol     string `json:"protocol,omitempty"`
 	InternalIP   string `json:"internalIp"`
-	InternalPort string `json:"internalPort"`
+	InternalPort string `json:"internalPort"`      // mapped to the external ports one by one
+	EipPool      string `json:"eipPool,omitempty"` // ip pool to allocate the eip from if eip is not set
+	NatGwDp      string `json:"natGwDp,omitempty"` // nat gateway of the allocated eip
 }
 
 // IptablesDnatRuleCondition describes the state of an object at a certain point.
@@ -955,6 +1029,8 @@ type OvnEipSpec struct {
 	V4Ip           string `json:"v4Ip"`
 	V6Ip           string `json:"v6Ip"`
 	MacAddress     string `json:"macAddress"`
+	IPPool         string `json:"ipPool,omitempty"`  // ip pool of the external subnet to allocate the eip from
+	Hairpin        bool   `json:"hairpin,omitempty"` // allow the clients in the vpc to access the fip and dnat of the eip
 	Type           string `json:"type"`
 	// usage type: lrp, lsp, nat
 	// nat: used by nat: dnat, snat, fip
@@ -1003,11 +1079,13 @@ type OvnFip struct {
 	Status OvnFipStatus `json:"status,omitempty"`
 }
 type OvnFipSpec struct {
-	OvnEip string `json:"ovnEip"`
-	IPType string `json:"ipType"` // vip, ip
-	IPName string `json:"ipName"` // vip, ip crd name
-	Vpc    string `json:"vpc"`
-	V4Ip   string `json:"v4Ip"`
+	OvnEip  string `json:"ovnEip"`
+	IPType  string `json:"ipType"` // vip, ip
+	IPName  string `json:"ipName"` // vip, ip crd name
+	Vpc     string `json:"vpc"`
+	V4Ip    string `json:"v4Ip"`
+	V6Ip    string `json:"v6Ip"`
+	EipPool string `json:"eipPool,omitempty"` // ip pool to allocate the eip from if ovnEip is not set
 }
 
 // OvnFipCondition describes the state of an object at a certain point.
@@ -1019,7 +1097,9 @@ type OvnFipStatus struct {
 	// +patchStrategy=merge
 	Vpc   string `json:"vpc" patchStrategy:"merge"`
 	V4Eip string `json:"v4Eip" patchStrategy:"merge"`
+	V6Eip string `json:"v6Eip" patchStrategy:"merge"`
 	V4Ip  string `json:"v4Ip" patchStrategy:"merge"`
+	V6Ip  string `json:"v6Ip" patchStrategy:"merge"`
 	Ready bool   `json:"ready" patchStrategy:"merge"`
 
 	// Conditions represents the latest state of the object
@@ -1056,7 +1136,9 @@ type OvnSnatRuleSpec struct {
 	VpcSubnet string `json:"vpcSubnet"`
 	IPName    string `json:"ipName"`
 	Vpc       string `json:"vpc"`
-	V4IpCidr  string `json:"v4IpCidr"` // subnet cidr or pod ip address
+	V4IpCidr  string `json:"v4IpCidr"`          // subnet cidr or pod ip address
+	V6IpCidr  string `json:"v6IpCidr"`          // subnet cidr or pod ip address
+	EipPool   string `json:"eipPool,omitempty"` // ip pool to allocate the eip from if ovnEip is not set
 }
 
 // OvnSnatRuleCondition describes the state of an object at a certain point.
@@ -1068,7 +1150,9 @@ type OvnSnatRuleStatus struct {
 	// +patchStrategy=merge
 	Vpc      string `json:"vpc" patchStrategy:"merge"`
 	V4Eip    string `json:"v4Eip" patchStrategy:"merge"`
+	V6Eip    string `json:"v6Eip" patchStrategy:"merge"`
 	V4IpCidr string `json:"v4IpCidr" patchStrategy:"merge"`
+	V6IpCidr string `json:"v6IpCidr" patchStrategy:"merge"`
 	Ready    bool   `json:"ready" patchStrategy:"merge"`
 
 	// Conditions represents the latest state of the object
@@ -1102,13 +1186,15 @@ type OvnDnatRule struct {
 
 type OvnDnatRuleSpec struct {
 	OvnEip       string `json:"ovnEip"`
-	IPType       string `json:"ipType"` // vip, ip
-	IPName       string `json:"ipName"` // vip, ip crd name
-	InternalPort string `json:"internalPort"`
-	ExternalPort string `json:"externalPort"`
+	IPType       string `json:"ipType"`       // vip, ip
+	IPName       string `json:"ipName"`       // vip, ip crd name
+	InternalPort string `json:"internalPort"` // mapped to the external ports one by one
+	ExternalPort string `json:"externalPort"` // port, port range or list of them, e.g. 80,8000-8100
 	Protocol     string `json:"protocol,omitempty"`
 	Vpc          string `json:"vpc"`
 	V4Ip         string `json:"v4Ip"`
+	V6Ip         string `json:"v6Ip"`
+	EipPool      string `json:"eipPool,omitempty"` // ip pool to allocate the eip from if ovnEip is not set
 }
 
 // OvnDnatRuleCondition describes the state of an object at a certain point.
@@ -1121,8 +1207,10 @@ type OvnDnatRuleStatus struct {
 	// +patchStrategy=merge
 	Vpc          string `json:"vpc" patchStrategy:"merge"`
 	V4Eip        string `json:"v4Eip" patchStrategy:"merge"`
+	V6Eip        string `json:"v6Eip" patchStrategy:"merge"`
 	ExternalPort string `json:"externalPort"`
 	V4Ip         string `json:"v4Ip" patchStrategy:"merge"`
+	V6Ip         string `json:"v6Ip" patchStrategy:"merge"`
 	InternalPort string `json:"internalPort"`
 	Protocol     string `json:"protocol,omitempty"`
 	IPName       string `json:"ipName"`
@@ -1160,6 +1248,16 @@ type QoSPolicySpec struct {
 	BandwidthLimitRules QoSPolicyBandwidthLimitRules `json:"bandwidthLimitRules"`
 	Shared              bool                         `json:"shared"`
 	BindingType         QoSPolicyBindingType         `json:"bindingType"`
+
+	// Selector selects the pods or namespaces the policy is bound to, only used by the POD and NAMESPACE binding type
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Subnets are the subnets the policy is bound to, only used by the SUBNET binding type
+	// +optional
+	Subnets []string `json:"subnets,omitempty"`
+	// DSCPMarkingRules mark the dscp of the traffic of the bound pods, only used by the POD, NAMESPACE and SUBNET binding type
+	// +optional
+	DSCPMarkingRules QoSPolicyDSCPMarkingRules `json:"dscpMarkingRules,omitempty"`
 }
 
 // Condition describes the state of an object at a certain point.
@@ -1176,6 +1274,9 @@ type QoSPolicyBandwidthLimitRule struct {
 	Direction  QoSPolicyRuleDirection `json:"direction,omitempty"`
 	MatchType  QoSPolicyRuleMatchType `json:"matchType,omitempty"`
 	MatchValue string                 `json:"matchValue,omitempty"`
+	// Protocol and Ports match the destination port of the traffic, only used by the POD, NAMESPACE and SUBNET binding type
+	Protocol string `json:"protocol,omitempty"`
+	Ports    string `json:"ports,omitempty"`
 }
 
 type QoSPolicyBandwidthLimitRules []*QoSPolicyBandwidthLimitRule
@@ -1188,11 +1289,30 @@ func (s QoSPolicyBandwidthLimitRules) Strings() string {
 	return fmt.Sprintf("%s", resultNames)
 }
 
+// QoSPolicyDSCPMarkingRule describes the rule marking the dscp of the matched traffic.
+type QoSPolicyDSCPMarkingRule struct {
+	Name       string                 `json:"name"`
+	DSCP       int                    `json:"dscp"`
+	Priority   int                    `json:"priority,omitempty"`
+	Direction  QoSPolicyRuleDirection `json:"direction,omitempty"`
+	MatchType  QoSPolicyRuleMatchType `json:"matchType,omitempty"`
+	MatchValue string                 `json:"matchValue,omitempty"`
+	// Protocol and Ports match the destination port of the traffic, the ports is a single port or a range like 10000-20000
+	Protocol string `json:"protocol,omitempty"`
+	Ports    string `json:"ports,omitempty"`
+}
+
+type QoSPolicyDSCPMarkingRules []*QoSPolicyDSCPMarkingRule
+
 type QoSPolicyStatus struct {
 	BandwidthLimitRules QoSPolicyBandwidthLimitRules `json:"bandwidthLimitRules" patchStrategy:"merge"`
 	Shared              bool                         `json:"shared" patchStrategy:"merge"`
 	BindingType         QoSPolicyBindingType         `json:"bindingType"`
 
+	// BoundObjects are the pods, namespaces or subnets the policy is bound to
+	// +optional
+	BoundObjects []string `json:"boundObjects,omitempty"`
+
 	// Conditions represents the latest state of the object
 	// +optional
 	// +patchMergeKey=type
@@ -1208,3 +1328,282 @@ type QoSPolicyList struct {
 
 	Items []QoSPolicy `json:"items"`
 }
+
+// Condition types of the interconnection
+const (
+	// ICDBConnected => the ovn-ic nb and sb databases are connected
+	ICDBConnected = "ICDBConnected"
+	// ICEstablished => the gateway chassis and transit switches are configured
+	ICEstablished = "Established"
+	// ICRoutesSynced => the routes are advertised to and learned from the other azs
+	ICRoutesSynced = "RoutesSynced"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient:nonNamespaced
+
+type InterConnection struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InterConnectionSpec   `json:"spec"`
+	Status InterConnectionStatus `json:"status,omitempty"`
+}
+
+type InterConnectionSpec struct {
+	AzName       string   `json:"azName"`
+	ICDBHosts    []string `json:"icDBHosts"`
+	ICNBPort     int32    `json:"icNBPort,omitempty"`
+	ICSBPort     int32    `json:"icSBPort,omitempty"`
+	GatewayNodes []string `json:"gatewayNodes"`
+	// TSCount is the number of the transit switches expected in the ovn-ic nb db, which are created
+	// by the ovn-ic-db leader according to TS_NUM, the interconnection waits until they exist
+	TSCount   int  `json:"tsCount,omitempty"`
+	AutoRoute bool `json:"autoRoute,omitempty"`
+	// RouteImports are the cidrs allowed to be learned from the other azs, keyed by the az name
+	RouteImports map[string][]string `json:"routeImports,omitempty"`
+	// RouteTables are the route tables of the routes learned from the other azs, keyed by the az name
+	RouteTables map[string]string `json:"routeTables,omitempty"`
+}
+
+type InterConnectionRoute struct {
+	Az         string `json:"az,omitempty"`
+	Prefix     string `json:"prefix"`
+	NextHop    string `json:"nextHop"`
+	RouteTable string `json:"routeTable,omitempty"`
+}
+
+// InterConnectionCondition describes the state of an object at a certain point.
+// +k8s:deepcopy-gen=true
+type InterConnectionCondition Condition
+
+type InterConnectionStatus struct {
+	GatewayChassis   []string               `json:"gatewayChassis,omitempty"`
+	AdvertisedRoutes []string               `json:"advertisedRoutes,omitempty"`
+	LearnedRoutes    []InterConnectionRoute `json:"learnedRoutes,omitempty"`
+
+	// Conditions represents the latest state of the object
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []InterConnectionCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type InterConnectionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []InterConnection `json:"items"`
+}
+
+type TrafficMirrorDirection string
+
+const (
+	// TrafficMirrorDirectionIngress => the traffic to the pods
+	TrafficMirrorDirectionIngress TrafficMirrorDirection = "ingress"
+	// TrafficMirrorDirectionEgress => the traffic from the pods
+	TrafficMirrorDirectionEgress TrafficMirrorDirection = "egress"
+	// TrafficMirrorDirectionBoth => the traffic to and from the pods
+	TrafficMirrorDirectionBoth TrafficMirrorDirection = "both"
+)
+
+type TrafficMirrorType string
+
+const (
+	TrafficMirrorTypeErspan TrafficMirrorType = "erspan"
+	TrafficMirrorTypeGre    TrafficMirrorType = "gre"
+	TrafficMirrorTypeVxlan  TrafficMirrorType = "vxlan"
+)
+
+// TrafficMirrorBackend is what mirrors the traffic, the ovn mirrors are used by erspan and gre,
+// while vxlan which is not supported by ovn falls back to the ovs mirrors configured on the nodes
+type TrafficMirrorBackend string
+
+const (
+	TrafficMirrorBackendOVN TrafficMirrorBackend = "ovn"
+	TrafficMirrorBackendOVS TrafficMirrorBackend = "ovs"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient:nonNamespaced
+
+type TrafficMirror struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TrafficMirrorSpec   `json:"spec"`
+	Status TrafficMirrorStatus `json:"status,omitempty"`
+}
+
+type TrafficMirrorSpec struct {
+	// NamespaceSelector selects the namespaces of the mirrored pods, pods in all namespaces are selected if not set
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Selector selects the mirrored pods by labels
+	Selector *metav1.LabelSelector `json:"selector"`
+	// Direction is the direction of the mirrored traffic, all the packets of the selected pods in the direction
+	// are mirrored since neither the ovn mirrors nor the ovs mirrors are able to filter packets
+	Direction TrafficMirrorDirection `json:"direction"`
+	Type      TrafficMirrorType      `json:"type"`
+	// Remote is the ip address of the collector
+	Remote string `json:"remote"`
+	// Key is the tunnel key of gre and vxlan, or the session id of erspan
+	Key int `json:"key,omitempty"`
+}
+
+type TrafficMirrorNode struct {
+	Node  string   `json:"node"`
+	Ports []string `json:"ports"`
+}
+
+// TrafficMirrorCondition describes the state of an object at a certain point.
+// +k8s:deepcopy-gen=true
+type TrafficMirrorCondition Condition
+
+type TrafficMirrorStatus struct {
+	Backend TrafficMirrorBackend `json:"backend,omitempty"`
+	// Nodes are the mirrored logical switch ports grouped by the nodes of the pods
+	Nodes []TrafficMirrorNode `json:"nodes"`
+
+	// Conditions represents the latest state of the object
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []TrafficMirrorCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type TrafficMirrorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TrafficMirror `json:"items"`
+}
+
+type ConnectivityCheckProtocol string
+
+const (
+	ConnectivityCheckProtocolICMP ConnectivityCheckProtocol = "icmp"
+	ConnectivityCheckProtocolTCP  ConnectivityCheckProtocol = "tcp"
+	ConnectivityCheckProtocolUDP  ConnectivityCheckProtocol = "udp"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient:nonNamespaced
+
+type ConnectivityCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ConnectivityCheckSpec   `json:"spec"`
+	Status ConnectivityCheckStatus `json:"status,omitempty"`
+}
+
+type ConnectivityCheckSpec struct {
+	Source      ConnectivityCheckSource      `json:"source,omitempty"`
+	Destination ConnectivityCheckDestination `json:"destination"`
+	Protocol    ConnectivityCheckProtocol    `json:"protocol"`
+	// Port is the destination port of tcp and udp checks, services are checked on all their ports of the protocol if not set
+	Port int `json:"port,omitempty"`
+	// Interval is the seconds between two rounds of the check
+	Interval int                  `json:"interval,omitempty"`
+	SLO      ConnectivityCheckSLO `json:"slo,omitempty"`
+}
+
+// ConnectivityCheckSource selects the nodes whose kube-ovn-pinger pods run the check, the probes are sent from
+// the kube-ovn-pinger pods in the default vpc, so sources in custom vpcs are not supported and destinations in
+// custom vpcs are reachable only if they are routed from the default vpc
+type ConnectivityCheckSource struct {
+	// NodeSelector selects the source nodes, all nodes are selected if not set
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+}
+
+type ConnectivityCheckDestination struct {
+	// NamespaceSelector selects the namespaces of the destination pods, pods in all namespaces are selected if not set
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Selector selects the destination pods by labels
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Subnets selects the destination pods by the subnets of their addresses, which may be subnets of
+	// attachment networks, or subnets of custom vpcs routed from the default vpc
+	Subnets []string `json:"subnets,omitempty"`
+	// Services are the destination services in the format of namespace/name
+	Services []string `json:"services,omitempty"`
+}
+
+type ConnectivityCheckSLO struct {
+	// MaxLossPercent is the max percentage of failed probes of a source and destination pair
+	MaxLossPercent int `json:"maxLossPercent,omitempty"`
+	// MaxLatency is the max p99 latency in milliseconds of a source and destination pair
+	MaxLatency int `json:"maxLatency,omitempty"`
+}
+
+// ConnectivityCheckResult is the result of the probes sent from a source node to a destination address,
+// which covers the latest probes in a sliding window
+type ConnectivityCheckResult struct {
+	// Destination is the pod or service in the format of pod/namespace/name or service/namespace/name
+	Destination string `json:"destination"`
+	// Target is the address checked, with the port for tcp and udp
+	Target     string `json:"target"`
+	Sent       int    `json:"sent"`
+	Received   int    `json:"received"`
+	LatencyP50 string `json:"latencyP50,omitempty"`
+	LatencyP90 string `json:"latencyP90,omitempty"`
+	LatencyP99 string `json:"latencyP99,omitempty"`
+	// SLOMet is whether the loss and the latency of the pair are within the slo
+	SLOMet             bool         `json:"sloMet"`
+	LastFailureTime    *metav1.Time `json:"lastFailureTime,omitempty"`
+	LastFailureMessage string       `json:"lastFailureMessage,omitempty"`
+}
+
+type ConnectivityCheckNodeResult struct {
+	LastCheckTime metav1.Time `json:"lastCheckTime"`
+	// Total and Failing are the numbers of all the pairs of the node and the ones violating the slo
+	Total   int `json:"total"`
+	Failing int `json:"failing"`
+	// LatencyP50, LatencyP90 and LatencyP99 are calculated from the probes of all the pairs of the node
+	LatencyP50 string `json:"latencyP50,omitempty"`
+	LatencyP90 string `json:"latencyP90,omitempty"`
+	LatencyP99 string `json:"latencyP99,omitempty"`
+	// Results are the failing pairs only, the number of which is limited by sharing a budget among
+	// all the source nodes to bound the size of the status
+	Results []ConnectivityCheckResult `json:"results,omitempty"`
+}
+
+type ConnectivityCheckPair struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Target      string `json:"target"`
+}
+
+// ConnectivityCheckCondition describes the state of an object at a certain point.
+// +k8s:deepcopy-gen=true
+type ConnectivityCheckCondition Condition
+
+type ConnectivityCheckStatus struct {
+	// Nodes are the results keyed by the source nodes, each of which is reported by the kube-ovn-pinger on the node
+	Nodes map[string]ConnectivityCheckNodeResult `json:"nodes,omitempty"`
+	// TotalPairs and FailingPairs are aggregated from the results of all the source nodes by kube-ovn-controller,
+	// at most 100 failing pairs are listed
+	TotalPairs   int                     `json:"totalPairs"`
+	FailingPairs []ConnectivityCheckPair `json:"failingPairs,omitempty"`
+
+	// Conditions represents the latest state of the object
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []ConnectivityCheckCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type ConnectivityCheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ConnectivityCheck `json:"items"`
+}
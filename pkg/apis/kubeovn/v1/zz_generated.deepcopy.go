@@ -23,6 +23,7 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -60,6 +61,272 @@ func (in *Condition) DeepCopy() *Condition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheck) DeepCopyInto(out *ConnectivityCheck) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheck.
+func (in *ConnectivityCheck) DeepCopy() *ConnectivityCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConnectivityCheck) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckCondition) DeepCopyInto(out *ConnectivityCheckCondition) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckCondition.
+func (in *ConnectivityCheckCondition) DeepCopy() *ConnectivityCheckCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckDestination) DeepCopyInto(out *ConnectivityCheckDestination) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckDestination.
+func (in *ConnectivityCheckDestination) DeepCopy() *ConnectivityCheckDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckList) DeepCopyInto(out *ConnectivityCheckList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ConnectivityCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckList.
+func (in *ConnectivityCheckList) DeepCopy() *ConnectivityCheckList {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConnectivityCheckList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckNodeResult) DeepCopyInto(out *ConnectivityCheckNodeResult) {
+	*out = *in
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
+	if in.Results != nil {
+		in, out := &in.Results, &out.Results
+		*out = make([]ConnectivityCheckResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckNodeResult.
+func (in *ConnectivityCheckNodeResult) DeepCopy() *ConnectivityCheckNodeResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckNodeResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckPair) DeepCopyInto(out *ConnectivityCheckPair) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckPair.
+func (in *ConnectivityCheckPair) DeepCopy() *ConnectivityCheckPair {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckPair)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckResult) DeepCopyInto(out *ConnectivityCheckResult) {
+	*out = *in
+	if in.LastFailureTime != nil {
+		in, out := &in.LastFailureTime, &out.LastFailureTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckResult.
+func (in *ConnectivityCheckResult) DeepCopy() *ConnectivityCheckResult {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckSLO) DeepCopyInto(out *ConnectivityCheckSLO) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckSLO.
+func (in *ConnectivityCheckSLO) DeepCopy() *ConnectivityCheckSLO {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckSLO)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckSource) DeepCopyInto(out *ConnectivityCheckSource) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckSource.
+func (in *ConnectivityCheckSource) DeepCopy() *ConnectivityCheckSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckSpec) DeepCopyInto(out *ConnectivityCheckSpec) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+	in.Destination.DeepCopyInto(&out.Destination)
+	out.SLO = in.SLO
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckSpec.
+func (in *ConnectivityCheckSpec) DeepCopy() *ConnectivityCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectivityCheckStatus) DeepCopyInto(out *ConnectivityCheckStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make(map[string]ConnectivityCheckNodeResult, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.FailingPairs != nil {
+		in, out := &in.FailingPairs, &out.FailingPairs
+		*out = make([]ConnectivityCheckPair, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ConnectivityCheckCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectivityCheckStatus.
+func (in *ConnectivityCheckStatus) DeepCopy() *ConnectivityCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectivityCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CustomInterface) DeepCopyInto(out *CustomInterface) {
 	*out = *in
@@ -263,43 +530,224 @@ func (in *IPPoolStatus) DeepCopyInto(out *IPPoolStatus) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPoolStatus.
-func (in *IPPoolStatus) DeepCopy() *IPPoolStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPoolStatus.
+func (in *IPPoolStatus) DeepCopy() *IPPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IPPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPSpec) DeepCopyInto(out *IPSpec) {
+	*out = *in
+	if in.AttachSubnets != nil {
+		in, out := &in.AttachSubnets, &out.AttachSubnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AttachIPs != nil {
+		in, out := &in.AttachIPs, &out.AttachIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AttachMacs != nil {
+		in, out := &in.AttachMacs, &out.AttachMacs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPSpec.
+func (in *IPSpec) DeepCopy() *IPSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IPSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterConnection) DeepCopyInto(out *InterConnection) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterConnection.
+func (in *InterConnection) DeepCopy() *InterConnection {
+	if in == nil {
+		return nil
+	}
+	out := new(InterConnection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InterConnection) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterConnectionCondition) DeepCopyInto(out *InterConnectionCondition) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterConnectionCondition.
+func (in *InterConnectionCondition) DeepCopy() *InterConnectionCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(InterConnectionCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterConnectionList) DeepCopyInto(out *InterConnectionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InterConnection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterConnectionList.
+func (in *InterConnectionList) DeepCopy() *InterConnectionList {
+	if in == nil {
+		return nil
+	}
+	out := new(InterConnectionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InterConnectionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterConnectionRoute) DeepCopyInto(out *InterConnectionRoute) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterConnectionRoute.
+func (in *InterConnectionRoute) DeepCopy() *InterConnectionRoute {
+	if in == nil {
+		return nil
+	}
+	out := new(InterConnectionRoute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterConnectionSpec) DeepCopyInto(out *InterConnectionSpec) {
+	*out = *in
+	if in.ICDBHosts != nil {
+		in, out := &in.ICDBHosts, &out.ICDBHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GatewayNodes != nil {
+		in, out := &in.GatewayNodes, &out.GatewayNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RouteImports != nil {
+		in, out := &in.RouteImports, &out.RouteImports
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.RouteTables != nil {
+		in, out := &in.RouteTables, &out.RouteTables
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterConnectionSpec.
+func (in *InterConnectionSpec) DeepCopy() *InterConnectionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IPPoolStatus)
+	out := new(InterConnectionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IPSpec) DeepCopyInto(out *IPSpec) {
+func (in *InterConnectionStatus) DeepCopyInto(out *InterConnectionStatus) {
 	*out = *in
-	if in.AttachSubnets != nil {
-		in, out := &in.AttachSubnets, &out.AttachSubnets
+	if in.GatewayChassis != nil {
+		in, out := &in.GatewayChassis, &out.GatewayChassis
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.AttachIPs != nil {
-		in, out := &in.AttachIPs, &out.AttachIPs
+	if in.AdvertisedRoutes != nil {
+		in, out := &in.AdvertisedRoutes, &out.AdvertisedRoutes
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.AttachMacs != nil {
-		in, out := &in.AttachMacs, &out.AttachMacs
-		*out = make([]string, len(*in))
+	if in.LearnedRoutes != nil {
+		in, out := &in.LearnedRoutes, &out.LearnedRoutes
+		*out = make([]InterConnectionRoute, len(*in))
 		copy(*out, *in)
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]InterConnectionCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPSpec.
-func (in *IPSpec) DeepCopy() *IPSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterConnectionStatus.
+func (in *InterConnectionStatus) DeepCopy() *InterConnectionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IPSpec)
+	out := new(InterConnectionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1546,6 +1994,48 @@ func (in *QoSPolicyCondition) DeepCopy() *QoSPolicyCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QoSPolicyDSCPMarkingRule) DeepCopyInto(out *QoSPolicyDSCPMarkingRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QoSPolicyDSCPMarkingRule.
+func (in *QoSPolicyDSCPMarkingRule) DeepCopy() *QoSPolicyDSCPMarkingRule {
+	if in == nil {
+		return nil
+	}
+	out := new(QoSPolicyDSCPMarkingRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in QoSPolicyDSCPMarkingRules) DeepCopyInto(out *QoSPolicyDSCPMarkingRules) {
+	{
+		in := &in
+		*out = make(QoSPolicyDSCPMarkingRules, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(QoSPolicyDSCPMarkingRule)
+				**out = **in
+			}
+		}
+		return
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QoSPolicyDSCPMarkingRules.
+func (in QoSPolicyDSCPMarkingRules) DeepCopy() QoSPolicyDSCPMarkingRules {
+	if in == nil {
+		return nil
+	}
+	out := new(QoSPolicyDSCPMarkingRules)
+	in.DeepCopyInto(out)
+	return *out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QoSPolicyList) DeepCopyInto(out *QoSPolicyList) {
 	*out = *in
@@ -1593,6 +2083,27 @@ func (in *QoSPolicySpec) DeepCopyInto(out *QoSPolicySpec) {
 			}
 		}
 	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Subnets != nil {
+		in, out := &in.Subnets, &out.Subnets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DSCPMarkingRules != nil {
+		in, out := &in.DSCPMarkingRules, &out.DSCPMarkingRules
+		*out = make(QoSPolicyDSCPMarkingRules, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(QoSPolicyDSCPMarkingRule)
+				**out = **in
+			}
+		}
+	}
 	return
 }
 
@@ -1620,6 +2131,11 @@ func (in *QoSPolicyStatus) DeepCopyInto(out *QoSPolicyStatus) {
 			}
 		}
 	}
+	if in.BoundObjects != nil {
+		in, out := &in.BoundObjects, &out.BoundObjects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]QoSPolicyCondition, len(*in))
@@ -2094,6 +2610,162 @@ func (in *SwitchLBRuleStatus) DeepCopy() *SwitchLBRuleStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficMirror) DeepCopyInto(out *TrafficMirror) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficMirror.
+func (in *TrafficMirror) DeepCopy() *TrafficMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TrafficMirror) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficMirrorCondition) DeepCopyInto(out *TrafficMirrorCondition) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficMirrorCondition.
+func (in *TrafficMirrorCondition) DeepCopy() *TrafficMirrorCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficMirrorCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficMirrorList) DeepCopyInto(out *TrafficMirrorList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TrafficMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficMirrorList.
+func (in *TrafficMirrorList) DeepCopy() *TrafficMirrorList {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficMirrorList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TrafficMirrorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficMirrorNode) DeepCopyInto(out *TrafficMirrorNode) {
+	*out = *in
+	if in.Ports != nil {
+		in, out := &in.Ports, &out.Ports
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficMirrorNode.
+func (in *TrafficMirrorNode) DeepCopy() *TrafficMirrorNode {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficMirrorNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficMirrorSpec) DeepCopyInto(out *TrafficMirrorSpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficMirrorSpec.
+func (in *TrafficMirrorSpec) DeepCopy() *TrafficMirrorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficMirrorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrafficMirrorStatus) DeepCopyInto(out *TrafficMirrorStatus) {
+	*out = *in
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]TrafficMirrorNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]TrafficMirrorCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrafficMirrorStatus.
+func (in *TrafficMirrorStatus) DeepCopy() *TrafficMirrorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TrafficMirrorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Vip) DeepCopyInto(out *Vip) {
 	*out = *in
@@ -2537,6 +3209,22 @@ func (in *VpcList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VpcNatConntrack) DeepCopyInto(out *VpcNatConntrack) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VpcNatConntrack.
+func (in *VpcNatConntrack) DeepCopy() *VpcNatConntrack {
+	if in == nil {
+		return nil
+	}
+	out := new(VpcNatConntrack)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VpcNatGateway) DeepCopyInto(out *VpcNatGateway) {
 	*out = *in
@@ -2619,6 +3307,7 @@ func (in *VpcNatSpec) DeepCopyInto(out *VpcNatSpec) {
 		}
 	}
 	in.Affinity.DeepCopyInto(&out.Affinity)
+	out.Conntrack = in.Conntrack
 	return
 }
 
@@ -2653,6 +3342,14 @@ func (in *VpcNatStatus) DeepCopyInto(out *VpcNatStatus) {
 		}
 	}
 	in.Affinity.DeepCopyInto(&out.Affinity)
+	out.Conntrack = in.Conntrack
+	if in.ReadyReplicas != nil {
+		in, out := &in.ReadyReplicas, &out.ReadyReplicas
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastFailoverTime.DeepCopyInto(&out.LastFailoverTime)
+	out.FailoverDuration = in.FailoverDuration
 	return
 }
 
@@ -2666,6 +3363,43 @@ func (in *VpcNatStatus) DeepCopy() *VpcNatStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VpcICPeering) DeepCopyInto(out *VpcICPeering) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VpcICPeering.
+func (in *VpcICPeering) DeepCopy() *VpcICPeering {
+	if in == nil {
+		return nil
+	}
+	out := new(VpcICPeering)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VpcICPeeringStatus) DeepCopyInto(out *VpcICPeeringStatus) {
+	*out = *in
+	if in.LearnedPrefixes != nil {
+		in, out := &in.LearnedPrefixes, &out.LearnedPrefixes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VpcICPeeringStatus.
+func (in *VpcICPeeringStatus) DeepCopy() *VpcICPeeringStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VpcICPeeringStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VpcPeering) DeepCopyInto(out *VpcPeering) {
 	*out = *in
@@ -2723,6 +3457,17 @@ func (in *VpcSpec) DeepCopyInto(out *VpcSpec) {
 			}
 		}
 	}
+	if in.ICPeerings != nil {
+		in, out := &in.ICPeerings, &out.ICPeerings
+		*out = make([]*VpcICPeering, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(VpcICPeering)
+				**out = **in
+			}
+		}
+	}
 	return
 }
 
@@ -2756,6 +3501,13 @@ func (in *VpcStatus) DeepCopyInto(out *VpcStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ICPeerings != nil {
+		in, out := &in.ICPeerings, &out.ICPeerings
+		*out = make([]VpcICPeeringStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -28,6 +28,10 @@ type FakeKubeovnV1 struct {
 	*testing.Fake
 }
 
+func (c *FakeKubeovnV1) ConnectivityChecks() v1.ConnectivityCheckInterface {
+	return &FakeConnectivityChecks{c}
+}
+
 func (c *FakeKubeovnV1) IPs() v1.IPInterface {
 	return &FakeIPs{c}
 }
@@ -36,6 +40,10 @@ func (c *FakeKubeovnV1) IPPools() v1.IPPoolInterface {
 	return &FakeIPPools{c}
 }
 
+func (c *FakeKubeovnV1) InterConnections() v1.InterConnectionInterface {
+	return &FakeInterConnections{c}
+}
+
 func (c *FakeKubeovnV1) IptablesDnatRules() v1.IptablesDnatRuleInterface {
 	return &FakeIptablesDnatRules{c}
 }
@@ -88,6 +96,10 @@ func (c *FakeKubeovnV1) SwitchLBRules() v1.SwitchLBRuleInterface {
 	return &FakeSwitchLBRules{c}
 }
 
+func (c *FakeKubeovnV1) TrafficMirrors() v1.TrafficMirrorInterface {
+	return &FakeTrafficMirrors{c}
+}
+
 func (c *FakeKubeovnV1) Vips() v1.VipInterface {
 	return &FakeVips{c}
 }
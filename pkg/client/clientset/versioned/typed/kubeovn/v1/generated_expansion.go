@@ -18,10 +18,14 @@ limitations under the License.
 
 package v1
 
+type ConnectivityCheckExpansion interface{}
+
 type IPExpansion interface{}
 
 type IPPoolExpansion interface{}
 
+type InterConnectionExpansion interface{}
+
 type IptablesDnatRuleExpansion interface{}
 
 type IptablesEIPExpansion interface{}
@@ -48,6 +52,8 @@ type SubnetExpansion interface{}
 
 type SwitchLBRuleExpansion interface{}
 
+type TrafficMirrorExpansion interface{}
+
 type VipExpansion interface{}
 
 type VlanExpansion interface{}
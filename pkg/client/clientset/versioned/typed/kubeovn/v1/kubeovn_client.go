@@ -28,8 +28,10 @@ import (
 
 type KubeovnV1Interface interface {
 	RESTClient() rest.Interface
+	ConnectivityChecksGetter
 	IPsGetter
 	IPPoolsGetter
+	InterConnectionsGetter
 	IptablesDnatRulesGetter
 	IptablesEIPsGetter
 	IptablesFIPRulesGetter
@@ -43,6 +45,7 @@ type KubeovnV1Interface interface {
 	SecurityGroupsGetter
 	SubnetsGetter
 	SwitchLBRulesGetter
+	TrafficMirrorsGetter
 	VipsGetter
 	VlansGetter
 	VpcsGetter
@@ -55,6 +58,10 @@ type KubeovnV1Client struct {
 	restClient rest.Interface
 }
 
+func (c *KubeovnV1Client) ConnectivityChecks() ConnectivityCheckInterface {
+	return newConnectivityChecks(c)
+}
+
 func (c *KubeovnV1Client) IPs() IPInterface {
 	return newIPs(c)
 }
@@ -63,6 +70,10 @@ func (c *KubeovnV1Client) IPPools() IPPoolInterface {
 	return newIPPools(c)
 }
 
+func (c *KubeovnV1Client) InterConnections() InterConnectionInterface {
+	return newInterConnections(c)
+}
+
 func (c *KubeovnV1Client) IptablesDnatRules() IptablesDnatRuleInterface {
 	return newIptablesDnatRules(c)
 }
@@ -115,6 +126,10 @@ func (c *KubeovnV1Client) SwitchLBRules() SwitchLBRuleInterface {
 	return newSwitchLBRules(c)
 }
 
+func (c *KubeovnV1Client) TrafficMirrors() TrafficMirrorInterface {
+	return newTrafficMirrors(c)
+}
+
 func (c *KubeovnV1Client) Vips() VipInterface {
 	return newVips(c)
 }
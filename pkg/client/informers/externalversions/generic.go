@@ -53,10 +53,14 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=kubeovn.io, Version=v1
+	case v1.SchemeGroupVersion.WithResource("connectivity-checks"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().ConnectivityChecks().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("ips"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().IPs().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("ippools"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().IPPools().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("interconnections"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().InterConnections().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("iptables-dnat-rules"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().IptablesDnatRules().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("iptables-eips"):
@@ -83,6 +87,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().Subnets().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("switch-lb-rules"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().SwitchLBRules().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("traffic-mirrors"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().TrafficMirrors().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("vips"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Kubeovn().V1().Vips().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("vlans"):
@@ -24,10 +24,14 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ConnectivityChecks returns a ConnectivityCheckInformer.
+	ConnectivityChecks() ConnectivityCheckInformer
 	// IPs returns a IPInformer.
 	IPs() IPInformer
 	// IPPools returns a IPPoolInformer.
 	IPPools() IPPoolInformer
+	// InterConnections returns a InterConnectionInformer.
+	InterConnections() InterConnectionInformer
 	// IptablesDnatRules returns a IptablesDnatRuleInformer.
 	IptablesDnatRules() IptablesDnatRuleInformer
 	// IptablesEIPs returns a IptablesEIPInformer.
@@ -54,6 +58,8 @@ type Interface interface {
 	Subnets() SubnetInformer
 	// SwitchLBRules returns a SwitchLBRuleInformer.
 	SwitchLBRules() SwitchLBRuleInformer
+	// TrafficMirrors returns a TrafficMirrorInformer.
+	TrafficMirrors() TrafficMirrorInformer
 	// Vips returns a VipInformer.
 	Vips() VipInformer
 	// Vlans returns a VlanInformer.
@@ -77,6 +83,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ConnectivityChecks returns a ConnectivityCheckInformer.
+func (v *version) ConnectivityChecks() ConnectivityCheckInformer {
+	return &connectivityCheckInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // IPs returns a IPInformer.
 func (v *version) IPs() IPInformer {
 	return &iPInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -87,6 +98,11 @@ func (v *version) IPPools() IPPoolInformer {
 	return &iPPoolInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// InterConnections returns a InterConnectionInformer.
+func (v *version) InterConnections() InterConnectionInformer {
+	return &interConnectionInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // IptablesDnatRules returns a IptablesDnatRuleInformer.
 func (v *version) IptablesDnatRules() IptablesDnatRuleInformer {
 	return &iptablesDnatRuleInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -152,6 +168,11 @@ func (v *version) SwitchLBRules() SwitchLBRuleInformer {
 	return &switchLBRuleInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// TrafficMirrors returns a TrafficMirrorInformer.
+func (v *version) TrafficMirrors() TrafficMirrorInformer {
+	return &trafficMirrorInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // Vips returns a VipInformer.
 func (v *version) Vips() VipInformer {
 	return &vipInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -18,6 +18,10 @@ limitations under the License.
 
 package v1
 
+// ConnectivityCheckListerExpansion allows custom methods to be added to
+// ConnectivityCheckLister.
+type ConnectivityCheckListerExpansion interface{}
+
 // IPListerExpansion allows custom methods to be added to
 // IPLister.
 type IPListerExpansion interface{}
@@ -26,6 +30,10 @@ type IPListerExpansion interface{}
 // IPPoolLister.
 type IPPoolListerExpansion interface{}
 
+// InterConnectionListerExpansion allows custom methods to be added to
+// InterConnectionLister.
+type InterConnectionListerExpansion interface{}
+
 // IptablesDnatRuleListerExpansion allows custom methods to be added to
 // IptablesDnatRuleLister.
 type IptablesDnatRuleListerExpansion interface{}
@@ -78,6 +86,10 @@ type SubnetListerExpansion interface{}
 // SwitchLBRuleLister.
 type SwitchLBRuleListerExpansion interface{}
 
+// TrafficMirrorListerExpansion allows custom methods to be added to
+// TrafficMirrorLister.
+type TrafficMirrorListerExpansion interface{}
+
 // VipListerExpansion allows custom methods to be added to
 // VipLister.
 type VipListerExpansion interface{}
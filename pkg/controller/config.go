@@ -67,6 +67,7 @@ type Configuration struct {
 	WorkerNum       int
 	PprofPort       int
 	EnablePprof     bool
+	EnableFlowTrace bool
 	NodePgProbeTime int
 
 	NetworkType             string
@@ -87,6 +88,9 @@ type Configuration struct {
 	EnableLbSvc       bool
 	EnableMetrics     bool
 
+	EnableLiveMigrationOptimize bool
+	EnableOVNBandwidthLimit     bool
+
 	ExternalGatewaySwitch   string
 	ExternalGatewayConfigNS string
 	ExternalGatewayNet      string
@@ -139,6 +143,7 @@ func ParseFlags() (*Configuration, error) {
 		argWorkerNum       = pflag.Int("worker-num", 3, "The parallelism of each worker")
 		argEnablePprof     = pflag.Bool("enable-pprof", false, "Enable pprof")
 		argPprofPort       = pflag.Int("pprof-port", 10660, "The port to get profiling data")
+		argEnableFlowTrace = pflag.Bool("enable-flow-trace", false, "Enable the flow trace api /api/v1/trace on the pprof port of the leader, which is served without authentication to anyone able to reach the pprof port")
 		argNodePgProbeTime = pflag.Int("nodepg-probe-time", 1, "The probe interval for node port-group, the unit is minute")
 
 		argNetworkType             = pflag.String("network-type", util.NetworkTypeGeneve, "The ovn network type")
@@ -159,6 +164,9 @@ func ParseFlags() (*Configuration, error) {
 		argEnableLbSvc             = pflag.Bool("enable-lb-svc", false, "Whether to support loadbalancer service")
 		argEnableMetrics           = pflag.Bool("enable-metrics", true, "Whether to support metrics query")
 
+		argEnableLiveMigrationOptimize = pflag.Bool("enable-live-migration-optimize", true, "Whether to bind the port of kubevirt vm to both the source and the target node during live migration, it takes effect only with --keep-vm-ip")
+		argEnableOVNBandwidthLimit     = pflag.Bool("enable-ovn-bandwidth-limit", false, "Whether to program the pod bandwidth limits as ovn qos rules of the logical switch ports instead of ovs interface policing and queues on nodes")
+
 		argExternalGatewayConfigNS = pflag.String("external-gateway-config-ns", "kube-system", "The namespace of configmap external-gateway-config, default: kube-system")
 		argExternalGatewaySwitch   = pflag.String("external-gateway-switch", "external", "The name of the external gateway switch which is a ovs bridge to provide external network, default: external")
 		argExternalGatewayNet      = pflag.String("external-gateway-net", "external", "The name of the external network which mappings with an ovs bridge, default: external")
@@ -219,6 +227,7 @@ func ParseFlags() (*Configuration, error) {
 		WorkerNum:                      *argWorkerNum,
 		EnablePprof:                    *argEnablePprof,
 		PprofPort:                      *argPprofPort,
+		EnableFlowTrace:                *argEnableFlowTrace,
 		NetworkType:                    *argNetworkType,
 		DefaultVlanID:                  *argDefaultVlanID,
 		LsDnatModDlDst:                 *argLsDnatModDlDst,
@@ -240,6 +249,8 @@ func ParseFlags() (*Configuration, error) {
 		ExternalGatewayVlanID:          *argExternalGatewayVlanID,
 		EnableEcmp:                     *argEnableEcmp,
 		EnableKeepVMIP:                 *argKeepVMIP,
+		EnableLiveMigrationOptimize:    *argEnableLiveMigrationOptimize,
+		EnableOVNBandwidthLimit:        *argEnableOVNBandwidthLimit,
 		NodePgProbeTime:                *argNodePgProbeTime,
 		GCInterval:                     *argGCInterval,
 		InspectInterval:                *argInspectInterval,
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+const (
+	// connectivityCheckSyncInterval is the interval to remove the results of the nodes which stop reporting
+	connectivityCheckSyncInterval = time.Minute
+	// connectivityCheckStaleRounds is the number of rounds after which the result of a node is considered stale
+	connectivityCheckStaleRounds = 3
+	// connectivityCheckMaxFailingPairs is the max number of the failing pairs in the status
+	connectivityCheckMaxFailingPairs = 100
+)
+
+func (c *Controller) enqueueAddConnectivityCheck(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	klog.V(3).Infof("enqueue add connectivity check %s", key)
+	c.updateConnectivityCheckQueue.Add(key)
+}
+
+func (c *Controller) enqueueUpdateConnectivityCheck(oldObj, newObj interface{}) {
+	oldCC := oldObj.(*kubeovnv1.ConnectivityCheck)
+	newCC := newObj.(*kubeovnv1.ConnectivityCheck)
+	if oldCC.ResourceVersion == newCC.ResourceVersion ||
+		(reflect.DeepEqual(oldCC.Spec, newCC.Spec) && reflect.DeepEqual(oldCC.Status.Nodes, newCC.Status.Nodes)) {
+		return
+	}
+
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(newObj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	klog.V(3).Infof("enqueue update connectivity check %s", key)
+	c.updateConnectivityCheckQueue.Add(key)
+}
+
+func (c *Controller) runUpdateConnectivityCheckWorker() {
+	for c.processNextWorkItem("updateConnectivityCheck", c.updateConnectivityCheckQueue, c.handleUpdateConnectivityCheck) {
+	}
+}
+
+// resyncConnectivityChecks enqueues all connectivity checks, since the results of the nodes
+// which are deleted or whose kube-ovn-pinger stops running are never updated
+func (c *Controller) resyncConnectivityChecks() {
+	connectivityChecks, err := c.connectivityChecksLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list connectivity checks, %v", err)
+		return
+	}
+	for _, cc := range connectivityChecks {
+		c.updateConnectivityCheckQueue.Add(cc.Name)
+	}
+}
+
+// handleUpdateConnectivityCheck aggregates the results reported by kube-ovn-pinger into the status matrix
+func (c *Controller) handleUpdateConnectivityCheck(key string) error {
+	cachedCC, err := c.connectivityChecksLister.Get(key)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Error(err)
+		return err
+	}
+	klog.V(3).Infof("handle update connectivity check %s", key)
+
+	cc := cachedCC.DeepCopy()
+	if err = util.ValidateConnectivityCheck(cc); err != nil {
+		klog.Errorf("failed to validate connectivity check %s, %v", key, err)
+		cc.Status.ClearCondition(kubeovnv1.Ready, "ValidationFailed", err.Error())
+		return c.patchConnectivityCheckStatus(cachedCC, &cc.Status, nil)
+	}
+
+	nodes, err := c.nodesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list nodes, %v", err)
+		return err
+	}
+	staleNodes := aggregateConnectivityCheck(cc, nodes, time.Now())
+	return c.patchConnectivityCheckStatus(cachedCC, &cc.Status, staleNodes)
+}
+
+// aggregateConnectivityCheck updates the failing pairs and the conditions in the status by the results of the nodes,
+// and returns the nodes whose results should be removed since they are deleted, not selected or stop reporting
+func aggregateConnectivityCheck(cc *kubeovnv1.ConnectivityCheck, nodes []*v1.Node, now time.Time) []string {
+	selector, _ := metav1.LabelSelectorAsSelector(cc.Spec.Source.NodeSelector)
+	if cc.Spec.Source.NodeSelector == nil {
+		selector = labels.Everything()
+	}
+	selected := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		if selector.Matches(labels.Set(node.Labels)) {
+			selected[node.Name] = true
+		}
+	}
+
+	interval := cc.Spec.Interval
+	if interval == 0 {
+		interval = util.ConnectivityCheckDefaultInterval
+	}
+	deadline := now.Add(-time.Duration(connectivityCheckStaleRounds*interval) * time.Second)
+
+	var staleNodes []string
+	var failingPairs int
+	cc.Status.TotalPairs = 0
+	cc.Status.FailingPairs = nil
+	for node, result := range cc.Status.Nodes {
+		if !selected[node] || result.LastCheckTime.Time.Before(deadline) {
+			staleNodes = append(staleNodes, node)
+			continue
+		}
+		// the results reported by kube-ovn-pinger are capped, while the numbers of all the results are reported
+		var failing int
+		for _, r := range result.Results {
+			if !r.SLOMet {
+				failing++
+				cc.Status.FailingPairs = append(cc.Status.FailingPairs, kubeovnv1.ConnectivityCheckPair{
+					Source:      node,
+					Destination: r.Destination,
+					Target:      r.Target,
+				})
+			}
+		}
+		cc.Status.TotalPairs += max(result.Total, len(result.Results))
+		failingPairs += max(result.Failing, failing)
+	}
+	for _, node := range staleNodes {
+		delete(cc.Status.Nodes, node)
+	}
+	sort.Strings(staleNodes)
+	sort.Slice(cc.Status.FailingPairs, func(i, j int) bool {
+		x, y := cc.Status.FailingPairs[i], cc.Status.FailingPairs[j]
+		if x.Source != y.Source {
+			return x.Source < y.Source
+		}
+		if x.Destination != y.Destination {
+			return x.Destination < y.Destination
+		}
+		return x.Target < y.Target
+	})
+	if len(cc.Status.FailingPairs) > connectivityCheckMaxFailingPairs {
+		cc.Status.FailingPairs = cc.Status.FailingPairs[:connectivityCheckMaxFailingPairs]
+	}
+
+	switch {
+	case cc.Status.TotalPairs == 0:
+		cc.Status.ClearCondition(kubeovnv1.Ready, "NoResults", "no results have been reported by kube-ovn-pinger")
+	case failingPairs != 0:
+		cc.Status.ClearCondition(kubeovnv1.Ready, "SLOViolated",
+			fmt.Sprintf("%d of %d pairs violate the slo", failingPairs, cc.Status.TotalPairs))
+	default:
+		cc.Status.SetCondition(kubeovnv1.Ready, "SLOMet", fmt.Sprintf("all %d pairs meet the slo", cc.Status.TotalPairs))
+	}
+	return staleNodes
+}
+
+// patchConnectivityCheckStatus patches the fields of the status aggregated by kube-ovn-controller and removes
+// the stale results of the nodes, the results of other nodes are left untouched since they are updated
+// by kube-ovn-pinger concurrently
+func (c *Controller) patchConnectivityCheckStatus(cc *kubeovnv1.ConnectivityCheck, status *kubeovnv1.ConnectivityCheckStatus, staleNodes []string) error {
+	if len(staleNodes) == 0 && cc.Status.TotalPairs == status.TotalPairs &&
+		reflect.DeepEqual(cc.Status.FailingPairs, status.FailingPairs) &&
+		reflect.DeepEqual(cc.Status.Conditions, status.Conditions) {
+		return nil
+	}
+
+	patch := map[string]interface{}{
+		"totalPairs":   status.TotalPairs,
+		"failingPairs": status.FailingPairs,
+		"conditions":   status.Conditions,
+	}
+	if len(staleNodes) != 0 {
+		nodes := make(map[string]interface{}, len(staleNodes))
+		for _, node := range staleNodes {
+			nodes[node] = nil
+		}
+		patch["nodes"] = nodes
+	}
+	bytes, err := json.Marshal(map[string]interface{}{"status": patch})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err = c.config.KubeOvnClient.KubeovnV1().ConnectivityChecks().Patch(context.Background(), cc.Name,
+		types.MergePatchType, bytes, metav1.PatchOptions{}, "status"); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf("failed to patch status of connectivity check %s, %v", cc.Name, err)
+		return err
+	}
+	return nil
+}
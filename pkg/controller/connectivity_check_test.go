@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+)
+
+func Test_aggregateConnectivityCheck(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"zone": "a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{"zone": "a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node3", Labels: map[string]string{"zone": "b"}}},
+	}
+	cc := &kubeovnv1.ConnectivityCheck{
+		ObjectMeta: metav1.ObjectMeta{Name: "cc1"},
+		Spec: kubeovnv1.ConnectivityCheckSpec{
+			Source:   kubeovnv1.ConnectivityCheckSource{NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}}},
+			Protocol: kubeovnv1.ConnectivityCheckProtocolICMP,
+			Interval: 10,
+		},
+		Status: kubeovnv1.ConnectivityCheckStatus{
+			Nodes: map[string]kubeovnv1.ConnectivityCheckNodeResult{
+				"node1": {
+					LastCheckTime: metav1.Time{Time: now},
+					Results: []kubeovnv1.ConnectivityCheckResult{
+						{Destination: "pod/ns1/pod2", Target: "10.16.0.6", SLOMet: false},
+						{Destination: "pod/ns1/pod1", Target: "10.16.0.5", SLOMet: false},
+						{Destination: "pod/ns1/pod3", Target: "10.16.0.7", SLOMet: true},
+					},
+				},
+				"node2": {
+					LastCheckTime: metav1.Time{Time: now.Add(-time.Minute)},
+					Results:       []kubeovnv1.ConnectivityCheckResult{{Destination: "pod/ns1/pod1", Target: "10.16.0.5"}},
+				},
+				"node3": {
+					LastCheckTime: metav1.Time{Time: now},
+					Results:       []kubeovnv1.ConnectivityCheckResult{{Destination: "pod/ns1/pod1", Target: "10.16.0.5"}},
+				},
+				"node4": {LastCheckTime: metav1.Time{Time: now}},
+			},
+		},
+	}
+
+	staleNodes := aggregateConnectivityCheck(cc, nodes, now)
+	require.Equal(t, []string{"node2", "node3", "node4"}, staleNodes)
+	require.Len(t, cc.Status.Nodes, 1)
+	require.Equal(t, 3, cc.Status.TotalPairs)
+	require.Equal(t, []kubeovnv1.ConnectivityCheckPair{
+		{Source: "node1", Destination: "pod/ns1/pod1", Target: "10.16.0.5"},
+		{Source: "node1", Destination: "pod/ns1/pod2", Target: "10.16.0.6"},
+	}, cc.Status.FailingPairs)
+	condition := cc.Status.GetCondition(kubeovnv1.Ready)
+	require.NotNil(t, condition)
+	require.Equal(t, v1.ConditionFalse, condition.Status)
+	require.Equal(t, "SLOViolated", condition.Reason)
+
+	cc.Status.Nodes["node1"].Results[0].SLOMet = true
+	cc.Status.Nodes["node1"].Results[1].SLOMet = true
+	require.Empty(t, aggregateConnectivityCheck(cc, nodes, now))
+	require.Empty(t, cc.Status.FailingPairs)
+	require.True(t, cc.Status.IsReady())
+
+	// the results of the node are capped by kube-ovn-pinger
+	cc.Status.Nodes["node1"] = kubeovnv1.ConnectivityCheckNodeResult{
+		LastCheckTime: metav1.Time{Time: now},
+		Total:         300,
+		Failing:       150,
+		Results:       make([]kubeovnv1.ConnectivityCheckResult, 100),
+	}
+	require.Empty(t, aggregateConnectivityCheck(cc, nodes, now))
+	require.Equal(t, 300, cc.Status.TotalPairs)
+	require.Len(t, cc.Status.FailingPairs, connectivityCheckMaxFailingPairs)
+	require.Equal(t, "150 of 300 pairs violate the slo", cc.Status.GetCondition(kubeovnv1.Ready).Message)
+
+	cc.Spec.Source.NodeSelector = nil
+	require.Equal(t, []string{"node1"}, aggregateConnectivityCheck(cc, nodes, now.Add(time.Minute)))
+	require.Zero(t, cc.Status.TotalPairs)
+	require.Equal(t, "NoResults", cc.Status.GetCondition(kubeovnv1.Ready).Reason)
+}
@@ -10,6 +10,7 @@ import (
 	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -17,12 +18,14 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	v1 "k8s.io/client-go/listers/core/v1"
+	discoveryv1 "k8s.io/client-go/listers/discovery/v1"
 	netv1 "k8s.io/client-go/listers/networking/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/keymutex"
+	kubevirtv1 "kubevirt.io/api/core/v1"
 
 	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
 	kubeovninformer "github.com/kubeovn/kube-ovn/pkg/client/informers/externalversions"
@@ -87,6 +90,7 @@ type Controller struct {
 	updateVpcDnatQueue            workqueue.RateLimitingInterface
 	updateVpcSnatQueue            workqueue.RateLimitingInterface
 	updateVpcSubnetQueue          workqueue.RateLimitingInterface
+	updateVpcNatGwHAQueue         workqueue.RateLimitingInterface
 	vpcNatGwKeyMutex              keymutex.KeyMutex
 
 	switchLBRuleLister      kubeovnlister.SwitchLBRuleLister
@@ -207,10 +211,10 @@ type Controller struct {
 	updateServiceQueue workqueue.RateLimitingInterface
 	svcKeyMutex        keymutex.KeyMutex
 
-	endpointsLister     v1.EndpointsLister
-	endpointsSynced     cache.InformerSynced
-	updateEndpointQueue workqueue.RateLimitingInterface
-	epKeyMutex          keymutex.KeyMutex
+	endpointSlicesLister discoveryv1.EndpointSliceLister
+	endpointSlicesSynced cache.InformerSynced
+	updateEndpointQueue  workqueue.RateLimitingInterface
+	epKeyMutex           keymutex.KeyMutex
 
 	npsLister     netv1.NetworkPolicyLister
 	npsSynced     cache.InformerSynced
@@ -230,19 +234,36 @@ type Controller struct {
 	addQoSPolicyQueue    workqueue.RateLimitingInterface
 	updateQoSPolicyQueue workqueue.RateLimitingInterface
 	delQoSPolicyQueue    workqueue.RateLimitingInterface
+	qosPolicyKeyMutex    keymutex.KeyMutex
+
+	trafficMirrorsLister          kubeovnlister.TrafficMirrorLister
+	trafficMirrorSynced           cache.InformerSynced
+	addOrUpdateTrafficMirrorQueue workqueue.RateLimitingInterface
+	delTrafficMirrorQueue         workqueue.RateLimitingInterface
+
+	connectivityChecksLister     kubeovnlister.ConnectivityCheckLister
+	connectivityCheckSynced      cache.InformerSynced
+	updateConnectivityCheckQueue workqueue.RateLimitingInterface
 
 	configMapsLister v1.ConfigMapLister
 	configMapsSynced cache.InformerSynced
 
+	vmiMigrationStore            cache.Store
+	vmiMigrationSynced           cache.InformerSynced
+	addOrUpdateVMIMigrationQueue workqueue.RateLimitingInterface
+	deleteVMIMigrationQueue      workqueue.RateLimitingInterface
+
 	recorder               record.EventRecorder
 	informerFactory        kubeinformers.SharedInformerFactory
 	cmInformerFactory      kubeinformers.SharedInformerFactory
 	kubeovnInformerFactory kubeovninformer.SharedInformerFactory
 }
 
-// Run creates and runs a new ovn controller
-func Run(ctx context.Context, config *Configuration) {
+// Run creates and runs a new ovn controller, the flow trace requests are served
+// by the controller if the flow trace handler is not nil
+func Run(ctx context.Context, config *Configuration, flowTrace *FlowTraceHandler) {
 	utilruntime.Must(kubeovnv1.AddToScheme(scheme.Scheme))
+	utilruntime.Must(kubevirtv1.AddToScheme(scheme.Scheme))
 	klog.V(4).Info("Creating event broadcaster")
 	eventBroadcaster := record.NewBroadcasterWithCorrelatorOptions(record.CorrelatorOptions{BurstSize: 100})
 	eventBroadcaster.StartLogging(klog.Infof)
@@ -283,8 +304,10 @@ func Run(ctx context.Context, config *Configuration) {
 	namespaceInformer := informerFactory.Core().V1().Namespaces()
 	nodeInformer := informerFactory.Core().V1().Nodes()
 	serviceInformer := informerFactory.Core().V1().Services()
-	endpointInformer := informerFactory.Core().V1().Endpoints()
+	endpointSliceInformer := informerFactory.Discovery().V1().EndpointSlices()
 	qosPolicyInformer := kubeovnInformerFactory.Kubeovn().V1().QoSPolicies()
+	trafficMirrorInformer := kubeovnInformerFactory.Kubeovn().V1().TrafficMirrors()
+	connectivityCheckInformer := kubeovnInformerFactory.Kubeovn().V1().ConnectivityChecks()
 	configMapInformer := cmInformerFactory.Core().V1().ConfigMaps()
 	npInformer := informerFactory.Networking().V1().NetworkPolicies()
 	switchLBRuleInformer := kubeovnInformerFactory.Kubeovn().V1().SwitchLBRules()
@@ -324,6 +347,7 @@ func Run(ctx context.Context, config *Configuration) {
 		updateVpcDnatQueue:            workqueue.NewNamedRateLimitingQueue(custCrdRateLimiter, "UpdateVpcDnat"),
 		updateVpcSnatQueue:            workqueue.NewNamedRateLimitingQueue(custCrdRateLimiter, "UpdateVpcSnat"),
 		updateVpcSubnetQueue:          workqueue.NewNamedRateLimitingQueue(custCrdRateLimiter, "UpdateVpcSubnet"),
+		updateVpcNatGwHAQueue:         workqueue.NewNamedRateLimitingQueue(custCrdRateLimiter, "UpdateVpcNatGwHA"),
 		vpcNatGwKeyMutex:              keymutex.NewHashed(numKeyLocks),
 
 		subnetsLister:           subnetInformer.Lister(),
@@ -418,16 +442,26 @@ func Run(ctx context.Context, config *Configuration) {
 		updateServiceQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "UpdateService"),
 		svcKeyMutex:        keymutex.NewHashed(numKeyLocks),
 
-		endpointsLister:     endpointInformer.Lister(),
-		endpointsSynced:     endpointInformer.Informer().HasSynced,
-		updateEndpointQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "UpdateEndpoint"),
-		epKeyMutex:          keymutex.NewHashed(numKeyLocks),
+		endpointSlicesLister: endpointSliceInformer.Lister(),
+		endpointSlicesSynced: endpointSliceInformer.Informer().HasSynced,
+		updateEndpointQueue:  workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "UpdateEndpoint"),
+		epKeyMutex:           keymutex.NewHashed(numKeyLocks),
 
 		qosPoliciesLister:    qosPolicyInformer.Lister(),
 		qosPolicySynced:      qosPolicyInformer.Informer().HasSynced,
 		addQoSPolicyQueue:    workqueue.NewNamedRateLimitingQueue(custCrdRateLimiter, "AddQoSPolicy"),
 		updateQoSPolicyQueue: workqueue.NewNamedRateLimitingQueue(custCrdRateLimiter, "UpdateQoSPolicy"),
 		delQoSPolicyQueue:    workqueue.NewNamedRateLimitingQueue(custCrdRateLimiter, "DeleteQoSPolicy"),
+		qosPolicyKeyMutex:    keymutex.NewHashed(numKeyLocks),
+
+		trafficMirrorsLister:          trafficMirrorInformer.Lister(),
+		trafficMirrorSynced:           trafficMirrorInformer.Informer().HasSynced,
+		addOrUpdateTrafficMirrorQueue: workqueue.NewNamedRateLimitingQueue(custCrdRateLimiter, "AddOrUpdateTrafficMirror"),
+		delTrafficMirrorQueue:         workqueue.NewNamedRateLimitingQueue(custCrdRateLimiter, "DeleteTrafficMirror"),
+
+		connectivityChecksLister:     connectivityCheckInformer.Lister(),
+		connectivityCheckSynced:      connectivityCheckInformer.Informer().HasSynced,
+		updateConnectivityCheckQueue: workqueue.NewNamedRateLimitingQueue(custCrdRateLimiter, "UpdateConnectivityCheck"),
 
 		configMapsLister: configMapInformer.Lister(),
 		configMapsSynced: configMapInformer.Informer().HasSynced,
@@ -498,6 +532,31 @@ func Run(ctx context.Context, config *Configuration) {
 		controller.npKeyMutex = keymutex.NewHashed(numKeyLocks)
 	}
 
+	var vmiMigrationInformer cache.SharedIndexInformer
+	if config.EnableLiveMigrationOptimize && !config.EnableKeepVMIP {
+		// the source and the target virt-launcher pods share the logical switch ports only if keep-vm-ip is enabled
+		klog.Info("keep-vm-ip is disabled, live migration optimize is disabled")
+		config.EnableLiveMigrationOptimize = false
+	}
+	if config.EnableLiveMigrationOptimize {
+		installed, err := isVMIMigrationCRDInstalled(config)
+		if err != nil {
+			util.LogFatalAndExit(err, "failed to check whether kubevirt is installed")
+		}
+		if installed {
+			vmiMigrationInformer = cache.NewSharedIndexInformer(
+				cache.NewListWatchFromClient(config.KubevirtClient.RestClient(), "virtualmachineinstancemigrations", metav1.NamespaceAll, fields.Everything()),
+				&kubevirtv1.VirtualMachineInstanceMigration{}, 0, cache.Indexers{})
+			controller.vmiMigrationStore = vmiMigrationInformer.GetStore()
+			controller.vmiMigrationSynced = vmiMigrationInformer.HasSynced
+			controller.addOrUpdateVMIMigrationQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "AddOrUpdateVMIMigration")
+			controller.deleteVMIMigrationQueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "DeleteVMIMigration")
+		} else {
+			klog.Infof("crd %s is not found, live migration optimize is disabled", vmiMigrationCRDName)
+			config.EnableLiveMigrationOptimize = false
+		}
+	}
+
 	defer controller.shutdown()
 	klog.Info("Starting OVN controller")
 
@@ -505,6 +564,9 @@ func Run(ctx context.Context, config *Configuration) {
 	controller.informerFactory.Start(ctx.Done())
 	controller.cmInformerFactory.Start(ctx.Done())
 	controller.kubeovnInformerFactory.Start(ctx.Done())
+	if vmiMigrationInformer != nil {
+		go vmiMigrationInformer.Run(ctx.Done())
+	}
 
 	klog.Info("Waiting for informer caches to sync")
 	cacheSyncs := []cache.InformerSynced{
@@ -512,9 +574,9 @@ func Run(ctx context.Context, config *Configuration) {
 		controller.ipSynced, controller.virtualIpsSynced, controller.iptablesEipSynced,
 		controller.iptablesFipSynced, controller.iptablesDnatRuleSynced, controller.iptablesSnatRuleSynced,
 		controller.vlanSynced, controller.podsSynced, controller.namespacesSynced, controller.nodesSynced,
-		controller.serviceSynced, controller.endpointsSynced, controller.configMapsSynced,
+		controller.serviceSynced, controller.endpointSlicesSynced, controller.configMapsSynced,
 		controller.ovnEipSynced, controller.ovnFipSynced, controller.ovnSnatRuleSynced,
-		controller.ovnDnatRuleSynced,
+		controller.ovnDnatRuleSynced, controller.trafficMirrorSynced, controller.connectivityCheckSynced,
 	}
 	if controller.config.EnableLb {
 		cacheSyncs = append(cacheSyncs, controller.switchLBRuleSynced, controller.vpcDNSSynced)
@@ -522,6 +584,9 @@ func Run(ctx context.Context, config *Configuration) {
 	if controller.config.EnableNP {
 		cacheSyncs = append(cacheSyncs, controller.npsSynced)
 	}
+	if controller.config.EnableLiveMigrationOptimize {
+		cacheSyncs = append(cacheSyncs, controller.vmiMigrationSynced)
+	}
 	if !cache.WaitForCacheSync(ctx.Done(), cacheSyncs...) {
 		util.LogFatalAndExit(nil, "failed to wait for caches to sync")
 	}
@@ -558,11 +623,12 @@ func Run(ctx context.Context, config *Configuration) {
 		util.LogFatalAndExit(err, "failed to add service event handler")
 	}
 
-	if _, err = endpointInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc:    controller.enqueueAddEndpoint,
-		UpdateFunc: controller.enqueueUpdateEndpoint,
+	if _, err = endpointSliceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.enqueueAddEndpointSlice,
+		UpdateFunc: controller.enqueueUpdateEndpointSlice,
+		DeleteFunc: controller.enqueueDeleteEndpointSlice,
 	}); err != nil {
-		util.LogFatalAndExit(err, "failed to add endpoint event handler")
+		util.LogFatalAndExit(err, "failed to add endpoint slice event handler")
 	}
 
 	if _, err = vpcInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -701,6 +767,21 @@ func Run(ctx context.Context, config *Configuration) {
 		util.LogFatalAndExit(err, "failed to add qos policy event handler")
 	}
 
+	if _, err = trafficMirrorInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.enqueueAddTrafficMirror,
+		UpdateFunc: controller.enqueueUpdateTrafficMirror,
+		DeleteFunc: controller.enqueueDelTrafficMirror,
+	}); err != nil {
+		util.LogFatalAndExit(err, "failed to add traffic mirror event handler")
+	}
+
+	if _, err = connectivityCheckInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.enqueueAddConnectivityCheck,
+		UpdateFunc: controller.enqueueUpdateConnectivityCheck,
+	}); err != nil {
+		util.LogFatalAndExit(err, "failed to add connectivity check event handler")
+	}
+
 	if config.EnableLb {
 		if _, err = switchLBRuleInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 			AddFunc:    controller.enqueueAddSwitchLBRule,
@@ -729,6 +810,19 @@ func Run(ctx context.Context, config *Configuration) {
 		}
 	}
 
+	if config.EnableLiveMigrationOptimize {
+		if _, err = vmiMigrationInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    controller.enqueueAddVMIMigration,
+			UpdateFunc: controller.enqueueUpdateVMIMigration,
+			DeleteFunc: controller.enqueueDeleteVMIMigration,
+		}); err != nil {
+			util.LogFatalAndExit(err, "failed to add vmi migration event handler")
+		}
+	}
+
+	if flowTrace != nil {
+		flowTrace.controller.Store(controller)
+	}
 	controller.Run(ctx)
 }
 
@@ -831,6 +925,7 @@ func (c *Controller) shutdown() {
 	c.updateVpcDnatQueue.ShutDown()
 	c.updateVpcSnatQueue.ShutDown()
 	c.updateVpcSubnetQueue.ShutDown()
+	c.updateVpcNatGwHAQueue.ShutDown()
 
 	if c.config.EnableLb {
 		c.addSwitchLBRuleQueue.ShutDown()
@@ -871,6 +966,11 @@ func (c *Controller) shutdown() {
 	c.updateQoSPolicyQueue.ShutDown()
 	c.delQoSPolicyQueue.ShutDown()
 
+	c.addOrUpdateTrafficMirrorQueue.ShutDown()
+	c.delTrafficMirrorQueue.ShutDown()
+
+	c.updateConnectivityCheckQueue.ShutDown()
+
 	c.addOvnEipQueue.ShutDown()
 	c.updateOvnEipQueue.ShutDown()
 	c.resetOvnEipQueue.ShutDown()
@@ -895,6 +995,11 @@ func (c *Controller) shutdown() {
 	c.addOrUpdateSgQueue.ShutDown()
 	c.delSgQueue.ShutDown()
 	c.syncSgPortsQueue.ShutDown()
+
+	if c.config.EnableLiveMigrationOptimize {
+		c.addOrUpdateVMIMigrationQueue.ShutDown()
+		c.deleteVMIMigrationQueue.ShutDown()
+	}
 }
 
 func (c *Controller) startWorkers(ctx context.Context) {
@@ -910,6 +1015,7 @@ func (c *Controller) startWorkers(ctx context.Context) {
 	go wait.Until(c.runUpdateVpcDnatWorker, time.Second, ctx.Done())
 	go wait.Until(c.runUpdateVpcSnatWorker, time.Second, ctx.Done())
 	go wait.Until(c.runUpdateVpcSubnetWorker, time.Second, ctx.Done())
+	go wait.Until(c.runUpdateVpcNatGwHAWorker, time.Second, ctx.Done())
 
 	// add default/join subnet and wait them ready
 	go wait.Until(c.runAddSubnetWorker, time.Second, ctx.Done())
@@ -995,6 +1101,11 @@ func (c *Controller) startWorkers(ctx context.Context) {
 			go wait.Until(c.runDeleteNpWorker, time.Second, ctx.Done())
 		}
 
+		if c.config.EnableLiveMigrationOptimize {
+			go wait.Until(c.runAddOrUpdateVMIMigrationWorker, time.Second, ctx.Done())
+			go wait.Until(c.runDeleteVMIMigrationWorker, time.Second, ctx.Done())
+		}
+
 		go wait.Until(c.runDelVlanWorker, time.Second, ctx.Done())
 		go wait.Until(c.runUpdateVlanWorker, time.Second, ctx.Done())
 	}
@@ -1012,6 +1123,9 @@ func (c *Controller) startWorkers(ctx context.Context) {
 	go wait.Until(func() {
 		c.resyncVpcNatGwConfig()
 	}, time.Second, ctx.Done())
+	go wait.Until(c.syncVpcNatGwState, natGwStateSyncInterval, ctx.Done())
+	go wait.Until(c.resyncTrafficMirrors, trafficMirrorSyncInterval, ctx.Done())
+	go wait.Until(c.resyncConnectivityChecks, connectivityCheckSyncInterval, ctx.Done())
 
 	go wait.Until(func() {
 		if err := c.markAndCleanLSP(); err != nil {
@@ -1085,6 +1199,11 @@ func (c *Controller) startWorkers(ctx context.Context) {
 	go wait.Until(c.runAddQoSPolicyWorker, time.Second, ctx.Done())
 	go wait.Until(c.runUpdateQoSPolicyWorker, time.Second, ctx.Done())
 	go wait.Until(c.runDelQoSPolicyWorker, time.Second, ctx.Done())
+
+	go wait.Until(c.runAddOrUpdateTrafficMirrorWorker, time.Second, ctx.Done())
+	go wait.Until(c.runDelTrafficMirrorWorker, time.Second, ctx.Done())
+
+	go wait.Until(c.runUpdateConnectivityCheckWorker, time.Second, ctx.Done())
 }
 
 func (c *Controller) allSubnetReady(subnets ...string) (bool, error) {
@@ -10,6 +10,7 @@ import (
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/keymutex"
 
 	mockovs "github.com/kubeovn/kube-ovn/mocks/pkg/ovs"
 	kubeovnfake "github.com/kubeovn/kube-ovn/pkg/client/clientset/versioned/fake"
@@ -18,9 +19,11 @@ import (
 )
 
 type fakeControllerInformers struct {
-	vpcInformer     kubeovninformer.VpcInformer
-	sbunetInformer  kubeovninformer.SubnetInformer
-	serviceInformer coreinformers.ServiceInformer
+	vpcInformer           kubeovninformer.VpcInformer
+	sbunetInformer        kubeovninformer.SubnetInformer
+	vpcNatGatewayInformer kubeovninformer.VpcNatGatewayInformer
+	serviceInformer       coreinformers.ServiceInformer
+	podInformer           coreinformers.PodInformer
 }
 
 type fakeController struct {
@@ -36,28 +39,37 @@ func newFakeController(t *testing.T) *fakeController {
 	kubeClient := fake.NewSimpleClientset()
 	kubeInformerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
 	serviceInformer := kubeInformerFactory.Core().V1().Services()
+	podInformer := kubeInformerFactory.Core().V1().Pods()
 
 	/* fake kube ovn client */
 	kubeovnClient := kubeovnfake.NewSimpleClientset()
 	kubeovnInformerFactory := kubeovninformerfactory.NewSharedInformerFactory(kubeovnClient, 0)
 	vpcInformer := kubeovnInformerFactory.Kubeovn().V1().Vpcs()
 	sbunetInformer := kubeovnInformerFactory.Kubeovn().V1().Subnets()
+	vpcNatGatewayInformer := kubeovnInformerFactory.Kubeovn().V1().VpcNatGateways()
 
 	fakeInformers := &fakeControllerInformers{
-		vpcInformer:     vpcInformer,
-		sbunetInformer:  sbunetInformer,
-		serviceInformer: serviceInformer,
+		vpcInformer:           vpcInformer,
+		sbunetInformer:        sbunetInformer,
+		vpcNatGatewayInformer: vpcNatGatewayInformer,
+		serviceInformer:       serviceInformer,
+		podInformer:           podInformer,
 	}
 
 	/* ovn fake client */
 	mockOvnClient := mockovs.NewMockNbClient(gomock.NewController(t))
 
 	ctrl := &Controller{
+		config:                &Configuration{PodNamespace: "kube-system"},
 		servicesLister:        serviceInformer.Lister(),
+		podsLister:            podInformer.Lister(),
+		endpointSlicesLister:  kubeInformerFactory.Discovery().V1().EndpointSlices().Lister(),
+		epKeyMutex:            keymutex.NewHashed(0),
 		vpcsLister:            vpcInformer.Lister(),
 		vpcSynced:             alwaysReady,
 		subnetsLister:         sbunetInformer.Lister(),
 		subnetSynced:          alwaysReady,
+		vpcNatGatewayLister:   vpcNatGatewayInformer.Lister(),
 		OVNNbClient:           mockOvnClient,
 		syncVirtualPortsQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ""),
 	}
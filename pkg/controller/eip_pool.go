@@ -0,0 +1,380 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+const (
+	ovnFipKind           = "OvnFip"
+	ovnSnatRuleKind      = "OvnSnatRule"
+	ovnDnatRuleKind      = "OvnDnatRule"
+	iptablesFipKind      = "IptablesFIPRule"
+	iptablesSnatRuleKind = "IptablesSnatRule"
+	iptablesDnatRuleKind = "IptablesDnatRule"
+
+	autoFipPodKind     = "pod"
+	autoFipServiceKind = "service"
+)
+
+// An eip pool is an ip pool of an external subnet. The nat rules which refer to an eip pool
+// instead of an eip get an eip allocated from the pool, the eip is owned by the nat rule
+// and it is released by the garbage collector after the nat rule is deleted.
+
+// getEipPool returns the ip pool and checks it is in an external subnet
+func (c *Controller) getEipPool(poolName string) (*kubeovnv1.IPPool, error) {
+	pool, err := c.ippoolLister.Get(poolName)
+	if err != nil {
+		klog.Errorf("failed to get eip pool %s, %v", poolName, err)
+		return nil, err
+	}
+	subnet, err := c.subnetsLister.Get(pool.Spec.Subnet)
+	if err != nil {
+		klog.Errorf("failed to get subnet %s of eip pool %s, %v", pool.Spec.Subnet, poolName, err)
+		return nil, err
+	}
+	if subnet.Spec.Vpc != "" && subnet.Spec.Vpc != c.config.ClusterRouter {
+		err = fmt.Errorf("subnet %s of eip pool %s is not an external subnet", subnet.Name, poolName)
+		klog.Error(err)
+		return nil, err
+	}
+	return pool, nil
+}
+
+// eipPoolOwnerReference returns the owner reference of the eip allocated for the nat rule
+func eipPoolOwnerReference(owner metav1.Object, kind string) metav1.OwnerReference {
+	return *metav1.NewControllerRef(owner, kubeovnv1.SchemeGroupVersion.WithKind(kind))
+}
+
+func isOwnedBy(obj, owner metav1.Object) bool {
+	return slices.ContainsFunc(obj.GetOwnerReferences(), func(ref metav1.OwnerReference) bool {
+		return ref.UID == owner.GetUID()
+	})
+}
+
+// allocateOvnEipFromPool creates an ovn eip from the eip pool for the ovn nat rule,
+// the eip name is the nat type followed by the name of the rule
+func (c *Controller) allocateOvnEipFromPool(natType, kind string, owner metav1.Object, poolName string) (string, error) {
+	eipName := fmt.Sprintf("%s-%s", natType, owner.GetName())
+	if eip, err := c.ovnEipsLister.Get(eipName); err == nil {
+		if !isOwnedBy(eip, owner) {
+			err = fmt.Errorf("failed to allocate eip for %s %s, ovn eip %s already exists", natType, owner.GetName(), eipName)
+			klog.Error(err)
+			return "", err
+		}
+		return eipName, nil
+	} else if !k8serrors.IsNotFound(err) {
+		klog.Errorf("failed to get ovn eip %s, %v", eipName, err)
+		return "", err
+	}
+
+	pool, err := c.getEipPool(poolName)
+	if err != nil {
+		return "", err
+	}
+	eip := &kubeovnv1.OvnEip{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            eipName,
+			Labels:          map[string]string{util.EipPoolLabel: pool.Name},
+			OwnerReferences: []metav1.OwnerReference{eipPoolOwnerReference(owner, kind)},
+		},
+		Spec: kubeovnv1.OvnEipSpec{
+			ExternalSubnet: pool.Spec.Subnet,
+			IPPool:         pool.Name,
+			Type:           util.OvnEipTypeNAT,
+		},
+	}
+	if _, err = c.config.KubeOvnClient.KubeovnV1().OvnEips().Create(context.Background(), eip, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
+		klog.Errorf("failed to create ovn eip %s from eip pool %s, %v", eipName, pool.Name, err)
+		return "", err
+	}
+	klog.Infof("allocate ovn eip %s from eip pool %s for %s %s", eipName, pool.Name, natType, owner.GetName())
+	return eipName, nil
+}
+
+// allocateIptablesEipFromPool creates an iptables eip of the vpc nat gateway from the eip pool for the iptables nat rule
+func (c *Controller) allocateIptablesEipFromPool(natType, kind string, owner metav1.Object, poolName, natGwDp string) (string, error) {
+	if natGwDp == "" {
+		err := fmt.Errorf("failed to allocate eip for %s %s, should set natGwDp", natType, owner.GetName())
+		klog.Error(err)
+		return "", err
+	}
+	eipName := fmt.Sprintf("%s-%s", natType, owner.GetName())
+	if eip, err := c.iptablesEipsLister.Get(eipName); err == nil {
+		if !isOwnedBy(eip, owner) {
+			err = fmt.Errorf("failed to allocate eip for %s %s, iptables eip %s already exists", natType, owner.GetName(), eipName)
+			klog.Error(err)
+			return "", err
+		}
+		return eipName, nil
+	} else if !k8serrors.IsNotFound(err) {
+		klog.Errorf("failed to get iptables eip %s, %v", eipName, err)
+		return "", err
+	}
+
+	gw, err := c.vpcNatGatewayLister.Get(natGwDp)
+	if err != nil {
+		klog.Errorf("failed to get vpc nat gateway %s, %v", natGwDp, err)
+		return "", err
+	}
+	pool, err := c.getEipPool(poolName)
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(gw.Spec.ExternalSubnets, pool.Spec.Subnet) && pool.Spec.Subnet != util.GetNatGwExternalNetwork(gw.Spec.ExternalSubnets) {
+		err = fmt.Errorf("eip pool %s is not in the external subnets of vpc nat gateway %s", pool.Name, natGwDp)
+		klog.Error(err)
+		return "", err
+	}
+	eip := &kubeovnv1.IptablesEIP{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            eipName,
+			Labels:          map[string]string{util.EipPoolLabel: pool.Name},
+			OwnerReferences: []metav1.OwnerReference{eipPoolOwnerReference(owner, kind)},
+		},
+		Spec: kubeovnv1.IptablesEipSpec{
+			NatGwDp:        natGwDp,
+			ExternalSubnet: pool.Spec.Subnet,
+			IPPool:         pool.Name,
+		},
+	}
+	if _, err = c.config.KubeOvnClient.KubeovnV1().IptablesEIPs().Create(context.Background(), eip, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
+		klog.Errorf("failed to create iptables eip %s from eip pool %s, %v", eipName, pool.Name, err)
+		return "", err
+	}
+	klog.Infof("allocate iptables eip %s from eip pool %s for %s %s", eipName, pool.Name, natType, owner.GetName())
+	return eipName, nil
+}
+
+// patchNatRuleEip sets the eip allocated from the eip pool in the spec of the nat rule
+func (c *Controller) patchNatRuleEip(kind, name, eipName string) error {
+	var err error
+	ovnPatch := []byte(fmt.Sprintf(`{"spec":{"ovnEip":%q}}`, eipName))
+	iptablesPatch := []byte(fmt.Sprintf(`{"spec":{"eip":%q}}`, eipName))
+	client := c.config.KubeOvnClient.KubeovnV1()
+	switch kind {
+	case ovnFipKind:
+		_, err = client.OvnFips().Patch(context.Background(), name, types.MergePatchType, ovnPatch, metav1.PatchOptions{})
+	case ovnSnatRuleKind:
+		_, err = client.OvnSnatRules().Patch(context.Background(), name, types.MergePatchType, ovnPatch, metav1.PatchOptions{})
+	case ovnDnatRuleKind:
+		_, err = client.OvnDnatRules().Patch(context.Background(), name, types.MergePatchType, ovnPatch, metav1.PatchOptions{})
+	case iptablesFipKind:
+		_, err = client.IptablesFIPRules().Patch(context.Background(), name, types.MergePatchType, iptablesPatch, metav1.PatchOptions{})
+	case iptablesSnatRuleKind:
+		_, err = client.IptablesSnatRules().Patch(context.Background(), name, types.MergePatchType, iptablesPatch, metav1.PatchOptions{})
+	case iptablesDnatRuleKind:
+		_, err = client.IptablesDnatRules().Patch(context.Background(), name, types.MergePatchType, iptablesPatch, metav1.PatchOptions{})
+	default:
+		err = fmt.Errorf("unknown nat kind %s", kind)
+	}
+	if err != nil {
+		klog.Errorf("failed to patch eip %s of %s %s, %v", eipName, kind, name, err)
+		return err
+	}
+	return nil
+}
+
+// allocateOvnNatRuleEip allocates an ovn eip from the eip pool for the ovn nat rule and sets it in the rule,
+// the rule is added again after the update of the rule is observed
+func (c *Controller) allocateOvnNatRuleEip(natType, kind string, owner metav1.Object, poolName string) error {
+	eipName, err := c.allocateOvnEipFromPool(natType, kind, owner, poolName)
+	if err != nil {
+		return err
+	}
+	return c.patchNatRuleEip(kind, owner.GetName(), eipName)
+}
+
+// allocateIptablesNatRuleEip allocates an iptables eip from the eip pool for the iptables nat rule and sets it in the rule,
+// the rule is added again after the update of the rule is observed
+func (c *Controller) allocateIptablesNatRuleEip(natType, kind string, owner metav1.Object, poolName, natGwDp string) error {
+	eipName, err := c.allocateIptablesEipFromPool(natType, kind, owner, poolName, natGwDp)
+	if err != nil {
+		return err
+	}
+	return c.patchNatRuleEip(kind, owner.GetName(), eipName)
+}
+
+// The pods and services annotated with the eip pool get an auto fip, which is an ovn fip,
+// or an iptables fip if the vpc nat gateway is annotated as well.
+
+// autoFipName returns the name of the auto fip of the pod or service
+func autoFipName(kind string, obj metav1.Object) string {
+	return fmt.Sprintf("%s.%s.%s", kind, obj.GetName(), obj.GetNamespace())
+}
+
+func autoFipMeta(kind string, obj metav1.Object, poolName string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name: autoFipName(kind, obj),
+		Labels: map[string]string{
+			util.EipPoolLabel: poolName,
+			util.AutoFipLabel: kind,
+		},
+		Annotations: map[string]string{
+			util.AutoFipOwnerAnnotation: fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName()),
+		},
+	}
+}
+
+// syncAutoFip creates, updates or deletes the auto fip of the pod or service according to its annotations
+func (c *Controller) syncAutoFip(kind string, obj metav1.Object, v4ip, v6ip, vpcName string) error {
+	poolName := obj.GetAnnotations()[util.EipPoolAnnotation]
+	natGwDp := obj.GetAnnotations()[util.EipNatGwAnnotation]
+	fipName := autoFipName(kind, obj)
+	if poolName == "" || natGwDp != "" {
+		if err := c.deleteAutoOvnFip(fipName); err != nil {
+			return err
+		}
+	}
+	if poolName == "" || natGwDp == "" {
+		if err := c.deleteAutoIptablesFip(fipName); err != nil {
+			return err
+		}
+	}
+	if poolName == "" {
+		return nil
+	}
+	if v4ip == "" && v6ip == "" {
+		klog.Warningf("%s %s/%s has no ip address for the auto fip", kind, obj.GetNamespace(), obj.GetName())
+		return nil
+	}
+
+	client := c.config.KubeOvnClient.KubeovnV1()
+	if natGwDp != "" {
+		if v4ip == "" {
+			err := fmt.Errorf("failed to create iptables fip %s, no ipv4 address", fipName)
+			klog.Error(err)
+			return err
+		}
+		fip, err := c.iptablesFipsLister.Get(fipName)
+		if err == nil {
+			if fip.Spec.InternalIP == v4ip {
+				return nil
+			}
+			fip = fip.DeepCopy()
+			fip.Spec.InternalIP = v4ip
+			if _, err = client.IptablesFIPRules().Update(context.Background(), fip, metav1.UpdateOptions{}); err != nil {
+				klog.Errorf("failed to update iptables fip %s, %v", fipName, err)
+				return err
+			}
+			return nil
+		} else if !k8serrors.IsNotFound(err) {
+			klog.Errorf("failed to get iptables fip %s, %v", fipName, err)
+			return err
+		}
+		fip = &kubeovnv1.IptablesFIPRule{
+			ObjectMeta: autoFipMeta(kind, obj, poolName),
+			Spec: kubeovnv1.IptablesFIPRuleSpec{
+				InternalIP: v4ip,
+				EipPool:    poolName,
+				NatGwDp:    natGwDp,
+			},
+		}
+		if _, err = client.IptablesFIPRules().Create(context.Background(), fip, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
+			klog.Errorf("failed to create iptables fip %s, %v", fipName, err)
+			return err
+		}
+		klog.Infof("create iptables fip %s from eip pool %s", fipName, poolName)
+		return nil
+	}
+
+	fip, err := c.ovnFipsLister.Get(fipName)
+	if err == nil {
+		if fip.Spec.V4Ip == v4ip && fip.Spec.V6Ip == v6ip && fip.Spec.Vpc == vpcName {
+			return nil
+		}
+		fip = fip.DeepCopy()
+		fip.Spec.V4Ip, fip.Spec.V6Ip, fip.Spec.Vpc = v4ip, v6ip, vpcName
+		if _, err = client.OvnFips().Update(context.Background(), fip, metav1.UpdateOptions{}); err != nil {
+			klog.Errorf("failed to update ovn fip %s, %v", fipName, err)
+			return err
+		}
+		return nil
+	} else if !k8serrors.IsNotFound(err) {
+		klog.Errorf("failed to get ovn fip %s, %v", fipName, err)
+		return err
+	}
+	fip = &kubeovnv1.OvnFip{
+		ObjectMeta: autoFipMeta(kind, obj, poolName),
+		Spec: kubeovnv1.OvnFipSpec{
+			Vpc:     vpcName,
+			V4Ip:    v4ip,
+			V6Ip:    v6ip,
+			EipPool: poolName,
+		},
+	}
+	if _, err = client.OvnFips().Create(context.Background(), fip, metav1.CreateOptions{}); err != nil && !k8serrors.IsAlreadyExists(err) {
+		klog.Errorf("failed to create ovn fip %s, %v", fipName, err)
+		return err
+	}
+	klog.Infof("create ovn fip %s from eip pool %s", fipName, poolName)
+	return nil
+}
+
+// reconcilePodAutoFip syncs the auto fip of the pod which has been routed, the auto fip
+// of a pod being routed is synced by reconcileRouteSubnets
+func (c *Controller) reconcilePodAutoFip(pod *v1.Pod, podNets []*kubeovnNet) error {
+	for _, podNet := range podNets {
+		if podNet.ProviderName != util.OvnProvider || pod.Annotations[fmt.Sprintf(util.RoutedAnnotationTemplate, podNet.ProviderName)] != "true" {
+			continue
+		}
+		podIP := pod.Annotations[fmt.Sprintf(util.IPAddressAnnotationTemplate, podNet.ProviderName)]
+		if podIP == "" {
+			continue
+		}
+		v4ip, v6ip := util.SplitStringIP(podIP)
+		return c.syncAutoFip(autoFipPodKind, pod, v4ip, v6ip, podNet.Subnet.Spec.Vpc)
+	}
+	return nil
+}
+
+// deleteAutoFip deletes the auto fip of the pod or service, the eip is released along with the fip
+func (c *Controller) deleteAutoFip(kind string, obj metav1.Object) error {
+	fipName := autoFipName(kind, obj)
+	if err := c.deleteAutoOvnFip(fipName); err != nil {
+		return err
+	}
+	return c.deleteAutoIptablesFip(fipName)
+}
+
+func (c *Controller) deleteAutoOvnFip(name string) error {
+	if _, err := c.ovnFipsLister.Get(name); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf("failed to get ovn fip %s, %v", name, err)
+		return err
+	}
+	klog.Infof("delete auto ovn fip %s", name)
+	if err := c.config.KubeOvnClient.KubeovnV1().OvnFips().Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		klog.Errorf("failed to delete ovn fip %s, %v", name, err)
+		return err
+	}
+	return nil
+}
+
+func (c *Controller) deleteAutoIptablesFip(name string) error {
+	if _, err := c.iptablesFipsLister.Get(name); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf("failed to get iptables fip %s, %v", name, err)
+		return err
+	}
+	klog.Infof("delete auto iptables fip %s", name)
+	if err := c.config.KubeOvnClient.KubeovnV1().IptablesFIPRules().Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		klog.Errorf("failed to delete iptables fip %s, %v", name, err)
+		return err
+	}
+	return nil
+}
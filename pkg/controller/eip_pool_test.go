@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	kubeovnfake "github.com/kubeovn/kube-ovn/pkg/client/clientset/versioned/fake"
+	kubeovnlister "github.com/kubeovn/kube-ovn/pkg/client/listers/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+type eipPoolTestController struct {
+	*Controller
+	client     *kubeovnfake.Clientset
+	eips, fips cache.Indexer
+}
+
+func newEipPoolTestController(t *testing.T) *eipPoolTestController {
+	newIndexer := func(objs ...interface{}) cache.Indexer {
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		for _, obj := range objs {
+			require.NoError(t, indexer.Add(obj))
+		}
+		return indexer
+	}
+
+	client := kubeovnfake.NewSimpleClientset()
+	eips, fips := newIndexer(), newIndexer()
+	c := &Controller{
+		config: &Configuration{KubeOvnClient: client, ClusterRouter: util.DefaultVpc},
+		ippoolLister: kubeovnlister.NewIPPoolLister(newIndexer(
+			&kubeovnv1.IPPool{ObjectMeta: metav1.ObjectMeta{Name: "pool1"}, Spec: kubeovnv1.IPPoolSpec{Subnet: "external"}},
+			&kubeovnv1.IPPool{ObjectMeta: metav1.ObjectMeta{Name: "pool2"}, Spec: kubeovnv1.IPPoolSpec{Subnet: "vpc1-subnet"}},
+		)),
+		subnetsLister: kubeovnlister.NewSubnetLister(newIndexer(
+			&kubeovnv1.Subnet{ObjectMeta: metav1.ObjectMeta{Name: "external"}, Spec: kubeovnv1.SubnetSpec{CIDRBlock: "172.18.0.0/16"}},
+			&kubeovnv1.Subnet{ObjectMeta: metav1.ObjectMeta{Name: "vpc1-subnet"}, Spec: kubeovnv1.SubnetSpec{Vpc: "vpc1", CIDRBlock: "10.0.1.0/24"}},
+		)),
+		ovnEipsLister:      kubeovnlister.NewOvnEipLister(eips),
+		ovnFipsLister:      kubeovnlister.NewOvnFipLister(fips),
+		iptablesEipsLister: kubeovnlister.NewIptablesEIPLister(newIndexer()),
+		iptablesFipsLister: kubeovnlister.NewIptablesFIPRuleLister(newIndexer()),
+	}
+	return &eipPoolTestController{Controller: c, client: client, eips: eips, fips: fips}
+}
+
+func Test_allocateOvnEipFromPool(t *testing.T) {
+	t.Parallel()
+
+	c := newEipPoolTestController(t)
+	fip := &kubeovnv1.OvnFip{ObjectMeta: metav1.ObjectMeta{Name: "fip1", UID: "uid-fip1"}}
+
+	eipName, err := c.allocateOvnEipFromPool(util.FipUsingEip, ovnFipKind, fip, "pool1")
+	require.NoError(t, err)
+	require.Equal(t, "fip-fip1", eipName)
+	eip, err := c.client.KubeovnV1().OvnEips().Get(context.Background(), eipName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, kubeovnv1.OvnEipSpec{ExternalSubnet: "external", IPPool: "pool1", Type: util.OvnEipTypeNAT}, eip.Spec)
+	require.Equal(t, "pool1", eip.Labels[util.EipPoolLabel])
+	require.True(t, isOwnedBy(eip, fip))
+
+	// the eip owned by the nat rule is reused
+	require.NoError(t, c.eips.Add(eip))
+	eipName, err = c.allocateOvnEipFromPool(util.FipUsingEip, ovnFipKind, fip, "pool1")
+	require.NoError(t, err)
+	require.Equal(t, "fip-fip1", eipName)
+
+	// the eip of the same name owned by others is not taken over
+	other := &kubeovnv1.OvnFip{ObjectMeta: metav1.ObjectMeta{Name: "fip1", UID: "uid-other"}}
+	_, err = c.allocateOvnEipFromPool(util.FipUsingEip, ovnFipKind, other, "pool1")
+	require.Error(t, err)
+
+	// the pool must be in an external subnet
+	fip2 := &kubeovnv1.OvnFip{ObjectMeta: metav1.ObjectMeta{Name: "fip2", UID: "uid-fip2"}}
+	_, err = c.allocateOvnEipFromPool(util.FipUsingEip, ovnFipKind, fip2, "pool2")
+	require.Error(t, err)
+	_, err = c.allocateOvnEipFromPool(util.FipUsingEip, ovnFipKind, fip2, "nonexistent")
+	require.Error(t, err)
+}
+
+func Test_allocateIptablesEipFromPool(t *testing.T) {
+	t.Parallel()
+
+	c := newEipPoolTestController(t)
+	fip := &kubeovnv1.IptablesFIPRule{ObjectMeta: metav1.ObjectMeta{Name: "fip1", UID: "uid-fip1"}}
+
+	// the vpc nat gateway is required
+	_, err := c.allocateIptablesEipFromPool(util.FipUsingEip, iptablesFipKind, fip, "pool1", "")
+	require.Error(t, err)
+}
+
+func Test_syncAutoFip(t *testing.T) {
+	t.Parallel()
+
+	c := newEipPoolTestController(t)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:        "pod1",
+		Namespace:   "default",
+		Annotations: map[string]string{util.EipPoolAnnotation: "pool1"},
+	}}
+	fipName := autoFipName(autoFipPodKind, pod)
+
+	// allocate
+	require.NoError(t, c.syncAutoFip(autoFipPodKind, pod, "10.16.0.5", "fd00::5", util.DefaultVpc))
+	fip, err := c.client.KubeovnV1().OvnFips().Get(context.Background(), fipName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, kubeovnv1.OvnFipSpec{Vpc: util.DefaultVpc, V4Ip: "10.16.0.5", V6Ip: "fd00::5", EipPool: "pool1"}, fip.Spec)
+	require.Equal(t, autoFipPodKind, fip.Labels[util.AutoFipLabel])
+	require.Equal(t, "default/pod1", fip.Annotations[util.AutoFipOwnerAnnotation])
+	require.NoError(t, c.fips.Add(fip))
+
+	// the internal ips are updated along with the pod
+	require.NoError(t, c.syncAutoFip(autoFipPodKind, pod, "10.16.0.6", "", util.DefaultVpc))
+	fip, err = c.client.KubeovnV1().OvnFips().Get(context.Background(), fipName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "10.16.0.6", fip.Spec.V4Ip)
+	require.Empty(t, fip.Spec.V6Ip)
+	require.NoError(t, c.fips.Update(fip))
+
+	// release after the annotation is removed
+	pod.Annotations = nil
+	require.NoError(t, c.syncAutoFip(autoFipPodKind, pod, "10.16.0.6", "", util.DefaultVpc))
+	_, err = c.client.KubeovnV1().OvnFips().Get(context.Background(), fipName, metav1.GetOptions{})
+	require.True(t, k8serrors.IsNotFound(err))
+}
+
+func Test_reconcilePodAutoFip(t *testing.T) {
+	t.Parallel()
+
+	c := newEipPoolTestController(t)
+	subnet := &kubeovnv1.Subnet{ObjectMeta: metav1.ObjectMeta{Name: "ovn-default"}, Spec: kubeovnv1.SubnetSpec{Vpc: util.DefaultVpc}}
+	podNets := []*kubeovnNet{{Type: providerTypeOriginal, ProviderName: util.OvnProvider, Subnet: subnet, IsDefault: true}}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "pod1",
+		Namespace: "default",
+		Annotations: map[string]string{
+			util.EipPoolAnnotation:   "pool1",
+			util.IPAddressAnnotation: "10.16.0.5",
+		},
+	}}
+	fipName := autoFipName(autoFipPodKind, pod)
+
+	// the pod is not routed yet
+	require.NoError(t, c.reconcilePodAutoFip(pod, podNets))
+	_, err := c.client.KubeovnV1().OvnFips().Get(context.Background(), fipName, metav1.GetOptions{})
+	require.True(t, k8serrors.IsNotFound(err))
+
+	// the annotation is added to the running pod
+	pod.Annotations[util.RoutedAnnotation] = "true"
+	require.NoError(t, c.reconcilePodAutoFip(pod, podNets))
+	fip, err := c.client.KubeovnV1().OvnFips().Get(context.Background(), fipName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "10.16.0.5", fip.Spec.V4Ip)
+}
+
+func Test_enqueueUpdateServiceEipPool(t *testing.T) {
+	t.Parallel()
+
+	c := &Controller{updateServiceQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "")}
+	oldSvc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc1", Namespace: "default", ResourceVersion: "1"}}
+	newSvc := oldSvc.DeepCopy()
+	newSvc.ResourceVersion = "2"
+	newSvc.Annotations = map[string]string{util.EipPoolAnnotation: "pool1"}
+
+	// the service is synced when the annotation is added after its creation
+	c.enqueueUpdateService(oldSvc, newSvc)
+	require.Equal(t, 1, c.updateServiceQueue.Len())
+}
@@ -3,57 +3,70 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/scylladb/go-set/strset"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 
 	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
 	"github.com/kubeovn/kube-ovn/pkg/util"
 )
 
-func (c *Controller) enqueueAddEndpoint(obj interface{}) {
-	var (
-		key string
-		err error
-	)
-
-	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
-		utilruntime.HandleError(err)
+func (c *Controller) enqueueAddEndpointSlice(obj interface{}) {
+	key, ok := endpointSliceServiceKey(obj.(*discoveryv1.EndpointSlice))
+	if !ok {
 		return
 	}
-	klog.V(3).Infof("enqueue add endpoint %s", key)
+	klog.V(3).Infof("enqueue add endpoint slice for service %s", key)
 	c.updateEndpointQueue.Add(key)
 }
 
-func (c *Controller) enqueueUpdateEndpoint(oldObj, newObj interface{}) {
-	oldEp := oldObj.(*v1.Endpoints)
-	newEp := newObj.(*v1.Endpoints)
-	if oldEp.ResourceVersion == newEp.ResourceVersion {
+func (c *Controller) enqueueUpdateEndpointSlice(oldObj, newObj interface{}) {
+	oldEps := oldObj.(*discoveryv1.EndpointSlice)
+	newEps := newObj.(*discoveryv1.EndpointSlice)
+	if oldEps.ResourceVersion == newEps.ResourceVersion {
 		return
 	}
 
-	if len(oldEp.Subsets) == 0 && len(newEp.Subsets) == 0 {
+	if len(oldEps.Endpoints) == 0 && len(newEps.Endpoints) == 0 {
 		return
 	}
 
-	var (
-		key string
-		err error
-	)
+	key, ok := endpointSliceServiceKey(newEps)
+	if !ok {
+		return
+	}
+	klog.V(3).Infof("enqueue update endpoint slice for service %s", key)
+	c.updateEndpointQueue.Add(key)
+}
 
-	if key, err = cache.MetaNamespaceKeyFunc(newObj); err != nil {
-		utilruntime.HandleError(err)
+func (c *Controller) enqueueDeleteEndpointSlice(obj interface{}) {
+	key, ok := endpointSliceServiceKey(obj.(*discoveryv1.EndpointSlice))
+	if !ok {
 		return
 	}
-	klog.V(3).Infof("enqueue update endpoint %s", key)
+	klog.V(3).Infof("enqueue delete endpoint slice for service %s", key)
 	c.updateEndpointQueue.Add(key)
 }
 
+// endpointSliceServiceKey returns the key of the service which the endpoint slice belongs to
+func endpointSliceServiceKey(eps *discoveryv1.EndpointSlice) (string, bool) {
+	svcName := eps.Labels[discoveryv1.LabelServiceName]
+	if svcName == "" {
+		return "", false
+	}
+	return cache.NewObjectName(eps.Namespace, svcName).String(), true
+}
+
 func (c *Controller) runUpdateEndpointWorker() {
 	for c.processNextUpdateEndpointWorkItem() {
 	}
@@ -103,14 +116,12 @@ func (c *Controller) handleUpdateEndpoint(key string) error {
 
 	c.epKeyMutex.LockKey(key)
 	defer func() { _ = c.epKeyMutex.UnlockKey(key) }()
-	klog.Infof("update add/update endpoint %s/%s", namespace, name)
+	klog.Infof("update add/update endpoint slices for service %s/%s", namespace, name)
 
-	ep, err := c.endpointsLister.Endpoints(namespace).Get(name)
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: name})
+	endpointSlices, err := c.endpointSlicesLister.EndpointSlices(namespace).List(selector)
 	if err != nil {
-		if errors.IsNotFound(err) {
-			return nil
-		}
-		klog.Error(err)
+		klog.Errorf("failed to list endpoint slices for service %s/%s: %v", namespace, name, err)
 		return err
 	}
 
@@ -135,11 +146,11 @@ func (c *Controller) handleUpdateEndpoint(key string) error {
 	if vip, ok = svc.Annotations[util.SwitchLBRuleVipsAnnotation]; ok {
 		lbVips = []string{vip}
 
-		for _, subset := range ep.Subsets {
-			for _, address := range subset.Addresses {
+		for _, eps := range endpointSlices {
+			for _, endpoint := range eps.Endpoints {
 				// TODO: IPv6
 				if util.CheckProtocol(vip) == kubeovnv1.ProtocolIPv4 &&
-					address.TargetRef.Name != "" {
+					endpoint.TargetRef != nil && endpoint.TargetRef.Name != "" {
 					ignoreHealthCheck = false
 				}
 			}
@@ -153,7 +164,12 @@ func (c *Controller) handleUpdateEndpoint(key string) error {
 		return err
 	}
 
-	vpcName, subnetName = c.getVpcSubnetName(pods, ep, svc)
+	vpcName, subnetName = c.getVpcSubnetName(pods, endpointSlices, svc)
+
+	var unhealthyIPs []string
+	if svc.Annotations[util.LbHealthCheckTypeAnnotation] != "" {
+		endpointSlices, unhealthyIPs = filterUnhealthyEndpoints(endpointSlices, pods, svc.Name)
+	}
 
 	var (
 		vpc    *kubeovnv1.Vpc
@@ -211,7 +227,15 @@ func (c *Controller) handleUpdateEndpoint(key string) error {
 				}
 			}
 
-			ipPortMapping, backends = getIPPortMappingBackend(ep, pods, port, lbVip, checkIP, ignoreHealthCheck)
+			ipPortMapping, backends = getIPPortMappingBackend(endpointSlices, pods, port, lbVip, checkIP, ignoreHealthCheck)
+
+			if !ignoreHealthCheck && len(unhealthyIPs) != 0 {
+				klog.Infof("delete ip port mappings of unhealthy backends %v from LB %s", unhealthyIPs, lb)
+				if err = c.OVNNbClient.LoadBalancerDeleteIPPortMappingByIPs(lb, unhealthyIPs...); err != nil {
+					klog.Errorf("failed to delete ip port mappings of %v from LB %s: %v", unhealthyIPs, lb, err)
+					return err
+				}
+			}
 
 			// for performance reason delete lb with no backends
 			if len(backends) != 0 {
@@ -245,7 +269,7 @@ func (c *Controller) handleUpdateEndpoint(key string) error {
 	return nil
 }
 
-func (c *Controller) getVpcSubnetName(pods []*v1.Pod, endpoints *v1.Endpoints, service *v1.Service) (string, string) {
+func (c *Controller) getVpcSubnetName(pods []*v1.Pod, endpointSlices []*discoveryv1.EndpointSlice, service *v1.Service) (string, string) {
 	var (
 		vpcName    string
 		subnetName string
@@ -261,15 +285,17 @@ func (c *Controller) getVpcSubnetName(pods []*v1.Pod, endpoints *v1.Endpoints, s
 		}
 
 	LOOP:
-		for _, subset := range endpoints.Subsets {
-			for _, addr := range subset.Addresses {
-				if addr.IP == pod.Status.PodIP {
-					if vpcName == "" {
-						vpcName = pod.Annotations[util.LogicalRouterAnnotation]
-					}
+		for _, eps := range endpointSlices {
+			for _, endpoint := range eps.Endpoints {
+				for _, addr := range endpoint.Addresses {
+					if addr == pod.Status.PodIP {
+						if vpcName == "" {
+							vpcName = pod.Annotations[util.LogicalRouterAnnotation]
+						}
 
-					if vpcName != "" {
-						break LOOP
+						if vpcName != "" {
+							break LOOP
+						}
 					}
 				}
 			}
@@ -340,18 +366,27 @@ func (c *Controller) getHealthCheckVip(vpcName, subnetName, lbVip string) (strin
 	return checkIP, nil
 }
 
-func getIPPortMappingBackend(endpoints *v1.Endpoints, pods []*v1.Pod, servicePort v1.ServicePort, serviceIP, checkVip string, ignoreHealthCheck bool) (map[string]string, []string) {
+// getIPPortMappingBackend aggregates the backends of the service port from all the endpoint slices of the service.
+// Ready endpoints are preferred, serving but terminating endpoints are used only when no ready endpoint exists,
+// so that the terminating backends are drained gracefully.
+func getIPPortMappingBackend(endpointSlices []*discoveryv1.EndpointSlice, pods []*v1.Pod, servicePort v1.ServicePort, serviceIP, checkVip string, ignoreHealthCheck bool) (map[string]string, []string) {
 	var (
-		ipPortMapping = map[string]string{}
-		backends      = []string{}
-		protocol      = util.CheckProtocol(serviceIP)
+		ipPortMapping       = map[string]string{}
+		backends            = []string{}
+		terminatingBackends = []string{}
+		protocol            = util.CheckProtocol(serviceIP)
+		existing            = strset.New()
 	)
 
-	for _, subset := range endpoints.Subsets {
+	for _, eps := range endpointSlices {
+		if eps.AddressType == discoveryv1.AddressTypeFQDN {
+			continue
+		}
+
 		var targetPort int32
-		for _, port := range subset.Ports {
-			if port.Name == servicePort.Name {
-				targetPort = port.Port
+		for _, port := range eps.Ports {
+			if port.Port != nil && ptr.Deref(port.Name, "") == servicePort.Name {
+				targetPort = *port.Port
 				break
 			}
 		}
@@ -359,41 +394,112 @@ func getIPPortMappingBackend(endpoints *v1.Endpoints, pods []*v1.Pod, servicePor
 			continue
 		}
 
-		for _, address := range subset.Addresses {
-			if !ignoreHealthCheck && address.TargetRef.Name != "" {
-				ipName := fmt.Sprintf("%s.%s", address.TargetRef.Name, endpoints.Namespace)
-				ipPortMapping[address.IP] = fmt.Sprintf(util.HealthCheckNamedVipTemplate, ipName, checkVip)
+		for _, endpoint := range eps.Endpoints {
+			ready, serving := endpointReady(endpoint), endpointServing(endpoint)
+			if !ready && !serving {
+				continue
 			}
-			if address.TargetRef == nil || address.TargetRef.Kind != "Pod" {
-				if util.CheckProtocol(address.IP) == protocol {
-					backends = append(backends, util.JoinHostPort(address.IP, targetPort))
-				}
+
+			ip := getEndpointBackendIP(endpoint, pods, protocol)
+			if ip == "" {
 				continue
 			}
-			var ip string
-			for _, pod := range pods {
-				if pod.Name == address.TargetRef.Name {
-					podIPs := pod.Status.PodIPs
-					if len(podIPs) == 0 && pod.Status.PodIP != "" {
-						podIPs = []v1.PodIP{{IP: pod.Status.PodIP}}
-					}
-					for _, podIP := range podIPs {
-						if util.CheckProtocol(podIP.IP) == protocol {
-							ip = podIP.IP
-							break
-						}
-					}
-					break
-				}
+
+			backend := util.JoinHostPort(ip, targetPort)
+			if existing.Has(backend) {
+				continue
 			}
-			if ip == "" && util.CheckProtocol(address.IP) == protocol {
-				ip = address.IP
+			existing.Add(backend)
+
+			if !ignoreHealthCheck && endpoint.TargetRef != nil && endpoint.TargetRef.Name != "" {
+				ipName := fmt.Sprintf("%s.%s", endpoint.TargetRef.Name, eps.Namespace)
+				ipPortMapping[ip] = fmt.Sprintf(util.HealthCheckNamedVipTemplate, ipName, checkVip)
 			}
-			if ip != "" {
-				backends = append(backends, util.JoinHostPort(ip, targetPort))
+			if ready {
+				backends = append(backends, backend)
+			} else {
+				terminatingBackends = append(terminatingBackends, backend)
 			}
 		}
 	}
 
+	if len(backends) == 0 {
+		backends = terminatingBackends
+	}
+
 	return ipPortMapping, backends
 }
+
+// filterUnhealthyEndpoints removes the endpoints whose target pod failed the active health check of the service,
+// the ips of the unhealthy pods are returned as well
+func filterUnhealthyEndpoints(endpointSlices []*discoveryv1.EndpointSlice, pods []*v1.Pod, svcName string) ([]*discoveryv1.EndpointSlice, []string) {
+	var unhealthyIPs []string
+	unhealthyPods := strset.New()
+	for _, pod := range pods {
+		if services := pod.Annotations[util.LbUnhealthyServicesAnnotation]; services != "" {
+			if slices.Contains(strings.Split(services, ","), svcName) {
+				unhealthyPods.Add(pod.Name)
+				for _, podIP := range pod.Status.PodIPs {
+					unhealthyIPs = append(unhealthyIPs, podIP.IP)
+				}
+			}
+		}
+	}
+	if unhealthyPods.IsEmpty() {
+		return endpointSlices, nil
+	}
+
+	filtered := make([]*discoveryv1.EndpointSlice, 0, len(endpointSlices))
+	for _, eps := range endpointSlices {
+		newEps := *eps
+		newEps.Endpoints = make([]discoveryv1.Endpoint, 0, len(eps.Endpoints))
+		for _, endpoint := range eps.Endpoints {
+			if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" && unhealthyPods.Has(endpoint.TargetRef.Name) {
+				klog.V(3).Infof("skip unhealthy backend pod %s/%s of service %s", eps.Namespace, endpoint.TargetRef.Name, svcName)
+				continue
+			}
+			newEps.Endpoints = append(newEps.Endpoints, endpoint)
+		}
+		filtered = append(filtered, &newEps)
+	}
+	return filtered, unhealthyIPs
+}
+
+// endpointReady returns whether the endpoint is ready, nil should be interpreted as ready
+func endpointReady(endpoint discoveryv1.Endpoint) bool {
+	return ptr.Deref(endpoint.Conditions.Ready, true)
+}
+
+// endpointServing returns whether the endpoint is serving, nil should be interpreted as the value of ready
+func endpointServing(endpoint discoveryv1.Endpoint) bool {
+	return ptr.Deref(endpoint.Conditions.Serving, endpointReady(endpoint))
+}
+
+// getEndpointBackendIP returns the ip of the endpoint with the same protocol as the service ip.
+// The ip of the target pod is preferred since the endpoint slice of the other ip family may not exist.
+func getEndpointBackendIP(endpoint discoveryv1.Endpoint, pods []*v1.Pod, protocol string) string {
+	if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" {
+		for _, pod := range pods {
+			if pod.Name != endpoint.TargetRef.Name {
+				continue
+			}
+			podIPs := pod.Status.PodIPs
+			if len(podIPs) == 0 && pod.Status.PodIP != "" {
+				podIPs = []v1.PodIP{{IP: pod.Status.PodIP}}
+			}
+			for _, podIP := range podIPs {
+				if util.CheckProtocol(podIP.IP) == protocol {
+					return podIP.IP
+				}
+			}
+			break
+		}
+	}
+
+	for _, address := range endpoint.Addresses {
+		if util.CheckProtocol(address) == protocol {
+			return address
+		}
+	}
+	return ""
+}
@@ -0,0 +1,251 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+func mockEndpointSlice(name string, addressType discoveryv1.AddressType, port int32, endpoints ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+	return &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "svc"},
+		},
+		AddressType: addressType,
+		Ports: []discoveryv1.EndpointPort{{
+			Name:     ptr.To("http"),
+			Port:     ptr.To(port),
+			Protocol: ptr.To(v1.ProtocolTCP),
+		}},
+		Endpoints: endpoints,
+	}
+}
+
+func mockEndpoint(podName string, ready, serving, terminating *bool, addresses ...string) discoveryv1.Endpoint {
+	endpoint := discoveryv1.Endpoint{
+		Addresses: addresses,
+		Conditions: discoveryv1.EndpointConditions{
+			Ready:       ready,
+			Serving:     serving,
+			Terminating: terminating,
+		},
+	}
+	if podName != "" {
+		endpoint.TargetRef = &v1.ObjectReference{Kind: "Pod", Name: podName, Namespace: "default"}
+	}
+	return endpoint
+}
+
+func mockPod(name string, ips ...string) *v1.Pod {
+	pod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	for _, ip := range ips {
+		pod.Status.PodIPs = append(pod.Status.PodIPs, v1.PodIP{IP: ip})
+	}
+	if len(ips) != 0 {
+		pod.Status.PodIP = ips[0]
+	}
+	return pod
+}
+
+func Test_endpointSliceServiceKey(t *testing.T) {
+	t.Parallel()
+
+	eps := mockEndpointSlice("svc-abcde", discoveryv1.AddressTypeIPv4, 80)
+	key, ok := endpointSliceServiceKey(eps)
+	require.True(t, ok)
+	require.Equal(t, "default/svc", key)
+
+	eps.Labels = nil
+	_, ok = endpointSliceServiceKey(eps)
+	require.False(t, ok)
+}
+
+func Test_getIPPortMappingBackend(t *testing.T) {
+	t.Parallel()
+
+	servicePort := v1.ServicePort{Name: "http", Protocol: v1.ProtocolTCP, Port: 80}
+
+	t.Run("aggregate backends across slices", func(t *testing.T) {
+		t.Parallel()
+
+		slices := []*discoveryv1.EndpointSlice{
+			mockEndpointSlice("svc-1", discoveryv1.AddressTypeIPv4, 8080,
+				mockEndpoint("pod-1", nil, nil, nil, "10.16.0.2"),
+				mockEndpoint("pod-2", ptr.To(true), ptr.To(true), ptr.To(false), "10.16.0.3"),
+			),
+			mockEndpointSlice("svc-2", discoveryv1.AddressTypeIPv4, 8080,
+				mockEndpoint("pod-3", ptr.To(true), ptr.To(true), ptr.To(false), "10.16.0.4"),
+			),
+		}
+		pods := []*v1.Pod{mockPod("pod-1", "10.16.0.2"), mockPod("pod-2", "10.16.0.3"), mockPod("pod-3", "10.16.0.4")}
+
+		mapping, backends := getIPPortMappingBackend(slices, pods, servicePort, "10.96.0.10", "", true)
+		require.Empty(t, mapping)
+		require.ElementsMatch(t, []string{"10.16.0.2:8080", "10.16.0.3:8080", "10.16.0.4:8080"}, backends)
+	})
+
+	t.Run("skip terminating backends when ready backends exist", func(t *testing.T) {
+		t.Parallel()
+
+		slices := []*discoveryv1.EndpointSlice{
+			mockEndpointSlice("svc-1", discoveryv1.AddressTypeIPv4, 8080,
+				mockEndpoint("pod-1", ptr.To(true), ptr.To(true), ptr.To(false), "10.16.0.2"),
+				mockEndpoint("pod-2", ptr.To(false), ptr.To(true), ptr.To(true), "10.16.0.3"),
+				mockEndpoint("pod-3", ptr.To(false), ptr.To(false), ptr.To(false), "10.16.0.4"),
+			),
+		}
+
+		_, backends := getIPPortMappingBackend(slices, nil, servicePort, "10.96.0.10", "", true)
+		require.Equal(t, []string{"10.16.0.2:8080"}, backends)
+	})
+
+	t.Run("fall back to serving terminating backends", func(t *testing.T) {
+		t.Parallel()
+
+		slices := []*discoveryv1.EndpointSlice{
+			mockEndpointSlice("svc-1", discoveryv1.AddressTypeIPv4, 8080,
+				mockEndpoint("pod-1", ptr.To(false), ptr.To(true), ptr.To(true), "10.16.0.2"),
+				mockEndpoint("pod-2", ptr.To(false), ptr.To(false), ptr.To(true), "10.16.0.3"),
+			),
+		}
+
+		_, backends := getIPPortMappingBackend(slices, nil, servicePort, "10.96.0.10", "", true)
+		require.Equal(t, []string{"10.16.0.2:8080"}, backends)
+	})
+
+	t.Run("dual stack slices", func(t *testing.T) {
+		t.Parallel()
+
+		slices := []*discoveryv1.EndpointSlice{
+			mockEndpointSlice("svc-v4", discoveryv1.AddressTypeIPv4, 8080,
+				mockEndpoint("pod-1", nil, nil, nil, "10.16.0.2"),
+			),
+			mockEndpointSlice("svc-v6", discoveryv1.AddressTypeIPv6, 8080,
+				mockEndpoint("pod-1", nil, nil, nil, "fd00:10:16::2"),
+			),
+		}
+		pods := []*v1.Pod{mockPod("pod-1", "10.16.0.2", "fd00:10:16::2")}
+
+		_, backends := getIPPortMappingBackend(slices, pods, servicePort, "10.96.0.10", "", true)
+		require.Equal(t, []string{"10.16.0.2:8080"}, backends)
+
+		_, backends = getIPPortMappingBackend(slices, pods, servicePort, "fd00:10:96::10", "", true)
+		require.Equal(t, []string{"[fd00:10:16::2]:8080"}, backends)
+	})
+
+	t.Run("non pod endpoints and unmatched ports", func(t *testing.T) {
+		t.Parallel()
+
+		slices := []*discoveryv1.EndpointSlice{
+			mockEndpointSlice("svc-1", discoveryv1.AddressTypeIPv4, 8080,
+				mockEndpoint("", nil, nil, nil, "192.168.0.10"),
+			),
+			mockEndpointSlice("svc-2", discoveryv1.AddressTypeFQDN, 8080,
+				mockEndpoint("", nil, nil, nil, "example.com"),
+			),
+		}
+		_, backends := getIPPortMappingBackend(slices, nil, servicePort, "10.96.0.10", "", true)
+		require.Equal(t, []string{"192.168.0.10:8080"}, backends)
+
+		_, backends = getIPPortMappingBackend(slices, nil, v1.ServicePort{Name: "https", Port: 443}, "10.96.0.10", "", true)
+		require.Empty(t, backends)
+	})
+
+	t.Run("health check ip port mapping", func(t *testing.T) {
+		t.Parallel()
+
+		checkVip := "10.16.0.100"
+		slices := []*discoveryv1.EndpointSlice{
+			mockEndpointSlice("svc-1", discoveryv1.AddressTypeIPv4, 8080,
+				mockEndpoint("pod-1", nil, nil, nil, "10.16.0.2"),
+				mockEndpoint("", nil, nil, nil, "10.16.0.3"),
+			),
+		}
+
+		mapping, backends := getIPPortMappingBackend(slices, nil, servicePort, "10.96.0.10", checkVip, false)
+		require.ElementsMatch(t, []string{"10.16.0.2:8080", "10.16.0.3:8080"}, backends)
+		require.Equal(t, map[string]string{
+			"10.16.0.2": fmt.Sprintf(util.HealthCheckNamedVipTemplate, "pod-1.default", checkVip),
+		}, mapping)
+	})
+}
+
+func Test_filterUnhealthyEndpoints(t *testing.T) {
+	t.Parallel()
+
+	slices := []*discoveryv1.EndpointSlice{
+		mockEndpointSlice("svc-1", discoveryv1.AddressTypeIPv4, 8080,
+			mockEndpoint("pod-1", nil, nil, nil, "10.16.0.2"),
+			mockEndpoint("pod-2", nil, nil, nil, "10.16.0.3"),
+			mockEndpoint("", nil, nil, nil, "192.168.0.10"),
+		),
+	}
+	pods := []*v1.Pod{mockPod("pod-1", "10.16.0.2"), mockPod("pod-2", "10.16.0.3")}
+
+	t.Run("no unhealthy pods", func(t *testing.T) {
+		filtered, unhealthyIPs := filterUnhealthyEndpoints(slices, pods, "svc")
+		require.Equal(t, slices, filtered)
+		require.Empty(t, unhealthyIPs)
+	})
+
+	t.Run("remove unhealthy pods of the service", func(t *testing.T) {
+		unhealthyPod := mockPod("pod-2", "10.16.0.3")
+		unhealthyPod.Annotations = map[string]string{util.LbUnhealthyServicesAnnotation: "other,svc"}
+		otherPod := mockPod("pod-1", "10.16.0.2")
+		otherPod.Annotations = map[string]string{util.LbUnhealthyServicesAnnotation: "other"}
+
+		filtered, unhealthyIPs := filterUnhealthyEndpoints(slices, []*v1.Pod{otherPod, unhealthyPod}, "svc")
+		require.Equal(t, []string{"10.16.0.3"}, unhealthyIPs)
+		require.Len(t, slices[0].Endpoints, 3)
+
+		servicePort := v1.ServicePort{Name: "http", Protocol: v1.ProtocolTCP, Port: 80}
+		_, backends := getIPPortMappingBackend(filtered, pods, servicePort, "10.96.0.10", "", true)
+		require.ElementsMatch(t, []string{"10.16.0.2:8080", "192.168.0.10:8080"}, backends)
+	})
+}
+
+func Test_handleUpdateEndpointWithoutSlices(t *testing.T) {
+	t.Parallel()
+
+	fakeController := newFakeController(t)
+	ctrl := fakeController.fakeController
+	fakeinformers := fakeController.fakeinformers
+	mockOvnClient := fakeController.mockOvnClient
+
+	vpc := &kubeovnv1.Vpc{
+		ObjectMeta: metav1.ObjectMeta{Name: util.DefaultVpc},
+		Status: kubeovnv1.VpcStatus{
+			TCPLoadBalancer:        "cluster-tcp-loadbalancer",
+			TCPSessionLoadBalancer: "cluster-tcp-session-loadbalancer",
+		},
+	}
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "svc",
+			Namespace:   "default",
+			Annotations: map[string]string{util.VpcAnnotation: util.DefaultVpc},
+		},
+		Spec: v1.ServiceSpec{
+			ClusterIP:  "10.96.0.10",
+			ClusterIPs: []string{"10.96.0.10"},
+			Ports:      []v1.ServicePort{{Name: "http", Protocol: v1.ProtocolTCP, Port: 80}},
+		},
+	}
+	require.NoError(t, fakeinformers.vpcInformer.Informer().GetStore().Add(vpc))
+	require.NoError(t, fakeinformers.serviceInformer.Informer().GetStore().Add(svc))
+
+	// the backends are removed from the vips after the last endpoint slice is deleted
+	mockOvnClient.EXPECT().LoadBalancerDeleteVip("cluster-tcp-loadbalancer", "10.96.0.10:80", true).Return(nil)
+	mockOvnClient.EXPECT().LoadBalancerDeleteVip("cluster-tcp-session-loadbalancer", "10.96.0.10:80", true).Return(nil)
+	require.NoError(t, ctrl.handleUpdateEndpoint("default/svc"))
+}
@@ -0,0 +1,478 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/ovs"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// the source port of the traced tcp/udp microflow, which is the same as kubectl ko trace
+const flowTraceSrcPort = 10000
+
+// priority offset of the logical flows generated from the acls by ovn-northd
+const aclPriorityOffset = 1000
+
+var (
+	flowTracePipelineRegex = regexp.MustCompile(`^(ingress|egress)\(dp="([^"]*)"`)
+	flowTraceStageRegex    = regexp.MustCompile(`^\s*(\d+)\. (\S+)(?: \([^)]*\))?: (.*), priority (\d+), uuid ([0-9a-f]+)$`)
+	flowTraceOutputRegex   = regexp.MustCompile(`output to "([^"]+)"`)
+	flowTraceBackendsRegex = regexp.MustCompile(`backends=([^);]+)`)
+	flowTraceNATRegex      = regexp.MustCompile(`^ct_(snat|dnat)(?:_in_czone)?\((.+)\);$`)
+)
+
+// FlowTraceRequest is a microflow from a pod to the destination, which is traced by ovn-trace
+type FlowTraceRequest struct {
+	Namespace string
+	Pod       string
+	DstIP     string
+	Protocol  string
+	DstPort   int
+}
+
+// FlowTraceResult is the structured result of ovn-trace
+type FlowTraceResult struct {
+	Datapath      string                   `json:"datapath"`
+	Microflow     string                   `json:"microflow"`
+	Stages        []FlowTraceStage         `json:"stages"`
+	ACLs          []FlowTraceACL           `json:"acls,omitempty"`
+	LoadBalancers []FlowTraceLoadBalancing `json:"loadBalancers,omitempty"`
+	NAT           []FlowTraceNAT           `json:"nat,omitempty"`
+	OutputPort    string                   `json:"outputPort,omitempty"`
+	Dropped       bool                     `json:"dropped"`
+}
+
+// FlowTraceStage is a logical flow matched in a pipeline stage
+type FlowTraceStage struct {
+	Datapath string   `json:"datapath"`
+	Pipeline string   `json:"pipeline"`
+	Table    int      `json:"table"`
+	Name     string   `json:"name"`
+	Match    string   `json:"match"`
+	Priority int      `json:"priority"`
+	UUID     string   `json:"uuid"`
+	Actions  []string `json:"actions,omitempty"`
+}
+
+// FlowTraceACL is an acl matched by the microflow and the policy it is created for
+type FlowTraceACL struct {
+	Stage     string `json:"stage"`
+	Datapath  string `json:"datapath"`
+	Direction string `json:"direction"`
+	Priority  int    `json:"priority"`
+	Match     string `json:"match"`
+	Action    string `json:"action,omitempty"`
+	// PolicyKind is NetworkPolicy, SecurityGroup, Node or Subnet
+	PolicyKind string `json:"policyKind,omitempty"`
+	PolicyName string `json:"policyName,omitempty"`
+}
+
+// FlowTraceLoadBalancing is a load balancing decision of the microflow
+type FlowTraceLoadBalancing struct {
+	Stage    string   `json:"stage"`
+	Datapath string   `json:"datapath"`
+	Match    string   `json:"match"`
+	Backends []string `json:"backends,omitempty"`
+}
+
+// FlowTraceNAT is a snat or dnat applied to the microflow
+type FlowTraceNAT struct {
+	Stage    string `json:"stage"`
+	Datapath string `json:"datapath"`
+	Type     string `json:"type"`
+	Target   string `json:"target"`
+}
+
+// FlowTraceHandler serves the flow trace requests like
+// /api/v1/trace?pod=default/nginx&dst=10.16.0.10&protocol=tcp&port=80
+type FlowTraceHandler struct {
+	config *Configuration
+
+	// controller is set once the replica is elected as the leader,
+	// the logical switch ports, acls and port groups are read with its ovn nb client
+	controller atomic.Pointer[Controller]
+}
+
+func NewFlowTraceHandler(config *Configuration) *FlowTraceHandler {
+	return &FlowTraceHandler{config: config}
+}
+
+func (h *FlowTraceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeFlowTraceError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+	c := h.controller.Load()
+	if c == nil {
+		writeFlowTraceError(w, http.StatusServiceUnavailable, errors.New("flow trace is served by the leader of kube-ovn-controller"))
+		return
+	}
+	req, err := parseFlowTraceRequest(r)
+	if err != nil {
+		writeFlowTraceError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), time.Duration(h.config.OvnTimeout)*time.Second)
+	defer cancel()
+	result, err := h.trace(ctx, c.OVNNbClient, req)
+	if err != nil {
+		klog.Errorf("failed to trace flow from pod %s/%s to %s: %v", req.Namespace, req.Pod, req.DstIP, err)
+		status := http.StatusInternalServerError
+		var statusErr *flowTraceStatusError
+		if errors.As(err, &statusErr) {
+			status = statusErr.status
+		}
+		writeFlowTraceError(w, status, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(result); err != nil {
+		klog.Errorf("failed to write flow trace result: %v", err)
+	}
+}
+
+type flowTraceStatusError struct {
+	status int
+	err    error
+}
+
+func (e *flowTraceStatusError) Error() string { return e.err.Error() }
+
+func writeFlowTraceError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()}); err != nil {
+		klog.Errorf("failed to write flow trace error: %v", err)
+	}
+}
+
+func parseFlowTraceRequest(r *http.Request) (*FlowTraceRequest, error) {
+	query := r.URL.Query()
+	req := &FlowTraceRequest{
+		Namespace: "default",
+		DstIP:     query.Get("dst"),
+		Protocol:  strings.ToLower(query.Get("protocol")),
+	}
+
+	pod := query.Get("pod")
+	if pod == "" {
+		return nil, errors.New("missing source pod")
+	}
+	if ns, name, found := strings.Cut(pod, "/"); found {
+		req.Namespace, req.Pod = ns, name
+	} else {
+		req.Pod = pod
+	}
+	if net.ParseIP(req.DstIP) == nil {
+		return nil, fmt.Errorf("invalid destination ip %q", req.DstIP)
+	}
+
+	switch req.Protocol {
+	case "icmp":
+	case "tcp", "udp":
+		port, err := strconv.Atoi(query.Get("port"))
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid destination port %q", query.Get("port"))
+		}
+		req.DstPort = port
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q", req.Protocol)
+	}
+	return req, nil
+}
+
+func (h *FlowTraceHandler) trace(ctx context.Context, nbClient ovs.NbClient, req *FlowTraceRequest) (*FlowTraceResult, error) {
+	pod, err := h.config.KubeClient.CoreV1().Pods(req.Namespace).Get(ctx, req.Pod, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, &flowTraceStatusError{status: http.StatusNotFound, err: err}
+		}
+		klog.Error(err)
+		return nil, err
+	}
+	if pod.Spec.HostNetwork {
+		return nil, &flowTraceStatusError{status: http.StatusBadRequest, err: fmt.Errorf("pod %s/%s is in host network", req.Namespace, req.Pod)}
+	}
+	if pod.Annotations[util.AllocatedAnnotation] != "true" {
+		return nil, &flowTraceStatusError{status: http.StatusBadRequest, err: fmt.Errorf("address of pod %s/%s is not ready", req.Namespace, req.Pod)}
+	}
+
+	srcIP := ""
+	protocol := util.CheckProtocol(req.DstIP)
+	for _, ip := range strings.Split(pod.Annotations[util.IPAddressAnnotation], ",") {
+		if util.CheckProtocol(ip) == protocol {
+			srcIP = ip
+			break
+		}
+	}
+	if srcIP == "" {
+		return nil, &flowTraceStatusError{status: http.StatusBadRequest, err: fmt.Errorf("pod %s/%s has no %s address", req.Namespace, req.Pod, protocol)}
+	}
+
+	podName := pod.Name
+	if isVM, vmName := isVMPod(pod); isVM && h.config.EnableKeepVMIP {
+		podName = vmName
+	}
+	lsName := pod.Annotations[util.LogicalSwitchAnnotation]
+	lspName := ovs.PodNameToPortName(podName, pod.Namespace, util.OvnProvider)
+
+	dstMAC, err := getFlowTraceDstMAC(nbClient, h.config.ClusterRouter, req.DstIP, pod.Annotations[util.CidrAnnotation], lsName, pod.Annotations[util.LogicalRouterAnnotation])
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	microflow := flowTraceMicroflow(lspName, pod.Annotations[util.MacAddressAnnotation], srcIP, dstMAC, req)
+	output, err := h.ovnCommand(ctx, "ovn-trace", h.config.OvnSbAddr, lsName, microflow)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	result := parseOVNTrace(output)
+	result.Datapath, result.Microflow = lsName, microflow
+	if err = attributeFlowTraceACLs(nbClient, result); err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// getFlowTraceDstMAC returns the mac of the logical switch port of the source logical switch with the destination
+// ip in the same subnet, or the mac of the logical router port as the gateway
+func getFlowTraceDstMAC(nbClient ovs.NbClient, clusterRouter, dstIP, cidr, lsName, lrName string) (string, error) {
+	if util.CIDRContainIP(cidr, dstIP) {
+		lsList, err := nbClient.ListLogicalSwitch(false, func(ls *ovnnb.LogicalSwitch) bool {
+			return ls.Name == lsName
+		})
+		if err != nil {
+			klog.Errorf("failed to get logical switch %s: %v", lsName, err)
+			return "", err
+		}
+		if len(lsList) == 0 {
+			return "", fmt.Errorf("logical switch %s not found", lsName)
+		}
+		lspList, err := nbClient.ListLogicalSwitchPorts(false, nil, func(lsp *ovnnb.LogicalSwitchPort) bool {
+			return slices.Contains(lsList[0].Ports, lsp.UUID)
+		})
+		if err != nil {
+			klog.Errorf("failed to list logical switch ports of logical switch %s: %v", lsName, err)
+			return "", err
+		}
+		for _, lsp := range lspList {
+			for _, address := range lsp.Addresses {
+				fields := strings.Fields(address)
+				if len(fields) > 1 && slices.Contains(fields[1:], dstIP) {
+					return fields[0], nil
+				}
+			}
+		}
+	}
+
+	if lrName == "" {
+		lrName = clusterRouter
+	}
+	lrpName := fmt.Sprintf("%s-%s", lrName, lsName)
+	lrp, err := nbClient.GetLogicalRouterPort(lrpName, true)
+	if err != nil {
+		klog.Error(err)
+		return "", err
+	}
+	if lrp == nil || lrp.MAC == "" {
+		return "", fmt.Errorf("failed to find mac of logical router port %s", lrpName)
+	}
+	return lrp.MAC, nil
+}
+
+func flowTraceMicroflow(lspName, srcMAC, srcIP, dstMAC string, req *FlowTraceRequest) string {
+	af := "4"
+	if util.CheckProtocol(req.DstIP) == kubeovnv1.ProtocolIPv6 {
+		af = "6"
+	}
+	microflow := fmt.Sprintf(`inport == "%s" && ip.ttl == 64 && eth.src == %s && ip%s.src == %s && eth.dst == %s && ip%s.dst == %s`,
+		lspName, srcMAC, af, srcIP, dstMAC, af, req.DstIP)
+	if req.Protocol == "icmp" {
+		return microflow + " && icmp && ct.new"
+	}
+	return fmt.Sprintf("%s && %s.src == %d && %s.dst == %d && ct.new", microflow, req.Protocol, flowTraceSrcPort, req.Protocol, req.DstPort)
+}
+
+// ovnCommand runs ovn-trace against the database
+func (h *FlowTraceHandler) ovnCommand(ctx context.Context, cmd, db string, args ...string) (string, error) {
+	cmdArgs := []string{"--db=" + db, fmt.Sprintf("--timeout=%d", h.config.OvnTimeout)}
+	if strings.HasPrefix(db, "ssl:") {
+		cmdArgs = append(cmdArgs, "-p", "/var/run/tls/key", "-c", "/var/run/tls/cert", "-C", "/var/run/tls/cacert")
+	}
+	cmdArgs = append(cmdArgs, args...)
+	output, err := exec.CommandContext(ctx, cmd, cmdArgs...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s %s: %v, %q", cmd, strings.Join(args, " "), err, output)
+	}
+	return string(output), nil
+}
+
+// parseOVNTrace parses the detailed output of ovn-trace
+func parseOVNTrace(output string) *FlowTraceResult {
+	result := &FlowTraceResult{}
+	var datapath, pipeline string
+	var stage *FlowTraceStage
+	for _, line := range strings.Split(output, "\n") {
+		if match := flowTracePipelineRegex.FindStringSubmatch(line); match != nil {
+			pipeline, datapath, stage = match[1], match[2], nil
+			continue
+		}
+		if match := flowTraceStageRegex.FindStringSubmatch(line); match != nil {
+			table, _ := strconv.Atoi(match[1])
+			priority, _ := strconv.Atoi(match[4])
+			result.Stages = append(result.Stages, FlowTraceStage{
+				Datapath: datapath,
+				Pipeline: pipeline,
+				Table:    table,
+				Name:     match[2],
+				Match:    match[3],
+				Priority: priority,
+				UUID:     match[5],
+			})
+			stage = &result.Stages[len(result.Stages)-1]
+			continue
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "---") || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if match := flowTraceOutputRegex.FindStringSubmatch(line); match != nil {
+			result.OutputPort, result.Dropped = match[1], false
+		}
+		if line == "drop;" || strings.Contains(line, "implicit drop") {
+			result.Dropped = true
+		}
+		if stage == nil || strings.HasPrefix(line, "/*") || !strings.HasSuffix(line, ";") {
+			continue
+		}
+		stage.Actions = append(stage.Actions, line)
+
+		if strings.HasPrefix(line, "ct_lb") {
+			lb := FlowTraceLoadBalancing{Stage: stage.Name, Datapath: stage.Datapath, Match: stage.Match}
+			if match := flowTraceBackendsRegex.FindStringSubmatch(line); match != nil {
+				lb.Backends = strings.Split(match[1], ",")
+			}
+			result.LoadBalancers = append(result.LoadBalancers, lb)
+		} else if match := flowTraceNATRegex.FindStringSubmatch(line); match != nil {
+			result.NAT = append(result.NAT, FlowTraceNAT{Stage: stage.Name, Datapath: stage.Datapath, Type: match[1], Target: match[2]})
+		}
+	}
+	return result
+}
+
+// isFlowTraceACLStage returns whether the logical flow is generated from an acl
+func isFlowTraceACLStage(stage *FlowTraceStage) bool {
+	if !strings.Contains(stage.Name, "_acl") || stage.Priority < aclPriorityOffset {
+		return false
+	}
+	for _, suffix := range []string{"_pre_acl", "_acl_hint", "_acl_action"} {
+		if strings.Contains(stage.Name, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// attributeFlowTraceACLs finds the acls of the logical flows matched in the acl stages, and the network policies,
+// security groups or subnets which the acls are created for
+func attributeFlowTraceACLs(nbClient ovs.NbClient, result *FlowTraceResult) error {
+	acls := make(map[string][]ovnnb.ACL, 2)
+	for i := range result.Stages {
+		stage := &result.Stages[i]
+		if !isFlowTraceACLStage(stage) {
+			continue
+		}
+
+		direction := ovnnbACLDirection(stage.Name)
+		acl := FlowTraceACL{
+			Stage:     stage.Name,
+			Datapath:  stage.Datapath,
+			Direction: direction,
+			Priority:  stage.Priority - aclPriorityOffset,
+			Match:     stage.Match,
+		}
+		if _, ok := acls[direction]; !ok {
+			aclList, err := nbClient.ListAcls(direction, nil)
+			if err != nil {
+				klog.Error(err)
+				return err
+			}
+			acls[direction] = aclList
+		}
+		for _, row := range acls[direction] {
+			if row.Priority != acl.Priority || row.Match == "" || !strings.Contains(stage.Match, row.Match) {
+				continue
+			}
+			acl.Match, acl.Action = row.Match, row.Action
+			var err error
+			if acl.PolicyKind, acl.PolicyName, err = flowTraceACLPolicy(nbClient, row.ExternalIDs); err != nil {
+				klog.Error(err)
+				return err
+			}
+			break
+		}
+		result.ACLs = append(result.ACLs, acl)
+	}
+	return nil
+}
+
+func ovnnbACLDirection(stage string) string {
+	if strings.HasPrefix(stage, "ls_out_") {
+		return ovnnb.ACLDirectionToLport
+	}
+	return ovnnb.ACLDirectionFromLport
+}
+
+// flowTraceACLPolicy returns the kind and the name of the policy which the acl is created for
+func flowTraceACLPolicy(nbClient ovs.NbClient, externalIDs map[string]string) (string, string, error) {
+	if subnet := externalIDs["subnet"]; subnet != "" {
+		return "Subnet", subnet, nil
+	}
+	parent := externalIDs["parent"]
+	if parent == "" {
+		return "", "", nil
+	}
+	pg, err := nbClient.GetPortGroup(parent, true)
+	if err != nil {
+		klog.Error(err)
+		return "", "", err
+	}
+	if pg == nil {
+		return "", "", nil
+	}
+	if sg := pg.ExternalIDs[sgKey]; sg != "" {
+		return "SecurityGroup", sg, nil
+	}
+	if np := pg.ExternalIDs[networkPolicyKey]; np != "" {
+		if node, found := strings.CutPrefix(np, "node/"); found {
+			return "Node", node, nil
+		}
+		return "NetworkPolicy", np, nil
+	}
+	return "", "", nil
+}
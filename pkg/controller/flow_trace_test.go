@@ -0,0 +1,216 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+)
+
+const ovnTraceOutput = `# tcp,reg14=0x5,vlan_tci=0x0000,dl_src=00:00:00:11:22:33,dl_dst=00:00:00:44:55:66,nw_src=10.16.0.5,nw_dst=10.96.0.10,nw_tos=0,nw_ecn=0,nw_ttl=64,tp_src=10000,tp_dst=53,tcp_flags=0
+
+ingress(dp="ovn-default", inport="pod1.ns1")
+--------------------------------------------
+ 0. ls_in_check_port_sec (northd.c:8591): 1, priority 50, uuid 4a0d3a2c
+    reg0[15] = check_in_port_sec();
+    next;
+ 4. ls_in_pre_acl (northd.c:5997): ip, priority 100, uuid 3e6a5b2c
+    reg0[0] = 1;
+    next;
+ 8. ls_in_acl_eval (northd.c:6645): reg0[7] == 1 && (inport == @ovn.np.allow.dns.ns1_ingress && ip4), priority 3001, uuid 8d1e0f2a
+    reg8[16] = 1;
+    next;
+ 9. ls_in_acl_action (northd.c:6752): reg8[16] == 1, priority 1000, uuid 1b2c3d4e
+    reg8[16] = 0;
+    next;
+13. ls_in_lb (northd.c:7208): ct.new && ip4.dst == 10.96.0.10 && tcp.dst == 53, priority 120, uuid 5e6f7a8b
+    reg0[1] = 0;
+    ct_lb_mark(backends=10.16.0.7:53,10.16.0.8:53);
+
+ct_lb_mark /* default (use --ct to customize) */
+------------------------------------------------
+27. ls_in_l2_lkup (northd.c:9015): eth.dst == 00:00:00:44:55:66, priority 50, uuid 9c8d7e6f
+    outport = "ovn-default-ovn-cluster";
+    output;
+
+ingress(dp="ovn-cluster", inport="ovn-cluster-ovn-default")
+-----------------------------------------------------------
+ 6. lr_in_dnat (northd.c:10452): ct.est && ip4 && reg0 == 10.96.0.20, priority 100, uuid 0a1b2c3d
+    ct_dnat_in_czone(10.16.0.9);
+ 3. lr_out_snat (northd.c:11012): ip && ip4.src == 10.16.0.0/16, priority 153, uuid 2b3c4d5e
+    ct_snat(172.18.0.2);
+
+egress(dp="ovn-default", inport="ovn-default-ovn-cluster", outport="pod2.ns2")
+-----------------------------------------------------------------------------
+ 9. ls_out_apply_port_sec (northd.c:5697): 1, priority 0, uuid 6f7a8b9c
+    output;
+    /* output to "pod2.ns2", type "" */
+`
+
+func Test_parseOVNTrace(t *testing.T) {
+	t.Parallel()
+
+	result := parseOVNTrace(ovnTraceOutput)
+	require.Len(t, result.Stages, 9)
+	require.Equal(t, FlowTraceStage{
+		Datapath: "ovn-default",
+		Pipeline: "ingress",
+		Table:    8,
+		Name:     "ls_in_acl_eval",
+		Match:    "reg0[7] == 1 && (inport == @ovn.np.allow.dns.ns1_ingress && ip4)",
+		Priority: 3001,
+		UUID:     "8d1e0f2a",
+		Actions:  []string{"reg8[16] = 1;", "next;"},
+	}, result.Stages[2])
+	require.Equal(t, "egress", result.Stages[8].Pipeline)
+
+	require.Equal(t, []FlowTraceLoadBalancing{{
+		Stage:    "ls_in_lb",
+		Datapath: "ovn-default",
+		Match:    "ct.new && ip4.dst == 10.96.0.10 && tcp.dst == 53",
+		Backends: []string{"10.16.0.7:53", "10.16.0.8:53"},
+	}}, result.LoadBalancers)
+	require.Equal(t, []FlowTraceNAT{
+		{Stage: "lr_in_dnat", Datapath: "ovn-cluster", Type: "dnat", Target: "10.16.0.9"},
+		{Stage: "lr_out_snat", Datapath: "ovn-cluster", Type: "snat", Target: "172.18.0.2"},
+	}, result.NAT)
+	require.Equal(t, "pod2.ns2", result.OutputPort)
+	require.False(t, result.Dropped)
+
+	var aclStages []string
+	for i := range result.Stages {
+		if isFlowTraceACLStage(&result.Stages[i]) {
+			aclStages = append(aclStages, result.Stages[i].Name)
+		}
+	}
+	require.Equal(t, []string{"ls_in_acl_eval"}, aclStages)
+}
+
+func Test_parseOVNTraceDrop(t *testing.T) {
+	t.Parallel()
+
+	result := parseOVNTrace(`ingress(dp="ovn-default", inport="pod1.ns1")
+--------------------------------------------
+ 9. ls_in_acl_eval (northd.c:6645): reg0[7] == 1 && (inport == @ovn.sg.deny_all && ip), priority 3000, uuid 8d1e0f2a
+    drop;
+`)
+	require.Len(t, result.Stages, 1)
+	require.True(t, result.Dropped)
+	require.Empty(t, result.OutputPort)
+}
+
+func Test_parseFlowTraceRequest(t *testing.T) {
+	t.Parallel()
+
+	req, err := parseFlowTraceRequest(httptest.NewRequest(http.MethodGet, "/api/v1/trace?pod=ns1/pod1&dst=10.16.0.6&protocol=TCP&port=80", nil))
+	require.NoError(t, err)
+	require.Equal(t, &FlowTraceRequest{Namespace: "ns1", Pod: "pod1", DstIP: "10.16.0.6", Protocol: "tcp", DstPort: 80}, req)
+
+	req, err = parseFlowTraceRequest(httptest.NewRequest(http.MethodGet, "/api/v1/trace?pod=pod1&dst=fd00::6&protocol=icmp", nil))
+	require.NoError(t, err)
+	require.Equal(t, &FlowTraceRequest{Namespace: "default", Pod: "pod1", DstIP: "fd00::6", Protocol: "icmp"}, req)
+
+	for _, query := range []string{
+		"dst=10.16.0.6&protocol=icmp",
+		"pod=pod1&dst=pod2&protocol=icmp",
+		"pod=pod1&dst=10.16.0.6&protocol=sctp&port=80",
+		"pod=pod1&dst=10.16.0.6&protocol=udp",
+		"pod=pod1&dst=10.16.0.6&protocol=udp&port=65536",
+	} {
+		_, err = parseFlowTraceRequest(httptest.NewRequest(http.MethodGet, "/api/v1/trace?"+query, nil))
+		require.Error(t, err, query)
+	}
+}
+
+func Test_FlowTraceHandler(t *testing.T) {
+	t.Parallel()
+
+	fakeController := newFakeController(t)
+	config := &Configuration{KubeClient: fake.NewSimpleClientset(), OvnTimeout: 1}
+	handler := NewFlowTraceHandler(config)
+	serve := func() int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/trace?pod=ns1/pod1&dst=10.16.0.6&protocol=icmp", nil))
+		return rec.Code
+	}
+
+	// the requests are served only by the leader with the ovn nb client of the controller
+	require.Equal(t, http.StatusServiceUnavailable, serve())
+	handler.controller.Store(fakeController.fakeController)
+	require.Equal(t, http.StatusNotFound, serve())
+}
+
+func Test_flowTraceMicroflow(t *testing.T) {
+	t.Parallel()
+
+	microflow := flowTraceMicroflow("pod1.ns1", "00:00:00:11:22:33", "10.16.0.5", "00:00:00:44:55:66",
+		&FlowTraceRequest{DstIP: "10.16.0.6", Protocol: "udp", DstPort: 53})
+	require.Equal(t, `inport == "pod1.ns1" && ip.ttl == 64 && eth.src == 00:00:00:11:22:33 && ip4.src == 10.16.0.5 && eth.dst == 00:00:00:44:55:66 && ip4.dst == 10.16.0.6 && udp.src == 10000 && udp.dst == 53 && ct.new`, microflow)
+
+	microflow = flowTraceMicroflow("pod1.ns1", "00:00:00:11:22:33", "fd00::5", "00:00:00:44:55:66",
+		&FlowTraceRequest{DstIP: "fd00::6", Protocol: "icmp"})
+	require.Equal(t, `inport == "pod1.ns1" && ip.ttl == 64 && eth.src == 00:00:00:11:22:33 && ip6.src == fd00::5 && eth.dst == 00:00:00:44:55:66 && ip6.dst == fd00::6 && icmp && ct.new`, microflow)
+}
+
+func Test_getFlowTraceDstMAC(t *testing.T) {
+	t.Parallel()
+
+	mockOvnClient := newFakeController(t).mockOvnClient
+	lsList := []ovnnb.LogicalSwitch{{Name: "ovn-default", Ports: []string{"lsp-uuid-1", "lsp-uuid-2"}}}
+	lspList := []ovnnb.LogicalSwitchPort{
+		{UUID: "lsp-uuid-1", Name: "pod1.ns1", Addresses: []string{"00:00:00:11:22:33 10.16.0.5 fd00::5"}},
+		{UUID: "lsp-uuid-2", Name: "pod2.ns2", Addresses: []string{"00:00:00:44:55:66 10.16.0.6"}},
+	}
+
+	mockOvnClient.EXPECT().ListLogicalSwitch(false, gomock.Any()).Return(lsList, nil)
+	mockOvnClient.EXPECT().ListLogicalSwitchPorts(false, nil, gomock.Any()).DoAndReturn(
+		func(_ bool, _ map[string]string, filter func(lsp *ovnnb.LogicalSwitchPort) bool) ([]ovnnb.LogicalSwitchPort, error) {
+			// the ports of other logical switches are not looked at
+			require.False(t, filter(&ovnnb.LogicalSwitchPort{UUID: "lsp-uuid-3"}))
+			return lspList, nil
+		})
+	mac, err := getFlowTraceDstMAC(mockOvnClient, "ovn-cluster", "10.16.0.6", "10.16.0.0/16", "ovn-default", "")
+	require.NoError(t, err)
+	require.Equal(t, "00:00:00:44:55:66", mac)
+
+	mockOvnClient.EXPECT().GetLogicalRouterPort("ovn-cluster-ovn-default", true).Return(&ovnnb.LogicalRouterPort{MAC: "00:00:00:aa:bb:cc"}, nil)
+	mac, err = getFlowTraceDstMAC(mockOvnClient, "ovn-cluster", "10.17.0.6", "10.16.0.0/16", "ovn-default", "")
+	require.NoError(t, err)
+	require.Equal(t, "00:00:00:aa:bb:cc", mac)
+
+	mockOvnClient.EXPECT().GetLogicalRouterPort("vpc1-subnet1", true).Return(nil, nil)
+	_, err = getFlowTraceDstMAC(mockOvnClient, "ovn-cluster", "10.17.0.6", "10.16.0.0/16", "subnet1", "vpc1")
+	require.Error(t, err)
+}
+
+func Test_attributeFlowTraceACLs(t *testing.T) {
+	t.Parallel()
+
+	mockOvnClient := newFakeController(t).mockOvnClient
+	match := "inport == @ovn.np.allow.dns.ns1_ingress && ip4"
+	mockOvnClient.EXPECT().ListAcls(ovnnb.ACLDirectionFromLport, nil).Return([]ovnnb.ACL{
+		{Priority: 2000, Match: match, Action: ovnnb.ACLActionDrop},
+		{Priority: 2001, Match: match, Action: ovnnb.ACLActionAllowRelated, ExternalIDs: map[string]string{"parent": "ovn.np.allow.dns.ns1"}},
+	}, nil)
+	mockOvnClient.EXPECT().GetPortGroup("ovn.np.allow.dns.ns1", true).Return(&ovnnb.PortGroup{
+		ExternalIDs: map[string]string{networkPolicyKey: "ns1/allow-dns"},
+	}, nil)
+
+	result := parseOVNTrace(ovnTraceOutput)
+	require.NoError(t, attributeFlowTraceACLs(mockOvnClient, result))
+	require.Equal(t, []FlowTraceACL{{
+		Stage:      "ls_in_acl_eval",
+		Datapath:   "ovn-default",
+		Direction:  ovnnb.ACLDirectionFromLport,
+		Priority:   2001,
+		Match:      match,
+		Action:     ovnnb.ACLActionAllowRelated,
+		PolicyKind: "NetworkPolicy",
+		PolicyName: "ns1/allow-dns",
+	}}, result.ACLs)
+}
@@ -39,6 +39,7 @@ func (c *Controller) gc() error {
 		c.gcVip,
 		c.gcLbSvcPods,
 		c.gcVPCDNS,
+		c.gcAutoFip,
 	}
 	for _, gcFunc := range gcFunctions {
 		if err := gcFunc(); err != nil {
@@ -355,6 +356,10 @@ func (c *Controller) markAndCleanLSP() error {
 		}
 
 		klog.Infof("gc logical switch port %s", lsp.Name)
+		if err := c.deletePortQoSs(lsp.ExternalIDs[logicalSwitchKey], lsp.Name); err != nil {
+			klog.Errorf("failed to delete qos rules of lsp %s: %v", lsp.Name, err)
+			return err
+		}
 		if err := c.OVNNbClient.DeleteLogicalSwitchPort(lsp.Name); err != nil {
 			klog.Errorf("failed to delete lsp %s: %v", lsp.Name, err)
 			return err
@@ -467,6 +472,11 @@ func (c *Controller) gcLoadBalancer() error {
 		klog.Errorf("failed to list dnats, %v", err)
 		return err
 	}
+	fips, err := c.ovnFipsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list fips, %v", err)
+		return err
+	}
 
 	var (
 		tcpVips         = strset.NewWithSize(len(svcs) * 2)
@@ -525,6 +535,9 @@ func (c *Controller) gcLoadBalancer() error {
 	for _, dnat := range dnats {
 		vpcLbs = append(vpcLbs, dnat.Name)
 	}
+	for _, fip := range fips {
+		vpcLbs = append(vpcLbs, ovnFipHairpinLbName(fip.Name))
+	}
 
 	removeVip = func(lbName string, svcVips *strset.Set) error {
 		if lbName == "" {
@@ -948,3 +961,52 @@ func logicalRouterPortFilter(exceptPeerPorts *strset.Set) func(lrp *ovnnb.Logica
 		return lrp.Peer != nil && len(*lrp.Peer) != 0
 	}
 }
+
+func (c *Controller) gcAutoFip() error {
+	klog.Infof("start to gc auto fip")
+	selector, err := labels.Parse(util.AutoFipLabel)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	ownerExists := func(kind, owner string) bool {
+		namespace, name, _ := strings.Cut(owner, "/")
+		var err error
+		switch kind {
+		case autoFipPodKind:
+			_, err = c.podsLister.Pods(namespace).Get(name)
+		case autoFipServiceKind:
+			_, err = c.servicesLister.Services(namespace).Get(name)
+		}
+		return !k8serrors.IsNotFound(err)
+	}
+
+	ovnFips, err := c.ovnFipsLister.List(selector)
+	if err != nil {
+		klog.Errorf("failed to list ovn fip, %v", err)
+		return err
+	}
+	for _, fip := range ovnFips {
+		if ownerExists(fip.Labels[util.AutoFipLabel], fip.Annotations[util.AutoFipOwnerAnnotation]) {
+			continue
+		}
+		if err = c.deleteAutoOvnFip(fip.Name); err != nil {
+			return err
+		}
+	}
+
+	iptablesFips, err := c.iptablesFipsLister.List(selector)
+	if err != nil {
+		klog.Errorf("failed to list iptables fip, %v", err)
+		return err
+	}
+	for _, fip := range iptablesFips {
+		if ownerExists(fip.Labels[util.AutoFipLabel], fip.Annotations[util.AutoFipOwnerAnnotation]) {
+			continue
+		}
+		if err = c.deleteAutoIptablesFip(fip.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -388,12 +388,17 @@ func (c *Controller) handleDelIPFinalizer(cachedIP *kubeovnv1.IP, finalizer stri
 }
 
 func (c *Controller) acquireIPAddress(subnetName, name, nicName string) (string, string, string, error) {
+	return c.acquireIPAddressFromPool(subnetName, "", name, nicName)
+}
+
+// acquireIPAddressFromPool allocates the address from the ip pool of the subnet, or the subnet if poolName is empty
+func (c *Controller) acquireIPAddressFromPool(subnetName, poolName, name, nicName string) (string, string, string, error) {
 	var skippedAddrs []string
 	var v4ip, v6ip, mac string
 	checkConflict := true
 	var err error
 	for {
-		v4ip, v6ip, mac, err = c.ipam.GetRandomAddress(name, nicName, nil, subnetName, "", skippedAddrs, checkConflict)
+		v4ip, v6ip, mac, err = c.ipam.GetRandomAddress(name, nicName, nil, subnetName, poolName, skippedAddrs, checkConflict)
 		if err != nil {
 			klog.Error(err)
 			return "", "", "", err
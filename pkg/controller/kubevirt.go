@@ -0,0 +1,356 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+
+	"github.com/kubeovn/kube-ovn/pkg/ovs"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+const vmiMigrationCRDName = "virtualmachineinstancemigrations.kubevirt.io"
+
+// phases of the logical switch port binding during live migration, recorded in the annotation of the migration
+const (
+	liveMigrationPhaseDualBound  = "DualBound"
+	liveMigrationPhaseSucceeded  = "Succeeded"
+	liveMigrationPhaseRolledBack = "RolledBack"
+)
+
+func isVMIMigrationCRDInstalled(config *Configuration) (bool, error) {
+	_, err := config.KubevirtClient.ExtensionsClient().ApiextensionsV1().CustomResourceDefinitions().Get(context.Background(), vmiMigrationCRDName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return false, nil
+		}
+		klog.Errorf("failed to get crd %s, %v", vmiMigrationCRDName, err)
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *Controller) enqueueAddVMIMigration(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	klog.V(3).Infof("enqueue add vmi migration %s", key)
+	c.addOrUpdateVMIMigrationQueue.Add(key)
+}
+
+func (c *Controller) enqueueUpdateVMIMigration(oldObj, newObj interface{}) {
+	oldVmim := oldObj.(*kubevirtv1.VirtualMachineInstanceMigration)
+	newVmim := newObj.(*kubevirtv1.VirtualMachineInstanceMigration)
+	if oldVmim.Status.Phase == newVmim.Status.Phase {
+		return
+	}
+
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(newObj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	klog.V(3).Infof("enqueue update vmi migration %s, phase %s -> %s", key, oldVmim.Status.Phase, newVmim.Status.Phase)
+	c.addOrUpdateVMIMigrationQueue.Add(key)
+}
+
+func (c *Controller) enqueueDeleteVMIMigration(obj interface{}) {
+	var vmim *kubevirtv1.VirtualMachineInstanceMigration
+	switch t := obj.(type) {
+	case *kubevirtv1.VirtualMachineInstanceMigration:
+		vmim = t
+	case cache.DeletedFinalStateUnknown:
+		v, ok := t.Obj.(*kubevirtv1.VirtualMachineInstanceMigration)
+		if !ok {
+			klog.Warningf("unexpected object type: %T", t.Obj)
+			return
+		}
+		vmim = v
+	default:
+		klog.Warningf("unexpected type: %T", obj)
+		return
+	}
+
+	// the ports are left bound to both chassis only if the migration is deleted before it succeeds or fails
+	switch vmim.Annotations[util.LiveMigrationPhaseAnnotation] {
+	case liveMigrationPhaseSucceeded, liveMigrationPhaseRolledBack:
+		return
+	}
+	klog.V(3).Infof("enqueue delete vmi migration %s/%s", vmim.Namespace, vmim.Name)
+	c.deleteVMIMigrationQueue.Add(vmim)
+}
+
+func (c *Controller) runAddOrUpdateVMIMigrationWorker() {
+	for c.processNextAddOrUpdateVMIMigrationWorkItem() {
+	}
+}
+
+func (c *Controller) processNextAddOrUpdateVMIMigrationWorkItem() bool {
+	obj, shutdown := c.addOrUpdateVMIMigrationQueue.Get()
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.addOrUpdateVMIMigrationQueue.Done(obj)
+		var key string
+		var ok bool
+		if key, ok = obj.(string); !ok {
+			c.addOrUpdateVMIMigrationQueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.handleAddOrUpdateVMIMigration(key); err != nil {
+			c.addOrUpdateVMIMigrationQueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
+		}
+		c.addOrUpdateVMIMigrationQueue.Forget(obj)
+		return nil
+	}(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+	return true
+}
+
+func (c *Controller) runDeleteVMIMigrationWorker() {
+	for c.processNextDeleteVMIMigrationWorkItem() {
+	}
+}
+
+func (c *Controller) processNextDeleteVMIMigrationWorkItem() bool {
+	obj, shutdown := c.deleteVMIMigrationQueue.Get()
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.deleteVMIMigrationQueue.Done(obj)
+		var vmim *kubevirtv1.VirtualMachineInstanceMigration
+		var ok bool
+		if vmim, ok = obj.(*kubevirtv1.VirtualMachineInstanceMigration); !ok {
+			c.deleteVMIMigrationQueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected vmi migration in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.handleDeleteVMIMigration(vmim); err != nil {
+			c.deleteVMIMigrationQueue.AddRateLimited(obj)
+			return fmt.Errorf("error syncing '%s/%s': %s, requeuing", vmim.Namespace, vmim.Name, err.Error())
+		}
+		c.deleteVMIMigrationQueue.Forget(obj)
+		return nil
+	}(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+	return true
+}
+
+// liveMigrationPhase maps the phase of the vmi migration to the phase of the port binding,
+// an empty phase is returned if the port binding is not changed in the migration phase
+func liveMigrationPhase(phase kubevirtv1.VirtualMachineInstanceMigrationPhase) string {
+	switch phase {
+	case kubevirtv1.MigrationScheduled, kubevirtv1.MigrationPreparingTarget, kubevirtv1.MigrationTargetReady, kubevirtv1.MigrationRunning:
+		return liveMigrationPhaseDualBound
+	case kubevirtv1.MigrationSucceeded:
+		return liveMigrationPhaseSucceeded
+	case kubevirtv1.MigrationFailed:
+		return liveMigrationPhaseRolledBack
+	}
+	return ""
+}
+
+// handleAddOrUpdateVMIMigration binds the logical switch ports of the vm to both the source and the target chassis
+// while the vm is being migrated, so that the port on the target chassis is activated by the first RARP packet sent
+// by the migrated vm instead of waiting for the port to be rebound, and removes the binding once the migration
+// succeeds or fails, so that the ports are bound to the chassis where the vm is running
+func (c *Controller) handleAddOrUpdateVMIMigration(key string) error {
+	obj, exists, err := c.vmiMigrationStore.GetByKey(key)
+	if err != nil {
+		klog.Errorf("failed to get vmi migration %s, %v", key, err)
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	vmim := obj.(*kubevirtv1.VirtualMachineInstanceMigration)
+
+	phase := liveMigrationPhase(vmim.Status.Phase)
+	if phase == "" || vmim.Annotations[util.LiveMigrationPhaseAnnotation] == phase {
+		return nil
+	}
+
+	vmi, err := c.config.KubevirtClient.VirtualMachineInstance(vmim.Namespace).Get(context.Background(), vmim.Spec.VMIName, &metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf("failed to get vmi %s/%s, %v", vmim.Namespace, vmim.Spec.VMIName, err)
+		return err
+	}
+	state := vmi.Status.MigrationState
+	if state == nil || state.MigrationUID != vmim.UID {
+		if phase == liveMigrationPhaseDualBound {
+			// the target node is not known yet, wait for the next phase
+			return nil
+		}
+		klog.Warningf("migration state of vmi %s/%s does not belong to migration %s", vmi.Namespace, vmi.Name, vmim.Name)
+		return nil
+	}
+	if state.SourceNode == "" || state.TargetNode == "" {
+		return nil
+	}
+
+	portNames, err := c.getVMIMigrationPortNames(vmi, state.SourceNode)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if len(portNames) == 0 {
+		return nil
+	}
+
+	var srcChassis, targetChassis string
+	if phase == liveMigrationPhaseDualBound {
+		chassis, err := c.OVNSbClient.GetChassisByHost(state.SourceNode)
+		if err != nil {
+			klog.Errorf("failed to get chassis of source node %s, %v", state.SourceNode, err)
+			return err
+		}
+		srcChassis = chassis.Name
+		if chassis, err = c.OVNSbClient.GetChassisByHost(state.TargetNode); err != nil {
+			klog.Errorf("failed to get chassis of target node %s, %v", state.TargetNode, err)
+			return err
+		}
+		targetChassis = chassis.Name
+	}
+
+	for _, portName := range portNames {
+		if phase == liveMigrationPhaseDualBound {
+			err = c.OVNNbClient.SetLogicalSwitchPortMigrateOptions(portName, srcChassis, targetChassis)
+		} else {
+			err = c.OVNNbClient.ResetLogicalSwitchPortMigrateOptions(portName)
+		}
+		if err != nil {
+			klog.Errorf("failed to update migrate options of logical switch port %s, %v", portName, err)
+			c.recorder.Eventf(vmim, v1.EventTypeWarning, "UpdatePortBindingFailed", "failed to update binding of port %s: %v", portName, err)
+			return err
+		}
+	}
+
+	switch phase {
+	case liveMigrationPhaseDualBound:
+		c.recorder.Eventf(vmim, v1.EventTypeNormal, "PortDualBound", "ports %v are bound to both node %s and node %s until the vm sends a RARP packet from the target", portNames, state.SourceNode, state.TargetNode)
+	case liveMigrationPhaseSucceeded:
+		c.recorder.Eventf(vmim, v1.EventTypeNormal, "PortMigrated", "ports %v are bound to the target node %s", portNames, state.TargetNode)
+	case liveMigrationPhaseRolledBack:
+		c.recorder.Eventf(vmim, v1.EventTypeWarning, "PortRolledBack", "migration failed, ports %v are bound back to the source node %s", portNames, state.SourceNode)
+	}
+	klog.Infof("logical switch ports %v of vmi %s/%s are in phase %s of migration %s", portNames, vmi.Namespace, vmi.Name, phase, vmim.Name)
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{util.LiveMigrationPhaseAnnotation: phase},
+		},
+	})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err = c.config.KubevirtClient.VirtualMachineInstanceMigration(vmim.Namespace).Patch(vmim.Name, types.MergePatchType, patch); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf("failed to patch vmi migration %s/%s, %v", vmim.Namespace, vmim.Name, err)
+		return err
+	}
+	return nil
+}
+
+// handleDeleteVMIMigration removes the binding of the logical switch ports to both chassis if the migration is
+// deleted before it succeeds or fails, e.g. an aborted migration, otherwise the ports are left bound to both chassis
+func (c *Controller) handleDeleteVMIMigration(vmim *kubevirtv1.VirtualMachineInstanceMigration) error {
+	vmi, err := c.config.KubevirtClient.VirtualMachineInstance(vmim.Namespace).Get(context.Background(), vmim.Spec.VMIName, &metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf("failed to get vmi %s/%s, %v", vmim.Namespace, vmim.Spec.VMIName, err)
+		return err
+	}
+	if state := vmi.Status.MigrationState; state != nil && state.MigrationUID != vmim.UID && !state.Completed {
+		// the ports are bound to both chassis by another migration in progress
+		return nil
+	}
+
+	portNames, err := c.getVMIMigrationPortNames(vmi, vmi.Status.NodeName)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	for _, portName := range portNames {
+		if err = c.OVNNbClient.ResetLogicalSwitchPortMigrateOptions(portName); err != nil {
+			klog.Errorf("failed to reset migrate options of logical switch port %s, %v", portName, err)
+			return err
+		}
+	}
+	if len(portNames) != 0 {
+		klog.Infof("logical switch ports %v of vmi %s/%s are reset after migration %s is deleted", portNames, vmi.Namespace, vmi.Name, vmim.Name)
+	}
+	return nil
+}
+
+// getVMIMigrationPortNames returns the overlay logical switch ports of the vmi, which are shared by the source and
+// the target virt-launcher pods as the ports are named after the vm, so live migration optimize requires keep-vm-ip
+func (c *Controller) getVMIMigrationPortNames(vmi *kubevirtv1.VirtualMachineInstance, srcNode string) ([]string, error) {
+	selector := labels.SelectorFromSet(labels.Set{util.VMCreatedByLabel: string(vmi.UID)})
+	pods, err := c.podsLister.Pods(vmi.Namespace).List(selector)
+	if err != nil {
+		klog.Errorf("failed to list virt-launcher pods of vmi %s/%s, %v", vmi.Namespace, vmi.Name, err)
+		return nil, err
+	}
+	var srcPod *v1.Pod
+	for _, pod := range pods {
+		if pod.Spec.NodeName == srcNode {
+			srcPod = pod
+			break
+		}
+	}
+	if srcPod == nil {
+		klog.Infof("virt-launcher pod of vmi %s/%s is not found on source node %s", vmi.Namespace, vmi.Name, srcNode)
+		return nil, nil
+	}
+
+	podNets, err := c.getPodKubeovnNets(srcPod)
+	if err != nil {
+		klog.Errorf("failed to get networks of pod %s/%s, %v", srcPod.Namespace, srcPod.Name, err)
+		return nil, err
+	}
+	var portNames []string
+	for _, podNet := range podNets {
+		if podNet.Type == providerTypeIPAM || podNet.Subnet.Spec.Vlan != "" {
+			continue
+		}
+		if srcPod.Annotations[fmt.Sprintf(util.AllocatedAnnotationTemplate, podNet.ProviderName)] != "true" {
+			continue
+		}
+		portNames = append(portNames, ovs.PodNameToPortName(vmi.Name, vmi.Namespace, podNet.ProviderName))
+	}
+	return portNames, nil
+}
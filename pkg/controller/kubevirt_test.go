@@ -0,0 +1,266 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	kubevirtv1 "kubevirt.io/api/core/v1"
+	"kubevirt.io/client-go/kubecli"
+
+	mockovs "github.com/kubeovn/kube-ovn/mocks/pkg/ovs"
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/ovs"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnsb"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+func Test_liveMigrationPhase(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		phase kubevirtv1.VirtualMachineInstanceMigrationPhase
+		exp   string
+	}{
+		{kubevirtv1.MigrationPhaseUnset, ""},
+		{kubevirtv1.MigrationPending, ""},
+		{kubevirtv1.MigrationScheduling, ""},
+		{kubevirtv1.MigrationScheduled, liveMigrationPhaseDualBound},
+		{kubevirtv1.MigrationPreparingTarget, liveMigrationPhaseDualBound},
+		{kubevirtv1.MigrationTargetReady, liveMigrationPhaseDualBound},
+		{kubevirtv1.MigrationRunning, liveMigrationPhaseDualBound},
+		{kubevirtv1.MigrationSucceeded, liveMigrationPhaseSucceeded},
+		{kubevirtv1.MigrationFailed, liveMigrationPhaseRolledBack},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.phase), func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.exp, liveMigrationPhase(tt.phase))
+		})
+	}
+}
+
+func Test_handleAddOrUpdateVMIMigration(t *testing.T) {
+	t.Parallel()
+
+	const (
+		namespace  = "default"
+		vmName     = "vm1"
+		srcNode    = "node1"
+		targetNode = "node2"
+	)
+	portName := ovs.PodNameToPortName(vmName, namespace, util.OvnProvider)
+	subnet := &kubeovnv1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{Name: util.DefaultSubnet},
+		Spec:       kubeovnv1.SubnetSpec{Provider: util.OvnProvider},
+	}
+	vmi := &kubevirtv1.VirtualMachineInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: vmName, Namespace: namespace, UID: "vmi-uid"},
+		Status: kubevirtv1.VirtualMachineInstanceStatus{
+			MigrationState: &kubevirtv1.VirtualMachineInstanceMigrationState{
+				MigrationUID: "vmim-uid",
+				SourceNode:   srcNode,
+				TargetNode:   targetNode,
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "virt-launcher-vm1-abcde",
+			Namespace: namespace,
+			Labels:    map[string]string{util.VMCreatedByLabel: string(vmi.UID)},
+			Annotations: map[string]string{
+				util.LogicalSwitchAnnotation:                                    util.DefaultSubnet,
+				fmt.Sprintf(util.AllocatedAnnotationTemplate, util.OvnProvider): "true",
+			},
+		},
+		Spec: corev1.PodSpec{NodeName: srcNode},
+	}
+
+	tests := []struct {
+		name       string
+		phase      kubevirtv1.VirtualMachineInstanceMigrationPhase
+		annotated  string
+		expPhase   string
+		expOvnCall func(mock *mockovs.MockNbClient)
+	}{
+		{
+			name:  "pending migration is ignored",
+			phase: kubevirtv1.MigrationPending,
+		},
+		{
+			name:      "phase already handled",
+			phase:     kubevirtv1.MigrationRunning,
+			annotated: liveMigrationPhaseDualBound,
+		},
+		{
+			name:     "running migration binds the port to both chassis",
+			phase:    kubevirtv1.MigrationRunning,
+			expPhase: liveMigrationPhaseDualBound,
+			expOvnCall: func(mock *mockovs.MockNbClient) {
+				mock.EXPECT().SetLogicalSwitchPortMigrateOptions(portName, "chassis-"+srcNode, "chassis-"+targetNode).Return(nil)
+			},
+		},
+		{
+			name:     "succeeded migration removes the binding to both chassis",
+			phase:    kubevirtv1.MigrationSucceeded,
+			expPhase: liveMigrationPhaseSucceeded,
+			expOvnCall: func(mock *mockovs.MockNbClient) {
+				mock.EXPECT().ResetLogicalSwitchPortMigrateOptions(portName).Return(nil)
+			},
+		},
+		{
+			name:     "failed migration removes the binding to both chassis",
+			phase:    kubevirtv1.MigrationFailed,
+			expPhase: liveMigrationPhaseRolledBack,
+			expOvnCall: func(mock *mockovs.MockNbClient) {
+				mock.EXPECT().ResetLogicalSwitchPortMigrateOptions(portName).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fakeController := newFakeController(t)
+			ctrl := fakeController.fakeController
+			require.NoError(t, fakeController.fakeinformers.sbunetInformer.Informer().GetIndexer().Add(subnet))
+			require.NoError(t, fakeController.fakeinformers.podInformer.Informer().GetIndexer().Add(pod))
+
+			vmim := &kubevirtv1.VirtualMachineInstanceMigration{
+				ObjectMeta: metav1.ObjectMeta{Name: "vmim1", Namespace: namespace, UID: "vmim-uid"},
+				Spec:       kubevirtv1.VirtualMachineInstanceMigrationSpec{VMIName: vmName},
+				Status:     kubevirtv1.VirtualMachineInstanceMigrationStatus{Phase: tt.phase},
+			}
+			if tt.annotated != "" {
+				vmim.Annotations = map[string]string{util.LiveMigrationPhaseAnnotation: tt.annotated}
+			}
+			ctrl.vmiMigrationStore = cache.NewStore(cache.MetaNamespaceKeyFunc)
+			require.NoError(t, ctrl.vmiMigrationStore.Add(vmim))
+			ctrl.recorder = record.NewFakeRecorder(10)
+
+			mockCtrl := gomock.NewController(t)
+			virtClient := kubecli.NewMockKubevirtClient(mockCtrl)
+			ctrl.config.KubevirtClient = virtClient
+			sbClient := mockovs.NewMockSbClient(mockCtrl)
+			ctrl.OVNSbClient = sbClient
+
+			if tt.expPhase != "" {
+				vmiClient := kubecli.NewMockVirtualMachineInstanceInterface(mockCtrl)
+				virtClient.EXPECT().VirtualMachineInstance(namespace).Return(vmiClient)
+				vmiClient.EXPECT().Get(gomock.Any(), vmName, gomock.Any()).Return(vmi, nil)
+
+				if tt.expPhase == liveMigrationPhaseDualBound {
+					sbClient.EXPECT().GetChassisByHost(gomock.Any()).DoAndReturn(func(host string) (*ovnsb.Chassis, error) {
+						return &ovnsb.Chassis{Name: "chassis-" + host, Hostname: host}, nil
+					}).Times(2)
+				}
+				tt.expOvnCall(fakeController.mockOvnClient)
+
+				vmimClient := kubecli.NewMockVirtualMachineInstanceMigrationInterface(mockCtrl)
+				virtClient.EXPECT().VirtualMachineInstanceMigration(namespace).Return(vmimClient)
+				vmimClient.EXPECT().Patch(vmim.Name, types.MergePatchType, gomock.Any()).DoAndReturn(
+					func(_ string, _ types.PatchType, data []byte, _ ...string) (*kubevirtv1.VirtualMachineInstanceMigration, error) {
+						var patch struct {
+							Metadata metav1.ObjectMeta `json:"metadata"`
+						}
+						require.NoError(t, json.Unmarshal(data, &patch))
+						require.Equal(t, tt.expPhase, patch.Metadata.Annotations[util.LiveMigrationPhaseAnnotation])
+						return vmim, nil
+					})
+			}
+
+			require.NoError(t, ctrl.handleAddOrUpdateVMIMigration(namespace+"/"+vmim.Name))
+		})
+	}
+}
+
+func Test_handleDeleteVMIMigration(t *testing.T) {
+	t.Parallel()
+
+	const (
+		namespace = "default"
+		vmName    = "vm1"
+		node      = "node1"
+	)
+	portName := ovs.PodNameToPortName(vmName, namespace, util.OvnProvider)
+	subnet := &kubeovnv1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{Name: util.DefaultSubnet},
+		Spec:       kubeovnv1.SubnetSpec{Provider: util.OvnProvider},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "virt-launcher-vm1-abcde",
+			Namespace: namespace,
+			Labels:    map[string]string{util.VMCreatedByLabel: "vmi-uid"},
+			Annotations: map[string]string{
+				util.LogicalSwitchAnnotation:                                    util.DefaultSubnet,
+				fmt.Sprintf(util.AllocatedAnnotationTemplate, util.OvnProvider): "true",
+			},
+		},
+		Spec: corev1.PodSpec{NodeName: node},
+	}
+
+	tests := []struct {
+		name     string
+		state    *kubevirtv1.VirtualMachineInstanceMigrationState
+		expReset bool
+	}{
+		{
+			name:     "aborted migration",
+			state:    &kubevirtv1.VirtualMachineInstanceMigrationState{MigrationUID: "vmim-uid", SourceNode: node, TargetNode: "node2"},
+			expReset: true,
+		},
+		{
+			name:     "migration state not found",
+			expReset: true,
+		},
+		{
+			name:  "another migration in progress",
+			state: &kubevirtv1.VirtualMachineInstanceMigrationState{MigrationUID: "another-vmim-uid", SourceNode: node, TargetNode: "node2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fakeController := newFakeController(t)
+			ctrl := fakeController.fakeController
+			require.NoError(t, fakeController.fakeinformers.sbunetInformer.Informer().GetIndexer().Add(subnet))
+			require.NoError(t, fakeController.fakeinformers.podInformer.Informer().GetIndexer().Add(pod))
+
+			vmi := &kubevirtv1.VirtualMachineInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: vmName, Namespace: namespace, UID: "vmi-uid"},
+				Status:     kubevirtv1.VirtualMachineInstanceStatus{NodeName: node, MigrationState: tt.state},
+			}
+			vmim := &kubevirtv1.VirtualMachineInstanceMigration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "vmim1",
+					Namespace:   namespace,
+					UID:         "vmim-uid",
+					Annotations: map[string]string{util.LiveMigrationPhaseAnnotation: liveMigrationPhaseDualBound},
+				},
+				Spec: kubevirtv1.VirtualMachineInstanceMigrationSpec{VMIName: vmName},
+			}
+
+			mockCtrl := gomock.NewController(t)
+			virtClient := kubecli.NewMockKubevirtClient(mockCtrl)
+			ctrl.config.KubevirtClient = virtClient
+			vmiClient := kubecli.NewMockVirtualMachineInstanceInterface(mockCtrl)
+			virtClient.EXPECT().VirtualMachineInstance(namespace).Return(vmiClient)
+			vmiClient.EXPECT().Get(gomock.Any(), vmName, gomock.Any()).Return(vmi, nil)
+			if tt.expReset {
+				fakeController.mockOvnClient.EXPECT().ResetLogicalSwitchPortMigrateOptions(portName).Return(nil)
+			}
+
+			require.NoError(t, ctrl.handleDeleteVMIMigration(vmim))
+		})
+	}
+}
@@ -25,6 +25,7 @@ func (c *Controller) enqueueAddNamespace(obj interface{}) {
 			c.updateNpQueue.Add(np)
 		}
 	}
+	c.enqueueQoSPoliciesOfNamespaces(obj.(*v1.Namespace))
 	var key string
 	var err error
 	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
@@ -56,6 +57,9 @@ func (c *Controller) enqueueUpdateNamespace(oldObj, newObj interface{}) {
 			c.updateNpQueue.Add(np)
 		}
 	}
+	if !reflect.DeepEqual(oldNs.Labels, newNs.Labels) {
+		c.enqueueQoSPoliciesOfNamespaces(oldNs, newNs)
+	}
 
 	// in case annotations are removed by other controllers
 	if newNs.Annotations == nil || newNs.Annotations[util.LogicalSwitchAnnotation] == "" {
@@ -24,9 +24,115 @@ var (
 			"protocol",
 			"subnet_cidr",
 		})
+
+	metricVpcNatGwRulePackets = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "vpc_nat_gw_rule_packets",
+			Help: "The num of packets matched by the nat rule in vpc nat gateway pod.",
+		},
+		[]string{
+			"vpc_nat_gw",
+			"pod",
+			"kind",
+			"rule",
+		})
+
+	metricVpcNatGwRuleBytes = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "vpc_nat_gw_rule_bytes",
+			Help: "The num of bytes matched by the nat rule in vpc nat gateway pod.",
+		},
+		[]string{
+			"vpc_nat_gw",
+			"pod",
+			"kind",
+			"rule",
+		})
+
+	metricVpcNatGwRuleDrifts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "vpc_nat_gw_rule_drift_total",
+			Help: "The num of drifted rules corrected in vpc nat gateway.",
+		},
+		[]string{
+			"vpc_nat_gw",
+			"kind",
+			"reason",
+		})
+
+	metricVpcNatGwConntrackEntries = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "vpc_nat_gw_conntrack_entries",
+			Help: "The num of conntrack entries in vpc nat gateway pod.",
+		},
+		[]string{
+			"vpc_nat_gw",
+			"pod",
+		})
+
+	metricVpcNatGwConntrackMax = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "vpc_nat_gw_conntrack_max",
+			Help: "The size of the conntrack table of vpc nat gateway pod.",
+		},
+		[]string{
+			"vpc_nat_gw",
+			"pod",
+		})
+
+	metricVpcNatGwConntrackDrops = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "vpc_nat_gw_conntrack_drops",
+			Help: "The num of packets dropped since the conntrack entries failed to be created in vpc nat gateway pod.",
+		},
+		[]string{
+			"vpc_nat_gw",
+			"pod",
+		})
+
+	metricVpcNatGwConntrackLimitDrops = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "vpc_nat_gw_conntrack_limit_drops",
+			Help: "The num of packets dropped by the connection limit of vpc nat gateway pod.",
+		},
+		[]string{
+			"vpc_nat_gw",
+			"pod",
+		})
+
+	metricVpcNatGwEipConntrackEntries = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "vpc_nat_gw_eip_conntrack_entries",
+			Help: "The num of conntrack entries of the eip in vpc nat gateway pod.",
+		},
+		[]string{
+			"vpc_nat_gw",
+			"pod",
+			"eip",
+		})
+
+	metricVpcNatGwEipConntrackLimitDrops = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "vpc_nat_gw_eip_conntrack_limit_drops",
+			Help: "The num of packets dropped by the connection limit of the eip in vpc nat gateway pod.",
+		},
+		[]string{
+			"vpc_nat_gw",
+			"pod",
+			"eip",
+		})
 )
 
 func registerMetrics() {
 	prometheus.MustRegister(metricSubnetAvailableIPs)
 	prometheus.MustRegister(metricSubnetUsedIPs)
+	prometheus.MustRegister(metricVpcNatGwRulePackets)
+	prometheus.MustRegister(metricVpcNatGwRuleBytes)
+	prometheus.MustRegister(metricVpcNatGwRuleDrifts)
+	prometheus.MustRegister(metricVpcNatGwConntrackEntries)
+	prometheus.MustRegister(metricVpcNatGwConntrackMax)
+	prometheus.MustRegister(metricVpcNatGwConntrackDrops)
+	prometheus.MustRegister(metricVpcNatGwConntrackLimitDrops)
+	prometheus.MustRegister(metricVpcNatGwEipConntrackEntries)
+	prometheus.MustRegister(metricVpcNatGwEipConntrackLimitDrops)
 }
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net"
 	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/ovn-org/libovsdb/ovsdb"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -56,6 +58,12 @@ func (c *Controller) enqueueUpdateOvnDnatRule(oldObj, newObj interface{}) {
 		return
 	}
 	oldDnat := oldObj.(*kubeovnv1.OvnDnatRule)
+	if oldDnat.Spec.OvnEip == "" && newDnat.Spec.OvnEip != "" {
+		// eip allocated from the eip pool
+		klog.Infof("enqueue add ovn dnat %s", key)
+		c.addOvnDnatRuleQueue.Add(key)
+		return
+	}
 	if oldDnat.Spec.OvnEip != newDnat.Spec.OvnEip {
 		c.resetOvnEipQueue.Add(oldDnat.Spec.OvnEip)
 	}
@@ -63,6 +71,8 @@ func (c *Controller) enqueueUpdateOvnDnatRule(oldObj, newObj interface{}) {
 	if oldDnat.Spec.OvnEip != newDnat.Spec.OvnEip ||
 		oldDnat.Spec.Protocol != newDnat.Spec.Protocol ||
 		oldDnat.Spec.IPName != newDnat.Spec.IPName ||
+		oldDnat.Spec.V4Ip != newDnat.Spec.V4Ip ||
+		oldDnat.Spec.V6Ip != newDnat.Spec.V6Ip ||
 		oldDnat.Spec.InternalPort != newDnat.Spec.InternalPort ||
 		oldDnat.Spec.ExternalPort != newDnat.Spec.ExternalPort {
 		klog.Infof("enqueue update dnat %s", key)
@@ -186,26 +196,69 @@ func (c *Controller) processNextDeleteOvnDnatRuleWorkItem() bool {
 	return true
 }
 
-func (c *Controller) isOvnDnatDuplicated(eipName, dnatName, externalPort string) error {
-	// check if eip:external port already used
-	dnats, err := c.ovnDnatRulesLister.List(labels.SelectorFromSet(labels.Set{
-		util.VpcDnatEPortLabel: externalPort,
-	}))
+func (c *Controller) isOvnDnatDuplicated(eipName, dnatName, protocol, externalPort string) error {
+	// check if the external ports of eip already used
+	ports, err := util.ParsePortRanges(externalPort)
 	if err != nil {
-		if !k8serrors.IsNotFound(err) {
-			klog.Error(err)
+		return fmt.Errorf("failed to create dnat %s, %w", dnatName, err)
+	}
+	dnats, err := c.ovnDnatRulesLister.List(labels.Everything())
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	for _, d := range dnats {
+		if d.Name == dnatName || d.Spec.OvnEip != eipName || !strings.EqualFold(d.Spec.Protocol, protocol) {
+			continue
+		}
+		used, err := util.ParsePortRanges(d.Spec.ExternalPort)
+		if err != nil {
+			continue
+		}
+		if r := util.PortRangesOverlap(ports, used); r != nil {
+			err = fmt.Errorf("failed to create dnat %s, duplicate, same eip %s, external port '%s' is using by dnat %s", dnatName, eipName, r, d.Name)
 			return err
 		}
 	}
-	if len(dnats) != 0 {
-		for _, d := range dnats {
-			if d.Name != dnatName && d.Spec.OvnEip == eipName {
-				err = fmt.Errorf("failed to create dnat %s, duplicate, same eip %s, same external port '%s' is using by dnat %s", dnatName, eipName, externalPort, d.Name)
-				return err
+	return nil
+}
+
+// getOvnDnatInternalIPs returns the internal ips and the vpc of the dnat
+func (c *Controller) getOvnDnatInternalIPs(dnat *kubeovnv1.OvnDnatRule) (string, string, string, error) {
+	var internalV4Ip, internalV6Ip, subnetName, vpcName string
+	vpcName = dnat.Spec.Vpc
+	internalV4Ip, internalV6Ip = dnat.Spec.V4Ip, dnat.Spec.V6Ip
+	if internalV4Ip == "" && internalV6Ip == "" && dnat.Spec.IPName != "" {
+		if dnat.Spec.IPType == util.Vip {
+			internalVip, err := c.virtualIpsLister.Get(dnat.Spec.IPName)
+			if err != nil {
+				klog.Errorf("failed to get vip %s, %v", dnat.Spec.IPName, err)
+				return "", "", "", err
 			}
+			internalV4Ip, internalV6Ip = internalVip.Status.V4ip, internalVip.Status.V6ip
+			subnetName = internalVip.Spec.Subnet
+		} else {
+			internalIP, err := c.ipsLister.Get(dnat.Spec.IPName)
+			if err != nil {
+				klog.Errorf("failed to get ip %s, %v", dnat.Spec.IPName, err)
+				return "", "", "", err
+			}
+			internalV4Ip, internalV6Ip = internalIP.Spec.V4IPAddress, internalIP.Spec.V6IPAddress
+			subnetName = internalIP.Spec.Subnet
+		}
+		subnet, err := c.subnetsLister.Get(subnetName)
+		if err != nil {
+			klog.Errorf("failed to get vpc subnet %s, %v", subnetName, err)
+			return "", "", "", err
 		}
+		vpcName = subnet.Spec.Vpc
 	}
-	return nil
+	if vpcName == "" {
+		err := fmt.Errorf("failed to create dnat %s, no vpc", dnat.Name)
+		klog.Error(err)
+		return "", "", "", err
+	}
+	return internalV4Ip, internalV6Ip, vpcName, nil
 }
 
 func (c *Controller) handleAddOvnDnatRule(key string) error {
@@ -218,13 +271,16 @@ func (c *Controller) handleAddOvnDnatRule(key string) error {
 		return err
 	}
 
-	if cachedDnat.Status.Ready && cachedDnat.Status.V4Ip != "" {
+	if cachedDnat.Status.Ready && (cachedDnat.Status.V4Ip != "" || cachedDnat.Status.V6Ip != "") {
 		// already ok
 		return nil
 	}
 	klog.Infof("handle add dnat %s", key)
 	// check eip
 	eipName := cachedDnat.Spec.OvnEip
+	if eipName == "" && cachedDnat.Spec.EipPool != "" {
+		return c.allocateOvnNatRuleEip(util.DnatUsingEip, ovnDnatRuleKind, cachedDnat, cachedDnat.Spec.EipPool)
+	}
 	if eipName == "" {
 		err := fmt.Errorf("failed to create dnat %s, should set eip", cachedDnat.Name)
 		klog.Error(err)
@@ -241,63 +297,37 @@ func (c *Controller) handleAddOvnDnatRule(key string) error {
 		klog.Error(err)
 		return err
 	}
-	if cachedEip.Status.V4Ip == "" {
-		err := fmt.Errorf("failed to create v4 dnat %s, eip %s has no v4 ip", cachedDnat.Name, eipName)
-		klog.Error(err)
-		return err
-	}
-	if err := c.isOvnDnatDuplicated(eipName, key, cachedDnat.Spec.ExternalPort); err != nil {
+	if err := c.isOvnDnatDuplicated(eipName, key, cachedDnat.Spec.Protocol, cachedDnat.Spec.ExternalPort); err != nil {
 		klog.Errorf("failed to create dnat %s, %v", cachedDnat.Name, err)
 		return err
 	}
 
 	// get dnat external ip, internal ip, vpc name
-	var internalV4Ip, subnetName, vpcName string
-	if cachedDnat.Spec.Vpc != "" {
-		vpcName = cachedDnat.Spec.Vpc
+	internalV4Ip, internalV6Ip, vpcName, err := c.getOvnDnatInternalIPs(cachedDnat)
+	if err != nil {
+		return err
 	}
-	if cachedDnat.Spec.V4Ip != "" {
-		internalV4Ip = cachedDnat.Spec.V4Ip
+	v4Eip, v4Ip, v6Eip, v6Ip, err := getOvnNatIPPairs(key, cachedEip, internalV4Ip, internalV6Ip)
+	if err != nil {
+		return err
 	}
-	if internalV4Ip == "" && cachedDnat.Spec.IPName != "" {
-		if cachedDnat.Spec.IPType == util.Vip {
-			internalVip, err := c.virtualIpsLister.Get(cachedDnat.Spec.IPName)
-			if err != nil {
-				klog.Errorf("failed to get vip %s, %v", cachedDnat.Spec.IPName, err)
-				return err
-			}
-			internalV4Ip = internalVip.Status.V4ip
-			subnetName = internalVip.Spec.Subnet
-		} else {
-			internalIP, err := c.ipsLister.Get(cachedDnat.Spec.IPName)
-			if err != nil {
-				klog.Errorf("failed to get ip %s, %v", cachedDnat.Spec.IPName, err)
-				return err
-			}
-			internalV4Ip = internalIP.Spec.V4IPAddress
-			subnetName = internalIP.Spec.Subnet
-		}
-		subnet, err := c.subnetsLister.Get(subnetName)
-		if err != nil {
-			klog.Errorf("failed to get vpc subnet %s, %v", subnetName, err)
+
+	if v4Ip != "" {
+		if err = c.AddDnatRule(vpcName, cachedDnat.Name, v4Eip, v4Ip,
+			cachedDnat.Spec.ExternalPort, cachedDnat.Spec.InternalPort, cachedDnat.Spec.Protocol); err != nil {
+			klog.Errorf("failed to create v4 dnat, %v", err)
 			return err
 		}
-		vpcName = subnet.Spec.Vpc
-	}
-	if internalV4Ip == "" {
-		err := fmt.Errorf("failed to create v4 dnat %s, no internal v4 ip", cachedDnat.Name)
-		klog.Error(err)
-		return err
 	}
-	if vpcName == "" {
-		err := fmt.Errorf("failed to create v4 dnat %s, no vpc", cachedDnat.Name)
-		klog.Error(err)
-		return err
+	if v6Ip != "" {
+		if err = c.AddDnatRule(vpcName, cachedDnat.Name, v6Eip, v6Ip,
+			cachedDnat.Spec.ExternalPort, cachedDnat.Spec.InternalPort, cachedDnat.Spec.Protocol); err != nil {
+			klog.Errorf("failed to create v6 dnat, %v", err)
+			return err
+		}
 	}
-
-	if err = c.AddDnatRule(vpcName, cachedDnat.Name, cachedEip.Status.V4Ip, internalV4Ip,
-		cachedDnat.Spec.ExternalPort, cachedDnat.Spec.InternalPort, cachedDnat.Spec.Protocol); err != nil {
-		klog.Errorf("failed to create v4 dnat, %v", err)
+	if err = c.updateOvnNatHairpin(vpcName, cachedDnat.Name, cachedEip.Spec.Hairpin); err != nil {
+		klog.Errorf("failed to update hairpin of dnat %s, %v", key, err)
 		return err
 	}
 
@@ -317,8 +347,7 @@ func (c *Controller) handleAddOvnDnatRule(key string) error {
 		return err
 	}
 
-	if err = c.patchOvnDnatStatus(key, vpcName, cachedEip.Status.V4Ip,
-		internalV4Ip, true); err != nil {
+	if err = c.patchOvnDnatStatus(key, cachedEip, vpcName, v4Eip, v4Ip, v6Eip, v6Ip, true); err != nil {
 		klog.Errorf("failed to patch status for dnat %s, %v", key, err)
 		return err
 	}
@@ -331,6 +360,27 @@ func (c *Controller) handleAddOvnDnatRule(key string) error {
 	return nil
 }
 
+// delOvnDnatRules deletes the dnat rules of both ip families recorded in the status
+func (c *Controller) delOvnDnatRules(dnat *kubeovnv1.OvnDnatRule) error {
+	if dnat.Status.Vpc == "" || dnat.Status.ExternalPort == "" {
+		return nil
+	}
+	for _, eip := range []string{dnat.Status.V4Eip, dnat.Status.V6Eip} {
+		if eip == "" {
+			continue
+		}
+		if err := c.DelDnatRule(dnat.Status.Vpc, dnat.Name, eip, dnat.Status.ExternalPort); err != nil {
+			klog.Errorf("failed to delete dnat %s, %v", dnat.Name, err)
+			return err
+		}
+	}
+	if err := c.updateOvnNatHairpin(dnat.Status.Vpc, dnat.Name, false); err != nil {
+		klog.Errorf("failed to delete hairpin of dnat %s, %v", dnat.Name, err)
+		return err
+	}
+	return nil
+}
+
 func (c *Controller) handleDelOvnDnatRule(key string) error {
 	klog.Infof("handle delete ovn dnat %s", key)
 	cachedDnat, err := c.ovnDnatRulesLister.Get(key)
@@ -341,12 +391,8 @@ func (c *Controller) handleDelOvnDnatRule(key string) error {
 		klog.Error(err)
 		return err
 	}
-	if cachedDnat.Status.Vpc != "" && cachedDnat.Status.V4Eip != "" && cachedDnat.Status.ExternalPort != "" {
-		if err = c.DelDnatRule(cachedDnat.Status.Vpc, cachedDnat.Name,
-			cachedDnat.Status.V4Eip, cachedDnat.Status.ExternalPort); err != nil {
-			klog.Errorf("failed to delete dnat %s, %v", key, err)
-			return err
-		}
+	if err = c.delOvnDnatRules(cachedDnat); err != nil {
+		return err
 	}
 	if err = c.handleDelOvnDnatFinalizer(cachedDnat, util.ControllerName); err != nil {
 		klog.Errorf("failed to remove finalizer for ovn dnat %s, %v", cachedDnat.Name, err)
@@ -387,71 +433,45 @@ func (c *Controller) handleUpdateOvnDnatRule(key string) error {
 		klog.Error(err)
 		return err
 	}
-	if cachedEip.Status.V4Ip == "" {
-		err := fmt.Errorf("failed to create v4 dnat %s, eip %s has no v4 ip", cachedDnat.Name, eipName)
-		klog.Error(err)
-		return err
-	}
-	if err := c.isOvnDnatDuplicated(eipName, key, cachedDnat.Spec.ExternalPort); err != nil {
+	if err := c.isOvnDnatDuplicated(eipName, key, cachedDnat.Spec.Protocol, cachedDnat.Spec.ExternalPort); err != nil {
 		klog.Errorf("failed to create dnat %s, %v", cachedDnat.Name, err)
 		return err
 	}
 
 	// get dnat external ip, internal ip, vpc name
-	var internalV4Ip, subnetName, vpcName string
-	if cachedDnat.Spec.Vpc != "" {
-		vpcName = cachedDnat.Spec.Vpc
-	}
-	if cachedDnat.Spec.V4Ip != "" {
-		internalV4Ip = cachedDnat.Spec.V4Ip
-	}
-	if internalV4Ip == "" && cachedDnat.Spec.IPName != "" {
-		if cachedDnat.Spec.IPType == util.Vip {
-			internalVip, err := c.virtualIpsLister.Get(cachedDnat.Spec.IPName)
-			if err != nil {
-				klog.Errorf("failed to get vip %s, %v", cachedDnat.Spec.IPName, err)
-				return err
-			}
-			internalV4Ip = internalVip.Status.V4ip
-			subnetName = internalVip.Spec.Subnet
-		} else {
-			internalIP, err := c.ipsLister.Get(cachedDnat.Spec.IPName)
-			if err != nil {
-				klog.Errorf("failed to get ip %s, %v", cachedDnat.Spec.IPName, err)
-				return err
-			}
-			internalV4Ip = internalIP.Spec.V4IPAddress
-			subnetName = internalIP.Spec.Subnet
-		}
-		subnet, err := c.subnetsLister.Get(subnetName)
-		if err != nil {
-			klog.Errorf("failed to get vpc subnet %s, %v", subnetName, err)
-			return err
-		}
-		vpcName = subnet.Spec.Vpc
-	}
-	if internalV4Ip == "" {
-		err := fmt.Errorf("failed to create v4 dnat %s, no internal v4 ip", cachedDnat.Name)
-		klog.Error(err)
+	internalV4Ip, internalV6Ip, vpcName, err := c.getOvnDnatInternalIPs(cachedDnat)
+	if err != nil {
 		return err
 	}
-	if vpcName == "" {
-		err := fmt.Errorf("failed to create v4 dnat %s, no vpc", cachedDnat.Name)
-		klog.Error(err)
+	v4Eip, v4Ip, v6Eip, v6Ip, err := getOvnNatIPPairs(key, cachedEip, internalV4Ip, internalV6Ip)
+	if err != nil {
 		return err
 	}
 
 	dnat := cachedDnat.DeepCopy()
 	if dnat.Status.Ready {
-		klog.Infof("dnat change ip, old ip '%s', new ip %s", dnat.Status.V4Ip, cachedEip.Status.V4Ip)
-		if err = c.DelDnatRule(dnat.Status.Vpc, dnat.Name, dnat.Status.V4Eip, dnat.Status.ExternalPort); err != nil {
-			klog.Errorf("failed to delete dnat, %v", err)
+		klog.Infof("dnat change ip, old ip '%s', new ip %s", util.GetStringIP(dnat.Status.V4Ip, dnat.Status.V6Ip),
+			util.GetStringIP(cachedEip.Status.V4Ip, cachedEip.Status.V6Ip))
+		if err = c.delOvnDnatRules(dnat); err != nil {
 			return err
 		}
 
-		if err = c.AddDnatRule(vpcName, dnat.Name, cachedEip.Status.V4Ip, internalV4Ip,
-			dnat.Spec.ExternalPort, dnat.Spec.InternalPort, dnat.Spec.Protocol); err != nil {
-			klog.Errorf("failed to create dnat, %v", err)
+		if v4Ip != "" {
+			if err = c.AddDnatRule(vpcName, dnat.Name, v4Eip, v4Ip,
+				dnat.Spec.ExternalPort, dnat.Spec.InternalPort, dnat.Spec.Protocol); err != nil {
+				klog.Errorf("failed to create v4 dnat, %v", err)
+				return err
+			}
+		}
+		if v6Ip != "" {
+			if err = c.AddDnatRule(vpcName, dnat.Name, v6Eip, v6Ip,
+				dnat.Spec.ExternalPort, dnat.Spec.InternalPort, dnat.Spec.Protocol); err != nil {
+				klog.Errorf("failed to create v6 dnat, %v", err)
+				return err
+			}
+		}
+		if err = c.updateOvnNatHairpin(vpcName, dnat.Name, cachedEip.Spec.Hairpin); err != nil {
+			klog.Errorf("failed to update hairpin of dnat %s, %v", key, err)
 			return err
 		}
 
@@ -465,7 +485,7 @@ func (c *Controller) handleUpdateOvnDnatRule(key string) error {
 			return err
 		}
 
-		if err = c.patchOvnDnatStatus(key, vpcName, cachedEip.Status.V4Ip, internalV4Ip, true); err != nil {
+		if err = c.patchOvnDnatStatus(key, cachedEip, vpcName, v4Eip, v4Ip, v6Eip, v6Ip, true); err != nil {
 			klog.Errorf("failed to patch status for dnat '%s', %v", key, err)
 			return err
 		}
@@ -519,7 +539,7 @@ func (c *Controller) patchOvnDnatAnnotations(key, eipName string) error {
 	return nil
 }
 
-func (c *Controller) patchOvnDnatStatus(key, vpcName, v4Eip, podIP string, ready bool) error {
+func (c *Controller) patchOvnDnatStatus(key string, eip *kubeovnv1.OvnEip, vpcName, v4Eip, v4Ip, v6Eip, v6Ip string, ready bool) error {
 	var (
 		oriDnat, dnat *kubeovnv1.OvnDnatRule
 		err           error
@@ -533,23 +553,12 @@ func (c *Controller) patchOvnDnatStatus(key, vpcName, v4Eip, podIP string, ready
 	}
 	dnat = oriDnat.DeepCopy()
 	var (
-		needUpdateLabel = false
+		needUpdateLabel bool
 		changed         bool
 		op              string
 	)
 
-	if len(dnat.Labels) == 0 {
-		op = "add"
-		needUpdateLabel = true
-		dnat.Labels = map[string]string{
-			util.EipV4IpLabel: v4Eip,
-		}
-	} else if dnat.Labels[util.EipV4IpLabel] != v4Eip {
-		op = "replace"
-		needUpdateLabel = true
-		dnat.Labels[util.EipV4IpLabel] = v4Eip
-	}
-	if needUpdateLabel {
+	if dnat.Labels, op, needUpdateLabel = patchOvnNatLabels(dnat.Labels, eip); needUpdateLabel {
 		patchPayloadTemplate := `[{ "op": "%s", "path": "/metadata/labels", "value": %s }]`
 		raw, _ := json.Marshal(dnat.Labels)
 		patchPayload := fmt.Sprintf(patchPayloadTemplate, op, raw)
@@ -570,13 +579,13 @@ func (c *Controller) patchOvnDnatStatus(key, vpcName, v4Eip, podIP string, ready
 		changed = true
 	}
 
-	if v4Eip != "" && dnat.Status.V4Eip != v4Eip {
-		dnat.Status.V4Eip = v4Eip
+	if ready && (dnat.Status.V4Eip != v4Eip || dnat.Status.V4Ip != v4Ip) {
+		dnat.Status.V4Eip, dnat.Status.V4Ip = v4Eip, v4Ip
 		changed = true
 	}
 
-	if podIP != "" && dnat.Status.V4Ip != podIP {
-		dnat.Status.V4Ip = podIP
+	if ready && (dnat.Status.V6Eip != v6Eip || dnat.Status.V6Ip != v6Ip) {
+		dnat.Status.V6Eip, dnat.Status.V6Ip = v6Eip, v6Ip
 		changed = true
 	}
 
@@ -616,19 +625,26 @@ func (c *Controller) patchOvnDnatStatus(key, vpcName, v4Eip, podIP string, ready
 }
 
 func (c *Controller) AddDnatRule(vpcName, dnatName, externalIP, internalIP, externalPort, internalPort, protocol string) error {
-	var (
-		externalEndpoint = net.JoinHostPort(externalIP, externalPort)
-		internalEndpoint = net.JoinHostPort(internalIP, internalPort)
-		err              error
-	)
+	mappings, err := util.ParsePortMappings(externalPort, internalPort)
+	if err != nil {
+		klog.Errorf("invalid ports of dnat %s: %v", dnatName, err)
+		return err
+	}
 
 	if err = c.OVNNbClient.CreateLoadBalancer(dnatName, protocol, ""); err != nil {
 		klog.Errorf("create loadBalancer %s: %v", dnatName, err)
 		return err
 	}
 
-	if err = c.OVNNbClient.LoadBalancerAddVip(dnatName, externalEndpoint, internalEndpoint); err != nil {
-		klog.Errorf("add vip %s with backends %s to LB %s: %v", externalEndpoint, internalEndpoint, dnatName, err)
+	// load balancer vips do not support port ranges, map the ports one by one in a single transaction
+	vips := make(map[string]string, util.PortMappingsSize(mappings))
+	for _, mapping := range mappings {
+		for _, port := range mapping.Ports() {
+			vips[net.JoinHostPort(externalIP, port.External.String())] = net.JoinHostPort(internalIP, port.Internal.String())
+		}
+	}
+	if err = c.OVNNbClient.LoadBalancerAddVips(dnatName, vips); err != nil {
+		klog.Errorf("add vips of ports %s to LB %s: %v", externalPort, dnatName, err)
 		return err
 	}
 
@@ -640,15 +656,20 @@ func (c *Controller) AddDnatRule(vpcName, dnatName, externalIP, internalIP, exte
 }
 
 func (c *Controller) DelDnatRule(vpcName, dnatName, externalIP, externalPort string) error {
-	var (
-		ignoreHealthCheck = true
-		externalEndpoint  string
-		err               error
-	)
-	externalEndpoint = net.JoinHostPort(externalIP, externalPort)
+	ports, err := util.ParsePortRanges(externalPort)
+	if err != nil {
+		klog.Errorf("invalid external port of dnat %s: %v", dnatName, err)
+		return err
+	}
 
-	if err = c.OVNNbClient.LoadBalancerDeleteVip(dnatName, externalEndpoint, ignoreHealthCheck); err != nil {
-		klog.Errorf("delete loadBalancer vips %s: %v", externalEndpoint, err)
+	var vips []string
+	for _, r := range ports {
+		for port := r.Start; port <= r.End; port++ {
+			vips = append(vips, net.JoinHostPort(externalIP, strconv.Itoa(port)))
+		}
+	}
+	if err = c.OVNNbClient.LoadBalancerDeleteVips(dnatName, vips); err != nil {
+		klog.Errorf("delete vips of ports %s from LB %s: %v", externalPort, dnatName, err)
 		return err
 	}
 
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AddDnatRule(t *testing.T) {
+	t.Parallel()
+
+	fakeController := newFakeController(t)
+	ctrl := fakeController.fakeController
+	mockOvnClient := fakeController.mockOvnClient
+
+	// the vips of all the ports are added in one call
+	mockOvnClient.EXPECT().CreateLoadBalancer("dnat1", "tcp", "").Return(nil)
+	mockOvnClient.EXPECT().LoadBalancerAddVips("dnat1", map[string]string{
+		"172.18.0.10:80":   "10.0.1.5:8080",
+		"172.18.0.10:8000": "10.0.1.5:9000",
+		"172.18.0.10:8001": "10.0.1.5:9001",
+		"172.18.0.10:8002": "10.0.1.5:9002",
+	}).Return(nil)
+	mockOvnClient.EXPECT().LogicalRouterUpdateLoadBalancers("vpc1", ovsdb.MutateOperationInsert, "dnat1").Return(nil)
+	require.NoError(t, ctrl.AddDnatRule("vpc1", "dnat1", "172.18.0.10", "10.0.1.5", "80,8000-8002", "8080,9000-9002", "tcp"))
+
+	require.Error(t, ctrl.AddDnatRule("vpc1", "dnat1", "172.18.0.10", "10.0.1.5", "8000-8002", "9000-9001", "tcp"))
+}
+
+func Test_DelDnatRule(t *testing.T) {
+	t.Parallel()
+
+	fakeController := newFakeController(t)
+	ctrl := fakeController.fakeController
+	mockOvnClient := fakeController.mockOvnClient
+
+	mockOvnClient.EXPECT().LoadBalancerDeleteVips("dnat1", []string{"[fc00::10]:80", "[fc00::10]:8000", "[fc00::10]:8001"}).Return(nil)
+	mockOvnClient.EXPECT().LogicalRouterUpdateLoadBalancers("vpc1", ovsdb.MutateOperationDelete, "dnat1").Return(nil)
+	require.NoError(t, ctrl.DelDnatRule("vpc1", "dnat1", "fc00::10", "80,8000-8001"))
+}
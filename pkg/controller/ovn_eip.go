@@ -57,7 +57,8 @@ func (c *Controller) enqueueUpdateOvnEip(oldObj, newObj interface{}) {
 		return
 	}
 	if oldEip.Spec.V4Ip != newEip.Spec.V4Ip ||
-		oldEip.Spec.V6Ip != newEip.Spec.V6Ip {
+		oldEip.Spec.V6Ip != newEip.Spec.V6Ip ||
+		oldEip.Spec.Hairpin != newEip.Spec.Hairpin {
 		klog.Infof("enqueue update ovn eip %s", key)
 		c.updateOvnEipQueue.Add(key)
 	}
@@ -232,11 +233,11 @@ func (c *Controller) handleAddOvnEip(key string) error {
 		return err
 	}
 	portName := cachedEip.Name
-	if cachedEip.Spec.V4Ip != "" {
-		v4ip, v6ip, mac, err = c.acquireStaticIPAddress(subnet.Name, cachedEip.Name, portName, cachedEip.Spec.V4Ip)
+	if cachedEip.Spec.V4Ip != "" || cachedEip.Spec.V6Ip != "" {
+		v4ip, v6ip, mac, err = c.acquireStaticIPAddress(subnet.Name, cachedEip.Name, portName, util.GetStringIP(cachedEip.Spec.V4Ip, cachedEip.Spec.V6Ip))
 	} else {
 		// random allocate
-		v4ip, v6ip, mac, err = c.acquireIPAddress(subnet.Name, cachedEip.Name, portName)
+		v4ip, v6ip, mac, err = c.acquireIPAddressFromPool(subnet.Name, cachedEip.Spec.IPPool, cachedEip.Name, portName)
 	}
 	if err != nil {
 		klog.Errorf("failed to acquire ip address, %v", err)
@@ -296,6 +297,28 @@ func (c *Controller) handleUpdateOvnEip(key string) error {
 			return err
 		}
 	}
+
+	// resync the hairpin of the fips and dnats using the eip
+	fips, err := c.ovnFipsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list ovn fips, %v", err)
+		return err
+	}
+	for _, fip := range fips {
+		if fip.Spec.OvnEip == key && fip.Status.Ready {
+			c.updateOvnFipQueue.Add(fip.Name)
+		}
+	}
+	dnats, err := c.ovnDnatRulesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list ovn dnats, %v", err)
+		return err
+	}
+	for _, dnat := range dnats {
+		if dnat.Spec.OvnEip == key && dnat.Status.Ready {
+			c.updateOvnDnatRuleQueue.Add(dnat.Name)
+		}
+	}
 	return nil
 }
 
@@ -496,7 +519,7 @@ func (c *Controller) patchOvnEipStatus(key string, ready bool) error {
 		ovnEip.Status.Type = ovnEip.Spec.Type
 		changed = true
 	}
-	nat, err := c.getOvnEipNat(ovnEip.Spec.V4Ip)
+	nat, err := c.getOvnEipNat(ovnEip)
 	if err != nil {
 		err := fmt.Errorf("failed to get ovn eip nat")
 		klog.Error(err)
@@ -613,7 +636,7 @@ func (c *Controller) handleDelOvnEipFinalizer(cachedEip *kubeovnv1.OvnEip, final
 		return nil
 	}
 	var err error
-	nat, err := c.getOvnEipNat(cachedEip.Spec.V4Ip)
+	nat, err := c.getOvnEipNat(cachedEip)
 	if err != nil {
 		err := fmt.Errorf("failed to get ovn eip nat")
 		klog.Error(err)
@@ -642,9 +665,18 @@ func (c *Controller) handleDelOvnEipFinalizer(cachedEip *kubeovnv1.OvnEip, final
 	return nil
 }
 
-func (c *Controller) getOvnEipNat(eipV4IP string) (string, error) {
+// ovnEipNatSelector selects the nat rules using the eip, the rules are labeled with the v4 ip of the eip,
+// and the eip name is used for the ipv6 only eip since an ipv6 address is not a valid label value
+func ovnEipNatSelector(eip *kubeovnv1.OvnEip) labels.Selector {
+	if eip.Spec.V4Ip != "" {
+		return labels.SelectorFromSet(labels.Set{util.EipV4IpLabel: eip.Spec.V4Ip})
+	}
+	return labels.SelectorFromSet(labels.Set{util.OvnEipNameLabel: eip.Name})
+}
+
+func (c *Controller) getOvnEipNat(eip *kubeovnv1.OvnEip) (string, error) {
 	nats := make([]string, 0, 3)
-	selector := labels.SelectorFromSet(labels.Set{util.EipV4IpLabel: eipV4IP})
+	selector := ovnEipNatSelector(eip)
 	dnats, err := c.ovnDnatRulesLister.List(selector)
 	if err != nil {
 		klog.Errorf("failed to get ovn dnats, %v", err)
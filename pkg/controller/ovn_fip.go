@@ -9,7 +9,6 @@ import (
 
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
@@ -50,6 +49,12 @@ func (c *Controller) enqueueUpdateOvnFip(oldObj, newObj interface{}) {
 		return
 	}
 	oldFip := oldObj.(*kubeovnv1.OvnFip)
+	if oldFip.Spec.OvnEip == "" && newFip.Spec.OvnEip != "" {
+		// eip allocated from the eip pool
+		klog.Infof("enqueue add ovn fip %s", key)
+		c.addOvnFipQueue.Add(key)
+		return
+	}
 	if oldFip.Spec.OvnEip != newFip.Spec.OvnEip {
 		// enqueue to reset eip to be clean
 		klog.Infof("enqueue reset old ovn eip %s", oldFip.Spec.OvnEip)
@@ -174,17 +179,16 @@ func (c *Controller) processNextDeleteOvnFipWorkItem() bool {
 	return true
 }
 
-func (c *Controller) isOvnFipDuplicated(fipName, eipV4IP string) error {
+func (c *Controller) isOvnFipDuplicated(fipName string, eip *kubeovnv1.OvnEip) error {
 	// check if has another fip using this eip already
-	selector := labels.SelectorFromSet(labels.Set{util.EipV4IpLabel: eipV4IP})
-	usingFips, err := c.ovnFipsLister.List(selector)
+	usingFips, err := c.ovnFipsLister.List(ovnEipNatSelector(eip))
 	if err != nil {
 		klog.Errorf("failed to get fips, %v", err)
 		return err
 	}
 	for _, uf := range usingFips {
 		if uf.Name != fipName {
-			err = fmt.Errorf("%s is using by the other fip %s", eipV4IP, uf.Name)
+			err = fmt.Errorf("eip %s is using by the other fip %s", eip.Name, uf.Name)
 			klog.Error(err)
 			return err
 		}
@@ -192,6 +196,66 @@ func (c *Controller) isOvnFipDuplicated(fipName, eipV4IP string) error {
 	return nil
 }
 
+// getOvnFipInternalIPs returns the internal ips, the mac and the vpc of the fip
+func (c *Controller) getOvnFipInternalIPs(fip *kubeovnv1.OvnFip) (string, string, string, string, error) {
+	var mac, internalV4Ip, internalV6Ip, subnetName, vpcName string
+	vpcName = fip.Spec.Vpc
+	internalV4Ip, internalV6Ip = fip.Spec.V4Ip, fip.Spec.V6Ip
+	if internalV4Ip == "" && internalV6Ip == "" && fip.Spec.IPName != "" {
+		if fip.Spec.IPType == util.Vip {
+			internalVip, err := c.virtualIpsLister.Get(fip.Spec.IPName)
+			if err != nil {
+				klog.Errorf("failed to get vip %s, %v", fip.Spec.IPName, err)
+				return "", "", "", "", err
+			}
+			internalV4Ip, internalV6Ip = internalVip.Status.V4ip, internalVip.Status.V6ip
+			subnetName = internalVip.Spec.Subnet
+			// though vip lsp has its mac, vip always use its parent lsp nic mac
+			// and vip could float to different parent lsp nic
+			// all vip its parent lsp acl should allow the vip ip
+		} else {
+			internalIP, err := c.ipsLister.Get(fip.Spec.IPName)
+			if err != nil {
+				klog.Errorf("failed to get ip %s, %v", fip.Spec.IPName, err)
+				return "", "", "", "", err
+			}
+			internalV4Ip, internalV6Ip = internalIP.Spec.V4IPAddress, internalIP.Spec.V6IPAddress
+			subnetName = internalIP.Spec.Subnet
+			mac = internalIP.Spec.MacAddress
+			// mac is necessary while using distributed router fip, fip use lsp its mac
+			// centralized router fip not need lsp mac, fip use lrp mac
+		}
+		subnet, err := c.subnetsLister.Get(subnetName)
+		if err != nil {
+			klog.Errorf("failed to get vpc subnet %s, %v", subnetName, err)
+			return "", "", "", "", err
+		}
+		vpcName = subnet.Spec.Vpc
+	}
+	if vpcName == "" {
+		err := fmt.Errorf("failed to create fip %s, no vpc", fip.Name)
+		klog.Error(err)
+		return "", "", "", "", err
+	}
+	return internalV4Ip, internalV6Ip, mac, vpcName, nil
+}
+
+// getOvnNatIPPairs returns the eip and the internal ip of each ip family supported by both of them
+func getOvnNatIPPairs(natName string, eip *kubeovnv1.OvnEip, internalV4Ip, internalV6Ip string) (v4Eip, v4Ip, v6Eip, v6Ip string, err error) {
+	if eip.Status.V4Ip != "" && internalV4Ip != "" {
+		v4Eip, v4Ip = eip.Status.V4Ip, internalV4Ip
+	}
+	if eip.Status.V6Ip != "" && internalV6Ip != "" {
+		v6Eip, v6Ip = eip.Status.V6Ip, internalV6Ip
+	}
+	if v4Ip == "" && v6Ip == "" {
+		err = fmt.Errorf("failed to create nat %s, eip %s has no ip of the same family as internal ip %s",
+			natName, eip.Name, util.GetStringIP(internalV4Ip, internalV6Ip))
+		klog.Error(err)
+	}
+	return
+}
+
 func (c *Controller) handleAddOvnFip(key string) error {
 	cachedFip, err := c.ovnFipsLister.Get(key)
 	if err != nil {
@@ -201,13 +265,16 @@ func (c *Controller) handleAddOvnFip(key string) error {
 		klog.Error(err)
 		return err
 	}
-	if cachedFip.Status.Ready && cachedFip.Status.V4Ip != "" {
+	if cachedFip.Status.Ready && (cachedFip.Status.V4Ip != "" || cachedFip.Status.V6Ip != "") {
 		// already ok
 		return nil
 	}
 	klog.Infof("handle add fip %s", key)
 	// check eip
 	eipName := cachedFip.Spec.OvnEip
+	if eipName == "" && cachedFip.Spec.EipPool != "" {
+		return c.allocateOvnNatRuleEip(util.FipUsingEip, ovnFipKind, cachedFip, cachedFip.Spec.EipPool)
+	}
 	if eipName == "" {
 		err := fmt.Errorf("failed to create fip rule, should set eip")
 		klog.Error(err)
@@ -224,75 +291,36 @@ func (c *Controller) handleAddOvnFip(key string) error {
 		klog.Error(err)
 		return err
 	}
-	if cachedEip.Status.V4Ip == "" {
-		err := fmt.Errorf("failed to create v4 fip %s, eip %s has no v4 ip", cachedFip.Name, eipName)
-		klog.Error(err)
-		return err
-	}
-	if err = c.isOvnFipDuplicated(key, cachedEip.Spec.V4Ip); err != nil {
+	if err = c.isOvnFipDuplicated(key, cachedEip); err != nil {
 		err = fmt.Errorf("failed to add fip %s, %v", key, err)
 		klog.Error(err)
 		return err
 	}
 
-	var mac, internalV4Ip, subnetName, vpcName string
-	if cachedFip.Spec.Vpc != "" {
-		vpcName = cachedFip.Spec.Vpc
+	internalV4Ip, internalV6Ip, mac, vpcName, err := c.getOvnFipInternalIPs(cachedFip)
+	if err != nil {
+		return err
 	}
-	if cachedFip.Spec.V4Ip != "" {
-		internalV4Ip = cachedFip.Spec.V4Ip
+	v4Eip, v4Ip, v6Eip, v6Ip, err := getOvnNatIPPairs(key, cachedEip, internalV4Ip, internalV6Ip)
+	if err != nil {
+		return err
 	}
-	if internalV4Ip == "" && cachedFip.Spec.IPName != "" {
-		if cachedFip.Spec.IPType == util.Vip {
-			internalVip, err := c.virtualIpsLister.Get(cachedFip.Spec.IPName)
-			if err != nil {
-				klog.Errorf("failed to get vip %s, %v", cachedFip.Spec.IPName, err)
-				return err
-			}
-			internalV4Ip = internalVip.Status.V4ip
-			subnetName = internalVip.Spec.Subnet
-			// though vip lsp has its mac, vip always use its parent lsp nic mac
-			// and vip could float to different parent lsp nic
-			// all vip its parent lsp acl should allow the vip ip
-		} else {
-			internalIP, err := c.ipsLister.Get(cachedFip.Spec.IPName)
-			if err != nil {
-				klog.Errorf("failed to get ip %s, %v", cachedFip.Spec.IPName, err)
-				return err
-			}
-			internalV4Ip = internalIP.Spec.V4IPAddress
-			subnetName = internalIP.Spec.Subnet
-			mac = internalIP.Spec.MacAddress
-			// mac is necessary while using distributed router fip, fip use lsp its mac
-			// centralized router fip not need lsp mac, fip use lrp mac
-		}
-		subnet, err := c.subnetsLister.Get(subnetName)
-		if err != nil {
-			klog.Errorf("failed to get vpc subnet %s, %v", subnetName, err)
+	// ovn add fip
+	options := map[string]string{"staleless": strconv.FormatBool(c.ExternalGatewayType == kubeovnv1.GWDistributedType)}
+	if v4Ip != "" {
+		if err = c.OVNNbClient.AddNat(vpcName, ovnnb.NATTypeDNATAndSNAT, v4Eip, v4Ip, mac, cachedFip.Spec.IPName, options); err != nil {
+			klog.Errorf("failed to create v4 fip, %v", err)
 			return err
 		}
-		vpcName = subnet.Spec.Vpc
-		if err = c.isOvnFipDuplicated(key, cachedEip.Spec.V4Ip); err != nil {
-			err = fmt.Errorf("failed to update fip %s, %v", key, err)
-			klog.Error(err)
+	}
+	if v6Ip != "" {
+		if err = c.OVNNbClient.AddNat(vpcName, ovnnb.NATTypeDNATAndSNAT, v6Eip, v6Ip, mac, cachedFip.Spec.IPName, options); err != nil {
+			klog.Errorf("failed to create v6 fip, %v", err)
 			return err
 		}
 	}
-	if internalV4Ip == "" {
-		err := fmt.Errorf("failed to create v4 fip %s, no internal v4 ip", cachedFip.Name)
-		klog.Error(err)
-		return err
-	}
-	if vpcName == "" {
-		err := fmt.Errorf("failed to create v4 fip %s, no vpc", cachedFip.Name)
-		klog.Error(err)
-		return err
-	}
-	// ovn add fip
-	options := map[string]string{"staleless": strconv.FormatBool(c.ExternalGatewayType == kubeovnv1.GWDistributedType)}
-	if err = c.OVNNbClient.AddNat(vpcName, ovnnb.NATTypeDNATAndSNAT, cachedEip.Status.V4Ip,
-		internalV4Ip, mac, cachedFip.Spec.IPName, options); err != nil {
-		klog.Errorf("failed to create v4 fip, %v", err)
+	if err = c.syncOvnFipHairpin(cachedFip.Name, vpcName, cachedEip.Spec.Hairpin, v4Eip, v4Ip, v6Eip, v6Ip); err != nil {
+		klog.Errorf("failed to sync hairpin of fip %s, %v", key, err)
 		return err
 	}
 
@@ -310,8 +338,7 @@ func (c *Controller) handleAddOvnFip(key string) error {
 		klog.Errorf("failed to update label for fip %s, %v", key, err)
 		return err
 	}
-	if err = c.patchOvnFipStatus(key, vpcName, cachedEip.Status.V4Ip,
-		internalV4Ip, true); err != nil {
+	if err = c.patchOvnFipStatus(key, cachedEip, vpcName, v4Eip, v4Ip, v6Eip, v6Ip, true); err != nil {
 		klog.Errorf("failed to patch status for fip %s, %v", key, err)
 		return err
 	}
@@ -349,83 +376,57 @@ func (c *Controller) handleUpdateOvnFip(key string) error {
 		klog.Error(err)
 		return err
 	}
-	if cachedEip.Status.V4Ip == "" {
-		err := fmt.Errorf("failed to create v4 fip %s, eip %s has no v4 ip", cachedFip.Name, eipName)
-		klog.Error(err)
-		return err
-	}
-	if err = c.isOvnFipDuplicated(key, cachedEip.Spec.V4Ip); err != nil {
-		err = fmt.Errorf("failed to add fip %s, %v", key, err)
+	if err = c.isOvnFipDuplicated(key, cachedEip); err != nil {
+		err = fmt.Errorf("failed to update fip %s, %v", key, err)
 		klog.Error(err)
 		return err
 	}
 
-	var mac, internalV4Ip, subnetName, vpcName string
-	if cachedFip.Spec.Vpc != "" {
-		vpcName = cachedFip.Spec.Vpc
-	}
-	if cachedFip.Spec.V4Ip != "" {
-		internalV4Ip = cachedFip.Spec.V4Ip
-	}
-	if internalV4Ip == "" && cachedFip.Spec.IPName != "" {
-		if cachedFip.Spec.IPType == util.Vip {
-			internalVip, err := c.virtualIpsLister.Get(cachedFip.Spec.IPName)
-			if err != nil {
-				klog.Errorf("failed to get vip %s, %v", cachedFip.Spec.IPName, err)
-				return err
-			}
-			internalV4Ip = internalVip.Status.V4ip
-			subnetName = internalVip.Spec.Subnet
-			// though vip lsp has its mac, vip always use its parent lsp nic mac
-			// and vip could float to different parent lsp nic
-			// all vip its parent lsp acl should allow the vip ip
-		} else {
-			internalIP, err := c.ipsLister.Get(cachedFip.Spec.IPName)
-			if err != nil {
-				klog.Errorf("failed to get ip %s, %v", cachedFip.Spec.IPName, err)
-				return err
-			}
-			internalV4Ip = internalIP.Spec.V4IPAddress
-			subnetName = internalIP.Spec.Subnet
-			mac = internalIP.Spec.MacAddress
-			// mac is necessary while using distributed router fip, fip use lsp its mac
-			// centralized router fip not need lsp mac, fip use lrp mac
-		}
-		subnet, err := c.subnetsLister.Get(subnetName)
-		if err != nil {
-			klog.Errorf("failed to get vpc subnet %s, %v", subnetName, err)
-			return err
-		}
-		vpcName = subnet.Spec.Vpc
-		if err = c.isOvnFipDuplicated(key, cachedEip.Spec.V4Ip); err != nil {
-			err = fmt.Errorf("failed to update fip %s, %v", key, err)
-			klog.Error(err)
-			return err
-		}
-	}
-	if internalV4Ip == "" {
-		err := fmt.Errorf("failed to create v4 fip %s, no internal v4 ip", cachedFip.Name)
-		klog.Error(err)
+	internalV4Ip, internalV6Ip, mac, vpcName, err := c.getOvnFipInternalIPs(cachedFip)
+	if err != nil {
 		return err
 	}
-	if vpcName == "" {
-		err := fmt.Errorf("failed to create v4 fip %s, no vpc", cachedFip.Name)
-		klog.Error(err)
+	v4Eip, v4Ip, v6Eip, v6Ip, err := getOvnNatIPPairs(key, cachedEip, internalV4Ip, internalV6Ip)
+	if err != nil {
 		return err
 	}
 	fip := cachedFip.DeepCopy()
 	// fip change eip
 	if c.ovnFipChangeEip(fip, cachedEip) {
-		klog.Infof("fip change ip, old ip '%s', new ip %s", fip.Status.V4Ip, cachedEip.Status.V4Ip)
-		if err = c.OVNNbClient.DeleteNat(vpcName, ovnnb.NATTypeDNATAndSNAT, fip.Status.V4Ip, internalV4Ip); err != nil {
-			klog.Errorf("failed to create fip, %v", err)
+		klog.Infof("fip change ip, old ip '%s', new ip %s", util.GetStringIP(fip.Status.V4Eip, fip.Status.V6Eip),
+			util.GetStringIP(cachedEip.Status.V4Ip, cachedEip.Status.V6Ip))
+		if fip.Status.V4Eip != "" && fip.Status.V4Ip != "" {
+			if err = c.OVNNbClient.DeleteNat(fip.Status.Vpc, ovnnb.NATTypeDNATAndSNAT, fip.Status.V4Eip, fip.Status.V4Ip); err != nil {
+				klog.Errorf("failed to delete v4 fip, %v", err)
+				return err
+			}
+		}
+		if fip.Status.V6Eip != "" && fip.Status.V6Ip != "" {
+			if err = c.OVNNbClient.DeleteNat(fip.Status.Vpc, ovnnb.NATTypeDNATAndSNAT, fip.Status.V6Eip, fip.Status.V6Ip); err != nil {
+				klog.Errorf("failed to delete v6 fip, %v", err)
+				return err
+			}
+		}
+		if err = c.syncOvnFipHairpin(fip.Name, fip.Status.Vpc, false, "", "", "", ""); err != nil {
+			klog.Errorf("failed to delete hairpin of fip %s, %v", key, err)
 			return err
 		}
 		// ovn add fip
 		options := map[string]string{"staleless": strconv.FormatBool(c.ExternalGatewayType == kubeovnv1.GWDistributedType)}
-		if err = c.OVNNbClient.AddNat(vpcName, ovnnb.NATTypeDNATAndSNAT, cachedEip.Status.V4Ip,
-			internalV4Ip, mac, cachedFip.Spec.IPName, options); err != nil {
-			klog.Errorf("failed to create fip, %v", err)
+		if v4Ip != "" {
+			if err = c.OVNNbClient.AddNat(vpcName, ovnnb.NATTypeDNATAndSNAT, v4Eip, v4Ip, mac, cachedFip.Spec.IPName, options); err != nil {
+				klog.Errorf("failed to create v4 fip, %v", err)
+				return err
+			}
+		}
+		if v6Ip != "" {
+			if err = c.OVNNbClient.AddNat(vpcName, ovnnb.NATTypeDNATAndSNAT, v6Eip, v6Ip, mac, cachedFip.Spec.IPName, options); err != nil {
+				klog.Errorf("failed to create v6 fip, %v", err)
+				return err
+			}
+		}
+		if err = c.syncOvnFipHairpin(fip.Name, vpcName, cachedEip.Spec.Hairpin, v4Eip, v4Ip, v6Eip, v6Ip); err != nil {
+			klog.Errorf("failed to sync hairpin of fip %s, %v", key, err)
 			return err
 		}
 		if err = c.natLabelAndAnnoOvnEip(eipName, fip.Name, vpcName); err != nil {
@@ -436,13 +437,19 @@ func (c *Controller) handleUpdateOvnFip(key string) error {
 			klog.Errorf("failed to update label for fip %s, %v", key, err)
 			return err
 		}
-		if err = c.patchOvnFipStatus(key, vpcName, cachedEip.Status.V4Ip,
-			internalV4Ip, true); err != nil {
+		if err = c.patchOvnFipStatus(key, cachedEip, vpcName, v4Eip, v4Ip, v6Eip, v6Ip, true); err != nil {
 			klog.Errorf("failed to patch status for fip '%s', %v", key, err)
 			return err
 		}
 		return nil
 	}
+	if fip.Status.Ready {
+		// the hairpin of the eip may be switched
+		if err = c.syncOvnFipHairpin(fip.Name, fip.Status.Vpc, cachedEip.Spec.Hairpin, v4Eip, v4Ip, v6Eip, v6Ip); err != nil {
+			klog.Errorf("failed to sync hairpin of fip %s, %v", key, err)
+			return err
+		}
+	}
 	return nil
 }
 
@@ -463,6 +470,16 @@ func (c *Controller) handleDelOvnFip(key string) error {
 			return err
 		}
 	}
+	if cachedFip.Status.Vpc != "" && cachedFip.Status.V6Eip != "" && cachedFip.Status.V6Ip != "" {
+		if err = c.OVNNbClient.DeleteNat(cachedFip.Status.Vpc, ovnnb.NATTypeDNATAndSNAT, cachedFip.Status.V6Eip, cachedFip.Status.V6Ip); err != nil {
+			klog.Errorf("failed to delete fip %s, %v", key, err)
+			return err
+		}
+	}
+	if err = c.syncOvnFipHairpin(cachedFip.Name, cachedFip.Status.Vpc, false, "", "", "", ""); err != nil {
+		klog.Errorf("failed to delete hairpin of fip %s, %v", key, err)
+		return err
+	}
 	if err = c.handleDelOvnFipFinalizer(cachedFip, util.ControllerName); err != nil {
 		klog.Errorf("failed to remove finalizer for ovn fip %s, %v", cachedFip.Name, err)
 		return err
@@ -511,7 +528,33 @@ func (c *Controller) patchOvnFipAnnotations(key, eipName string) error {
 	return nil
 }
 
-func (c *Controller) patchOvnFipStatus(key, vpcName, v4Eip, podIP string, ready bool) error {
+// patchOvnNatLabels labels the nat rule with the eip name, and the eip v4 ip if it has one,
+// the labels are used to select the nat rules by ovnEipNatSelector
+func patchOvnNatLabels(natLabels map[string]string, eip *kubeovnv1.OvnEip) (map[string]string, string, bool) {
+	if len(natLabels) == 0 {
+		natLabels = map[string]string{util.OvnEipNameLabel: eip.Name}
+		if eip.Spec.V4Ip != "" {
+			natLabels[util.EipV4IpLabel] = eip.Spec.V4Ip
+		}
+		return natLabels, "add", true
+	}
+	var changed bool
+	if natLabels[util.OvnEipNameLabel] != eip.Name {
+		natLabels[util.OvnEipNameLabel] = eip.Name
+		changed = true
+	}
+	if natLabels[util.EipV4IpLabel] != eip.Spec.V4Ip {
+		if eip.Spec.V4Ip == "" {
+			delete(natLabels, util.EipV4IpLabel)
+		} else {
+			natLabels[util.EipV4IpLabel] = eip.Spec.V4Ip
+		}
+		changed = true
+	}
+	return natLabels, "replace", changed
+}
+
+func (c *Controller) patchOvnFipStatus(key string, eip *kubeovnv1.OvnEip, vpcName, v4Eip, v4Ip, v6Eip, v6Ip string, ready bool) error {
 	oriFip, err := c.ovnFipsLister.Get(key)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
@@ -521,20 +564,9 @@ func (c *Controller) patchOvnFipStatus(key, vpcName, v4Eip, podIP string, ready
 		return err
 	}
 	fip := oriFip.DeepCopy()
-	needUpdateLabel := false
 	var op string
-	if len(fip.Labels) == 0 {
-		op = "add"
-		needUpdateLabel = true
-		fip.Labels = map[string]string{
-			util.EipV4IpLabel: v4Eip,
-		}
-	} else if fip.Labels[util.EipV4IpLabel] != v4Eip {
-		op = "replace"
-		needUpdateLabel = true
-		fip.Labels[util.EipV4IpLabel] = v4Eip
-	}
-	if needUpdateLabel {
+	var needUpdateLabel bool
+	if fip.Labels, op, needUpdateLabel = patchOvnNatLabels(fip.Labels, eip); needUpdateLabel {
 		patchPayloadTemplate := `[{ "op": "%s", "path": "/metadata/labels", "value": %s }]`
 		raw, _ := json.Marshal(fip.Labels)
 		patchPayload := fmt.Sprintf(patchPayloadTemplate, op, raw)
@@ -553,12 +585,12 @@ func (c *Controller) patchOvnFipStatus(key, vpcName, v4Eip, podIP string, ready
 		fip.Status.Vpc = vpcName
 		changed = true
 	}
-	if v4Eip != "" && fip.Status.V4Eip != v4Eip {
-		fip.Status.V4Eip = v4Eip
+	if ready && (fip.Status.V4Eip != v4Eip || fip.Status.V4Ip != v4Ip) {
+		fip.Status.V4Eip, fip.Status.V4Ip = v4Eip, v4Ip
 		changed = true
 	}
-	if podIP != "" && fip.Status.V4Ip != podIP {
-		fip.Status.V4Ip = podIP
+	if ready && (fip.Status.V6Eip != v6Eip || fip.Status.V6Ip != v6Ip) {
+		fip.Status.V6Eip, fip.Status.V6Ip = v6Eip, v6Ip
 		changed = true
 	}
 	if changed {
@@ -577,14 +609,12 @@ func (c *Controller) patchOvnFipStatus(key, vpcName, v4Eip, podIP string, ready
 }
 
 func (c *Controller) ovnFipChangeEip(fip *kubeovnv1.OvnFip, eip *kubeovnv1.OvnEip) bool {
-	if fip.Status.V4Ip == "" || eip.Status.V4Ip == "" {
+	if fip.Status.V4Eip == "" && fip.Status.V6Eip == "" {
 		// eip created but not ready
 		return false
 	}
-	if fip.Status.V4Ip != eip.Status.V4Ip {
-		return true
-	}
-	return false
+	return (fip.Status.V4Eip != "" && fip.Status.V4Eip != eip.Status.V4Ip) ||
+		(fip.Status.V6Eip != "" && fip.Status.V6Eip != eip.Status.V6Ip)
 }
 
 func (c *Controller) GetOvnEip(eipName string) (*kubeovnv1.OvnEip, error) {
@@ -593,8 +623,8 @@ func (c *Controller) GetOvnEip(eipName string) (*kubeovnv1.OvnEip, error) {
 		klog.Errorf("failed to get eip %s, %v", eipName, err)
 		return nil, err
 	}
-	if cachedEip.Status.V4Ip == "" {
-		return nil, fmt.Errorf("eip '%s' is not ready, has no v4ip", eipName)
+	if cachedEip.Status.V4Ip == "" && cachedEip.Status.V6Ip == "" {
+		return nil, fmt.Errorf("eip '%s' is not ready, has no ip", eipName)
 	}
 	return cachedEip, nil
 }
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+func Test_getOvnNatIPPairs(t *testing.T) {
+	t.Parallel()
+
+	dualEip := &kubeovnv1.OvnEip{
+		ObjectMeta: metav1.ObjectMeta{Name: "eip"},
+		Status:     kubeovnv1.OvnEipStatus{V4Ip: "172.18.0.10", V6Ip: "fc00::10"},
+	}
+	v6Eip := &kubeovnv1.OvnEip{
+		ObjectMeta: metav1.ObjectMeta{Name: "eip"},
+		Status:     kubeovnv1.OvnEipStatus{V6Ip: "fc00::10"},
+	}
+
+	tests := []struct {
+		name                       string
+		eip                        *kubeovnv1.OvnEip
+		internalV4Ip, internalV6Ip string
+		v4Eip, v4Ip, v6Eip, v6Ip   string
+		wantErr                    bool
+	}{
+		{"dual stack", dualEip, "10.0.1.5", "fd00::5", "172.18.0.10", "10.0.1.5", "fc00::10", "fd00::5", false},
+		{"dual stack eip with v4 ip", dualEip, "10.0.1.5", "", "172.18.0.10", "10.0.1.5", "", "", false},
+		{"dual stack eip with v6 ip", dualEip, "", "fd00::5", "", "", "fc00::10", "fd00::5", false},
+		{"v6 eip with dual stack ip", v6Eip, "10.0.1.5", "fd00::5", "", "", "fc00::10", "fd00::5", false},
+		{"v6 eip with v4 ip", v6Eip, "10.0.1.5", "", "", "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			v4Eip, v4Ip, v6Eip, v6Ip, err := getOvnNatIPPairs("nat", tt.eip, tt.internalV4Ip, tt.internalV6Ip)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []string{tt.v4Eip, tt.v4Ip, tt.v6Eip, tt.v6Ip}, []string{v4Eip, v4Ip, v6Eip, v6Ip})
+		})
+	}
+}
+
+func Test_patchOvnNatLabels(t *testing.T) {
+	t.Parallel()
+
+	v4Eip := &kubeovnv1.OvnEip{ObjectMeta: metav1.ObjectMeta{Name: "eip1"}, Spec: kubeovnv1.OvnEipSpec{V4Ip: "172.18.0.10"}}
+	v6Eip := &kubeovnv1.OvnEip{ObjectMeta: metav1.ObjectMeta{Name: "eip2"}, Spec: kubeovnv1.OvnEipSpec{V6Ip: "fc00::10"}}
+
+	natLabels, op, changed := patchOvnNatLabels(nil, v6Eip)
+	require.Equal(t, map[string]string{util.OvnEipNameLabel: "eip2"}, natLabels)
+	require.Equal(t, "add", op)
+	require.True(t, changed)
+
+	natLabels, op, changed = patchOvnNatLabels(natLabels, v6Eip)
+	require.Equal(t, "replace", op)
+	require.False(t, changed)
+
+	natLabels, _, changed = patchOvnNatLabels(natLabels, v4Eip)
+	require.Equal(t, map[string]string{util.OvnEipNameLabel: "eip1", util.EipV4IpLabel: "172.18.0.10"}, natLabels)
+	require.True(t, changed)
+
+	// the v4 ip label is removed when the nat rule is switched to a v6 only eip
+	natLabels, _, changed = patchOvnNatLabels(natLabels, v6Eip)
+	require.Equal(t, map[string]string{util.OvnEipNameLabel: "eip2"}, natLabels)
+	require.True(t, changed)
+}
+
+func Test_ovnEipNatSelector(t *testing.T) {
+	t.Parallel()
+
+	v4Eip := &kubeovnv1.OvnEip{ObjectMeta: metav1.ObjectMeta{Name: "eip1"}, Spec: kubeovnv1.OvnEipSpec{V4Ip: "172.18.0.10", V6Ip: "fc00::10"}}
+	v6Eip := &kubeovnv1.OvnEip{ObjectMeta: metav1.ObjectMeta{Name: "eip2"}, Spec: kubeovnv1.OvnEipSpec{V6Ip: "fc00::11"}}
+
+	require.True(t, ovnEipNatSelector(v4Eip).Matches(labels.Set{util.EipV4IpLabel: "172.18.0.10"}))
+	require.False(t, ovnEipNatSelector(v4Eip).Matches(labels.Set{util.OvnEipNameLabel: "eip1"}))
+	require.True(t, ovnEipNatSelector(v6Eip).Matches(labels.Set{util.OvnEipNameLabel: "eip2"}))
+	require.False(t, ovnEipNatSelector(v6Eip).Matches(labels.Set{util.OvnEipNameLabel: "eip1"}))
+}
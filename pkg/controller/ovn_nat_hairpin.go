@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"github.com/ovn-org/libovsdb/ovsdb"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+)
+
+// ovnFipHairpinLbName returns the name of the load balancer translating the connections
+// from the vpc to the eip of the fip
+func ovnFipHairpinLbName(fipName string) string {
+	return "hairpin-" + fipName
+}
+
+// vpcOvnSwitches returns the logical switches of the ovn subnets in the vpc
+func (c *Controller) vpcOvnSwitches(vpcName string) ([]string, error) {
+	vpc, err := c.vpcsLister.Get(vpcName)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, nil
+		}
+		klog.Errorf("failed to get vpc %s, %v", vpcName, err)
+		return nil, err
+	}
+	switches := make([]string, 0, len(vpc.Status.Subnets))
+	for _, name := range vpc.Status.Subnets {
+		subnet, err := c.subnetsLister.Get(name)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			klog.Errorf("failed to get subnet %s, %v", name, err)
+			return nil, err
+		}
+		if isOvnSubnet(subnet) {
+			switches = append(switches, subnet.Name)
+		}
+	}
+	return switches, nil
+}
+
+// updateOvnNatHairpin attaches the nat load balancer to or detaches it from the logical switches of the vpc,
+// the connections from the vpc to the eip are translated by the switches rather than the router,
+// so that the replies are translated back even if the client and the backend are in the same subnet
+func (c *Controller) updateOvnNatHairpin(vpcName, lbName string, enable bool) error {
+	if vpcName == "" {
+		return nil
+	}
+	switches, err := c.vpcOvnSwitches(vpcName)
+	if err != nil {
+		return err
+	}
+	op := ovsdb.MutateOperationInsert
+	if !enable {
+		op = ovsdb.MutateOperationDelete
+	}
+	for _, ls := range switches {
+		if err = c.OVNNbClient.LogicalSwitchUpdateLoadBalancers(ls, op, lbName); err != nil {
+			klog.Errorf("failed to update hairpin lb %s of logical switch %s: %v", lbName, ls, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// syncOvnFipHairpin creates the load balancer mapping the eips of the fip to the internal ips
+// and attaches it to the logical switches of the vpc, the load balancer is deleted if disabled
+func (c *Controller) syncOvnFipHairpin(fipName, vpcName string, enable bool, v4Eip, v4Ip, v6Eip, v6Ip string) error {
+	lbName := ovnFipHairpinLbName(fipName)
+	if !enable {
+		if err := c.updateOvnNatHairpin(vpcName, lbName, false); err != nil {
+			return err
+		}
+		if err := c.OVNNbClient.DeleteLoadBalancers(func(lb *ovnnb.LoadBalancer) bool { return lb.Name == lbName }); err != nil {
+			klog.Errorf("failed to delete hairpin lb %s: %v", lbName, err)
+			return err
+		}
+		return nil
+	}
+
+	// the protocol is ignored by the vips without ports
+	if err := c.OVNNbClient.CreateLoadBalancer(lbName, string(v1.ProtocolTCP), ""); err != nil {
+		klog.Errorf("failed to create hairpin lb %s: %v", lbName, err)
+		return err
+	}
+	for _, pair := range [][2]string{{v4Eip, v4Ip}, {v6Eip, v6Ip}} {
+		if pair[0] == "" || pair[1] == "" {
+			continue
+		}
+		if err := c.OVNNbClient.LoadBalancerAddVip(lbName, pair[0], pair[1]); err != nil {
+			klog.Errorf("failed to add vip %s with backend %s to hairpin lb %s: %v", pair[0], pair[1], lbName, err)
+			return err
+		}
+	}
+	return c.updateOvnNatHairpin(vpcName, lbName, true)
+}
+
+// vpcOvnNatHairpinLbs returns the load balancers of the ready fips and dnats in the vpc
+// whose eips have hairpin enabled, which are attached to the logical switches of the vpc
+func (c *Controller) vpcOvnNatHairpinLbs(vpcName string) ([]string, error) {
+	hairpin := func(eipName string) bool {
+		eip, err := c.ovnEipsLister.Get(eipName)
+		return err == nil && eip.Spec.Hairpin
+	}
+
+	var lbs []string
+	fips, err := c.ovnFipsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list ovn fips, %v", err)
+		return nil, err
+	}
+	for _, fip := range fips {
+		if fip.Status.Ready && fip.Status.Vpc == vpcName && hairpin(fip.Spec.OvnEip) {
+			lbs = append(lbs, ovnFipHairpinLbName(fip.Name))
+		}
+	}
+	dnats, err := c.ovnDnatRulesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list ovn dnats, %v", err)
+		return nil, err
+	}
+	for _, dnat := range dnats {
+		if dnat.Status.Ready && dnat.Status.Vpc == vpcName && hairpin(dnat.Spec.OvnEip) {
+			lbs = append(lbs, dnat.Name)
+		}
+	}
+	return lbs, nil
+}
@@ -47,13 +47,21 @@ func (c *Controller) enqueueUpdateOvnSnatRule(oldObj, newObj interface{}) {
 		return
 	}
 	oldSnat := oldObj.(*kubeovnv1.OvnSnatRule)
+	if oldSnat.Spec.OvnEip == "" && newSnat.Spec.OvnEip != "" {
+		// eip allocated from the eip pool
+		klog.Infof("enqueue add ovn snat %s", key)
+		c.addOvnSnatRuleQueue.Add(key)
+		return
+	}
 	if oldSnat.Spec.OvnEip != newSnat.Spec.OvnEip {
 		// enqueue to reset eip to be clean
 		c.resetOvnEipQueue.Add(oldSnat.Spec.OvnEip)
 	}
 	if oldSnat.Spec.OvnEip != newSnat.Spec.OvnEip ||
 		oldSnat.Spec.VpcSubnet != newSnat.Spec.VpcSubnet ||
-		oldSnat.Spec.IPName != newSnat.Spec.IPName {
+		oldSnat.Spec.IPName != newSnat.Spec.IPName ||
+		oldSnat.Spec.V4IpCidr != newSnat.Spec.V4IpCidr ||
+		oldSnat.Spec.V6IpCidr != newSnat.Spec.V6IpCidr {
 		klog.Infof("enqueue update snat %s", key)
 		c.updateOvnSnatRuleQueue.Add(key)
 		return
@@ -173,6 +181,84 @@ func (c *Controller) processNextDeleteOvnSnatRuleWorkItem() bool {
 	return true
 }
 
+// getOvnSnatInternalCIDRs returns the internal cidrs and the vpc of the snat
+func (c *Controller) getOvnSnatInternalCIDRs(snat *kubeovnv1.OvnSnatRule) (string, string, string, error) {
+	vpcName := snat.Spec.Vpc
+	v4IpCidr, v6IpCidr := snat.Spec.V4IpCidr, snat.Spec.V6IpCidr
+	if v4IpCidr == "" && v6IpCidr == "" && snat.Spec.VpcSubnet != "" {
+		subnet, err := c.subnetsLister.Get(snat.Spec.VpcSubnet)
+		if err != nil {
+			klog.Errorf("failed to get vpc subnet %s, %v", snat.Spec.VpcSubnet, err)
+			return "", "", "", err
+		}
+		vpcName = subnet.Spec.Vpc
+		v4IpCidr, v6IpCidr = util.SplitStringIP(subnet.Spec.CIDRBlock)
+	}
+	if v4IpCidr == "" && v6IpCidr == "" && snat.Spec.IPName != "" {
+		vpcPodIP, err := c.ipsLister.Get(snat.Spec.IPName)
+		if err != nil {
+			klog.Errorf("failed to get pod ip %s, %v", snat.Spec.IPName, err)
+			return "", "", "", err
+		}
+		subnet, err := c.subnetsLister.Get(vpcPodIP.Spec.Subnet)
+		if err != nil {
+			klog.Errorf("failed to get vpc subnet %s, %v", vpcPodIP.Spec.Subnet, err)
+			return "", "", "", err
+		}
+		vpcName = subnet.Spec.Vpc
+		v4IpCidr, v6IpCidr = vpcPodIP.Spec.V4IPAddress, vpcPodIP.Spec.V6IPAddress
+	}
+	if v4IpCidr == "" && v6IpCidr == "" {
+		err := fmt.Errorf("failed to get internal ip for snat %s", snat.Name)
+		klog.Error(err)
+		return "", "", "", err
+	}
+	if vpcName == "" {
+		err := fmt.Errorf("failed to get vpc for snat %s", snat.Name)
+		klog.Error(err)
+		return "", "", "", err
+	}
+	return v4IpCidr, v6IpCidr, vpcName, nil
+}
+
+// addOvnSnatRules adds the snat rules of both ip families
+func (c *Controller) addOvnSnatRules(vpcName, v4Eip, v4IpCidr, v6Eip, v6IpCidr string) error {
+	// about conflicts: if multi vpc snat use the same eip, if only one gw node exist, it may should work
+	if v4IpCidr != "" {
+		if err := c.OVNNbClient.AddNat(vpcName, ovnnb.NATTypeSNAT, v4Eip, v4IpCidr, "", "", nil); err != nil {
+			klog.Errorf("failed to create v4 snat, %v", err)
+			return err
+		}
+	}
+	if v6IpCidr != "" {
+		if err := c.OVNNbClient.AddNat(vpcName, ovnnb.NATTypeSNAT, v6Eip, v6IpCidr, "", "", nil); err != nil {
+			klog.Errorf("failed to create v6 snat, %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// delOvnSnatRules deletes the snat rules of both ip families recorded in the status
+func (c *Controller) delOvnSnatRules(snat *kubeovnv1.OvnSnatRule) error {
+	if snat.Status.Vpc == "" {
+		return nil
+	}
+	if snat.Status.V4Eip != "" && snat.Status.V4IpCidr != "" {
+		if err := c.OVNNbClient.DeleteNat(snat.Status.Vpc, ovnnb.NATTypeSNAT, snat.Status.V4Eip, snat.Status.V4IpCidr); err != nil {
+			klog.Errorf("failed to delete v4 snat %s, %v", snat.Name, err)
+			return err
+		}
+	}
+	if snat.Status.V6Eip != "" && snat.Status.V6IpCidr != "" {
+		if err := c.OVNNbClient.DeleteNat(snat.Status.Vpc, ovnnb.NATTypeSNAT, snat.Status.V6Eip, snat.Status.V6IpCidr); err != nil {
+			klog.Errorf("failed to delete v6 snat %s, %v", snat.Name, err)
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *Controller) handleAddOvnSnatRule(key string) error {
 	cachedSnat, err := c.ovnSnatRulesLister.Get(key)
 	if err != nil {
@@ -182,13 +268,16 @@ func (c *Controller) handleAddOvnSnatRule(key string) error {
 		klog.Error(err)
 		return err
 	}
-	if cachedSnat.Status.Ready && cachedSnat.Status.V4IpCidr != "" {
+	if cachedSnat.Status.Ready && (cachedSnat.Status.V4IpCidr != "" || cachedSnat.Status.V6IpCidr != "") {
 		// already ok
 		return nil
 	}
 	klog.Infof("handle add ovn snat %s", key)
 	// check eip
 	eipName := cachedSnat.Spec.OvnEip
+	if eipName == "" && cachedSnat.Spec.EipPool != "" {
+		return c.allocateOvnNatRuleEip(util.SnatUsingEip, ovnSnatRuleKind, cachedSnat, cachedSnat.Spec.EipPool)
+	}
 	if eipName == "" {
 		err := fmt.Errorf("failed to create ovn snat rule, should set eip")
 		klog.Error(err)
@@ -205,56 +294,16 @@ func (c *Controller) handleAddOvnSnatRule(key string) error {
 		klog.Error(err)
 		return err
 	}
-	if cachedEip.Status.V4Ip == "" {
-		err := fmt.Errorf("failed to create v4 snat %s, eip %s has no v4 ip", cachedSnat.Name, eipName)
-		klog.Error(err)
-		return err
-	}
-	var v4IpCidr, vpcName string
-	if cachedSnat.Spec.Vpc != "" {
-		vpcName = cachedSnat.Spec.Vpc
-	}
-	if cachedSnat.Spec.V4IpCidr != "" {
-		v4IpCidr = cachedSnat.Spec.V4IpCidr
-	}
-	if v4IpCidr == "" && cachedSnat.Spec.VpcSubnet != "" {
-		subnet, err := c.subnetsLister.Get(cachedSnat.Spec.VpcSubnet)
-		if err != nil {
-			klog.Errorf("failed to get vpc subnet %s, %v", cachedSnat.Spec.VpcSubnet, err)
-			return err
-		}
-		vpcName = subnet.Spec.Vpc
-		v4IpCidr = subnet.Spec.CIDRBlock
-	}
-	if v4IpCidr == "" && cachedSnat.Spec.IPName != "" {
-		vpcPodIP, err := c.ipsLister.Get(cachedSnat.Spec.IPName)
-		if err != nil {
-			klog.Errorf("failed to get pod ip %s, %v", cachedSnat.Spec.IPName, err)
-			return err
-		}
-		subnet, err := c.subnetsLister.Get(vpcPodIP.Spec.Subnet)
-		if err != nil {
-			klog.Errorf("failed to get vpc subnet %s, %v", vpcPodIP.Spec.Subnet, err)
-			return err
-		}
-		vpcName = subnet.Spec.Vpc
-		v4IpCidr = vpcPodIP.Spec.V4IPAddress
-	}
-	if v4IpCidr == "" {
-		// only support IPv4 snat
-		err = fmt.Errorf("failed to get v4 internal ip for snat %s", key)
-		klog.Error(err)
+	v4IpCidr, v6IpCidr, vpcName, err := c.getOvnSnatInternalCIDRs(cachedSnat)
+	if err != nil {
 		return err
 	}
-	if vpcName == "" {
-		err := fmt.Errorf("failed to get vpc for snat %s", cachedSnat.Name)
-		klog.Error(err)
+	v4Eip, v4IpCidr, v6Eip, v6IpCidr, err := getOvnNatIPPairs(key, cachedEip, v4IpCidr, v6IpCidr)
+	if err != nil {
 		return err
 	}
 
-	// about conflicts: if multi vpc snat use the same eip, if only one gw node exist, it may should work
-	if err = c.OVNNbClient.AddNat(vpcName, ovnnb.NATTypeSNAT, cachedEip.Spec.V4Ip, v4IpCidr, "", "", nil); err != nil {
-		klog.Errorf("failed to create snat, %v", err)
+	if err = c.addOvnSnatRules(vpcName, v4Eip, v4IpCidr, v6Eip, v6IpCidr); err != nil {
 		return err
 	}
 	if err := c.handleAddOvnSnatFinalizer(cachedSnat, util.ControllerName); err != nil {
@@ -269,7 +318,7 @@ func (c *Controller) handleAddOvnSnatRule(key string) error {
 		klog.Errorf("failed to patch label for snat %s, %v", key, err)
 		return err
 	}
-	if err = c.patchOvnSnatStatus(key, vpcName, cachedEip.Spec.V4Ip, v4IpCidr, true); err != nil {
+	if err = c.patchOvnSnatStatus(key, cachedEip, vpcName, v4Eip, v4IpCidr, v6Eip, v6IpCidr, true); err != nil {
 		klog.Errorf("failed to update status for snat %s, %v", key, err)
 		return err
 	}
@@ -292,11 +341,8 @@ func (c *Controller) handleUpdateOvnSnatRule(key string) error {
 	// should delete
 	if !cachedSnat.DeletionTimestamp.IsZero() {
 		klog.Infof("ovn delete snat %s", key)
-		if cachedSnat.Status.Vpc != "" && cachedSnat.Status.V4Eip != "" && cachedSnat.Status.V4IpCidr != "" {
-			if err = c.OVNNbClient.DeleteNat(cachedSnat.Status.Vpc, ovnnb.NATTypeSNAT, cachedSnat.Status.V4Eip, cachedSnat.Status.V4IpCidr); err != nil {
-				klog.Errorf("failed to delete snat, %v", err)
-				return err
-			}
+		if err = c.delOvnSnatRules(cachedSnat); err != nil {
+			return err
 		}
 		c.resetOvnEipQueue.Add(cachedSnat.Spec.OvnEip)
 		return nil
@@ -320,68 +366,24 @@ func (c *Controller) handleUpdateOvnSnatRule(key string) error {
 		klog.Error(err)
 		return err
 	}
-	if cachedEip.Status.V4Ip == "" {
-		err := fmt.Errorf("failed to create v4 snat %s, eip %s has no v4 ip", cachedSnat.Name, eipName)
-		klog.Error(err)
-		return err
-	}
-	var v4IpCidr, vpcName string
-	if cachedSnat.Spec.Vpc != "" {
-		vpcName = cachedSnat.Spec.Vpc
-	}
-	if cachedSnat.Spec.V4IpCidr != "" {
-		v4IpCidr = cachedSnat.Spec.V4IpCidr
-	}
-	if v4IpCidr == "" && cachedSnat.Spec.VpcSubnet != "" {
-		subnet, err := c.subnetsLister.Get(cachedSnat.Spec.VpcSubnet)
-		if err != nil {
-			klog.Errorf("failed to get vpc subnet %s, %v", cachedSnat.Spec.VpcSubnet, err)
-			return err
-		}
-		vpcName = subnet.Spec.Vpc
-		v4IpCidr = subnet.Spec.CIDRBlock
-	}
-	if v4IpCidr == "" && cachedSnat.Spec.IPName != "" {
-		vpcPodIP, err := c.ipsLister.Get(cachedSnat.Spec.IPName)
-		if err != nil {
-			klog.Errorf("failed to get pod ip %s, %v", cachedSnat.Spec.IPName, err)
-			return err
-		}
-		subnet, err := c.subnetsLister.Get(vpcPodIP.Spec.Subnet)
-		if err != nil {
-			klog.Errorf("failed to get vpc subnet %s, %v", vpcPodIP.Spec.Subnet, err)
-			return err
-		}
-		vpcName = subnet.Spec.Vpc
-		v4IpCidr = vpcPodIP.Spec.V4IPAddress
-	}
-	if v4IpCidr == "" {
-		// only support IPv4 snat
-		err = fmt.Errorf("failed to get v4 internal ip for snat %s", key)
-		klog.Error(err)
-		return err
-	}
-	if vpcName == "" {
-		err := fmt.Errorf("failed to get vpc for snat %s", cachedSnat.Name)
-		klog.Error(err)
+	v4IpCidr, v6IpCidr, vpcName, err := c.getOvnSnatInternalCIDRs(cachedSnat)
+	if err != nil {
 		return err
 	}
-	if cachedEip.Spec.Type == util.OvnEipTypeLSP {
-		// eip is using by ecmp nexthop lsp, nat can not use
-		err = fmt.Errorf("ovn nat %s can not use type %s eip %s", key, util.OvnEipTypeLSP, eipName)
-		klog.Error(err)
+	v4Eip, v4IpCidr, v6Eip, v6IpCidr, err := getOvnNatIPPairs(key, cachedEip, v4IpCidr, v6IpCidr)
+	if err != nil {
 		return err
 	}
-	// snat change eip
-	if c.ovnSnatChangeEip(cachedSnat, cachedEip) {
-		klog.Infof("snat change ip, old ip %s, new ip %s", cachedEip.Status.V4Ip, cachedEip.Spec.V4Ip)
-		if err = c.OVNNbClient.DeleteNat(vpcName, ovnnb.NATTypeSNAT, cachedEip.Status.V4Ip, v4IpCidr); err != nil {
-			klog.Errorf("failed to delte snat, %v", err)
+	// snat change eip or internal cidr
+	if c.ovnSnatChangeEip(cachedSnat, cachedEip) || (cachedSnat.Status.Ready &&
+		(cachedSnat.Status.V4IpCidr != v4IpCidr || cachedSnat.Status.V6IpCidr != v6IpCidr)) {
+		klog.Infof("snat change ip, old ip %s, new ip %s", util.GetStringIP(cachedSnat.Status.V4Eip, cachedSnat.Status.V6Eip),
+			util.GetStringIP(v4Eip, v6Eip))
+		if err = c.delOvnSnatRules(cachedSnat); err != nil {
 			return err
 		}
 		// ovn add snat with new eip
-		if err = c.OVNNbClient.AddNat(vpcName, ovnnb.NATTypeSNAT, cachedEip.Spec.V4Ip, v4IpCidr, "", "", nil); err != nil {
-			klog.Errorf("failed to create snat, %v", err)
+		if err = c.addOvnSnatRules(vpcName, v4Eip, v4IpCidr, v6Eip, v6IpCidr); err != nil {
 			return err
 		}
 		if err = c.natLabelAndAnnoOvnEip(eipName, cachedSnat.Name, vpcName); err != nil {
@@ -392,7 +394,7 @@ func (c *Controller) handleUpdateOvnSnatRule(key string) error {
 			klog.Errorf("failed to patch label for snat %s, %v", key, err)
 			return err
 		}
-		if err = c.patchOvnSnatStatus(key, vpcName, cachedEip.Spec.V4Ip, v4IpCidr, true); err != nil {
+		if err = c.patchOvnSnatStatus(key, cachedEip, vpcName, v4Eip, v4IpCidr, v6Eip, v6IpCidr, true); err != nil {
 			klog.Errorf("failed to update status for snat %s, %v", key, err)
 			return err
 		}
@@ -412,12 +414,8 @@ func (c *Controller) handleDelOvnSnatRule(key string) error {
 		return err
 	}
 	// ovn delete snat
-	if cachedSnat.Status.Vpc != "" && cachedSnat.Status.V4Eip != "" && cachedSnat.Status.V4IpCidr != "" {
-		if err = c.OVNNbClient.DeleteNat(cachedSnat.Status.Vpc, ovnnb.NATTypeSNAT,
-			cachedSnat.Status.V4Eip, cachedSnat.Status.V4IpCidr); err != nil {
-			klog.Errorf("failed to delete snat %s, %v", key, err)
-			return err
-		}
+	if err = c.delOvnSnatRules(cachedSnat); err != nil {
+		return err
 	}
 	if err = c.handleDelOvnSnatFinalizer(cachedSnat, util.ControllerName); err != nil {
 		klog.Errorf("failed to remove finalizer for ovn snat %s, %v", cachedSnat.Name, err)
@@ -429,7 +427,7 @@ func (c *Controller) handleDelOvnSnatRule(key string) error {
 	return nil
 }
 
-func (c *Controller) patchOvnSnatStatus(key, vpc, v4Eip, v4IpCidr string, ready bool) error {
+func (c *Controller) patchOvnSnatStatus(key string, eip *kubeovnv1.OvnEip, vpc, v4Eip, v4IpCidr, v6Eip, v6IpCidr string, ready bool) error {
 	oriSnat, err := c.ovnSnatRulesLister.Get(key)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
@@ -439,20 +437,9 @@ func (c *Controller) patchOvnSnatStatus(key, vpc, v4Eip, v4IpCidr string, ready
 		return err
 	}
 	snat := oriSnat.DeepCopy()
-	needUpdateLabel := false
+	var needUpdateLabel bool
 	var op string
-	if len(snat.Labels) == 0 {
-		op = "add"
-		needUpdateLabel = true
-		snat.Labels = map[string]string{
-			util.EipV4IpLabel: v4Eip,
-		}
-	} else if snat.Labels[util.EipV4IpLabel] != v4Eip {
-		op = "replace"
-		needUpdateLabel = true
-		snat.Labels[util.EipV4IpLabel] = v4Eip
-	}
-	if needUpdateLabel {
+	if snat.Labels, op, needUpdateLabel = patchOvnNatLabels(snat.Labels, eip); needUpdateLabel {
 		patchPayloadTemplate := `[{ "op": "%s", "path": "/metadata/labels", "value": %s }]`
 		raw, _ := json.Marshal(snat.Labels)
 		patchPayload := fmt.Sprintf(patchPayloadTemplate, op, raw)
@@ -471,12 +458,12 @@ func (c *Controller) patchOvnSnatStatus(key, vpc, v4Eip, v4IpCidr string, ready
 		snat.Status.Vpc = vpc
 		changed = true
 	}
-	if v4Eip != "" && snat.Status.V4Eip != v4Eip {
-		snat.Status.V4Eip = v4Eip
+	if ready && (snat.Status.V4Eip != v4Eip || snat.Status.V4IpCidr != v4IpCidr) {
+		snat.Status.V4Eip, snat.Status.V4IpCidr = v4Eip, v4IpCidr
 		changed = true
 	}
-	if v4IpCidr != "" && snat.Status.V4IpCidr != v4IpCidr {
-		snat.Status.V4IpCidr = v4IpCidr
+	if ready && (snat.Status.V6Eip != v6Eip || snat.Status.V6IpCidr != v6IpCidr) {
+		snat.Status.V6Eip, snat.Status.V6IpCidr = v6Eip, v6IpCidr
 		changed = true
 	}
 	if changed {
@@ -532,14 +519,12 @@ func (c *Controller) patchOvnSnatAnnotation(key, eipName string) error {
 }
 
 func (c *Controller) ovnSnatChangeEip(snat *kubeovnv1.OvnSnatRule, eip *kubeovnv1.OvnEip) bool {
-	if snat.Status.V4Eip == "" || eip.Spec.V4Ip == "" {
+	if snat.Status.V4Eip == "" && snat.Status.V6Eip == "" {
 		// eip created but not ready
 		return false
 	}
-	if snat.Status.V4Eip != eip.Spec.V4Ip {
-		return true
-	}
-	return false
+	return (snat.Status.V4Eip != "" && snat.Status.V4Eip != eip.Status.V4Ip) ||
+		(snat.Status.V6Eip != "" && snat.Status.V6Eip != eip.Status.V6Ip)
 }
 
 func (c *Controller) handleAddOvnSnatFinalizer(cachedSnat *kubeovnv1.OvnSnatRule, finalizer string) error {
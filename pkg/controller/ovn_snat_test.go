@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+)
+
+func Test_getOvnSnatInternalCIDRs(t *testing.T) {
+	t.Parallel()
+
+	fakeController := newFakeController(t)
+	ctrl := fakeController.fakeController
+	subnet := &kubeovnv1.Subnet{
+		ObjectMeta: metav1.ObjectMeta{Name: "vpc1-subnet"},
+		Spec:       kubeovnv1.SubnetSpec{Vpc: "vpc1", CIDRBlock: "10.0.1.0/24,fd00::/120"},
+	}
+	require.NoError(t, fakeController.fakeinformers.sbunetInformer.Informer().GetStore().Add(subnet))
+
+	tests := []struct {
+		name                        string
+		spec                        kubeovnv1.OvnSnatRuleSpec
+		v4IpCidr, v6IpCidr, vpcName string
+		wantErr                     bool
+	}{
+		{"dual stack subnet", kubeovnv1.OvnSnatRuleSpec{VpcSubnet: "vpc1-subnet"}, "10.0.1.0/24", "fd00::/120", "vpc1", false},
+		{"v6 cidr", kubeovnv1.OvnSnatRuleSpec{Vpc: "vpc2", V6IpCidr: "fd00:1::/120"}, "", "fd00:1::/120", "vpc2", false},
+		{"cidr without vpc", kubeovnv1.OvnSnatRuleSpec{V6IpCidr: "fd00:1::/120"}, "", "", "", true},
+		{"no internal ip", kubeovnv1.OvnSnatRuleSpec{Vpc: "vpc2"}, "", "", "", true},
+		{"subnet not found", kubeovnv1.OvnSnatRuleSpec{VpcSubnet: "nonexistent"}, "", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snat := &kubeovnv1.OvnSnatRule{ObjectMeta: metav1.ObjectMeta{Name: "snat"}, Spec: tt.spec}
+			v4IpCidr, v6IpCidr, vpcName, err := ctrl.getOvnSnatInternalCIDRs(snat)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []string{tt.v4IpCidr, tt.v6IpCidr, tt.vpcName}, []string{v4IpCidr, v6IpCidr, vpcName})
+		})
+	}
+}
+
+func Test_addOvnSnatRules(t *testing.T) {
+	t.Parallel()
+
+	fakeController := newFakeController(t)
+	ctrl := fakeController.fakeController
+	mockOvnClient := fakeController.mockOvnClient
+
+	mockOvnClient.EXPECT().AddNat("vpc1", ovnnb.NATTypeSNAT, "fc00::10", "fd00::/120", "", "", nil).Return(nil)
+	require.NoError(t, ctrl.addOvnSnatRules("vpc1", "", "", "fc00::10", "fd00::/120"))
+
+	mockOvnClient.EXPECT().AddNat("vpc1", ovnnb.NATTypeSNAT, "172.18.0.10", "10.0.1.0/24", "", "", nil).Return(nil)
+	mockOvnClient.EXPECT().AddNat("vpc1", ovnnb.NATTypeSNAT, "fc00::10", "fd00::/120", "", "", nil).Return(nil)
+	require.NoError(t, ctrl.addOvnSnatRules("vpc1", "172.18.0.10", "10.0.1.0/24", "fc00::10", "fd00::/120"))
+}
+
+func Test_delOvnSnatRules(t *testing.T) {
+	t.Parallel()
+
+	fakeController := newFakeController(t)
+	ctrl := fakeController.fakeController
+	mockOvnClient := fakeController.mockOvnClient
+
+	// nothing is deleted if the snat has not been created
+	require.NoError(t, ctrl.delOvnSnatRules(&kubeovnv1.OvnSnatRule{ObjectMeta: metav1.ObjectMeta{Name: "snat"}}))
+
+	snat := &kubeovnv1.OvnSnatRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "snat"},
+		Status: kubeovnv1.OvnSnatRuleStatus{
+			Vpc:   "vpc1",
+			V4Eip: "172.18.0.10", V4IpCidr: "10.0.1.0/24",
+			V6Eip: "fc00::10", V6IpCidr: "fd00::/120",
+		},
+	}
+	mockOvnClient.EXPECT().DeleteNat("vpc1", ovnnb.NATTypeSNAT, "172.18.0.10", "10.0.1.0/24").Return(nil)
+	mockOvnClient.EXPECT().DeleteNat("vpc1", ovnnb.NATTypeSNAT, "fc00::10", "fd00::/120").Return(nil)
+	require.NoError(t, ctrl.delOvnSnatRules(snat))
+}
@@ -192,6 +192,7 @@ func (c *Controller) enqueueAddPod(obj interface{}) {
 	if p.Spec.HostNetwork {
 		return
 	}
+	c.enqueueQoSPoliciesOfPods(p)
 
 	if !isPodAlive(p) {
 		isStateful, statefulSetName := isStatefulSetPod(p)
@@ -245,6 +246,12 @@ func (c *Controller) enqueueDeletePod(obj interface{}) {
 	if p.Spec.HostNetwork {
 		return
 	}
+	c.enqueueQoSPoliciesOfPods(p)
+
+	if vpcGwName, isVpcNatGw := p.Annotations[util.VpcNatGatewayAnnotation]; isVpcNatGw {
+		klog.V(3).Infof("enqueue update ha of vpc nat gw %s", vpcGwName)
+		c.updateVpcNatGwHAQueue.Add(vpcGwName)
+	}
 
 	klog.Infof("enqueue delete pod %s", key)
 	c.deletingPodObjMap.Store(key, p)
@@ -318,10 +325,34 @@ func (c *Controller) enqueueUpdatePod(oldObj, newObj interface{}) {
 		}
 	}
 
+	if c.config.EnableLb && oldPod.Annotations[util.LbUnhealthyServicesAnnotation] != newPod.Annotations[util.LbUnhealthyServicesAnnotation] {
+		svcNames := strset.New(strings.Split(oldPod.Annotations[util.LbUnhealthyServicesAnnotation], ",")...)
+		svcNames.Add(strings.Split(newPod.Annotations[util.LbUnhealthyServicesAnnotation], ",")...)
+		for _, svcName := range svcNames.List() {
+			if svcName != "" {
+				svcKey := cache.NewObjectName(newPod.Namespace, svcName).String()
+				klog.V(3).Infof("enqueue update endpoint slices of service %s for pod %s", svcKey, key)
+				c.updateEndpointQueue.Add(svcKey)
+			}
+		}
+	}
+
 	if newPod.Spec.HostNetwork {
 		return
 	}
 
+	if !reflect.DeepEqual(oldPod.Labels, newPod.Labels) || !reflect.DeepEqual(oldPod.Annotations, newPod.Annotations) ||
+		isPodAlive(oldPod) != isPodAlive(newPod) {
+		c.enqueueQoSPoliciesOfPods(oldPod, newPod)
+	}
+
+	if vpcGwName, isVpcNatGw := newPod.Annotations[util.VpcNatGatewayAnnotation]; isVpcNatGw &&
+		(isNatGwPodReady(oldPod) != isNatGwPodReady(newPod) ||
+			oldPod.Annotations[util.VpcNatGatewayInitAnnotation] != newPod.Annotations[util.VpcNatGatewayInitAnnotation]) {
+		klog.V(3).Infof("enqueue update ha of vpc nat gw %s", vpcGwName)
+		c.updateVpcNatGwHAQueue.Add(vpcGwName)
+	}
+
 	isStateful, statefulSetName := isStatefulSetPod(newPod)
 	isVMPod, vmName := isVMPod(newPod)
 	if !isPodStatusPhaseAlive(newPod) && !isStateful && !isVMPod {
@@ -625,7 +656,20 @@ func (c *Controller) handleAddOrUpdatePod(key string) (err error) {
 
 	// check if route subnet is need.
 	pod = cachedPod.DeepCopy()
-	return c.reconcileRouteSubnets(cachedPod, pod, needRouteSubnets(pod, podNets))
+	if err = c.reconcileRouteSubnets(cachedPod, pod, needRouteSubnets(pod, podNets)); err != nil {
+		return err
+	}
+	// the eip pool annotation may be added to or removed from the running pod
+	if err = c.reconcilePodAutoFip(cachedPod, podNets); err != nil {
+		klog.Errorf("failed to reconcile auto fip of pod %s, %v", key, err)
+		return err
+	}
+
+	if err = c.reconcilePodBandwidth(cachedPod, podNets); err != nil {
+		klog.Errorf("failed to reconcile bandwidth of pod %s, %v", key, err)
+		return err
+	}
+	return nil
 }
 
 // do the same thing as add pod
@@ -937,6 +981,14 @@ func (c *Controller) reconcileRouteSubnets(cachedPod, pod *v1.Pod, needRoutePodN
 			}
 		}
 
+		if podIP != "" && podNet.ProviderName == util.OvnProvider {
+			v4ip, v6ip := util.SplitStringIP(podIP)
+			if err := c.syncAutoFip(autoFipPodKind, pod, v4ip, v6ip, subnet.Spec.Vpc); err != nil {
+				klog.Errorf("failed to sync auto fip of pod %s/%s, %v", namespace, name, err)
+				return err
+			}
+		}
+
 		pod.Annotations[fmt.Sprintf(util.RoutedAnnotationTemplate, podNet.ProviderName)] = "true"
 	}
 	patch, err := util.GenerateMergePatchPayload(cachedPod, pod)
@@ -1077,11 +1129,19 @@ func (c *Controller) handleDeletePod(key string) error {
 		for _, port := range ports {
 			// when lsp is deleted, the port of pod is deleted from any port-group automatically.
 			klog.Infof("gc logical switch port %s", port.Name)
+			if err := c.deletePortQoSs(port.ExternalIDs[logicalSwitchKey], port.Name); err != nil {
+				klog.Errorf("failed to delete qos rules of lsp %s, %v", port.Name, err)
+				return err
+			}
 			if err := c.OVNNbClient.DeleteLogicalSwitchPort(port.Name); err != nil {
 				klog.Errorf("failed to delete lsp %s, %v", port.Name, err)
 				return err
 			}
 		}
+		if err = c.deleteAutoFip(autoFipPodKind, pod); err != nil {
+			klog.Errorf("failed to delete auto fip of pod %s, %v", podKey, err)
+			return err
+		}
 		klog.Infof("try release all ip address for deleting pod %s", podKey)
 		for _, podNet := range podNets {
 			portName := ovs.PodNameToPortName(podName, pod.Namespace, podNet.ProviderName)
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeovn/kube-ovn/pkg/ovs"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// podBandwidthQoSPriority is the priority of the ovn qos rules limiting the bandwidth of pods, the rate annotations
+// of pods take precedence over the bandwidth limit rules of qos policies
+const podBandwidthQoSPriority = 32767
+
+const podBandwidthQoSKey = "pod-bandwidth"
+
+// podBandwidthQoSRows converts the ingress and egress rate of the pod in Mbps to the ovn qos rules of the logical
+// switch port, the ingress rate limits the traffic to the pod while the egress rate limits the traffic from the pod
+func podBandwidthQoSRows(lsName, portName, ingress, egress string) ([]*qosPolicyRow, error) {
+	var rows []*qosPolicyRow
+	for _, limit := range []struct {
+		direction, match, rate string
+	}{
+		{ovnnb.QoSDirectionToLport, fmt.Sprintf("outport == %q", portName), ingress},
+		{ovnnb.QoSDirectionFromLport, fmt.Sprintf("inport == %q", portName), egress},
+	} {
+		if limit.rate == "" {
+			continue
+		}
+		rate, err := strconv.Atoi(limit.rate)
+		if err != nil || rate < 0 {
+			return nil, fmt.Errorf("invalid rate %q of port %s", limit.rate, portName)
+		}
+		if rate == 0 {
+			continue
+		}
+		rows = append(rows, &qosPolicyRow{
+			lsName:    lsName,
+			portName:  portName,
+			direction: limit.direction,
+			priority:  podBandwidthQoSPriority,
+			match:     limit.match,
+			bandwidth: map[string]int{ovnnb.QoSBandwidthRate: rate * 1000},
+		})
+	}
+	return rows, nil
+}
+
+// reconcilePodBandwidth programs the rate annotations of the pod as ovn qos rules of its logical switch ports,
+// so that the limits apply wherever the pod lands without configuring ovs interfaces on the nodes, the rules
+// are removed if the ovn bandwidth limit is disabled
+func (c *Controller) reconcilePodBandwidth(pod *v1.Pod, podNets []*kubeovnNet) error {
+	podName := c.getNameByPod(pod)
+	for _, podNet := range podNets {
+		if podNet.Type == providerTypeIPAM ||
+			pod.Annotations[fmt.Sprintf(util.AllocatedAnnotationTemplate, podNet.ProviderName)] != "true" {
+			continue
+		}
+		lsName := pod.Annotations[fmt.Sprintf(util.LogicalSwitchAnnotationTemplate, podNet.ProviderName)]
+		if lsName == "" {
+			continue
+		}
+		portName := ovs.PodNameToPortName(podName, pod.Namespace, podNet.ProviderName)
+		var rows []*qosPolicyRow
+		if c.config.EnableOVNBandwidthLimit {
+			var err error
+			if rows, err = podBandwidthQoSRows(lsName, portName,
+				pod.Annotations[fmt.Sprintf(util.IngressRateAnnotationTemplate, podNet.ProviderName)],
+				pod.Annotations[fmt.Sprintf(util.EgressRateAnnotationTemplate, podNet.ProviderName)]); err != nil {
+				klog.Error(err)
+				return err
+			}
+		}
+
+		externalIDs := map[string]string{"vendor": util.CniTypeName, podBandwidthQoSKey: "true", "lsp": portName}
+		desired := make(map[string]struct{}, len(rows))
+		for _, row := range rows {
+			if err := c.OVNNbClient.AddQoS(lsName, row.direction, row.priority, row.match, nil, row.bandwidth, externalIDs); err != nil {
+				klog.Errorf("failed to set bandwidth of port %s, %v", portName, err)
+				return err
+			}
+			desired[qosRuleKey(row.direction, row.priority, row.match)] = struct{}{}
+		}
+		if err := c.OVNNbClient.DeleteQoSs(lsName, map[string]string{podBandwidthQoSKey: "true", "lsp": portName}, func(qos *ovnnb.QoS) bool {
+			_, ok := desired[qosRuleKey(qos.Direction, qos.Priority, qos.Match)]
+			return !ok
+		}); err != nil {
+			klog.Errorf("failed to delete stale bandwidth qos rules of port %s, %v", portName, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// deletePortQoSs deletes the ovn qos rules of the pod bandwidth and the qos policies applied to the logical switch port
+func (c *Controller) deletePortQoSs(lsName, portName string) error {
+	if lsName == "" {
+		return nil
+	}
+	exists, err := c.OVNNbClient.LogicalSwitchExists(lsName)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	if err = c.OVNNbClient.DeleteQoSs(lsName, map[string]string{"lsp": portName}, nil); err != nil {
+		klog.Errorf("failed to delete qos rules of lsp %s, %v", portName, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+)
+
+func Test_podBandwidthQoSRows(t *testing.T) {
+	t.Parallel()
+
+	rows, err := podBandwidthQoSRows("ovn-default", "pod1.ns1", "10", "20")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, ovnnb.QoSDirectionToLport, rows[0].direction)
+	require.Equal(t, `outport == "pod1.ns1"`, rows[0].match)
+	require.Equal(t, map[string]int{ovnnb.QoSBandwidthRate: 10000}, rows[0].bandwidth)
+	require.Equal(t, ovnnb.QoSDirectionFromLport, rows[1].direction)
+	require.Equal(t, `inport == "pod1.ns1"`, rows[1].match)
+	require.Equal(t, map[string]int{ovnnb.QoSBandwidthRate: 20000}, rows[1].bandwidth)
+	require.Equal(t, podBandwidthQoSPriority, rows[1].priority)
+
+	rows, err = podBandwidthQoSRows("ovn-default", "pod1.ns1", "", "0")
+	require.NoError(t, err)
+	require.Empty(t, rows)
+
+	_, err = podBandwidthQoSRows("ovn-default", "pod1.ns1", "x", "")
+	require.Error(t, err)
+}
+
+func Test_deletePortQoSs(t *testing.T) {
+	t.Parallel()
+
+	fakeController := newFakeController(t)
+	ctrl := fakeController.fakeController
+	mockOvnClient := fakeController.mockOvnClient
+
+	t.Run("no logical switch", func(t *testing.T) {
+		require.NoError(t, ctrl.deletePortQoSs("", "pod1.ns1"))
+	})
+
+	t.Run("logical switch deleted", func(t *testing.T) {
+		mockOvnClient.EXPECT().LogicalSwitchExists("ovn-default").Return(false, nil)
+		require.NoError(t, ctrl.deletePortQoSs("ovn-default", "pod1.ns1"))
+	})
+
+	t.Run("delete by lsp", func(t *testing.T) {
+		mockOvnClient.EXPECT().LogicalSwitchExists("ovn-default").Return(true, nil)
+		mockOvnClient.EXPECT().DeleteQoSs("ovn-default", map[string]string{"lsp": "pod1.ns1"}, gomock.Nil()).Return(nil)
+		require.NoError(t, ctrl.deletePortQoSs("ovn-default", "pod1.ns1"))
+	})
+}
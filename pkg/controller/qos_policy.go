@@ -61,7 +61,10 @@ func (c *Controller) enqueueUpdateQoSPolicy(oldObj, newObj interface{}) {
 	if oldQos.Status.Shared != newQos.Spec.Shared ||
 		oldQos.Status.BindingType != newQos.Spec.BindingType ||
 		!compareQoSPolicyBandwidthLimitRules(oldQos.Status.BandwidthLimitRules,
-			newQos.Spec.BandwidthLimitRules) {
+			newQos.Spec.BandwidthLimitRules) ||
+		!reflect.DeepEqual(oldQos.Spec.Selector, newQos.Spec.Selector) ||
+		!reflect.DeepEqual(oldQos.Spec.DSCPMarkingRules, newQos.Spec.DSCPMarkingRules) ||
+		!slices.Equal(oldQos.Spec.Subnets, newQos.Spec.Subnets) {
 		klog.V(3).Infof("enqueue update qos %s", key)
 		c.updateQoSPolicyQueue.Add(key)
 		return
@@ -181,8 +184,8 @@ func (c *Controller) processNextDeleteQoSPolicyWorkItem() bool {
 }
 
 func (c *Controller) handleAddQoSPolicy(key string) error {
-	c.vpcNatGwKeyMutex.LockKey(key)
-	defer func() { _ = c.vpcNatGwKeyMutex.UnlockKey(key) }()
+	c.qosPolicyKeyMutex.LockKey(key)
+	defer func() { _ = c.qosPolicyKeyMutex.UnlockKey(key) }()
 	klog.Infof("handle add QoS policy %s", key)
 
 	cachedQoS, err := c.qosPoliciesLister.Get(key)
@@ -203,7 +206,10 @@ func (c *Controller) handleAddQoSPolicy(key string) error {
 		sortedNewRules) &&
 		cachedQoS.Status.Shared == cachedQoS.Spec.Shared &&
 		cachedQoS.Status.BindingType == cachedQoS.Spec.BindingType {
-		// already ok
+		// already ok, the rules of the bound pods are reconciled since they may change while the controller is down
+		if isQoSPolicyBoundToPods(cachedQoS.Status.BindingType) {
+			c.updateQoSPolicyQueue.Add(key)
+		}
 		return nil
 	}
 	klog.V(3).Infof("handle add qos %s", key)
@@ -384,12 +390,17 @@ func (c *Controller) validateQosPolicy(qosPolicy *kubeovnv1.QoSPolicy) error {
 		klog.Error(err)
 		return err
 	}
+	if err = validateQoSPolicyBinding(qosPolicy); err != nil {
+		err = fmt.Errorf("invalid binding of qos policy %s: %v", qosPolicy.Name, err)
+		klog.Error(err)
+		return err
+	}
 	return nil
 }
 
 func (c *Controller) handleUpdateQoSPolicy(key string) error {
-	c.vpcNatGwKeyMutex.LockKey(key)
-	defer func() { _ = c.vpcNatGwKeyMutex.UnlockKey(key) }()
+	c.qosPolicyKeyMutex.LockKey(key)
+	defer func() { _ = c.qosPolicyKeyMutex.UnlockKey(key) }()
 	klog.Infof("handle update QoS policy %s", key)
 
 	cachedQos, err := c.qosPoliciesLister.Get(key)
@@ -432,6 +443,13 @@ func (c *Controller) handleUpdateQoSPolicy(key string) error {
 			}
 		}
 
+		if isQoSPolicyBoundToPods(cachedQos.Spec.BindingType) {
+			if err = c.deleteQoSPolicyRules(key, nil); err != nil {
+				klog.Errorf("failed to delete qos rules of qos policy %s, %v", key, err)
+				return err
+			}
+		}
+
 		if err = c.handleDelQoSPoliciesFinalizer(key); err != nil {
 			klog.Errorf("failed to handle del finalizer for qos %s, %v", key, err)
 			return err
@@ -503,6 +521,13 @@ func (c *Controller) handleUpdateQoSPolicy(key string) error {
 			return err
 		}
 	}
+
+	if isQoSPolicyBoundToPods(cachedQos.Status.BindingType) {
+		if err = c.reconcileQoSPolicyBinding(cachedQos); err != nil {
+			klog.Errorf("failed to reconcile binding of qos policy %s, %v", key, err)
+			return err
+		}
+	}
 	return nil
 }
 
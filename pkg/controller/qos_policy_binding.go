@@ -0,0 +1,527 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/ovs"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// qosPolicyKey is the external id key of the ovn qos rules created for qos policies
+const qosPolicyKey = "qos-policy"
+
+// qosPolicyPort is a logical switch port of the pods bound to a qos policy
+type qosPolicyPort struct {
+	lsName   string
+	portName string
+}
+
+// isQoSPolicyBoundToPods returns whether the rules of the qos policy are applied to the logical
+// switch ports of pods through ovn qos rules, rather than to eips or nat gateways
+func isQoSPolicyBoundToPods(bindingType kubeovnv1.QoSPolicyBindingType) bool {
+	switch bindingType {
+	case kubeovnv1.QoSBindingTypePod, kubeovnv1.QoSBindingTypeNamespace, kubeovnv1.QoSBindingTypeSubnet:
+		return true
+	}
+	return false
+}
+
+func validateQoSPolicyBinding(qosPolicy *kubeovnv1.QoSPolicy) error {
+	switch qosPolicy.Spec.BindingType {
+	case kubeovnv1.QoSBindingTypePod, kubeovnv1.QoSBindingTypeNamespace:
+		if qosPolicy.Spec.Selector == nil {
+			return fmt.Errorf("selector is required by binding type %s", qosPolicy.Spec.BindingType)
+		}
+		if _, err := metav1.LabelSelectorAsSelector(qosPolicy.Spec.Selector); err != nil {
+			return fmt.Errorf("invalid selector: %v", err)
+		}
+	case kubeovnv1.QoSBindingTypeSubnet:
+		if len(qosPolicy.Spec.Subnets) == 0 {
+			return fmt.Errorf("subnets are required by binding type %s", qosPolicy.Spec.BindingType)
+		}
+	default:
+		if qosPolicy.Spec.Selector != nil || len(qosPolicy.Spec.Subnets) != 0 {
+			return fmt.Errorf("selector and subnets are not supported by binding type %s", qosPolicy.Spec.BindingType)
+		}
+		if len(qosPolicy.Spec.DSCPMarkingRules) != 0 {
+			return fmt.Errorf("dscp marking rules are not supported by binding type %s", qosPolicy.Spec.BindingType)
+		}
+		for _, rule := range qosPolicy.Spec.BandwidthLimitRules {
+			if rule.Protocol != "" || rule.Ports != "" {
+				return fmt.Errorf("protocol and ports of rule %s are not supported by binding type %s", rule.Name, qosPolicy.Spec.BindingType)
+			}
+		}
+		return nil
+	}
+
+	if qosPolicy.Spec.Shared {
+		return fmt.Errorf("binding type %s does not support shared qos policy", qosPolicy.Spec.BindingType)
+	}
+	for _, rule := range qosPolicy.Spec.BandwidthLimitRules {
+		if _, _, err := qosPolicyRuleMatch(rule, ""); err != nil {
+			return err
+		}
+		if _, err := qosPolicyRuleBandwidth(rule); err != nil {
+			return err
+		}
+	}
+	for _, rule := range qosPolicy.Spec.DSCPMarkingRules {
+		if _, _, err := qosPolicyDSCPRuleMatch(rule, ""); err != nil {
+			return err
+		}
+		if rule.DSCP < 0 || rule.DSCP > 63 {
+			return fmt.Errorf("invalid dscp %d of rule %s, must be in range 0-63", rule.DSCP, rule.Name)
+		}
+	}
+	return nil
+}
+
+// qosPolicyRuleMatch returns the direction and match of the ovn qos rule of the bandwidth limit rule
+func qosPolicyRuleMatch(rule *kubeovnv1.QoSPolicyBandwidthLimitRule, portName string) (string, string, error) {
+	return qosRuleMatch(rule.Name, rule.Direction, rule.MatchType, rule.MatchValue, rule.Protocol, rule.Ports, portName)
+}
+
+// qosPolicyDSCPRuleMatch returns the direction and match of the ovn qos rule of the dscp marking rule
+func qosPolicyDSCPRuleMatch(rule *kubeovnv1.QoSPolicyDSCPMarkingRule, portName string) (string, string, error) {
+	return qosRuleMatch(rule.Name, rule.Direction, rule.MatchType, rule.MatchValue, rule.Protocol, rule.Ports, portName)
+}
+
+// qosRuleMatch returns the direction and match of the ovn qos rule of the logical switch port,
+// the ingress rule matches the traffic to the port while the egress rule matches the traffic from the port
+func qosRuleMatch(name string, ruleDirection kubeovnv1.QoSPolicyRuleDirection, matchType kubeovnv1.QoSPolicyRuleMatchType, matchValue, protocol, ports, portName string) (string, string, error) {
+	var direction, match string
+	switch ruleDirection {
+	case kubeovnv1.DirectionIngress:
+		direction, match = ovnnb.QoSDirectionToLport, fmt.Sprintf("outport == %q", portName)
+	case kubeovnv1.DirectionEgress:
+		direction, match = ovnnb.QoSDirectionFromLport, fmt.Sprintf("inport == %q", portName)
+	default:
+		return "", "", fmt.Errorf("invalid direction %q of rule %s, must be %s or %s", ruleDirection, name, kubeovnv1.DirectionIngress, kubeovnv1.DirectionEgress)
+	}
+
+	switch matchType {
+	case "":
+	case kubeovnv1.MatchTypeIP:
+		if !validateIPMatchValue(matchValue) {
+			return "", "", fmt.Errorf("invalid ip MatchValue %s of rule %s", matchValue, name)
+		}
+		fields := strings.Fields(matchValue)
+		if util.CheckProtocol(fields[1]) == kubeovnv1.ProtocolIPv4 {
+			match += fmt.Sprintf(" && ip4.%s == %s", fields[0], fields[1])
+		} else {
+			match += fmt.Sprintf(" && ip6.%s == %s", fields[0], fields[1])
+		}
+	default:
+		return "", "", fmt.Errorf("invalid match type %q of rule %s", matchType, name)
+	}
+
+	switch protocol {
+	case "":
+		if ports != "" {
+			return "", "", fmt.Errorf("protocol is required by ports %s of rule %s", ports, name)
+		}
+	case util.ProtocolTCP, util.ProtocolUDP, util.ProtocolSCTP:
+		if ports == "" {
+			match += " && " + protocol
+			break
+		}
+		minPort, maxPort, err := parseQoSRulePorts(ports)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid ports %q of rule %s: %v", ports, name, err)
+		}
+		if minPort == maxPort {
+			match += fmt.Sprintf(" && %s.dst == %d", protocol, minPort)
+		} else {
+			match += fmt.Sprintf(" && %s.dst >= %d && %s.dst <= %d", protocol, minPort, protocol, maxPort)
+		}
+	default:
+		return "", "", fmt.Errorf("invalid protocol %q of rule %s, must be %s, %s or %s", protocol, name, util.ProtocolTCP, util.ProtocolUDP, util.ProtocolSCTP)
+	}
+	return direction, match, nil
+}
+
+// parseQoSRulePorts parses a single port or a port range like 10000-20000
+func parseQoSRulePorts(ports string) (int, int, error) {
+	minStr, maxStr, isRange := strings.Cut(ports, "-")
+	if !isRange {
+		maxStr = minStr
+	}
+	minPort, err := strconv.Atoi(strings.TrimSpace(minStr))
+	if err != nil {
+		return 0, 0, err
+	}
+	maxPort, err := strconv.Atoi(strings.TrimSpace(maxStr))
+	if err != nil {
+		return 0, 0, err
+	}
+	if minPort < 1 || maxPort > 65535 || minPort > maxPort {
+		return 0, 0, fmt.Errorf("port must be in range 1-65535")
+	}
+	return minPort, maxPort, nil
+}
+
+// qosPolicyRuleBandwidth converts the rate in Mbps and the burst in Mbits of the rule to the bandwidth of ovn qos rule,
+// whose rate is in kbps and burst is in kbits
+func qosPolicyRuleBandwidth(rule *kubeovnv1.QoSPolicyBandwidthLimitRule) (map[string]int, error) {
+	rate, err := strconv.Atoi(rule.RateMax)
+	if err != nil || rate <= 0 {
+		return nil, fmt.Errorf("invalid rateMax %q of rule %s", rule.RateMax, rule.Name)
+	}
+	bandwidth := map[string]int{ovnnb.QoSBandwidthRate: rate * 1000}
+	if rule.BurstMax != "" {
+		burst, err := strconv.Atoi(rule.BurstMax)
+		if err != nil || burst <= 0 {
+			return nil, fmt.Errorf("invalid burstMax %q of rule %s", rule.BurstMax, rule.Name)
+		}
+		bandwidth[ovnnb.QoSBandwidthBurst] = burst * 1000
+	}
+	return bandwidth, nil
+}
+
+// getQoSPolicyBoundPorts returns the objects the qos policy is bound to, and the logical switch ports of the pods
+// selected by the objects
+func (c *Controller) getQoSPolicyBoundPorts(qosPolicy *kubeovnv1.QoSPolicy) ([]string, []qosPolicyPort, error) {
+	var boundObjects []string
+	var pods []*v1.Pod
+	switch qosPolicy.Spec.BindingType {
+	case kubeovnv1.QoSBindingTypePod:
+		selector, err := metav1.LabelSelectorAsSelector(qosPolicy.Spec.Selector)
+		if err != nil {
+			klog.Error(err)
+			return nil, nil, err
+		}
+		if pods, err = c.podsLister.List(selector); err != nil {
+			klog.Errorf("failed to list pods, %v", err)
+			return nil, nil, err
+		}
+		for _, pod := range pods {
+			boundObjects = append(boundObjects, pod.Namespace+"/"+pod.Name)
+		}
+	case kubeovnv1.QoSBindingTypeNamespace:
+		selector, err := metav1.LabelSelectorAsSelector(qosPolicy.Spec.Selector)
+		if err != nil {
+			klog.Error(err)
+			return nil, nil, err
+		}
+		namespaces, err := c.namespacesLister.List(selector)
+		if err != nil {
+			klog.Errorf("failed to list namespaces, %v", err)
+			return nil, nil, err
+		}
+		for _, ns := range namespaces {
+			nsPods, err := c.podsLister.Pods(ns.Name).List(labels.Everything())
+			if err != nil {
+				klog.Errorf("failed to list pods in namespace %s, %v", ns.Name, err)
+				return nil, nil, err
+			}
+			pods = append(pods, nsPods...)
+			boundObjects = append(boundObjects, ns.Name)
+		}
+	case kubeovnv1.QoSBindingTypeSubnet:
+		for _, name := range qosPolicy.Spec.Subnets {
+			if _, err := c.subnetsLister.Get(name); err != nil {
+				if k8serrors.IsNotFound(err) {
+					continue
+				}
+				klog.Errorf("failed to get subnet %s, %v", name, err)
+				return nil, nil, err
+			}
+			boundObjects = append(boundObjects, name)
+		}
+		if len(boundObjects) != 0 {
+			var err error
+			if pods, err = c.podsLister.List(labels.Everything()); err != nil {
+				klog.Errorf("failed to list pods, %v", err)
+				return nil, nil, err
+			}
+		}
+	}
+
+	var ports []qosPolicyPort
+	for _, pod := range pods {
+		if pod.Spec.HostNetwork || !isPodAlive(pod) {
+			continue
+		}
+		podNets, err := c.getPodKubeovnNets(pod)
+		if err != nil {
+			klog.Errorf("failed to get networks of pod %s/%s, %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		for _, podNet := range podNets {
+			if podNet.Type == providerTypeIPAM ||
+				pod.Annotations[fmt.Sprintf(util.AllocatedAnnotationTemplate, podNet.ProviderName)] != "true" {
+				continue
+			}
+			lsName := pod.Annotations[fmt.Sprintf(util.LogicalSwitchAnnotationTemplate, podNet.ProviderName)]
+			if lsName == "" {
+				continue
+			}
+			if qosPolicy.Spec.BindingType == kubeovnv1.QoSBindingTypeSubnet && !slices.Contains(boundObjects, lsName) {
+				continue
+			}
+			portName := ovs.PodNameToPortName(c.getNameByPod(pod), pod.Namespace, podNet.ProviderName)
+			ports = append(ports, qosPolicyPort{lsName: lsName, portName: portName})
+		}
+	}
+
+	sort.Strings(boundObjects)
+	return boundObjects, ports, nil
+}
+
+// reconcileQoSPolicyBinding applies the bandwidth limit and dscp marking rules of the qos policy to the logical switch
+// ports of the bound pods, removes the stale ovn qos rules and records the bound objects in the status
+func (c *Controller) reconcileQoSPolicyBinding(qosPolicy *kubeovnv1.QoSPolicy) error {
+	boundObjects, ports, err := c.getQoSPolicyBoundPorts(qosPolicy)
+	if err != nil {
+		klog.Errorf("failed to get bound ports of qos policy %s, %v", qosPolicy.Name, err)
+		return err
+	}
+
+	rows, err := qosPolicyRows(qosPolicy, ports)
+	if err != nil {
+		klog.Errorf("failed to build qos rules of qos policy %s, %v", qosPolicy.Name, err)
+		return err
+	}
+	// the rules in logical switches are identified by direction, priority and match, a rule conflicting
+	// with the one of another owner is skipped so that it does not block the other rules of the policy
+	var errs []error
+	desired := make(map[string]map[string]struct{})
+	for _, row := range rows {
+		externalIDs := map[string]string{
+			"vendor":     util.CniTypeName,
+			qosPolicyKey: qosPolicy.Name,
+			"rule":       strings.Join(row.rules, ","),
+			"lsp":        row.portName,
+		}
+		if err = c.OVNNbClient.AddQoS(row.lsName, row.direction, row.priority, row.match, row.action, row.bandwidth, externalIDs); err != nil {
+			klog.Errorf("failed to add qos rules %v of qos policy %s to port %s, %v", row.rules, qosPolicy.Name, row.portName, err)
+			errs = append(errs, err)
+		}
+		if desired[row.lsName] == nil {
+			desired[row.lsName] = make(map[string]struct{})
+		}
+		desired[row.lsName][qosRuleKey(row.direction, row.priority, row.match)] = struct{}{}
+	}
+
+	if err = c.deleteQoSPolicyRules(qosPolicy.Name, desired); err != nil {
+		klog.Errorf("failed to delete stale qos rules of qos policy %s, %v", qosPolicy.Name, err)
+		return err
+	}
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+
+	if slices.Equal(boundObjects, qosPolicy.Status.BoundObjects) {
+		return nil
+	}
+	// only the bound objects are patched since the cached bandwidth limit rules in status may be stale
+	bytes, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{"boundObjects": boundObjects},
+	})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err = c.config.KubeOvnClient.KubeovnV1().QoSPolicies().Patch(context.Background(), qosPolicy.Name,
+		types.MergePatchType, bytes, metav1.PatchOptions{}, "status"); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf("failed to patch status of qos policy %s, %v", qosPolicy.Name, err)
+		return err
+	}
+	return nil
+}
+
+// qosPolicyRow is an ovn qos rule of a logical switch port, which may be shared by a bandwidth limit rule
+// and a dscp marking rule with the same direction, priority and match
+type qosPolicyRow struct {
+	lsName    string
+	portName  string
+	direction string
+	priority  int
+	match     string
+	action    map[string]int
+	bandwidth map[string]int
+	rules     []string
+}
+
+// qosPolicyRows converts the bandwidth limit rules and the dscp marking rules of the qos policy to the ovn qos rules
+// of the logical switch ports, the meter and the dscp marking of the same match are merged into one ovn qos rule
+func qosPolicyRows(qosPolicy *kubeovnv1.QoSPolicy, ports []qosPolicyPort) ([]*qosPolicyRow, error) {
+	var rows []*qosPolicyRow
+	rowIndex := make(map[string]*qosPolicyRow)
+	getRow := func(port qosPolicyPort, direction string, priority int, match string) *qosPolicyRow {
+		key := port.lsName + "/" + qosRuleKey(direction, priority, match)
+		if row := rowIndex[key]; row != nil {
+			return row
+		}
+		row := &qosPolicyRow{lsName: port.lsName, portName: port.portName, direction: direction, priority: priority, match: match}
+		rowIndex[key] = row
+		rows = append(rows, row)
+		return row
+	}
+
+	for _, port := range ports {
+		for _, rule := range qosPolicy.Spec.BandwidthLimitRules {
+			direction, match, err := qosPolicyRuleMatch(rule, port.portName)
+			if err != nil {
+				klog.Error(err)
+				return nil, err
+			}
+			bandwidth, err := qosPolicyRuleBandwidth(rule)
+			if err != nil {
+				klog.Error(err)
+				return nil, err
+			}
+			row := getRow(port, direction, rule.Priority, match)
+			if row.bandwidth != nil {
+				return nil, fmt.Errorf("bandwidth limit rules %v and %s have the same priority and match", row.rules, rule.Name)
+			}
+			row.bandwidth = bandwidth
+			row.rules = append(row.rules, rule.Name)
+		}
+		for _, rule := range qosPolicy.Spec.DSCPMarkingRules {
+			direction, match, err := qosPolicyDSCPRuleMatch(rule, port.portName)
+			if err != nil {
+				klog.Error(err)
+				return nil, err
+			}
+			row := getRow(port, direction, rule.Priority, match)
+			if row.action != nil {
+				return nil, fmt.Errorf("dscp marking rules %v and %s have the same priority and match", row.rules, rule.Name)
+			}
+			row.action = map[string]int{ovnnb.QoSActionDSCP: rule.DSCP}
+			row.rules = append(row.rules, rule.Name)
+		}
+	}
+	return rows, nil
+}
+
+func qosRuleKey(direction string, priority int, match string) string {
+	return fmt.Sprintf("%s/%d/%s", direction, priority, match)
+}
+
+// deleteQoSPolicyRules deletes the ovn qos rules of the qos policy which are not desired,
+// all of the rules are deleted if desired is nil
+func (c *Controller) deleteQoSPolicyRules(qosPolicyName string, desired map[string]map[string]struct{}) error {
+	subnets, err := c.subnetsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list subnets, %v", err)
+		return err
+	}
+	for _, subnet := range subnets {
+		exists, err := c.OVNNbClient.LogicalSwitchExists(subnet.Name)
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		if !exists {
+			continue
+		}
+		keys := desired[subnet.Name]
+		externalIDs := map[string]string{qosPolicyKey: qosPolicyName}
+		if err = c.OVNNbClient.DeleteQoSs(subnet.Name, externalIDs, func(qos *ovnnb.QoS) bool {
+			_, ok := keys[qosRuleKey(qos.Direction, qos.Priority, qos.Match)]
+			return !ok
+		}); err != nil {
+			klog.Errorf("failed to delete qos rules of qos policy %s from logical switch %s, %v", qosPolicyName, subnet.Name, err)
+			return err
+		}
+	}
+	return nil
+}
+
+// podBoundQoSPolicies returns the qos policies bound to pods, namespaces and subnets
+func (c *Controller) podBoundQoSPolicies() []*kubeovnv1.QoSPolicy {
+	qosPolicies, err := c.qosPoliciesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list qos policies, %v", err)
+		return nil
+	}
+	var result []*kubeovnv1.QoSPolicy
+	for _, qosPolicy := range qosPolicies {
+		if isQoSPolicyBoundToPods(qosPolicy.Status.BindingType) && qosPolicy.DeletionTimestamp.IsZero() {
+			result = append(result, qosPolicy)
+		}
+	}
+	return result
+}
+
+// enqueueQoSPoliciesOfPods enqueues the qos policies selecting the pods, or bound to the namespaces or
+// the subnets of the pods, so that the ovn qos rules follow the pods being created, deleted or relabeled
+func (c *Controller) enqueueQoSPoliciesOfPods(pods ...*v1.Pod) {
+	for _, qosPolicy := range c.podBoundQoSPolicies() {
+		for _, pod := range pods {
+			if c.qosPolicyMatchesPod(qosPolicy, pod) {
+				klog.V(3).Infof("enqueue update qos policy %s for pod %s/%s", qosPolicy.Name, pod.Namespace, pod.Name)
+				c.updateQoSPolicyQueue.Add(qosPolicy.Name)
+				break
+			}
+		}
+	}
+}
+
+// enqueueQoSPoliciesOfNamespaces enqueues the qos policies selecting the namespaces
+func (c *Controller) enqueueQoSPoliciesOfNamespaces(namespaces ...*v1.Namespace) {
+	for _, qosPolicy := range c.podBoundQoSPolicies() {
+		if qosPolicy.Spec.BindingType != kubeovnv1.QoSBindingTypeNamespace {
+			continue
+		}
+		for _, ns := range namespaces {
+			if qosPolicySelects(qosPolicy, ns.Labels) {
+				klog.V(3).Infof("enqueue update qos policy %s for namespace %s", qosPolicy.Name, ns.Name)
+				c.updateQoSPolicyQueue.Add(qosPolicy.Name)
+				break
+			}
+		}
+	}
+}
+
+func (c *Controller) qosPolicyMatchesPod(qosPolicy *kubeovnv1.QoSPolicy, pod *v1.Pod) bool {
+	switch qosPolicy.Spec.BindingType {
+	case kubeovnv1.QoSBindingTypePod:
+		return qosPolicySelects(qosPolicy, pod.Labels)
+	case kubeovnv1.QoSBindingTypeNamespace:
+		ns, err := c.namespacesLister.Get(pod.Namespace)
+		if err != nil {
+			if !k8serrors.IsNotFound(err) {
+				klog.Errorf("failed to get namespace %s, %v", pod.Namespace, err)
+			}
+			return false
+		}
+		return qosPolicySelects(qosPolicy, ns.Labels)
+	case kubeovnv1.QoSBindingTypeSubnet:
+		for key, value := range pod.Annotations {
+			if strings.HasSuffix(key, fmt.Sprintf(util.LogicalSwitchAnnotationTemplate, "")) && slices.Contains(qosPolicy.Spec.Subnets, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func qosPolicySelects(qosPolicy *kubeovnv1.QoSPolicy, set map[string]string) bool {
+	selector, err := metav1.LabelSelectorAsSelector(qosPolicy.Spec.Selector)
+	if err != nil {
+		klog.Errorf("invalid selector of qos policy %s, %v", qosPolicy.Name, err)
+		return false
+	}
+	return selector.Matches(labels.Set(set))
+}
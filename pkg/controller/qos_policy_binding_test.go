@@ -0,0 +1,283 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	kubeovnlister "github.com/kubeovn/kube-ovn/pkg/client/listers/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+)
+
+func Test_qosPolicyRuleMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		rule      *kubeovnv1.QoSPolicyBandwidthLimitRule
+		direction string
+		match     string
+		err       bool
+	}{
+		{
+			name:      "ingress",
+			rule:      &kubeovnv1.QoSPolicyBandwidthLimitRule{Name: "r1", Direction: kubeovnv1.DirectionIngress},
+			direction: ovnnb.QoSDirectionToLport,
+			match:     `outport == "pod1.ns1"`,
+		},
+		{
+			name:      "egress with ipv4 match",
+			rule:      &kubeovnv1.QoSPolicyBandwidthLimitRule{Name: "r1", Direction: kubeovnv1.DirectionEgress, MatchType: kubeovnv1.MatchTypeIP, MatchValue: "dst 10.0.0.0/24"},
+			direction: ovnnb.QoSDirectionFromLport,
+			match:     `inport == "pod1.ns1" && ip4.dst == 10.0.0.0/24`,
+		},
+		{
+			name:      "ingress with ipv6 match",
+			rule:      &kubeovnv1.QoSPolicyBandwidthLimitRule{Name: "r1", Direction: kubeovnv1.DirectionIngress, MatchType: kubeovnv1.MatchTypeIP, MatchValue: "src fd00::/120"},
+			direction: ovnnb.QoSDirectionToLport,
+			match:     `outport == "pod1.ns1" && ip6.src == fd00::/120`,
+		},
+		{
+			name:      "egress with tcp port",
+			rule:      &kubeovnv1.QoSPolicyBandwidthLimitRule{Name: "r1", Direction: kubeovnv1.DirectionEgress, Protocol: "tcp", Ports: "5060"},
+			direction: ovnnb.QoSDirectionFromLport,
+			match:     `inport == "pod1.ns1" && tcp.dst == 5060`,
+		},
+		{
+			name:      "ingress with udp port range",
+			rule:      &kubeovnv1.QoSPolicyBandwidthLimitRule{Name: "r1", Direction: kubeovnv1.DirectionIngress, MatchType: kubeovnv1.MatchTypeIP, MatchValue: "src 10.0.0.0/24", Protocol: "udp", Ports: "10000-20000"},
+			direction: ovnnb.QoSDirectionToLport,
+			match:     `outport == "pod1.ns1" && ip4.src == 10.0.0.0/24 && udp.dst >= 10000 && udp.dst <= 20000`,
+		},
+		{
+			name:      "protocol without ports",
+			rule:      &kubeovnv1.QoSPolicyBandwidthLimitRule{Name: "r1", Direction: kubeovnv1.DirectionEgress, Protocol: "sctp"},
+			direction: ovnnb.QoSDirectionFromLport,
+			match:     `inport == "pod1.ns1" && sctp`,
+		},
+		{
+			name: "ports without protocol",
+			rule: &kubeovnv1.QoSPolicyBandwidthLimitRule{Name: "r1", Direction: kubeovnv1.DirectionEgress, Ports: "80"},
+			err:  true,
+		},
+		{
+			name: "invalid port range",
+			rule: &kubeovnv1.QoSPolicyBandwidthLimitRule{Name: "r1", Direction: kubeovnv1.DirectionEgress, Protocol: "tcp", Ports: "200-100"},
+			err:  true,
+		},
+		{
+			name: "invalid protocol",
+			rule: &kubeovnv1.QoSPolicyBandwidthLimitRule{Name: "r1", Direction: kubeovnv1.DirectionEgress, Protocol: "icmp"},
+			err:  true,
+		},
+		{
+			name: "missing direction",
+			rule: &kubeovnv1.QoSPolicyBandwidthLimitRule{Name: "r1"},
+			err:  true,
+		},
+		{
+			name: "invalid ip match",
+			rule: &kubeovnv1.QoSPolicyBandwidthLimitRule{Name: "r1", Direction: kubeovnv1.DirectionEgress, MatchType: kubeovnv1.MatchTypeIP, MatchValue: "10.0.0.0/24"},
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			direction, match, err := qosPolicyRuleMatch(tt.rule, "pod1.ns1")
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.direction, direction)
+			require.Equal(t, tt.match, match)
+		})
+	}
+}
+
+func Test_qosPolicyRuleBandwidth(t *testing.T) {
+	t.Parallel()
+
+	bandwidth, err := qosPolicyRuleBandwidth(&kubeovnv1.QoSPolicyBandwidthLimitRule{Name: "r1", RateMax: "10", BurstMax: "20"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{ovnnb.QoSBandwidthRate: 10000, ovnnb.QoSBandwidthBurst: 20000}, bandwidth)
+
+	bandwidth, err = qosPolicyRuleBandwidth(&kubeovnv1.QoSPolicyBandwidthLimitRule{Name: "r1", RateMax: "1"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{ovnnb.QoSBandwidthRate: 1000}, bandwidth)
+
+	_, err = qosPolicyRuleBandwidth(&kubeovnv1.QoSPolicyBandwidthLimitRule{Name: "r1"})
+	require.Error(t, err)
+
+	_, err = qosPolicyRuleBandwidth(&kubeovnv1.QoSPolicyBandwidthLimitRule{Name: "r1", RateMax: "10", BurstMax: "x"})
+	require.Error(t, err)
+}
+
+func Test_validateQoSPolicyBinding(t *testing.T) {
+	t.Parallel()
+
+	rules := kubeovnv1.QoSPolicyBandwidthLimitRules{{Name: "r1", RateMax: "10", Direction: kubeovnv1.DirectionIngress}}
+	dscpRules := kubeovnv1.QoSPolicyDSCPMarkingRules{{Name: "d1", DSCP: 46, Direction: kubeovnv1.DirectionEgress, Protocol: "udp", Ports: "10000-20000"}}
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+
+	tests := []struct {
+		name string
+		spec kubeovnv1.QoSPolicySpec
+		err  bool
+	}{
+		{"pod", kubeovnv1.QoSPolicySpec{BindingType: kubeovnv1.QoSBindingTypePod, Selector: selector, BandwidthLimitRules: rules}, false},
+		{"pod without selector", kubeovnv1.QoSPolicySpec{BindingType: kubeovnv1.QoSBindingTypePod, BandwidthLimitRules: rules}, true},
+		{"shared namespace", kubeovnv1.QoSPolicySpec{BindingType: kubeovnv1.QoSBindingTypeNamespace, Selector: selector, Shared: true, BandwidthLimitRules: rules}, true},
+		{"subnet", kubeovnv1.QoSPolicySpec{BindingType: kubeovnv1.QoSBindingTypeSubnet, Subnets: []string{"ovn-default"}, BandwidthLimitRules: rules}, false},
+		{"subnet without subnets", kubeovnv1.QoSPolicySpec{BindingType: kubeovnv1.QoSBindingTypeSubnet, BandwidthLimitRules: rules}, true},
+		{"subnet with invalid rule", kubeovnv1.QoSPolicySpec{BindingType: kubeovnv1.QoSBindingTypeSubnet, Subnets: []string{"ovn-default"}, BandwidthLimitRules: kubeovnv1.QoSPolicyBandwidthLimitRules{{Name: "r1", Direction: kubeovnv1.DirectionIngress}}}, true},
+		{"pod with dscp", kubeovnv1.QoSPolicySpec{BindingType: kubeovnv1.QoSBindingTypePod, Selector: selector, DSCPMarkingRules: dscpRules}, false},
+		{"pod with invalid dscp", kubeovnv1.QoSPolicySpec{BindingType: kubeovnv1.QoSBindingTypePod, Selector: selector, DSCPMarkingRules: kubeovnv1.QoSPolicyDSCPMarkingRules{{Name: "d1", DSCP: 64, Direction: kubeovnv1.DirectionEgress}}}, true},
+		{"eip", kubeovnv1.QoSPolicySpec{BindingType: kubeovnv1.QoSBindingTypeEIP, BandwidthLimitRules: rules}, false},
+		{"eip with dscp", kubeovnv1.QoSPolicySpec{BindingType: kubeovnv1.QoSBindingTypeEIP, DSCPMarkingRules: dscpRules}, true},
+		{"eip with ports", kubeovnv1.QoSPolicySpec{BindingType: kubeovnv1.QoSBindingTypeEIP, BandwidthLimitRules: kubeovnv1.QoSPolicyBandwidthLimitRules{{Name: "r1", RateMax: "10", Protocol: "tcp", Ports: "80"}}}, true},
+		{"eip with selector", kubeovnv1.QoSPolicySpec{BindingType: kubeovnv1.QoSBindingTypeEIP, Selector: selector}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateQoSPolicyBinding(&kubeovnv1.QoSPolicy{Spec: tt.spec})
+			if tt.err {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_qosPolicyRows(t *testing.T) {
+	t.Parallel()
+
+	ports := []qosPolicyPort{{lsName: "ovn-default", portName: "pod1.ns1"}}
+	qosPolicy := &kubeovnv1.QoSPolicy{Spec: kubeovnv1.QoSPolicySpec{
+		BandwidthLimitRules: kubeovnv1.QoSPolicyBandwidthLimitRules{
+			{Name: "r1", RateMax: "10", Priority: 10, Direction: kubeovnv1.DirectionEgress, Protocol: "udp", Ports: "5060"},
+		},
+		DSCPMarkingRules: kubeovnv1.QoSPolicyDSCPMarkingRules{
+			{Name: "d1", DSCP: 46, Priority: 10, Direction: kubeovnv1.DirectionEgress, Protocol: "udp", Ports: "5060"},
+			{Name: "d2", DSCP: 34, Priority: 20, Direction: kubeovnv1.DirectionIngress},
+		},
+	}}
+
+	rows, err := qosPolicyRows(qosPolicy, ports)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	require.Equal(t, ovnnb.QoSDirectionFromLport, rows[0].direction)
+	require.Equal(t, `inport == "pod1.ns1" && udp.dst == 5060`, rows[0].match)
+	require.Equal(t, map[string]int{ovnnb.QoSBandwidthRate: 10000}, rows[0].bandwidth)
+	require.Equal(t, map[string]int{ovnnb.QoSActionDSCP: 46}, rows[0].action)
+	require.Equal(t, []string{"r1", "d1"}, rows[0].rules)
+
+	require.Equal(t, ovnnb.QoSDirectionToLport, rows[1].direction)
+	require.Equal(t, 20, rows[1].priority)
+	require.Nil(t, rows[1].bandwidth)
+	require.Equal(t, map[string]int{ovnnb.QoSActionDSCP: 34}, rows[1].action)
+
+	qosPolicy.Spec.DSCPMarkingRules = append(qosPolicy.Spec.DSCPMarkingRules,
+		&kubeovnv1.QoSPolicyDSCPMarkingRule{Name: "d3", DSCP: 10, Priority: 20, Direction: kubeovnv1.DirectionIngress})
+	_, err = qosPolicyRows(qosPolicy, ports)
+	require.Error(t, err)
+}
+
+func Test_enqueueQoSPoliciesOfPods(t *testing.T) {
+	t.Parallel()
+
+	newQoSPolicy := func(name string, bindingType kubeovnv1.QoSPolicyBindingType, selector map[string]string, subnets ...string) *kubeovnv1.QoSPolicy {
+		qosPolicy := &kubeovnv1.QoSPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       kubeovnv1.QoSPolicySpec{BindingType: bindingType, Subnets: subnets},
+			Status:     kubeovnv1.QoSPolicyStatus{BindingType: bindingType},
+		}
+		if selector != nil {
+			qosPolicy.Spec.Selector = &metav1.LabelSelector{MatchLabels: selector}
+		}
+		return qosPolicy
+	}
+
+	newController := func(t *testing.T) *Controller {
+		qosPolicyIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		for _, qosPolicy := range []*kubeovnv1.QoSPolicy{
+			newQoSPolicy("pod", kubeovnv1.QoSBindingTypePod, map[string]string{"app": "web"}),
+			newQoSPolicy("namespace", kubeovnv1.QoSBindingTypeNamespace, map[string]string{"team": "a"}),
+			newQoSPolicy("subnet", kubeovnv1.QoSBindingTypeSubnet, nil, "net1"),
+			newQoSPolicy("eip", kubeovnv1.QoSBindingTypeEIP, nil),
+		} {
+			require.NoError(t, qosPolicyIndexer.Add(qosPolicy))
+		}
+		namespaceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		require.NoError(t, namespaceIndexer.Add(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-a", Labels: map[string]string{"team": "a"}}}))
+		require.NoError(t, namespaceIndexer.Add(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-b", Labels: map[string]string{"team": "b"}}}))
+
+		return &Controller{
+			qosPoliciesLister:    kubeovnlister.NewQoSPolicyLister(qosPolicyIndexer),
+			namespacesLister:     listerv1.NewNamespaceLister(namespaceIndexer),
+			updateQoSPolicyQueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ""),
+		}
+	}
+
+	queued := func(c *Controller) []string {
+		var keys []string
+		for c.updateQoSPolicyQueue.Len() != 0 {
+			key, _ := c.updateQoSPolicyQueue.Get()
+			keys = append(keys, key.(string))
+			c.updateQoSPolicyQueue.Done(key)
+		}
+		return keys
+	}
+
+	tests := []struct {
+		name     string
+		pod      *v1.Pod
+		expected []string
+	}{
+		{
+			name:     "no match",
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "ns-b"}},
+			expected: nil,
+		},
+		{
+			name:     "pod selector",
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "ns-b", Labels: map[string]string{"app": "web"}}},
+			expected: []string{"pod"},
+		},
+		{
+			name:     "namespace selector",
+			pod:      &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "ns-a"}},
+			expected: []string{"namespace"},
+		},
+		{
+			name: "subnet of attachment network",
+			pod: &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: "ns-missing", Annotations: map[string]string{
+				"attach.default.kubernetes.io/logical_switch": "net1",
+			}}},
+			expected: []string{"subnet"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := newController(t)
+			c.enqueueQoSPoliciesOfPods(tt.pod)
+			require.ElementsMatch(t, tt.expected, queued(c))
+		})
+	}
+
+	t.Run("namespaces", func(t *testing.T) {
+		t.Parallel()
+		c := newController(t)
+		c.enqueueQoSPoliciesOfNamespaces(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ns-b", Labels: map[string]string{"team": "a"}}})
+		require.Equal(t, []string{"namespace"}, queued(c))
+	})
+}
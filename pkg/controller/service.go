@@ -35,6 +35,10 @@ func (c *Controller) enqueueAddService(obj interface{}) {
 	}
 	c.updateEndpointQueue.Add(key)
 	svc := obj.(*v1.Service)
+	if svc.Annotations[util.EipPoolAnnotation] != "" {
+		klog.V(3).Infof("enqueue update service %s", key)
+		c.updateServiceQueue.Add(key)
+	}
 
 	if c.config.EnableNP {
 		var netpols []string
@@ -288,6 +292,11 @@ func (c *Controller) handleDeleteService(service *vpcService) error {
 		}
 	}
 
+	if err = c.deleteAutoFip(autoFipServiceKind, service.Svc); err != nil {
+		klog.Errorf("failed to delete auto fip of service %s, %v", key, err)
+		return err
+	}
+
 	if service.Svc.Spec.Type == v1.ServiceTypeLoadBalancer && c.config.EnableLbSvc {
 		if err := c.deleteLbSvc(service.Svc); err != nil {
 			klog.Errorf("failed to delete service %s, %v", service.Svc.Name, err)
@@ -339,6 +348,12 @@ func (c *Controller) handleUpdateService(key string) error {
 		return err
 	}
 
+	v4ip, v6ip := util.SplitStringIP(strings.Join(ips, ","))
+	if err = c.syncAutoFip(autoFipServiceKind, svc, v4ip, v6ip, vpcName); err != nil {
+		klog.Errorf("failed to sync auto fip of service %s, %v", key, err)
+		return err
+	}
+
 	tcpLb, udpLb, sctpLb := vpc.Status.TCPLoadBalancer, vpc.Status.UDPLoadBalancer, vpc.Status.SctpLoadBalancer
 	oTCPLb, oUDPLb, oSctpLb := vpc.Status.TCPSessionLoadBalancer, vpc.Status.UDPSessionLoadBalancer, vpc.Status.SctpSessionLoadBalancer
 	if svc.Spec.SessionAffinity == v1.ServiceAffinityClientIP {
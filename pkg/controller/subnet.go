@@ -840,6 +840,19 @@ func (c *Controller) handleAddOrUpdateSubnet(key string) error {
 		}
 	}
 
+	if isOvnSubnet(subnet) {
+		// the connections to the eips with hairpin enabled are translated by the logical switches
+		hairpinLbs, err := c.vpcOvnNatHairpinLbs(vpc.Name)
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		if err = c.OVNNbClient.LogicalSwitchUpdateLoadBalancers(subnet.Name, ovsdb.MutateOperationInsert, hairpinLbs...); err != nil {
+			klog.Errorf("failed to add hairpin lbs to subnet %s: %v", subnet.Name, err)
+			return err
+		}
+	}
+
 	if err := c.reconcileSubnet(subnet); err != nil {
 		klog.Errorf("reconcile subnet for %s failed, %v", subnet.Name, err)
 		return err
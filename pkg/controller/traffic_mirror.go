@@ -0,0 +1,361 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/ovs"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// trafficMirrorSyncInterval is the interval to follow the changes of the selected pods
+const trafficMirrorSyncInterval = 10 * time.Second
+
+func (c *Controller) enqueueAddTrafficMirror(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	klog.V(3).Infof("enqueue add traffic mirror %s", key)
+	c.addOrUpdateTrafficMirrorQueue.Add(key)
+}
+
+func (c *Controller) enqueueUpdateTrafficMirror(oldObj, newObj interface{}) {
+	oldTM := oldObj.(*kubeovnv1.TrafficMirror)
+	newTM := newObj.(*kubeovnv1.TrafficMirror)
+	if oldTM.ResourceVersion == newTM.ResourceVersion || reflect.DeepEqual(oldTM.Spec, newTM.Spec) {
+		return
+	}
+
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(newObj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	klog.V(3).Infof("enqueue update traffic mirror %s", key)
+	c.addOrUpdateTrafficMirrorQueue.Add(key)
+}
+
+func (c *Controller) enqueueDelTrafficMirror(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	klog.V(3).Infof("enqueue delete traffic mirror %s", key)
+	c.delTrafficMirrorQueue.Add(key)
+}
+
+func (c *Controller) runAddOrUpdateTrafficMirrorWorker() {
+	for c.processNextWorkItem("addOrUpdateTrafficMirror", c.addOrUpdateTrafficMirrorQueue, c.handleAddOrUpdateTrafficMirror) {
+	}
+}
+
+func (c *Controller) runDelTrafficMirrorWorker() {
+	for c.processNextWorkItem("delTrafficMirror", c.delTrafficMirrorQueue, c.handleDelTrafficMirror) {
+	}
+}
+
+// resyncTrafficMirrors enqueues all traffic mirrors, since the selected pods are not watched
+func (c *Controller) resyncTrafficMirrors() {
+	trafficMirrors, err := c.trafficMirrorsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list traffic mirrors, %v", err)
+		return
+	}
+	for _, tm := range trafficMirrors {
+		c.addOrUpdateTrafficMirrorQueue.Add(tm.Name)
+	}
+}
+
+func validateTrafficMirror(tm *kubeovnv1.TrafficMirror) error {
+	if tm.Spec.Selector == nil {
+		return fmt.Errorf("selector is required")
+	}
+	if _, err := metav1.LabelSelectorAsSelector(tm.Spec.Selector); err != nil {
+		return fmt.Errorf("invalid selector: %v", err)
+	}
+	if _, err := metav1.LabelSelectorAsSelector(tm.Spec.NamespaceSelector); err != nil {
+		return fmt.Errorf("invalid namespace selector: %v", err)
+	}
+	switch tm.Spec.Direction {
+	case kubeovnv1.TrafficMirrorDirectionIngress, kubeovnv1.TrafficMirrorDirectionEgress, kubeovnv1.TrafficMirrorDirectionBoth:
+	default:
+		return fmt.Errorf("unsupported direction %q", tm.Spec.Direction)
+	}
+	if net.ParseIP(tm.Spec.Remote) == nil {
+		return fmt.Errorf("invalid remote ip address %q", tm.Spec.Remote)
+	}
+
+	// erspan session id is 10 bits, vxlan vni is 24 bits and gre key is 32 bits
+	var maxKey int64
+	switch tm.Spec.Type {
+	case kubeovnv1.TrafficMirrorTypeErspan:
+		maxKey = 1<<10 - 1
+	case kubeovnv1.TrafficMirrorTypeGre:
+		maxKey = 1<<32 - 1
+	case kubeovnv1.TrafficMirrorTypeVxlan:
+		maxKey = 1<<24 - 1
+	default:
+		return fmt.Errorf("unsupported type %q", tm.Spec.Type)
+	}
+	if tm.Spec.Key < 0 || int64(tm.Spec.Key) > maxKey {
+		return fmt.Errorf("key %d of type %s is out of range [0, %d]", tm.Spec.Key, tm.Spec.Type, maxKey)
+	}
+	return nil
+}
+
+// trafficMirrorBackend returns the backend of the mirror type, vxlan is not supported by ovn mirrors
+func trafficMirrorBackend(mirrorType kubeovnv1.TrafficMirrorType) kubeovnv1.TrafficMirrorBackend {
+	if mirrorType == kubeovnv1.TrafficMirrorTypeVxlan {
+		return kubeovnv1.TrafficMirrorBackendOVS
+	}
+	return kubeovnv1.TrafficMirrorBackendOVN
+}
+
+// trafficMirrorOVNMirrors returns the filters of the ovn mirrors keyed by the mirror names, the traffic to the
+// pods is mirrored by to-lport mirrors while the traffic from the pods is mirrored by from-lport mirrors
+func trafficMirrorOVNMirrors(tm *kubeovnv1.TrafficMirror) map[string]string {
+	mirrors := make(map[string]string, 2)
+	if tm.Spec.Direction != kubeovnv1.TrafficMirrorDirectionEgress {
+		mirrors[fmt.Sprintf("%s-%s", tm.Name, kubeovnv1.TrafficMirrorDirectionIngress)] = ovnnb.MirrorFilterToLport
+	}
+	if tm.Spec.Direction != kubeovnv1.TrafficMirrorDirectionIngress {
+		mirrors[fmt.Sprintf("%s-%s", tm.Name, kubeovnv1.TrafficMirrorDirectionEgress)] = ovnnb.MirrorFilterFromLport
+	}
+	return mirrors
+}
+
+// getTrafficMirrorNodes returns the logical switch ports of the selected pods grouped by the nodes of the pods
+func (c *Controller) getTrafficMirrorNodes(tm *kubeovnv1.TrafficMirror) ([]kubeovnv1.TrafficMirrorNode, error) {
+	selector, err := metav1.LabelSelectorAsSelector(tm.Spec.Selector)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	var pods []*v1.Pod
+	if tm.Spec.NamespaceSelector == nil {
+		if pods, err = c.podsLister.List(selector); err != nil {
+			klog.Errorf("failed to list pods, %v", err)
+			return nil, err
+		}
+	} else {
+		nsSelector, err := metav1.LabelSelectorAsSelector(tm.Spec.NamespaceSelector)
+		if err != nil {
+			klog.Error(err)
+			return nil, err
+		}
+		namespaces, err := c.namespacesLister.List(nsSelector)
+		if err != nil {
+			klog.Errorf("failed to list namespaces, %v", err)
+			return nil, err
+		}
+		for _, ns := range namespaces {
+			nsPods, err := c.podsLister.Pods(ns.Name).List(selector)
+			if err != nil {
+				klog.Errorf("failed to list pods in namespace %s, %v", ns.Name, err)
+				return nil, err
+			}
+			pods = append(pods, nsPods...)
+		}
+	}
+
+	nodePorts := make(map[string][]string)
+	for _, pod := range pods {
+		if pod.Spec.HostNetwork || pod.Spec.NodeName == "" || !isPodAlive(pod) {
+			continue
+		}
+		podNets, err := c.getPodKubeovnNets(pod)
+		if err != nil {
+			klog.Errorf("failed to get networks of pod %s/%s, %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		for _, podNet := range podNets {
+			if podNet.Type == providerTypeIPAM ||
+				pod.Annotations[fmt.Sprintf(util.AllocatedAnnotationTemplate, podNet.ProviderName)] != "true" {
+				continue
+			}
+			portName := ovs.PodNameToPortName(c.getNameByPod(pod), pod.Namespace, podNet.ProviderName)
+			nodePorts[pod.Spec.NodeName] = append(nodePorts[pod.Spec.NodeName], portName)
+		}
+	}
+
+	var nodes []kubeovnv1.TrafficMirrorNode
+	for node, ports := range nodePorts {
+		sort.Strings(ports)
+		nodes = append(nodes, kubeovnv1.TrafficMirrorNode{Node: node, Ports: ports})
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Node < nodes[j].Node })
+	return nodes, nil
+}
+
+func (c *Controller) handleAddOrUpdateTrafficMirror(key string) error {
+	tm, err := c.trafficMirrorsLister.Get(key)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Error(err)
+		return err
+	}
+	klog.V(3).Infof("handle add or update traffic mirror %s", key)
+
+	status := tm.Status.DeepCopy()
+	if err = validateTrafficMirror(tm); err != nil {
+		klog.Errorf("failed to validate traffic mirror %s, %v", key, err)
+		// stop mirroring rather than keep mirroring with the spec not applied
+		if delErr := c.reconcileTrafficMirrorOVNMirrors(tm, nil, nil); delErr != nil {
+			klog.Errorf("failed to delete ovn mirrors of traffic mirror %s, %v", key, delErr)
+			return delErr
+		}
+		status.Nodes = nil
+		status.ClearCondition(kubeovnv1.Ready, "ValidationFailed", err.Error())
+		return c.patchTrafficMirrorStatus(tm, status)
+	}
+
+	nodes, err := c.getTrafficMirrorNodes(tm)
+	if err != nil {
+		klog.Errorf("failed to get mirrored ports of traffic mirror %s, %v", key, err)
+		return err
+	}
+
+	backend := trafficMirrorBackend(tm.Spec.Type)
+	var mirrors map[string]string
+	if backend == kubeovnv1.TrafficMirrorBackendOVN {
+		mirrors = trafficMirrorOVNMirrors(tm)
+	}
+	if err = c.reconcileTrafficMirrorOVNMirrors(tm, mirrors, nodes); err != nil {
+		klog.Errorf("failed to reconcile ovn mirrors of traffic mirror %s, %v", key, err)
+		status.ClearCondition(kubeovnv1.Ready, "ReconcileFailed", err.Error())
+		if patchErr := c.patchTrafficMirrorStatus(tm, status); patchErr != nil {
+			klog.Error(patchErr)
+		}
+		return err
+	}
+
+	// the ovs mirrors are configured by kube-ovn-cni according to the ports of the node in status
+	status.Backend = backend
+	status.Nodes = nodes
+	status.SetCondition(kubeovnv1.Ready, "Reconciled", "")
+	return c.patchTrafficMirrorStatus(tm, status)
+}
+
+// reconcileTrafficMirrorOVNMirrors creates the desired ovn mirrors of the traffic mirror, attaches them to the
+// mirrored logical switch ports only and deletes the stale ones, all of them are deleted if mirrors is empty
+func (c *Controller) reconcileTrafficMirrorOVNMirrors(tm *kubeovnv1.TrafficMirror, mirrors map[string]string, nodes []kubeovnv1.TrafficMirrorNode) error {
+	externalIDs := map[string]string{"vendor": util.CniTypeName, "traffic-mirror": tm.Name}
+	existing, err := c.OVNNbClient.ListMirrors(externalIDs)
+	if err != nil {
+		klog.Errorf("failed to list ovn mirrors of traffic mirror %s, %v", tm.Name, err)
+		return err
+	}
+	for _, mirror := range existing {
+		if _, ok := mirrors[mirror.Name]; ok {
+			continue
+		}
+		if err = c.OVNNbClient.DeleteMirror(mirror.Name); err != nil {
+			klog.Errorf("failed to delete ovn mirror %s, %v", mirror.Name, err)
+			return err
+		}
+	}
+	if len(mirrors) == 0 {
+		return nil
+	}
+
+	var ports []string
+	for _, node := range nodes {
+		ports = append(ports, node.Ports...)
+	}
+	for name, filter := range mirrors {
+		if err = c.OVNNbClient.CreateOrUpdateMirror(name, filter, tm.Spec.Remote, string(tm.Spec.Type), tm.Spec.Key, externalIDs); err != nil {
+			klog.Errorf("failed to create ovn mirror %s, %v", name, err)
+			return err
+		}
+		mirror, err := c.OVNNbClient.GetMirror(name, false)
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+
+		lspList, err := c.OVNNbClient.ListLogicalSwitchPorts(false, nil, func(lsp *ovnnb.LogicalSwitchPort) bool {
+			return slices.Contains(ports, lsp.Name) || slices.Contains(lsp.MirrorRules, mirror.UUID)
+		})
+		if err != nil {
+			klog.Errorf("failed to list logical switch ports of ovn mirror %s, %v", name, err)
+			return err
+		}
+		for _, lsp := range lspList {
+			mirrored, desired := slices.Contains(lsp.MirrorRules, mirror.UUID), slices.Contains(ports, lsp.Name)
+			if mirrored == desired {
+				continue
+			}
+			op := ovsdb.MutateOperationInsert
+			if !desired {
+				op = ovsdb.MutateOperationDelete
+			}
+			if err = c.OVNNbClient.LogicalSwitchPortUpdateMirrors(lsp.Name, op, name); err != nil {
+				klog.Errorf("failed to update ovn mirror %s of logical switch port %s, %v", name, lsp.Name, err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Controller) patchTrafficMirrorStatus(tm *kubeovnv1.TrafficMirror, status *kubeovnv1.TrafficMirrorStatus) error {
+	if reflect.DeepEqual(tm.Status, *status) {
+		return nil
+	}
+	bytes, err := status.Bytes()
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err = c.config.KubeOvnClient.KubeovnV1().TrafficMirrors().Patch(context.Background(), tm.Name,
+		types.MergePatchType, bytes, metav1.PatchOptions{}, "status"); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf("failed to patch status of traffic mirror %s, %v", tm.Name, err)
+		return err
+	}
+	return nil
+}
+
+func (c *Controller) handleDelTrafficMirror(key string) error {
+	klog.V(3).Infof("handle delete traffic mirror %s", key)
+	mirrors, err := c.OVNNbClient.ListMirrors(map[string]string{"vendor": util.CniTypeName, "traffic-mirror": key})
+	if err != nil {
+		klog.Errorf("failed to list ovn mirrors of traffic mirror %s, %v", key, err)
+		return err
+	}
+	for _, mirror := range mirrors {
+		if err = c.OVNNbClient.DeleteMirror(mirror.Name); err != nil {
+			klog.Errorf("failed to delete ovn mirror %s, %v", mirror.Name, err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+)
+
+func Test_validateTrafficMirror(t *testing.T) {
+	t.Parallel()
+
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	newTrafficMirror := func(direction kubeovnv1.TrafficMirrorDirection, mirrorType kubeovnv1.TrafficMirrorType, remote string, key int) *kubeovnv1.TrafficMirror {
+		return &kubeovnv1.TrafficMirror{
+			ObjectMeta: metav1.ObjectMeta{Name: "tm1"},
+			Spec: kubeovnv1.TrafficMirrorSpec{
+				Selector:  selector,
+				Direction: direction,
+				Type:      mirrorType,
+				Remote:    remote,
+				Key:       key,
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		tm   *kubeovnv1.TrafficMirror
+		err  bool
+	}{
+		{
+			name: "erspan",
+			tm:   newTrafficMirror(kubeovnv1.TrafficMirrorDirectionBoth, kubeovnv1.TrafficMirrorTypeErspan, "192.168.0.10", 1023),
+		},
+		{
+			name: "gre with ipv6 remote",
+			tm:   newTrafficMirror(kubeovnv1.TrafficMirrorDirectionIngress, kubeovnv1.TrafficMirrorTypeGre, "fd00::10", 1<<32-1),
+		},
+		{
+			name: "vxlan",
+			tm:   newTrafficMirror(kubeovnv1.TrafficMirrorDirectionEgress, kubeovnv1.TrafficMirrorTypeVxlan, "192.168.0.10", 100),
+		},
+		{
+			name: "erspan session id out of range",
+			tm:   newTrafficMirror(kubeovnv1.TrafficMirrorDirectionBoth, kubeovnv1.TrafficMirrorTypeErspan, "192.168.0.10", 1024),
+			err:  true,
+		},
+		{
+			name: "vxlan vni out of range",
+			tm:   newTrafficMirror(kubeovnv1.TrafficMirrorDirectionBoth, kubeovnv1.TrafficMirrorTypeVxlan, "192.168.0.10", 1<<24),
+			err:  true,
+		},
+		{
+			name: "invalid remote",
+			tm:   newTrafficMirror(kubeovnv1.TrafficMirrorDirectionBoth, kubeovnv1.TrafficMirrorTypeGre, "collector", 0),
+			err:  true,
+		},
+		{
+			name: "invalid direction",
+			tm:   newTrafficMirror("all", kubeovnv1.TrafficMirrorTypeGre, "192.168.0.10", 0),
+			err:  true,
+		},
+		{
+			name: "invalid type",
+			tm:   newTrafficMirror(kubeovnv1.TrafficMirrorDirectionBoth, "geneve", "192.168.0.10", 0),
+			err:  true,
+		},
+		{
+			name: "missing selector",
+			tm: &kubeovnv1.TrafficMirror{Spec: kubeovnv1.TrafficMirrorSpec{
+				Direction: kubeovnv1.TrafficMirrorDirectionBoth, Type: kubeovnv1.TrafficMirrorTypeGre, Remote: "192.168.0.10",
+			}},
+			err: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTrafficMirror(tt.tm)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_trafficMirrorOVNMirrors(t *testing.T) {
+	t.Parallel()
+
+	tm := &kubeovnv1.TrafficMirror{ObjectMeta: metav1.ObjectMeta{Name: "tm1"}}
+
+	tm.Spec.Direction = kubeovnv1.TrafficMirrorDirectionIngress
+	require.Equal(t, map[string]string{"tm1-ingress": ovnnb.MirrorFilterToLport}, trafficMirrorOVNMirrors(tm))
+
+	tm.Spec.Direction = kubeovnv1.TrafficMirrorDirectionEgress
+	require.Equal(t, map[string]string{"tm1-egress": ovnnb.MirrorFilterFromLport}, trafficMirrorOVNMirrors(tm))
+
+	tm.Spec.Direction = kubeovnv1.TrafficMirrorDirectionBoth
+	require.Equal(t, map[string]string{
+		"tm1-ingress": ovnnb.MirrorFilterToLport,
+		"tm1-egress":  ovnnb.MirrorFilterFromLport,
+	}, trafficMirrorOVNMirrors(tm))
+
+	require.Equal(t, kubeovnv1.TrafficMirrorBackendOVN, trafficMirrorBackend(kubeovnv1.TrafficMirrorTypeErspan))
+	require.Equal(t, kubeovnv1.TrafficMirrorBackendOVN, trafficMirrorBackend(kubeovnv1.TrafficMirrorTypeGre))
+	require.Equal(t, kubeovnv1.TrafficMirrorBackendOVS, trafficMirrorBackend(kubeovnv1.TrafficMirrorTypeVxlan))
+}
@@ -51,6 +51,7 @@ func (c *Controller) enqueueUpdateVpc(oldObj, newObj interface{}) {
 		!reflect.DeepEqual(oldVpc.Spec.StaticRoutes, newVpc.Spec.StaticRoutes) ||
 		!reflect.DeepEqual(oldVpc.Spec.PolicyRoutes, newVpc.Spec.PolicyRoutes) ||
 		!reflect.DeepEqual(oldVpc.Spec.VpcPeerings, newVpc.Spec.VpcPeerings) ||
+		!reflect.DeepEqual(oldVpc.Status.ICPeerings, newVpc.Status.ICPeerings) ||
 		!reflect.DeepEqual(oldVpc.Annotations, newVpc.Annotations) ||
 		!reflect.DeepEqual(oldVpc.Spec.ExtraExternalSubnets, newVpc.Spec.ExtraExternalSubnets) ||
 		oldVpc.Spec.EnableExternal != newVpc.Spec.EnableExternal ||
@@ -253,6 +254,31 @@ func (c *Controller) addLoadBalancer(vpc string) (*VpcLoadBalancer, error) {
 	return vpcLbConfig, nil
 }
 
+// icPeeringStaticRoutes routes the prefixes learned from the vpcs peered through ovn-ic
+// to the remote vpc routers on the transit switches
+func icPeeringStaticRoutes(vpc *kubeovnv1.Vpc) []*kubeovnv1.StaticRoute {
+	var routes []*kubeovnv1.StaticRoute
+	for _, peering := range vpc.Status.ICPeerings {
+		if !peering.Ready {
+			continue
+		}
+		for _, prefix := range peering.LearnedPrefixes {
+			for _, nextHop := range strings.Split(peering.NextHopIP, ",") {
+				if util.CheckProtocol(nextHop) != util.CheckProtocol(prefix) {
+					continue
+				}
+				routes = append(routes, &kubeovnv1.StaticRoute{
+					Policy:     kubeovnv1.PolicyDst,
+					CIDR:       prefix,
+					NextHopIP:  nextHop,
+					RouteTable: util.MainRouteTable,
+				})
+			}
+		}
+	}
+	return routes
+}
+
 func (c *Controller) handleAddOrUpdateVpc(key string) error {
 	c.vpcKeyMutex.LockKey(key)
 	defer func() { _ = c.vpcKeyMutex.UnlockKey(key) }()
@@ -317,6 +343,10 @@ func (c *Controller) handleAddOrUpdateVpc(key string) error {
 		klog.Errorf("failed to get vpc %s static route list, %v", vpc.Name, err)
 		return err
 	}
+	// the routes learned from the other ovn-ic azs are managed by ovn-ic-controller
+	staticExistedRoutes = slices.DeleteFunc(staticExistedRoutes, func(route *ovnnb.LogicalRouterStaticRoute) bool {
+		return route.ExternalIDs[util.OvnICRouteAzKey] != ""
+	})
 
 	var externalSubnet *kubeovnv1.Subnet
 	externalSubnetExist := false
@@ -420,6 +450,13 @@ func (c *Controller) handleAddOrUpdateVpc(key string) error {
 		}
 	}
 
+	staticTargetRoutes = append(staticTargetRoutes, icPeeringStaticRoutes(vpc)...)
+
+	if staticTargetRoutes, err = c.redirectVpcNatGwRoutes(vpc, staticTargetRoutes); err != nil {
+		klog.Errorf("failed to redirect vpc %s static routes to ha nat gw, %v", vpc.Name, err)
+		return err
+	}
+
 	routeNeedDel, routeNeedAdd, err := diffStaticRoute(staticExistedRoutes, staticTargetRoutes)
 	if err != nil {
 		klog.Errorf("failed to diff vpc %s static route, %v", vpc.Name, err)
@@ -1098,9 +1135,10 @@ func (c *Controller) handleAddVpcExternalSubnet(key, subnet string) error {
 		}
 	} else {
 		v4ip = cachedEip.Spec.V4Ip
+		v6ip = cachedEip.Spec.V6Ip
 		mac = cachedEip.Spec.MacAddress
 	}
-	if v4ip == "" || mac == "" {
+	if (v4ip == "" && v6ip == "") || mac == "" {
 		err := fmt.Errorf("lrp '%s' ip or mac should not be empty", lrpEipName)
 		klog.Error(err)
 		return err
@@ -1134,7 +1172,7 @@ func (c *Controller) handleAddVpcExternalSubnet(key, subnet string) error {
 		return err
 	}
 
-	v4ipCidr, err := util.GetIPAddrWithMask(v4ip, cachedSubnet.Spec.CIDRBlock)
+	ipCidr, err := util.GetIPAddrWithMask(util.GetStringIP(v4ip, v6ip), cachedSubnet.Spec.CIDRBlock)
 	if err != nil {
 		klog.Error(err)
 		return err
@@ -1142,10 +1180,17 @@ func (c *Controller) handleAddVpcExternalSubnet(key, subnet string) error {
 	lspName := fmt.Sprintf("%s-%s", subnet, key)
 	lrpName := fmt.Sprintf("%s-%s", key, subnet)
 
-	if err := c.OVNNbClient.CreateLogicalPatchPort(subnet, key, lspName, lrpName, v4ipCidr, mac, chassises...); err != nil {
+	if err := c.OVNNbClient.CreateLogicalPatchPort(subnet, key, lspName, lrpName, ipCidr, mac, chassises...); err != nil {
 		klog.Errorf("failed to connect router '%s' to external: %v", key, err)
 		return err
 	}
+	// no ndp proxy is needed for the ipv6 eips of the nat rules, ovn northd installs the neighbor
+	// advertisement responders for the nat external ips reachable from the gateway port, which requires
+	// the ipv6 network missing in the port created before dual stack support
+	if err := c.OVNNbClient.UpdateLogicalRouterPortNetworks(lrpName, strings.Split(ipCidr, ",")); err != nil {
+		klog.Errorf("failed to update networks of lrp %s: %v", lrpName, err)
+		return err
+	}
 
 	cachedVpc, err := c.vpcsLister.Get(key)
 	if err != nil {
@@ -8,7 +8,10 @@ import (
 	"github.com/kubeovn/kube-ovn/pkg/util"
 )
 
-var vpcNatImage = ""
+var (
+	vpcNatImage           = ""
+	vpcNatFirewallBackend = util.FirewallBackendIptables
+)
 
 func (c *Controller) resyncVpcNatImage() error {
 	cm, err := c.configMapsLister.ConfigMaps(c.config.PodNamespace).Get(util.VpcNatConfig)
@@ -24,5 +27,17 @@ func (c *Controller) resyncVpcNatImage() error {
 		return err
 	}
 	vpcNatImage = image
+
+	backend := cm.Data["firewall-backend"]
+	switch backend {
+	case "":
+		backend = util.FirewallBackendIptables
+	case util.FirewallBackendIptables, util.FirewallBackendNftables:
+	default:
+		err = fmt.Errorf("unsupported firewall backend %q in %s", backend, util.VpcNatConfig)
+		klog.Error(err)
+		return err
+	}
+	vpcNatFirewallBackend = backend
 	return nil
 }
@@ -3,11 +3,11 @@ package controller
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
 	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -23,6 +23,7 @@ import (
 	"k8s.io/klog/v2"
 
 	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/natgw"
 	"github.com/kubeovn/kube-ovn/pkg/util"
 )
 
@@ -33,26 +34,34 @@ var (
 )
 
 const (
-	natGwInit              = "init"
-	natGwEipAdd            = "eip-add"
-	natGwEipDel            = "eip-del"
-	natGwDnatAdd           = "dnat-add"
-	natGwDnatDel           = "dnat-del"
-	natGwSnatAdd           = "snat-add"
-	natGwSnatDel           = "snat-del"
-	natGwEipIngressQoSAdd  = "eip-ingress-qos-add"
-	natGwEipIngressQoSDel  = "eip-ingress-qos-del"
-	QoSAdd                 = "qos-add"
-	QoSDel                 = "qos-del"
-	natGwEipEgressQoSAdd   = "eip-egress-qos-add"
-	natGwEipEgressQoSDel   = "eip-egress-qos-del"
-	natGwSubnetFipAdd      = "floating-ip-add"
-	natGwSubnetFipDel      = "floating-ip-del"
-	natGwSubnetRouteAdd    = "subnet-route-add"
-	natGwSubnetRouteDel    = "subnet-route-del"
-	natGwExtSubnetRouteAdd = "ext-subnet-route-add"
-
-	getIptablesVersion = "get-iptables-version"
+	natGwInit              = natgw.OpInit
+	natGwEipAdd            = natgw.OpEipAdd
+	natGwEipDel            = natgw.OpEipDel
+	natGwDnatAdd           = natgw.OpDnatAdd
+	natGwDnatDel           = natgw.OpDnatDel
+	natGwSnatAdd           = natgw.OpSnatAdd
+	natGwSnatDel           = natgw.OpSnatDel
+	natGwEipIngressQoSAdd  = natgw.OpIngressQoSAdd
+	natGwEipIngressQoSDel  = natgw.OpIngressQoSDel
+	QoSAdd                 = natgw.OpQoSAdd
+	QoSDel                 = natgw.OpQoSDel
+	natGwEipEgressQoSAdd   = natgw.OpEgressQoSAdd
+	natGwEipEgressQoSDel   = natgw.OpEgressQoSDel
+	natGwSubnetFipAdd      = natgw.OpFipAdd
+	natGwSubnetFipDel      = natgw.OpFipDel
+	natGwSubnetRouteAdd    = natgw.OpSubnetRouteAdd
+	natGwSubnetRouteDel    = natgw.OpSubnetRouteDel
+	natGwExtSubnetRouteAdd = natgw.OpExtSubnetRouteAdd
+	natGwHAInit            = natgw.OpHAInit
+	natGwHAActive          = natgw.OpHAActive
+	natGwHAStandby         = natgw.OpHAStandby
+	natGwHairpinAdd        = natgw.OpHairpinAdd
+	natGwHairpinDel        = natgw.OpHairpinDel
+	natGwConntrackSet      = natgw.OpConntrackSet
+	natGwConnLimitAdd      = natgw.OpConnLimitAdd
+	natGwConnLimitDel      = natgw.OpConnLimitDel
+
+	getIptablesVersion = natgw.OpIptablesVersion
 )
 
 func (c *Controller) resyncVpcNatGwConfig() {
@@ -127,6 +136,10 @@ func (c *Controller) enqueueDeleteVpcNatGw(obj interface{}) {
 	}
 	klog.V(3).Infof("enqueue del vpc-nat-gw %s", key)
 	c.delVpcNatGatewayQueue.Add(key)
+	// clean up the routes and bfd sessions to the replicas
+	if gw, ok := obj.(*kubeovnv1.VpcNatGateway); ok && isVpcNatGwHA(gw) {
+		c.addOrUpdateVpcQueue.Add(gw.Spec.Vpc)
+	}
 }
 
 func (c *Controller) runAddOrUpdateVpcNatGwWorker() {
@@ -169,6 +182,11 @@ func (c *Controller) runUpdateVpcSubnetWorker() {
 	}
 }
 
+func (c *Controller) runUpdateVpcNatGwHAWorker() {
+	for c.processNextWorkItem("updateVpcNatGwHA", c.updateVpcNatGwHAQueue, c.handleUpdateVpcNatGwHA) {
+	}
+}
+
 func (c *Controller) processNextWorkItem(processName string, queue workqueue.RateLimitingInterface, handler func(key string) error) bool {
 	obj, shutdown := queue.Get()
 	if shutdown {
@@ -203,6 +221,7 @@ func (c *Controller) handleDelVpcNatGw(key string) error {
 	defer func() { _ = c.vpcNatGwKeyMutex.UnlockKey(key) }()
 	name := util.GenNatGwStsName(key)
 	klog.Infof("delete vpc nat gw %s", name)
+	deleteVpcNatGwMetrics(key)
 	if err := c.config.KubeClient.AppsV1().StatefulSets(c.config.PodNamespace).Delete(context.Background(),
 		name, metav1.DeleteOptions{}); err != nil {
 		if k8serrors.IsNotFound(err) {
@@ -230,6 +249,14 @@ func isVpcNatGwChanged(gw *kubeovnv1.VpcNatGateway) bool {
 		gw.Status.Affinity = gw.Spec.Affinity
 		return true
 	}
+	if gw.Spec.Replicas != gw.Status.Replicas {
+		gw.Status.Replicas = gw.Spec.Replicas
+		return true
+	}
+	if gw.Spec.Mode != gw.Status.Mode {
+		gw.Status.Mode = gw.Spec.Mode
+		return true
+	}
 	return false
 }
 
@@ -304,6 +331,8 @@ func (c *Controller) handleAddOrUpdateVpcNatGw(key string) error {
 			klog.Errorf("failed to patch nat gw sts status for nat gw %s, %v", key, err)
 			return err
 		}
+		// the replicas or mode may be changed, so resync the active replica and vpc routes
+		c.updateVpcNatGwHAQueue.Add(key)
 	default:
 		// check if need to change qos
 		if gw.Spec.QoSPolicy != gw.Status.QoSPolicy {
@@ -330,6 +359,25 @@ func (c *Controller) handleAddOrUpdateVpcNatGw(key string) error {
 				return err
 			}
 		}
+		// check if need to change conntrack
+		if gw.Spec.Conntrack != gw.Status.Conntrack {
+			gwPod, err := c.getNatGwPod(key)
+			if err != nil && !k8serrors.IsNotFound(err) {
+				klog.Errorf("failed to get nat gw %s pod: %v", key, err)
+				return err
+			}
+			// the conntrack settings are applied when the pod is inited
+			if gwPod != nil && gwPod.Annotations[util.VpcNatGatewayInitAnnotation] == "true" {
+				if err = c.execNatGwRules(gwPod, natGwConntrackSet, []string{natGwConntrack(gw.Spec.Conntrack).Rule()}); err != nil {
+					klog.Errorf("failed to set conntrack for nat gw %s, %v", key, err)
+					return err
+				}
+			}
+			if err = c.patchNatGwConntrackStatus(key, gw.Spec.Conntrack); err != nil {
+				klog.Errorf("failed to patch nat gw conntrack status for nat gw %s, %v", key, err)
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -354,14 +402,40 @@ func (c *Controller) handleInitVpcNatGw(key string) error {
 	}
 	// subnet for vpc-nat-gw has been checked when create vpc-nat-gw
 
-	oriPod, err := c.getNatGwPod(key)
+	if !isVpcNatGwHA(gw) {
+		oriPod, err := c.getNatGwPod(key)
+		if err != nil {
+			err := fmt.Errorf("failed to get nat gw %s pod: %v", gw.Name, err)
+			klog.Error(err)
+			return err
+		}
+		return c.initVpcNatGwPod(gw, oriPod)
+	}
+
+	// every replica of the ha nat gw should be inited with all the rules
+	pods, err := c.listNatGwPods(key)
 	if err != nil {
-		err := fmt.Errorf("failed to get nat gw %s pod: %v", gw.Name, err)
+		err := fmt.Errorf("failed to list nat gw %s pods: %v", gw.Name, err)
 		klog.Error(err)
 		return err
 	}
-	pod := oriPod.DeepCopy()
+	if len(pods) == 0 {
+		return k8serrors.NewNotFound(v1.Resource("pod"), key)
+	}
+	for _, pod := range pods {
+		if err = c.initVpcNatGwPod(gw, pod); err != nil {
+			klog.Errorf("failed to init nat gw pod %s: %v", pod.Name, err)
+			return err
+		}
+	}
+	c.updateVpcNatGwHAQueue.Add(key)
+	return nil
+}
 
+func (c *Controller) initVpcNatGwPod(gw *kubeovnv1.VpcNatGateway, oriPod *corev1.Pod) error {
+	var err error
+	key := gw.Name
+	pod := oriPod.DeepCopy()
 	if pod.Status.Phase != corev1.PodRunning {
 		time.Sleep(10 * time.Second)
 		return fmt.Errorf("failed to init vpc nat gateway, pod is not ready")
@@ -372,11 +446,31 @@ func (c *Controller) handleInitVpcNatGw(key string) error {
 	}
 	natGwCreatedAT = pod.CreationTimestamp.Format("2006-01-02T15:04:05")
 	klog.V(3).Infof("nat gw pod '%s' inited at %s", key, natGwCreatedAT)
-	if err = c.execNatGwRules(pod, natGwInit, []string{fmt.Sprintf("%s,%s", c.config.ServiceClusterIPRange, pod.Annotations[util.GatewayAnnotation])}); err != nil {
+	if err = c.execNatGwRulesInPod(pod, natGwInit, []string{fmt.Sprintf("%s,%s", c.config.ServiceClusterIPRange, pod.Annotations[util.GatewayAnnotation])}); err != nil {
 		err = fmt.Errorf("failed to init vpc nat gateway, %v", err)
 		klog.Error(err)
 		return err
 	}
+	if isVpcNatGwHA(gw) {
+		if err = c.execNatGwRulesInPod(pod, natGwHAInit, []string{fmt.Sprintf("%s,%s", gw.Spec.Mode, pod.Annotations[util.GatewayAnnotation])}); err != nil {
+			err = fmt.Errorf("failed to init ha of vpc nat gateway, %v", err)
+			klog.Error(err)
+			return err
+		}
+	}
+
+	if gw.Spec.Conntrack != (kubeovnv1.VpcNatConntrack{}) {
+		if err = c.execNatGwRulesInPod(pod, natGwConntrackSet, []string{natGwConntrack(gw.Spec.Conntrack).Rule()}); err != nil {
+			klog.Errorf("failed to set conntrack for nat gw %s, %v", key, err)
+			return err
+		}
+	}
+	if gw.Spec.Conntrack != gw.Status.Conntrack {
+		if err = c.patchNatGwConntrackStatus(key, gw.Spec.Conntrack); err != nil {
+			klog.Errorf("failed to patch conntrack status for nat gw %s, %v", key, err)
+			return err
+		}
+	}
 
 	if gw.Spec.QoSPolicy != "" {
 		if err = c.execNatGwQoS(gw, gw.Spec.QoSPolicy, QoSAdd); err != nil {
@@ -556,34 +650,16 @@ func (c *Controller) handleUpdateVpcDnat(natGwKey string) error {
 }
 
 func (c *Controller) getIptablesVersion(pod *corev1.Pod) (version string, err error) {
-	operation := getIptablesVersion
-	cmd := fmt.Sprintf("bash /kube-ovn/nat-gateway.sh %s", operation)
-	klog.V(3).Infof(cmd)
-	stdOutput, errOutput, err := util.ExecuteCommandInContainer(c.config.KubeClient, c.config.KubeRestConfig, pod.Namespace, pod.Name, "vpc-nat-gw", []string{"/bin/bash", "-c", cmd}...)
+	output, err := c.natGwAgentClient(pod).Exec(context.Background(), getIptablesVersion, nil)
 	if err != nil {
-		if len(errOutput) > 0 {
-			klog.Errorf("failed to ExecuteCommandInContainer, errOutput: %v", errOutput)
-		}
-		if len(stdOutput) > 0 {
-			klog.V(3).Infof("failed to ExecuteCommandInContainer, stdOutput: %v", stdOutput)
-		}
-		klog.Error(err)
-		return "", err
-	}
-
-	if len(stdOutput) > 0 {
-		klog.V(3).Infof("ExecuteCommandInContainer stdOutput: %v", stdOutput)
-	}
-
-	if len(errOutput) > 0 {
-		klog.Errorf("failed to ExecuteCommandInContainer errOutput: %v", errOutput)
+		klog.Errorf("failed to get iptables version of pod %s: %v", pod.Name, err)
 		return "", err
 	}
 
 	versionMatcher := regexp.MustCompile(`v([0-9]+(\.[0-9]+)+)`)
-	match := versionMatcher.FindStringSubmatch(stdOutput)
+	match := versionMatcher.FindStringSubmatch(output)
 	if match == nil {
-		return "", fmt.Errorf("no iptables version found in string: %s", stdOutput)
+		return "", fmt.Errorf("no iptables version found in string: %s", output)
 	}
 	return match[1], nil
 }
@@ -709,34 +785,25 @@ func (c *Controller) handleUpdateNatGwSubnetRoute(natGwKey string) error {
 	return nil
 }
 
-func (c *Controller) execNatGwRules(pod *corev1.Pod, operation string, rules []string) error {
-	cmd := fmt.Sprintf("bash /kube-ovn/nat-gateway.sh %s %s", operation, strings.Join(rules, " "))
-	klog.V(3).Infof(cmd)
-	stdOutput, errOutput, err := util.ExecuteCommandInContainer(c.config.KubeClient, c.config.KubeRestConfig, pod.Namespace, pod.Name, "vpc-nat-gw", []string{"/bin/bash", "-c", cmd}...)
+// execNatGwRules executes the rules in the nat gw pod, the rules will be synchronized to
+// all the ready replicas if the nat gw runs in ha mode
+func (c *Controller) execNatGwRules(pod *corev1.Pod, operation natgw.Operation, rules []string) error {
+	pods, err := c.getNatGwReplicaPods(pod)
 	if err != nil {
-		if len(errOutput) > 0 {
-			klog.Errorf("failed to ExecuteCommandInContainer, errOutput: %v", errOutput)
-		}
-		if len(stdOutput) > 0 {
-			klog.V(3).Infof("failed to ExecuteCommandInContainer, stdOutput: %v", stdOutput)
-		}
 		klog.Error(err)
 		return err
 	}
-
-	if len(stdOutput) > 0 {
-		klog.V(3).Infof("ExecuteCommandInContainer stdOutput: %v", stdOutput)
-	}
-
-	if len(errOutput) > 0 {
-		klog.Errorf("failed to ExecuteCommandInContainer errOutput: %v", errOutput)
-		return errors.New(errOutput)
+	for _, p := range pods {
+		if err = c.execNatGwRulesInPod(p, operation, rules); err != nil {
+			klog.Errorf("failed to exec nat gateway rules in pod %s: %v", p.Name, err)
+			return err
+		}
 	}
 	return nil
 }
 
 func (c *Controller) genNatGwStatefulSet(gw *kubeovnv1.VpcNatGateway, oldSts *v1.StatefulSet) (newSts *v1.StatefulSet) {
-	replicas := int32(1)
+	replicas := vpcNatGwReplicas(gw)
 	name := util.GenNatGwStsName(gw.Name)
 	allowPrivilegeEscalation := true
 	privileged := true
@@ -758,6 +825,11 @@ func (c *Controller) genNatGwStatefulSet(gw *kubeovnv1.VpcNatGateway, oldSts *v1
 	for key, value := range podAnnotations {
 		newPodAnnotations[key] = value
 	}
+	if isVpcNatGwHA(gw) {
+		// the replicas can not share the same lan ip, the vpc routes to the lan ip will be
+		// redirected to the ip of the active replica or all the ready replicas
+		delete(newPodAnnotations, util.IPAddressAnnotation)
+	}
 
 	selectors := make(map[string]string)
 	for _, v := range gw.Spec.Selector {
@@ -769,13 +841,35 @@ func (c *Controller) genNatGwStatefulSet(gw *kubeovnv1.VpcNatGateway, oldSts *v1
 	}
 	klog.V(3).Infof("prepare for vpc nat gateway pod, node selector: %v", selectors)
 	v4SubnetGw, _, _ := c.GetGwBySubnet(gw.Spec.Subnet)
+	affinity := gw.Spec.Affinity.DeepCopy()
+	podManagementPolicy := v1.OrderedReadyPodManagement
+	if isVpcNatGwHA(gw) {
+		podManagementPolicy = v1.ParallelPodManagement
+		if affinity.PodAntiAffinity == nil {
+			// spread the replicas across nodes so that a node failure only affects one replica
+			affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+					Weight: 100,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{MatchLabels: labels},
+						TopologyKey:   corev1.LabelHostname,
+					},
+				}},
+			}
+		}
+	}
+	if oldSts != nil && oldSts.Spec.PodManagementPolicy != "" {
+		// pod management policy of statefulset is immutable
+		podManagementPolicy = oldSts.Spec.PodManagementPolicy
+	}
 	newSts = &v1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   name,
 			Labels: labels,
 		},
 		Spec: v1.StatefulSetSpec{
-			Replicas: &replicas,
+			Replicas:            &replicas,
+			PodManagementPolicy: podManagementPolicy,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
@@ -789,8 +883,9 @@ func (c *Controller) genNatGwStatefulSet(gw *kubeovnv1.VpcNatGateway, oldSts *v1
 						{
 							Name:            "vpc-nat-gw",
 							Image:           vpcNatImage,
-							Command:         []string{"bash"},
-							Args:            []string{"-c", "while true; do sleep 10000; done"},
+							Command:         []string{"/kube-ovn/nat-gw-agent"},
+							Args:            []string{fmt.Sprintf("--port=%d", natgw.DefaultPort), "--firewall-backend=" + vpcNatFirewallBackend},
+							Env:             []corev1.EnvVar{{Name: "FIREWALL_BACKEND", Value: vpcNatFirewallBackend}},
 							ImagePullPolicy: corev1.PullIfNotPresent,
 							SecurityContext: &corev1.SecurityContext{
 								Privileged:               &privileged,
@@ -804,6 +899,7 @@ func (c *Controller) genNatGwStatefulSet(gw *kubeovnv1.VpcNatGateway, oldSts *v1
 							Image:           vpcNatImage,
 							Command:         []string{"bash"},
 							Args:            []string{"-c", fmt.Sprintf("bash /kube-ovn/nat-gateway.sh init %s,%s", c.config.ServiceClusterIPRange, v4SubnetGw)},
+							Env:             []corev1.EnvVar{{Name: "FIREWALL_BACKEND", Value: vpcNatFirewallBackend}},
 							ImagePullPolicy: corev1.PullIfNotPresent,
 							SecurityContext: &corev1.SecurityContext{
 								Privileged:               &privileged,
@@ -813,7 +909,7 @@ func (c *Controller) genNatGwStatefulSet(gw *kubeovnv1.VpcNatGateway, oldSts *v1
 					},
 					NodeSelector: selectors,
 					Tolerations:  gw.Spec.Tolerations,
-					Affinity:     &gw.Spec.Affinity,
+					Affinity:     affinity,
 				},
 			},
 			UpdateStrategy: v1.StatefulSetUpdateStrategy{
@@ -836,12 +932,22 @@ func (c *Controller) cleanUpVpcNatGw() error {
 	return nil
 }
 
-func (c *Controller) getNatGwPod(name string) (*corev1.Pod, error) {
+func (c *Controller) listNatGwPods(name string) ([]*corev1.Pod, error) {
 	sel, _ := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
 		MatchLabels: map[string]string{"app": util.GenNatGwStsName(name), util.VpcNatGatewayLabel: "true"},
 	})
 
 	pods, err := c.podsLister.Pods(c.config.PodNamespace).List(sel)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	return pods, nil
+}
+
+func (c *Controller) getNatGwPod(name string) (*corev1.Pod, error) {
+	pods, err := c.listNatGwPods(name)
 
 	switch {
 	case err != nil:
@@ -850,6 +956,10 @@ func (c *Controller) getNatGwPod(name string) (*corev1.Pod, error) {
 	case len(pods) == 0:
 		return nil, k8serrors.NewNotFound(v1.Resource("pod"), name)
 	case len(pods) != 1:
+		// the rules are synchronized to all the replicas of the ha nat gw, so return the active one
+		if pod := c.getNatGwActivePod(name, pods); pod != nil {
+			return pod, nil
+		}
 		time.Sleep(5 * time.Second)
 		return nil, fmt.Errorf("too many pod")
 	case pods[0].Status.Phase != "Running":
@@ -958,6 +1068,36 @@ func (c *Controller) patchNatGwQoSStatus(key, qos string) error {
 	return nil
 }
 
+func (c *Controller) patchNatGwConntrackStatus(key string, conntrack kubeovnv1.VpcNatConntrack) error {
+	oriGw, err := c.vpcNatGatewayLister.Get(key)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf("failed to get vpc nat gw %s, %v", key, err)
+		return err
+	}
+	if oriGw.Status.Conntrack == conntrack {
+		return nil
+	}
+	gw := oriGw.DeepCopy()
+	gw.Status.Conntrack = conntrack
+	bytes, err := gw.Status.Bytes()
+	if err != nil {
+		klog.Errorf("failed to marshal vpc nat gw %s status, %v", gw.Name, err)
+		return err
+	}
+	if _, err = c.config.KubeOvnClient.KubeovnV1().VpcNatGateways().Patch(context.Background(), gw.Name, types.MergePatchType,
+		bytes, metav1.PatchOptions{}, "status"); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf("failed to patch gw %s, %v", gw.Name, err)
+		return err
+	}
+	return nil
+}
+
 func (c *Controller) patchNatGwStatus(key string) error {
 	var changed bool
 	oriGw, err := c.vpcNatGatewayLister.Get(key)
@@ -986,6 +1126,14 @@ func (c *Controller) patchNatGwStatus(key string) error {
 		gw.Status.Affinity = gw.Spec.Affinity
 		changed = true
 	}
+	if gw.Spec.Replicas != gw.Status.Replicas {
+		gw.Status.Replicas = gw.Spec.Replicas
+		changed = true
+	}
+	if gw.Spec.Mode != gw.Status.Mode {
+		gw.Status.Mode = gw.Spec.Mode
+		changed = true
+	}
 
 	if changed {
 		bytes, err := gw.Status.Bytes()
@@ -1005,7 +1153,7 @@ func (c *Controller) patchNatGwStatus(key string) error {
 	return nil
 }
 
-func (c *Controller) execNatGwQoS(gw *kubeovnv1.VpcNatGateway, qos, operation string) error {
+func (c *Controller) execNatGwQoS(gw *kubeovnv1.VpcNatGateway, qos string, operation natgw.Operation) error {
 	qosPolicy, err := c.qosPoliciesLister.Get(qos)
 	if err != nil {
 		klog.Errorf("get qos policy %s failed: %v", qos, err)
@@ -1024,7 +1172,7 @@ func (c *Controller) execNatGwQoS(gw *kubeovnv1.VpcNatGateway, qos, operation st
 	return c.execNatGwBandtithLimitRules(gw, qosPolicy.Status.BandwidthLimitRules, operation)
 }
 
-func (c *Controller) execNatGwBandtithLimitRules(gw *kubeovnv1.VpcNatGateway, rules kubeovnv1.QoSPolicyBandwidthLimitRules, operation string) error {
+func (c *Controller) execNatGwBandtithLimitRules(gw *kubeovnv1.VpcNatGateway, rules kubeovnv1.QoSPolicyBandwidthLimitRules, operation natgw.Operation) error {
 	var err error
 	for _, rule := range rules {
 		if err = c.execNatGwQoSInPod(gw.Name, rule, operation); err != nil {
@@ -1036,7 +1184,7 @@ func (c *Controller) execNatGwBandtithLimitRules(gw *kubeovnv1.VpcNatGateway, ru
 }
 
 func (c *Controller) execNatGwQoSInPod(
-	dp string, r *kubeovnv1.QoSPolicyBandwidthLimitRule, operation string,
+	dp string, r *kubeovnv1.QoSPolicyBandwidthLimitRule, operation natgw.Operation,
 ) error {
 	gwPod, err := c.getNatGwPod(dp)
 	if err != nil {
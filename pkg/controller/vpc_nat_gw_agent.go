@@ -0,0 +1,269 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/natgw"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+const (
+	natGwAgentTimeout      = 30 * time.Second
+	natGwStateSyncInterval = time.Minute
+)
+
+// natGwAgentClient returns the client of the agent running in the nat gw pod,
+// the agent is accessed through port forwarding since the pod may be unreachable from the controller
+func (c *Controller) natGwAgentClient(pod *corev1.Pod) *natgw.Client {
+	httpClient := &http.Client{
+		Timeout: natGwAgentTimeout,
+		Transport: &http.Transport{
+			DialContext:       util.PodPortForwardDialer(c.config.KubeClient, c.config.KubeRestConfig, pod.Namespace, pod.Name, natgw.DefaultPort),
+			DisableKeepAlives: true,
+		},
+	}
+	return natgw.NewClient("http://"+util.JoinHostPort("127.0.0.1", natgw.DefaultPort), httpClient)
+}
+
+func (c *Controller) execNatGwRulesInPod(pod *corev1.Pod, operation natgw.Operation, rules []string) error {
+	klog.V(3).Infof("exec nat gateway rules in pod %s: %s %s", pod.Name, operation, strings.Join(rules, " "))
+	output, err := c.natGwAgentClient(pod).Exec(context.Background(), operation, rules)
+	if err != nil {
+		klog.Errorf("failed to exec nat gateway rules %s %v in pod %s: %v", operation, rules, pod.Name, err)
+		return err
+	}
+	if len(output) > 0 {
+		klog.V(3).Infof("nat gateway rules output: %v", output)
+	}
+	return nil
+}
+
+// genNatGwState generates the desired state of the nat gw from the eip, fip, dnat and snat resources
+func (c *Controller) genNatGwState(gwName string) (*natgw.State, error) {
+	state := &natgw.State{}
+	eips, err := c.iptablesEipsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list iptables eips: %v", err)
+		return nil, err
+	}
+	eipIPs := make(map[string]string, len(eips))
+	for _, eip := range eips {
+		if eip.Spec.NatGwDp != gwName || eip.Status.IP == "" || eip.DeletionTimestamp != nil {
+			continue
+		}
+		eipIPs[eip.Name] = eip.Status.IP
+		externalNetwork := util.GetExternalNetwork(eip.Spec.ExternalSubnet)
+		v4Cidr, err := c.getEipV4Cidr(eip.Status.IP, externalNetwork)
+		if err != nil {
+			klog.Error(err)
+			return nil, err
+		}
+		v4Gw, v6Gw, err := c.GetGwBySubnet(externalNetwork)
+		if err != nil {
+			klog.Error(err)
+			return nil, err
+		}
+		state.EIPs = append(state.EIPs, natgw.EIP{Address: v4Cidr, Gateway: v4Gw})
+		if eip.Spec.V6ip != "" {
+			v6Cidr, err := c.getEipV6Cidr(eip.Spec.V6ip, externalNetwork)
+			if err != nil {
+				klog.Error(err)
+				return nil, err
+			}
+			state.EIPs = append(state.EIPs, natgw.EIP{Address: v6Cidr, Gateway: v6Gw})
+		}
+
+		qos, err := c.genNatGwEipQoS(eip)
+		if err != nil {
+			klog.Error(err)
+			return nil, err
+		}
+		state.QoS = append(state.QoS, qos...)
+		if eip.Spec.Hairpin {
+			state.Hairpins = append(state.Hairpins, natgw.Hairpin{EIP: eip.Status.IP})
+		}
+		if eip.Spec.ConnLimit > 0 {
+			state.ConnLimits = append(state.ConnLimits, natgw.ConnLimit{EIP: eip.Status.IP, Limit: int(eip.Spec.ConnLimit)})
+		}
+	}
+
+	fips, err := c.iptablesFipsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list iptables fips: %v", err)
+		return nil, err
+	}
+	for _, fip := range fips {
+		if v4ip := eipIPs[fip.Spec.EIP]; v4ip != "" && fip.DeletionTimestamp == nil {
+			state.FloatingIPs = append(state.FloatingIPs, natgw.FloatingIP{EIP: v4ip, InternalIP: fip.Spec.InternalIP})
+		}
+	}
+
+	dnats, err := c.iptablesDnatRulesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list iptables dnats: %v", err)
+		return nil, err
+	}
+	for _, dnat := range dnats {
+		if v4ip := eipIPs[dnat.Spec.EIP]; v4ip != "" && dnat.DeletionTimestamp == nil {
+			rules, err := natgw.DNAT{
+				EIP:          v4ip,
+				Protocol:     dnat.Spec.Protocol,
+				ExternalPort: dnat.Spec.ExternalPort,
+				InternalIP:   dnat.Spec.InternalIP,
+				InternalPort: dnat.Spec.InternalPort,
+			}.Expand()
+			if err != nil {
+				klog.Errorf("failed to generate rules of iptables dnat %s: %v", dnat.Name, err)
+				continue
+			}
+			state.DNATs = append(state.DNATs, rules...)
+		}
+	}
+
+	snats, err := c.iptablesSnatRulesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list iptables snats: %v", err)
+		return nil, err
+	}
+	for _, snat := range snats {
+		if v4ip := eipIPs[snat.Spec.EIP]; v4ip != "" && snat.DeletionTimestamp == nil {
+			state.SNATs = append(state.SNATs, natgw.SNAT{EIP: v4ip, InternalCIDR: snat.Spec.InternalCIDR})
+		}
+	}
+	return state, nil
+}
+
+// natGwConntrack converts the conntrack settings of the nat gw to the ones programmed by the agent
+func natGwConntrack(conntrack kubeovnv1.VpcNatConntrack) *natgw.Conntrack {
+	return &natgw.Conntrack{
+		Max:                   int(conntrack.Max),
+		TCPEstablishedTimeout: int(conntrack.TCPEstablishedTimeout),
+		TCPTimeWaitTimeout:    int(conntrack.TCPTimeWaitTimeout),
+		UDPTimeout:            int(conntrack.UDPTimeout),
+		UDPStreamTimeout:      int(conntrack.UDPStreamTimeout),
+	}
+}
+
+func (c *Controller) genNatGwEipQoS(eip *kubeovnv1.IptablesEIP) ([]natgw.EIPQoS, error) {
+	if eip.Status.QoSPolicy == "" {
+		return nil, nil
+	}
+	qosPolicy, err := c.qosPoliciesLister.Get(eip.Status.QoSPolicy)
+	if err != nil {
+		klog.Errorf("failed to get qos policy %s: %v", eip.Status.QoSPolicy, err)
+		return nil, err
+	}
+	qos := make([]natgw.EIPQoS, 0, len(qosPolicy.Status.BandwidthLimitRules))
+	for _, rule := range qosPolicy.Status.BandwidthLimitRules {
+		qos = append(qos, natgw.EIPQoS{
+			EIP:       eip.Status.IP,
+			Direction: string(rule.Direction),
+			Priority:  rule.Priority,
+			Rate:      rule.RateMax,
+			Burst:     rule.BurstMax,
+		})
+	}
+	return qos, nil
+}
+
+// syncVpcNatGwState synchronizes the full state of the nat gws periodically,
+// the rules drifted from the resources are corrected by the agents
+func (c *Controller) syncVpcNatGwState() {
+	if vpcNatEnabled != "true" {
+		return
+	}
+	gws, err := c.vpcNatGatewayLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list vpc nat gws: %v", err)
+		return
+	}
+	for _, gw := range gws {
+		if err = c.syncNatGwStateInPods(gw); err != nil {
+			klog.Errorf("failed to sync state of vpc nat gw %s: %v", gw.Name, err)
+		}
+	}
+}
+
+func (c *Controller) syncNatGwStateInPods(gw *kubeovnv1.VpcNatGateway) error {
+	c.vpcNatGwKeyMutex.LockKey(gw.Name)
+	defer func() { _ = c.vpcNatGwKeyMutex.UnlockKey(gw.Name) }()
+
+	pods, err := c.listNatGwPods(gw.Name)
+	if err != nil {
+		klog.Errorf("failed to list pods of vpc nat gw %s: %v", gw.Name, err)
+		return err
+	}
+	state, err := c.genNatGwState(gw.Name)
+	if err != nil {
+		klog.Errorf("failed to generate state of vpc nat gw %s: %v", gw.Name, err)
+		return err
+	}
+	state.Conntrack = natGwConntrack(gw.Spec.Conntrack)
+
+	// reset the counters so that the ones of the deleted rules and pods are removed
+	metricVpcNatGwRulePackets.DeletePartialMatch(prometheus.Labels{"vpc_nat_gw": gw.Name})
+	metricVpcNatGwRuleBytes.DeletePartialMatch(prometheus.Labels{"vpc_nat_gw": gw.Name})
+	deleteVpcNatGwConntrackMetrics(gw.Name)
+	for _, pod := range pods {
+		// the rules are replayed by the init process, skip the pods not initialized
+		if !isNatGwPodReady(pod) || pod.Annotations[util.VpcNatGatewayInitAnnotation] != "true" {
+			continue
+		}
+		resp, err := c.natGwAgentClient(pod).Sync(context.Background(), state, false)
+		if err != nil {
+			klog.Errorf("failed to sync state of vpc nat gw pod %s: %v", pod.Name, err)
+			return err
+		}
+		if len(resp.Drifts) != 0 {
+			drifts := make([]string, 0, len(resp.Drifts))
+			for _, drift := range resp.Drifts {
+				metricVpcNatGwRuleDrifts.WithLabelValues(gw.Name, drift.Kind, drift.Reason).Inc()
+				drifts = append(drifts, drift.String())
+			}
+			klog.Warningf("corrected %d drifted rules of vpc nat gw pod %s: %s", len(drifts), pod.Name, strings.Join(drifts, "; "))
+			c.recorder.Eventf(gw, corev1.EventTypeWarning, "RuleDrift", "corrected %d drifted rules in pod %s", len(drifts), pod.Name)
+		}
+
+		for _, counter := range resp.Counters {
+			metricVpcNatGwRulePackets.WithLabelValues(gw.Name, pod.Name, counter.Kind, counter.Rule).Set(float64(counter.Packets))
+			metricVpcNatGwRuleBytes.WithLabelValues(gw.Name, pod.Name, counter.Kind, counter.Rule).Set(float64(counter.Bytes))
+		}
+		if ct := resp.Conntrack; ct != nil {
+			metricVpcNatGwConntrackEntries.WithLabelValues(gw.Name, pod.Name).Set(float64(ct.Entries))
+			metricVpcNatGwConntrackMax.WithLabelValues(gw.Name, pod.Name).Set(float64(ct.Max))
+			metricVpcNatGwConntrackDrops.WithLabelValues(gw.Name, pod.Name).Set(float64(ct.Drops))
+			metricVpcNatGwConntrackLimitDrops.WithLabelValues(gw.Name, pod.Name).Set(float64(ct.LimitDrops))
+			for _, eip := range ct.EIPs {
+				metricVpcNatGwEipConntrackEntries.WithLabelValues(gw.Name, pod.Name, eip.EIP).Set(float64(eip.Entries))
+				metricVpcNatGwEipConntrackLimitDrops.WithLabelValues(gw.Name, pod.Name, eip.EIP).Set(float64(eip.LimitDrops))
+			}
+		}
+	}
+	return nil
+}
+
+func deleteVpcNatGwConntrackMetrics(gwName string) {
+	labels := prometheus.Labels{"vpc_nat_gw": gwName}
+	metricVpcNatGwConntrackEntries.DeletePartialMatch(labels)
+	metricVpcNatGwConntrackMax.DeletePartialMatch(labels)
+	metricVpcNatGwConntrackDrops.DeletePartialMatch(labels)
+	metricVpcNatGwConntrackLimitDrops.DeletePartialMatch(labels)
+	metricVpcNatGwEipConntrackEntries.DeletePartialMatch(labels)
+	metricVpcNatGwEipConntrackLimitDrops.DeletePartialMatch(labels)
+}
+
+func deleteVpcNatGwMetrics(gwName string) {
+	metricVpcNatGwRulePackets.DeletePartialMatch(prometheus.Labels{"vpc_nat_gw": gwName})
+	metricVpcNatGwRuleBytes.DeletePartialMatch(prometheus.Labels{"vpc_nat_gw": gwName})
+	metricVpcNatGwRuleDrifts.DeletePartialMatch(prometheus.Labels{"vpc_nat_gw": gwName})
+	deleteVpcNatGwConntrackMetrics(gwName)
+}
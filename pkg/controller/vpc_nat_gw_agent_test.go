@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	kubeovnlister "github.com/kubeovn/kube-ovn/pkg/client/listers/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/ipam"
+	"github.com/kubeovn/kube-ovn/pkg/natgw"
+)
+
+func Test_genNatGwState(t *testing.T) {
+	t.Parallel()
+
+	newIndexer := func(objs ...interface{}) cache.Indexer {
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		for _, obj := range objs {
+			require.NoError(t, indexer.Add(obj))
+		}
+		return indexer
+	}
+
+	c := &Controller{ipam: ipam.NewIPAM()}
+	require.NoError(t, c.ipam.AddOrUpdateSubnet("external", "172.18.0.0/16,fc00:f853:ccd:e793::/64", "172.18.0.1,fc00:f853:ccd:e793::1", nil))
+	c.iptablesEipsLister = kubeovnlister.NewIptablesEIPLister(newIndexer(
+		&kubeovnv1.IptablesEIP{
+			ObjectMeta: metav1.ObjectMeta{Name: "eip1"},
+			Spec:       kubeovnv1.IptablesEipSpec{NatGwDp: "gw1", V4ip: "172.18.0.10", V6ip: "fc00:f853:ccd:e793::10", ExternalSubnet: "external"},
+			Status:     kubeovnv1.IptablesEipStatus{IP: "172.18.0.10"},
+		},
+		&kubeovnv1.IptablesEIP{
+			ObjectMeta: metav1.ObjectMeta{Name: "eip2"},
+			Spec:       kubeovnv1.IptablesEipSpec{NatGwDp: "gw1", V4ip: "172.18.0.11", ExternalSubnet: "external"},
+			Status:     kubeovnv1.IptablesEipStatus{IP: "172.18.0.11"},
+		},
+		&kubeovnv1.IptablesEIP{
+			ObjectMeta: metav1.ObjectMeta{Name: "eip3"},
+			Spec:       kubeovnv1.IptablesEipSpec{NatGwDp: "gw2", V4ip: "172.18.0.12", ExternalSubnet: "external"},
+			Status:     kubeovnv1.IptablesEipStatus{IP: "172.18.0.12"},
+		},
+	))
+	c.iptablesFipsLister = kubeovnlister.NewIptablesFIPRuleLister(newIndexer(&kubeovnv1.IptablesFIPRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "fip1"},
+		Spec:       kubeovnv1.IptablesFIPRuleSpec{EIP: "eip1", InternalIP: "10.0.1.5"},
+	}))
+	c.iptablesDnatRulesLister = kubeovnlister.NewIptablesDnatRuleLister(newIndexer())
+	c.iptablesSnatRulesLister = kubeovnlister.NewIptablesSnatRuleLister(newIndexer(&kubeovnv1.IptablesSnatRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "snat1"},
+		Spec:       kubeovnv1.IptablesSnatRuleSpec{EIP: "eip3", InternalCIDR: "10.0.2.0/24"},
+	}))
+
+	state, err := c.genNatGwState("gw1")
+	require.NoError(t, err)
+	// the order of the eips listed is not stable
+	require.ElementsMatch(t, []natgw.EIP{
+		{Address: "172.18.0.10/16", Gateway: "172.18.0.1"},
+		{Address: "fc00:f853:ccd:e793::10/64", Gateway: "fc00:f853:ccd:e793::1"},
+		{Address: "172.18.0.11/16", Gateway: "172.18.0.1"},
+	}, state.EIPs)
+	require.Equal(t, []natgw.FloatingIP{{EIP: "172.18.0.10", InternalIP: "10.0.1.5"}}, state.FloatingIPs)
+	require.Empty(t, state.SNATs)
+}
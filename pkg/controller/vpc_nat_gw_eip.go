@@ -18,6 +18,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/natgw"
 	"github.com/kubeovn/kube-ovn/pkg/ovs"
 	"github.com/kubeovn/kube-ovn/pkg/util"
 )
@@ -44,7 +45,9 @@ func (c *Controller) enqueueUpdateIptablesEip(oldObj, newObj interface{}) {
 	newEip := newObj.(*kubeovnv1.IptablesEIP)
 	if !newEip.DeletionTimestamp.IsZero() ||
 		oldEip.Status.Redo != newEip.Status.Redo ||
-		oldEip.Spec.QoSPolicy != newEip.Spec.QoSPolicy {
+		oldEip.Spec.QoSPolicy != newEip.Spec.QoSPolicy ||
+		oldEip.Spec.Hairpin != newEip.Spec.Hairpin ||
+		oldEip.Spec.ConnLimit != newEip.Spec.ConnLimit {
 		klog.Infof("enqueue update iptables eip %s", key)
 		c.updateIptablesEipQueue.Add(key)
 	}
@@ -232,7 +235,7 @@ func (c *Controller) handleAddIptablesEip(key string) error {
 		}
 	} else {
 		// Random allocate
-		if v4ip, v6ip, mac, err = c.acquireEip(cachedEip.Name, cachedEip.Namespace, portName, externalNetwork); err != nil {
+		if v4ip, v6ip, mac, err = c.acquireEip(cachedEip.Name, cachedEip.Namespace, portName, externalNetwork, cachedEip.Spec.IPPool); err != nil {
 			klog.Errorf("failed to allocate eip, err: %v", err)
 			return err
 		}
@@ -249,6 +252,12 @@ func (c *Controller) handleAddIptablesEip(key string) error {
 		klog.Errorf("failed to create eip '%s' in pod, %v", key, err)
 		return err
 	}
+	if v6ip != "" {
+		if err = c.createEipV6InPod(cachedEip.Spec.NatGwDp, v6ip, externalNetwork); err != nil {
+			klog.Errorf("failed to create ipv6 eip '%s' in pod, %v", key, err)
+			return err
+		}
+	}
 
 	if cachedEip.Spec.QoSPolicy != "" {
 		if err = c.addEipQoS(cachedEip, v4ip); err != nil {
@@ -256,10 +265,24 @@ func (c *Controller) handleAddIptablesEip(key string) error {
 			return err
 		}
 	}
+	if cachedEip.Spec.Hairpin {
+		if err = c.setEipHairpinInPod(cachedEip.Spec.NatGwDp, v4ip, true); err != nil {
+			klog.Errorf("failed to add hairpin of eip '%s' in pod, %v", key, err)
+			return err
+		}
+	}
+	if cachedEip.Spec.ConnLimit > 0 {
+		if err = c.setEipConnLimitInPod(cachedEip.Spec.NatGwDp, v4ip, cachedEip.Spec.ConnLimit); err != nil {
+			klog.Errorf("failed to add connection limit of eip '%s' in pod, %v", key, err)
+			return err
+		}
+	}
 	if err = c.createOrUpdateEipCR(key, v4ip, v6ip, mac, cachedEip.Spec.NatGwDp, cachedEip.Spec.QoSPolicy, externalNetwork); err != nil {
 		klog.Errorf("failed to update eip %s, %v", key, err)
 		return err
 	}
+	// share out the eip among the replicas of the ecmp nat gw
+	c.updateVpcNatGwHAQueue.Add(cachedEip.Spec.NatGwDp)
 	return nil
 }
 
@@ -306,10 +329,33 @@ func (c *Controller) handleUpdateIptablesEip(key string) error {
 			return err
 		}
 		if vpcNatEnabled == "true" {
+			if cachedEip.Status.Hairpin {
+				if err = c.setEipHairpinInPod(cachedEip.Spec.NatGwDp, cachedEip.Status.IP, false); err != nil {
+					klog.Errorf("failed to clean hairpin of eip '%s' in pod, %v", key, err)
+					return err
+				}
+			}
+			if cachedEip.Status.ConnLimit > 0 {
+				if err = c.setEipConnLimitInPod(cachedEip.Spec.NatGwDp, cachedEip.Status.IP, 0); err != nil {
+					klog.Errorf("failed to clean connection limit of eip '%s' in pod, %v", key, err)
+					return err
+				}
+			}
 			if err = c.deleteEipInPod(cachedEip.Spec.NatGwDp, v4Cidr); err != nil {
 				klog.Errorf("failed to clean eip '%s' in pod, %v", key, err)
 				return err
 			}
+			if cachedEip.Spec.V6ip != "" {
+				v6Cidr, err := c.getEipV6Cidr(cachedEip.Spec.V6ip, externalNetwork)
+				if err != nil {
+					klog.Errorf("failed to clean ipv6 eip %s, %v", key, err)
+					return err
+				}
+				if err = c.deleteEipInPod(cachedEip.Spec.NatGwDp, v6Cidr); err != nil {
+					klog.Errorf("failed to clean ipv6 eip '%s' in pod, %v", key, err)
+					return err
+				}
+			}
 		}
 		if cachedEip.Status.QoSPolicy != "" {
 			if err = c.delEipQoS(cachedEip, cachedEip.Status.IP); err != nil {
@@ -322,6 +368,7 @@ func (c *Controller) handleUpdateIptablesEip(key string) error {
 			return err
 		}
 		c.ipam.ReleaseAddressByPod(key, cachedEip.Spec.ExternalSubnet)
+		c.updateVpcNatGwHAQueue.Add(cachedEip.Spec.NatGwDp)
 		return nil
 	}
 	klog.Infof("handle update eip %s", key)
@@ -364,6 +411,30 @@ func (c *Controller) handleUpdateIptablesEip(key string) error {
 		}
 	}
 
+	// update hairpin
+	if cachedEip.Status.Hairpin != cachedEip.Spec.Hairpin && cachedEip.Status.IP != "" {
+		if err = c.setEipHairpinInPod(cachedEip.Spec.NatGwDp, cachedEip.Status.IP, cachedEip.Spec.Hairpin); err != nil {
+			klog.Errorf("failed to update hairpin of eip '%s' in pod, %v", key, err)
+			return err
+		}
+		if err = c.patchEipHairpinStatus(key, cachedEip.Spec.Hairpin); err != nil {
+			klog.Errorf("failed to patch hairpin status for eip %s, %v", key, err)
+			return err
+		}
+	}
+
+	// update connection limit
+	if cachedEip.Status.ConnLimit != cachedEip.Spec.ConnLimit && cachedEip.Status.IP != "" {
+		if err = c.setEipConnLimitInPod(cachedEip.Spec.NatGwDp, cachedEip.Status.IP, cachedEip.Spec.ConnLimit); err != nil {
+			klog.Errorf("failed to update connection limit of eip '%s' in pod, %v", key, err)
+			return err
+		}
+		if err = c.patchEipConnLimitStatus(key, cachedEip.Spec.ConnLimit); err != nil {
+			klog.Errorf("failed to patch connection limit status for eip %s, %v", key, err)
+			return err
+		}
+	}
+
 	// redo
 	if !cachedEip.Status.Ready &&
 		cachedEip.Status.Redo != "" &&
@@ -402,6 +473,18 @@ func (c *Controller) handleUpdateIptablesEip(key string) error {
 				return err
 			}
 		}
+		if cachedEip.Spec.Hairpin {
+			if err = c.setEipHairpinInPod(cachedEip.Spec.NatGwDp, cachedEip.Status.IP, true); err != nil {
+				klog.Errorf("failed to add hairpin of eip '%s' in pod, %v", key, err)
+				return err
+			}
+		}
+		if cachedEip.Spec.ConnLimit > 0 {
+			if err = c.setEipConnLimitInPod(cachedEip.Spec.NatGwDp, cachedEip.Status.IP, cachedEip.Spec.ConnLimit); err != nil {
+				klog.Errorf("failed to add connection limit of eip '%s' in pod, %v", key, err)
+				return err
+			}
+		}
 
 		if err = c.patchEipStatus(key, "", "", cachedEip.Spec.QoSPolicy, true); err != nil {
 			klog.Errorf("failed to patch status for eip %s, %v", key, err)
@@ -445,6 +528,20 @@ func (c *Controller) createEipInPod(dp, gw, v4Cidr string) error {
 	return c.execNatGwRules(gwPod, natGwEipAdd, addRules)
 }
 
+func (c *Controller) createEipV6InPod(dp, v6ip, externalNetwork string) error {
+	v6Cidr, err := c.getEipV6Cidr(v6ip, externalNetwork)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	_, v6Gw, err := c.GetGwBySubnet(externalNetwork)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	return c.createEipInPod(dp, v6Gw, v6Cidr)
+}
+
 func (c *Controller) deleteEipInPod(dp, v4Cidr string) error {
 	gwPod, err := c.getNatGwPod(dp)
 	if err != nil {
@@ -464,6 +561,50 @@ func (c *Controller) deleteEipInPod(dp, v4Cidr string) error {
 	return nil
 }
 
+// setEipHairpinInPod enables or disables the hairpin nat of the eip in the nat gw pod,
+// so that the connections from the vpc to the eip return through the nat gw
+func (c *Controller) setEipHairpinInPod(dp, v4ip string, enable bool) error {
+	gwPod, err := c.getNatGwPod(dp)
+	if err != nil {
+		if !enable && k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Error(err)
+		return err
+	}
+	operation := natGwHairpinAdd
+	if !enable {
+		operation = natGwHairpinDel
+	}
+	if err = c.execNatGwRules(gwPod, operation, []string{v4ip}); err != nil {
+		klog.Error(err)
+		return err
+	}
+	return nil
+}
+
+// setEipConnLimitInPod limits the concurrent connections to the eip in the nat gw pod,
+// the connection limit is removed if the limit is 0
+func (c *Controller) setEipConnLimitInPod(dp, v4ip string, limit int32) error {
+	gwPod, err := c.getNatGwPod(dp)
+	if err != nil {
+		if limit == 0 && k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Error(err)
+		return err
+	}
+	operation, rule := natGwConnLimitAdd, fmt.Sprintf("%s,%d", v4ip, limit)
+	if limit == 0 {
+		operation, rule = natGwConnLimitDel, v4ip
+	}
+	if err = c.execNatGwRules(gwPod, operation, []string{rule}); err != nil {
+		klog.Error(err)
+		return err
+	}
+	return nil
+}
+
 func (c *Controller) addOrUpdateEIPBandtithLimitRules(eip *kubeovnv1.IptablesEIP, v4ip string, rules kubeovnv1.QoSPolicyBandwidthLimitRules) error {
 	var err error
 	for _, rule := range rules {
@@ -535,7 +676,7 @@ func (c *Controller) addEipQoSInPod(
 	dp, v4ip string, direction kubeovnv1.QoSPolicyRuleDirection, priority int, rate string,
 	burst string,
 ) error {
-	var operation string
+	var operation natgw.Operation
 	gwPod, err := c.getNatGwPod(dp)
 	if err != nil {
 		klog.Error(err)
@@ -556,7 +697,7 @@ func (c *Controller) addEipQoSInPod(
 }
 
 func (c *Controller) delEipQoSInPod(dp, v4ip string, direction kubeovnv1.QoSPolicyRuleDirection) error {
-	var operation string
+	var operation natgw.Operation
 	gwPod, err := c.getNatGwPod(dp)
 	if err != nil {
 		klog.Error(err)
@@ -592,10 +733,10 @@ func (c *Controller) acquireStaticEip(name, _, nicName, ip, externalSubnet strin
 	return v4ip, v6ip, mac, nil
 }
 
-func (c *Controller) acquireEip(name, _, nicName, externalSubnet string) (string, string, string, error) {
+func (c *Controller) acquireEip(name, _, nicName, externalSubnet, ipPool string) (string, string, string, error) {
 	var skippedAddrs []string
 	for {
-		ipv4, ipv6, mac, err := c.ipam.GetRandomAddress(name, nicName, nil, externalSubnet, "", skippedAddrs, true)
+		ipv4, ipv6, mac, err := c.ipam.GetRandomAddress(name, nicName, nil, externalSubnet, ipPool, skippedAddrs, true)
 		if err != nil {
 			klog.Error(err)
 			return "", "", "", err
@@ -639,6 +780,15 @@ func (c *Controller) getEipV4Cidr(v4ip, externalSubnet string) (string, error) {
 	return v4IpCidr, nil
 }
 
+func (c *Controller) getEipV6Cidr(v6ip, externalSubnet string) (string, error) {
+	extSubnetMask, err := c.ipam.GetSubnetV6Mask(externalSubnet)
+	if err != nil {
+		klog.Errorf("failed to get eip '%s' mask from subnet %s, %v", v6ip, externalSubnet, err)
+		return "", err
+	}
+	return fmt.Sprintf("%s/%s", v6ip, extSubnetMask), nil
+}
+
 func (c *Controller) GetGwBySubnet(name string) (string, string, error) {
 	if subnet, ok := c.ipam.Subnets[name]; ok {
 		return subnet.V4Gw, subnet.V6Gw, nil
@@ -701,6 +851,8 @@ func (c *Controller) createOrUpdateEipCR(key, v4ip, v6ip, mac, natGwDp, qos, ext
 			}
 			eip.Status.Ready = true
 			eip.Status.QoSPolicy = qos
+			eip.Status.Hairpin = eip.Spec.Hairpin
+			eip.Status.ConnLimit = eip.Spec.ConnLimit
 			bytes, err := eip.Status.Bytes()
 			if err != nil {
 				klog.Error(err)
@@ -848,6 +1000,66 @@ func (c *Controller) patchEipQoSStatus(key, qos string) error {
 	return nil
 }
 
+func (c *Controller) patchEipHairpinStatus(key string, hairpin bool) error {
+	oriEip, err := c.iptablesEipsLister.Get(key)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Error(err)
+		return err
+	}
+	if oriEip.Status.Hairpin == hairpin {
+		return nil
+	}
+	eip := oriEip.DeepCopy()
+	eip.Status.Hairpin = hairpin
+	bytes, err := eip.Status.Bytes()
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err = c.config.KubeOvnClient.KubeovnV1().IptablesEIPs().Patch(context.Background(), key, types.MergePatchType,
+		bytes, metav1.PatchOptions{}, "status"); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf("failed to patch eip %s, %v", eip.Name, err)
+		return err
+	}
+	return nil
+}
+
+func (c *Controller) patchEipConnLimitStatus(key string, limit int32) error {
+	oriEip, err := c.iptablesEipsLister.Get(key)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Error(err)
+		return err
+	}
+	if oriEip.Status.ConnLimit == limit {
+		return nil
+	}
+	eip := oriEip.DeepCopy()
+	eip.Status.ConnLimit = limit
+	bytes, err := eip.Status.Bytes()
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err = c.config.KubeOvnClient.KubeovnV1().IptablesEIPs().Patch(context.Background(), key, types.MergePatchType,
+		bytes, metav1.PatchOptions{}, "status"); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf("failed to patch eip %s, %v", eip.Name, err)
+		return err
+	}
+	return nil
+}
+
 func (c *Controller) getIptablesEipNat(eipV4IP string) (string, error) {
 	nats := make([]string, 0, 3)
 	selector := labels.SelectorFromSet(labels.Set{util.EipV4IpLabel: eipV4IP})
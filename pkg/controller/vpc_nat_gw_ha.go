@@ -0,0 +1,430 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/ovs"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+func vpcNatGwReplicas(gw *kubeovnv1.VpcNatGateway) int32 {
+	if gw.Spec.Replicas <= 0 {
+		return 1
+	}
+	return gw.Spec.Replicas
+}
+
+func isVpcNatGwHA(gw *kubeovnv1.VpcNatGateway) bool {
+	return gw.Spec.Mode != "" && vpcNatGwReplicas(gw) > 1
+}
+
+func isNatGwPodReady(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// natGwPodFailedAt returns the time when the replica became unavailable
+func natGwPodFailedAt(pod *corev1.Pod) time.Time {
+	if pod == nil {
+		return time.Now()
+	}
+	if pod.DeletionTimestamp != nil {
+		return pod.DeletionTimestamp.Time
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status != corev1.ConditionTrue {
+			return cond.LastTransitionTime.Time
+		}
+	}
+	return time.Now()
+}
+
+// getNatGwActivePod returns the active replica of the ha nat gw,
+// the first ready replica is returned if the active one is not elected yet
+func (c *Controller) getNatGwActivePod(name string, pods []*corev1.Pod) *corev1.Pod {
+	gw, err := c.vpcNatGatewayLister.Get(name)
+	if err != nil || !isVpcNatGwHA(gw) {
+		return nil
+	}
+
+	var active *corev1.Pod
+	for _, pod := range pods {
+		if !isNatGwPodReady(pod) {
+			continue
+		}
+		if pod.Name == gw.Status.ActivePod {
+			return pod
+		}
+		if active == nil {
+			active = pod
+		}
+	}
+	return active
+}
+
+// getNatGwReplicaPods returns the ready replicas of the nat gw which the pod belongs to
+func (c *Controller) getNatGwReplicaPods(pod *corev1.Pod) ([]*corev1.Pod, error) {
+	name := pod.Annotations[util.VpcNatGatewayAnnotation]
+	if name == "" {
+		return []*corev1.Pod{pod}, nil
+	}
+	gw, err := c.vpcNatGatewayLister.Get(name)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return []*corev1.Pod{pod}, nil
+		}
+		klog.Errorf("failed to get vpc nat gw %s: %v", name, err)
+		return nil, err
+	}
+	if !isVpcNatGwHA(gw) {
+		return []*corev1.Pod{pod}, nil
+	}
+
+	pods, err := c.listNatGwPods(name)
+	if err != nil {
+		klog.Errorf("failed to list pods of vpc nat gw %s: %v", name, err)
+		return nil, err
+	}
+	replicas := make([]*corev1.Pod, 0, len(pods))
+	for _, p := range pods {
+		if p.Name == pod.Name || isNatGwPodReady(p) {
+			replicas = append(replicas, p)
+		}
+	}
+	return replicas, nil
+}
+
+func (c *Controller) handleUpdateVpcNatGwHA(key string) error {
+	if vpcNatEnabled != "true" {
+		return fmt.Errorf("iptables nat gw not enable")
+	}
+
+	c.vpcNatGwKeyMutex.LockKey(key)
+	defer func() { _ = c.vpcNatGwKeyMutex.UnlockKey(key) }()
+	klog.Infof("handle update ha of vpc nat gateway %s", key)
+
+	cachedGw, err := c.vpcNatGatewayLister.Get(key)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Error(err)
+		return err
+	}
+	gw := cachedGw.DeepCopy()
+	if !isVpcNatGwHA(gw) {
+		if gw.Status.ActivePod == "" && len(gw.Status.ReadyReplicas) == 0 {
+			return nil
+		}
+		// the nat gw is switched to single replica, route the vpc traffic back to the lan ip
+		gw.Status.ActivePod = ""
+		gw.Status.ReadyReplicas = nil
+		if err = c.patchNatGwHAStatus(gw); err != nil {
+			klog.Errorf("failed to patch ha status of vpc nat gw %s: %v", key, err)
+			return err
+		}
+		c.addOrUpdateVpcQueue.Add(gw.Spec.Vpc)
+		return nil
+	}
+
+	pods, err := c.listNatGwPods(key)
+	if err != nil {
+		klog.Errorf("failed to list pods of vpc nat gw %s: %v", key, err)
+		return err
+	}
+	var oldActive, active *corev1.Pod
+	var ready []*corev1.Pod
+	var readyNames []string
+	for _, pod := range pods {
+		if pod.Name == gw.Status.ActivePod {
+			oldActive = pod
+		}
+		// the replica is not able to serve until all the rules are replayed after init
+		if !isNatGwPodReady(pod) || pod.Annotations[util.VpcNatGatewayInitAnnotation] != "true" {
+			continue
+		}
+		ready = append(ready, pod)
+		readyNames = append(readyNames, pod.Name)
+		if pod.Name == gw.Status.ActivePod {
+			active = pod
+		}
+	}
+	if len(ready) == 0 {
+		err = fmt.Errorf("no ready replica of vpc nat gw %s", key)
+		klog.Error(err)
+		return err
+	}
+
+	failover := false
+	if active == nil {
+		active = ready[0]
+		failover = gw.Status.ActivePod != ""
+	}
+	changed := active.Name != gw.Status.ActivePod || !slices.Equal(readyNames, gw.Status.ReadyReplicas)
+	if gw.Spec.Mode == kubeovnv1.VpcNatGwModeECMP {
+		// all the ready replicas are active, each one answers arp for its own share of the eips
+		if err = c.shareNatGwEips(key, ready); err != nil {
+			klog.Errorf("failed to share eips of vpc nat gw %s: %v", key, err)
+			return err
+		}
+	} else if changed {
+		// fence the standby replicas before the active one takes over the eips
+		for _, pod := range ready {
+			if pod.Name == active.Name {
+				continue
+			}
+			if err = c.execNatGwRulesInPod(pod, natGwHAStandby, nil); err != nil {
+				klog.Errorf("failed to set vpc nat gw pod %s to standby: %v", pod.Name, err)
+				return err
+			}
+		}
+		// the active replica announces the eips by gratuitous arp
+		if err = c.execNatGwRulesInPod(active, natGwHAActive, nil); err != nil {
+			klog.Errorf("failed to set vpc nat gw pod %s to active: %v", active.Name, err)
+			return err
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	if failover {
+		now := metav1.Now()
+		gw.Status.LastFailoverTime = now
+		gw.Status.FailoverDuration = metav1.Duration{Duration: now.Sub(natGwPodFailedAt(oldActive))}
+		klog.Infof("vpc nat gw %s failed over from %s to %s in %s", key, gw.Status.ActivePod, active.Name, gw.Status.FailoverDuration.Duration)
+	}
+	gw.Status.ActivePod = active.Name
+	gw.Status.ReadyReplicas = readyNames
+	if err = c.patchNatGwHAStatus(gw); err != nil {
+		klog.Errorf("failed to patch ha status of vpc nat gw %s: %v", key, err)
+		return err
+	}
+
+	// redirect the vpc routes to the active or ready replicas
+	c.addOrUpdateVpcQueue.Add(gw.Spec.Vpc)
+	return nil
+}
+
+// natGwIgnoredEips shares out the eips of the ecmp nat gw among the replicas,
+// and returns the eips owned by the other replicas, which are ignored by arp, of each replica
+func natGwIgnoredEips(eips, replicas []string) map[string][]string {
+	ignored := make(map[string][]string, len(replicas))
+	for _, name := range replicas {
+		ignored[name] = []string{}
+	}
+	for i, eip := range eips {
+		owner := replicas[i%len(replicas)]
+		for _, name := range replicas {
+			if name != owner {
+				ignored[name] = append(ignored[name], eip)
+			}
+		}
+	}
+	return ignored
+}
+
+// shareNatGwEips makes every ready replica of the ecmp nat gw answer arp only for its own share of the eips,
+// otherwise all the replicas answer arp for the same eip and the external traffic flaps between them
+func (c *Controller) shareNatGwEips(gwName string, pods []*corev1.Pod) error {
+	eips, err := c.iptablesEipsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list iptables eips: %v", err)
+		return err
+	}
+	var eipIPs []string
+	for _, eip := range eips {
+		if eip.Spec.NatGwDp == gwName && eip.Status.IP != "" && eip.DeletionTimestamp == nil {
+			eipIPs = append(eipIPs, eip.Status.IP)
+		}
+	}
+	slices.Sort(eipIPs)
+
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	ignored := natGwIgnoredEips(eipIPs, names)
+	for _, pod := range pods {
+		value := strings.Join(ignored[pod.Name], ",")
+		if v, ok := pod.Annotations[util.VpcNatGatewayIgnoredEipsAnnotation]; ok && v == value {
+			continue
+		}
+		if err = c.execNatGwRulesInPod(pod, natGwHAActive, ignored[pod.Name]); err != nil {
+			klog.Errorf("failed to set vpc nat gw pod %s to active: %v", pod.Name, err)
+			return err
+		}
+
+		newPod := pod.DeepCopy()
+		newPod.Annotations[util.VpcNatGatewayIgnoredEipsAnnotation] = value
+		patch, err := util.GenerateStrategicMergePatchPayload(pod, newPod)
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		if _, err = c.config.KubeClient.CoreV1().Pods(pod.Namespace).Patch(context.Background(), pod.Name,
+			types.StrategicMergePatchType, patch, metav1.PatchOptions{}, ""); err != nil {
+			klog.Errorf("failed to patch pod %s/%s: %v", pod.Namespace, pod.Name, err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Controller) patchNatGwHAStatus(gw *kubeovnv1.VpcNatGateway) error {
+	bytes, err := gw.Status.Bytes()
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err = c.config.KubeOvnClient.KubeovnV1().VpcNatGateways().Patch(context.Background(), gw.Name, types.MergePatchType,
+		bytes, metav1.PatchOptions{}, "status"); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf("failed to patch gw %s, %v", gw.Name, err)
+		return err
+	}
+	return nil
+}
+
+// vpcNatGwBfdExternalID is the external id of the bfd sessions created for the ecmp nat gw replicas
+const vpcNatGwBfdExternalID = "vpc-nat-gw"
+
+// redirectVpcNatGwRoutes redirects the static routes whose next hop is the lan ip of a ha nat gw
+// to the active replica, or to all the ready replicas by bfd checked ecmp routes
+func (c *Controller) redirectVpcNatGwRoutes(vpc *kubeovnv1.Vpc, routes []*kubeovnv1.StaticRoute) ([]*kubeovnv1.StaticRoute, error) {
+	gws, err := c.vpcNatGatewayLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list vpc nat gws: %v", err)
+		return nil, err
+	}
+
+	// the bfd sessions of the nat gws may be left on the lrps of all the vpc subnets,
+	// e.g. the nat gw is deleted or switched to active-standby mode
+	bfdIPs := make(map[string][]string)
+	for _, subnet := range vpc.Status.Subnets {
+		bfdIPs[ovs.LogicalRouterPortName(vpc.Name, subnet)] = nil
+	}
+	haGws := make(map[string]*kubeovnv1.VpcNatGateway)
+	for _, gw := range gws {
+		if gw.Spec.Vpc != vpc.Name {
+			continue
+		}
+		bfdIPs[ovs.LogicalRouterPortName(vpc.Name, gw.Spec.Subnet)] = nil
+		if isVpcNatGwHA(gw) && gw.Status.ActivePod != "" {
+			haGws[gw.Spec.LanIP] = gw
+		}
+	}
+
+	result := make([]*kubeovnv1.StaticRoute, 0, len(routes))
+	for _, route := range routes {
+		gw := haGws[route.NextHopIP]
+		if gw == nil {
+			result = append(result, route)
+			continue
+		}
+
+		replicas := []string{gw.Status.ActivePod}
+		if gw.Spec.Mode == kubeovnv1.VpcNatGwModeECMP {
+			replicas = gw.Status.ReadyReplicas
+		}
+		lrpName := ovs.LogicalRouterPortName(vpc.Name, gw.Spec.Subnet)
+		for _, name := range replicas {
+			nextHop, err := c.getNatGwPodIP(name, util.CheckProtocol(route.NextHopIP))
+			if err != nil {
+				klog.Errorf("failed to get ip of vpc nat gw pod %s: %v", name, err)
+				continue
+			}
+			r := route.DeepCopy()
+			r.NextHopIP = nextHop
+			if gw.Spec.Mode == kubeovnv1.VpcNatGwModeECMP {
+				bfd, err := c.createVpcNatGwBFD(gw.Name, lrpName, nextHop)
+				if err != nil {
+					klog.Errorf("failed to create bfd for vpc nat gw pod %s: %v", name, err)
+					return nil, err
+				}
+				r.BfdID = bfd.UUID
+				r.ECMPMode = util.StaticRouteBfdEcmp
+				bfdIPs[lrpName] = append(bfdIPs[lrpName], nextHop)
+			}
+			result = append(result, r)
+		}
+	}
+
+	// clean up the bfd sessions of the replicas which are not ready any more,
+	// the bfd sessions which are not created for the nat gws are left untouched
+	for lrpName, ips := range bfdIPs {
+		bfdList, err := c.OVNNbClient.ListBFDs(lrpName, "")
+		if err != nil {
+			klog.Errorf("failed to list bfd of lrp %s: %v", lrpName, err)
+			return nil, err
+		}
+		for _, bfd := range bfdList {
+			if bfd.ExternalIDs[vpcNatGwBfdExternalID] == "" || slices.Contains(ips, bfd.DstIP) {
+				continue
+			}
+			klog.Infof("delete bfd %s of vpc nat gw %s on lrp %s", bfd.DstIP, bfd.ExternalIDs[vpcNatGwBfdExternalID], lrpName)
+			if err = c.OVNNbClient.DeleteBFD(lrpName, bfd.DstIP); err != nil {
+				klog.Errorf("failed to delete bfd %s of lrp %s: %v", bfd.DstIP, lrpName, err)
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// createVpcNatGwBFD creates the bfd session to the nat gw replica, and marks it as owned by the nat gw
+func (c *Controller) createVpcNatGwBFD(gwName, lrpName, dstIP string) (*ovnnb.BFD, error) {
+	bfd, err := c.OVNNbClient.CreateBFD(lrpName, dstIP, c.config.BfdMinRx, c.config.BfdMinTx, c.config.BfdDetectMult)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	if bfd.ExternalIDs[vpcNatGwBfdExternalID] == gwName {
+		return bfd, nil
+	}
+	bfd.ExternalIDs = map[string]string{"vendor": util.CniTypeName, vpcNatGwBfdExternalID: gwName}
+	if err = c.OVNNbClient.UpdateBFD(bfd, &bfd.ExternalIDs); err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	return bfd, nil
+}
+
+func (c *Controller) getNatGwPodIP(name, protocol string) (string, error) {
+	pod, err := c.podsLister.Pods(c.config.PodNamespace).Get(name)
+	if err != nil {
+		klog.Error(err)
+		return "", err
+	}
+	v4IP, v6IP := util.SplitStringIP(pod.Annotations[util.IPAddressAnnotation])
+	ip := v4IP
+	if protocol == kubeovnv1.ProtocolIPv6 {
+		ip = v6IP
+	}
+	if ip == "" {
+		return "", fmt.Errorf("no %s address allocated for pod %s", protocol, name)
+	}
+	return ip, nil
+}
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+func mockNatGwPod(name, ip string, ready bool) *corev1.Pod {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "kube-system",
+			Annotations: map[string]string{util.IPAddressAnnotation: ip},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+		},
+	}
+}
+
+func Test_isVpcNatGwHA(t *testing.T) {
+	t.Parallel()
+
+	gw := &kubeovnv1.VpcNatGateway{}
+	require.False(t, isVpcNatGwHA(gw))
+	require.Equal(t, int32(1), vpcNatGwReplicas(gw))
+
+	gw.Spec.Replicas = 2
+	require.False(t, isVpcNatGwHA(gw))
+
+	gw.Spec.Mode = kubeovnv1.VpcNatGwModeActiveStandby
+	require.True(t, isVpcNatGwHA(gw))
+
+	require.True(t, isNatGwPodReady(mockNatGwPod("gw-0", "10.0.1.2", true)))
+	require.False(t, isNatGwPodReady(mockNatGwPod("gw-0", "10.0.1.2", false)))
+}
+
+func Test_redirectVpcNatGwRoutes(t *testing.T) {
+	t.Parallel()
+
+	fakeController := newFakeController(t)
+	ctrl := fakeController.fakeController
+	fakeinformers := fakeController.fakeinformers
+	mockOvnClient := fakeController.mockOvnClient
+
+	gws := []*kubeovnv1.VpcNatGateway{{
+		ObjectMeta: metav1.ObjectMeta{Name: "gw1"},
+		Spec: kubeovnv1.VpcNatSpec{
+			Vpc: "vpc1", Subnet: "subnet1", LanIP: "10.0.1.254",
+			Replicas: 2, Mode: kubeovnv1.VpcNatGwModeActiveStandby,
+		},
+		Status: kubeovnv1.VpcNatStatus{ActivePod: "vpc-nat-gw-gw1-1", ReadyReplicas: []string{"vpc-nat-gw-gw1-0", "vpc-nat-gw-gw1-1"}},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "gw2"},
+		Spec: kubeovnv1.VpcNatSpec{
+			Vpc: "vpc1", Subnet: "subnet2", LanIP: "10.0.2.254",
+			Replicas: 2, Mode: kubeovnv1.VpcNatGwModeECMP,
+		},
+		Status: kubeovnv1.VpcNatStatus{ActivePod: "vpc-nat-gw-gw2-0", ReadyReplicas: []string{"vpc-nat-gw-gw2-0", "vpc-nat-gw-gw2-1"}},
+	}}
+	for _, gw := range gws {
+		require.NoError(t, fakeinformers.vpcNatGatewayInformer.Informer().GetStore().Add(gw))
+	}
+	pods := []*corev1.Pod{
+		mockNatGwPod("vpc-nat-gw-gw1-0", "10.0.1.2", true),
+		mockNatGwPod("vpc-nat-gw-gw1-1", "10.0.1.3", true),
+		mockNatGwPod("vpc-nat-gw-gw2-0", "10.0.2.2", true),
+		mockNatGwPod("vpc-nat-gw-gw2-1", "10.0.2.3", true),
+	}
+	for _, pod := range pods {
+		require.NoError(t, fakeinformers.podInformer.Informer().GetStore().Add(pod))
+	}
+
+	lrpName := "vpc1-subnet2"
+	owned := map[string]string{"vendor": util.CniTypeName, vpcNatGwBfdExternalID: "gw2"}
+	mockOvnClient.EXPECT().CreateBFD(lrpName, "10.0.2.2", gomock.Any(), gomock.Any(), gomock.Any()).Return(&ovnnb.BFD{UUID: "bfd-0", DstIP: "10.0.2.2"}, nil)
+	mockOvnClient.EXPECT().UpdateBFD(&ovnnb.BFD{UUID: "bfd-0", DstIP: "10.0.2.2", ExternalIDs: owned}, gomock.Any()).Return(nil)
+	mockOvnClient.EXPECT().CreateBFD(lrpName, "10.0.2.3", gomock.Any(), gomock.Any(), gomock.Any()).Return(&ovnnb.BFD{UUID: "bfd-1", DstIP: "10.0.2.3", ExternalIDs: owned}, nil)
+	// the bfd sessions which are not created for the nat gw should be left untouched
+	mockOvnClient.EXPECT().ListBFDs(lrpName, "").Return([]ovnnb.BFD{
+		{DstIP: "10.0.2.2", ExternalIDs: owned}, {DstIP: "10.0.2.3", ExternalIDs: owned}, {DstIP: "10.0.2.4", ExternalIDs: owned}, {DstIP: "10.0.2.5"},
+	}, nil)
+	mockOvnClient.EXPECT().DeleteBFD(lrpName, "10.0.2.4").Return(nil)
+	// the bfd sessions of the nat gw which is switched to active-standby mode should be deleted
+	mockOvnClient.EXPECT().ListBFDs("vpc1-subnet1", "").Return([]ovnnb.BFD{{DstIP: "10.0.1.2", ExternalIDs: map[string]string{vpcNatGwBfdExternalID: "gw1"}}}, nil)
+	mockOvnClient.EXPECT().DeleteBFD("vpc1-subnet1", "10.0.1.2").Return(nil)
+	// the bfd sessions of the deleted nat gw should be deleted
+	mockOvnClient.EXPECT().ListBFDs("vpc1-subnet3", "").Return([]ovnnb.BFD{{DstIP: "10.0.3.2", ExternalIDs: map[string]string{vpcNatGwBfdExternalID: "gw3"}}}, nil)
+	mockOvnClient.EXPECT().DeleteBFD("vpc1-subnet3", "10.0.3.2").Return(nil)
+
+	routes := []*kubeovnv1.StaticRoute{
+		{Policy: kubeovnv1.PolicyDst, CIDR: "0.0.0.0/0", NextHopIP: "10.0.1.254"},
+		{Policy: kubeovnv1.PolicySrc, CIDR: "192.168.0.0/24", NextHopIP: "10.0.2.254"},
+		{Policy: kubeovnv1.PolicyDst, CIDR: "172.16.0.0/16", NextHopIP: "10.0.3.1"},
+	}
+	vpc := &kubeovnv1.Vpc{
+		ObjectMeta: metav1.ObjectMeta{Name: "vpc1"},
+		Status:     kubeovnv1.VpcStatus{Subnets: []string{"subnet1", "subnet2", "subnet3"}},
+	}
+	result, err := ctrl.redirectVpcNatGwRoutes(vpc, routes)
+	require.NoError(t, err)
+	require.Equal(t, []*kubeovnv1.StaticRoute{
+		{Policy: kubeovnv1.PolicyDst, CIDR: "0.0.0.0/0", NextHopIP: "10.0.1.3"},
+		{Policy: kubeovnv1.PolicySrc, CIDR: "192.168.0.0/24", NextHopIP: "10.0.2.2", BfdID: "bfd-0", ECMPMode: util.StaticRouteBfdEcmp},
+		{Policy: kubeovnv1.PolicySrc, CIDR: "192.168.0.0/24", NextHopIP: "10.0.2.3", BfdID: "bfd-1", ECMPMode: util.StaticRouteBfdEcmp},
+		{Policy: kubeovnv1.PolicyDst, CIDR: "172.16.0.0/16", NextHopIP: "10.0.3.1"},
+	}, result)
+	// the spec routes should not be modified
+	require.Equal(t, "10.0.1.254", routes[0].NextHopIP)
+
+	result, err = ctrl.redirectVpcNatGwRoutes(&kubeovnv1.Vpc{ObjectMeta: metav1.ObjectMeta{Name: "vpc2"}}, routes)
+	require.NoError(t, err)
+	require.Equal(t, routes, result)
+}
+
+func Test_natGwIgnoredEips(t *testing.T) {
+	t.Parallel()
+
+	replicas := []string{"gw-0", "gw-1"}
+	require.Equal(t, map[string][]string{"gw-0": {}, "gw-1": {}}, natGwIgnoredEips(nil, replicas))
+	require.Equal(t, map[string][]string{
+		"gw-0": {"172.18.0.2", "172.18.0.4"},
+		"gw-1": {"172.18.0.1", "172.18.0.3", "172.18.0.5"},
+	}, natGwIgnoredEips([]string{"172.18.0.1", "172.18.0.2", "172.18.0.3", "172.18.0.4", "172.18.0.5"}, replicas))
+	require.Equal(t, map[string][]string{"gw-0": {}}, natGwIgnoredEips([]string{"172.18.0.1"}, replicas[:1]))
+}
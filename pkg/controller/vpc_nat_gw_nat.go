@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
@@ -12,11 +13,13 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/natgw"
 	"github.com/kubeovn/kube-ovn/pkg/util"
 )
 
@@ -45,6 +48,12 @@ func (c *Controller) enqueueUpdateIptablesFip(oldObj, newObj interface{}) {
 		c.updateIptablesFipQueue.Add(key)
 		return
 	}
+	if oldFip.Spec.EIP == "" && newFip.Spec.EIP != "" {
+		// eip allocated from the eip pool
+		klog.V(3).Infof("enqueue add fip %s", key)
+		c.addIptablesFipQueue.Add(key)
+		return
+	}
 	if oldFip.Spec.EIP != newFip.Spec.EIP {
 		// to notify old eip to remove nat label
 		c.resetIptablesEipQueue.Add(oldFip.Spec.EIP)
@@ -95,6 +104,12 @@ func (c *Controller) enqueueUpdateIptablesDnatRule(oldObj, newObj interface{}) {
 		return
 	}
 
+	if oldDnat.Spec.EIP == "" && newDnat.Spec.EIP != "" {
+		// eip allocated from the eip pool
+		klog.V(3).Infof("enqueue add dnat %s", key)
+		c.addIptablesDnatRuleQueue.Add(key)
+		return
+	}
 	if oldDnat.Spec.EIP != newDnat.Spec.EIP {
 		// to notify old eip to remove nat table
 		c.resetIptablesEipQueue.Add(oldDnat.Spec.EIP)
@@ -147,6 +162,12 @@ func (c *Controller) enqueueUpdateIptablesSnatRule(oldObj, newObj interface{}) {
 		c.updateIptablesSnatRuleQueue.Add(key)
 		return
 	}
+	if oldSnat.Spec.EIP == "" && newSnat.Spec.EIP != "" {
+		// eip allocated from the eip pool
+		klog.V(3).Infof("enqueue add snat %s", key)
+		c.addIptablesSnatRuleQueue.Add(key)
+		return
+	}
 	if oldSnat.Spec.EIP != newSnat.Spec.EIP {
 		// to notify old eip to remove nat label
 		c.resetIptablesEipQueue.Add(oldSnat.Spec.EIP)
@@ -501,6 +522,9 @@ func (c *Controller) handleAddIptablesFip(key string) error {
 	klog.V(3).Infof("handle add fip %s", key)
 	// get eip
 	eipName := fip.Spec.EIP
+	if eipName == "" && fip.Spec.EipPool != "" {
+		return c.allocateIptablesNatRuleEip(util.FipUsingEip, iptablesFipKind, fip, fip.Spec.EipPool, fip.Spec.NatGwDp)
+	}
 	if eipName == "" {
 		return fmt.Errorf("failed to create fip rule, should set eip")
 	}
@@ -700,6 +724,9 @@ func (c *Controller) handleAddIptablesDnatRule(key string) error {
 	}
 	klog.V(3).Infof("handle add iptables dnat %s", key)
 	eipName := dnat.Spec.EIP
+	if eipName == "" && dnat.Spec.EipPool != "" {
+		return c.allocateIptablesNatRuleEip(util.DnatUsingEip, iptablesDnatRuleKind, dnat, dnat.Spec.EipPool, dnat.Spec.NatGwDp)
+	}
 	if eipName == "" {
 		return fmt.Errorf("failed to create dnat rule, should set eip")
 	}
@@ -709,7 +736,7 @@ func (c *Controller) handleAddIptablesDnatRule(key string) error {
 		klog.Errorf("failed to get eip, %v", err)
 		return err
 	}
-	if dup, err := c.isDnatDuplicated(eip.Spec.NatGwDp, eipName, dnat.Name, dnat.Spec.ExternalPort); dup || err != nil {
+	if dup, err := c.isDnatDuplicated(eip.Spec.NatGwDp, eipName, dnat.Name, dnat.Spec.Protocol, dnat.Spec.ExternalPort); dup || err != nil {
 		return err
 	}
 	// create nat
@@ -782,7 +809,7 @@ func (c *Controller) handleUpdateIptablesDnatRule(key string) error {
 		klog.Errorf("failed to get eip, %v", err)
 		return err
 	}
-	if dup, err := c.isDnatDuplicated(cachedDnat.Status.NatGwDp, eipName, cachedDnat.Name, cachedDnat.Spec.ExternalPort); dup || err != nil {
+	if dup, err := c.isDnatDuplicated(cachedDnat.Status.NatGwDp, eipName, cachedDnat.Name, cachedDnat.Spec.Protocol, cachedDnat.Spec.ExternalPort); dup || err != nil {
 		klog.Errorf("failed to update dnat, %v", err)
 		return err
 	}
@@ -885,6 +912,9 @@ func (c *Controller) handleAddIptablesSnatRule(key string) error {
 	}
 	klog.V(3).Infof("handle add iptables snat %s", key)
 	eipName := snat.Spec.EIP
+	if eipName == "" && snat.Spec.EipPool != "" {
+		return c.allocateIptablesNatRuleEip(util.SnatUsingEip, iptablesSnatRuleKind, snat, snat.Spec.EipPool, snat.Spec.NatGwDp)
+	}
 	if eipName == "" {
 		return fmt.Errorf("failed to create snat rule, should set eip")
 	}
@@ -1377,7 +1407,7 @@ func (c *Controller) patchDnatLabel(key string, eip *kubeovnv1.IptablesEIP) erro
 		op = "add"
 		dnat.Labels = map[string]string{
 			util.VpcNatGatewayNameLabel: eip.Spec.NatGwDp,
-			util.VpcDnatEPortLabel:      dnat.Spec.ExternalPort,
+			util.VpcDnatEPortLabel:      dnatPortLabel(dnat.Spec.ExternalPort),
 			util.EipV4IpLabel:           eip.Spec.V4ip,
 		}
 		needUpdateLabel = true
@@ -1385,7 +1415,7 @@ func (c *Controller) patchDnatLabel(key string, eip *kubeovnv1.IptablesEIP) erro
 		dnat.Labels[util.EipV4IpLabel] != eip.Spec.V4ip {
 		op = "replace"
 		dnat.Labels[util.VpcNatGatewayNameLabel] = eip.Spec.NatGwDp
-		dnat.Labels[util.VpcDnatEPortLabel] = dnat.Spec.ExternalPort
+		dnat.Labels[util.VpcDnatEPortLabel] = dnatPortLabel(dnat.Spec.ExternalPort)
 		dnat.Labels[util.EipV4IpLabel] = eip.Spec.V4ip
 		needUpdateLabel = true
 	}
@@ -1675,8 +1705,19 @@ func (c *Controller) createDnatInPod(dp, protocol, v4ip, internalIP, externalPor
 		return err
 	}
 	var addRules []string
-	rule := fmt.Sprintf("%s,%s,%s,%s,%s", v4ip, externalPort, protocol, internalIP, internalPort)
-	addRules = append(addRules, rule)
+	dnats, err := natgw.DNAT{EIP: v4ip, Protocol: protocol, ExternalPort: externalPort, InternalIP: internalIP, InternalPort: internalPort}.Expand()
+	if err != nil {
+		klog.Errorf("failed to create dnat, %v", err)
+		return err
+	}
+	if len(dnats) > util.DnatMaxPorts {
+		err = fmt.Errorf("external port %s is expanded to %d rules, more than the limit %d", externalPort, len(dnats), util.DnatMaxPorts)
+		klog.Error(err)
+		return err
+	}
+	for _, dnat := range dnats {
+		addRules = append(addRules, dnat.Rule())
+	}
 
 	if err = c.execNatGwRules(gwPod, natGwDnatAdd, addRules); err != nil {
 		klog.Errorf("failed to create dnat, err: %v", err)
@@ -1697,8 +1738,14 @@ func (c *Controller) deleteDnatInPod(dp, protocol, v4ip, internalIP, externalPor
 
 	// del nat
 	var delRules []string
-	rule := fmt.Sprintf("%s,%s,%s,%s,%s", v4ip, externalPort, protocol, internalIP, internalPort)
-	delRules = append(delRules, rule)
+	dnats, err := natgw.DNAT{EIP: v4ip, Protocol: protocol, ExternalPort: externalPort, InternalIP: internalIP, InternalPort: internalPort}.Expand()
+	if err != nil {
+		klog.Errorf("failed to delete dnat, %v", err)
+		return err
+	}
+	for _, dnat := range dnats {
+		delRules = append(delRules, dnat.Rule())
+	}
 	if err = c.execNatGwRules(gwPod, natGwDnatDel, delRules); err != nil {
 		klog.Errorf("failed to delete dnat, err: %v", err)
 		return err
@@ -1785,28 +1832,46 @@ func (c *Controller) snatChangeEip(snat *kubeovnv1.IptablesSnatRule, eip *kubeov
 	return false
 }
 
-func (c *Controller) isDnatDuplicated(gwName, eipName, dnatName, externalPort string) (bool, error) {
-	// check if eip:external port already used
+func (c *Controller) isDnatDuplicated(gwName, eipName, dnatName, protocol, externalPort string) (bool, error) {
+	// check if the external ports of eip already used
+	ports, err := util.ParsePortRanges(externalPort)
+	if err != nil {
+		err = fmt.Errorf("failed to create dnat %s, %w", dnatName, err)
+		klog.Error(err)
+		return true, err
+	}
 	dnats, err := c.iptablesDnatRulesLister.List(labels.SelectorFromSet(labels.Set{
 		util.VpcNatGatewayNameLabel: gwName,
-		util.VpcDnatEPortLabel:      externalPort,
 	}))
 	if err != nil {
 		if !k8serrors.IsNotFound(err) {
 			return false, err
 		}
 	}
-	if len(dnats) != 0 {
-		for _, d := range dnats {
-			if d.Name != dnatName && d.Spec.EIP == eipName {
-				err = fmt.Errorf("failed to create dnat %s, duplicate, same eip %s, same external port '%s' is using by dnat %s", dnatName, eipName, externalPort, d.Name)
-				return true, err
-			}
+	for _, d := range dnats {
+		if d.Name == dnatName || d.Spec.EIP != eipName || !strings.EqualFold(d.Spec.Protocol, protocol) {
+			continue
+		}
+		used, err := util.ParsePortRanges(d.Spec.ExternalPort)
+		if err != nil {
+			continue
+		}
+		if r := util.PortRangesOverlap(ports, used); r != nil {
+			err = fmt.Errorf("failed to create dnat %s, duplicate, same eip %s, external port '%s' is using by dnat %s", dnatName, eipName, r, d.Name)
+			return true, err
 		}
 	}
 	return false, nil
 }
 
+// dnatPortLabel returns the label value of the dnat external ports, a list of ports is not a valid label value
+func dnatPortLabel(externalPort string) string {
+	if errs := validation.IsValidLabelValue(externalPort); len(errs) != 0 {
+		return ""
+	}
+	return externalPort
+}
+
 func (c *Controller) updateIptableLabels(name, op, natType string, labels map[string]string) error {
 	patchPayloadTemplate := `[{ "op": "%s", "path": "/metadata/labels", "value": %s }]`
 	raw, _ := json.Marshal(labels)
@@ -24,6 +24,7 @@ import (
 
 	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
 	clientset "github.com/kubeovn/kube-ovn/pkg/client/clientset/versioned"
+	"github.com/kubeovn/kube-ovn/pkg/ovs"
 	"github.com/kubeovn/kube-ovn/pkg/util"
 )
 
@@ -66,6 +67,12 @@ type Configuration struct {
 	UDPConnCheckPort          int
 	EnableTProxy              bool
 	OVSVsctlConcurrency       int32
+	EnableLbHealthCheck       bool
+	LbHealthCheckInterval     int
+	FirewallBackend           string
+	EnableOVNBandwidthLimit   bool
+	EnableOVSDBClient         bool
+	OVSClient                 *ovs.OVSClient
 }
 
 // ParseFlags will parse cmd args then init kubeClient and configuration
@@ -105,6 +112,11 @@ func ParseFlags() *Configuration {
 		argUDPConnectivityCheckPort  = pflag.Int("udp-conn-check-port", 8101, "UDP connectivity Check Port")
 		argEnableTProxy              = pflag.Bool("enable-tproxy", false, "enable tproxy for vpc pod liveness or readiness probe")
 		argOVSVsctlConcurrency       = pflag.Int32("ovs-vsctl-concurrency", 100, "concurrency limit of ovs-vsctl")
+		argEnableLbHealthCheck       = pflag.Bool("enable-lb-health-check", false, "enable active http/grpc health check for the local load balancer backends of services")
+		argLbHealthCheckInterval     = pflag.Int("lb-health-check-interval", 5, "the interval in seconds of the active load balancer backend health check")
+		argFirewallBackend           = pflag.String("firewall-backend", util.FirewallBackendIptables, "The backend programming the node gateway rules, iptables or nftables")
+		argEnableOVNBandwidthLimit   = pflag.Bool("enable-ovn-bandwidth-limit", false, "Whether the pod bandwidth limits are programmed by kube-ovn-controller as ovn qos rules instead of ovs interface policing and queues")
+		argEnableOVSDBClient         = pflag.Bool("enable-ovsdb-client", false, "Whether to configure the ovs ports of pods by a long-lived ovsdb client connected to the ovs socket instead of ovs-vsctl commands")
 	)
 
 	// mute info log for ipset lib
@@ -161,6 +173,11 @@ func ParseFlags() *Configuration {
 		UDPConnCheckPort:          *argUDPConnectivityCheckPort,
 		EnableTProxy:              *argEnableTProxy,
 		OVSVsctlConcurrency:       *argOVSVsctlConcurrency,
+		EnableLbHealthCheck:       *argEnableLbHealthCheck,
+		LbHealthCheckInterval:     *argLbHealthCheckInterval,
+		FirewallBackend:           *argFirewallBackend,
+		EnableOVNBandwidthLimit:   *argEnableOVNBandwidthLimit,
+		EnableOVSDBClient:         *argEnableOVSDBClient,
 	}
 	return config
 }
@@ -178,17 +195,40 @@ func (config *Configuration) Init(nicBridgeMappings map[string]string) error {
 		}
 	}
 
+	if config.FirewallBackend != util.FirewallBackendIptables && config.FirewallBackend != util.FirewallBackendNftables {
+		return fmt.Errorf("unsupported firewall backend %q", config.FirewallBackend)
+	}
+
 	if err := config.initKubeClient(); err != nil {
 		return err
 	}
 	if err := config.initNicConfig(nicBridgeMappings); err != nil {
 		return err
 	}
+	if config.EnableOVSDBClient {
+		if err := config.initOVSClient(); err != nil {
+			return err
+		}
+	}
 
 	klog.Infof("daemon config: %v", config)
 	return nil
 }
 
+func (config *Configuration) initOVSClient() error {
+	ovsSocket := config.OvsSocket
+	if ovsSocket == "" {
+		ovsSocket = "/run/openvswitch/db.sock"
+	}
+	ovsClient, err := ovs.NewOVSClient("unix:"+ovsSocket, 30)
+	if err != nil {
+		klog.Errorf("failed to create ovs client for socket %s: %v", ovsSocket, err)
+		return err
+	}
+	config.OVSClient = ovsClient
+	return nil
+}
+
 func (config *Configuration) initNicConfig(nicBridgeMappings map[string]string) error {
 	// Support to specify node network card separately
 	node, err := config.KubeClient.CoreV1().Nodes().Get(context.Background(), config.NodeName, metav1.GetOptions{})
@@ -48,6 +48,10 @@ type Controller struct {
 	ovnEipsLister kubeovnlister.OvnEipLister
 	ovnEipsSynced cache.InformerSynced
 
+	trafficMirrorsLister kubeovnlister.TrafficMirrorLister
+	trafficMirrorsSynced cache.InformerSynced
+	trafficMirrorQueue   workqueue.RateLimitingInterface
+
 	podsLister listerv1.PodLister
 	podsSynced cache.InformerSynced
 	podQueue   workqueue.RateLimitingInterface
@@ -55,6 +59,12 @@ type Controller struct {
 	nodesLister listerv1.NodeLister
 	nodesSynced cache.InformerSynced
 
+	servicesLister listerv1.ServiceLister
+	servicesSynced cache.InformerSynced
+
+	// consecutive active health check failures of the local lb backends, keyed by pod and service
+	lbHealthCheckFailures map[string]int
+
 	recorder record.EventRecorder
 
 	protocol string
@@ -76,6 +86,7 @@ func NewController(config *Configuration, stopCh <-chan struct{}, podInformerFac
 	providerNetworkInformer := kubeovnInformerFactory.Kubeovn().V1().ProviderNetworks()
 	subnetInformer := kubeovnInformerFactory.Kubeovn().V1().Subnets()
 	ovnEipInformer := kubeovnInformerFactory.Kubeovn().V1().OvnEips()
+	trafficMirrorInformer := kubeovnInformerFactory.Kubeovn().V1().TrafficMirrors()
 	podInformer := podInformerFactory.Core().V1().Pods()
 	nodeInformer := nodeInformerFactory.Core().V1().Nodes()
 
@@ -94,6 +105,10 @@ func NewController(config *Configuration, stopCh <-chan struct{}, podInformerFac
 		ovnEipsLister: ovnEipInformer.Lister(),
 		ovnEipsSynced: ovnEipInformer.Informer().HasSynced,
 
+		trafficMirrorsLister: trafficMirrorInformer.Lister(),
+		trafficMirrorsSynced: trafficMirrorInformer.Informer().HasSynced,
+		trafficMirrorQueue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "TrafficMirror"),
+
 		podsLister: podInformer.Lister(),
 		podsSynced: podInformer.Informer().HasSynced,
 		podQueue:   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Pod"),
@@ -105,6 +120,18 @@ func NewController(config *Configuration, stopCh <-chan struct{}, podInformerFac
 		k8sExec:  k8sexec.New(),
 	}
 
+	cacheSyncs := []cache.InformerSynced{
+		controller.providerNetworksSynced, controller.subnetsSynced,
+		controller.podsSynced, controller.nodesSynced, controller.trafficMirrorsSynced,
+	}
+	if config.EnableLbHealthCheck {
+		serviceInformer := nodeInformerFactory.Core().V1().Services()
+		controller.servicesLister = serviceInformer.Lister()
+		controller.servicesSynced = serviceInformer.Informer().HasSynced
+		controller.lbHealthCheckFailures = make(map[string]int)
+		cacheSyncs = append(cacheSyncs, controller.servicesSynced)
+	}
+
 	node, err := config.KubeClient.CoreV1().Nodes().Get(context.Background(), config.NodeName, metav1.GetOptions{})
 	if err != nil {
 		util.LogFatalAndExit(err, "failed to get node %s info", config.NodeName)
@@ -119,9 +146,7 @@ func NewController(config *Configuration, stopCh <-chan struct{}, podInformerFac
 	nodeInformerFactory.Start(stopCh)
 	kubeovnInformerFactory.Start(stopCh)
 
-	if !cache.WaitForCacheSync(stopCh,
-		controller.providerNetworksSynced, controller.subnetsSynced,
-		controller.podsSynced, controller.nodesSynced) {
+	if !cache.WaitForCacheSync(stopCh, cacheSyncs...) {
 		util.LogFatalAndExit(nil, "failed to wait for caches to sync")
 	}
 
@@ -144,6 +169,13 @@ func NewController(config *Configuration, stopCh <-chan struct{}, podInformerFac
 	}); err != nil {
 		return nil, err
 	}
+	if _, err = trafficMirrorInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.enqueueTrafficMirror,
+		UpdateFunc: controller.enqueueUpdateTrafficMirror,
+		DeleteFunc: controller.enqueueTrafficMirror,
+	}); err != nil {
+		return nil, err
+	}
 
 	return controller, nil
 }
@@ -587,14 +619,15 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 	defer c.deleteProviderNetworkQueue.ShutDown()
 	defer c.subnetQueue.ShutDown()
 	defer c.podQueue.ShutDown()
+	defer c.trafficMirrorQueue.ShutDown()
 
 	go wait.Until(ovs.CleanLostInterface, time.Minute, stopCh)
 	go wait.Until(recompute, 10*time.Minute, stopCh)
 	go wait.Until(rotateLog, 1*time.Hour, stopCh)
 	go wait.Until(c.operateMod, 10*time.Second, stopCh)
 
-	if err := c.setIPSet(); err != nil {
-		util.LogFatalAndExit(err, "failed to set ipsets")
+	if err := c.setGatewaySets(); err != nil {
+		util.LogFatalAndExit(err, "failed to set gateway sets")
 	}
 
 	klog.Info("Started workers")
@@ -604,6 +637,8 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 	go wait.Until(c.runDeleteProviderNetworkWorker, time.Second, stopCh)
 	go wait.Until(c.runSubnetWorker, time.Second, stopCh)
 	go wait.Until(c.runPodWorker, time.Second, stopCh)
+	go wait.Until(c.runTrafficMirrorWorker, time.Second, stopCh)
+	go wait.Until(c.resyncTrafficMirrors, 10*time.Second, stopCh)
 	go wait.Until(c.runGateway, 3*time.Second, stopCh)
 	go wait.Until(c.loopEncapIPCheck, 3*time.Second, stopCh)
 	go wait.Until(c.ovnMetricsUpdate, 3*time.Second, stopCh)
@@ -618,6 +653,10 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 		}
 	}, 5*time.Minute, stopCh)
 
+	if c.config.EnableLbHealthCheck {
+		go wait.Until(c.checkLbBackendsHealth, time.Duration(c.config.LbHealthCheckInterval)*time.Second, stopCh)
+	}
+
 	if c.config.EnableTProxy {
 		go c.StartTProxyForwarding()
 		go wait.Until(c.runTProxyConfigWorker, 3*time.Second, stopCh)
@@ -39,6 +39,7 @@ type ControllerRuntime struct {
 	k8sipsets        k8sipset.Interface
 	ipsets           map[string]*ipsets.IPSets
 	gwCounters       map[string]*util.GwIPtableCounters
+	firewall         gatewayFirewall
 
 	nmSyncer *networkManagerSyncer
 }
@@ -69,6 +70,29 @@ func isLegacyIptablesMode() (bool, error) {
 }
 
 func (c *Controller) initRuntime() error {
+	c.iptables = make(map[string]*iptables.IPTables)
+	c.ipsets = make(map[string]*ipsets.IPSets)
+	c.gwCounters = make(map[string]*util.GwIPtableCounters)
+	c.k8siptables = make(map[string]k8siptables.Interface)
+
+	if c.config.FirewallBackend == util.FirewallBackendNftables {
+		// the ipsets of kube-proxy are checked to decide whether the ipvs rules are generated
+		c.k8sipsets = k8sipset.New(c.k8sExec)
+		c.firewall = newNftablesFirewall(c)
+	} else {
+		if err := c.initIptables(); err != nil {
+			return err
+		}
+		c.firewall = &iptablesFirewall{c}
+	}
+
+	c.nmSyncer = newNetworkManagerSyncer()
+	c.nmSyncer.Run(c.transferAddrsAndRoutes)
+
+	return nil
+}
+
+func (c *Controller) initIptables() error {
 	ok, err := isLegacyIptablesMode()
 	if err != nil {
 		klog.Errorf("failed to check iptables mode: %v", err)
@@ -79,10 +103,6 @@ func (c *Controller) initRuntime() error {
 		c.iptablesObsolete = make(map[string]*iptables.IPTables, 2)
 	}
 
-	c.iptables = make(map[string]*iptables.IPTables)
-	c.ipsets = make(map[string]*ipsets.IPSets)
-	c.gwCounters = make(map[string]*util.GwIPtableCounters)
-	c.k8siptables = make(map[string]k8siptables.Interface)
 	c.k8sipsets = k8sipset.New(c.k8sExec)
 
 	if c.protocol == kubeovnv1.ProtocolIPv4 || c.protocol == kubeovnv1.ProtocolDual {
@@ -116,9 +136,6 @@ func (c *Controller) initRuntime() error {
 		c.k8siptables[kubeovnv1.ProtocolIPv6] = k8siptables.New(c.k8sExec, k8siptables.ProtocolIPv6)
 	}
 
-	c.nmSyncer = newNetworkManagerSyncer()
-	c.nmSyncer.Run(c.transferAddrsAndRoutes)
-
 	return nil
 }
 
@@ -529,13 +546,15 @@ func (c *Controller) handlePod(key string) error {
 		podName = pod.Annotations[fmt.Sprintf(util.VMTemplate, util.OvnProvider)]
 	}
 
-	// set default nic bandwidth
+	// set default nic bandwidth, which is limited by ovn qos rules programmed by kube-ovn-controller if enabled
 	ifaceID := ovs.PodNameToPortName(podName, pod.Namespace, util.OvnProvider)
-	err = ovs.SetInterfaceBandwidth(podName, pod.Namespace, ifaceID, pod.Annotations[util.EgressRateAnnotation], pod.Annotations[util.IngressRateAnnotation])
-	if err != nil {
-		return err
+	if !c.config.EnableOVNBandwidthLimit {
+		err = c.config.setInterfaceBandwidth(podName, pod.Namespace, ifaceID, pod.Annotations[util.EgressRateAnnotation], pod.Annotations[util.IngressRateAnnotation])
+		if err != nil {
+			return err
+		}
 	}
-	err = ovs.ConfigInterfaceMirror(c.config.EnableMirror, pod.Annotations[util.MirrorControlAnnotation], ifaceID)
+	err = c.config.configInterfaceMirror(pod.Annotations[util.MirrorControlAnnotation], ifaceID)
 	if err != nil {
 		return err
 	}
@@ -558,11 +577,13 @@ func (c *Controller) handlePod(key string) error {
 		if pod.Annotations[fmt.Sprintf(util.AllocatedAnnotationTemplate, provider)] == "true" {
 			ifaceID = ovs.PodNameToPortName(podName, pod.Namespace, provider)
 
-			err = ovs.SetInterfaceBandwidth(podName, pod.Namespace, ifaceID, pod.Annotations[fmt.Sprintf(util.EgressRateAnnotationTemplate, provider)], pod.Annotations[fmt.Sprintf(util.IngressRateAnnotationTemplate, provider)])
-			if err != nil {
-				return err
+			if !c.config.EnableOVNBandwidthLimit {
+				err = c.config.setInterfaceBandwidth(podName, pod.Namespace, ifaceID, pod.Annotations[fmt.Sprintf(util.EgressRateAnnotationTemplate, provider)], pod.Annotations[fmt.Sprintf(util.IngressRateAnnotationTemplate, provider)])
+				if err != nil {
+					return err
+				}
 			}
-			err = ovs.ConfigInterfaceMirror(c.config.EnableMirror, pod.Annotations[fmt.Sprintf(util.MirrorControlAnnotationTemplate, provider)], ifaceID)
+			err = c.config.configInterfaceMirror(pod.Annotations[fmt.Sprintf(util.MirrorControlAnnotationTemplate, provider)], ifaceID)
 			if err != nil {
 				return err
 			}
@@ -623,7 +644,7 @@ func (c *Controller) loopEncapIPCheck() {
 }
 
 func (c *Controller) ovnMetricsUpdate() {
-	c.setOvnSubnetGatewayMetric()
+	c.firewall.setSubnetGatewayMetric()
 }
 
 func (c *Controller) operateMod() {
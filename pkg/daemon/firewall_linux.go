@@ -0,0 +1,62 @@
+package daemon
+
+// gatewayFirewall programs the node gateway rules, which are implemented by iptables and ipset,
+// or by nftables tables replaced atomically in each round
+type gatewayFirewall interface {
+	// setSets updates the sets of the subnets, services and nodes matched by the rules
+	setSets() error
+	// setRules updates the nat, filter and mangle rules of the node
+	setRules() error
+	// gcSets deletes the sets no longer in use
+	gcSets()
+	// setMssRule clamps the tcp mss of the packets sent out of the tunnel interface
+	setMssRule()
+	// cleanTProxyRules deletes the rules redirecting the probes to tproxy
+	cleanTProxyRules(protocol string)
+	// setSubnetGatewayMetric updates the traffic metrics of the subnet gateways on the node
+	setSubnetGatewayMetric()
+}
+
+func (c *Controller) setGatewaySets() error {
+	return c.firewall.setSets()
+}
+
+func (c *Controller) setGatewayRules() error {
+	return c.firewall.setRules()
+}
+
+func (c *Controller) gcGatewaySets() {
+	c.firewall.gcSets()
+}
+
+func (c *Controller) setGatewayMssRule() {
+	c.firewall.setMssRule()
+}
+
+type iptablesFirewall struct {
+	c *Controller
+}
+
+func (f *iptablesFirewall) setSets() error {
+	return f.c.setIPSet()
+}
+
+func (f *iptablesFirewall) setRules() error {
+	return f.c.setIptables()
+}
+
+func (f *iptablesFirewall) gcSets() {
+	f.c.gcIPSet()
+}
+
+func (f *iptablesFirewall) setMssRule() {
+	f.c.appendMssRule()
+}
+
+func (f *iptablesFirewall) cleanTProxyRules(protocol string) {
+	f.c.cleanTProxyIPTableRules(protocol)
+}
+
+func (f *iptablesFirewall) setSubnetGatewayMetric() {
+	f.c.setOvnSubnetGatewayMetric()
+}
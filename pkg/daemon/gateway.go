@@ -16,14 +16,14 @@ import (
 )
 
 func (c *Controller) runGateway() {
-	if err := c.setIPSet(); err != nil {
-		klog.Errorf("failed to set gw ipsets")
+	if err := c.setGatewaySets(); err != nil {
+		klog.Errorf("failed to set gw sets")
 	}
 	if err := c.setPolicyRouting(); err != nil {
 		klog.Errorf("failed to set gw policy routing")
 	}
-	if err := c.setIptables(); err != nil {
-		klog.Errorf("failed to set gw iptables")
+	if err := c.setGatewayRules(); err != nil {
+		klog.Errorf("failed to set gw rules")
 	}
 
 	if err := c.setGatewayBandwidth(); err != nil {
@@ -35,8 +35,8 @@ func (c *Controller) runGateway() {
 	if err := c.setExGateway(); err != nil {
 		klog.Errorf("failed to set ex gateway, %v", err)
 	}
-	c.gcIPSet()
-	c.appendMssRule()
+	c.gcGatewaySets()
+	c.setGatewayMssRule()
 }
 
 func (c *Controller) setGatewayBandwidth() error {
@@ -225,19 +225,13 @@ func (c *Controller) reconcileNatOutGoingPolicyIPset(protocol string) {
 }
 
 func (c *Controller) setPolicyRouting() error {
-	protocols := make([]string, 2)
+	// the ipsets are not created with the nftables backend, so only the configured protocols are iterated
+	protocols := []string{c.protocol}
 	if c.protocol == kubeovnv1.ProtocolDual {
-		protocols[0] = kubeovnv1.ProtocolIPv4
-		protocols[1] = kubeovnv1.ProtocolIPv6
-	} else {
-		protocols[0] = c.protocol
+		protocols = []string{kubeovnv1.ProtocolIPv4, kubeovnv1.ProtocolIPv6}
 	}
 
 	for _, protocol := range protocols {
-		if c.ipsets[protocol] == nil {
-			continue
-		}
-
 		localPodIPs, err := c.getLocalPodIPsNeedPR(protocol)
 		if err != nil {
 			klog.Errorf("failed to get local pod ips failed: %+v", err)
@@ -801,19 +795,20 @@ func (c *Controller) setIptables() error {
 	return nil
 }
 
-func (c *Controller) reconcileTProxyIPTableRules(protocol string, isDual bool) error {
-	if !c.config.EnableTProxy {
-		return nil
-	}
+// tproxyProbe is a probe port of a pod in custom vpc, which is redirected to the tproxy listening on hostIP
+type tproxyProbe struct {
+	podIP  string
+	port   string
+	hostIP string
+}
 
-	ipt := c.iptables[protocol]
-	tproxyPreRoutingRules := make([]util.IPTableRule, 0)
-	tproxyOutputRules := make([]util.IPTableRule, 0)
+func (c *Controller) getTProxyProbes(protocol string, isDual bool) ([]tproxyProbe, error) {
+	var probes []tproxyProbe
 	probePorts := strset.New()
 
 	pods, err := c.getTProxyConditionPod(true)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for _, pod := range pods {
@@ -874,15 +869,7 @@ func (c *Controller) reconcileTProxyIPTableRules(protocol string, isDual bool) e
 		probePortList := probePorts.List()
 		sort.Strings(probePortList)
 		for _, probePort := range probePortList {
-			tProxyOutputMarkMask := fmt.Sprintf("%#x/%#x", TProxyOutputMark, TProxyOutputMask)
-			tProxyPreRoutingMarkMask := fmt.Sprintf("%#x/%#x", TProxyPreroutingMark, TProxyPreroutingMask)
-
 			hostIP := pod.Status.HostIP
-			prefixLen := 32
-			if protocol == kubeovnv1.ProtocolIPv6 {
-				prefixLen = 128
-			}
-
 			if isDual || os.Getenv("ENABLE_BIND_LOCAL_IP") == "false" {
 				if protocol == kubeovnv1.ProtocolIPv4 {
 					hostIP = "0.0.0.0"
@@ -890,10 +877,36 @@ func (c *Controller) reconcileTProxyIPTableRules(protocol string, isDual bool) e
 					hostIP = "::"
 				}
 			}
-			tproxyOutputRules = append(tproxyOutputRules, util.IPTableRule{Table: MANGLE, Chain: OvnOutput, Rule: strings.Fields(fmt.Sprintf(`-d %s/%d -p tcp -m tcp --dport %s -j MARK --set-xmark %s`, podIP, prefixLen, probePort, tProxyOutputMarkMask))})
-			tproxyPreRoutingRules = append(tproxyPreRoutingRules, util.IPTableRule{Table: MANGLE, Chain: OvnPrerouting, Rule: strings.Fields(fmt.Sprintf(`-d %s/%d -p tcp -m tcp --dport %s -j TPROXY --on-port %d --on-ip %s --tproxy-mark %s`, podIP, prefixLen, probePort, util.TProxyListenPort, hostIP, tProxyPreRoutingMarkMask))})
+			probes = append(probes, tproxyProbe{podIP: podIP, port: probePort, hostIP: hostIP})
 		}
 	}
+	return probes, nil
+}
+
+func (c *Controller) reconcileTProxyIPTableRules(protocol string, isDual bool) error {
+	if !c.config.EnableTProxy {
+		return nil
+	}
+
+	ipt := c.iptables[protocol]
+	tproxyPreRoutingRules := make([]util.IPTableRule, 0)
+	tproxyOutputRules := make([]util.IPTableRule, 0)
+
+	probes, err := c.getTProxyProbes(protocol, isDual)
+	if err != nil {
+		return err
+	}
+
+	tProxyOutputMarkMask := fmt.Sprintf("%#x/%#x", TProxyOutputMark, TProxyOutputMask)
+	tProxyPreRoutingMarkMask := fmt.Sprintf("%#x/%#x", TProxyPreroutingMark, TProxyPreroutingMask)
+	prefixLen := 32
+	if protocol == kubeovnv1.ProtocolIPv6 {
+		prefixLen = 128
+	}
+	for _, probe := range probes {
+		tproxyOutputRules = append(tproxyOutputRules, util.IPTableRule{Table: MANGLE, Chain: OvnOutput, Rule: strings.Fields(fmt.Sprintf(`-d %s/%d -p tcp -m tcp --dport %s -j MARK --set-xmark %s`, probe.podIP, prefixLen, probe.port, tProxyOutputMarkMask))})
+		tproxyPreRoutingRules = append(tproxyPreRoutingRules, util.IPTableRule{Table: MANGLE, Chain: OvnPrerouting, Rule: strings.Fields(fmt.Sprintf(`-d %s/%d -p tcp -m tcp --dport %s -j TPROXY --on-port %d --on-ip %s --tproxy-mark %s`, probe.podIP, prefixLen, probe.port, util.TProxyListenPort, probe.hostIP, tProxyPreRoutingMarkMask))})
+	}
 
 	if err := c.updateIptablesChain(ipt, MANGLE, OvnPrerouting, Prerouting, tproxyPreRoutingRules); err != nil {
 		klog.Errorf("failed to update chain %s with rules %v: %v", OvnPrerouting, tproxyPreRoutingRules, err)
@@ -1253,59 +1266,64 @@ func (c *Controller) setOvnSubnetGatewayMetric() {
 				}
 			}
 
-			proto := util.CheckProtocol(cidr)
+			c.updateSubnetGatewayMetric(hostname, subnetName, cidr, direction, currentPackets, currentPacketBytes)
+		}
+	}
+}
 
-			if cidr == "" || direction == "" || subnetName == "" && proto != "" {
-				continue
-			}
+// updateSubnetGatewayMetric accounts the increment of the subnet gateway counters since the last round
+func (c *Controller) updateSubnetGatewayMetric(hostname, subnetName, cidr, direction string, currentPackets, currentPacketBytes int) {
+	proto := util.CheckProtocol(cidr)
 
-			lastPacketBytes := 0
-			lastPackets := 0
-			diffPacketBytes := 0
-			diffPackets := 0
+	if cidr == "" || direction == "" || subnetName == "" && proto != "" {
+		return
+	}
 
-			key := strings.Join([]string{subnetName, direction, proto}, "/")
-			if ret, ok := c.gwCounters[key]; ok {
-				lastPackets = ret.Packets
-				lastPacketBytes = ret.PacketBytes
-			} else {
-				c.gwCounters[key] = &util.GwIPtableCounters{
-					Packets:     lastPackets,
-					PacketBytes: lastPacketBytes,
-				}
-			}
+	lastPacketBytes := 0
+	lastPackets := 0
+	diffPacketBytes := 0
+	diffPackets := 0
 
-			if lastPacketBytes == 0 && lastPackets == 0 {
-				// the gwCounters may just initialize don't cal the diff values,
-				// it may loss packets to calculate during a metric period
-				c.gwCounters[key].Packets = currentPackets
-				c.gwCounters[key].PacketBytes = currentPacketBytes
-				continue
-			}
+	key := strings.Join([]string{subnetName, direction, proto}, "/")
+	if ret, ok := c.gwCounters[key]; ok {
+		lastPackets = ret.Packets
+		lastPacketBytes = ret.PacketBytes
+	} else {
+		c.gwCounters[key] = &util.GwIPtableCounters{
+			Packets:     lastPackets,
+			PacketBytes: lastPacketBytes,
+		}
+	}
 
-			if currentPackets >= lastPackets && currentPacketBytes >= lastPacketBytes {
-				diffPacketBytes = currentPacketBytes - lastPacketBytes
-				diffPackets = currentPackets - lastPackets
-			} else {
-				// if currentPacketBytes < lastPacketBytes, the reason is that iptables rule is reset ,
-				// it may loss packets to calculate during a metric period
-				c.gwCounters[key].Packets = currentPackets
-				c.gwCounters[key].PacketBytes = currentPacketBytes
-				continue
-			}
+	if lastPacketBytes == 0 && lastPackets == 0 {
+		// the gwCounters may just initialize don't cal the diff values,
+		// it may loss packets to calculate during a metric period
+		c.gwCounters[key].Packets = currentPackets
+		c.gwCounters[key].PacketBytes = currentPacketBytes
+		return
+	}
 
-			c.gwCounters[key].Packets = currentPackets
-			c.gwCounters[key].PacketBytes = currentPacketBytes
+	if currentPackets >= lastPackets && currentPacketBytes >= lastPacketBytes {
+		diffPacketBytes = currentPacketBytes - lastPacketBytes
+		diffPackets = currentPackets - lastPackets
+	} else {
+		// if currentPacketBytes < lastPacketBytes, the reason is that iptables rule is reset ,
+		// it may loss packets to calculate during a metric period
+		c.gwCounters[key].Packets = currentPackets
+		c.gwCounters[key].PacketBytes = currentPacketBytes
+		return
+	}
 
-			klog.V(3).Infof(`hostname %s key %s cidr %s direction %s proto %s has diffPackets %d diffPacketBytes %d currentPackets %d currentPacketBytes %d lastPackets %d lastPacketBytes %d`,
-				hostname, key, cidr, direction, proto, diffPackets, diffPacketBytes, currentPackets, currentPacketBytes, lastPackets, lastPacketBytes)
-			if diffPackets > 0 {
-				metricOvnSubnetGatewayPackets.WithLabelValues(hostname, key, cidr, direction, proto).Add(float64(diffPackets))
-			}
-			if diffPacketBytes > 0 {
-				metricOvnSubnetGatewayPacketBytes.WithLabelValues(hostname, key, cidr, direction, proto).Add(float64(diffPacketBytes))
-			}
-		}
+	c.gwCounters[key].Packets = currentPackets
+	c.gwCounters[key].PacketBytes = currentPacketBytes
+
+	klog.V(3).Infof(`hostname %s key %s cidr %s direction %s proto %s has diffPackets %d diffPacketBytes %d currentPackets %d currentPacketBytes %d lastPackets %d lastPacketBytes %d`,
+		hostname, key, cidr, direction, proto, diffPackets, diffPacketBytes, currentPackets, currentPacketBytes, lastPackets, lastPacketBytes)
+	if diffPackets > 0 {
+		metricOvnSubnetGatewayPackets.WithLabelValues(hostname, key, cidr, direction, proto).Add(float64(diffPackets))
+	}
+	if diffPacketBytes > 0 {
+		metricOvnSubnetGatewayPacketBytes.WithLabelValues(hostname, key, cidr, direction, proto).Add(float64(diffPacketBytes))
 	}
 }
 
@@ -320,7 +320,7 @@ func (csh cniServerHandler) handleAdd(req *restful.Request, resp *restful.Respon
 		}
 
 		ifaceID := ovs.PodNameToPortName(podRequest.PodName, podRequest.PodNamespace, podRequest.Provider)
-		if err = ovs.ConfigInterfaceMirror(csh.Config.EnableMirror, pod.Annotations[util.MirrorControlAnnotation], ifaceID); err != nil {
+		if err = csh.Config.configInterfaceMirror(pod.Annotations[util.MirrorControlAnnotation], ifaceID); err != nil {
 			klog.Errorf("failed mirror to mirror0, %v", err)
 			return
 		}
@@ -0,0 +1,314 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/scylladb/go-set/strset"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeovn/kube-ovn/pkg/ovs"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+const (
+	lbHealthCheckDefaultTimeout     = time.Second
+	lbHealthCheckFailureThreshold   = 3
+	lbHealthCheckDefaultHTTPPath    = "/"
+	lbHealthCheckUnhealthySeparator = ","
+)
+
+// lbHealthCheck is the active health check of the service backends specified by the service annotations
+type lbHealthCheck struct {
+	Type    string
+	Port    *intstr.IntOrString
+	Path    string
+	Timeout time.Duration
+}
+
+func parseLbHealthCheck(svc *v1.Service) (*lbHealthCheck, error) {
+	hcType := svc.Annotations[util.LbHealthCheckTypeAnnotation]
+	if hcType == "" {
+		return nil, nil
+	}
+	if hcType != util.LbHealthCheckTypeHTTP && hcType != util.LbHealthCheckTypeGRPC {
+		return nil, fmt.Errorf("unsupported health check type %q", hcType)
+	}
+
+	hc := &lbHealthCheck{
+		Type:    hcType,
+		Path:    svc.Annotations[util.LbHealthCheckPathAnnotation],
+		Timeout: lbHealthCheckDefaultTimeout,
+	}
+	if hc.Type == util.LbHealthCheckTypeHTTP && hc.Path == "" {
+		hc.Path = lbHealthCheckDefaultHTTPPath
+	}
+	if port := svc.Annotations[util.LbHealthCheckPortAnnotation]; port != "" {
+		p := intstr.Parse(port)
+		hc.Port = &p
+	}
+	if timeout := svc.Annotations[util.LbHealthCheckTimeoutAnnotation]; timeout != "" {
+		seconds, err := strconv.Atoi(timeout)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("invalid health check timeout %q", timeout)
+		}
+		hc.Timeout = time.Duration(seconds) * time.Second
+	}
+	return hc, nil
+}
+
+// checkLbBackendsHealth probes the local backends of the services with active health check,
+// and publishes the services whose health check failed to the backend pods' annotation,
+// which will be used by kube-ovn-controller to remove the unhealthy backends from the ovn load balancers
+func (c *Controller) checkLbBackendsHealth() {
+	services, err := c.servicesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list services: %v", err)
+		return
+	}
+
+	checked := strset.New()
+	unhealthy := make(map[string][]string)
+	for _, svc := range services {
+		hc, err := parseLbHealthCheck(svc)
+		if err != nil {
+			klog.Errorf("invalid lb health check of service %s/%s: %v", svc.Namespace, svc.Name, err)
+			continue
+		}
+		if hc == nil || len(svc.Spec.Selector) == 0 || len(svc.Spec.Ports) == 0 {
+			continue
+		}
+
+		pods, err := c.podsLister.Pods(svc.Namespace).List(labels.Set(svc.Spec.Selector).AsSelector())
+		if err != nil {
+			klog.Errorf("failed to list pods of service %s/%s: %v", svc.Namespace, svc.Name, err)
+			continue
+		}
+		for _, pod := range pods {
+			if pod.Spec.NodeName != c.config.NodeName || pod.Spec.HostNetwork ||
+				pod.Status.Phase != v1.PodRunning || pod.Status.PodIP == "" || pod.DeletionTimestamp != nil {
+				continue
+			}
+
+			podKey := cache.MetaObjectToName(pod).String()
+			failureKey := fmt.Sprintf("%s/%s", podKey, svc.Name)
+			checked.Add(failureKey)
+			if err = probeLbBackend(pod, svc, hc); err != nil {
+				c.lbHealthCheckFailures[failureKey]++
+				klog.V(3).Infof("%s health check of pod %s for service %s/%s failed: %v", hc.Type, podKey, svc.Namespace, svc.Name, err)
+			} else {
+				delete(c.lbHealthCheckFailures, failureKey)
+			}
+			if c.lbHealthCheckFailures[failureKey] >= lbHealthCheckFailureThreshold {
+				unhealthy[podKey] = append(unhealthy[podKey], svc.Name)
+			}
+		}
+	}
+	for key := range c.lbHealthCheckFailures {
+		if !checked.Has(key) {
+			delete(c.lbHealthCheckFailures, key)
+		}
+	}
+
+	pods, err := c.podsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list pods: %v", err)
+		return
+	}
+	for _, pod := range pods {
+		if pod.Spec.NodeName != c.config.NodeName || pod.Spec.HostNetwork {
+			continue
+		}
+		svcNames := unhealthy[cache.MetaObjectToName(pod).String()]
+		slices.Sort(svcNames)
+		if err = c.updateLbUnhealthyServices(pod, strings.Join(svcNames, lbHealthCheckUnhealthySeparator)); err != nil {
+			klog.Errorf("failed to update unhealthy services of pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+	}
+}
+
+func (c *Controller) updateLbUnhealthyServices(pod *v1.Pod, svcNames string) error {
+	if pod.Annotations[util.LbUnhealthyServicesAnnotation] == svcNames {
+		return nil
+	}
+
+	newPod := pod.DeepCopy()
+	if svcNames == "" {
+		delete(newPod.Annotations, util.LbUnhealthyServicesAnnotation)
+	} else {
+		if newPod.Annotations == nil {
+			newPod.Annotations = make(map[string]string, 1)
+		}
+		newPod.Annotations[util.LbUnhealthyServicesAnnotation] = svcNames
+	}
+
+	patch, err := util.GenerateMergePatchPayload(pod, newPod)
+	if err != nil {
+		klog.Errorf("failed to generate patch payload for pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		return err
+	}
+	if _, err = c.config.KubeClient.CoreV1().Pods(pod.Namespace).Patch(context.Background(), pod.Name,
+		types.MergePatchType, patch, metav1.PatchOptions{}, ""); err != nil {
+		klog.Errorf("failed to patch pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		return err
+	}
+	if svcNames != "" {
+		klog.Infof("pod %s/%s is unhealthy for services %s", pod.Namespace, pod.Name, svcNames)
+	}
+	return nil
+}
+
+// lbHealthCheckPorts returns the ports of the pod to be checked, which are the port of the annotation if specified,
+// otherwise the target ports of all the service ports. The unhealthy backends are removed from the vips of all
+// the service ports, so a backend of a multi-port service is healthy only if all the target ports pass the check.
+func lbHealthCheckPorts(pod *v1.Pod, svc *v1.Service, hc *lbHealthCheck) ([]int32, error) {
+	if hc.Port != nil {
+		port, err := resolveLbHealthCheckPort(pod, *hc.Port)
+		if err != nil {
+			return nil, err
+		}
+		return []int32{port}, nil
+	}
+
+	ports := make([]int32, 0, len(svc.Spec.Ports))
+	for _, svcPort := range svc.Spec.Ports {
+		port := svcPort.Port
+		if svcPort.TargetPort.Type != intstr.Int || svcPort.TargetPort.IntVal != 0 {
+			var err error
+			if port, err = resolveLbHealthCheckPort(pod, svcPort.TargetPort); err != nil {
+				return nil, err
+			}
+		}
+		if !slices.Contains(ports, port) {
+			ports = append(ports, port)
+		}
+	}
+	return ports, nil
+}
+
+func resolveLbHealthCheckPort(pod *v1.Pod, port intstr.IntOrString) (int32, error) {
+	if port.Type == intstr.Int {
+		return port.IntVal, nil
+	}
+	for _, container := range pod.Spec.Containers {
+		for _, p := range container.Ports {
+			if p.Name == port.StrVal {
+				return p.ContainerPort, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("named port %s not found", port.StrVal)
+}
+
+// probeLbBackend probes the backend pod in its network namespace,
+// so that the pods in custom vpcs which are unreachable from the node can be checked
+func probeLbBackend(pod *v1.Pod, svc *v1.Service, hc *lbHealthCheck) error {
+	ports, err := lbHealthCheckPorts(pod, svc, hc)
+	if err != nil {
+		return err
+	}
+
+	iface := ovs.PodNameToPortName(pod.Name, pod.Namespace, util.OvnProvider)
+	nsPath, err := ovs.GetInterfacePodNs(iface)
+	if err != nil {
+		return err
+	}
+	if nsPath == "" {
+		return fmt.Errorf("netns of interface %s not found", iface)
+	}
+
+	for _, port := range ports {
+		if err = probeLbBackendPort(nsPath, util.JoinHostPort(pod.Status.PodIP, port), hc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func probeLbBackendPort(nsPath, addr string, hc *lbHealthCheck) error {
+	var conn net.Conn
+	if err := ns.WithNetNSPath(nsPath, func(_ ns.NetNS) error {
+		var err error
+		conn, err = net.DialTimeout("tcp", addr, hc.Timeout)
+		return err
+	}); err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	switch hc.Type {
+	case util.LbHealthCheckTypeHTTP:
+		return probeHTTP(conn, addr, hc)
+	case util.LbHealthCheckTypeGRPC:
+		return probeGRPC(conn, addr, hc)
+	}
+	return nil
+}
+
+func probeHTTP(conn net.Conn, addr string, hc *lbHealthCheck) error {
+	client := &http.Client{
+		Timeout: hc.Timeout,
+		Transport: &http.Transport{
+			DialContext: func(context.Context, string, string) (net.Conn, error) {
+				return conn, nil
+			},
+			DisableKeepAlives: true,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(fmt.Sprintf("http://%s%s", addr, hc.Path))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// the same as kubelet http probe, any code greater than or equal to 200 and less than 400 indicates success
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected http status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func probeGRPC(conn net.Conn, addr string, hc *lbHealthCheck) error {
+	ctx, cancel := context.WithTimeout(context.Background(), hc.Timeout)
+	defer cancel()
+
+	client, err := grpc.DialContext(ctx, addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return conn, nil
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	resp, err := healthpb.NewHealthClient(client).Check(ctx, &healthpb.HealthCheckRequest{Service: hc.Path})
+	if err != nil {
+		return err
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("unexpected grpc health status %s", resp.GetStatus())
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+func Test_parseLbHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	port := intstr.FromString("health")
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		exp         *lbHealthCheck
+		expErr      bool
+	}{
+		{
+			name: "no health check",
+		},
+		{
+			name:        "bad type",
+			annotations: map[string]string{util.LbHealthCheckTypeAnnotation: "tcp"},
+			expErr:      true,
+		},
+		{
+			name:        "http with default path and timeout",
+			annotations: map[string]string{util.LbHealthCheckTypeAnnotation: util.LbHealthCheckTypeHTTP},
+			exp:         &lbHealthCheck{Type: util.LbHealthCheckTypeHTTP, Path: lbHealthCheckDefaultHTTPPath, Timeout: lbHealthCheckDefaultTimeout},
+		},
+		{
+			name: "http with path, port and timeout",
+			annotations: map[string]string{
+				util.LbHealthCheckTypeAnnotation:    util.LbHealthCheckTypeHTTP,
+				util.LbHealthCheckPathAnnotation:    "/healthz",
+				util.LbHealthCheckPortAnnotation:    "health",
+				util.LbHealthCheckTimeoutAnnotation: "3",
+			},
+			exp: &lbHealthCheck{Type: util.LbHealthCheckTypeHTTP, Port: &port, Path: "/healthz", Timeout: 3 * time.Second},
+		},
+		{
+			name:        "grpc without path",
+			annotations: map[string]string{util.LbHealthCheckTypeAnnotation: util.LbHealthCheckTypeGRPC},
+			exp:         &lbHealthCheck{Type: util.LbHealthCheckTypeGRPC, Timeout: lbHealthCheckDefaultTimeout},
+		},
+		{
+			name: "bad timeout",
+			annotations: map[string]string{
+				util.LbHealthCheckTypeAnnotation:    util.LbHealthCheckTypeHTTP,
+				util.LbHealthCheckTimeoutAnnotation: "1s",
+			},
+			expErr: true,
+		},
+		{
+			name: "non-positive timeout",
+			annotations: map[string]string{
+				util.LbHealthCheckTypeAnnotation:    util.LbHealthCheckTypeGRPC,
+				util.LbHealthCheckTimeoutAnnotation: "0",
+			},
+			expErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Annotations: tt.annotations}}
+			hc, err := parseLbHealthCheck(svc)
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.exp, hc)
+		})
+	}
+}
+
+func Test_lbHealthCheckPorts(t *testing.T) {
+	t.Parallel()
+
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8080}}},
+				{Ports: []v1.ContainerPort{{Name: "grpc", ContainerPort: 9090}, {Name: "health", ContainerPort: 8081}}},
+			},
+		},
+	}
+	healthPort, numericPort := intstr.FromString("health"), intstr.FromInt32(8082)
+
+	tests := []struct {
+		name   string
+		ports  []v1.ServicePort
+		hcPort *intstr.IntOrString
+		exp    []int32
+		expErr bool
+	}{
+		{
+			name:  "target port not specified",
+			ports: []v1.ServicePort{{Port: 80}},
+			exp:   []int32{80},
+		},
+		{
+			name:  "numeric target port",
+			ports: []v1.ServicePort{{Port: 80, TargetPort: intstr.FromInt32(8080)}},
+			exp:   []int32{8080},
+		},
+		{
+			name:  "named target port",
+			ports: []v1.ServicePort{{Port: 80, TargetPort: intstr.FromString("http")}},
+			exp:   []int32{8080},
+		},
+		{
+			name:   "named target port not found",
+			ports:  []v1.ServicePort{{Port: 80, TargetPort: intstr.FromString("https")}},
+			expErr: true,
+		},
+		{
+			name: "multiple ports",
+			ports: []v1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+				{Name: "grpc", Port: 90, TargetPort: intstr.FromString("grpc")},
+				{Name: "metrics", Port: 9100, TargetPort: intstr.FromInt32(9100)},
+				{Name: "http-alt", Port: 8080, TargetPort: intstr.FromInt32(8080)},
+			},
+			exp: []int32{8080, 9090, 9100},
+		},
+		{
+			name: "named port of annotation",
+			ports: []v1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: intstr.FromString("http")},
+				{Name: "grpc", Port: 90, TargetPort: intstr.FromString("grpc")},
+			},
+			hcPort: &healthPort,
+			exp:    []int32{8081},
+		},
+		{
+			name:   "numeric port of annotation",
+			ports:  []v1.ServicePort{{Port: 80, TargetPort: intstr.FromString("https")}},
+			hcPort: &numericPort,
+			exp:    []int32{8082},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc := &v1.Service{Spec: v1.ServiceSpec{Ports: tt.ports}}
+			ports, err := lbHealthCheckPorts(pod, svc, &lbHealthCheck{Type: util.LbHealthCheckTypeHTTP, Port: tt.hcPort})
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.exp, ports)
+		})
+	}
+}
@@ -0,0 +1,4 @@
+package daemon
+
+func (c *Controller) checkLbBackendsHealth() {
+}
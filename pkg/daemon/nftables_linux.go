@@ -0,0 +1,595 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kubeovn/go-iptables/iptables"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/nftables"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+const (
+	nftTable = "kube-ovn"
+
+	nftNatPrerouting     = "nat-prerouting"
+	nftNatPostrouting    = "nat-postrouting"
+	nftMasquerade        = "masquerade"
+	nftNatPolicy         = "nat-policy"
+	nftNatPolicySubnet   = "nat-policy-subnet-"
+	nftFilterInput       = "filter-input"
+	nftFilterForward     = "filter-forward"
+	nftFilterOutput      = "filter-output"
+	nftManglePrerouting  = "mangle-prerouting"
+	nftMangleOutput      = "mangle-output"
+	nftManglePostrouting = "mangle-postrouting"
+
+	nftClusterIPSet     = "cluster-ip"
+	nftNodePortLocalSet = "node-port-local"
+
+	// iptablesAcceptChain holds the accept rules in the filter table of iptables, the verdict of a
+	// base chain only ends the traversal of its own table, so the accept rules of the kube-ovn table
+	// can not override the DROP policy set by others in the FORWARD chain of iptables
+	iptablesAcceptChain = "OVN-ACCEPT"
+)
+
+// nftablesFirewall programs the node gateway rules in a table of each ip family, the sets and
+// rules are generated from scratch in each round and the tables are replaced in one transaction
+type nftablesFirewall struct {
+	c   *Controller
+	nft nftables.Interface
+
+	// the script applied in the last round, the tables are not replaced if nothing changes
+	// so that the counters of the subnet gateway metrics are not reset
+	lastScript      string
+	iptablesCleaned bool
+	iptables        map[string]*iptables.IPTables
+}
+
+func newNftablesFirewall(c *Controller) *nftablesFirewall {
+	return &nftablesFirewall{c: c, nft: nftables.New(), iptables: make(map[string]*iptables.IPTables)}
+}
+
+// setSets does nothing since the sets are replaced along with the rules referring to them
+func (f *nftablesFirewall) setSets() error {
+	return nil
+}
+
+func (f *nftablesFirewall) setRules() error {
+	klog.V(3).Infoln("start to set up nftables")
+	protocols := getProtocols(f.c.protocol)
+	tables := make([]*nftables.Table, 0, len(protocols))
+	var script strings.Builder
+	for _, protocol := range protocols {
+		table, err := f.c.generateNftablesTable(protocol, len(protocols) == 2)
+		if err != nil {
+			klog.Errorf("failed to generate nftables table of protocol %s: %v", protocol, err)
+			return err
+		}
+		tables = append(tables, table)
+		script.WriteString(table.Script())
+
+		if err = f.setIptablesAcceptRules(protocol); err != nil {
+			klog.Errorf("failed to set iptables accept rules of protocol %s: %v", protocol, err)
+			return err
+		}
+	}
+
+	if script.String() == f.lastScript && f.tablesExist(tables) {
+		return nil
+	}
+	if err := f.nft.Replace(tables...); err != nil {
+		return err
+	}
+	f.lastScript = script.String()
+
+	if !f.iptablesCleaned {
+		for _, protocol := range protocols {
+			cleanIptablesBackendRules(protocol)
+		}
+		f.iptablesCleaned = true
+	}
+	return nil
+}
+
+// setIptablesAcceptRules accepts the traffic of the subnets and services in the filter table of iptables,
+// nothing is done if iptables is not installed since there is no DROP policy to override
+func (f *nftablesFirewall) setIptablesAcceptRules(protocol string) error {
+	ipt := f.iptables[protocol]
+	if ipt == nil {
+		var err error
+		if ipt, err = iptables.NewWithProtocol(iptablesProtocol(protocol)); err != nil {
+			klog.V(3).Infof("skip setting the iptables accept rules: %v", err)
+			return nil
+		}
+		f.iptables[protocol] = ipt
+	}
+
+	subnets, _, err := f.c.getDefaultVpcSubnetsCIDR(protocol)
+	if err != nil {
+		klog.Errorf("get subnets failed, %+v", err)
+		return err
+	}
+	cidrs := nftSetElements(protocol, append(subnets, f.c.getServicesCIDR(protocol)...))
+	rules := make([]util.IPTableRule, 0, 2*len(cidrs))
+	for _, direction := range [...]string{"-s", "-d"} {
+		for _, cidr := range cidrs {
+			rules = append(rules, util.IPTableRule{Table: "filter", Chain: iptablesAcceptChain, Rule: []string{direction, cidr, "-j", "ACCEPT"}})
+		}
+	}
+	for _, parent := range [...]string{"INPUT", "FORWARD"} {
+		if err = f.c.updateIptablesChain(ipt, "filter", iptablesAcceptChain, parent, rules); err != nil {
+			klog.Error(err)
+			return err
+		}
+	}
+	return nil
+}
+
+func iptablesProtocol(protocol string) iptables.Protocol {
+	if protocol == kubeovnv1.ProtocolIPv6 {
+		return iptables.ProtocolIPv6
+	}
+	return iptables.ProtocolIPv4
+}
+
+func (f *nftablesFirewall) tablesExist(tables []*nftables.Table) bool {
+	for _, table := range tables {
+		if _, exists, err := f.nft.List(table.Family, table.Name); err != nil || !exists {
+			return false
+		}
+	}
+	return true
+}
+
+// gcSets does nothing since the stale sets are removed by the table replacement
+func (f *nftablesFirewall) gcSets() {}
+
+// setMssRule does nothing since the mss rule is a part of the mangle rules
+func (f *nftablesFirewall) setMssRule() {}
+
+// cleanTProxyRules does nothing since the tproxy rules are only generated if tproxy is enabled
+func (f *nftablesFirewall) cleanTProxyRules(_ string) {}
+
+func (f *nftablesFirewall) setSubnetGatewayMetric() {
+	hostname := os.Getenv(util.HostnameEnv)
+	for _, protocol := range getProtocols(f.c.protocol) {
+		output, exists, err := f.nft.List(nftFamily(protocol), nftTable)
+		if err != nil || !exists {
+			continue
+		}
+		for _, rule := range nftables.ParseRules(output) {
+			if rule.Chain != nftFilterForward {
+				continue
+			}
+			comments := strings.Split(rule.Comment, ",")
+			if len(comments) != 2 || comments[0] != util.OvnSubnetGatewayIptables {
+				continue
+			}
+			// ip saddr 10.16.0.0/16 counter packets 0 bytes 0 comment "ovn-subnet-gateway,ovn-default"
+			fields := strings.Fields(rule.Rule)
+			if len(fields) < 3 {
+				continue
+			}
+			var direction string
+			switch fields[1] {
+			case "saddr":
+				direction = "egress"
+			case "daddr":
+				direction = "ingress"
+			}
+			f.c.updateSubnetGatewayMetric(hostname, comments[1], fields[2], direction, int(rule.Packets), int(rule.Bytes))
+		}
+	}
+}
+
+func nftFamily(protocol string) string {
+	if protocol == kubeovnv1.ProtocolIPv6 {
+		return nftables.FamilyIP6
+	}
+	return nftables.FamilyIP
+}
+
+// nftMarkMatch matches the packet mark in the same way as iptables -m mark --mark value/mask
+func nftMarkMatch(markMask string) string {
+	value, mask := parseMark(markMask)
+	return fmt.Sprintf("meta mark & %#x == %#x", mask, value)
+}
+
+// nftMarkNotMatch matches the packet mark in the same way as iptables -m mark ! --mark value/mask
+func nftMarkNotMatch(markMask string) string {
+	value, mask := parseMark(markMask)
+	return fmt.Sprintf("meta mark & %#x != %#x", mask, value)
+}
+
+// nftMarkSet sets the packet mark in the same way as iptables -j MARK --set-xmark value/mask
+func nftMarkSet(markMask string) string {
+	value, mask := parseMark(markMask)
+	if mask == 0xffffffff {
+		return fmt.Sprintf("meta mark set %#x", value)
+	}
+	if value == mask {
+		return fmt.Sprintf("meta mark set meta mark | %#x", value)
+	}
+	return fmt.Sprintf("meta mark set meta mark & %#x ^ %#x", ^mask, value)
+}
+
+func parseMark(markMask string) (uint32, uint32) {
+	v, m, found := strings.Cut(markMask, "/")
+	value, _ := strconv.ParseUint(v, 0, 32)
+	mask := uint64(0xffffffff)
+	if found {
+		mask, _ = strconv.ParseUint(m, 0, 32)
+	}
+	return uint32(value), uint32(mask)
+}
+
+// nftSetElements filters the addresses of the protocol, duplicated elements are removed
+func nftSetElements(protocol string, elements []string) []string {
+	result := make([]string, 0, len(elements))
+	for _, e := range elements {
+		if e = strings.TrimSpace(e); e != "" && util.CheckProtocol(e) == protocol {
+			result = append(result, e)
+		}
+	}
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// kubeProxyServiceElements returns the elements of the sets taking the place of the ipsets
+// KUBE-CLUSTER-IP and KUBE-NODE-PORT-LOCAL-TCP/UDP of kube-proxy, they are generated from services
+// since the ipsets are not accessible from nftables
+func (c *Controller) kubeProxyServiceElements(protocol string) ([]string, []string, error) {
+	services, err := c.servicesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list services: %v", err)
+		return nil, nil, err
+	}
+
+	var clusterIPs, nodePortLocal []string
+	for _, svc := range services {
+		local := svc.Spec.ExternalTrafficPolicy == v1.ServiceExternalTrafficPolicyTypeLocal
+		for _, port := range svc.Spec.Ports {
+			proto := strings.ToLower(string(port.Protocol))
+			if proto == "" {
+				proto = "tcp"
+			}
+			for _, ip := range svc.Spec.ClusterIPs {
+				if util.CheckProtocol(ip) == protocol {
+					clusterIPs = append(clusterIPs, fmt.Sprintf("%s . %s . %d", ip, proto, port.Port))
+				}
+			}
+			if local && port.NodePort != 0 && (proto == "tcp" || proto == "udp") {
+				nodePortLocal = append(nodePortLocal, fmt.Sprintf("%s . %d", proto, port.NodePort))
+			}
+		}
+	}
+	slices.Sort(clusterIPs)
+	slices.Sort(nodePortLocal)
+	return slices.Compact(clusterIPs), slices.Compact(nodePortLocal), nil
+}
+
+// kubeProxyIPSetExists checks whether kube-proxy works in ipvs mode and has created the ipset
+func (c *Controller) kubeProxyIPSetExists(name string) bool {
+	exists, err := c.ipsetExists(name)
+	if err != nil {
+		klog.V(3).Infof("failed to check existence of ipset %s: %v", name, err)
+		return false
+	}
+	return exists
+}
+
+// generateNftablesTable translates the rules programmed by setIptables to nftables, the rules matching the
+// ipsets of kube-proxy match the sets generated from services instead, and the accept rules are placed
+// in the filter table of iptables by setIptablesAcceptRules
+func (c *Controller) generateNftablesTable(protocol string, isDual bool) (*nftables.Table, error) {
+	node, err := c.nodesLister.Get(c.config.NodeName)
+	if err != nil {
+		klog.Errorf("failed to get node %s, %v", c.config.NodeName, err)
+		return nil, err
+	}
+	nodeIPv4, nodeIPv6 := util.GetNodeInternalIP(*node)
+	addr, setType, prefix, nodeIP, kubeProxyIPSetProtocol := "ip", nftables.TypeIPv4Addr, "ovn40", nodeIPv4, ""
+	if protocol == kubeovnv1.ProtocolIPv6 {
+		addr, setType, prefix, nodeIP, kubeProxyIPSetProtocol = "ip6", nftables.TypeIPv6Addr, "ovn60", nodeIPv6, "6-"
+	}
+
+	services := c.getServicesCIDR(protocol)
+	subnets, subnetCidrs, err := c.getDefaultVpcSubnetsCIDR(protocol)
+	if err != nil {
+		klog.Errorf("get subnets failed, %+v", err)
+		return nil, err
+	}
+	subnetsNeedNat, err := c.getSubnetsNeedNAT(protocol)
+	if err != nil {
+		klog.Errorf("get need nat subnets failed, %+v", err)
+		return nil, err
+	}
+	subnetsDistributedGateway, err := c.getSubnetsDistributedGateway(protocol)
+	if err != nil {
+		klog.Errorf("failed to get subnets with centralized gateway: %v", err)
+		return nil, err
+	}
+	otherNode, err := c.getOtherNodes(protocol)
+	if err != nil {
+		klog.Errorf("failed to get node, %+v", err)
+		return nil, err
+	}
+	natPolicySubnets, err := c.getSubnetsNatOutGoingPolicy(protocol)
+	if err != nil {
+		klog.Errorf("failed to get subnets with NAT outgoing policy rule: %v", err)
+		return nil, err
+	}
+	centralGwNatIPs, err := c.getEgressNatIPByNode(c.config.NodeName)
+	if err != nil {
+		klog.Errorf("failed to get centralized subnets nat ips on node %s, %v", c.config.NodeName, err)
+		return nil, err
+	}
+	clusterIPs, nodePortLocal, err := c.kubeProxyServiceElements(protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	table := nftables.NewTable(nftFamily(protocol), nftTable)
+	set := func(name string, elements []string) string {
+		table.AddSet(prefix+name, setType, true, nftSetElements(protocol, elements))
+		return "@" + prefix + name
+	}
+	servicesSet := set(ServiceSet, services)
+	subnetsSet := set(SubnetSet, subnets)
+	subnetsNatSet := set(SubnetNatSet, subnetsNeedNat)
+	distributedGwSet := set(SubnetDistributedGwSet, subnetsDistributedGateway)
+	otherNodeSet := set(OtherNodeSet, otherNode)
+	clusterIPSet := "@" + prefix + nftClusterIPSet
+	table.AddSet(prefix+nftClusterIPSet, strings.Join([]string{setType, nftables.TypeInetProto, nftables.TypeInetService}, " . "), false, clusterIPs)
+
+	// nat outgoing policy rules
+	masquerade := table.AddChain(nftMasquerade)
+	masquerade.AddRule("meta mark set 0x0")
+	masquerade.AddRule("masquerade fully-random")
+
+	natPolicySubnetCidrs := make([]string, 0, len(natPolicySubnets))
+	natPolicyRules := make([]string, 0, len(natPolicySubnets))
+	slices.SortFunc(natPolicySubnets, func(a, b *kubeovnv1.Subnet) int { return strings.Compare(a.Name, b.Name) })
+	for _, subnet := range natPolicySubnets {
+		cidrBlock := getCidrByProtocol(subnet.Spec.CIDRBlock, protocol)
+		natPolicySubnetCidrs = append(natPolicySubnetCidrs, cidrBlock)
+		chain := table.AddChain(nftNatPolicySubnet + util.GetTruncatedUID(string(subnet.GetUID())))
+		natPolicyRules = append(natPolicyRules, fmt.Sprintf("%s saddr %s jump %s comment %s", addr, cidrBlock, chain.Name, nftables.Quote("natPolicySubnet-"+subnet.Name)))
+		for _, rule := range subnet.Status.NatOutgoingPolicyRules {
+			if rule.RuleID == "" {
+				continue
+			}
+			var markCode string
+			switch rule.Action {
+			case util.NatPolicyRuleActionNat:
+				markCode = OnOutGoingNatMark
+			case util.NatPolicyRuleActionForward:
+				markCode = OnOutGoingForwardMark
+			default:
+				continue
+			}
+
+			var match []string
+			for _, m := range [...]struct{ ips, direction, field string }{
+				{rule.Match.SrcIPs, "src", "saddr"},
+				{rule.Match.DstIPs, "dst", "daddr"},
+			} {
+				if m.ips == "" {
+					continue
+				}
+				elements := nftSetElements(protocol, strings.Split(m.ips, ","))
+				if len(elements) == 0 {
+					match = nil
+					break
+				}
+				name := getNatOutGoingPolicyRuleIPSetName(rule.RuleID, m.direction, "", false)
+				match = append(match, fmt.Sprintf("%s %s %s", addr, m.field, set(name, elements)))
+			}
+			if len(match) == 0 {
+				continue
+			}
+			chain.AddRule("%s %s", strings.Join(match, " "), nftMarkSet(markCode))
+		}
+	}
+	natPolicySubnetSet := set(NatOutGoingPolicySubnetSet, natPolicySubnetCidrs)
+	natPolicy := table.AddChain(nftNatPolicy)
+	natPolicy.Rules = natPolicyRules
+
+	// nat rules
+	prerouting := table.AddBaseChain(nftNatPrerouting, nftables.ChainTypeNat, nftables.HookPrerouting, nftables.PriorityDstNat)
+	// mark packets from pod to service, only the packets to cluster ips are marked if kube-proxy works in ipvs mode
+	ipvs := c.kubeProxyIPSetExists(fmt.Sprintf("KUBE-%sCLUSTER-IP", kubeProxyIPSetProtocol))
+	if ipvs {
+		prerouting.AddRule(`iifname "ovn0" %s saddr %s %s daddr . meta l4proto . th dport %s %s`, addr, subnetsSet, addr, clusterIPSet, nftMarkSet("0x4000/0x4000"))
+	} else {
+		prerouting.AddRule(`iifname "ovn0" %s saddr %s %s daddr %s %s`, addr, subnetsSet, addr, servicesSet, nftMarkSet("0x4000/0x4000"))
+	}
+	if nodeIP != "" {
+		var elements []string
+		for _, p := range [...]string{"tcp", "udp"} {
+			if !c.kubeProxyIPSetExists(fmt.Sprintf("KUBE-%sNODE-PORT-LOCAL-%s", kubeProxyIPSetProtocol, strings.ToUpper(p))) {
+				continue
+			}
+			for _, e := range nodePortLocal {
+				if strings.HasPrefix(e, p+" ") {
+					elements = append(elements, e)
+				}
+			}
+		}
+		if len(elements) != 0 {
+			nodePortLocalSet := "@" + prefix + nftNodePortLocalSet
+			table.AddSet(prefix+nftNodePortLocalSet, nftables.TypeInetProto+" . "+nftables.TypeInetService, false, elements)
+			// mark node port service traffic with external traffic policy set to local
+			prerouting.AddRule("fib daddr type local meta l4proto . th dport %s %s", nodePortLocalSet, nftMarkSet("0x80000/0x80000"))
+			prerouting.AddRule("%s saddr %s meta l4proto . th dport %s %s", addr, otherNodeSet, nodePortLocalSet, nftMarkSet("0x4000/0x4000"))
+		}
+	}
+
+	postrouting := table.AddBaseChain(nftNatPostrouting, nftables.ChainTypeNat, nftables.HookPostrouting, nftables.PrioritySrcNat)
+	if nodeIP != "" {
+		postrouting.AddRule("%s saddr %s %s daddr %s %s snat to %s fully-random", addr, servicesSet, addr, subnetsSet, nftMarkMatch("0x4000/0x4000"), nodeIP)
+	}
+	// nat packets marked by kube-proxy or kube-ovn
+	postrouting.AddRule("%s jump %s", nftMarkMatch("0x4000/0x4000"), masquerade.Name)
+	// nat service traffic
+	postrouting.AddRule("%s saddr %s %s daddr %s jump %s", addr, subnetsSet, addr, subnetsSet, masquerade.Name)
+	// do not nat node port service traffic with external traffic policy set to local
+	postrouting.AddRule("%s %s daddr %s return", nftMarkMatch("0x80000/0x80000"), addr, distributedGwSet)
+	// nat node port service traffic with external traffic policy set to local for subnets with centralized gateway
+	postrouting.AddRule("%s jump %s", nftMarkMatch("0x80000/0x80000"), masquerade.Name)
+	// do not nat reply packets in direct routing
+	postrouting.AddRule("tcp flags & syn != syn ct state new return")
+	// do not nat route traffic
+	postrouting.AddRule("%s saddr != %s %s saddr != %s %s daddr %s return", addr, subnetsSet, addr, otherNodeSet, addr, subnetsNatSet)
+	// nat outgoing policy rules
+	postrouting.AddRule("%s saddr %s %s daddr != %s jump %s", addr, natPolicySubnetSet, addr, subnetsSet, natPolicy.Name)
+	postrouting.AddRule("%s jump %s", nftMarkMatch(OnOutGoingNatMark), masquerade.Name)
+	postrouting.AddRule("%s return", nftMarkMatch(OnOutGoingForwardMark))
+	// nat gw with designative ip in centralized subnet
+	cidrs := make([]string, 0, len(centralGwNatIPs))
+	for cidr := range centralGwNatIPs {
+		if util.CheckProtocol(cidr) == protocol {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	slices.Sort(cidrs)
+	for _, cidr := range cidrs {
+		postrouting.AddRule("%s saddr %s %s daddr != %s snat to %s fully-random", addr, cidr, addr, subnetsSet, centralGwNatIPs[cidr])
+	}
+	// default nat outgoing rules
+	postrouting.AddRule("%s saddr %s %s daddr != %s jump %s", addr, subnetsNatSet, addr, subnetsSet, masquerade.Name)
+
+	// filter rules
+	input := table.AddBaseChain(nftFilterInput, nftables.ChainTypeFilter, nftables.HookInput, nftables.PriorityFilter)
+	forward := table.AddBaseChain(nftFilterForward, nftables.ChainTypeFilter, nftables.HookForward, nftables.PriorityFilter)
+	// the counters of the subnet gateway metrics are placed before the accept rules
+	names := make([]string, 0, len(subnetCidrs))
+	for name := range subnetCidrs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if cidr := subnetCidrs[name]; util.CheckProtocol(cidr) == protocol {
+			comment := nftables.Quote(util.OvnSubnetGatewayIptables + "," + name)
+			forward.AddRule("%s saddr %s counter comment %s", addr, cidr, comment)
+			forward.AddRule("%s daddr %s counter comment %s", addr, cidr, comment)
+		}
+	}
+	output := table.AddBaseChain(nftFilterOutput, nftables.ChainTypeFilter, nftables.HookOutput, nftables.PriorityFilter)
+	if ipvs {
+		// reject new connections to the service cidr which are not destined to a cluster ip
+		for _, chain := range []*nftables.Chain{input, output} {
+			chain.AddRule("%s %s daddr %s %s daddr . meta l4proto . th dport != %s ct state new reject",
+				nftMarkNotMatch("0x4000/0x4000"), addr, servicesSet, addr, clusterIPSet)
+		}
+	}
+	// the accept rules only end the traversal of this table, the effective ones are in the iptables chain OVN-ACCEPT
+	for _, chain := range []*nftables.Chain{input, forward} {
+		chain.AddRule("%s saddr %s accept", addr, subnetsSet)
+		chain.AddRule("%s daddr %s accept", addr, subnetsSet)
+		chain.AddRule("%s saddr %s accept", addr, servicesSet)
+		chain.AddRule("%s daddr %s accept", addr, servicesSet)
+	}
+	// unmark to bypass kernel nat checksum issue https://github.com/flannel-io/flannel/issues/1279
+	output.AddRule("udp dport 6081 meta mark set 0x0")
+
+	// mangle rules
+	manglePostrouting := table.AddBaseChain(nftManglePostrouting, nftables.ChainTypeFilter, nftables.HookPostrouting, nftables.PriorityMangle)
+	// drop invalid rst
+	manglePostrouting.AddRule("%s saddr %s tcp flags & rst == rst ct state invalid drop", addr, subnetsSet)
+	if c.config.Iface != "" && c.config.MSS > 0 {
+		iface, err := findInterface(c.config.Iface)
+		if err != nil {
+			klog.Errorf("failed to findInterface, %v", err)
+			return nil, err
+		}
+		manglePostrouting.AddRule("oifname %s tcp flags & (syn|rst) == syn tcp option maxseg size set %d", nftables.Quote(iface.Name), c.config.MSS)
+	}
+
+	if c.config.EnableTProxy {
+		probes, err := c.getTProxyProbes(protocol, isDual)
+		if err != nil {
+			return nil, err
+		}
+		manglePrerouting := table.AddBaseChain(nftManglePrerouting, nftables.ChainTypeFilter, nftables.HookPrerouting, nftables.PriorityMangle)
+		mangleOutput := table.AddBaseChain(nftMangleOutput, nftables.ChainTypeRoute, nftables.HookOutput, nftables.PriorityMangle)
+		outputMark := nftMarkSet(fmt.Sprintf("%#x/%#x", TProxyOutputMark, TProxyOutputMask))
+		preroutingMark := nftMarkSet(fmt.Sprintf("%#x/%#x", TProxyPreroutingMark, TProxyPreroutingMask))
+		for _, probe := range probes {
+			hostIP := probe.hostIP
+			if protocol == kubeovnv1.ProtocolIPv6 {
+				hostIP = "[" + hostIP + "]"
+			}
+			mangleOutput.AddRule("%s daddr %s tcp dport %s %s", addr, probe.podIP, probe.port, outputMark)
+			manglePrerouting.AddRule("%s daddr %s tcp dport %s %s tproxy to %s:%d", addr, probe.podIP, probe.port, preroutingMark, hostIP, util.TProxyListenPort)
+		}
+	}
+
+	return table, nil
+}
+
+// cleanIptablesBackendRules deletes the rules programmed by the iptables backend,
+// so that the traffic is not handled twice after the node is switched to nftables
+func cleanIptablesBackendRules(protocol string) {
+	ipt, err := iptables.NewWithProtocol(iptablesProtocol(protocol))
+	if err != nil {
+		klog.V(3).Infof("skip cleaning the rules of the iptables backend: %v", err)
+		return
+	}
+
+	for _, chain := range [...]struct{ table, chain, parent string }{
+		{NAT, OvnPrerouting, Prerouting},
+		{NAT, OvnPostrouting, Postrouting},
+		{MANGLE, OvnPrerouting, Prerouting},
+		{MANGLE, OvnPostrouting, Postrouting},
+		{MANGLE, OvnOutput, Output},
+	} {
+		if err = clearObsoleteIptablesChain(ipt, chain.table, chain.chain, chain.parent); err != nil {
+			klog.Errorf("failed to clean iptables chain %s/%s: %v", chain.table, chain.chain, err)
+		}
+	}
+
+	chains, err := ipt.ListChains(NAT)
+	if err != nil {
+		klog.Errorf("failed to list iptables chains in table %s: %v", NAT, err)
+		return
+	}
+	for _, chain := range chains {
+		if chain == OvnMasquerade || chain == OvnNatOutGoingPolicy || strings.HasPrefix(chain, OvnNatOutGoingPolicySubnet) {
+			if err = ipt.ClearAndDeleteChain(NAT, chain); err != nil {
+				klog.Errorf("failed to delete iptables chain %s/%s: %v", NAT, chain, err)
+			}
+		}
+	}
+
+	setPrefix := IPSetPrefix + "40"
+	if protocol == kubeovnv1.ProtocolIPv6 {
+		setPrefix = IPSetPrefix + "60"
+	}
+	for _, chain := range [...]string{"INPUT", "FORWARD", "OUTPUT"} {
+		rules, err := ipt.List("filter", chain)
+		if err != nil {
+			klog.Errorf("failed to list iptables rules in chain filter/%s: %v", chain, err)
+			continue
+		}
+		for _, rule := range rules {
+			if !strings.HasPrefix(rule, "-A ") ||
+				(!strings.Contains(rule, "--match-set "+setPrefix) &&
+					!strings.Contains(rule, util.OvnSubnetGatewayIptables) &&
+					!strings.Contains(rule, "--dport 6081 -j MARK")) {
+				continue
+			}
+			fields := util.DoubleQuotedFields(rule)
+			_ = deleteIptablesRule(ipt, util.IPTableRule{Table: "filter", Chain: chain, Rule: fields[2:]})
+		}
+	}
+}
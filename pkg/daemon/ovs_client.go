@@ -0,0 +1,13 @@
+package daemon
+
+import (
+	"github.com/kubeovn/kube-ovn/pkg/ovs"
+)
+
+// configInterfaceMirror configures the mirror of the pod interface by the ovsdb client if enabled, otherwise by ovs-vsctl
+func (config *Configuration) configInterfaceMirror(open, ifaceID string) error {
+	if config.OVSClient != nil {
+		return config.OVSClient.ConfigInterfaceMirror(config.EnableMirror, open, ifaceID)
+	}
+	return ovs.ConfigInterfaceMirror(config.EnableMirror, open, ifaceID)
+}
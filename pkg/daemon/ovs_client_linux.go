@@ -0,0 +1,36 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/kubeovn/kube-ovn/pkg/ovs"
+)
+
+// addPodPort adds the host end of the pod nic to br-int and clears the iface-id of the stale ports of the pod,
+// which is done in one transaction by the ovsdb client if enabled, otherwise by ovs-vsctl
+func (config *Configuration) addPodPort(portName string, externalIDs map[string]string) error {
+	if config.OVSClient != nil {
+		return config.OVSClient.AddPort("br-int", portName, "", externalIDs)
+	}
+
+	ovs.CleanDuplicatePort(externalIDs["iface-id"], portName)
+	args := []string{ovs.MayExist, "add-port", "br-int", portName, "--", "set", "interface", portName}
+	for _, key := range []string{"iface-id", "vendor", "pod_name", "pod_namespace", "ip", "pod_netns"} {
+		if value, ok := externalIDs[key]; ok {
+			args = append(args, fmt.Sprintf("external_ids:%s=%s", key, value))
+		}
+	}
+	output, err := ovs.Exec(args...)
+	if err != nil {
+		return fmt.Errorf("add nic to ovs failed %v: %q", err, output)
+	}
+	return nil
+}
+
+// setInterfaceBandwidth configures the bandwidth of the pod interface by the ovsdb client if enabled, otherwise by ovs-vsctl
+func (config *Configuration) setInterfaceBandwidth(podName, podNamespace, ifaceID, ingress, egress string) error {
+	if config.OVSClient != nil {
+		return config.OVSClient.SetInterfaceBandwidth(podName, podNamespace, ifaceID, ingress, egress)
+	}
+	return ovs.SetInterfaceBandwidth(podName, podNamespace, ifaceID, ingress, egress)
+}
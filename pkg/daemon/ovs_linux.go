@@ -60,7 +60,10 @@ func (csh cniServerHandler) configureDpdkNic(podName, podNamespace, provider, ne
 	if err != nil {
 		return fmt.Errorf("add nic to ovs failed %v: %q", err, output)
 	}
-	return ovs.SetInterfaceBandwidth(podName, podNamespace, ifaceID, egress, ingress)
+	if csh.Config.EnableOVNBandwidthLimit {
+		return nil
+	}
+	return csh.Config.setInterfaceBandwidth(podName, podNamespace, ifaceID, egress, ingress)
 }
 
 func (csh cniServerHandler) configureNic(podName, podNamespace, provider, netns, containerID, vfDriver, ifName, mac string, mtu int, ip, gateway string, isDefaultRoute, detectIPConflict bool, routes []request.Route, _, _ []string, ingress, egress, deviceID, nicType, latency, limit, loss, jitter string, gwCheckMode int, u2oInterconnectionIP, oldPodName string) error {
@@ -82,17 +85,16 @@ func (csh cniServerHandler) configureNic(podName, podNamespace, provider, netns,
 
 	ipStr := util.GetIPWithoutMask(ip)
 	ifaceID := ovs.PodNameToPortName(podName, podNamespace, provider)
-	ovs.CleanDuplicatePort(ifaceID, hostNicName)
 	// Add veth pair host end to ovs port
-	output, err := ovs.Exec(ovs.MayExist, "add-port", "br-int", hostNicName, "--",
-		"set", "interface", hostNicName, fmt.Sprintf("external_ids:iface-id=%s", ifaceID),
-		fmt.Sprintf("external_ids:vendor=%s", util.CniTypeName),
-		fmt.Sprintf("external_ids:pod_name=%s", podName),
-		fmt.Sprintf("external_ids:pod_namespace=%s", podNamespace),
-		fmt.Sprintf("external_ids:ip=%s", ipStr),
-		fmt.Sprintf("external_ids:pod_netns=%s", netns))
-	if err != nil {
-		return fmt.Errorf("add nic to ovs failed %v: %q", err, output)
+	if err = csh.Config.addPodPort(hostNicName, map[string]string{
+		"iface-id":      ifaceID,
+		"vendor":        util.CniTypeName,
+		"pod_name":      podName,
+		"pod_namespace": podNamespace,
+		"ip":            ipStr,
+		"pod_netns":     netns,
+	}); err != nil {
+		return err
 	}
 
 	// add hostNicName and containerNicName into pod annotations
@@ -131,8 +133,10 @@ func (csh cniServerHandler) configureNic(podName, podNamespace, provider, netns,
 	if err = configureHostNic(hostNicName); err != nil {
 		return err
 	}
-	if err = ovs.SetInterfaceBandwidth(podName, podNamespace, ifaceID, egress, ingress); err != nil {
-		return err
+	if !csh.Config.EnableOVNBandwidthLimit {
+		if err = csh.Config.setInterfaceBandwidth(podName, podNamespace, ifaceID, egress, ingress); err != nil {
+			return err
+		}
 	}
 
 	if err = ovs.SetNetemQos(podName, podNamespace, ifaceID, latency, limit, loss, jitter); err != nil {
@@ -1444,8 +1448,10 @@ func (csh cniServerHandler) configureNicWithInternalPort(podName, podNamespace,
 		return containerNicName, fmt.Errorf("failed to parse mac %s %v", macAddr, err)
 	}
 
-	if err = ovs.SetInterfaceBandwidth(podName, podNamespace, ifaceID, egress, ingress); err != nil {
-		return containerNicName, err
+	if !csh.Config.EnableOVNBandwidthLimit {
+		if err = csh.Config.setInterfaceBandwidth(podName, podNamespace, ifaceID, egress, ingress); err != nil {
+			return containerNicName, err
+		}
 	}
 
 	if err = ovs.SetNetemQos(podName, podNamespace, ifaceID, latency, limit, loss, jitter); err != nil {
@@ -124,8 +124,10 @@ func (csh cniServerHandler) configureNic(podName, podNamespace, provider, netns,
 			return fmt.Errorf("failed to add OVS port %s, %v: %q", epName, err, output)
 		}
 
-		if err = ovs.SetInterfaceBandwidth(podName, podNamespace, ifaceID, egress, ingress); err != nil {
-			return err
+		if !csh.Config.EnableOVNBandwidthLimit {
+			if err = ovs.SetInterfaceBandwidth(podName, podNamespace, ifaceID, egress, ingress); err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -140,7 +140,7 @@ func (c *Controller) cleanTProxyConfig() {
 	protocols := getProtocols(c.protocol)
 	for _, protocol := range protocols {
 		c.cleanTProxyRoutes(protocol)
-		c.cleanTProxyIPTableRules(protocol)
+		c.firewall.cleanTProxyRules(protocol)
 	}
 }
 
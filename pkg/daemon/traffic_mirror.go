@@ -0,0 +1,124 @@
+package daemon
+
+import (
+	"fmt"
+	"slices"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/ovs"
+)
+
+func (c *Controller) enqueueTrafficMirror(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+
+	klog.V(3).Infof("enqueue traffic mirror %s", key)
+	c.trafficMirrorQueue.Add(key)
+}
+
+func (c *Controller) enqueueUpdateTrafficMirror(_, newObj interface{}) {
+	c.enqueueTrafficMirror(newObj)
+}
+
+func (c *Controller) runTrafficMirrorWorker() {
+	for c.processNextTrafficMirrorWorkItem() {
+	}
+}
+
+func (c *Controller) processNextTrafficMirrorWorkItem() bool {
+	obj, shutdown := c.trafficMirrorQueue.Get()
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.trafficMirrorQueue.Done(obj)
+		var key string
+		var ok bool
+		if key, ok = obj.(string); !ok {
+			c.trafficMirrorQueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.handleTrafficMirror(key); err != nil {
+			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
+		}
+		c.trafficMirrorQueue.Forget(obj)
+		return nil
+	}(obj)
+	if err != nil {
+		utilruntime.HandleError(err)
+		c.trafficMirrorQueue.AddRateLimited(obj)
+		return true
+	}
+	return true
+}
+
+// handleTrafficMirror configures the ovs mirror of the traffic mirror with the ovs backend for the mirrored ports
+// of this node in status, which are recorded by kube-ovn-controller
+func (c *Controller) handleTrafficMirror(key string) error {
+	tm, err := c.trafficMirrorsLister.Get(key)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		klog.Error(err)
+		return err
+	}
+
+	var ports []string
+	if tm != nil && tm.Status.Backend == kubeovnv1.TrafficMirrorBackendOVS {
+		for _, node := range tm.Status.Nodes {
+			if node.Node == c.config.NodeName {
+				ports = node.Ports
+				break
+			}
+		}
+	}
+	if len(ports) == 0 {
+		klog.V(3).Infof("delete ovs mirror of traffic mirror %s", key)
+		if err = ovs.DeleteTrafficMirror(key); err != nil {
+			klog.Errorf("failed to delete ovs mirror of traffic mirror %s: %v", key, err)
+			return err
+		}
+		return nil
+	}
+
+	klog.V(3).Infof("set ovs mirror of traffic mirror %s for ports %v", key, ports)
+	egress := tm.Spec.Direction != kubeovnv1.TrafficMirrorDirectionIngress
+	ingress := tm.Spec.Direction != kubeovnv1.TrafficMirrorDirectionEgress
+	if err = ovs.SetTrafficMirror(key, string(tm.Spec.Type), tm.Spec.Remote, tm.Spec.Key, egress, ingress, ports); err != nil {
+		klog.Errorf("failed to set ovs mirror of traffic mirror %s: %v", key, err)
+		return err
+	}
+	return nil
+}
+
+// resyncTrafficMirrors enqueues the traffic mirrors configured in ovs or in kubernetes, since the ovs ports of
+// the mirrored pods change without any update of the traffic mirrors once the pods are recreated
+func (c *Controller) resyncTrafficMirrors() {
+	names, err := ovs.ListTrafficMirrors()
+	if err != nil {
+		klog.Errorf("failed to list ovs traffic mirrors: %v", err)
+		return
+	}
+	trafficMirrors, err := c.trafficMirrorsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list traffic mirrors: %v", err)
+		return
+	}
+	for _, tm := range trafficMirrors {
+		if tm.Status.Backend == kubeovnv1.TrafficMirrorBackendOVS && !slices.Contains(names, tm.Name) {
+			names = append(names, tm.Name)
+		}
+	}
+	for _, name := range names {
+		c.trafficMirrorQueue.Add(name)
+	}
+}
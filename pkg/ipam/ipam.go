@@ -386,6 +386,15 @@ func (ipam *IPAM) GetSubnetV4Mask(subnetName string) (string, error) {
 	return "", ErrNoAvailable
 }
 
+func (ipam *IPAM) GetSubnetV6Mask(subnetName string) (string, error) {
+	subnet, ok := ipam.Subnets[subnetName]
+	if ok && subnet.V6CIDR != nil {
+		mask, _ := subnet.V6CIDR.Mask.Size()
+		return strconv.Itoa(mask), nil
+	}
+	return "", ErrNoAvailable
+}
+
 func (ipam *IPAM) GetSubnetIPRangeString(subnetName string, excludeIps []string) (string, string, string, string) {
 	ipam.mutex.RLock()
 	defer ipam.mutex.RUnlock()
@@ -0,0 +1,432 @@
+package natgw
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeovn/kube-ovn/pkg/nftables"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+const (
+	DefaultScript    = "/kube-ovn/nat-gateway.sh"
+	DefaultInterface = "net1"
+
+	egressQdisc = "1:0"
+)
+
+const randomFullyVersion = "1.6.2"
+
+// Runner runs a command and returns the combined output
+type Runner func(name string, args ...string) (string, error)
+
+func execRunner(name string, args ...string) (string, error) {
+	output, err := exec.Command(name, args...).CombinedOutput() // #nosec G204
+	return strings.TrimSpace(string(output)), err
+}
+
+// Agent programs the rules of the nat gateway, the incremental operations are executed by nat-gateway.sh
+// and the full state synchronization compares the desired state with the rules read back from the kernel.
+// With the nftables backend, the nat rules are programmed by the agent in a table replaced atomically
+type Agent struct {
+	mutex           sync.Mutex
+	script          string
+	iface           string
+	run             Runner
+	iptablesVersion string
+	backend         string
+	nft             nftables.Interface
+	// the cached conntrack entries of the eips and the time they are counted
+	eipEntries     map[string]uint64
+	eipEntriesTime time.Time
+}
+
+func NewAgent(script, iface, backend string) *Agent {
+	return &Agent{script: script, iface: iface, run: execRunner, backend: backend, nft: nftables.New()}
+}
+
+func (a *Agent) useNftables() bool {
+	return a.backend == util.FirewallBackendNftables
+}
+
+// Exec executes an operation of nat-gateway.sh, the request is validated by the caller
+func (a *Agent) Exec(operation Operation, rules []string) (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.useNftables() && isNatOperation(operation) {
+		if operation == OpConntrackSet {
+			// the timeouts are set by nat-gateway.sh
+			if output, err := a.exec(operation, rules...); err != nil {
+				return output, err
+			}
+		}
+		return "", a.execNft(operation, rules)
+	}
+	return a.exec(operation, rules...)
+}
+
+func (a *Agent) exec(operation Operation, rules ...string) (string, error) {
+	args := append([]string{a.script, string(operation)}, rules...)
+	klog.V(3).Infof("bash %s", strings.Join(args, " "))
+	output, err := a.run("bash", args...)
+	if err != nil {
+		klog.Errorf("failed to exec %s %s: %v, output: %s", operation, strings.Join(rules, " "), err, output)
+		return output, err
+	}
+	return output, nil
+}
+
+// GetState reads the actual rules and counters of the nat gateway
+func (a *Agent) GetState() (*StateResponse, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	state, primaries, counters, err := a.readState()
+	if err != nil {
+		return nil, err
+	}
+	return &StateResponse{State: *state, Counters: counters, Conntrack: a.conntrackStats(state, primaries, counters)}, nil
+}
+
+// readState reads the actual state of the nat gateway, the ipv4 and ipv6 addresses of the pod on the external
+// interface are returned separately since they are not eips
+func (a *Agent) readState() (*State, []string, []Counter, error) {
+	state := &State{}
+	var output string
+	var rules *natRules
+	var counters []Counter
+	var err error
+	if a.useNftables() {
+		if rules, counters, err = a.readNftNatRules(); err != nil {
+			klog.Errorf("failed to read nat rules: %v", err)
+			return nil, nil, nil, err
+		}
+	} else {
+		// the connection limits are in the filter table
+		var dump strings.Builder
+		for _, table := range []string{"nat", "filter"} {
+			if output, err = a.run("iptables-save", "-c", "-t", table); err != nil {
+				klog.Errorf("failed to dump %s rules: %v, output: %s", table, err, output)
+				return nil, nil, nil, err
+			}
+			dump.WriteString(output + "\n")
+		}
+		rules, counters = parseNatRules(dump.String())
+		connLimits, connLimitCounters, err := a.readNftConnLimits()
+		if err != nil {
+			klog.Errorf("failed to read eip connection limits: %v", err)
+			return nil, nil, nil, err
+		}
+		rules.connLimits = append(rules.connLimits, connLimits...)
+		counters = append(counters, connLimitCounters...)
+	}
+	state.FloatingIPs, state.DNATs, state.SNATs, state.Hairpins = rules.fips, rules.dnats, rules.snats, rules.hairpins
+	state.ConnLimits = rules.connLimits
+	if state.Conntrack, err = a.readConntrack(); err != nil {
+		return nil, nil, nil, err
+	}
+	state.Conntrack.Max = rules.connMax
+
+	if output, err = a.run("ip", "-o", "-4", "addr", "show", "dev", a.iface); err != nil {
+		klog.Errorf("failed to show addresses of %s: %v, output: %s", a.iface, err, output)
+		return nil, nil, nil, err
+	}
+	var primary string
+	state.EIPs, primary = parseAddresses(output)
+	// the link local addresses are not eips
+	if output, err = a.run("ip", "-o", "-6", "addr", "show", "dev", a.iface, "scope", "global"); err != nil {
+		klog.Errorf("failed to show ipv6 addresses of %s: %v, output: %s", a.iface, err, output)
+		return nil, nil, nil, err
+	}
+	v6EIPs, primaryV6 := parseAddresses(output)
+	state.EIPs = append(state.EIPs, v6EIPs...)
+	var primaries []string
+	for _, address := range []string{primary, primaryV6} {
+		if address != "" {
+			primaries = append(primaries, address)
+		}
+	}
+
+	for _, direction := range []string{QoSIngress, QoSEgress} {
+		qdisc, err := a.qosQdisc(direction)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if qdisc == "" {
+			continue
+		}
+		if output, err = a.run("tc", "-s", "-d", "filter", "show", "dev", a.iface, "parent", qdisc); err != nil {
+			klog.Errorf("failed to show %s filters of %s: %v, output: %s", direction, a.iface, err, output)
+			return nil, nil, nil, err
+		}
+		state.QoS = append(state.QoS, parseQoS(output, direction)...)
+	}
+	return state, primaries, counters, nil
+}
+
+func (a *Agent) qosQdisc(direction string) (string, error) {
+	kind := direction
+	if direction == QoSEgress {
+		kind = "root"
+	}
+	output, err := a.run("tc", "qdisc", "show", "dev", a.iface, kind)
+	if err != nil {
+		klog.Errorf("failed to show %s qdisc of %s: %v, output: %s", direction, a.iface, err, output)
+		return "", err
+	}
+	// qdisc ingress ffff: parent ffff:fff1 ----------------
+	// qdisc htb 1: root refcnt 2 r2q 10 default 0 direct_packets_stat 0
+	fields := strings.Fields(output)
+	switch {
+	case len(fields) < 3:
+		return "", nil
+	case direction == QoSIngress && fields[1] == "ingress":
+		return fields[2], nil
+	case direction == QoSEgress && fields[1] == "htb" && fields[2]+"0" == egressQdisc:
+		return egressQdisc, nil
+	}
+	return "", nil
+}
+
+var iptablesVersionRegexp = regexp.MustCompile(`v([0-9]+(\.[0-9]+)+)`)
+
+func (a *Agent) randomFully() bool {
+	if a.iptablesVersion == "" {
+		output, err := a.exec(OpIptablesVersion)
+		if err != nil {
+			return false
+		}
+		match := iptablesVersionRegexp.FindStringSubmatch(output)
+		if match == nil {
+			klog.Warningf("no iptables version found in %q", output)
+			return false
+		}
+		a.iptablesVersion = match[1]
+	}
+	return util.CompareVersion(a.iptablesVersion, randomFullyVersion) >= 1
+}
+
+// Sync synchronizes the rules of the nat gateway to the desired state,
+// the drifts of the actual state are returned along with the rule counters
+func (a *Agent) Sync(req *SyncRequest) (*SyncResponse, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	actual, primaries, counters, err := a.readState()
+	if err != nil {
+		return nil, err
+	}
+	plan := diffState(&req.State, actual, primaries)
+	resp := &SyncResponse{Drifts: plan.drifts, Counters: counters, Conntrack: a.conntrackStats(actual, primaries, counters)}
+	if req.DryRun || len(plan.drifts) == 0 {
+		return resp, nil
+	}
+
+	klog.Infof("synchronizing %d drifted rules", len(plan.drifts))
+	var errs []error
+	if a.useNftables() && slices.ContainsFunc(plan.ops, func(op syncOp) bool { return isNatOperation(op.operation) }) {
+		// the nat rules are replaced at once, the rules referring to the eips to be added
+		// take effect as soon as the eips are configured
+		rules := newNatRules(&req.State)
+		if req.State.Conntrack == nil {
+			rules.connMax = actual.Conntrack.Max
+		}
+		if err = a.nft.Replace(rules.table(a.iface)); err != nil {
+			errs = append(errs, fmt.Errorf("replace nat rules: %w", err))
+		}
+	}
+	for _, op := range plan.ops {
+		// the timeouts are still set by nat-gateway.sh
+		if a.useNftables() && isNatOperation(op.operation) && op.operation != OpConntrackSet {
+			continue
+		}
+		rule := op.rule
+		if op.operation == OpSnatAdd && a.randomFully() {
+			rule += ",--random-fully"
+		}
+		if _, err = a.exec(op.operation, rule); err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", op.operation, rule, err))
+		}
+	}
+	return resp, errors.Join(errs...)
+}
+
+type syncOp struct {
+	operation Operation
+	rule      string
+}
+
+type syncPlan struct {
+	drifts []Drift
+	ops    []syncOp
+}
+
+func (p *syncPlan) drift(kind, rule, reason string) {
+	p.drifts = append(p.drifts, Drift{Kind: kind, Rule: rule, Reason: reason})
+}
+
+func (p *syncPlan) op(operation Operation, rule string) {
+	p.ops = append(p.ops, syncOp{operation: operation, rule: rule})
+}
+
+func qosOps(direction string) (Operation, Operation) {
+	if direction == QoSEgress {
+		return OpEgressQoSAdd, OpEgressQoSDel
+	}
+	return OpIngressQoSAdd, OpIngressQoSDel
+}
+
+// diffState compares the desired state with the actual one, the stale rules are deleted
+// before the eips are removed, and the eips are added before the rules referring to them
+func diffState(desired, actual *State, primaries []string) *syncPlan {
+	plan := &syncPlan{}
+
+	fips := diffRules(desired.FloatingIPs, actual.FloatingIPs, FloatingIP.key)
+	dnats := diffRules(desired.DNATs, actual.DNATs, DNAT.key)
+	snats := diffRules(desired.SNATs, actual.SNATs, SNAT.key)
+	hairpins := diffRules(desired.Hairpins, actual.Hairpins, Hairpin.key)
+	connLimits := diffRules(desired.ConnLimits, actual.ConnLimits, ConnLimit.key)
+	eips := diffRules(desired.EIPs, actual.EIPs, EIP.key)
+	qos := diffRules(desired.QoS, eipQoS(desired, actual), EIPQoS.key)
+
+	for _, r := range fips.unexpected {
+		plan.drift(KindFloatingIP, r.Rule(), DriftUnexpected)
+		plan.op(OpFipDel, r.Rule())
+	}
+	for _, r := range dnats.unexpected {
+		plan.drift(KindDNAT, r.Rule(), DriftUnexpected)
+		plan.op(OpDnatDel, r.Rule())
+	}
+	for _, r := range snats.unexpected {
+		plan.drift(KindSNAT, r.Rule(), DriftUnexpected)
+		plan.op(OpSnatDel, r.Rule())
+	}
+	for _, r := range hairpins.unexpected {
+		plan.drift(KindHairpin, r.Rule(), DriftUnexpected)
+		plan.op(OpHairpinDel, r.Rule())
+	}
+	for _, r := range connLimits.unexpected {
+		plan.drift(KindConnLimit, r.Rule(), DriftUnexpected)
+		plan.op(OpConnLimitDel, r.EIP)
+	}
+	for _, r := range qos.unexpected {
+		plan.drift(KindQoS, r.Direction+","+r.Rule(), DriftUnexpected)
+		_, del := qosOps(r.Direction)
+		plan.op(del, r.EIP)
+	}
+	for _, r := range eips.unexpected {
+		if slices.Contains(primaries, r.Address) {
+			continue
+		}
+		plan.drift(KindEIP, r.Address, DriftUnexpected)
+		plan.op(OpEipDel, r.Address)
+	}
+
+	for _, r := range eips.missing {
+		plan.drift(KindEIP, r.Rule(), DriftMissing)
+		plan.op(OpEipAdd, r.Rule())
+	}
+	for _, r := range fips.missing {
+		plan.drift(KindFloatingIP, r.Rule(), DriftMissing)
+		plan.op(OpFipAdd, r.Rule())
+	}
+	for _, r := range dnats.missing {
+		plan.drift(KindDNAT, r.Rule(), DriftMissing)
+		plan.op(OpDnatAdd, r.Rule())
+	}
+	for _, r := range snats.missing {
+		plan.drift(KindSNAT, r.Rule(), DriftMissing)
+		plan.op(OpSnatAdd, r.Rule())
+	}
+	for _, r := range hairpins.missing {
+		plan.drift(KindHairpin, r.Rule(), DriftMissing)
+		plan.op(OpHairpinAdd, r.Rule())
+	}
+	for _, r := range connLimits.missing {
+		plan.drift(KindConnLimit, r.Rule(), DriftMissing)
+		plan.op(OpConnLimitAdd, r.Rule())
+	}
+	// the old connection limit is replaced when adding the one of the eip
+	for _, pair := range connLimits.common {
+		if pair[0].Limit != pair[1].Limit {
+			plan.drift(KindConnLimit, pair[0].Rule(), DriftMismatched)
+			plan.op(OpConnLimitAdd, pair[0].Rule())
+		}
+	}
+	for _, r := range qos.missing {
+		plan.drift(KindQoS, r.Direction+","+r.Rule(), DriftMissing)
+		add, _ := qosOps(r.Direction)
+		plan.op(add, r.Rule())
+	}
+	// the old filter is replaced when adding the bandwidth limit of the eip
+	for _, pair := range qos.common {
+		if !qosEqual(pair[0], pair[1]) {
+			plan.drift(KindQoS, pair[0].Direction+","+pair[0].Rule(), DriftMismatched)
+			add, _ := qosOps(pair[0].Direction)
+			plan.op(add, pair[0].Rule())
+		}
+	}
+	if desired.Conntrack != nil && actual.Conntrack != nil && !conntrackEqual(*desired.Conntrack, *actual.Conntrack) {
+		plan.drift(KindConntrack, desired.Conntrack.Rule(), DriftMismatched)
+		plan.op(OpConntrackSet, desired.Conntrack.Rule())
+	}
+	return plan
+}
+
+// eipQoS returns the actual bandwidth limits of the eips, so that the u32 filters
+// of the nat gateway qos policy are not treated as unexpected
+func eipQoS(desired, actual *State) []EIPQoS {
+	ips := make(map[string]bool, len(desired.EIPs)+len(actual.EIPs))
+	for _, eip := range slices.Concat(desired.EIPs, actual.EIPs) {
+		ips[ipOf(eip.Address)] = true
+	}
+	result := make([]EIPQoS, 0, len(actual.QoS))
+	for _, qos := range actual.QoS {
+		if ips[qos.EIP] {
+			result = append(result, qos)
+		}
+	}
+	return result
+}
+
+type ruleDiff[T any] struct {
+	missing    []T
+	unexpected []T
+	// pairs of the desired and the actual rules with the same key
+	common [][2]T
+}
+
+func diffRules[T any](desired, actual []T, key func(T) string) ruleDiff[T] {
+	var diff ruleDiff[T]
+	actualRules := make(map[string]T, len(actual))
+	for _, r := range actual {
+		actualRules[key(r)] = r
+	}
+	desiredKeys := make(map[string]bool, len(desired))
+	for _, r := range desired {
+		k := key(r)
+		if desiredKeys[k] {
+			continue
+		}
+		desiredKeys[k] = true
+		if a, ok := actualRules[k]; ok {
+			diff.common = append(diff.common, [2]T{r, a})
+		} else {
+			diff.missing = append(diff.missing, r)
+		}
+	}
+	for _, r := range actual {
+		if !desiredKeys[key(r)] {
+			diff.unexpected = append(diff.unexpected, r)
+		}
+	}
+	return diff
+}
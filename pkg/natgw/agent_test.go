@@ -0,0 +1,147 @@
+package natgw
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeovn/kube-ovn/pkg/nftables"
+)
+
+func Test_diffState(t *testing.T) {
+	t.Parallel()
+
+	desired := &State{
+		EIPs:        []EIP{{Address: "172.18.11.2/16", Gateway: "172.18.0.1"}, {Address: "172.18.11.5/16", Gateway: "172.18.0.1"}},
+		FloatingIPs: []FloatingIP{{EIP: "172.18.11.2", InternalIP: "10.0.1.5"}},
+		DNATs:       []DNAT{{EIP: "172.18.11.5", Protocol: "udp", ExternalPort: "53", InternalIP: "10.0.1.7", InternalPort: "53"}},
+		SNATs:       []SNAT{{EIP: "172.18.11.5", InternalCIDR: "10.0.1.10"}},
+		Hairpins:    []Hairpin{{EIP: "172.18.11.5"}},
+		ConnLimits:  []ConnLimit{{EIP: "172.18.11.2", Limit: 100}},
+		Conntrack:   &Conntrack{Max: 10000, UDPTimeout: 60},
+		QoS: []EIPQoS{
+			{EIP: "172.18.11.2", Direction: QoSIngress, Priority: 1, Rate: "20", Burst: "10"},
+			{EIP: "172.18.11.5", Direction: QoSEgress, Priority: 1, Rate: "10", Burst: "10"},
+		},
+	}
+	actual := &State{
+		EIPs:        []EIP{{Address: "172.18.0.2/16"}, {Address: "172.18.11.2/16"}, {Address: "172.18.11.3/16"}},
+		FloatingIPs: []FloatingIP{{EIP: "172.18.11.2", InternalIP: "10.0.1.5"}},
+		DNATs:       []DNAT{{EIP: "172.18.11.3", Protocol: "tcp", ExternalPort: "8888", InternalIP: "10.0.1.6", InternalPort: "80"}},
+		SNATs:       []SNAT{{EIP: "172.18.11.5", InternalCIDR: "10.0.1.10/32"}},
+		Hairpins:    []Hairpin{{EIP: "172.18.11.3"}},
+		ConnLimits:  []ConnLimit{{EIP: "172.18.11.2", Limit: 50}, {EIP: "172.18.11.3", Limit: 10}},
+		Conntrack:   &Conntrack{TCPEstablishedTimeout: 432000, UDPTimeout: 30},
+		QoS: []EIPQoS{
+			{EIP: "172.18.11.2", Direction: QoSIngress, Priority: 1, Rate: "10Mbit", Burst: "10Mb"},
+			// filter of the nat gateway qos policy
+			{EIP: "192.168.0.1", Direction: QoSIngress, Priority: 5, Rate: "10Mbit", Burst: "10Mb"},
+		},
+	}
+
+	// the ipv6 address of the pod is not treated as an unexpected eip, while the stale ipv6 eip is
+	actual.EIPs = append(actual.EIPs, EIP{Address: "fc00:f853:ccd:e793::2/64"}, EIP{Address: "fc00:f853:ccd:e793::11/64"})
+	primaries := []string{"172.18.0.2/16", "fc00:f853:ccd:e793::2/64"}
+	plan := diffState(desired, actual, primaries)
+	require.Equal(t, []Drift{
+		{Kind: KindDNAT, Rule: "172.18.11.3,8888,tcp,10.0.1.6,80", Reason: DriftUnexpected},
+		{Kind: KindHairpin, Rule: "172.18.11.3", Reason: DriftUnexpected},
+		{Kind: KindConnLimit, Rule: "172.18.11.3,10", Reason: DriftUnexpected},
+		{Kind: KindEIP, Rule: "172.18.11.3/16", Reason: DriftUnexpected},
+		{Kind: KindEIP, Rule: "fc00:f853:ccd:e793::11/64", Reason: DriftUnexpected},
+		{Kind: KindEIP, Rule: "172.18.11.5/16,172.18.0.1", Reason: DriftMissing},
+		{Kind: KindDNAT, Rule: "172.18.11.5,53,udp,10.0.1.7,53", Reason: DriftMissing},
+		{Kind: KindHairpin, Rule: "172.18.11.5", Reason: DriftMissing},
+		{Kind: KindConnLimit, Rule: "172.18.11.2,100", Reason: DriftMismatched},
+		{Kind: KindQoS, Rule: "egress,172.18.11.5,1,10,10", Reason: DriftMissing},
+		{Kind: KindQoS, Rule: "ingress,172.18.11.2,1,20,10", Reason: DriftMismatched},
+		{Kind: KindConntrack, Rule: "10000,0,0,60,0", Reason: DriftMismatched},
+	}, plan.drifts)
+	require.Equal(t, []syncOp{
+		{operation: OpDnatDel, rule: "172.18.11.3,8888,tcp,10.0.1.6,80"},
+		{operation: OpHairpinDel, rule: "172.18.11.3"},
+		{operation: OpConnLimitDel, rule: "172.18.11.3"},
+		{operation: OpEipDel, rule: "172.18.11.3/16"},
+		{operation: OpEipDel, rule: "fc00:f853:ccd:e793::11/64"},
+		{operation: OpEipAdd, rule: "172.18.11.5/16,172.18.0.1"},
+		{operation: OpDnatAdd, rule: "172.18.11.5,53,udp,10.0.1.7,53"},
+		{operation: OpHairpinAdd, rule: "172.18.11.5"},
+		{operation: OpConnLimitAdd, rule: "172.18.11.2,100"},
+		{operation: OpEgressQoSAdd, rule: "172.18.11.5,1,10,10"},
+		{operation: OpIngressQoSAdd, rule: "172.18.11.2,1,20,10"},
+		{operation: OpConntrackSet, rule: "10000,0,0,60,0"},
+	}, plan.ops)
+
+	actual.QoS = actual.QoS[:1]
+	plan = diffState(actual, actual, primaries)
+	require.Empty(t, plan.drifts)
+}
+
+type fakeRunner struct {
+	outputs  map[string]string
+	commands []string
+}
+
+func (r *fakeRunner) run(name string, args ...string) (string, error) {
+	cmd := strings.Join(append([]string{name}, args...), " ")
+	r.commands = append(r.commands, cmd)
+	for prefix, output := range r.outputs {
+		if strings.HasPrefix(cmd, prefix) {
+			if output == "error" {
+				return "failed", fmt.Errorf("exit status 1")
+			}
+			return output, nil
+		}
+	}
+	return "", nil
+}
+
+func Test_Sync(t *testing.T) {
+	t.Parallel()
+
+	runner := &fakeRunner{outputs: map[string]string{
+		"iptables-save -c -t nat":                  iptablesSaveOutput,
+		"iptables-save -c -t filter":               iptablesSaveFilterOutput,
+		"sysctl -n " + sysctlTCPEstablishedTimeout: "432000\n120\n30\n120",
+		"ip -o -4 addr show":                       "3: net1    inet 172.18.11.2/16 brd 172.18.255.255 scope global net1",
+		"bash nat.sh " + string(OpIptablesVersion): "iptables v1.8.9 (legacy)",
+		"bash nat.sh " + string(OpDnatDel):         "error",
+		"tc qdisc show dev net1 ingress":           "",
+		"tc qdisc show dev net1 root":              "qdisc noqueue 0: root refcnt 2",
+	}}
+	// the connection limits of the eips are read from nftables
+	nft := nftables.NewWithRunner(func(_ string, args ...string) (string, error) {
+		if slices.Equal(args, []string{"list", "table", nftables.FamilyIP, ConnLimitTable}) {
+			return nftConnLimitListOutput, nil
+		}
+		return "", fmt.Errorf("unexpected nft %v", args)
+	})
+	agent := &Agent{script: "nat.sh", iface: "net1", run: runner.run, nft: nft}
+
+	state := &State{
+		EIPs:        []EIP{{Address: "172.18.11.2/16", Gateway: "172.18.0.1"}},
+		FloatingIPs: []FloatingIP{{EIP: "172.18.11.2", InternalIP: "10.0.1.5"}},
+		SNATs:       []SNAT{{EIP: "172.18.11.4", InternalCIDR: "10.0.1.0/24"}, {EIP: "172.18.11.2", InternalCIDR: "10.0.2.0/24"}},
+		ConnLimits:  []ConnLimit{{EIP: "172.18.11.2", Limit: 100}},
+		Conntrack:   &Conntrack{Max: 10000, UDPTimeout: 30},
+	}
+
+	resp, err := agent.Sync(&SyncRequest{State: *state, DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, resp.Drifts, 3)
+	require.Len(t, resp.Counters, 6)
+	for _, cmd := range runner.commands {
+		require.NotContains(t, cmd, "nat.sh")
+	}
+
+	runner.commands = nil
+	resp, err = agent.Sync(&SyncRequest{State: *state})
+	require.ErrorContains(t, err, string(OpDnatDel))
+	require.Len(t, resp.Drifts, 3)
+	require.Contains(t, runner.commands, "bash nat.sh "+string(OpDnatDel)+" 172.18.11.3,8888,tcp,10.0.1.6,80")
+	require.Contains(t, runner.commands, "bash nat.sh "+string(OpHairpinDel)+" 172.18.11.3")
+	require.Contains(t, runner.commands, "bash nat.sh "+string(OpSnatAdd)+" 172.18.11.2,10.0.2.0/24,--random-fully")
+}
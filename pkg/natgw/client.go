@@ -0,0 +1,106 @@
+package natgw
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"k8s.io/klog/v2"
+)
+
+// Error is the error returned by the agent, Output is the output of the failed command
+type Error struct {
+	StatusCode int
+	Message    string
+	Output     string
+}
+
+func (e *Error) Error() string {
+	if e.Output == "" {
+		return fmt.Sprintf("nat gateway agent error %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("nat gateway agent error %d: %s, output: %s", e.StatusCode, e.Message, e.Output)
+}
+
+// Client is the client of the nat gateway agent api
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	return &Client{baseURL: baseURL, httpClient: httpClient}
+}
+
+// Exec executes an incremental operation of the nat gateway rules and returns the output
+func (c *Client) Exec(ctx context.Context, operation Operation, rules []string) (string, error) {
+	req := &RuleRequest{Operation: operation, Rules: rules}
+	if err := req.Validate(); err != nil {
+		klog.Error(err)
+		return "", err
+	}
+	var resp RuleResponse
+	if err := c.do(ctx, http.MethodPost, RulesPath, req, &resp); err != nil {
+		return "", err
+	}
+	return resp.Output, nil
+}
+
+// GetState returns the actual rules and counters of the nat gateway
+func (c *Client) GetState(ctx context.Context) (*StateResponse, error) {
+	var resp StateResponse
+	if err := c.do(ctx, http.MethodGet, StatePath, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Sync synchronizes the nat gateway to the desired state and returns the drifts and counters
+func (c *Client) Sync(ctx context.Context, state *State, dryRun bool) (*SyncResponse, error) {
+	var resp SyncResponse
+	if err := c.do(ctx, http.MethodPut, StatePath, &SyncRequest{State: *state, DryRun: dryRun}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		klog.Errorf("failed to request %s %s: %v", method, path, err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp ErrorResponse
+		if err = json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
+			errResp.Error = http.StatusText(resp.StatusCode)
+		}
+		return &Error{StatusCode: resp.StatusCode, Message: errResp.Error, Output: errResp.Output}
+	}
+	if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+		klog.Errorf("failed to decode response of %s %s: %v", method, path, err)
+		return err
+	}
+	return nil
+}
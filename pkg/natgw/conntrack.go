@@ -0,0 +1,205 @@
+package natgw
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	sysctlConntrackCount        = "net.netfilter.nf_conntrack_count"
+	sysctlConntrackMax          = "net.netfilter.nf_conntrack_max"
+	sysctlTCPEstablishedTimeout = "net.netfilter.nf_conntrack_tcp_timeout_established"
+	sysctlTCPTimeWaitTimeout    = "net.netfilter.nf_conntrack_tcp_timeout_time_wait"
+	sysctlUDPTimeout            = "net.netfilter.nf_conntrack_udp_timeout"
+	sysctlUDPStreamTimeout      = "net.netfilter.nf_conntrack_udp_timeout_stream"
+)
+
+// conntrackEIPEntriesInterval is the minimum interval of counting the conntrack entries of the eips,
+// which lists the entries of each eip and is expensive with a large number of connections
+const conntrackEIPEntriesInterval = time.Minute
+
+// conntrackCountRegexp matches the summary printed by conntrack -L
+var conntrackCountRegexp = regexp.MustCompile(`(\d+) flow entries have been shown`)
+
+// conntrackDropFields are the statistics of the packets dropped by conntrack
+var conntrackDropFields = []string{"drop", "early_drop", "insert_failed"}
+
+// readSysctls reads the values of the sysctl keys in order
+func (a *Agent) readSysctls(keys ...string) ([]uint64, error) {
+	output, err := a.run("sysctl", append([]string{"-n"}, keys...)...)
+	if err != nil {
+		klog.Errorf("failed to read sysctl %s: %v, output: %s", strings.Join(keys, " "), err, output)
+		return nil, err
+	}
+	fields := strings.Fields(output)
+	values := make([]uint64, len(keys))
+	for i := range keys {
+		if i >= len(fields) {
+			break
+		}
+		if values[i], err = strconv.ParseUint(fields[i], 10, 64); err != nil {
+			err = fmt.Errorf("invalid value %q of sysctl %s: %w", fields[i], keys[i], err)
+			klog.Error(err)
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// readConntrack reads the conntrack timeouts of the nat gateway, the max is read from the connection limit rules
+func (a *Agent) readConntrack() (*Conntrack, error) {
+	values, err := a.readSysctls(sysctlTCPEstablishedTimeout, sysctlTCPTimeWaitTimeout, sysctlUDPTimeout, sysctlUDPStreamTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return &Conntrack{
+		TCPEstablishedTimeout: int(values[0]),
+		TCPTimeWaitTimeout:    int(values[1]),
+		UDPTimeout:            int(values[2]),
+		UDPStreamTimeout:      int(values[3]),
+	}, nil
+}
+
+// conntrackEqual compares the desired conntrack settings with the actual ones, the zero timeouts are not managed
+func conntrackEqual(desired, actual Conntrack) bool {
+	timeoutEqual := func(desired, actual int) bool {
+		return desired == 0 || desired == actual
+	}
+	return desired.Max == actual.Max &&
+		timeoutEqual(desired.TCPEstablishedTimeout, actual.TCPEstablishedTimeout) &&
+		timeoutEqual(desired.TCPTimeWaitTimeout, actual.TCPTimeWaitTimeout) &&
+		timeoutEqual(desired.UDPTimeout, actual.UDPTimeout) &&
+		timeoutEqual(desired.UDPStreamTimeout, actual.UDPStreamTimeout)
+}
+
+// conntrackStats reads the usage of the conntrack table, the stats are optional
+// and nil is returned if they are not available
+func (a *Agent) conntrackStats(state *State, primaries []string, counters []Counter) *ConntrackStats {
+	values, err := a.readSysctls(sysctlConntrackCount, sysctlConntrackMax)
+	if err != nil {
+		klog.Warningf("failed to read conntrack usage: %v", err)
+		return nil
+	}
+	stats := &ConntrackStats{Entries: values[0], Max: values[1]}
+
+	output, err := a.run("conntrack", "-S")
+	if err != nil {
+		klog.Warningf("failed to read conntrack statistics: %v, output: %s", err, output)
+		return nil
+	}
+	stats.Drops = parseConntrackDrops(output)
+
+	eips := make([]string, 0, len(state.EIPs))
+	for _, eip := range state.EIPs {
+		if !slices.Contains(primaries, eip.Address) {
+			eips = append(eips, ipOf(eip.Address))
+		}
+	}
+	entries, err := a.eipConntrackEntries(eips)
+	if err != nil {
+		return nil
+	}
+
+	limitDrops := make(map[string]uint64)
+	for _, counter := range counters {
+		switch counter.Kind {
+		case KindConntrack:
+			stats.LimitDrops += counter.Packets
+		case KindConnLimit:
+			eip, _, _ := strings.Cut(counter.Rule, ",")
+			limitDrops[eip] += counter.Packets
+		}
+	}
+	for _, eip := range eips {
+		stats.EIPs = append(stats.EIPs, EIPConntrackStats{EIP: eip, Entries: entries[eip], LimitDrops: limitDrops[eip]})
+	}
+	return stats
+}
+
+// parseConntrackDrops sums the packets dropped since the conntrack entries failed to be created
+// from the output of conntrack -S, which prints the statistics of each cpu in a line
+func parseConntrackDrops(output string) uint64 {
+	var drops uint64
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		for _, field := range strings.Fields(scanner.Text()) {
+			name, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			if slices.Contains(conntrackDropFields, name) {
+				n, _ := strconv.ParseUint(value, 10, 64)
+				drops += n
+			}
+		}
+	}
+	return drops
+}
+
+// eipConntrackEntries returns the conntrack entries of the eips, the counts are cached
+// for conntrackEIPEntriesInterval unless there are eips not counted yet
+func (a *Agent) eipConntrackEntries(eips []string) (map[string]uint64, error) {
+	uncounted := slices.ContainsFunc(eips, func(eip string) bool {
+		_, ok := a.eipEntries[eip]
+		return !ok
+	})
+	if !uncounted && time.Since(a.eipEntriesTime) < conntrackEIPEntriesInterval {
+		return a.eipEntries, nil
+	}
+
+	entries := make(map[string]uint64, len(eips))
+	for _, eip := range eips {
+		n, err := a.conntrackEIPEntries(eip)
+		if err != nil {
+			return nil, err
+		}
+		entries[eip] = n
+	}
+	a.eipEntries, a.eipEntriesTime = entries, time.Now()
+	return entries, nil
+}
+
+// conntrackEIPEntries counts the conntrack entries of the eip with the address filters of conntrack -L
+// instead of listing the whole table, an entry belongs to the eip if the original destination is the eip
+// for the floating ips and dnats, or the reply destination is the eip for the floating ips and snats
+func (a *Agent) conntrackEIPEntries(eip string) (uint64, error) {
+	family := "ipv4"
+	if ip := net.ParseIP(eip); ip != nil && ip.To4() == nil {
+		family = "ipv6"
+	}
+	var entries uint64
+	for _, filter := range [...]string{"--orig-dst", "--reply-dst"} {
+		output, err := a.run("conntrack", "-L", "-f", family, filter, eip)
+		if err != nil {
+			klog.Warningf("failed to list conntrack entries of eip %s: %v, output: %s", eip, err, output)
+			return 0, err
+		}
+		entries += parseConntrackCount(output)
+	}
+	return entries, nil
+}
+
+// parseConntrackCount returns the number of the entries listed by conntrack -L from the summary,
+// or from the entries if the summary is not printed
+func parseConntrackCount(output string) uint64 {
+	if match := conntrackCountRegexp.FindStringSubmatch(output); match != nil {
+		n, _ := strconv.ParseUint(match[1], 10, 64)
+		return n
+	}
+	var n uint64
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		if strings.Contains(scanner.Text(), " src=") {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,95 @@
+package natgw
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseConntrackDrops(t *testing.T) {
+	t.Parallel()
+
+	output := `cpu=0   	found=0 invalid=12 insert=0 insert_failed=1 drop=2 early_drop=3 error=0 search_restart=0
+cpu=1   	found=0 invalid=0 insert=0 insert_failed=0 drop=4 early_drop=0 error=5 search_restart=0`
+	require.Equal(t, uint64(10), parseConntrackDrops(output))
+}
+
+func Test_parseConntrackCount(t *testing.T) {
+	t.Parallel()
+
+	output := `tcp      6 117 TIME_WAIT src=1.1.1.1 dst=172.18.11.3 sport=50000 dport=8888 src=10.0.1.6 dst=1.1.1.1 sport=80 dport=50000 [ASSURED] mark=0 use=1
+udp      17 29 src=1.1.1.1 dst=172.18.11.3 sport=53 dport=53 src=10.0.1.5 dst=1.1.1.1 sport=53 dport=53 mark=0 use=1
+conntrack v1.4.7 (conntrack-tools): 2 flow entries have been shown.`
+	require.Equal(t, uint64(2), parseConntrackCount(output))
+	require.Equal(t, uint64(0), parseConntrackCount("conntrack v1.4.7 (conntrack-tools): 0 flow entries have been shown."))
+	// the summary is missing
+	require.Equal(t, uint64(1), parseConntrackCount(strings.Split(output, "\n")[0]))
+	require.Equal(t, uint64(0), parseConntrackCount(""))
+}
+
+func Test_conntrackEqual(t *testing.T) {
+	t.Parallel()
+
+	actual := Conntrack{Max: 10000, TCPEstablishedTimeout: 432000, TCPTimeWaitTimeout: 120, UDPTimeout: 30, UDPStreamTimeout: 120}
+	require.True(t, conntrackEqual(Conntrack{Max: 10000}, actual))
+	require.True(t, conntrackEqual(Conntrack{Max: 10000, UDPTimeout: 30}, actual))
+	require.False(t, conntrackEqual(Conntrack{}, actual))
+	require.False(t, conntrackEqual(Conntrack{Max: 10000, TCPEstablishedTimeout: 3600}, actual))
+}
+
+func Test_conntrackStats(t *testing.T) {
+	t.Parallel()
+
+	runner := &fakeRunner{outputs: map[string]string{
+		"sysctl -n " + sysctlConntrackCount:            "3\n262144",
+		"conntrack -S":                                 "cpu=0 found=0 invalid=0 insert=0 insert_failed=0 drop=2 early_drop=0 error=0 search_restart=0",
+		"conntrack -L -f ipv4 --orig-dst 172.18.11.2":  "conntrack v1.4.7 (conntrack-tools): 1 flow entries have been shown.",
+		"conntrack -L -f ipv4 --reply-dst 172.18.11.2": "conntrack v1.4.7 (conntrack-tools): 2 flow entries have been shown.",
+		"conntrack -L -f ipv6 --orig-dst fc00::11":     "conntrack v1.4.7 (conntrack-tools): 0 flow entries have been shown.",
+		"conntrack -L -f ipv6 --reply-dst fc00::11":    "conntrack v1.4.7 (conntrack-tools): 4 flow entries have been shown.",
+	}}
+	agent := &Agent{script: "nat.sh", iface: "net1", run: runner.run}
+	state := &State{EIPs: []EIP{{Address: "172.18.0.2/16"}, {Address: "172.18.11.2/16"}, {Address: "fc00::11/64"}}}
+	counters := []Counter{
+		{Kind: KindConnLimit, Rule: "172.18.11.2,100", Packets: 2},
+		{Kind: KindConntrack, Rule: "10000", Packets: 3},
+	}
+
+	stats := agent.conntrackStats(state, []string{"172.18.0.2/16"}, counters)
+	require.Equal(t, &ConntrackStats{
+		Entries:    3,
+		Max:        262144,
+		Drops:      2,
+		LimitDrops: 3,
+		EIPs: []EIPConntrackStats{
+			{EIP: "172.18.11.2", Entries: 3, LimitDrops: 2},
+			{EIP: "fc00::11", Entries: 4},
+		},
+	}, stats)
+	// the whole conntrack table is never listed
+	require.NotContains(t, runner.commands, "conntrack -L")
+
+	// the entries of the eips are counted again only after the interval or if new eips are added
+	countEIPEntries := func() int {
+		n := 0
+		for _, cmd := range runner.commands {
+			if strings.HasPrefix(cmd, "conntrack -L") {
+				n++
+			}
+		}
+		return n
+	}
+	require.Equal(t, 4, countEIPEntries())
+	require.NotNil(t, agent.conntrackStats(state, []string{"172.18.0.2/16"}, counters))
+	require.Equal(t, 4, countEIPEntries())
+	agent.eipEntriesTime = agent.eipEntriesTime.Add(-conntrackEIPEntriesInterval)
+	require.NotNil(t, agent.conntrackStats(state, []string{"172.18.0.2/16"}, counters))
+	require.Equal(t, 8, countEIPEntries())
+	state.EIPs = append(state.EIPs, EIP{Address: "172.18.11.3/16"})
+	require.NotNil(t, agent.conntrackStats(state, []string{"172.18.0.2/16"}, counters))
+	require.Equal(t, 14, countEIPEntries())
+
+	runner.outputs["conntrack -S"] = "error"
+	require.Nil(t, agent.conntrackStats(state, []string{"172.18.0.2/16"}, counters))
+}
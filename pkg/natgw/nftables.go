@@ -0,0 +1,353 @@
+package natgw
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"github.com/kubeovn/kube-ovn/pkg/nftables"
+)
+
+const (
+	// NftablesTable is the table of the nat rules created by nat-gateway.sh init
+	NftablesTable = "kube-ovn-nat"
+	// ConnLimitTable is the table of the eip connection limits with the iptables backend,
+	// since the limits are applied after snat which is not possible with iptables
+	ConnLimitTable = "kube-ovn-connlimit"
+
+	nftDnatFilter    = "dnat-filter"
+	nftSnatFilter    = "snat-filter"
+	nftExclusiveDNAT = "exclusive-dnat"
+	nftExclusiveSNAT = "exclusive-snat"
+	nftSharedDNAT    = "shared-dnat"
+	nftSharedSNAT    = "shared-snat"
+	nftHairpinMark   = "hairpin-mark"
+	nftHairpinSNAT   = "hairpin-snat"
+	nftConnLimit     = "connlimit"
+	nftEIPConnLimit  = "eip-connlimit"
+
+	// hairpinMark marks the connections from the vpc to the eips with hairpin enabled,
+	// they are masqueraded when sent back to the vpc so that the replies return through the gateway
+	hairpinMark = "0x4000"
+	// vpcInterface is the interface of the nat gateway attached to the vpc
+	vpcInterface = "eth0"
+)
+
+// natRules are the floating ip, dnat, snat and hairpin rules programmed in the nat table,
+// along with the connection limits of the nat gateway and the eips
+type natRules struct {
+	fips       []FloatingIP
+	dnats      []DNAT
+	snats      []SNAT
+	hairpins   []Hairpin
+	connLimits []ConnLimit
+	connMax    int
+}
+
+// parseNatRule parses a rule in the format of the nat-gateway.sh arguments,
+// which is also used as the comment identifying the rule in the nftables table
+func (r *natRules) parseNatRule(kind, rule string) (string, error) {
+	fields := strings.Split(rule, ",")
+	switch {
+	case kind == KindFloatingIP && len(fields) >= 2:
+		fip := FloatingIP{EIP: ipOf(fields[0]), InternalIP: ipOf(fields[1])}
+		r.fips = append(r.fips, fip)
+		return fip.key(), nil
+	case kind == KindDNAT && len(fields) >= 5:
+		dnat := DNAT{EIP: ipOf(fields[0]), ExternalPort: fields[1], Protocol: strings.ToLower(fields[2]), InternalIP: ipOf(fields[3]), InternalPort: fields[4]}
+		r.dnats = append(r.dnats, dnat)
+		return dnat.key(), nil
+	case kind == KindSNAT && len(fields) >= 2:
+		// the optional --random-fully is ignored since the snat rules are always fully randomized
+		snat := SNAT{EIP: ipOf(fields[0]), InternalCIDR: fields[1]}
+		r.snats = append(r.snats, snat)
+		return snat.key(), nil
+	case kind == KindHairpin && len(fields) >= 1 && fields[0] != "":
+		hairpin := Hairpin{EIP: ipOf(fields[0])}
+		r.hairpins = append(r.hairpins, hairpin)
+		return hairpin.key(), nil
+	case kind == KindConnLimit && len(fields) >= 1 && fields[0] != "":
+		// the limit is omitted when deleting the connection limit of the eip
+		connLimit := ConnLimit{EIP: ipOf(fields[0])}
+		if len(fields) >= 2 {
+			limit, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return "", fmt.Errorf("invalid %s rule %q: %w", kind, rule, err)
+			}
+			connLimit.Limit = limit
+		}
+		r.connLimits = append(r.connLimits, connLimit)
+		return connLimit.key(), nil
+	case kind == KindConntrack && len(fields) >= 1:
+		// only the max is programmed in the table, the timeouts are set by nat-gateway.sh
+		connMax, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return "", fmt.Errorf("invalid %s rule %q: %w", kind, rule, err)
+		}
+		r.connMax = connMax
+		return KindConntrack, nil
+	}
+	return "", fmt.Errorf("invalid %s rule %q", kind, rule)
+}
+
+// parseNftNatRules parses the nat rules and counters from the output of nft list table,
+// the rules are identified by the comments and a floating ip is made up of a dnat and a snat rule
+func parseNftNatRules(output string) (*natRules, []Counter) {
+	rules := &natRules{}
+	var counters []Counter
+	index := make(map[string]int)
+	for _, rule := range nftables.ParseRules(output) {
+		kind, r, _ := strings.Cut(rule.Comment, ",")
+		if i, ok := index[rule.Comment]; ok {
+			counters[i].Packets += rule.Packets
+			counters[i].Bytes += rule.Bytes
+			continue
+		}
+		if _, err := rules.parseNatRule(kind, r); err != nil {
+			klog.Warningf("unknown rule %q in nftables table %s: %v", rule.Rule, NftablesTable, err)
+			continue
+		}
+		index[rule.Comment] = len(counters)
+		counters = append(counters, Counter{Kind: kind, Rule: r, Packets: rule.Packets, Bytes: rule.Bytes})
+	}
+	return rules, counters
+}
+
+func (r *natRules) apply(operation Operation, rules []string) error {
+	var kind string
+	var del bool
+	switch operation {
+	case OpFipAdd, OpFipDel:
+		kind, del = KindFloatingIP, operation == OpFipDel
+	case OpDnatAdd, OpDnatDel:
+		kind, del = KindDNAT, operation == OpDnatDel
+	case OpSnatAdd, OpSnatDel:
+		kind, del = KindSNAT, operation == OpSnatDel
+	case OpHairpinAdd, OpHairpinDel:
+		kind, del = KindHairpin, operation == OpHairpinDel
+	case OpConnLimitAdd, OpConnLimitDel:
+		kind, del = KindConnLimit, operation == OpConnLimitDel
+	case OpConntrackSet:
+		kind = KindConntrack
+	default:
+		return fmt.Errorf("unsupported nat operation %s", operation)
+	}
+
+	changes := &natRules{}
+	keys := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		key, err := changes.parseNatRule(kind, rule)
+		if err != nil {
+			return err
+		}
+		keys[key] = true
+	}
+	if del {
+		r.fips = slices.DeleteFunc(r.fips, func(fip FloatingIP) bool { return keys[fip.key()] })
+		r.dnats = slices.DeleteFunc(r.dnats, func(dnat DNAT) bool { return keys[dnat.key()] })
+		r.snats = slices.DeleteFunc(r.snats, func(snat SNAT) bool { return keys[snat.key()] })
+		r.hairpins = slices.DeleteFunc(r.hairpins, func(hairpin Hairpin) bool { return keys[hairpin.key()] })
+		r.connLimits = slices.DeleteFunc(r.connLimits, func(connLimit ConnLimit) bool { return keys[connLimit.key()] })
+		return nil
+	}
+	if kind == KindConntrack {
+		r.connMax = changes.connMax
+		return nil
+	}
+	if kind == KindConnLimit {
+		// the old connection limit of the eip is replaced, the same as nat-gateway.sh does
+		r.connLimits = slices.DeleteFunc(r.connLimits, func(connLimit ConnLimit) bool { return keys[connLimit.key()] })
+	}
+
+	// the rules are added only if they do not exist, the same as nat-gateway.sh does
+	r.fips = appendRules(r.fips, changes.fips, FloatingIP.key)
+	r.dnats = appendRules(r.dnats, changes.dnats, DNAT.key)
+	r.snats = appendRules(r.snats, changes.snats, SNAT.key)
+	r.hairpins = appendRules(r.hairpins, changes.hairpins, Hairpin.key)
+	r.connLimits = appendRules(r.connLimits, changes.connLimits, ConnLimit.key)
+	return nil
+}
+
+func appendRules[T any](rules, added []T, key func(T) string) []T {
+	keys := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		keys[key(r)] = true
+	}
+	for _, r := range added {
+		if !keys[key(r)] {
+			keys[key(r)] = true
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+func cidrPrefixLen(cidr string) int {
+	if _, ipNet, err := net.ParseCIDR(normalizeCIDR(cidr)); err == nil {
+		ones, _ := ipNet.Mask.Size()
+		return ones
+	}
+	return 0
+}
+
+// table generates the nftables table of the nat rules, the snat rules of longer prefixes
+// are placed first so that the result does not depend on the order the rules are added
+func (r *natRules) table(iface string) *nftables.Table {
+	table := nftables.NewTable(nftables.FamilyIP, NftablesTable)
+	exclusiveDNAT := table.AddChain(nftExclusiveDNAT)
+	exclusiveSNAT := table.AddChain(nftExclusiveSNAT)
+	sharedDNAT := table.AddChain(nftSharedDNAT)
+	sharedSNAT := table.AddChain(nftSharedSNAT)
+	hairpinMarkChain := table.AddChain(nftHairpinMark)
+	hairpinSNAT := table.AddChain(nftHairpinSNAT)
+
+	for _, hairpin := range r.hairpins {
+		comment := nftables.Quote(KindHairpin + "," + hairpin.Rule())
+		hairpinMarkChain.AddRule("iifname %s ip daddr %s counter meta mark set meta mark | %s comment %s", nftables.Quote(vpcInterface), hairpin.EIP, hairpinMark, comment)
+	}
+	hairpinSNAT.AddRule("oifname %s meta mark & %s == %s masquerade", nftables.Quote(vpcInterface), hairpinMark, hairpinMark)
+
+	for _, fip := range r.fips {
+		comment := nftables.Quote(KindFloatingIP + "," + fip.Rule())
+		exclusiveDNAT.AddRule("ip daddr %s counter dnat to %s comment %s", fip.EIP, fip.InternalIP, comment)
+		exclusiveSNAT.AddRule("ip saddr %s counter snat to %s comment %s", fip.InternalIP, fip.EIP, comment)
+	}
+	for _, dnat := range r.dnats {
+		comment := nftables.Quote(KindDNAT + "," + dnat.Rule())
+		if strings.Contains(dnat.ExternalPort, "-") {
+			// a port range is mapped to the same ports
+			sharedDNAT.AddRule("ip daddr %s %s dport %s counter dnat to %s comment %s", dnat.EIP, dnat.Protocol, dnat.ExternalPort, dnat.InternalIP, comment)
+			continue
+		}
+		sharedDNAT.AddRule("ip daddr %s %s dport %s counter dnat to %s:%s comment %s", dnat.EIP, dnat.Protocol, dnat.ExternalPort, dnat.InternalIP, dnat.InternalPort, comment)
+	}
+	snats := slices.Clone(r.snats)
+	slices.SortStableFunc(snats, func(a, b SNAT) int { return cidrPrefixLen(b.InternalCIDR) - cidrPrefixLen(a.InternalCIDR) })
+	for _, snat := range snats {
+		comment := nftables.Quote(KindSNAT + "," + snat.Rule())
+		sharedSNAT.AddRule("oifname %s ip saddr %s counter snat to %s fully-random comment %s", nftables.Quote(iface), snat.InternalCIDR, snat.EIP, comment)
+	}
+
+	dnatFilter := table.AddBaseChain(nftDnatFilter, nftables.ChainTypeNat, nftables.HookPrerouting, nftables.PriorityDstNat)
+	dnatFilter.AddRule("jump %s", hairpinMarkChain.Name)
+	dnatFilter.AddRule("jump %s", exclusiveDNAT.Name)
+	dnatFilter.AddRule("jump %s", sharedDNAT.Name)
+	snatFilter := table.AddBaseChain(nftSnatFilter, nftables.ChainTypeNat, nftables.HookPostrouting, nftables.PrioritySrcNat)
+	snatFilter.AddRule("jump %s", hairpinSNAT.Name)
+	snatFilter.AddRule("jump %s", exclusiveSNAT.Name)
+	snatFilter.AddRule("jump %s", sharedSNAT.Name)
+
+	addEIPConnLimits(table, r.connLimits)
+	// the new connections over the limit of the nat gateway are dropped before being forwarded
+	connLimit := table.AddBaseChain(nftConnLimit, nftables.ChainTypeFilter, nftables.HookForward, nftables.PriorityFilter)
+	if r.connMax > 0 {
+		comment := nftables.Quote(KindConntrack + "," + strconv.Itoa(r.connMax))
+		connLimit.AddRule("ct state new ct count over %d counter drop comment %s", r.connMax, comment)
+	}
+	return table
+}
+
+// eipConnLimitChain returns the chain counting the connections of the eip
+func eipConnLimitChain(eip string) string {
+	return nftConnLimit + "-" + eip
+}
+
+// addEIPConnLimits adds the connection limits of the eips, the same as nat-gateway.sh does with the iptables backend.
+// The limits are applied after snat, so that a connection belongs to the eip if the original destination is the eip
+// for the floating ips and dnats, or the reply destination is the eip for the floating ips and snats.
+// Both are counted by the single rule in the chain of the eip, so that they share the limit.
+func addEIPConnLimits(table *nftables.Table, limits []ConnLimit) {
+	chains := make([]string, 0, len(limits))
+	for _, limit := range limits {
+		comment := nftables.Quote(KindConnLimit + "," + limit.Rule())
+		chain := table.AddChain(eipConnLimitChain(limit.EIP))
+		chain.AddRule("ct count over %d counter drop comment %s", limit.Limit, comment)
+		chains = append(chains, chain.Name)
+	}
+	eipConnLimit := table.AddBaseChain(nftEIPConnLimit, nftables.ChainTypeFilter, nftables.HookPostrouting, nftables.PrioritySrcNat+" + 1")
+	for i, limit := range limits {
+		eipConnLimit.AddRule("ct state new ct original ip daddr %s jump %s", limit.EIP, chains[i])
+		eipConnLimit.AddRule("ct state new ct reply ip daddr %s jump %s", limit.EIP, chains[i])
+	}
+}
+
+// newNatRules returns the nat rules of the desired state, the duplicated rules are removed
+func newNatRules(state *State) *natRules {
+	r := &natRules{}
+	for _, fip := range state.FloatingIPs {
+		_, _ = r.parseNatRule(KindFloatingIP, fip.Rule())
+	}
+	for _, dnat := range state.DNATs {
+		_, _ = r.parseNatRule(KindDNAT, dnat.Rule())
+	}
+	for _, snat := range state.SNATs {
+		_, _ = r.parseNatRule(KindSNAT, snat.Rule())
+	}
+	for _, hairpin := range state.Hairpins {
+		_, _ = r.parseNatRule(KindHairpin, hairpin.Rule())
+	}
+	for _, connLimit := range state.ConnLimits {
+		_, _ = r.parseNatRule(KindConnLimit, connLimit.Rule())
+	}
+	if state.Conntrack != nil {
+		r.connMax = state.Conntrack.Max
+	}
+	r.fips = appendRules(nil, r.fips, FloatingIP.key)
+	r.dnats = appendRules(nil, r.dnats, DNAT.key)
+	r.snats = appendRules(nil, r.snats, SNAT.key)
+	r.hairpins = appendRules(nil, r.hairpins, Hairpin.key)
+	r.connLimits = appendRules(nil, r.connLimits, ConnLimit.key)
+	return r
+}
+
+func isNatOperation(operation Operation) bool {
+	switch operation {
+	case OpFipAdd, OpFipDel, OpDnatAdd, OpDnatDel, OpSnatAdd, OpSnatDel, OpHairpinAdd, OpHairpinDel,
+		OpConnLimitAdd, OpConnLimitDel, OpConntrackSet:
+		return true
+	}
+	return false
+}
+
+// readNftNatRules reads the nat rules from the nftables table created by nat-gateway.sh init
+func (a *Agent) readNftNatRules() (*natRules, []Counter, error) {
+	output, exists, err := a.nft.List(nftables.FamilyIP, NftablesTable)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exists {
+		return nil, nil, fmt.Errorf("nat gateway not inited, nftables table %s not found", NftablesTable)
+	}
+	rules, counters := parseNftNatRules(output)
+	return rules, counters, nil
+}
+
+// readNftConnLimits reads the eip connection limits from the nftables table
+// created by nat-gateway.sh with the iptables backend, the table is created on demand
+func (a *Agent) readNftConnLimits() ([]ConnLimit, []Counter, error) {
+	output, exists, err := a.nft.List(nftables.FamilyIP, ConnLimitTable)
+	if err != nil || !exists {
+		return nil, nil, err
+	}
+	rules, counters := parseNftNatRules(output)
+	return rules.connLimits, counters, nil
+}
+
+// execNft executes the incremental nat operation by replacing the table with the updated rules
+func (a *Agent) execNft(operation Operation, rules []string) error {
+	klog.V(3).Infof("nftables %s %s", operation, strings.Join(rules, " "))
+	current, _, err := a.readNftNatRules()
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if err = current.apply(operation, rules); err != nil {
+		klog.Error(err)
+		return err
+	}
+	return a.nft.Replace(current.table(a.iface))
+}
@@ -0,0 +1,137 @@
+package natgw
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeovn/kube-ovn/pkg/nftables"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+const nftListOutput = `table ip kube-ovn-nat {
+	chain exclusive-dnat {
+		ip daddr 172.18.11.2 counter packets 3 bytes 180 dnat to 10.0.1.5 comment "floating-ip,172.18.11.2,10.0.1.5"
+	}
+	chain exclusive-snat {
+		ip saddr 10.0.1.5 counter packets 2 bytes 120 snat to 172.18.11.2 comment "floating-ip,172.18.11.2,10.0.1.5"
+	}
+	chain shared-dnat {
+		ip daddr 172.18.11.3 tcp dport 8888 counter packets 5 bytes 300 dnat to 10.0.1.6:80 comment "dnat,172.18.11.3,8888,tcp,10.0.1.6,80"
+	}
+	chain shared-snat {
+		oifname "net1" ip saddr 10.0.1.0/24 counter packets 7 bytes 420 snat to 172.18.11.4 fully-random comment "snat,172.18.11.4,10.0.1.0/24"
+	}
+	chain dnat-filter {
+		type nat hook prerouting priority dstnat; policy accept;
+		jump exclusive-dnat
+		jump shared-dnat
+	}
+	chain snat-filter {
+		type nat hook postrouting priority srcnat; policy accept;
+		jump exclusive-snat
+		jump shared-snat
+	}
+}`
+
+const nftConnLimitListOutput = `table ip kube-ovn-connlimit {
+	chain eip-connlimit {
+		type filter hook postrouting priority srcnat + 1; policy accept;
+		ct state new ct original ip daddr 172.18.11.2 jump connlimit-172.18.11.2
+		ct state new ct reply ip daddr 172.18.11.2 jump connlimit-172.18.11.2
+	}
+	chain connlimit-172.18.11.2 {
+		ct count over 100 counter packets 2 bytes 120 drop comment "connlimit,172.18.11.2,100"
+	}
+}`
+
+func Test_parseNftNatRules(t *testing.T) {
+	t.Parallel()
+
+	rules, counters := parseNftNatRules(nftListOutput)
+	require.Equal(t, []FloatingIP{{EIP: "172.18.11.2", InternalIP: "10.0.1.5"}}, rules.fips)
+	require.Equal(t, []DNAT{{EIP: "172.18.11.3", Protocol: "tcp", ExternalPort: "8888", InternalIP: "10.0.1.6", InternalPort: "80"}}, rules.dnats)
+	require.Equal(t, []SNAT{{EIP: "172.18.11.4", InternalCIDR: "10.0.1.0/24"}}, rules.snats)
+	require.ElementsMatch(t, []Counter{
+		{Kind: KindFloatingIP, Rule: "172.18.11.2,10.0.1.5", Packets: 5, Bytes: 300},
+		{Kind: KindDNAT, Rule: "172.18.11.3,8888,tcp,10.0.1.6,80", Packets: 5, Bytes: 300},
+		{Kind: KindSNAT, Rule: "172.18.11.4,10.0.1.0/24", Packets: 7, Bytes: 420},
+	}, counters)
+}
+
+func Test_natRulesApply(t *testing.T) {
+	t.Parallel()
+
+	rules, _ := parseNftNatRules(nftListOutput)
+	require.NoError(t, rules.apply(OpSnatAdd, []string{"172.18.11.2,10.0.0.0/16", "172.18.11.4,10.0.1.0/24,--random-fully"}))
+	require.Equal(t, []SNAT{{EIP: "172.18.11.4", InternalCIDR: "10.0.1.0/24"}, {EIP: "172.18.11.2", InternalCIDR: "10.0.0.0/16"}}, rules.snats)
+	require.NoError(t, rules.apply(OpFipDel, []string{"172.18.11.2,10.0.1.5"}))
+	require.Empty(t, rules.fips)
+	require.Error(t, rules.apply(OpDnatAdd, []string{"172.18.11.3,8888"}))
+	require.NoError(t, rules.apply(OpDnatAdd, []string{"172.18.11.3,8000-8100,udp,10.0.1.7,8000-8100"}))
+	require.Error(t, rules.apply(OpEipAdd, nil))
+	require.NoError(t, rules.apply(OpHairpinAdd, []string{"172.18.11.3/16"}))
+	require.Equal(t, []Hairpin{{EIP: "172.18.11.3"}}, rules.hairpins)
+	require.NoError(t, rules.apply(OpConnLimitAdd, []string{"172.18.11.3,100"}))
+	require.NoError(t, rules.apply(OpConnLimitAdd, []string{"172.18.11.3,200"}))
+	require.Equal(t, []ConnLimit{{EIP: "172.18.11.3", Limit: 200}}, rules.connLimits)
+	require.NoError(t, rules.apply(OpConntrackSet, []string{"10000,0,0,60,0"}))
+	require.Equal(t, 10000, rules.connMax)
+
+	script := rules.table("net1").Script()
+	require.NotContains(t, script, "10.0.1.5")
+	require.Contains(t, script, `ip daddr 172.18.11.3 tcp dport 8888 counter dnat to 10.0.1.6:80 comment "dnat,172.18.11.3,8888,tcp,10.0.1.6,80"`)
+	require.Contains(t, script, `ip daddr 172.18.11.3 udp dport 8000-8100 counter dnat to 10.0.1.7 comment "dnat,172.18.11.3,8000-8100,udp,10.0.1.7,8000-8100"`)
+	require.Contains(t, script, `iifname "eth0" ip daddr 172.18.11.3 counter meta mark set meta mark | 0x4000 comment "hairpin,172.18.11.3"`)
+	require.Contains(t, script, `oifname "eth0" meta mark & 0x4000 == 0x4000 masquerade`)
+	// the connections to and snated to the eip share the limit applied after snat
+	require.Contains(t, script, "\tchain connlimit-172.18.11.3 {\n\t\tct count over 200 counter drop comment \"connlimit,172.18.11.3,200\"\n\t}")
+	require.Contains(t, script, "type filter hook postrouting priority srcnat + 1; policy accept;\n"+
+		"\t\tct state new ct original ip daddr 172.18.11.3 jump connlimit-172.18.11.3\n"+
+		"\t\tct state new ct reply ip daddr 172.18.11.3 jump connlimit-172.18.11.3\n")
+	require.Less(t, strings.Index(script, "chain connlimit-172.18.11.3"), strings.Index(script, "chain eip-connlimit"))
+	require.Contains(t, script, `ct state new ct count over 10000 counter drop comment "conntrack,10000"`)
+
+	require.NoError(t, rules.apply(OpConnLimitDel, []string{"172.18.11.3"}))
+	require.Empty(t, rules.connLimits)
+	// the snat rule of the longer prefix is matched first
+	require.Less(t, strings.Index(script, "10.0.1.0/24"), strings.Index(script, "10.0.0.0/16"))
+}
+
+func Test_SyncNftables(t *testing.T) {
+	t.Parallel()
+
+	runner := &fakeRunner{outputs: map[string]string{
+		"ip -o -4 addr show":             "3: net1    inet 172.18.11.2/16 brd 172.18.255.255 scope global net1",
+		"tc qdisc show dev net1 ingress": "",
+		"tc qdisc show dev net1 root":    "qdisc noqueue 0: root refcnt 2",
+	}}
+	var scripts []string
+	nft := nftables.NewWithRunner(func(stdin string, args ...string) (string, error) {
+		if len(args) != 0 && args[0] == "list" {
+			return nftListOutput, nil
+		}
+		scripts = append(scripts, stdin)
+		return "", nil
+	})
+	agent := &Agent{script: "nat.sh", iface: "net1", run: runner.run, backend: util.FirewallBackendNftables, nft: nft}
+
+	state := &State{
+		EIPs:        []EIP{{Address: "172.18.11.2/16", Gateway: "172.18.0.1"}},
+		FloatingIPs: []FloatingIP{{EIP: "172.18.11.2", InternalIP: "10.0.1.5"}},
+		SNATs:       []SNAT{{EIP: "172.18.11.4", InternalCIDR: "10.0.1.0/24"}, {EIP: "172.18.11.2", InternalCIDR: "10.0.2.0/24"}},
+	}
+
+	resp, err := agent.Sync(&SyncRequest{State: *state})
+	require.NoError(t, err)
+	require.Len(t, resp.Drifts, 2)
+	require.Len(t, resp.Counters, 3)
+	require.Len(t, scripts, 1)
+	require.Contains(t, scripts[0], `oifname "net1" ip saddr 10.0.2.0/24 counter snat to 172.18.11.2 fully-random`)
+	require.NotContains(t, scripts[0], "dnat to 10.0.1.6:80")
+	for _, cmd := range runner.commands {
+		require.NotContains(t, cmd, "nat.sh")
+		require.NotContains(t, cmd, "iptables")
+	}
+}
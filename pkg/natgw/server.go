@@ -0,0 +1,89 @@
+package natgw
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// NewHandler returns the http handler of the agent api
+func NewHandler(agent *Agent) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(HealthzPath, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc(RulesPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method), "")
+			return
+		}
+		var req RuleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+		if err := req.Validate(); err != nil {
+			writeError(w, http.StatusBadRequest, err, "")
+			return
+		}
+		output, err := agent.Exec(req.Operation, req.Rules)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err, output)
+			return
+		}
+		writeJSON(w, http.StatusOK, &RuleResponse{Output: output})
+	})
+	mux.HandleFunc(StatePath, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			resp, err := agent.GetState()
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err, "")
+				return
+			}
+			writeJSON(w, http.StatusOK, resp)
+		case http.MethodPut:
+			var req SyncRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, http.StatusBadRequest, err, "")
+				return
+			}
+			resp, err := agent.Sync(&req)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err, "")
+				return
+			}
+			writeJSON(w, http.StatusOK, resp)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method), "")
+		}
+	})
+	return mux
+}
+
+// RunServer runs the agent api server, the controller accesses it through pod port forwarding,
+// so listening on the loopback address is enough and the api is not exposed to the networks
+func RunServer(addr string, agent *Agent) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           NewHandler(agent),
+		ReadHeaderTimeout: 3 * time.Second,
+	}
+	klog.Infof("nat gateway agent listening on %s", addr)
+	return server.ListenAndServe()
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		klog.Errorf("failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, err error, output string) {
+	writeJSON(w, code, &ErrorResponse{Error: err.Error(), Output: output})
+}
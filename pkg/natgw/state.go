@@ -0,0 +1,260 @@
+package natgw
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	chainExclusiveDNAT = "EXCLUSIVE_DNAT"
+	chainExclusiveSNAT = "EXCLUSIVE_SNAT"
+	chainSharedDNAT    = "SHARED_DNAT"
+	chainSharedSNAT    = "SHARED_SNAT"
+	chainHairpinMark   = "HAIRPIN_MARK"
+	chainConnLimit     = "CONNLIMIT"
+)
+
+// ipOf returns the ip address without the prefix length
+func ipOf(s string) string {
+	ip, _, _ := strings.Cut(s, "/")
+	return ip
+}
+
+// normalizeCIDR returns the cidr in the format printed by iptables-save
+func normalizeCIDR(s string) string {
+	if !strings.Contains(s, "/") {
+		if ip := net.ParseIP(s); ip != nil && ip.To4() == nil {
+			return s + "/128"
+		}
+		return s + "/32"
+	}
+	if _, cidr, err := net.ParseCIDR(s); err == nil {
+		return cidr.String()
+	}
+	return s
+}
+
+// iptablesRule is a rule of the nat table parsed from the output of iptables-save -c
+type iptablesRule struct {
+	chain   string
+	args    map[string]string
+	packets uint64
+	bytes   uint64
+}
+
+func parseIptablesRule(line string) (*iptablesRule, bool) {
+	rule := &iptablesRule{args: make(map[string]string)}
+	if strings.HasPrefix(line, "[") {
+		counters, remaining, found := strings.Cut(line[1:], "]")
+		if !found {
+			return nil, false
+		}
+		packets, bytes, _ := strings.Cut(counters, ":")
+		rule.packets, _ = strconv.ParseUint(packets, 10, 64)
+		rule.bytes, _ = strconv.ParseUint(bytes, 10, 64)
+		line = strings.TrimSpace(remaining)
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 2 || fields[0] != "-A" {
+		return nil, false
+	}
+	rule.chain = fields[1]
+	for i := 2; i < len(fields); i++ {
+		if !strings.HasPrefix(fields[i], "-") {
+			continue
+		}
+		if i+1 < len(fields) && !strings.HasPrefix(fields[i+1], "-") {
+			rule.args[fields[i]] = fields[i+1]
+			i++
+		} else {
+			rule.args[fields[i]] = ""
+		}
+	}
+	return rule, true
+}
+
+// parseNatRules parses the floating ip, dnat, snat and hairpin rules from the output of iptables-save -c -t nat,
+// and the connection limit of the nat gateway from the output of iptables-save -c -t filter
+func parseNatRules(output string) (*natRules, []Counter) {
+	rules := &natRules{}
+	var counters []Counter
+	fipSnats := make(map[string]*iptablesRule)
+	var fipDnats []*iptablesRule
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		rule, ok := parseIptablesRule(strings.TrimSpace(scanner.Text()))
+		if !ok {
+			continue
+		}
+		switch rule.chain {
+		case chainExclusiveDNAT:
+			fipDnats = append(fipDnats, rule)
+		case chainExclusiveSNAT:
+			fipSnats[ipOf(rule.args["-s"])] = rule
+		case chainSharedDNAT:
+			// a port range is mapped to the same ports without the port in the destination
+			externalPort := strings.ReplaceAll(rule.args["--dport"], ":", "-")
+			internalIP, internalPort, ok := strings.Cut(rule.args["--to-destination"], ":")
+			if !ok {
+				internalPort = externalPort
+			}
+			dnat := DNAT{
+				EIP:          ipOf(rule.args["-d"]),
+				Protocol:     rule.args["-p"],
+				ExternalPort: externalPort,
+				InternalIP:   internalIP,
+				InternalPort: internalPort,
+			}
+			rules.dnats = append(rules.dnats, dnat)
+			counters = append(counters, Counter{Kind: KindDNAT, Rule: dnat.Rule(), Packets: rule.packets, Bytes: rule.bytes})
+		case chainSharedSNAT:
+			snat := SNAT{EIP: rule.args["--to-source"], InternalCIDR: rule.args["-s"]}
+			rules.snats = append(rules.snats, snat)
+			counters = append(counters, Counter{Kind: KindSNAT, Rule: snat.Rule(), Packets: rule.packets, Bytes: rule.bytes})
+		case chainHairpinMark:
+			hairpin := Hairpin{EIP: ipOf(rule.args["-d"])}
+			rules.hairpins = append(rules.hairpins, hairpin)
+			counters = append(counters, Counter{Kind: KindHairpin, Rule: hairpin.Rule(), Packets: rule.packets, Bytes: rule.bytes})
+		case chainConnLimit:
+			limit, _ := strconv.Atoi(rule.args["--connlimit-above"])
+			// the connection limits of the eips are applied in nftables after snat, the ones matching
+			// the original destination are left by the previous versions and they do not limit
+			// the snat connections, so they are reported as mismatched to be replaced
+			if eip := rule.args["--ctorigdst"]; eip != "" {
+				connLimit := ConnLimit{EIP: ipOf(eip)}
+				rules.connLimits = append(rules.connLimits, connLimit)
+				counters = append(counters, Counter{Kind: KindConnLimit, Rule: connLimit.Rule(), Packets: rule.packets, Bytes: rule.bytes})
+			} else {
+				rules.connMax = limit
+				counters = append(counters, Counter{Kind: KindConntrack, Rule: strconv.Itoa(limit), Packets: rule.packets, Bytes: rule.bytes})
+			}
+		}
+	}
+
+	// a floating ip is made up of a dnat rule and a snat rule, it is identified by the dnat one
+	// which is checked by nat-gateway.sh before adding or deleting the floating ip
+	for _, rule := range fipDnats {
+		fip := FloatingIP{EIP: ipOf(rule.args["-d"]), InternalIP: rule.args["--to-destination"]}
+		counter := Counter{Kind: KindFloatingIP, Rule: fip.Rule(), Packets: rule.packets, Bytes: rule.bytes}
+		if snat := fipSnats[fip.InternalIP]; snat != nil {
+			counter.Packets += snat.packets
+			counter.Bytes += snat.bytes
+		}
+		rules.fips = append(rules.fips, fip)
+		counters = append(counters, counter)
+	}
+	return rules, counters
+}
+
+// parseAddresses parses the output of ip -o addr show, the primary address is returned separately
+// since it is allocated to the external interface by the cni and it is not managed as an eip.
+// The ipv6 addresses are never secondary, but the ones of the same scope are listed in the order
+// they are added, so the first one is the address allocated by the cni.
+func parseAddresses(output string) (eips []EIP, primary string) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		for i := 0; i+1 < len(fields); i++ {
+			if fields[i] != "inet" && fields[i] != "inet6" {
+				continue
+			}
+			address := fields[i+1]
+			if primary == "" && !strings.Contains(scanner.Text(), " secondary ") {
+				primary = address
+			}
+			eips = append(eips, EIP{Address: address})
+			break
+		}
+	}
+	return eips, primary
+}
+
+var (
+	tcFilterRegexp = regexp.MustCompile(`^filter .*pref (\d+) u32 .*fh \w+::\w+`)
+	tcMatchRegexp  = regexp.MustCompile(`match IP (src|dst) ([0-9.]+)/32`)
+	tcPoliceRegexp = regexp.MustCompile(`police .*rate (\S+) burst (\S+)`)
+)
+
+// parseQoS parses the eip bandwidth limit filters from the output of tc -s -d filter show
+func parseQoS(output, direction string) []EIPQoS {
+	var result []EIPQoS
+	var current *EIPQoS
+	flush := func() {
+		if current != nil && current.EIP != "" && current.Rate != "" {
+			result = append(result, *current)
+		}
+		current = nil
+	}
+
+	matchDirection := "dst"
+	if direction == QoSEgress {
+		matchDirection = "src"
+	}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if match := tcFilterRegexp.FindStringSubmatch(line); match != nil {
+			flush()
+			priority, _ := strconv.Atoi(match[1])
+			current = &EIPQoS{Direction: direction, Priority: priority}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if match := tcMatchRegexp.FindStringSubmatch(line); match != nil && match[1] == matchDirection {
+			current.EIP = match[2]
+		} else if match := tcPoliceRegexp.FindStringSubmatch(line); match != nil {
+			current.Rate, current.Burst = match[1], match[2]
+		}
+	}
+	flush()
+	return result
+}
+
+var tcSizeRegexp = regexp.MustCompile(`^([0-9.]+)([A-Za-z]*)$`)
+
+// parseTcSize parses the rate or burst printed by tc, the value without unit is in the default unit
+func parseTcSize(s string, defaultUnit float64, units map[string]float64) (float64, error) {
+	match := tcSizeRegexp.FindStringSubmatch(s)
+	if match == nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, err
+	}
+	if match[2] == "" {
+		return value * defaultUnit, nil
+	}
+	unit, ok := units[match[2]]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit of size %q", s)
+	}
+	return value * unit, nil
+}
+
+var (
+	tcRateUnits  = map[string]float64{"bit": 1, "Kbit": 1e3, "Mbit": 1e6, "Gbit": 1e9, "Tbit": 1e12}
+	tcBurstUnits = map[string]float64{"b": 1, "Kb": 1 << 10, "Mb": 1 << 20, "Gb": 1 << 30}
+)
+
+// qosEqual compares the desired bandwidth limit in Mbit/s and MBytes with the one printed by tc
+func qosEqual(desired, actual EIPQoS) bool {
+	if desired.Priority != actual.Priority {
+		return false
+	}
+	equal := func(a, b string, defaultUnit float64, units map[string]float64) bool {
+		x, err1 := parseTcSize(a, defaultUnit, units)
+		y, err2 := parseTcSize(b, defaultUnit, units)
+		// tc prints the values with limited precision
+		return err1 == nil && err2 == nil && math.Abs(x-y) <= math.Max(x, y)*0.01
+	}
+	return equal(desired.Rate, actual.Rate, 1e6, tcRateUnits) && equal(desired.Burst, actual.Burst, 1<<20, tcBurstUnits)
+}
@@ -0,0 +1,157 @@
+package natgw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const iptablesSaveOutput = `# Generated by iptables-save v1.8.9 (legacy) on Mon Mar  4 08:00:00 2024
+*nat
+:PREROUTING ACCEPT [0:0]
+:DNAT_FILTER - [0:0]
+:EXCLUSIVE_DNAT - [0:0]
+:EXCLUSIVE_SNAT - [0:0]
+:HAIRPIN_MARK - [0:0]
+:HAIRPIN_SNAT - [0:0]
+:SHARED_DNAT - [0:0]
+:SHARED_SNAT - [0:0]
+:SNAT_FILTER - [0:0]
+[10:600] -A PREROUTING -j DNAT_FILTER
+[4:240] -A DNAT_FILTER -j HAIRPIN_MARK
+[1:60] -A HAIRPIN_MARK -d 172.18.11.3/32 -i eth0 -j MARK --set-xmark 0x4000/0x4000
+[1:60] -A HAIRPIN_SNAT -o eth0 -m mark --mark 0x4000/0x4000 -j MASQUERADE
+[3:180] -A EXCLUSIVE_DNAT -d 172.18.11.2/32 -j DNAT --to-destination 10.0.1.5
+[2:120] -A EXCLUSIVE_SNAT -s 10.0.1.5/32 -j SNAT --to-source 172.18.11.2
+[5:300] -A SHARED_DNAT -d 172.18.11.3/32 -p tcp -m tcp --dport 8888 -j DNAT --to-destination 10.0.1.6:80
+[7:420] -A SHARED_SNAT -s 10.0.1.0/24 -o net1 -j SNAT --to-source 172.18.11.4 --random-fully
+COMMIT
+`
+
+const iptablesSaveFilterOutput = `*filter
+:INPUT ACCEPT [0:0]
+:FORWARD ACCEPT [0:0]
+:OUTPUT ACCEPT [0:0]
+:CONNLIMIT - [0:0]
+[20:1200] -A FORWARD -j CONNLIMIT
+[3:180] -A CONNLIMIT -m conntrack --ctstate NEW -m connlimit --connlimit-above 10000 --connlimit-mask 0 --connlimit-saddr -j DROP
+COMMIT
+`
+
+func Test_parseNatRules(t *testing.T) {
+	t.Parallel()
+
+	rules, counters := parseNatRules(iptablesSaveOutput)
+	require.Equal(t, []FloatingIP{{EIP: "172.18.11.2", InternalIP: "10.0.1.5"}}, rules.fips)
+	require.Equal(t, []DNAT{{EIP: "172.18.11.3", Protocol: "tcp", ExternalPort: "8888", InternalIP: "10.0.1.6", InternalPort: "80"}}, rules.dnats)
+	require.Equal(t, []SNAT{{EIP: "172.18.11.4", InternalCIDR: "10.0.1.0/24"}}, rules.snats)
+	require.Equal(t, []Hairpin{{EIP: "172.18.11.3"}}, rules.hairpins)
+	require.Empty(t, rules.connLimits)
+	require.ElementsMatch(t, []Counter{
+		{Kind: KindHairpin, Rule: "172.18.11.3", Packets: 1, Bytes: 60},
+		{Kind: KindFloatingIP, Rule: "172.18.11.2,10.0.1.5", Packets: 5, Bytes: 300},
+		{Kind: KindDNAT, Rule: "172.18.11.3,8888,tcp,10.0.1.6,80", Packets: 5, Bytes: 300},
+		{Kind: KindSNAT, Rule: "172.18.11.4,10.0.1.0/24", Packets: 7, Bytes: 420},
+	}, counters)
+}
+
+func Test_parseNatRulesConnLimit(t *testing.T) {
+	t.Parallel()
+
+	rules, counters := parseNatRules(iptablesSaveFilterOutput)
+	require.Empty(t, rules.connLimits)
+	require.Equal(t, 10000, rules.connMax)
+	require.Equal(t, []Counter{{Kind: KindConntrack, Rule: "10000", Packets: 3, Bytes: 180}}, counters)
+
+	// the limit of the eip left by the previous versions is reported to be replaced
+	output := "[2:120] -A CONNLIMIT -m conntrack --ctstate NEW --ctorigdst 172.18.11.2 -m connlimit --connlimit-above 100 --connlimit-mask 0 --connlimit-saddr -j DROP"
+	rules, _ = parseNatRules(output)
+	require.Equal(t, []ConnLimit{{EIP: "172.18.11.2"}}, rules.connLimits)
+}
+
+func Test_parseNatRulesPortRange(t *testing.T) {
+	t.Parallel()
+
+	output := "[1:60] -A SHARED_DNAT -d 172.18.11.3/32 -p udp -m udp --dport 10000:10100 -j DNAT --to-destination 10.0.1.6"
+	rules, _ := parseNatRules(output)
+	require.Equal(t, []DNAT{{EIP: "172.18.11.3", Protocol: "udp", ExternalPort: "10000-10100", InternalIP: "10.0.1.6", InternalPort: "10000-10100"}}, rules.dnats)
+}
+
+func Test_DNATExpand(t *testing.T) {
+	t.Parallel()
+
+	dnat := DNAT{EIP: "172.18.11.3", Protocol: "tcp", ExternalPort: "80,8000-8100,9000-9001", InternalIP: "10.0.1.6", InternalPort: "8443,8000-8100,7000-7001"}
+	dnats, err := dnat.Expand()
+	require.NoError(t, err)
+	require.Len(t, dnats, 4)
+	require.Equal(t, []string{
+		"172.18.11.3,80,tcp,10.0.1.6,8443",
+		"172.18.11.3,8000-8100,tcp,10.0.1.6,8000-8100",
+		"172.18.11.3,9000,tcp,10.0.1.6,7000",
+		"172.18.11.3,9001,tcp,10.0.1.6,7001",
+	}, []string{dnats[0].Rule(), dnats[1].Rule(), dnats[2].Rule(), dnats[3].Rule()})
+
+	dnat.InternalPort = "8080"
+	_, err = dnat.Expand()
+	require.Error(t, err)
+}
+
+func Test_parseAddresses(t *testing.T) {
+	t.Parallel()
+
+	output := `3: net1    inet 172.18.0.2/16 brd 172.18.255.255 scope global net1\       valid_lft forever preferred_lft forever
+3: net1    inet 172.18.11.2/16 scope global secondary net1\       valid_lft forever preferred_lft forever`
+	eips, primary := parseAddresses(output)
+	require.Equal(t, "172.18.0.2/16", primary)
+	require.Equal(t, []EIP{{Address: "172.18.0.2/16"}, {Address: "172.18.11.2/16"}}, eips)
+
+	output = `3: net1    inet6 fc00:f853:ccd:e793::2/64 scope global \       valid_lft forever preferred_lft forever
+3: net1    inet6 fc00:f853:ccd:e793::11/64 scope global \       valid_lft forever preferred_lft forever`
+	eips, primary = parseAddresses(output)
+	require.Equal(t, "fc00:f853:ccd:e793::2/64", primary)
+	require.Equal(t, []EIP{{Address: "fc00:f853:ccd:e793::2/64"}, {Address: "fc00:f853:ccd:e793::11/64"}}, eips)
+}
+
+func Test_parseQoS(t *testing.T) {
+	t.Parallel()
+
+	output := `filter protocol ip pref 1 u32 chain 0
+filter protocol ip pref 1 u32 chain 0 fh 800: ht divisor 1
+filter protocol ip pref 1 u32 chain 0 fh 800::800 order 2048 key ht 800 bkt 0 *flowid :1 not_in_hw
+  match IP dst 172.18.11.2/32
+ police 0x1 rate 10Mbit burst 10Mb mtu 2Kb action drop overhead 0b linklayer ethernet
+        ref 1 bind 1  installed 47118 sec used 47118 sec firstused 18113444 sec
+
+ Sent 0 bytes 0 pkts (dropped 0, overlimits 0)
+filter protocol ip pref 2 u32 chain 0
+filter protocol ip pref 2 u32 chain 0 fh 801: ht divisor 1
+filter protocol ip pref 2 u32 chain 0 fh 801::800 order 2048 key ht 801 bkt 0 *flowid :1 not_in_hw
+  match IP dst 172.18.11.3/32
+ police 0x2 rate 1Gbit burst 512Kb mtu 2Kb action drop overhead 0b linklayer ethernet`
+
+	qos := parseQoS(output, QoSIngress)
+	require.Equal(t, []EIPQoS{
+		{EIP: "172.18.11.2", Direction: QoSIngress, Priority: 1, Rate: "10Mbit", Burst: "10Mb"},
+		{EIP: "172.18.11.3", Direction: QoSIngress, Priority: 2, Rate: "1Gbit", Burst: "512Kb"},
+	}, qos)
+	require.Empty(t, parseQoS(output, QoSEgress))
+}
+
+func Test_qosEqual(t *testing.T) {
+	t.Parallel()
+
+	desired := EIPQoS{EIP: "172.18.11.3", Direction: QoSIngress, Priority: 2, Rate: "1000", Burst: "0.5"}
+	require.True(t, qosEqual(desired, EIPQoS{Priority: 2, Rate: "1Gbit", Burst: "512Kb"}))
+	require.False(t, qosEqual(desired, EIPQoS{Priority: 2, Rate: "100Mbit", Burst: "512Kb"}))
+	require.False(t, qosEqual(desired, EIPQoS{Priority: 2, Rate: "1Gbit", Burst: "1Mb"}))
+	require.False(t, qosEqual(desired, EIPQoS{Priority: 1, Rate: "1Gbit", Burst: "512Kb"}))
+	require.False(t, qosEqual(desired, EIPQoS{Priority: 2, Rate: "invalid", Burst: "512Kb"}))
+}
+
+func Test_normalizeCIDR(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "10.0.1.5/32", normalizeCIDR("10.0.1.5"))
+	require.Equal(t, "fd00::5/128", normalizeCIDR("fd00::5"))
+	require.Equal(t, "10.0.1.0/24", normalizeCIDR("10.0.1.10/24"))
+}
@@ -0,0 +1,359 @@
+package natgw
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+const (
+	// DefaultPort is the port the nat gateway agent listens on
+	DefaultPort = 10680
+
+	APIPrefix = "/api/v1"
+	// RulesPath executes the incremental rule operations of the nat gateway
+	RulesPath = APIPrefix + "/rules"
+	// StatePath reads or synchronizes the full state of the nat gateway
+	StatePath = APIPrefix + "/state"
+	// HealthzPath is the health check path of the agent
+	HealthzPath = "/healthz"
+)
+
+const (
+	KindEIP        = "eip"
+	KindFloatingIP = "floating-ip"
+	KindDNAT       = "dnat"
+	KindSNAT       = "snat"
+	KindQoS        = "qos"
+	KindHairpin    = "hairpin"
+	KindConntrack  = "conntrack"
+	KindConnLimit  = "connlimit"
+
+	DriftMissing    = "missing"
+	DriftUnexpected = "unexpected"
+	DriftMismatched = "mismatched"
+
+	QoSIngress = "ingress"
+	QoSEgress  = "egress"
+)
+
+// EIP is an external ip address configured on the external interface of the nat gateway
+type EIP struct {
+	// Address is the eip with prefix length, e.g. 172.18.0.10/16
+	Address string `json:"address"`
+	Gateway string `json:"gateway,omitempty"`
+}
+
+type FloatingIP struct {
+	EIP        string `json:"eip"`
+	InternalIP string `json:"internalIP"`
+}
+
+type DNAT struct {
+	EIP          string `json:"eip"`
+	Protocol     string `json:"protocol"`
+	ExternalPort string `json:"externalPort"`
+	InternalIP   string `json:"internalIP"`
+	InternalPort string `json:"internalPort"`
+}
+
+type SNAT struct {
+	EIP          string `json:"eip"`
+	InternalCIDR string `json:"internalCIDR"`
+}
+
+// Hairpin enables the connections from the vpc to the eip to be translated by
+// the dnat and floating ip rules and return through the nat gateway
+type Hairpin struct {
+	EIP string `json:"eip"`
+}
+
+// Conntrack is the connection tracking settings of the nat gateway, the timeouts are in seconds and
+// the zero ones are not managed, and Max limits the concurrent connections forwarded by the nat gateway
+// since nf_conntrack_max is read only in the network namespace of the pod
+type Conntrack struct {
+	Max                   int `json:"max,omitempty"`
+	TCPEstablishedTimeout int `json:"tcpEstablishedTimeout,omitempty"`
+	TCPTimeWaitTimeout    int `json:"tcpTimeWaitTimeout,omitempty"`
+	UDPTimeout            int `json:"udpTimeout,omitempty"`
+	UDPStreamTimeout      int `json:"udpStreamTimeout,omitempty"`
+}
+
+// ConnLimit limits the concurrent connections to the eip and the ones snated to it
+type ConnLimit struct {
+	EIP   string `json:"eip"`
+	Limit int    `json:"limit"`
+}
+
+// EIPQoS is the bandwidth limit of an eip, the rate is in Mbit/s and the burst is in MBytes
+type EIPQoS struct {
+	EIP       string `json:"eip"`
+	Direction string `json:"direction"`
+	Priority  int    `json:"priority"`
+	Rate      string `json:"rate"`
+	Burst     string `json:"burst"`
+}
+
+// State is the full state of the rules managed in the nat gateway
+type State struct {
+	EIPs        []EIP        `json:"eips,omitempty"`
+	FloatingIPs []FloatingIP `json:"floatingIPs,omitempty"`
+	DNATs       []DNAT       `json:"dnats,omitempty"`
+	SNATs       []SNAT       `json:"snats,omitempty"`
+	QoS         []EIPQoS     `json:"qos,omitempty"`
+	Hairpins    []Hairpin    `json:"hairpins,omitempty"`
+	Conntrack   *Conntrack   `json:"conntrack,omitempty"`
+	ConnLimits  []ConnLimit  `json:"connLimits,omitempty"`
+}
+
+// Operation is an incremental operation of the nat gateway rules executed by nat-gateway.sh
+type Operation string
+
+const (
+	OpInit              Operation = "init"
+	OpSubnetRouteAdd    Operation = "subnet-route-add"
+	OpSubnetRouteDel    Operation = "subnet-route-del"
+	OpExtSubnetRouteAdd Operation = "ext-subnet-route-add"
+	OpExtSubnetRouteDel Operation = "ext-subnet-route-del"
+	OpEipAdd            Operation = "eip-add"
+	OpEipDel            Operation = "eip-del"
+	OpFipAdd            Operation = "floating-ip-add"
+	OpFipDel            Operation = "floating-ip-del"
+	OpDnatAdd           Operation = "dnat-add"
+	OpDnatDel           Operation = "dnat-del"
+	OpSnatAdd           Operation = "snat-add"
+	OpSnatDel           Operation = "snat-del"
+	OpHairpinAdd        Operation = "hairpin-add"
+	OpHairpinDel        Operation = "hairpin-del"
+	OpConntrackSet      Operation = "conntrack-set"
+	OpConnLimitAdd      Operation = "connlimit-add"
+	OpConnLimitDel      Operation = "connlimit-del"
+	OpHAInit            Operation = "ha-init"
+	OpHAActive          Operation = "ha-active"
+	OpHAStandby         Operation = "ha-standby"
+	OpIptablesVersion   Operation = "get-iptables-version"
+	OpIngressQoSAdd     Operation = "eip-ingress-qos-add"
+	OpIngressQoSDel     Operation = "eip-ingress-qos-del"
+	OpEgressQoSAdd      Operation = "eip-egress-qos-add"
+	OpEgressQoSDel      Operation = "eip-egress-qos-del"
+	OpQoSAdd            Operation = "qos-add"
+	OpQoSDel            Operation = "qos-del"
+)
+
+// operationFields are the least comma separated fields of each rule of the operations,
+// the operations with zero fields take no rules
+var operationFields = map[Operation]int{
+	OpInit:              2,
+	OpSubnetRouteAdd:    2,
+	OpSubnetRouteDel:    1,
+	OpExtSubnetRouteAdd: 2,
+	OpExtSubnetRouteDel: 1,
+	OpEipAdd:            2,
+	OpEipDel:            1,
+	OpFipAdd:            2,
+	OpFipDel:            2,
+	OpDnatAdd:           5,
+	OpDnatDel:           5,
+	OpSnatAdd:           2,
+	OpSnatDel:           2,
+	OpHairpinAdd:        1,
+	OpHairpinDel:        1,
+	OpConntrackSet:      5,
+	OpConnLimitAdd:      2,
+	OpConnLimitDel:      1,
+	OpHAInit:            2,
+	OpHAActive:          1,
+	OpHAStandby:         0,
+	OpIptablesVersion:   0,
+	OpIngressQoSAdd:     4,
+	OpIngressQoSDel:     1,
+	OpEgressQoSAdd:      4,
+	OpEgressQoSDel:      1,
+	OpQoSAdd:            9,
+	OpQoSDel:            9,
+}
+
+// RuleRequest is an incremental operation of the nat gateway rules,
+// the operation and rules are the same as the arguments of nat-gateway.sh
+type RuleRequest struct {
+	Operation Operation `json:"operation"`
+	Rules     []string  `json:"rules,omitempty"`
+}
+
+// Validate checks that the operation is supported and the rules are well formed,
+// the rules are passed to nat-gateway.sh as arguments and must not contain any whitespace
+func (r *RuleRequest) Validate() error {
+	fields, ok := operationFields[r.Operation]
+	if !ok {
+		return fmt.Errorf("unsupported operation %q", r.Operation)
+	}
+	if fields == 0 && len(r.Rules) != 0 {
+		return fmt.Errorf("operation %s takes no rules", r.Operation)
+	}
+	for _, rule := range r.Rules {
+		if rule == "" || strings.ContainsFunc(rule, unicode.IsSpace) {
+			return fmt.Errorf("invalid rule %q of operation %s", rule, r.Operation)
+		}
+		if n := len(strings.Split(rule, ",")); n < fields {
+			return fmt.Errorf("rule %q of operation %s has %d fields, at least %d expected", rule, r.Operation, n, fields)
+		}
+	}
+	return nil
+}
+
+type RuleResponse struct {
+	Output string `json:"output,omitempty"`
+}
+
+// SyncRequest synchronizes the nat gateway to the desired state,
+// the drift is reported but not corrected if DryRun is set
+type SyncRequest struct {
+	State  State `json:"state"`
+	DryRun bool  `json:"dryRun,omitempty"`
+}
+
+// Drift is a difference between the desired and the actual state
+type Drift struct {
+	Kind   string `json:"kind"`
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+}
+
+func (d Drift) String() string {
+	return fmt.Sprintf("%s %s %s", d.Reason, d.Kind, d.Rule)
+}
+
+// Counter is the statistics of a nat rule, note that the rules in the nat table
+// are only matched by the first packet of each connection
+type Counter struct {
+	Kind    string `json:"kind"`
+	Rule    string `json:"rule"`
+	Packets uint64 `json:"packets"`
+	Bytes   uint64 `json:"bytes"`
+}
+
+// ConntrackStats is the usage of the conntrack table in the nat gateway
+type ConntrackStats struct {
+	Entries uint64 `json:"entries"`
+	// Max is the size of the conntrack table shared with the host
+	Max uint64 `json:"max"`
+	// Drops are the packets dropped by the kernel since the conntrack entries failed to be created
+	Drops uint64 `json:"drops"`
+	// LimitDrops are the packets of the new connections dropped by the max of the nat gateway
+	LimitDrops uint64              `json:"limitDrops"`
+	EIPs       []EIPConntrackStats `json:"eips,omitempty"`
+}
+
+// EIPConntrackStats is the conntrack entries of the connections to or translated to the eip
+type EIPConntrackStats struct {
+	EIP     string `json:"eip"`
+	Entries uint64 `json:"entries"`
+	// LimitDrops are the packets of the new connections dropped by the connection limit of the eip
+	LimitDrops uint64 `json:"limitDrops"`
+}
+
+type StateResponse struct {
+	State     State           `json:"state"`
+	Counters  []Counter       `json:"counters,omitempty"`
+	Conntrack *ConntrackStats `json:"conntrack,omitempty"`
+}
+
+type SyncResponse struct {
+	Drifts    []Drift         `json:"drifts,omitempty"`
+	Counters  []Counter       `json:"counters,omitempty"`
+	Conntrack *ConntrackStats `json:"conntrack,omitempty"`
+}
+
+type ErrorResponse struct {
+	Error  string `json:"error"`
+	Output string `json:"output,omitempty"`
+}
+
+// the rules are formatted in the same way as the arguments of nat-gateway.sh
+
+func (r EIP) Rule() string {
+	return fmt.Sprintf("%s,%s", r.Address, r.Gateway)
+}
+
+func (r FloatingIP) Rule() string {
+	return fmt.Sprintf("%s,%s", r.EIP, r.InternalIP)
+}
+
+func (r DNAT) Rule() string {
+	return fmt.Sprintf("%s,%s,%s,%s,%s", r.EIP, r.ExternalPort, r.Protocol, r.InternalIP, r.InternalPort)
+}
+
+// Expand splits a dnat with lists of ports and port ranges into the rules of the nat gateway,
+// a port range mapped to the same internal port range is kept in one rule which preserves the
+// destination port, and the other port ranges are mapped one by one with rules of single ports
+func (r DNAT) Expand() ([]DNAT, error) {
+	mappings, err := util.ParsePortMappings(r.ExternalPort, r.InternalPort)
+	if err != nil {
+		return nil, err
+	}
+	var dnats []DNAT
+	for _, mapping := range mappings {
+		ports := []util.PortMapping{mapping}
+		if mapping.External != mapping.Internal {
+			ports = mapping.Ports()
+		}
+		for _, port := range ports {
+			dnat := r
+			dnat.ExternalPort, dnat.InternalPort = port.External.String(), port.Internal.String()
+			dnats = append(dnats, dnat)
+		}
+	}
+	return dnats, nil
+}
+
+func (r SNAT) Rule() string {
+	return fmt.Sprintf("%s,%s", r.EIP, r.InternalCIDR)
+}
+
+func (r Hairpin) Rule() string {
+	return r.EIP
+}
+
+func (r Conntrack) Rule() string {
+	return fmt.Sprintf("%d,%d,%d,%d,%d", r.Max, r.TCPEstablishedTimeout, r.TCPTimeWaitTimeout, r.UDPTimeout, r.UDPStreamTimeout)
+}
+
+func (r ConnLimit) Rule() string {
+	return fmt.Sprintf("%s,%d", r.EIP, r.Limit)
+}
+
+func (r EIPQoS) Rule() string {
+	return fmt.Sprintf("%s,%d,%s,%s", r.EIP, r.Priority, r.Rate, r.Burst)
+}
+
+// the keys identify the rules when comparing the desired and the actual state
+
+func (r EIP) key() string {
+	return ipOf(r.Address)
+}
+
+func (r FloatingIP) key() string {
+	return fmt.Sprintf("%s,%s", ipOf(r.EIP), ipOf(r.InternalIP))
+}
+
+func (r DNAT) key() string {
+	return fmt.Sprintf("%s,%s,%s,%s,%s", ipOf(r.EIP), r.ExternalPort, strings.ToLower(r.Protocol), ipOf(r.InternalIP), r.InternalPort)
+}
+
+func (r SNAT) key() string {
+	return fmt.Sprintf("%s,%s", ipOf(r.EIP), normalizeCIDR(r.InternalCIDR))
+}
+
+func (r Hairpin) key() string {
+	return ipOf(r.EIP)
+}
+
+func (r ConnLimit) key() string {
+	return ipOf(r.EIP)
+}
+
+func (r EIPQoS) key() string {
+	return fmt.Sprintf("%s,%s", r.Direction, ipOf(r.EIP))
+}
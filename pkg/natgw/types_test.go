@@ -0,0 +1,40 @@
+package natgw
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleRequestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		req     RuleRequest
+		wantErr bool
+	}{
+		{"eip add", RuleRequest{Operation: OpEipAdd, Rules: []string{"172.18.0.10/16,172.18.0.1"}}, false},
+		{"ipv6 eip add without gateway", RuleRequest{Operation: OpEipAdd, Rules: []string{"fc00::10/64,"}}, false},
+		{"snat add with random fully", RuleRequest{Operation: OpSnatAdd, Rules: []string{"172.18.0.10,10.0.1.0/24,--random-fully"}}, false},
+		{"ha active without rules", RuleRequest{Operation: OpHAActive}, false},
+		{"ha active with ignored eips", RuleRequest{Operation: OpHAActive, Rules: []string{"172.18.0.10", "172.18.0.11"}}, false},
+		{"iptables version", RuleRequest{Operation: OpIptablesVersion}, false},
+		{"unknown operation", RuleRequest{Operation: "rm -rf", Rules: []string{"/"}}, true},
+		{"empty operation", RuleRequest{}, true},
+		{"too few fields", RuleRequest{Operation: OpDnatAdd, Rules: []string{"172.18.0.10,80,tcp"}}, true},
+		{"empty rule", RuleRequest{Operation: OpEipDel, Rules: []string{""}}, true},
+		{"rule with whitespace", RuleRequest{Operation: OpEipDel, Rules: []string{"172.18.0.10 ; reboot"}}, true},
+		{"rules of operation without rules", RuleRequest{Operation: OpHAStandby, Rules: []string{"172.18.0.10"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
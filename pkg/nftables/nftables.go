@@ -0,0 +1,240 @@
+package nftables
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	FamilyIP   = "ip"
+	FamilyIP6  = "ip6"
+	FamilyInet = "inet"
+
+	TypeIPv4Addr    = "ipv4_addr"
+	TypeIPv6Addr    = "ipv6_addr"
+	TypeInetProto   = "inet_proto"
+	TypeInetService = "inet_service"
+
+	ChainTypeFilter = "filter"
+	ChainTypeNat    = "nat"
+	ChainTypeRoute  = "route"
+
+	HookPrerouting  = "prerouting"
+	HookInput       = "input"
+	HookForward     = "forward"
+	HookOutput      = "output"
+	HookPostrouting = "postrouting"
+
+	PriorityDstNat = "dstnat"
+	PrioritySrcNat = "srcnat"
+	PriorityFilter = "filter"
+	PriorityMangle = "mangle"
+)
+
+// Set is a named set of addresses, it takes the place of an ipset
+type Set struct {
+	Name string
+	Type string
+	// Interval allows the set to contain cidrs, overlapped elements are merged
+	Interval bool
+	Elements []string
+}
+
+// Chain is a regular chain, or a base chain attached to a netfilter hook if Hook is set
+type Chain struct {
+	Name     string
+	Type     string
+	Hook     string
+	Priority string
+	Rules    []string
+}
+
+// AddRule appends a rule to the chain
+func (c *Chain) AddRule(format string, a ...interface{}) {
+	c.Rules = append(c.Rules, fmt.Sprintf(format, a...))
+}
+
+// Table is the desired content of an nftables table
+type Table struct {
+	Family string
+	Name   string
+	Sets   []*Set
+	Chains []*Chain
+}
+
+func NewTable(family, name string) *Table {
+	return &Table{Family: family, Name: name}
+}
+
+func (t *Table) AddSet(name, setType string, interval bool, elements []string) *Set {
+	set := &Set{Name: name, Type: setType, Interval: interval, Elements: elements}
+	t.Sets = append(t.Sets, set)
+	return set
+}
+
+// AddChain adds a regular chain, a chain must be added before the chains jumping to it
+func (t *Table) AddChain(name string) *Chain {
+	chain := &Chain{Name: name}
+	t.Chains = append(t.Chains, chain)
+	return chain
+}
+
+// AddBaseChain adds a chain attached to the netfilter hook with the policy accept
+func (t *Table) AddBaseChain(name, chainType, hook, priority string) *Chain {
+	chain := &Chain{Name: name, Type: chainType, Hook: hook, Priority: priority}
+	t.Chains = append(t.Chains, chain)
+	return chain
+}
+
+// Script returns the nft script replacing the table in a single transaction, the table
+// is declared before the deletion so that the deletion does not fail if it does not exist
+func (t *Table) Script() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "table %s %s\n", t.Family, t.Name)
+	fmt.Fprintf(&b, "delete table %s %s\n", t.Family, t.Name)
+	fmt.Fprintf(&b, "table %s %s {\n", t.Family, t.Name)
+	for _, set := range t.Sets {
+		fmt.Fprintf(&b, "\tset %s {\n", set.Name)
+		fmt.Fprintf(&b, "\t\ttype %s\n", set.Type)
+		if set.Interval {
+			b.WriteString("\t\tflags interval\n")
+			b.WriteString("\t\tauto-merge\n")
+		}
+		if len(set.Elements) != 0 {
+			fmt.Fprintf(&b, "\t\telements = { %s }\n", strings.Join(set.Elements, ", "))
+		}
+		b.WriteString("\t}\n")
+	}
+	for _, chain := range t.Chains {
+		fmt.Fprintf(&b, "\tchain %s {\n", chain.Name)
+		if chain.Hook != "" {
+			fmt.Fprintf(&b, "\t\ttype %s hook %s priority %s; policy accept;\n", chain.Type, chain.Hook, chain.Priority)
+		}
+		for _, rule := range chain.Rules {
+			fmt.Fprintf(&b, "\t\t%s\n", rule)
+		}
+		b.WriteString("\t}\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Runner runs nft with the script read from stdin if it is not empty
+type Runner func(stdin string, args ...string) (string, error)
+
+func execRunner(stdin string, args ...string) (string, error) {
+	cmd := exec.Command("nft", args...) // #nosec G204
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	output, err := cmd.CombinedOutput()
+	return strings.TrimSpace(string(output)), err
+}
+
+// Interface programs nftables tables
+type Interface interface {
+	// Replace replaces the tables atomically, the rules are never absent during the replacement
+	Replace(tables ...*Table) error
+	// Delete deletes the table if it exists
+	Delete(family, name string) error
+	// List returns the table printed by nft, the returned bool is false if the table does not exist
+	List(family, name string) (string, bool, error)
+}
+
+type nftables struct {
+	run Runner
+}
+
+func New() Interface {
+	return &nftables{run: execRunner}
+}
+
+// NewWithRunner returns an Interface running nft with the runner, it is used by the tests
+func NewWithRunner(run Runner) Interface {
+	return &nftables{run: run}
+}
+
+func (n *nftables) Replace(tables ...*Table) error {
+	var b strings.Builder
+	for _, t := range tables {
+		b.WriteString(t.Script())
+	}
+	if output, err := n.run(b.String(), "-f", "-"); err != nil {
+		klog.Errorf("failed to replace nftables tables: %v, output: %s", err, output)
+		return fmt.Errorf("failed to replace nftables tables: %w, output: %s", err, output)
+	}
+	return nil
+}
+
+func (n *nftables) Delete(family, name string) error {
+	script := fmt.Sprintf("table %s %s\ndelete table %s %s\n", family, name, family, name)
+	if output, err := n.run(script, "-f", "-"); err != nil {
+		klog.Errorf("failed to delete nftables table %s %s: %v, output: %s", family, name, err, output)
+		return fmt.Errorf("failed to delete nftables table %s %s: %w, output: %s", family, name, err, output)
+	}
+	return nil
+}
+
+func (n *nftables) List(family, name string) (string, bool, error) {
+	output, err := n.run("", "list", "table", family, name)
+	if err != nil {
+		if strings.Contains(output, "No such file or directory") {
+			return "", false, nil
+		}
+		klog.Errorf("failed to list nftables table %s %s: %v, output: %s", family, name, err, output)
+		return "", false, fmt.Errorf("failed to list nftables table %s %s: %w, output: %s", family, name, err, output)
+	}
+	return output, true, nil
+}
+
+// Rule is a commented rule parsed from the output of nft list
+type Rule struct {
+	Chain   string
+	Rule    string
+	Comment string
+	Packets uint64
+	Bytes   uint64
+}
+
+var (
+	chainRegexp   = regexp.MustCompile(`^chain (\S+) \{`)
+	counterRegexp = regexp.MustCompile(`counter packets (\d+) bytes (\d+)`)
+	commentRegexp = regexp.MustCompile(`comment "([^"]*)"`)
+)
+
+// ParseRules parses the rules with comments from the output of nft list,
+// the comments are used to identify the rules and the counters are returned along with them
+func ParseRules(output string) []Rule {
+	var rules []Rule
+	var chain string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if match := chainRegexp.FindStringSubmatch(line); match != nil {
+			chain = match[1]
+			continue
+		}
+		match := commentRegexp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		rule := Rule{Chain: chain, Rule: line, Comment: match[1]}
+		if match = counterRegexp.FindStringSubmatch(line); match != nil {
+			rule.Packets, _ = strconv.ParseUint(match[1], 10, 64)
+			rule.Bytes, _ = strconv.ParseUint(match[2], 10, 64)
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// Quote quotes a string used as an interface name or a comment
+func Quote(s string) string {
+	return strconv.Quote(s)
+}
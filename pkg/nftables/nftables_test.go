@@ -0,0 +1,100 @@
+package nftables
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableScript(t *testing.T) {
+	t.Parallel()
+
+	table := NewTable(FamilyIP, "kube-ovn")
+	table.AddSet("ovn40subnets", TypeIPv4Addr, true, []string{"10.16.0.0/16", "100.64.0.0/16"})
+	table.AddSet("ovn40local-pod-ip-nat", TypeIPv4Addr, false, nil)
+	masquerade := table.AddChain("ovn-masquerade")
+	masquerade.AddRule("meta mark set 0x0")
+	masquerade.AddRule("masquerade fully-random")
+	postrouting := table.AddBaseChain("ovn-postrouting", ChainTypeNat, HookPostrouting, PrioritySrcNat)
+	postrouting.AddRule("ip saddr @%s ip daddr != @%s jump %s", "ovn40subnets", "ovn40subnets", masquerade.Name)
+
+	expected := `table ip kube-ovn
+delete table ip kube-ovn
+table ip kube-ovn {
+	set ovn40subnets {
+		type ipv4_addr
+		flags interval
+		auto-merge
+		elements = { 10.16.0.0/16, 100.64.0.0/16 }
+	}
+	set ovn40local-pod-ip-nat {
+		type ipv4_addr
+	}
+	chain ovn-masquerade {
+		meta mark set 0x0
+		masquerade fully-random
+	}
+	chain ovn-postrouting {
+		type nat hook postrouting priority srcnat; policy accept;
+		ip saddr @ovn40subnets ip daddr != @ovn40subnets jump ovn-masquerade
+	}
+}
+`
+	require.Equal(t, expected, table.Script())
+}
+
+func TestReplace(t *testing.T) {
+	t.Parallel()
+
+	var stdin string
+	var args []string
+	nft := NewWithRunner(func(s string, a ...string) (string, error) {
+		stdin, args = s, a
+		return "", nil
+	})
+	v4 := NewTable(FamilyIP, "kube-ovn")
+	v6 := NewTable(FamilyIP6, "kube-ovn")
+	require.NoError(t, nft.Replace(v4, v6))
+	require.Equal(t, []string{"-f", "-"}, args)
+	require.Equal(t, v4.Script()+v6.Script(), stdin)
+}
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	nft := NewWithRunner(func(_ string, _ ...string) (string, error) {
+		return "Error: No such file or directory\nlist table ip kube-ovn\n                 ^^^^^^^^", errors.New("exit status 1")
+	})
+	output, exists, err := nft.List(FamilyIP, "kube-ovn")
+	require.NoError(t, err)
+	require.False(t, exists)
+	require.Empty(t, output)
+
+	nft = NewWithRunner(func(_ string, _ ...string) (string, error) {
+		return "Error: Could not process rule: Operation not permitted", errors.New("exit status 1")
+	})
+	_, _, err = nft.List(FamilyIP, "kube-ovn")
+	require.Error(t, err)
+}
+
+func TestParseRules(t *testing.T) {
+	t.Parallel()
+
+	output := `table ip kube-ovn {
+	chain ovn-forward {
+		type filter hook forward priority filter; policy accept;
+		ip saddr @ovn40subnets accept
+		ip saddr 10.16.0.0/16 counter packets 12 bytes 1008 comment "ovn-subnet-gateway,ovn-default"
+		ip daddr 10.16.0.0/16 counter packets 0 bytes 0 comment "ovn-subnet-gateway,ovn-default"
+	}
+	chain ovn-nat-policy {
+		ip saddr 10.0.1.0/24 jump ovn-nat-psn-1a2b3c comment "natPolicySubnet-subnet1"
+	}
+}`
+	require.Equal(t, []Rule{
+		{Chain: "ovn-forward", Rule: `ip saddr 10.16.0.0/16 counter packets 12 bytes 1008 comment "ovn-subnet-gateway,ovn-default"`, Comment: "ovn-subnet-gateway,ovn-default", Packets: 12, Bytes: 1008},
+		{Chain: "ovn-forward", Rule: `ip daddr 10.16.0.0/16 counter packets 0 bytes 0 comment "ovn-subnet-gateway,ovn-default"`, Comment: "ovn-subnet-gateway,ovn-default"},
+		{Chain: "ovn-nat-policy", Rule: `ip saddr 10.0.1.0/24 jump ovn-nat-psn-1a2b3c comment "natPolicySubnet-subnet1"`, Comment: "natPolicySubnet-subnet1"},
+	}, ParseRules(output))
+}
@@ -36,13 +36,19 @@ type Controller struct {
 	vpcsLister       kubeovnlister.VpcLister
 	vpcSynced        cache.InformerSynced
 
+	interConnectionsLister kubeovnlister.InterConnectionLister
+	interConnectionSynced  cache.InformerSynced
+
 	informerFactory        kubeinformers.SharedInformerFactory
 	kubeovnInformerFactory kubeovninformer.SharedInformerFactory
 	recorder               record.EventRecorder
 
-	ovnLegacyClient *ovs.LegacyClient
-	OVNNbClient     ovs.NbClient
-	OVNSbClient     ovs.SbClient
+	OVNNbClient   ovs.NbClient
+	OVNSbClient   ovs.SbClient
+	OVNIcNBClient ovs.IcNbClient
+	OVNIcSBClient ovs.IcSbClient
+	icNbAddress   string
+	icSbAddress   string
 }
 
 func NewController(config *Configuration) *Controller {
@@ -66,6 +72,7 @@ func NewController(config *Configuration) *Controller {
 	nodeInformer := informerFactory.Core().V1().Nodes()
 	subnetInformer := kubeovnInformerFactory.Kubeovn().V1().Subnets()
 	configMapInformer := informerFactory.Core().V1().ConfigMaps()
+	interConnectionInformer := kubeovnInformerFactory.Kubeovn().V1().InterConnections()
 
 	controller := &Controller{
 		config: config,
@@ -79,11 +86,12 @@ func NewController(config *Configuration) *Controller {
 		configMapsLister: configMapInformer.Lister(),
 		configMapsSynced: configMapInformer.Informer().HasSynced,
 
+		interConnectionsLister: interConnectionInformer.Lister(),
+		interConnectionSynced:  interConnectionInformer.Informer().HasSynced,
+
 		informerFactory:        informerFactory,
 		kubeovnInformerFactory: kubeovnInformerFactory,
 		recorder:               recorder,
-
-		ovnLegacyClient: ovs.NewLegacyClient(config.OvnTimeout),
 	}
 
 	var err error
@@ -102,7 +110,7 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 	c.informerFactory.Start(stopCh)
 	c.kubeovnInformerFactory.Start(stopCh)
 
-	if !cache.WaitForCacheSync(stopCh, c.subnetSynced, c.nodesSynced) {
+	if !cache.WaitForCacheSync(stopCh, c.subnetSynced, c.nodesSynced, c.vpcSynced, c.interConnectionSynced) {
 		util.LogFatalAndExit(nil, "failed to wait for caches to sync")
 		return
 	}
@@ -110,6 +118,9 @@ func (c *Controller) Run(stopCh <-chan struct{}) {
 	klog.Info("Started workers")
 	go wait.Until(c.resyncInterConnection, time.Second, stopCh)
 	go wait.Until(c.SynRouteToPolicy, 5*time.Second, stopCh)
+	go wait.Until(c.syncVpcICPeerings, 5*time.Second, stopCh)
+	go wait.Until(c.syncICRoutes, 5*time.Second, stopCh)
+	go wait.Until(c.syncInterConnectionStatus, 5*time.Second, stopCh)
 	<-stopCh
 	klog.Info("Shutting down workers")
 }
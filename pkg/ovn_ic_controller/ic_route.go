@@ -0,0 +1,408 @@
+package ovn_ic_controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/scylladb/go-set/strset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	ovsclient "github.com/kubeovn/kube-ovn/pkg/ovsdb/client"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+const (
+	// icRouteImportPrefix is the prefix of the ovn-ic-config keys holding the cidrs
+	// allowed to be learned from the az, e.g. route-import.az1: 10.16.0.0/16,10.17.0.0/16
+	icRouteImportPrefix = "route-import."
+	// icRouteTablePrefix is the prefix of the ovn-ic-config keys holding the route table
+	// of the routes learned from the az, e.g. route-table.az1: rtb1
+	icRouteTablePrefix = "route-table."
+)
+
+// icRouteFilter is the route import policy of the remote azs
+type icRouteFilter struct {
+	imports map[string][]netip.Prefix
+	tables  map[string]string
+}
+
+func isICRouteFilterKey(key string) bool {
+	return strings.HasPrefix(key, icRouteImportPrefix) || strings.HasPrefix(key, icRouteTablePrefix)
+}
+
+// icConnectionConfig returns the ovn-ic-config without the route filter keys,
+// which are applied without reestablishing the interconnection
+func icConnectionConfig(config map[string]string) map[string]string {
+	if config == nil {
+		return nil
+	}
+	result := make(map[string]string, len(config))
+	for k, v := range config {
+		if !isICRouteFilterKey(k) {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func parseICRouteFilter(config map[string]string) (*icRouteFilter, error) {
+	filter := &icRouteFilter{imports: map[string][]netip.Prefix{}, tables: map[string]string{}}
+	for k, v := range config {
+		if az, ok := strings.CutPrefix(k, icRouteTablePrefix); ok {
+			filter.tables[az] = strings.TrimSpace(v)
+			continue
+		}
+		az, ok := strings.CutPrefix(k, icRouteImportPrefix)
+		if !ok {
+			continue
+		}
+		prefixes := make([]netip.Prefix, 0)
+		for _, cidr := range strings.Split(v, ",") {
+			if cidr = strings.TrimSpace(cidr); cidr == "" {
+				continue
+			}
+			prefix, err := netip.ParsePrefix(cidr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cidr %q in %s: %v", cidr, k, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+		}
+		filter.imports[az] = prefixes
+	}
+	return filter, nil
+}
+
+// enabled returns whether the learned routes are managed by kube-ovn instead of ovn-ic
+func (f *icRouteFilter) enabled() bool {
+	return len(f.imports) != 0 || len(f.tables) != 0
+}
+
+// allowed returns whether the route advertised by the az is allowed to be learned,
+// all the routes are allowed if there is no import list of the az
+func (f *icRouteFilter) allowed(az, cidr string) bool {
+	allowList, ok := f.imports[az]
+	if !ok {
+		return true
+	}
+	return prefixCoveredBy(cidr, allowList)
+}
+
+// prefixCoveredBy returns whether the cidr is within one of the prefixes
+func prefixCoveredBy(cidr string, prefixes []netip.Prefix) bool {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		addr, err := netip.ParseAddr(cidr)
+		if err != nil {
+			return false
+		}
+		prefix = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	for _, p := range prefixes {
+		if p.Addr().Is4() == prefix.Addr().Is4() && p.Bits() <= prefix.Bits() && p.Contains(prefix.Addr()) {
+			return true
+		}
+	}
+	return false
+}
+
+// icRouteBlackList returns the cidrs not advertised to the other azs
+func (c *Controller) icRouteBlackList() ([]string, error) {
+	var blackList []string
+	subnets, err := c.subnetsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list subnets, %v", err)
+		return nil, err
+	}
+	for _, subnet := range subnets {
+		if subnet.Spec.DisableInterConnection || subnet.Name == c.config.NodeSwitch || subnet.Annotations[util.ICRouteExportAnnotation] == "false" {
+			blackList = append(blackList, subnet.Spec.CIDRBlock)
+		}
+	}
+	nodes, err := c.nodesLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list node, %v", err)
+		return nil, err
+	}
+	for _, node := range nodes {
+		ipv4, ipv6 := util.GetNodeInternalIP(*node)
+		if ipv4 != "" {
+			blackList = append(blackList, ipv4)
+		}
+		if ipv6 != "" {
+			blackList = append(blackList, ipv6)
+		}
+	}
+	return blackList, nil
+}
+
+type icLearnedRoute struct {
+	Az         string `json:"az,omitempty"`
+	Prefix     string `json:"prefix"`
+	NextHop    string `json:"nextHop"`
+	RouteTable string `json:"routeTable,omitempty"`
+	origin     string
+}
+
+func (r icLearnedRoute) key() string {
+	return fmt.Sprintf("%s:%s=>%s", r.RouteTable, r.Prefix, r.NextHop)
+}
+
+type icRejectedRoute struct {
+	Az     string `json:"az"`
+	Prefix string `json:"prefix"`
+}
+
+// icRouteStatus is reflected in the annotation of ovn-ic-config or the status of InterConnection
+type icRouteStatus struct {
+	// LearnedBy is ovn-ic if the routes are learned by ovn-ic, or kube-ovn if the route filter is configured
+	LearnedBy string            `json:"learnedBy"`
+	Exported  []string          `json:"exported"`
+	Learned   []icLearnedRoute  `json:"learned,omitempty"`
+	Rejected  []icRejectedRoute `json:"rejected,omitempty"`
+}
+
+// syncICRoutes learns the routes advertised by the other azs according to the route filter in ovn-ic-config,
+// the routes are learned by ovn-ic if no route filter is configured
+func (c *Controller) syncICRoutes() {
+	if icEnabled != "true" || lastIcCm == nil || c.OVNIcSBClient == nil {
+		return
+	}
+
+	status, err := c.reconcileICRoutes(lastIcCm)
+	setICPhase(kubeovnv1.ICRoutesSynced, err)
+	if err != nil {
+		klog.Errorf("failed to sync ovn-ic routes, %v", err)
+		return
+	}
+	setICRouteStatus(status)
+
+	// the route status is reflected in the status of InterConnection if it is used instead of ovn-ic-config
+	if ic, err := c.getInterConnection(); err != nil || ic != nil {
+		return
+	}
+	if err = c.patchICRouteStatus(status); err != nil {
+		klog.Errorf("failed to patch ovn-ic route status, %v", err)
+	}
+}
+
+func (c *Controller) reconcileICRoutes(config map[string]string) (*icRouteStatus, error) {
+	filter, err := parseICRouteFilter(config)
+	if err != nil {
+		klog.Errorf("invalid route filter in %s, %v", util.InterconnectionConfig, err)
+		return nil, err
+	}
+	blackList, err := c.icRouteBlackList()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &icRouteStatus{LearnedBy: "ovn-ic"}
+	var learned []icLearnedRoute
+	if config["auto-route"] == "true" && filter.enabled() {
+		status.LearnedBy = util.CniTypeName
+		if learned, status.Rejected, err = c.filterICRoutes(config["az-name"], filter, blackList); err != nil {
+			klog.Errorf("failed to filter ovn-ic routes, %v", err)
+			return nil, err
+		}
+		status.Learned = learned
+	}
+	if err = c.syncICLearnedRoutes(learned); err != nil {
+		klog.Errorf("failed to sync ovn-ic learned routes, %v", err)
+		return nil, err
+	}
+	if status.LearnedBy == "ovn-ic" {
+		if status.Learned, err = c.listOVNICLearnedRoutes(); err != nil {
+			return nil, err
+		}
+	}
+
+	if status.Exported, err = c.icExportedCIDRs(config["auto-route"] == "true", blackList); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// listOVNICLearnedRoutes returns the routes learned by ovn-ic on the cluster router
+func (c *Controller) listOVNICLearnedRoutes() ([]icLearnedRoute, error) {
+	routes, err := c.OVNNbClient.ListLogicalRouterStaticRoutes(c.config.ClusterRouter, nil, nil, "", map[string]string{"ic-learned-route": ""})
+	if err != nil {
+		klog.Errorf("failed to list learned routes of logical router %s: %v", c.config.ClusterRouter, err)
+		return nil, err
+	}
+	learned := make([]icLearnedRoute, 0, len(routes))
+	for _, route := range routes {
+		learned = append(learned, icLearnedRoute{Prefix: route.IPPrefix, NextHop: route.Nexthop, RouteTable: route.RouteTable})
+	}
+	sort.Slice(learned, func(i, j int) bool { return learned[i].key() < learned[j].key() })
+	return learned, nil
+}
+
+// filterICRoutes returns the routes allowed to be learned from the other azs and the rejected ones
+func (c *Controller) filterICRoutes(localAz string, filter *icRouteFilter, blackList []string) ([]icLearnedRoute, []icRejectedRoute, error) {
+	tsNames, err := c.getTs()
+	if err != nil {
+		return nil, nil, err
+	}
+	tss := strset.New(tsNames...)
+
+	var blocked []netip.Prefix
+	for _, cidr := range blackList {
+		for _, s := range strings.Split(cidr, ",") {
+			if prefix, err := netip.ParsePrefix(s); err == nil {
+				blocked = append(blocked, prefix.Masked())
+			} else if addr, err := netip.ParseAddr(s); err == nil {
+				blocked = append(blocked, netip.PrefixFrom(addr, addr.BitLen()))
+			}
+		}
+	}
+
+	azs, err := c.OVNIcSBClient.ListAvailabilityZones()
+	if err != nil {
+		klog.Error(err)
+		return nil, nil, err
+	}
+	var learned []icLearnedRoute
+	var rejected []icRejectedRoute
+	for _, az := range azs {
+		if az.Name == localAz {
+			continue
+		}
+		routes, err := c.OVNIcSBClient.ListRoutes(az.UUID)
+		if err != nil {
+			klog.Error(err)
+			return nil, nil, err
+		}
+		for _, route := range routes {
+			// only the routes of the main route table are advertised through the transit switches of the cluster router
+			if !tss.Has(route.TransitSwitch) || route.RouteTable != util.MainRouteTable || prefixCoveredBy(route.IPPrefix, blocked) {
+				continue
+			}
+			if !filter.allowed(az.Name, route.IPPrefix) {
+				rejected = append(rejected, icRejectedRoute{Az: az.Name, Prefix: route.IPPrefix})
+				continue
+			}
+			learned = append(learned, icLearnedRoute{
+				Az:         az.Name,
+				Prefix:     route.IPPrefix,
+				NextHop:    route.Nexthop,
+				RouteTable: filter.tables[az.Name],
+				origin:     route.Origin,
+			})
+		}
+	}
+
+	sort.Slice(learned, func(i, j int) bool { return learned[i].key() < learned[j].key() })
+	sort.Slice(rejected, func(i, j int) bool {
+		return rejected[i].Az+rejected[i].Prefix < rejected[j].Az+rejected[j].Prefix
+	})
+	return learned, rejected, nil
+}
+
+// syncICLearnedRoutes makes the routes learned by kube-ovn on the cluster router match the expected ones
+func (c *Controller) syncICLearnedRoutes(expected []icLearnedRoute) error {
+	existing, err := c.OVNNbClient.ListLogicalRouterStaticRoutes(c.config.ClusterRouter, nil, nil, "", map[string]string{util.OvnICRouteAzKey: ""})
+	if err != nil {
+		klog.Errorf("failed to list learned routes of logical router %s: %v", c.config.ClusterRouter, err)
+		return err
+	}
+
+	expectedRoutes := make(map[string]icLearnedRoute, len(expected))
+	for _, route := range expected {
+		expectedRoutes[route.key()] = route
+	}
+	for _, route := range existing {
+		key := icLearnedRoute{Prefix: route.IPPrefix, NextHop: route.Nexthop, RouteTable: route.RouteTable}.key()
+		if r, ok := expectedRoutes[key]; ok && r.Az == route.ExternalIDs[util.OvnICRouteAzKey] {
+			delete(expectedRoutes, key)
+			continue
+		}
+		klog.Infof("delete ovn-ic learned route %s via %s in route table %q", route.IPPrefix, route.Nexthop, route.RouteTable)
+		policy := ovnnb.LogicalRouterStaticRoutePolicyDstIP
+		if err = c.OVNNbClient.DeleteLogicalRouterStaticRoute(c.config.ClusterRouter, &route.RouteTable, &policy, route.IPPrefix, route.Nexthop); err != nil {
+			klog.Errorf("failed to delete ovn-ic learned route %s: %v", route.IPPrefix, err)
+			return err
+		}
+	}
+
+	routes := make([]*ovnnb.LogicalRouterStaticRoute, 0, len(expectedRoutes))
+	for _, r := range expectedRoutes {
+		klog.Infof("add ovn-ic learned route %s via %s from az %s in route table %q", r.Prefix, r.NextHop, r.Az, r.RouteTable)
+		policy := ovnnb.LogicalRouterStaticRoutePolicyDstIP
+		routes = append(routes, &ovnnb.LogicalRouterStaticRoute{
+			UUID:        ovsclient.NamedUUID(),
+			Policy:      &policy,
+			IPPrefix:    r.Prefix,
+			Nexthop:     r.NextHop,
+			RouteTable:  r.RouteTable,
+			ExternalIDs: map[string]string{"vendor": util.CniTypeName, util.OvnICRouteAzKey: r.Az},
+			Options:     map[string]string{util.OvnICKey: r.origin},
+		})
+	}
+	if err = c.OVNNbClient.CreateLogicalRouterStaticRoutes(c.config.ClusterRouter, routes...); err != nil {
+		klog.Errorf("failed to add ovn-ic learned routes: %v", err)
+		return err
+	}
+	return nil
+}
+
+// icExportedCIDRs returns the cidrs of the cluster router subnets advertised to the other azs
+func (c *Controller) icExportedCIDRs(autoRoute bool, blackList []string) ([]string, error) {
+	exported := make([]string, 0)
+	if !autoRoute {
+		return exported, nil
+	}
+	subnets, err := c.subnetsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list subnets, %v", err)
+		return nil, err
+	}
+	blocked := strset.New(blackList...)
+	for _, subnet := range subnets {
+		if subnet.Spec.Vpc != c.config.ClusterRouter || subnet.Spec.CIDRBlock == "" || blocked.Has(subnet.Spec.CIDRBlock) {
+			continue
+		}
+		exported = append(exported, strings.Split(subnet.Spec.CIDRBlock, ",")...)
+	}
+	sort.Strings(exported)
+	return exported, nil
+}
+
+func (c *Controller) patchICRouteStatus(status *icRouteStatus) error {
+	cm, err := c.configMapsLister.ConfigMaps(c.config.PodNamespace).Get(util.InterconnectionConfig)
+	if err != nil {
+		klog.Errorf("failed to get %s, %v", util.InterconnectionConfig, err)
+		return err
+	}
+	value, err := json.Marshal(status)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if cm.Annotations[util.ICRouteStatusAnnotation] == string(value) {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{util.ICRouteStatusAnnotation: string(value)},
+		},
+	})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err = c.config.KubeClient.CoreV1().ConfigMaps(cm.Namespace).Patch(context.Background(), cm.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		klog.Errorf("failed to patch %s, %v", util.InterconnectionConfig, err)
+		return err
+	}
+	return nil
+}
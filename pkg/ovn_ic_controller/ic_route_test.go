@@ -0,0 +1,142 @@
+package ovn_ic_controller
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_icConnectionConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config map[string]string
+		exp    map[string]string
+	}{
+		{
+			name: "nil config",
+		},
+		{
+			name:   "without route filter keys",
+			config: map[string]string{"az-name": "az1", "ic-db-host": "192.168.0.1"},
+			exp:    map[string]string{"az-name": "az1", "ic-db-host": "192.168.0.1"},
+		},
+		{
+			name: "route filter keys removed",
+			config: map[string]string{
+				"az-name":          "az1",
+				"route-import.az2": "10.16.0.0/16",
+				"route-table.az2":  "rtb1",
+			},
+			exp: map[string]string{"az-name": "az1"},
+		},
+		{
+			name:   "only route filter keys",
+			config: map[string]string{"route-import.az2": "10.16.0.0/16"},
+			exp:    map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.exp, icConnectionConfig(tt.config))
+		})
+	}
+}
+
+func Test_parseICRouteFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		config     map[string]string
+		expImports map[string][]netip.Prefix
+		expTables  map[string]string
+		expEnabled bool
+		expErr     bool
+	}{
+		{
+			name:       "no route filter",
+			config:     map[string]string{"az-name": "az1"},
+			expImports: map[string][]netip.Prefix{},
+			expTables:  map[string]string{},
+		},
+		{
+			name: "import list and route table",
+			config: map[string]string{
+				"az-name":          "az1",
+				"route-import.az2": "10.16.0.0/16, 10.17.0.1/16,,fd00::/64",
+				"route-table.az2":  " rtb1 ",
+			},
+			expImports: map[string][]netip.Prefix{
+				"az2": {
+					netip.MustParsePrefix("10.16.0.0/16"),
+					netip.MustParsePrefix("10.17.0.0/16"),
+					netip.MustParsePrefix("fd00::/64"),
+				},
+			},
+			expTables:  map[string]string{"az2": "rtb1"},
+			expEnabled: true,
+		},
+		{
+			name:       "empty import list denies all routes",
+			config:     map[string]string{"route-import.az2": ""},
+			expImports: map[string][]netip.Prefix{"az2": {}},
+			expTables:  map[string]string{},
+			expEnabled: true,
+		},
+		{
+			name:   "invalid cidr",
+			config: map[string]string{"route-import.az2": "10.16.0.0/33"},
+			expErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			filter, err := parseICRouteFilter(tt.config)
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expImports, filter.imports)
+			require.Equal(t, tt.expTables, filter.tables)
+			require.Equal(t, tt.expEnabled, filter.enabled())
+		})
+	}
+}
+
+func Test_prefixCoveredBy(t *testing.T) {
+	t.Parallel()
+
+	prefixes := []netip.Prefix{
+		netip.MustParsePrefix("10.16.0.0/16"),
+		netip.MustParsePrefix("fd00::/64"),
+	}
+	tests := []struct {
+		name string
+		cidr string
+		exp  bool
+	}{
+		{"same prefix", "10.16.0.0/16", true},
+		{"smaller prefix", "10.16.1.0/24", true},
+		{"larger prefix", "10.0.0.0/8", false},
+		{"disjoint prefix", "10.17.0.0/16", false},
+		{"covered address", "10.16.0.1", true},
+		{"uncovered address", "10.17.0.1", false},
+		{"ipv6 prefix", "fd00::/96", true},
+		{"ipv6 address", "fd00::1", true},
+		{"uncovered ipv6 prefix", "fd01::/64", false},
+		{"invalid cidr", "foo", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.exp, prefixCoveredBy(tt.cidr, prefixes))
+		})
+	}
+	require.False(t, prefixCoveredBy("10.16.0.0/16", nil))
+}
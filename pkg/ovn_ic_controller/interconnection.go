@@ -0,0 +1,267 @@
+package ovn_ic_controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+const (
+	defaultICNBPort = 6645
+	defaultICSBPort = 6646
+)
+
+// icPhases records the result of each interconnection phase, which is reflected in the status of InterConnection
+var icPhases = struct {
+	sync.Mutex
+	results     map[string]error
+	routeStatus *icRouteStatus
+}{results: map[string]error{}}
+
+func setICPhase(ctype string, err error) {
+	icPhases.Lock()
+	defer icPhases.Unlock()
+	icPhases.results[ctype] = err
+}
+
+func resetICPhases() {
+	icPhases.Lock()
+	defer icPhases.Unlock()
+	icPhases.results = map[string]error{}
+	icPhases.routeStatus = nil
+}
+
+func setICRouteStatus(status *icRouteStatus) {
+	icPhases.Lock()
+	defer icPhases.Unlock()
+	icPhases.routeStatus = status
+}
+
+// getInterConnection returns the InterConnection not being deleted, only one is allowed by the webhook
+func (c *Controller) getInterConnection() (*kubeovnv1.InterConnection, error) {
+	ics, err := c.interConnectionsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list interconnections, %v", err)
+		return nil, err
+	}
+	sort.Slice(ics, func(i, j int) bool { return ics[i].Name < ics[j].Name })
+	for _, ic := range ics {
+		if ic.DeletionTimestamp.IsZero() {
+			return ic, nil
+		}
+	}
+	return nil, nil
+}
+
+// getICConfig returns the interconnection config, the InterConnection takes precedence over the ovn-ic-config ConfigMap,
+// nil is returned if neither of them exists. The ConfigMap is marked as superseded once the InterConnection is used,
+// so that deleting the InterConnection disables the interconnection instead of falling back to the ConfigMap
+func (c *Controller) getICConfig() (map[string]string, error) {
+	ic, err := c.getInterConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	cm, err := c.configMapsLister.ConfigMaps(c.config.PodNamespace).Get(util.InterconnectionConfig)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			klog.Errorf("failed to get ovn-ic-config, %v", err)
+			return nil, err
+		}
+		cm = nil
+	}
+
+	if ic != nil {
+		if cm != nil && cm.Annotations[util.ICSupersededAnnotation] != "true" {
+			if err = c.supersedeICConfigMap(cm.Namespace, cm.Name); err != nil {
+				return nil, err
+			}
+		}
+		return interConnectionConfig(ic), nil
+	}
+	if cm == nil || cm.Annotations[util.ICSupersededAnnotation] == "true" {
+		return nil, nil
+	}
+	return cm.Data, nil
+}
+
+// supersedeICConfigMap marks the ovn-ic-config ConfigMap as superseded by the InterConnection,
+// the annotation should be removed to use the ConfigMap again
+func (c *Controller) supersedeICConfigMap(namespace, name string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{util.ICSupersededAnnotation: "true"},
+		},
+	})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	klog.Infof("%s is superseded by the InterConnection", util.InterconnectionConfig)
+	if _, err = c.config.KubeClient.CoreV1().ConfigMaps(namespace).Patch(context.Background(), name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		klog.Errorf("failed to patch %s, %v", util.InterconnectionConfig, err)
+		return err
+	}
+	return nil
+}
+
+// interConnectionConfig converts the spec of InterConnection to the keys of ovn-ic-config
+func interConnectionConfig(ic *kubeovnv1.InterConnection) map[string]string {
+	nbPort, sbPort := ic.Spec.ICNBPort, ic.Spec.ICSBPort
+	if nbPort == 0 {
+		nbPort = defaultICNBPort
+	}
+	if sbPort == 0 {
+		sbPort = defaultICSBPort
+	}
+
+	config := map[string]string{
+		"enable-ic":  "true",
+		"az-name":    ic.Spec.AzName,
+		"ic-db-host": strings.Join(ic.Spec.ICDBHosts, ","),
+		"ic-nb-port": strconv.Itoa(int(nbPort)),
+		"ic-sb-port": strconv.Itoa(int(sbPort)),
+		"gw-nodes":   strings.Join(ic.Spec.GatewayNodes, ","),
+		"auto-route": strconv.FormatBool(ic.Spec.AutoRoute),
+	}
+	if ic.Spec.TSCount != 0 {
+		config["ts-count"] = strconv.Itoa(ic.Spec.TSCount)
+	}
+	for az, cidrs := range ic.Spec.RouteImports {
+		config[icRouteImportPrefix+az] = strings.Join(cidrs, ",")
+	}
+	for az, table := range ic.Spec.RouteTables {
+		config[icRouteTablePrefix+az] = table
+	}
+	return config
+}
+
+// checkTransitSwitches waits for the transit switches of the count in ovn-ic-config,
+// which are owned by the ovn-ic-db leader and created according to TS_NUM
+func (c *Controller) checkTransitSwitches(config map[string]string) error {
+	if config["ts-count"] == "" {
+		return nil
+	}
+	count, err := strconv.Atoi(config["ts-count"])
+	if err != nil {
+		klog.Errorf("invalid ts-count %q, %v", config["ts-count"], err)
+		return err
+	}
+
+	tsNames, err := c.getTs()
+	if err != nil {
+		return err
+	}
+	return checkTransitSwitchCount(tsNames, count)
+}
+
+func checkTransitSwitchCount(tsNames []string, count int) error {
+	if len(tsNames) < count {
+		return fmt.Errorf("%d transit switches exist while %d are expected, TS_NUM of ovn-ic-db should be %d", len(tsNames), count, count)
+	}
+	return nil
+}
+
+// setInterConnectionConditions sets the condition of each interconnection phase by the results,
+// the InterConnection is ready once all the phases succeed
+func setInterConnectionConditions(status *kubeovnv1.InterConnectionStatus, enabled bool, results map[string]error) {
+	var notReady error
+	for _, ctype := range []string{kubeovnv1.ICDBConnected, kubeovnv1.ICEstablished, kubeovnv1.ICRoutesSynced} {
+		err, ok := results[ctype]
+		switch {
+		case !ok:
+			err = errors.New("not observed")
+			status.ClearCondition(kubeovnv1.ConditionType(ctype), "Pending", err.Error())
+		case err != nil:
+			status.ClearCondition(kubeovnv1.ConditionType(ctype), "Failed", err.Error())
+		default:
+			status.SetCondition(kubeovnv1.ConditionType(ctype), "Succeeded", "")
+		}
+		if err != nil && notReady == nil {
+			notReady = err
+		}
+	}
+	switch {
+	case !enabled:
+		status.ClearCondition(kubeovnv1.Ready, "Disabled", "interconnection is not established")
+	case notReady != nil:
+		status.ClearCondition(kubeovnv1.Ready, "NotReady", notReady.Error())
+	default:
+		status.SetCondition(kubeovnv1.Ready, "Ready", "")
+	}
+}
+
+// syncInterConnectionStatus reflects the state of the interconnection in the status of InterConnection
+func (c *Controller) syncInterConnectionStatus() {
+	cachedIC, err := c.getInterConnection()
+	if err != nil || cachedIC == nil {
+		return
+	}
+	ic := cachedIC.DeepCopy()
+	status := &ic.Status
+
+	icPhases.Lock()
+	results := make(map[string]error, len(icPhases.results))
+	for k, v := range icPhases.results {
+		results[k] = v
+	}
+	routeStatus := icPhases.routeStatus
+	icPhases.Unlock()
+
+	enabled := icEnabled == "true"
+	setInterConnectionConditions(status, enabled, results)
+
+	status.GatewayChassis = nil
+	if enabled {
+		for _, gw := range ic.Spec.GatewayNodes {
+			chassis, err := c.OVNSbClient.GetChassisByHost(gw)
+			if err != nil {
+				klog.Errorf("failed to get chassis of gateway node %s, %v", gw, err)
+				continue
+			}
+			status.GatewayChassis = append(status.GatewayChassis, chassis.Name)
+		}
+	}
+
+	status.AdvertisedRoutes, status.LearnedRoutes = nil, nil
+	if enabled && routeStatus != nil {
+		if len(routeStatus.Exported) != 0 {
+			status.AdvertisedRoutes = routeStatus.Exported
+		}
+		for _, route := range routeStatus.Learned {
+			status.LearnedRoutes = append(status.LearnedRoutes, kubeovnv1.InterConnectionRoute{
+				Az:         route.Az,
+				Prefix:     route.Prefix,
+				NextHop:    route.NextHop,
+				RouteTable: route.RouteTable,
+			})
+		}
+	}
+
+	if reflect.DeepEqual(cachedIC.Status, ic.Status) {
+		return
+	}
+	bytes, err := status.Bytes()
+	if err != nil {
+		klog.Error(err)
+		return
+	}
+	if _, err = c.config.KubeOvnClient.KubeovnV1().InterConnections().Patch(context.Background(), ic.Name, types.MergePatchType, bytes, metav1.PatchOptions{}, "status"); err != nil {
+		klog.Errorf("failed to patch status of interconnection %s, %v", ic.Name, err)
+	}
+}
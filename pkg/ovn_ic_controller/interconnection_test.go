@@ -0,0 +1,285 @@
+package ovn_ic_controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	kubeovnlister "github.com/kubeovn/kube-ovn/pkg/client/listers/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+func Test_interConnectionConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		spec kubeovnv1.InterConnectionSpec
+		exp  map[string]string
+	}{
+		{
+			name: "default ports",
+			spec: kubeovnv1.InterConnectionSpec{
+				AzName:       "az1",
+				ICDBHosts:    []string{"192.168.0.1"},
+				GatewayNodes: []string{"node1"},
+			},
+			exp: map[string]string{
+				"enable-ic":  "true",
+				"az-name":    "az1",
+				"ic-db-host": "192.168.0.1",
+				"ic-nb-port": "6645",
+				"ic-sb-port": "6646",
+				"gw-nodes":   "node1",
+				"auto-route": "false",
+			},
+		},
+		{
+			name: "full spec",
+			spec: kubeovnv1.InterConnectionSpec{
+				AzName:       "az1",
+				ICDBHosts:    []string{"192.168.0.1", "192.168.0.2"},
+				ICNBPort:     16645,
+				ICSBPort:     16646,
+				GatewayNodes: []string{"node1", "node2"},
+				TSCount:      3,
+				AutoRoute:    true,
+				RouteImports: map[string][]string{"az2": {"10.16.0.0/16", "10.17.0.0/16"}},
+				RouteTables:  map[string]string{"az2": "rtb1"},
+			},
+			exp: map[string]string{
+				"enable-ic":        "true",
+				"az-name":          "az1",
+				"ic-db-host":       "192.168.0.1,192.168.0.2",
+				"ic-nb-port":       "16645",
+				"ic-sb-port":       "16646",
+				"gw-nodes":         "node1,node2",
+				"auto-route":       "true",
+				"ts-count":         "3",
+				"route-import.az2": "10.16.0.0/16,10.17.0.0/16",
+				"route-table.az2":  "rtb1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ic := &kubeovnv1.InterConnection{Spec: tt.spec}
+			require.Equal(t, tt.exp, interConnectionConfig(ic))
+		})
+	}
+}
+
+func Test_checkTransitSwitchCount(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, checkTransitSwitchCount(nil, 0))
+	require.NoError(t, checkTransitSwitchCount([]string{"ts", "ts1"}, 2))
+	require.NoError(t, checkTransitSwitchCount([]string{"ts", "ts1", "ts2"}, 2))
+	require.Error(t, checkTransitSwitchCount([]string{"ts"}, 2))
+}
+
+func Test_setInterConnectionConditions(t *testing.T) {
+	t.Parallel()
+
+	failed := errors.New("connection refused")
+	tests := []struct {
+		name      string
+		enabled   bool
+		results   map[string]error
+		expStatus map[kubeovnv1.ConditionType]corev1.ConditionStatus
+		expReason map[kubeovnv1.ConditionType]string
+	}{
+		{
+			name:    "disabled",
+			results: map[string]error{},
+			expStatus: map[kubeovnv1.ConditionType]corev1.ConditionStatus{
+				kubeovnv1.ICDBConnected:  corev1.ConditionFalse,
+				kubeovnv1.ICEstablished:  corev1.ConditionFalse,
+				kubeovnv1.ICRoutesSynced: corev1.ConditionFalse,
+				kubeovnv1.Ready:          corev1.ConditionFalse,
+			},
+			expReason: map[kubeovnv1.ConditionType]string{
+				kubeovnv1.ICDBConnected: "Pending",
+				kubeovnv1.Ready:         "Disabled",
+			},
+		},
+		{
+			name:    "phase failed",
+			enabled: true,
+			results: map[string]error{
+				kubeovnv1.ICDBConnected:  nil,
+				kubeovnv1.ICEstablished:  failed,
+				kubeovnv1.ICRoutesSynced: nil,
+			},
+			expStatus: map[kubeovnv1.ConditionType]corev1.ConditionStatus{
+				kubeovnv1.ICDBConnected:  corev1.ConditionTrue,
+				kubeovnv1.ICEstablished:  corev1.ConditionFalse,
+				kubeovnv1.ICRoutesSynced: corev1.ConditionTrue,
+				kubeovnv1.Ready:          corev1.ConditionFalse,
+			},
+			expReason: map[kubeovnv1.ConditionType]string{
+				kubeovnv1.ICEstablished: "Failed",
+				kubeovnv1.Ready:         "NotReady",
+			},
+		},
+		{
+			name:    "phase not observed",
+			enabled: true,
+			results: map[string]error{
+				kubeovnv1.ICDBConnected: nil,
+				kubeovnv1.ICEstablished: nil,
+			},
+			expStatus: map[kubeovnv1.ConditionType]corev1.ConditionStatus{
+				kubeovnv1.ICRoutesSynced: corev1.ConditionFalse,
+				kubeovnv1.Ready:          corev1.ConditionFalse,
+			},
+			expReason: map[kubeovnv1.ConditionType]string{
+				kubeovnv1.ICRoutesSynced: "Pending",
+				kubeovnv1.Ready:          "NotReady",
+			},
+		},
+		{
+			name:    "ready",
+			enabled: true,
+			results: map[string]error{
+				kubeovnv1.ICDBConnected:  nil,
+				kubeovnv1.ICEstablished:  nil,
+				kubeovnv1.ICRoutesSynced: nil,
+			},
+			expStatus: map[kubeovnv1.ConditionType]corev1.ConditionStatus{
+				kubeovnv1.ICDBConnected:  corev1.ConditionTrue,
+				kubeovnv1.ICEstablished:  corev1.ConditionTrue,
+				kubeovnv1.ICRoutesSynced: corev1.ConditionTrue,
+				kubeovnv1.Ready:          corev1.ConditionTrue,
+			},
+			expReason: map[kubeovnv1.ConditionType]string{
+				kubeovnv1.Ready: "Ready",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			status := &kubeovnv1.InterConnectionStatus{}
+			setInterConnectionConditions(status, tt.enabled, tt.results)
+			for ctype, exp := range tt.expStatus {
+				cond := status.GetCondition(ctype)
+				require.NotNil(t, cond, ctype)
+				require.Equal(t, exp, cond.Status, ctype)
+			}
+			for ctype, exp := range tt.expReason {
+				require.Equal(t, exp, status.GetCondition(ctype).Reason, ctype)
+			}
+		})
+	}
+}
+
+func Test_getICConfig(t *testing.T) {
+	t.Parallel()
+
+	const namespace = "kube-system"
+	newCM := func(superseded bool) *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: util.InterconnectionConfig, Namespace: namespace},
+			Data:       map[string]string{"enable-ic": "true", "az-name": "az-cm"},
+		}
+		if superseded {
+			cm.Annotations = map[string]string{util.ICSupersededAnnotation: "true"}
+		}
+		return cm
+	}
+	ic := &kubeovnv1.InterConnection{
+		ObjectMeta: metav1.ObjectMeta{Name: "ic"},
+		Spec:       kubeovnv1.InterConnectionSpec{AzName: "az-ic", ICDBHosts: []string{"192.168.0.1"}, GatewayNodes: []string{"node1"}},
+	}
+	deleting := ic.DeepCopy()
+	now := metav1.Now()
+	deleting.DeletionTimestamp = &now
+
+	tests := []struct {
+		name          string
+		cm            *corev1.ConfigMap
+		ic            *kubeovnv1.InterConnection
+		expAz         string
+		expNil        bool
+		expSuperseded bool
+	}{
+		{
+			name:   "neither exists",
+			expNil: true,
+		},
+		{
+			name:  "configmap only",
+			cm:    newCM(false),
+			expAz: "az-cm",
+		},
+		{
+			name:  "interconnection only",
+			ic:    ic,
+			expAz: "az-ic",
+		},
+		{
+			name:          "interconnection supersedes configmap",
+			cm:            newCM(false),
+			ic:            ic,
+			expAz:         "az-ic",
+			expSuperseded: true,
+		},
+		{
+			name:          "interconnection deleted",
+			cm:            newCM(true),
+			expNil:        true,
+			expSuperseded: true,
+		},
+		{
+			name:          "interconnection being deleted",
+			cm:            newCM(true),
+			ic:            deleting,
+			expNil:        true,
+			expSuperseded: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cmIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			icIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			kubeClient := fake.NewSimpleClientset()
+			if tt.cm != nil {
+				require.NoError(t, cmIndexer.Add(tt.cm))
+				_, err := kubeClient.CoreV1().ConfigMaps(namespace).Create(context.Background(), tt.cm, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			if tt.ic != nil {
+				require.NoError(t, icIndexer.Add(tt.ic))
+			}
+			c := &Controller{
+				config:                 &Configuration{KubeClient: kubeClient, PodNamespace: namespace},
+				configMapsLister:       listerv1.NewConfigMapLister(cmIndexer),
+				interConnectionsLister: kubeovnlister.NewInterConnectionLister(icIndexer),
+			}
+
+			config, err := c.getICConfig()
+			require.NoError(t, err)
+			if tt.expNil {
+				require.Nil(t, config)
+			} else {
+				require.Equal(t, tt.expAz, config["az-name"])
+			}
+			if tt.cm != nil {
+				cm, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(context.Background(), util.InterconnectionConfig, metav1.GetOptions{})
+				require.NoError(t, err)
+				require.Equal(t, tt.expSuperseded, cm.Annotations[util.ICSupersededAnnotation] == "true")
+			}
+		})
+	}
+}
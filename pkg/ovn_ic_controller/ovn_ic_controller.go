@@ -14,11 +14,12 @@ import (
 	"github.com/scylladb/go-set/strset"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 
 	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/ovs"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnicnb"
 	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
 	"github.com/kubeovn/kube-ovn/pkg/util"
 )
@@ -53,33 +54,12 @@ func (c *Controller) disableOVNIC(azName string) error {
 	return nil
 }
 
-func (c *Controller) setAutoRoute(autoRoute bool) {
-	var blackList []string
-	subnets, err := c.subnetsLister.List(labels.Everything())
+func (c *Controller) setAutoRoute(autoRoute, learn bool) {
+	blackList, err := c.icRouteBlackList()
 	if err != nil {
-		klog.Errorf("failed to list subnets, %v", err)
 		return
 	}
-	for _, subnet := range subnets {
-		if subnet.Spec.DisableInterConnection || subnet.Name == c.config.NodeSwitch {
-			blackList = append(blackList, subnet.Spec.CIDRBlock)
-		}
-	}
-	nodes, err := c.nodesLister.List(labels.Everything())
-	if err != nil {
-		klog.Errorf("failed to list node, %v", err)
-		return
-	}
-	for _, node := range nodes {
-		ipv4, ipv6 := util.GetNodeInternalIP(*node)
-		if ipv4 != "" {
-			blackList = append(blackList, ipv4)
-		}
-		if ipv6 != "" {
-			blackList = append(blackList, ipv6)
-		}
-	}
-	if err := c.OVNNbClient.SetICAutoRoute(autoRoute, blackList); err != nil {
+	if err := c.OVNNbClient.SetICAutoRoute(autoRoute, learn, blackList); err != nil {
 		klog.Errorf("failed to config auto route, %v", err)
 		return
 	}
@@ -99,12 +79,20 @@ func (c *Controller) DeleteICResources(azName string) error {
 			}
 			return needDelete
 		}
+		// ports of the transit switches connecting the peered vpcs
+		if ts, ok := strings.CutSuffix(lsp.Name, "-"+azName); ok && strings.HasPrefix(ts, util.VpcICPeeringSwitch+"-") {
+			icTSs = append(icTSs, ts)
+			return true
+		}
 		return false
 	}); err != nil {
 		return err
 	}
 
 	if err := c.OVNNbClient.DeleteLogicalRouterPorts(nil, func(lrp *ovnnb.LogicalRouterPort) bool {
+		if strings.HasPrefix(lrp.Name, azName+"-"+util.VpcICPeeringSwitch+"-") {
+			return true
+		}
 		lastIndex := strings.LastIndex(lrp.Name, "-")
 		if lastIndex != -1 {
 			firstPart := lrp.Name[:lastIndex]
@@ -125,15 +113,19 @@ func (c *Controller) DeleteICResources(azName string) error {
 }
 
 func (c *Controller) getICState(cmData, lastcmData map[string]string) int {
-	isCMEqual := reflect.DeepEqual(cmData, lastcmData)
+	// the route filter is applied without reestablishing the interconnection
+	isCMEqual := reflect.DeepEqual(icConnectionConfig(cmData), icConnectionConfig(lastcmData))
 	if icEnabled != "true" && len(lastcmData) == 0 && cmData["enable-ic"] == "true" {
 		return icFirstEstablish
 	}
 
 	if icEnabled == "true" && lastcmData != nil && isCMEqual {
 		var err error
-		c.ovnLegacyClient.OvnICNbAddress = genHostAddress(cmData["ic-db-host"], cmData["ic-nb-port"])
-		curTSs, err = c.ovnLegacyClient.GetTs()
+		if err = c.initOVNIcClients(cmData["ic-db-host"], cmData["ic-nb-port"], cmData["ic-sb-port"]); err != nil {
+			klog.Errorf("failed to connect to ovn-ic db, %v", err)
+			return icNoAction
+		}
+		curTSs, err = c.getTs()
 		if err != nil {
 			klog.Errorf("failed to get Transit_Switch, %v", err)
 			return icNoAction
@@ -147,23 +139,22 @@ func (c *Controller) getICState(cmData, lastcmData map[string]string) int {
 }
 
 func (c *Controller) resyncInterConnection() {
-	cm, err := c.configMapsLister.ConfigMaps(c.config.PodNamespace).Get(util.InterconnectionConfig)
-	if err != nil && !k8serrors.IsNotFound(err) {
-		klog.Errorf("failed to get ovn-ic-config, %v", err)
+	config, err := c.getICConfig()
+	if err != nil {
 		return
 	}
 
-	if k8serrors.IsNotFound(err) || cm.Data["enable-ic"] == "false" {
+	if config == nil || config["enable-ic"] == "false" {
 		if icEnabled == "false" {
 			return
 		}
 		klog.Info("start to remove ovn-ic")
 		var azName, icDBHost, icSBPort, icNBPort string
-		if cm != nil {
-			azName = cm.Data["az-name"]
-			icDBHost = cm.Data["ic-db-host"]
-			icSBPort = cm.Data["ic-sb-port"]
-			icNBPort = cm.Data["ic-nb-port"]
+		if config != nil {
+			azName = config["az-name"]
+			icDBHost = config["ic-db-host"]
+			icSBPort = config["ic-sb-port"]
+			icNBPort = config["ic-nb-port"]
 		} else if lastIcCm != nil {
 			azName = lastIcCm["az-name"]
 			icDBHost = lastIcCm["ic-db-host"]
@@ -172,8 +163,10 @@ func (c *Controller) resyncInterConnection() {
 		}
 
 		if icDBHost != "" {
-			c.ovnLegacyClient.OvnICSbAddress = genHostAddress(icDBHost, icSBPort)
-			c.ovnLegacyClient.OvnICNbAddress = genHostAddress(icDBHost, icNBPort)
+			if err := c.initOVNIcClients(icDBHost, icNBPort, icSBPort); err != nil {
+				klog.Errorf("failed to connect to ovn-ic db, %v", err)
+				return
+			}
 		}
 
 		err := c.disableOVNIC(azName)
@@ -183,53 +176,72 @@ func (c *Controller) resyncInterConnection() {
 		}
 		icEnabled = "false"
 		lastIcCm = nil
+		resetICPhases()
 
 		klog.Info("finish removing ovn-ic")
 		return
 	}
 
 	autoRoute := false
-	if cm.Data["auto-route"] == "true" {
+	if config["auto-route"] == "true" {
 		autoRoute = true
 	}
-	c.setAutoRoute(autoRoute)
+	// the routes are learned by kube-ovn instead of ovn-ic if the route filter is configured
+	filter, err := parseICRouteFilter(config)
+	if err != nil {
+		klog.Errorf("invalid route filter in %s, %v", util.InterconnectionConfig, err)
+		return
+	}
+	c.setAutoRoute(autoRoute, !filter.enabled())
 
-	switch c.getICState(cm.Data, lastIcCm) {
+	switch c.getICState(config, lastIcCm) {
 	case icNoAction:
+		if icEnabled == "true" && lastIcCm != nil {
+			lastIcCm = config
+		}
 		return
 	case icFirstEstablish:
-		c.ovnLegacyClient.OvnICNbAddress = genHostAddress(cm.Data["ic-db-host"], cm.Data["ic-nb-port"])
+		if err := c.initOVNIcClients(config["ic-db-host"], config["ic-nb-port"], config["ic-sb-port"]); err != nil {
+			klog.Errorf("failed to connect to ovn-ic db, %v", err)
+			return
+		}
 		klog.Info("start to establish ovn-ic")
-		if err := c.establishInterConnection(cm.Data); err != nil {
+		if err := c.establishInterConnection(config); err != nil {
 			klog.Errorf("failed to establish ovn-ic, %v", err)
+			setICPhase(kubeovnv1.ICEstablished, err)
 			return
 		}
-		curTSs, err := c.ovnLegacyClient.GetTs()
+		curTSs, err := c.getTs()
 		if err != nil {
 			klog.Errorf("failed to get Transit_Switch, %v", err)
 			return
 		}
 		icEnabled = "true"
-		lastIcCm = cm.Data
+		lastIcCm = config
+		setICPhase(kubeovnv1.ICEstablished, nil)
 		lastTSs = curTSs
 		klog.Info("finish establishing ovn-ic")
 		return
 	case icConfigChange:
-		c.ovnLegacyClient.OvnICSbAddress = genHostAddress(lastIcCm["ic-db-host"], cm.Data["ic-sb-port"])
-		c.ovnLegacyClient.OvnICNbAddress = genHostAddress(lastIcCm["ic-db-host"], cm.Data["ic-nb-port"])
+		if err := c.initOVNIcClients(lastIcCm["ic-db-host"], config["ic-nb-port"], config["ic-sb-port"]); err != nil {
+			klog.Errorf("failed to connect to ovn-ic db, %v", err)
+			return
+		}
 		err := c.disableOVNIC(lastIcCm["az-name"])
 		if err != nil {
 			klog.Errorf("Disable az %s OVN IC failed ", lastIcCm["az-name"])
 			return
 		}
 		klog.Info("start to reestablish ovn-ic")
-		if err := c.establishInterConnection(cm.Data); err != nil {
+		if err := c.establishInterConnection(config); err != nil {
 			klog.Errorf("failed to reestablish ovn-ic, %v", err)
+			setICPhase(kubeovnv1.ICEstablished, err)
 			return
 		}
 
 		icEnabled = "true"
-		lastIcCm = cm.Data
+		lastIcCm = config
+		setICPhase(kubeovnv1.ICEstablished, nil)
 		lastTSs = curTSs
 		klog.Info("finish reestablishing ovn-ic")
 		return
@@ -291,7 +303,12 @@ func (c *Controller) establishInterConnection(config map[string]string) error {
 		return err
 	}
 
-	tsNames, err := c.ovnLegacyClient.GetTs()
+	if err := c.checkTransitSwitches(config); err != nil {
+		klog.Errorf("failed to check transit switches, %v", err)
+		return err
+	}
+
+	tsNames, err := c.getTs()
 	if err != nil {
 		klog.Errorf("failed to list ic logical switch. %v ", err)
 		return err
@@ -370,11 +387,12 @@ func (c *Controller) establishInterConnection(config map[string]string) error {
 }
 
 func (c *Controller) acquireLrpAddress(ts string) (string, error) {
-	cidr, err := c.ovnLegacyClient.GetTsSubnet(ts)
+	transitSwitch, err := c.OVNIcNBClient.GetTransitSwitch(ts, false)
 	if err != nil {
 		klog.Errorf("failed to get ts subnet %s: %v", ts, err)
 		return "", err
 	}
+	cidr := transitSwitch.ExternalIDs["subnet"]
 	existAddress, err := c.listRemoteLogicalSwitchPortAddress()
 	if err != nil {
 		klog.Errorf("failed to list remote port address, %v", err)
@@ -467,6 +485,10 @@ func (c *Controller) delLearnedRoute() error {
 
 		}
 	}
+	if err = c.syncICLearnedRoutes(nil); err != nil {
+		klog.Errorf("failed to remove static routes learned by route filter: %v", err)
+		return err
+	}
 
 	klog.V(5).Infof("finish removing learned routes")
 	return nil
@@ -503,6 +525,58 @@ func (c *Controller) deleteStaticRouteFromVpc(name, table, cidr, nextHop string,
 	return nil
 }
 
+// initOVNIcClients connects to the ovn-ic db, the clients are recreated once the address changes
+func (c *Controller) initOVNIcClients(host, nbPort, sbPort string) error {
+	err := c.connectOVNIcDB(host, nbPort, sbPort)
+	setICPhase(kubeovnv1.ICDBConnected, err)
+	return err
+}
+
+func (c *Controller) connectOVNIcDB(host, nbPort, sbPort string) error {
+	nbAddr, sbAddr := genHostAddress(host, nbPort), genHostAddress(host, sbPort)
+	if c.OVNIcNBClient == nil || c.icNbAddress != nbAddr {
+		client, err := ovs.NewOvnIcNbClient(nbAddr, c.config.OvnTimeout)
+		if err != nil {
+			klog.Errorf("failed to create ovn-ic nb client for %s: %v", nbAddr, err)
+			return err
+		}
+		if c.OVNIcNBClient != nil {
+			c.OVNIcNBClient.Close()
+		}
+		c.OVNIcNBClient, c.icNbAddress = client, nbAddr
+	}
+	if c.OVNIcSBClient == nil || c.icSbAddress != sbAddr {
+		client, err := ovs.NewOvnIcSbClient(sbAddr, c.config.OvnTimeout)
+		if err != nil {
+			klog.Errorf("failed to create ovn-ic sb client for %s: %v", sbAddr, err)
+			return err
+		}
+		if c.OVNIcSBClient != nil {
+			c.OVNIcSBClient.Close()
+		}
+		c.OVNIcSBClient, c.icSbAddress = client, sbAddr
+	}
+	return nil
+}
+
+// getTs returns the names of the transit switches connecting the cluster routers,
+// the ones connecting the peered vpcs are excluded
+func (c *Controller) getTs() ([]string, error) {
+	tss, err := c.OVNIcNBClient.ListTransitSwitches(func(ts *ovnicnb.TransitSwitch) bool {
+		return util.IsTransitSwitchName(ts.Name)
+	})
+	if err != nil {
+		klog.Errorf("failed to list transit switches, %v", err)
+		return nil, err
+	}
+	names := make([]string, 0, len(tss))
+	for _, ts := range tss {
+		names = append(names, ts.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
 func genHostAddress(host, port string) (hostAddress string) {
 	hostList := strings.Split(host, ",")
 	if len(hostList) == 1 {
@@ -531,49 +605,17 @@ func (c *Controller) RemoveOldChassisInSbDB(azName string) error {
 	if azName == "" {
 		return nil
 	}
-
-	azUUID, err := c.ovnLegacyClient.GetAzUUID(azName)
-	if err != nil {
-		klog.Errorf("failed to get UUID of AZ %s: %v", lastIcCm["az-name"], err)
-		return err
-	}
-
-	if azUUID == "" {
-		klog.Infof("%s have already been deleted", azName)
-		return nil
-	}
-
-	gateways, err := c.ovnLegacyClient.GetGatewayUUIDsInOneAZ(azUUID)
-	if err != nil {
-		klog.Errorf("failed to get gateway UUIDs in AZ %s: %v", azUUID, err)
-		return err
-	}
-
-	routes, err := c.ovnLegacyClient.GetRouteUUIDsInOneAZ(azUUID)
-	if err != nil {
-		klog.Errorf("failed to get route UUIDs in AZ %s: %v", azUUID, err)
-		return err
-	}
-
-	portBindings, err := c.ovnLegacyClient.GetPortBindingUUIDsInOneAZ(azUUID)
-	if err != nil {
-		klog.Errorf("failed to get Port_Binding UUIDs in AZ %s: %v", azUUID, err)
-		return err
-	}
-
-	if err := c.ovnLegacyClient.DestroyPortBindings(portBindings); err != nil {
-		return err
-	}
-
-	if err := c.ovnLegacyClient.DestroyGateways(gateways); err != nil {
+	if c.OVNIcSBClient == nil {
+		err := fmt.Errorf("ovn-ic sb db is not connected, failed to remove az %s", azName)
+		klog.Error(err)
 		return err
 	}
 
-	if err := c.ovnLegacyClient.DestroyRoutes(routes); err != nil {
+	if err := c.OVNIcSBClient.DeleteAvailabilityZone(azName); err != nil {
+		klog.Errorf("failed to delete az %s from ovn-ic sb db: %v", azName, err)
 		return err
 	}
-
-	return c.ovnLegacyClient.DestroyChassis(azUUID)
+	return nil
 }
 
 func stripPrefix(policyMatch string) (string, error) {
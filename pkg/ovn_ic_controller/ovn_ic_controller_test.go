@@ -0,0 +1,15 @@
+package ovn_ic_controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RemoveOldChassisInSbDB(t *testing.T) {
+	t.Parallel()
+
+	c := &Controller{}
+	require.NoError(t, c.RemoveOldChassisInSbDB(""))
+	require.Error(t, c.RemoveOldChassisInSbDB("az1"))
+}
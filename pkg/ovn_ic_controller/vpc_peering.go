@@ -0,0 +1,406 @@
+package ovn_ic_controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/scylladb/go-set/strset"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnicnb"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// vpcICPeeringCIDR is the range the transit cidrs of the vpc peerings are derived from
+var vpcICPeeringCIDR = netip.MustParsePrefix("169.254.128.0/17")
+
+// vpcICPeeringKey identifies a side of the vpc peering
+func vpcICPeeringKey(az, vpc string) string {
+	return fmt.Sprintf("%s/%s", az, vpc)
+}
+
+// vpcICPeeringHash is the same in both availability zones, regardless of which side computes it
+func vpcICPeeringHash(localAz, localVpc, remoteAz, remoteVpc string) [sha256.Size]byte {
+	sides := []string{vpcICPeeringKey(localAz, localVpc), vpcICPeeringKey(remoteAz, remoteVpc)}
+	sort.Strings(sides)
+	return sha256.Sum256([]byte(strings.Join(sides, ",")))
+}
+
+// vpcICPeeringSwitch returns the name of the transit switch connecting the vpc pair
+func vpcICPeeringSwitch(localAz, localVpc, remoteAz, remoteVpc string) string {
+	hash := vpcICPeeringHash(localAz, localVpc, remoteAz, remoteVpc)
+	return fmt.Sprintf("%s-%s", util.VpcICPeeringSwitch, hex.EncodeToString(hash[:6]))
+}
+
+// vpcICPeeringDefaultCIDR derives a /30 in 169.254.128.0/17 from the vpc pair,
+// the following blocks are probed if the derived one is used by another transit switch
+func vpcICPeeringDefaultCIDR(localAz, localVpc, remoteAz, remoteVpc string, used []netip.Prefix) (string, error) {
+	hash := vpcICPeeringHash(localAz, localVpc, remoteAz, remoteVpc)
+	blocks := uint32(1) << (32 - vpcICPeeringCIDR.Bits() - 2)
+	start := binary.BigEndian.Uint32(hash[6:10]) % blocks
+	base := vpcICPeeringCIDR.Addr().As4()
+	for i := uint32(0); i < blocks; i++ {
+		var addr [4]byte
+		binary.BigEndian.PutUint32(addr[:], binary.BigEndian.Uint32(base[:])+((start+i)%blocks)*4)
+		prefix := netip.PrefixFrom(netip.AddrFrom4(addr), 30)
+		if !prefixesOverlap(prefix, used) {
+			return prefix.String(), nil
+		}
+	}
+	return "", fmt.Errorf("no available transit cidr in %s", vpcICPeeringCIDR)
+}
+
+// prefixesOverlap reports whether the prefix overlaps any of the others
+func prefixesOverlap(prefix netip.Prefix, others []netip.Prefix) bool {
+	for _, other := range others {
+		if prefix.Overlaps(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePrefixes parses the comma separated cidrs, the invalid ones are ignored
+func parsePrefixes(cidrs string) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, s := range strings.Split(cidrs, ",") {
+		if prefix, err := netip.ParsePrefix(strings.TrimSpace(s)); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		}
+	}
+	return prefixes
+}
+
+// vpcICPeeringUsedCIDRs returns the subnets of the vpc peering transit switches except the given one
+func vpcICPeeringUsedCIDRs(tss map[string]map[string]string, exclude string) []netip.Prefix {
+	var used []netip.Prefix
+	for ts, externalIDs := range tss {
+		if ts != exclude {
+			used = append(used, parsePrefixes(externalIDs["subnet"])...)
+		}
+	}
+	return used
+}
+
+// validateVpcICPrefixes splits the prefixes published by the remote vpc into the ones safe to route
+// and the rejected ones, which are invalid, default routes or overlap the local cidrs
+func validateVpcICPrefixes(prefixes string, local []netip.Prefix) (valid, rejected []string) {
+	for _, s := range strings.Split(prefixes, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil || prefix.Bits() == 0 || prefixesOverlap(prefix.Masked(), local) {
+			rejected = append(rejected, s)
+			continue
+		}
+		valid = append(valid, prefix.Masked().String())
+	}
+	return valid, rejected
+}
+
+// vpcICPeeringAddress returns the address of the local router port on the transit switch,
+// the side with the smaller key takes the first host address and the other one takes the second
+func vpcICPeeringAddress(cidr, localAz, localVpc, remoteAz, remoteVpc string) (string, error) {
+	host := 1
+	if vpcICPeeringKey(localAz, localVpc) > vpcICPeeringKey(remoteAz, remoteVpc) {
+		host = 2
+	}
+	var addresses []string
+	for _, s := range strings.Split(cidr, ",") {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(s))
+		if err != nil {
+			return "", fmt.Errorf("invalid transit cidr %s: %v", cidr, err)
+		}
+		addr := prefix.Masked().Addr()
+		for i := 0; i < host; i++ {
+			addr = addr.Next()
+		}
+		if !prefix.Contains(addr) {
+			return "", fmt.Errorf("transit cidr %s is too small", cidr)
+		}
+		addresses = append(addresses, netip.PrefixFrom(addr, prefix.Bits()).String())
+	}
+	return strings.Join(addresses, ","), nil
+}
+
+// vpcICPrefixes returns the cidrs of the vpc subnets advertised to the peered vpcs
+func (c *Controller) vpcICPrefixes(vpc *kubeovnv1.Vpc) (string, error) {
+	subnets, err := c.subnetsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list subnets, %v", err)
+		return "", err
+	}
+	var prefixes []string
+	for _, subnet := range subnets {
+		if subnet.Spec.Vpc != vpc.Name || subnet.Spec.DisableInterConnection || subnet.Spec.CIDRBlock == "" {
+			continue
+		}
+		prefixes = append(prefixes, strings.Split(subnet.Spec.CIDRBlock, ",")...)
+	}
+	sort.Strings(prefixes)
+	return strings.Join(prefixes, ","), nil
+}
+
+// vpcICLocalCIDRs returns the cidrs the prefixes learned by the vpc must not overlap,
+// which are the cidrs of all the vpc subnets and the join subnet
+func (c *Controller) vpcICLocalCIDRs(vpc *kubeovnv1.Vpc) ([]netip.Prefix, error) {
+	subnets, err := c.subnetsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list subnets, %v", err)
+		return nil, err
+	}
+	local := parsePrefixes(c.config.NodeSwitchCIDR)
+	for _, subnet := range subnets {
+		if subnet.Spec.Vpc == vpc.Name {
+			local = append(local, parsePrefixes(subnet.Spec.CIDRBlock)...)
+		}
+	}
+	return local, nil
+}
+
+func (c *Controller) gwChassises() ([]string, error) {
+	var chassises []string
+	for _, gw := range strings.Split(lastIcCm["gw-nodes"], ",") {
+		gw = strings.TrimSpace(gw)
+		chassis, err := c.OVNSbClient.GetChassisByHost(gw)
+		if err != nil {
+			klog.Errorf("failed to get gw %s chassis: %v", gw, err)
+			return nil, err
+		}
+		if chassis.Name == "" {
+			return nil, fmt.Errorf("no chassis for gw %s", gw)
+		}
+		chassises = append(chassises, chassis.Name)
+	}
+	return chassises, nil
+}
+
+// syncVpcICPeerings connects the custom vpcs to the vpcs in the other availability zones,
+// each vpc pair has a dedicated transit switch, and the subnets of the vpcs are published
+// in the external ids of the transit switch so that only the peered vpcs learn the routes
+func (c *Controller) syncVpcICPeerings() {
+	if icEnabled != "true" || lastIcCm == nil {
+		return
+	}
+	az := lastIcCm["az-name"]
+
+	vpcs, err := c.vpcsLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list vpcs, %v", err)
+		return
+	}
+	transitSwitches, err := c.OVNIcNBClient.ListTransitSwitches(func(ts *ovnicnb.TransitSwitch) bool {
+		return strings.HasPrefix(ts.Name, util.VpcICPeeringSwitch+"-")
+	})
+	if err != nil {
+		klog.Errorf("failed to list vpc peering transit switches, %v", err)
+		return
+	}
+	tss := make(map[string]map[string]string, len(transitSwitches))
+	for _, ts := range transitSwitches {
+		tss[ts.Name] = ts.ExternalIDs
+		if tss[ts.Name] == nil {
+			tss[ts.Name] = map[string]string{}
+		}
+	}
+
+	expected := strset.New()
+	for _, vpc := range vpcs {
+		if vpc.Name == c.config.ClusterRouter || !vpc.DeletionTimestamp.IsZero() {
+			continue
+		}
+		statuses := make([]kubeovnv1.VpcICPeeringStatus, 0, len(vpc.Spec.ICPeerings))
+		for _, peering := range vpc.Spec.ICPeerings {
+			status := kubeovnv1.VpcICPeeringStatus{
+				RemoteAz:      peering.RemoteAz,
+				RemoteVpc:     peering.RemoteVpc,
+				TransitSwitch: vpcICPeeringSwitch(az, vpc.Name, peering.RemoteAz, peering.RemoteVpc),
+			}
+			expected.Add(status.TransitSwitch)
+			if err = c.syncVpcICPeering(az, vpc, peering, tss, &status); err != nil {
+				klog.Errorf("failed to sync ic peering of vpc %s with vpc %s in az %s, %v", vpc.Name, peering.RemoteVpc, peering.RemoteAz, err)
+			}
+			statuses = append(statuses, status)
+		}
+		if len(statuses) == 0 && len(vpc.Status.ICPeerings) == 0 || reflect.DeepEqual(statuses, vpc.Status.ICPeerings) {
+			continue
+		}
+		if err = c.patchVpcICPeeringStatus(vpc.Name, statuses); err != nil {
+			klog.Errorf("failed to patch ic peering status of vpc %s, %v", vpc.Name, err)
+		}
+	}
+
+	for ts, externalIDs := range tss {
+		if _, ok := externalIDs["vpc."+az]; !ok || expected.Has(ts) {
+			continue
+		}
+		if err = c.deleteVpcICPeering(az, ts, externalIDs); err != nil {
+			klog.Errorf("failed to delete vpc peering transit switch %s, %v", ts, err)
+		}
+	}
+}
+
+func (c *Controller) syncVpcICPeering(az string, vpc *kubeovnv1.Vpc, peering *kubeovnv1.VpcICPeering, tss map[string]map[string]string, status *kubeovnv1.VpcICPeeringStatus) error {
+	ts := status.TransitSwitch
+	externalIDs := tss[ts]
+	subnet := externalIDs["subnet"]
+	if externalIDs == nil {
+		used := vpcICPeeringUsedCIDRs(tss, ts)
+		if subnet = peering.TransitCIDR; subnet != "" {
+			for _, prefix := range parsePrefixes(subnet) {
+				if prefixesOverlap(prefix, used) {
+					err := fmt.Errorf("transit cidr %s overlaps with another vpc peering transit switch", subnet)
+					klog.Error(err)
+					return err
+				}
+			}
+		} else {
+			var err error
+			if subnet, err = vpcICPeeringDefaultCIDR(az, vpc.Name, peering.RemoteAz, peering.RemoteVpc, used); err != nil {
+				klog.Error(err)
+				return err
+			}
+		}
+		klog.Infof("create transit switch %s with subnet %s for vpc %s", ts, subnet, vpc.Name)
+		if err := c.OVNIcNBClient.CreateTransitSwitch(ts, map[string]string{"subnet": subnet}); err != nil {
+			klog.Errorf("failed to create transit switch %s, %v", ts, err)
+			return err
+		}
+		externalIDs = map[string]string{"subnet": subnet}
+		tss[ts] = externalIDs
+	}
+
+	prefixes, err := c.vpcICPrefixes(vpc)
+	if err != nil {
+		return err
+	}
+	if externalIDs["vpc."+az] != vpc.Name || externalIDs["prefixes."+az] != prefixes {
+		if err = c.OVNIcNBClient.SetTransitSwitchExternalIDs(ts, map[string]string{"vpc." + az: vpc.Name, "prefixes." + az: prefixes}); err != nil {
+			klog.Errorf("failed to publish prefixes of vpc %s to transit switch %s, %v", vpc.Name, ts, err)
+			return err
+		}
+	}
+
+	// the logical switch is created by ovn-ic once the transit switch is synced to the az
+	exist, err := c.OVNNbClient.LogicalSwitchExists(ts)
+	if err != nil {
+		klog.Errorf("failed to check logical switch %s, %v", ts, err)
+		return err
+	}
+	if !exist {
+		klog.Infof("wait for transit switch %s to be synced by ovn-ic", ts)
+		return nil
+	}
+
+	tsPort := fmt.Sprintf("%s-%s", ts, az)
+	if exist, err = c.OVNNbClient.LogicalSwitchPortExists(tsPort); err != nil {
+		klog.Errorf("failed to check logical switch port %s, %v", tsPort, err)
+		return err
+	}
+	if !exist {
+		lrpAddr, err := vpcICPeeringAddress(subnet, az, vpc.Name, peering.RemoteAz, peering.RemoteVpc)
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		chassises, err := c.gwChassises()
+		if err != nil {
+			return err
+		}
+		lrpName := fmt.Sprintf("%s-%s", az, ts)
+		if err = c.OVNNbClient.CreateLogicalPatchPort(ts, vpc.Name, tsPort, lrpName, lrpAddr, util.GenerateMac(), chassises...); err != nil {
+			klog.Errorf("failed to create ovn-ic lrp %q: %v", lrpName, err)
+			return err
+		}
+	}
+
+	remotePort, err := c.OVNNbClient.GetLogicalSwitchPort(fmt.Sprintf("%s-%s", ts, peering.RemoteAz), true)
+	if err != nil {
+		klog.Errorf("failed to get remote port of transit switch %s, %v", ts, err)
+		return err
+	}
+	var fields []string
+	if remotePort != nil && remotePort.Type == "remote" && len(remotePort.Addresses) != 0 {
+		fields = strings.Fields(remotePort.Addresses[0])
+	}
+	if len(fields) < 2 {
+		klog.Infof("wait for vpc %s in az %s to connect to transit switch %s", peering.RemoteVpc, peering.RemoteAz, ts)
+		return nil
+	}
+	var nextHops []string
+	for _, addr := range fields[1:] {
+		nextHops = append(nextHops, strings.Split(addr, "/")[0])
+	}
+	status.NextHopIP = strings.Join(nextHops, ",")
+
+	if externalIDs["vpc."+peering.RemoteAz] != peering.RemoteVpc {
+		return nil
+	}
+	local, err := c.vpcICLocalCIDRs(vpc)
+	if err != nil {
+		return err
+	}
+	valid, rejected := validateVpcICPrefixes(externalIDs["prefixes."+peering.RemoteAz], local)
+	if len(rejected) != 0 {
+		klog.Warningf("reject prefixes %v of vpc %s in az %s, which are invalid, default routes or overlap the cidrs of vpc %s", rejected, peering.RemoteVpc, peering.RemoteAz, vpc.Name)
+	}
+	status.LearnedPrefixes = valid
+	status.Ready = status.NextHopIP != ""
+	return nil
+}
+
+// deleteVpcICPeering disconnects the local vpc from the transit switch,
+// the transit switch is deleted once no vpc is connected
+func (c *Controller) deleteVpcICPeering(az, ts string, externalIDs map[string]string) error {
+	klog.Infof("delete vpc %s from transit switch %s", externalIDs["vpc."+az], ts)
+	if err := c.OVNNbClient.DeleteLogicalRouterPort(fmt.Sprintf("%s-%s", az, ts)); err != nil {
+		klog.Error(err)
+		return err
+	}
+	if err := c.OVNNbClient.DeleteLogicalSwitchPort(fmt.Sprintf("%s-%s", ts, az)); err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	remaining := false
+	for key := range externalIDs {
+		if strings.HasPrefix(key, "vpc.") && key != "vpc."+az {
+			remaining = true
+			break
+		}
+	}
+	if !remaining {
+		return c.OVNIcNBClient.DeleteTransitSwitch(ts)
+	}
+	return c.OVNIcNBClient.RemoveTransitSwitchExternalIDs(ts, "vpc."+az, "prefixes."+az)
+}
+
+func (c *Controller) patchVpcICPeeringStatus(vpc string, statuses []kubeovnv1.VpcICPeeringStatus) error {
+	var value interface{} = statuses
+	if len(statuses) == 0 {
+		value = nil
+	}
+	bytes, err := json.Marshal(map[string]interface{}{"status": map[string]interface{}{"icPeerings": value}})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err = c.config.KubeOvnClient.KubeovnV1().Vpcs().Patch(context.Background(), vpc, types.MergePatchType, bytes, metav1.PatchOptions{}, "status"); err != nil {
+		klog.Error(err)
+		return err
+	}
+	return nil
+}
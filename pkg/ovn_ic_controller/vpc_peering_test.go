@@ -0,0 +1,230 @@
+package ovn_ic_controller
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_vpcICPeeringDefaultCIDR(t *testing.T) {
+	t.Parallel()
+
+	derived, err := vpcICPeeringDefaultCIDR("az1", "vpc1", "az2", "vpc2", nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		localAz   string
+		localVpc  string
+		remoteAz  string
+		remoteVpc string
+		used      []netip.Prefix
+		exp       string
+		differ    bool
+		expErr    bool
+	}{
+		{
+			name:      "derived from the vpc pair",
+			localAz:   "az1",
+			localVpc:  "vpc1",
+			remoteAz:  "az2",
+			remoteVpc: "vpc2",
+			exp:       derived,
+		},
+		{
+			name:      "same on the remote side",
+			localAz:   "az2",
+			localVpc:  "vpc2",
+			remoteAz:  "az1",
+			remoteVpc: "vpc1",
+			exp:       derived,
+		},
+		{
+			name:      "unrelated cidr used",
+			localAz:   "az1",
+			localVpc:  "vpc1",
+			remoteAz:  "az2",
+			remoteVpc: "vpc2",
+			used:      []netip.Prefix{netip.MustParsePrefix("10.0.0.0/30")},
+			exp:       derived,
+		},
+		{
+			name:      "derived cidr used by another peering",
+			localAz:   "az1",
+			localVpc:  "vpc1",
+			remoteAz:  "az2",
+			remoteVpc: "vpc2",
+			used:      []netip.Prefix{netip.MustParsePrefix(derived)},
+			differ:    true,
+		},
+		{
+			name:      "range exhausted",
+			localAz:   "az1",
+			localVpc:  "vpc1",
+			remoteAz:  "az2",
+			remoteVpc: "vpc2",
+			used:      []netip.Prefix{vpcICPeeringCIDR},
+			expErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cidr, err := vpcICPeeringDefaultCIDR(tt.localAz, tt.localVpc, tt.remoteAz, tt.remoteVpc, tt.used)
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			prefix := netip.MustParsePrefix(cidr)
+			require.Equal(t, 30, prefix.Bits())
+			require.True(t, vpcICPeeringCIDR.Contains(prefix.Addr()))
+			require.False(t, prefixesOverlap(prefix, tt.used))
+			if tt.differ {
+				require.NotEqual(t, derived, cidr)
+			} else {
+				require.Equal(t, tt.exp, cidr)
+			}
+		})
+	}
+}
+
+func Test_vpcICPeeringAddress(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		cidr      string
+		localAz   string
+		localVpc  string
+		remoteAz  string
+		remoteVpc string
+		exp       string
+		expErr    bool
+	}{
+		{
+			name:      "smaller key takes the first address",
+			cidr:      "169.254.128.0/30",
+			localAz:   "az1",
+			localVpc:  "vpc1",
+			remoteAz:  "az2",
+			remoteVpc: "vpc2",
+			exp:       "169.254.128.1/30",
+		},
+		{
+			name:      "larger key takes the second address",
+			cidr:      "169.254.128.0/30",
+			localAz:   "az2",
+			localVpc:  "vpc2",
+			remoteAz:  "az1",
+			remoteVpc: "vpc1",
+			exp:       "169.254.128.2/30",
+		},
+		{
+			name:      "unmasked cidr",
+			cidr:      "169.254.128.3/30",
+			localAz:   "az1",
+			localVpc:  "vpc1",
+			remoteAz:  "az2",
+			remoteVpc: "vpc2",
+			exp:       "169.254.128.1/30",
+		},
+		{
+			name:      "dual stack",
+			cidr:      "169.254.128.0/30, fd00::/126",
+			localAz:   "az2",
+			localVpc:  "vpc2",
+			remoteAz:  "az1",
+			remoteVpc: "vpc1",
+			exp:       "169.254.128.2/30,fd00::2/126",
+		},
+		{
+			name:      "cidr too small",
+			cidr:      "169.254.128.0/31",
+			localAz:   "az2",
+			localVpc:  "vpc2",
+			remoteAz:  "az1",
+			remoteVpc: "vpc1",
+			expErr:    true,
+		},
+		{
+			name:      "invalid cidr",
+			cidr:      "169.254.128.0",
+			localAz:   "az1",
+			localVpc:  "vpc1",
+			remoteAz:  "az2",
+			remoteVpc: "vpc2",
+			expErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			addr, err := vpcICPeeringAddress(tt.cidr, tt.localAz, tt.localVpc, tt.remoteAz, tt.remoteVpc)
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.exp, addr)
+		})
+	}
+}
+
+func Test_validateVpcICPrefixes(t *testing.T) {
+	t.Parallel()
+
+	local := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/16"),
+		netip.MustParsePrefix("100.64.0.0/16"),
+		netip.MustParsePrefix("fd00:10::/64"),
+	}
+	tests := []struct {
+		name        string
+		prefixes    string
+		expValid    []string
+		expRejected []string
+	}{
+		{
+			name:     "empty",
+			prefixes: "",
+		},
+		{
+			name:     "disjoint prefixes",
+			prefixes: "10.1.0.0/16,fd00:20::/64",
+			expValid: []string{"10.1.0.0/16", "fd00:20::/64"},
+		},
+		{
+			name:        "default routes",
+			prefixes:    "0.0.0.0/0,::/0,10.1.0.0/16",
+			expValid:    []string{"10.1.0.0/16"},
+			expRejected: []string{"0.0.0.0/0", "::/0"},
+		},
+		{
+			name:        "overlap with the local subnets",
+			prefixes:    "10.0.1.0/24,10.0.0.0/8,fd00:10::/96",
+			expRejected: []string{"10.0.1.0/24", "10.0.0.0/8", "fd00:10::/96"},
+		},
+		{
+			name:        "overlap with the join subnet",
+			prefixes:    "100.64.0.0/24,10.2.0.0/16",
+			expValid:    []string{"10.2.0.0/16"},
+			expRejected: []string{"100.64.0.0/24"},
+		},
+		{
+			name:        "invalid prefixes",
+			prefixes:    "10.3.0.0,foo,10.4.0.1/16",
+			expValid:    []string{"10.4.0.0/16"},
+			expRejected: []string{"10.3.0.0", "foo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			valid, rejected := validateVpcICPrefixes(tt.prefixes, local)
+			require.Equal(t, tt.expValid, valid)
+			require.Equal(t, tt.expRejected, rejected)
+		})
+	}
+}
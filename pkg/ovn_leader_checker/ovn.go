@@ -25,6 +25,7 @@ import (
 
 	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
 	"github.com/kubeovn/kube-ovn/pkg/ovs"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnicnb"
 	"github.com/kubeovn/kube-ovn/pkg/util"
 )
 
@@ -36,6 +37,8 @@ const (
 	DefaultProbeInterval = 5
 	OvnNorthdPort        = "6643"
 	MaxFailCount         = 3
+	OvnICNbPort          = 6645
+	OvnICNbTimeout       = 60
 )
 
 var failCount int
@@ -47,6 +50,9 @@ type Configuration struct {
 	ProbeInterval  int
 	EnableCompact  bool
 	ISICDBServer   bool
+
+	// OVNIcNBClient connects to the local ovn-ic nb db once it is the leader
+	OVNIcNBClient *ovs.OVNIcNbClient
 }
 
 // ParseFlags parses cmd args then init kubeclient and conf
@@ -406,7 +412,7 @@ func doOvnLeaderCheck(cfg *Configuration, podName, podNamespace string) {
 		}
 
 		if icNbLeader {
-			if err := updateTS(); err != nil {
+			if err := updateTS(cfg); err != nil {
 				klog.Errorf("update ts num failed err: %v ", err)
 				return
 			}
@@ -424,15 +430,8 @@ func StartOvnLeaderCheck(cfg *Configuration) {
 	}
 }
 
-func getTSName(index int) string {
-	if index == 0 {
-		return util.InterconnectionSwitch
-	}
-	return fmt.Sprintf("%s%d", util.InterconnectionSwitch, index)
-}
-
 func getTSCidr(index int) (string, error) {
-	var proto, cidr string
+	var proto string
 	podIpsEnv := os.Getenv("POD_IPS")
 	podIps := strings.Split(podIpsEnv, ",")
 	if len(podIps) == 1 {
@@ -444,31 +443,39 @@ func getTSCidr(index int) (string, error) {
 	} else if len(podIps) == 2 {
 		proto = kubeovnv1.ProtocolDual
 	}
+	return util.GetTransitSwitchCIDR(proto, index), nil
+}
 
-	switch proto {
-	case kubeovnv1.ProtocolIPv4:
-		cidr = fmt.Sprintf("169.254.%d.0/24", 100+index)
-	case kubeovnv1.ProtocolIPv6:
-		cidr = fmt.Sprintf("fe80:a9fe:%02x::/112", 100+index)
-	case kubeovnv1.ProtocolDual:
-		cidr = fmt.Sprintf("169.254.%d.0/24,fe80:a9fe:%02x::/112", 100+index, 100+index)
+func icNbAddress() string {
+	addr := net.JoinHostPort(os.Getenv("POD_IP"), strconv.Itoa(OvnICNbPort))
+	if os.Getenv(EnvSSL) == "false" {
+		return fmt.Sprintf("tcp:%s", addr)
 	}
-	return cidr, nil
+	return fmt.Sprintf("ssl:%s", addr)
 }
 
-func updateTS() error {
-	cmd := exec.Command("ovn-ic-nbctl", "show")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("ovn-ic-nbctl show output: %s, err: %v", output, err)
+func updateTS(cfg *Configuration) error {
+	if cfg.OVNIcNBClient == nil {
+		client, err := ovs.NewOvnIcNbClient(icNbAddress(), OvnICNbTimeout)
+		if err != nil {
+			klog.Errorf("failed to create ovn-ic nb client: %v", err)
+			return err
+		}
+		cfg.OVNIcNBClient = client
 	}
-	var existTSCount int
-	if lines := strings.TrimSpace(string(output)); lines != "" {
-		existTSCount = len(strings.Split(lines, "\n"))
+
+	// the transit switches of the vpc peerings are managed by the ic controller
+	tss, err := cfg.OVNIcNBClient.ListTransitSwitches(func(ts *ovnicnb.TransitSwitch) bool {
+		return util.IsTransitSwitchName(ts.Name)
+	})
+	if err != nil {
+		klog.Errorf("failed to list transit switches: %v", err)
+		return err
 	}
+	existTSCount := len(tss)
 	expectTSCount, err := strconv.Atoi(os.Getenv("TS_NUM"))
 	if err != nil {
-		return fmt.Errorf("expectTSCount atoi failed output: %s, err: %v", output, err)
+		return fmt.Errorf("expectTSCount atoi failed, err: %v", err)
 	}
 	if expectTSCount == existTSCount {
 		klog.V(3).Infof("expectTSCount %d no changes required.", expectTSCount)
@@ -477,45 +484,23 @@ func updateTS() error {
 
 	if expectTSCount > existTSCount {
 		for i := expectTSCount - 1; i > existTSCount-1; i-- {
-			tsName := getTSName(i)
+			tsName := util.GetTransitSwitchName(i)
 			subnet, err := getTSCidr(i)
 			if err != nil {
 				return err
 			}
-			cmd := exec.Command("ovn-ic-nbctl",
-				ovs.MayExist, "ts-add", tsName,
-				"--", "set", "Transit_Switch", tsName, fmt.Sprintf(`external_ids:subnet="%s"`, subnet))
-			if os.Getenv("ENABLE_SSL") == "true" {
-				cmd = exec.Command("ovn-ic-nbctl",
-					"--private-key=/var/run/tls/key",
-					"--certificate=/var/run/tls/cert",
-					"--ca-cert=/var/run/tls/cacert",
-					ovs.MayExist, "ts-add", tsName,
-					"--", "set", "Transit_Switch", tsName, fmt.Sprintf(`external_ids:subnet="%s"`, subnet))
-			}
-			output, err := cmd.CombinedOutput()
-			if err != nil {
-				return fmt.Errorf("output: %s, err: %v", output, err)
+			if err = cfg.OVNIcNBClient.CreateTransitSwitch(tsName, map[string]string{"subnet": subnet}); err != nil {
+				klog.Error(err)
+				return err
 			}
 		}
 	} else {
 		for i := existTSCount - 1; i >= expectTSCount; i-- {
-			tsName := getTSName(i)
-			cmd := exec.Command("ovn-ic-nbctl",
-				"ts-del", tsName)
-			if os.Getenv("ENABLE_SSL") == "true" {
-				cmd = exec.Command("ovn-ic-nbctl",
-					"--private-key=/var/run/tls/key",
-					"--certificate=/var/run/tls/cert",
-					"--ca-cert=/var/run/tls/cacert",
-					"ts-del", tsName)
-			}
-			output, err := cmd.CombinedOutput()
-			if err != nil {
-				return fmt.Errorf("output: %s, err: %v", output, err)
+			if err = cfg.OVNIcNBClient.DeleteTransitSwitch(util.GetTransitSwitchName(i)); err != nil {
+				klog.Error(err)
+				return err
 			}
 		}
 	}
-
 	return nil
 }
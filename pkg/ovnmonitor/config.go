@@ -45,6 +45,7 @@ type Configuration struct {
 	ServiceNorthdFileLogPath        string
 	ServiceNorthdFilePidPath        string
 	EnableMetrics                   bool
+	EnableSBTableRows               bool
 }
 
 // ParseFlags get parameters information.
@@ -56,6 +57,8 @@ func ParseFlags() (*Configuration, error) {
 		argPollInterval  = pflag.Int("ovs.poll-interval", 30, "The minimum interval (in seconds) between collections from OVN server.")
 		argEnableMetrics = pflag.Bool("enable-metrics", true, "Whether to support metrics query")
 
+		argEnableSBTableRows = pflag.Bool("enable-sb-table-rows", false, "Whether to count the rows of the OVN SB tables, which monitors the logical flows, port bindings and mac bindings of the SB db")
+
 		argSystemRunDir                    = pflag.String("system.run.dir", "/var/run/openvswitch", "OVS default run directory.")
 		argDatabaseVswitchName             = pflag.String("database.vswitch.name", "Open_vSwitch", "The name of OVS db.")
 		argDatabaseVswitchSocketRemote     = pflag.String("database.vswitch.socket.remote", "unix:/var/run/openvswitch/db.sock", "JSON-RPC unix socket to OVS db.")
@@ -144,6 +147,7 @@ func ParseFlags() (*Configuration, error) {
 		ServiceNorthdFileLogPath:        *argServiceNorthdFileLogPath,
 		ServiceNorthdFilePidPath:        *argServiceNorthdFilePidPath,
 		EnableMetrics:                   *argEnableMetrics,
+		EnableSBTableRows:               *argEnableSBTableRows,
 	}
 
 	klog.Infof("ovn monitor config is %+v", config)
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/greenpau/ovsdb"
+	"github.com/ovn-org/libovsdb/client"
 	"k8s.io/klog/v2"
 
 	"github.com/kubeovn/kube-ovn/pkg/util"
@@ -31,6 +32,14 @@ type Exporter struct {
 	pollInterval int
 	errors       int64
 	errorsLocker sync.RWMutex
+
+	// enableSBTableRows enables counting the rows of the SB tables, which requires the monitors of the large ones
+	enableSBTableRows bool
+
+	// serverClients are the clients of the _Server databases and dbClients are the clients
+	// of the monitored NB/SB databases on the local ovsdb-servers, indexed by database name
+	serverClients map[string]client.Client
+	dbClients     map[string]client.Client
 }
 
 // OVNDBClusterStatus contains information about a cluster.
@@ -39,7 +48,9 @@ type OVNDBClusterStatus struct {
 	sid             string
 	status          string
 	role            string
-	leader          string
+	leader          bool
+	connected       bool
+	index           float64
 	vote            string
 	term            float64
 	electionTimer   float64
@@ -55,7 +66,10 @@ type OVNDBClusterStatus struct {
 
 // NewExporter returns an initialized Exporter.
 func NewExporter(cfg *Configuration) *Exporter {
-	e := Exporter{}
+	e := Exporter{
+		serverClients: make(map[string]client.Client),
+		dbClients:     make(map[string]client.Client),
+	}
 	e.Client = ovsdb.NewOvnClient()
 	e.initParas(cfg)
 	return &e
@@ -64,6 +78,7 @@ func NewExporter(cfg *Configuration) *Exporter {
 func (e *Exporter) initParas(cfg *Configuration) {
 	e.timeout = cfg.PollTimeout
 	e.pollInterval = cfg.PollInterval
+	e.enableSBTableRows = cfg.EnableSBTableRows
 
 	e.Client.Timeout = cfg.PollTimeout
 	e.Client.System.Hostname = os.Getenv("KUBE_NODE_NAME")
@@ -103,6 +118,9 @@ func (e *Exporter) initParas(cfg *Configuration) {
 
 // StartConnection connect to database socket
 func (e *Exporter) StartConnection() error {
+	if err := e.connectOvsdb(); err != nil {
+		return err
+	}
 	if err := e.Client.Connect(); err != nil {
 		return err
 	}
@@ -153,6 +171,9 @@ func (e *Exporter) ovnMetricsUpdate() {
 		e.exportOvnChassisGauge()
 		e.exportLogicalSwitchGauge()
 		e.exportLogicalSwitchPortGauge()
+		e.exportOvnDBTableRowsGauge()
+
+		e.exportNorthdPerformanceGauge()
 
 		e.exportOvnClusterEnableGauge()
 		if isClusterEnabled {
@@ -238,9 +259,15 @@ func (e *Exporter) exportLogicalSwitchPortGauge() {
 	e.setLogicalSwitchPortInfoMetric()
 }
 
+func (e *Exporter) exportNorthdPerformanceGauge() {
+	resetNorthdPerformanceMetrics()
+	e.setNorthdStopwatchMetric()
+	e.setNorthdIncEngineMetric()
+}
+
 func (e *Exporter) exportOvnClusterEnableGauge() {
 	metricClusterEnabled.Reset()
-	isClusterEnabled, err := getClusterEnableState(e.Client.Database.Northbound.File.Data.Path)
+	isClusterEnabled, err := e.getClusterEnableState(nbDBName)
 	if err != nil {
 		klog.Errorf("failed to get output of cluster status: %v", err)
 	}
@@ -263,10 +290,32 @@ func (e *Exporter) exportOvnClusterInfoGauge() {
 			klog.Errorf("Failed to get Cluster Info for database %s: %v", database, err)
 			return
 		}
+		db, err := e.getServerDatabase(database)
+		if err != nil {
+			klog.Errorf("Failed to get _Server database of %s: %v", database, err)
+			e.IncrementErrorCounter()
+			return
+		}
+		setClusterServerInfo(clusterStatus, db)
 		e.setOvnClusterInfoMetric(clusterStatus, database)
 	}
 }
 
+func (e *Exporter) exportOvnDBTableRowsGauge() {
+	metricDBTableRows.Reset()
+	for _, database := range e.countedDatabases() {
+		rows, err := e.countTableRows(database)
+		if err != nil {
+			klog.Errorf("Failed to count table rows of database %s: %v", database, err)
+			e.IncrementErrorCounter()
+			continue
+		}
+		for table, count := range rows {
+			metricDBTableRows.WithLabelValues(e.Client.System.Hostname, database, table).Set(float64(count))
+		}
+	}
+}
+
 func (e *Exporter) exportOvnDBStatusGauge() {
 	metricDBStatus.Reset()
 	dbList := []string{"OVN_Northbound", "OVN_Southbound"}
@@ -235,6 +235,32 @@ var (
 			"cluster_id",
 		})
 
+	metricClusterConnected = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Name:      "cluster_connected",
+			Help:      "Is this server connected to the cluster (1) or not (0).",
+		},
+		[]string{
+			"hostname",
+			"db_name",
+			"server_id",
+			"cluster_id",
+		})
+
+	metricClusterIndex = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Name:      "cluster_index",
+			Help:      "The index of the latest change to the database seen by this server.",
+		},
+		[]string{
+			"hostname",
+			"db_name",
+			"server_id",
+			"cluster_id",
+		})
+
 	metricClusterElectionTimer = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: metricNamespace,
@@ -460,6 +486,54 @@ var (
 			"hostname",
 			"db_name",
 		})
+
+	metricDBTableRows = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Name:      "db_table_rows",
+			Help:      "The number of rows in the table of OVN NB/SB DB, the SB tables are counted only if enabled by --enable-sb-table-rows.",
+		},
+		[]string{
+			"hostname",
+			"db_name",
+			"table",
+		})
+
+	// OVN northd performance metrics
+	metricNorthdStopwatchSamples = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Name:      "northd_stopwatch_samples_total",
+			Help:      "The number of samples of the ovn-northd stopwatch.",
+		},
+		[]string{
+			"hostname",
+			"stopwatch",
+		})
+
+	metricNorthdStopwatchDuration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Name:      "northd_stopwatch_duration_ms",
+			Help:      "The statistics of the ovn-northd stopwatch, such as the duration of the main loop. The stat is one of max, min, p95, short_term_avg and long_term_avg. The unit is ms.",
+		},
+		[]string{
+			"hostname",
+			"stopwatch",
+			"stat",
+		})
+
+	metricNorthdIncEngineStats = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Name:      "northd_inc_engine_stats_total",
+			Help:      "The number of recompute, compute and cancel of the ovn-northd incremental processing engine node.",
+		},
+		[]string{
+			"hostname",
+			"node",
+			"stat",
+		})
 )
 
 func registerOvnMetrics() {
@@ -469,6 +543,12 @@ func registerOvnMetrics() {
 	prometheus.MustRegister(metricLogFileSize)
 	prometheus.MustRegister(metricDBFileSize)
 	prometheus.MustRegister(metricDBStatus)
+	prometheus.MustRegister(metricDBTableRows)
+
+	// ovn northd performance metrics
+	prometheus.MustRegister(metricNorthdStopwatchSamples)
+	prometheus.MustRegister(metricNorthdStopwatchDuration)
+	prometheus.MustRegister(metricNorthdIncEngineStats)
 
 	// ovn chassis metrics
 	prometheus.MustRegister(metricChassisInfo)
@@ -488,6 +568,8 @@ func registerOvnMetrics() {
 
 	prometheus.MustRegister(metricClusterLeaderSelf)
 	prometheus.MustRegister(metricClusterVoteSelf)
+	prometheus.MustRegister(metricClusterConnected)
+	prometheus.MustRegister(metricClusterIndex)
 	prometheus.MustRegister(metricClusterElectionTimer)
 	prometheus.MustRegister(metricClusterNotCommittedEntryCount)
 	prometheus.MustRegister(metricClusterNotAppliedEntryCount)
@@ -0,0 +1,204 @@
+package ovnmonitor
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb/serverdb"
+	"k8s.io/klog/v2"
+
+	ovsclient "github.com/kubeovn/kube-ovn/pkg/ovsdb/client"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnsb"
+)
+
+const (
+	nbDBName = "OVN_Northbound"
+	sbDBName = "OVN_Southbound"
+)
+
+// countedTables are the tables whose rows are counted from the monitored cache
+var countedTables = map[string][]string{
+	nbDBName: {
+		ovnnb.ACLTable,
+		ovnnb.AddressSetTable,
+		ovnnb.LoadBalancerTable,
+		ovnnb.LogicalRouterTable,
+		ovnnb.LogicalRouterPolicyTable,
+		ovnnb.LogicalRouterPortTable,
+		ovnnb.LogicalRouterStaticRouteTable,
+		ovnnb.LogicalSwitchTable,
+		ovnnb.LogicalSwitchPortTable,
+		ovnnb.NATTable,
+		ovnnb.PortGroupTable,
+	},
+	sbDBName: {
+		ovnsb.ChassisTable,
+		ovnsb.DatapathBindingTable,
+		ovnsb.LogicalFlowTable,
+		ovnsb.MACBindingTable,
+		ovnsb.MulticastGroupTable,
+		ovnsb.PortBindingTable,
+	},
+}
+
+// nbTableMonitors monitors only one column of each counted table to keep the cache small
+func nbTableMonitors() []client.MonitorOption {
+	acl, as, lb := &ovnnb.ACL{}, &ovnnb.AddressSet{}, &ovnnb.LoadBalancer{}
+	lr, lrPolicy, lrp, lrRoute := &ovnnb.LogicalRouter{}, &ovnnb.LogicalRouterPolicy{}, &ovnnb.LogicalRouterPort{}, &ovnnb.LogicalRouterStaticRoute{}
+	ls, lsp, nat, pg := &ovnnb.LogicalSwitch{}, &ovnnb.LogicalSwitchPort{}, &ovnnb.NAT{}, &ovnnb.PortGroup{}
+	return []client.MonitorOption{
+		client.WithTable(acl, &acl.Priority),
+		client.WithTable(as, &as.Name),
+		client.WithTable(lb, &lb.Name),
+		client.WithTable(lr, &lr.Name),
+		client.WithTable(lrPolicy, &lrPolicy.Priority),
+		client.WithTable(lrp, &lrp.Name),
+		client.WithTable(lrRoute, &lrRoute.IPPrefix),
+		client.WithTable(ls, &ls.Name),
+		client.WithTable(lsp, &lsp.Name),
+		client.WithTable(nat, &nat.Type),
+		client.WithTable(pg, &pg.Name),
+	}
+}
+
+// sbTableMonitors monitors only one column of each counted table to keep the cache small
+func sbTableMonitors() []client.MonitorOption {
+	chassis, dp, flow := &ovnsb.Chassis{}, &ovnsb.DatapathBinding{}, &ovnsb.LogicalFlow{}
+	mac, mg, pb := &ovnsb.MACBinding{}, &ovnsb.MulticastGroup{}, &ovnsb.PortBinding{}
+	return []client.MonitorOption{
+		client.WithTable(chassis, &chassis.Name),
+		client.WithTable(dp, &dp.TunnelKey),
+		client.WithTable(flow, &flow.Pipeline),
+		client.WithTable(mac, &mac.LogicalPort),
+		client.WithTable(mg, &mg.Name),
+		client.WithTable(pb, &pb.LogicalPort),
+	}
+}
+
+func newServerClient(addr string) (client.Client, error) {
+	dbModel, err := serverdb.FullDatabaseModel()
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	return ovsclient.NewOvsDbClient(ovsclient.SERVERDB, addr, dbModel, []client.MonitorOption{client.WithTable(&serverdb.Database{})})
+}
+
+func newDBClient(dbName, addr string) (client.Client, error) {
+	var db string
+	var dbModel model.ClientDBModel
+	var monitors []client.MonitorOption
+	var err error
+	switch dbName {
+	case nbDBName:
+		db, monitors = ovsclient.LOCALNBDB, nbTableMonitors()
+		dbModel, err = ovnnb.FullDatabaseModel()
+	case sbDBName:
+		db, monitors = ovsclient.LOCALSBDB, sbTableMonitors()
+		dbModel, err = ovnsb.FullDatabaseModel()
+	default:
+		return nil, fmt.Errorf("unsupported database %s", dbName)
+	}
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	return ovsclient.NewOvsDbClient(db, addr, dbModel, monitors)
+}
+
+// connectOvsdb connects to the _Server databases of the local ovsdb-servers and the NB/SB databases whose
+// table rows are counted, the clients which have been connected are reused
+func (e *Exporter) connectOvsdb() error {
+	e.Lock()
+	defer e.Unlock()
+
+	databases := map[string]string{
+		nbDBName: e.Client.Database.Northbound.Socket.Remote,
+		sbDBName: e.Client.Database.Southbound.Socket.Remote,
+	}
+	for dbName, addr := range databases {
+		if e.serverClients[dbName] == nil {
+			c, err := newServerClient(addr)
+			if err != nil {
+				klog.Errorf("failed to connect to _Server database of %s: %v", dbName, err)
+				return err
+			}
+			e.serverClients[dbName] = c
+		}
+		if e.dbClients[dbName] == nil && slices.Contains(e.countedDatabases(), dbName) {
+			c, err := newDBClient(dbName, addr)
+			if err != nil {
+				klog.Errorf("failed to connect to database %s: %v", dbName, err)
+				return err
+			}
+			e.dbClients[dbName] = c
+		}
+	}
+	return nil
+}
+
+// getServerDatabase returns the row of the database in the _Server database of the local ovsdb-server
+func (e *Exporter) getServerDatabase(dbName string) (*serverdb.Database, error) {
+	e.RLock()
+	c := e.serverClients[dbName]
+	e.RUnlock()
+	if c == nil || !c.Connected() {
+		return nil, fmt.Errorf("not connected to _Server database of %s", dbName)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(e.timeout)*time.Second)
+	defer cancel()
+	var databases []serverdb.Database
+	if err := c.WhereCache(func(db *serverdb.Database) bool { return db.Name == dbName }).List(ctx, &databases); err != nil {
+		return nil, fmt.Errorf("failed to list _Server database: %v", err)
+	}
+	if len(databases) == 0 {
+		return nil, fmt.Errorf("database %s not found in _Server database", dbName)
+	}
+	return &databases[0], nil
+}
+
+// countedDatabases returns the databases whose table rows are counted, the SB ones are counted only if enabled
+// since the monitors of the logical flows, port bindings and mac bindings are costly in large clusters
+func (e *Exporter) countedDatabases() []string {
+	if e.enableSBTableRows {
+		return []string{nbDBName, sbDBName}
+	}
+	return []string{nbDBName}
+}
+
+// countTableRows returns the number of rows of the counted tables in the database
+func (e *Exporter) countTableRows(dbName string) (map[string]int, error) {
+	e.RLock()
+	c := e.dbClients[dbName]
+	e.RUnlock()
+	if c == nil || !c.Connected() {
+		return nil, fmt.Errorf("not connected to database %s", dbName)
+	}
+
+	result := make(map[string]int, len(countedTables[dbName]))
+	for _, table := range countedTables[dbName] {
+		if rows := c.Cache().Table(table); rows != nil {
+			result[table] = rows.Len()
+		}
+	}
+	return result, nil
+}
+
+// dbStatus returns (1) for the leader or the standalone server, (2) for the follower
+// and (0) for the server which is disconnected from the cluster
+func dbStatus(db *serverdb.Database) int {
+	switch {
+	case !db.Connected:
+		return 0
+	case db.Leader:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,214 @@
+package ovnmonitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/greenpau/ovsdb"
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/database/inmemory"
+	"github.com/ovn-org/libovsdb/model"
+	ovsdbapi "github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/ovn-org/libovsdb/ovsdb/serverdb"
+	"github.com/ovn-org/libovsdb/server"
+	"github.com/stretchr/testify/require"
+
+	ovsclient "github.com/kubeovn/kube-ovn/pkg/ovsdb/client"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnsb"
+)
+
+func newOVSDBServer(t *testing.T, dbModel model.ClientDBModel, schema ovsdbapi.DatabaseSchema) string {
+	serverDBModel, err := serverdb.FullDatabaseModel()
+	require.NoError(t, err)
+	serverSchema := serverdb.Schema()
+
+	db := inmemory.NewDatabase(map[string]model.ClientDBModel{
+		schema.Name:       dbModel,
+		serverSchema.Name: serverDBModel,
+	})
+	dbMod, errs := model.NewDatabaseModel(schema, dbModel)
+	require.Empty(t, errs)
+	svrMod, errs := model.NewDatabaseModel(serverSchema, serverDBModel)
+	require.Empty(t, errs)
+
+	s, err := server.NewOvsdbServer(db, dbMod, svrMod)
+	require.NoError(t, err)
+
+	sock := fmt.Sprintf("/tmp/ovnmonitor-%d.sock", rand.IntN(100000))
+	t.Cleanup(func() {
+		os.Remove(sock)
+	})
+	go func() {
+		if err := s.Serve("unix", sock); err != nil {
+			t.Error(err)
+		}
+	}()
+	t.Cleanup(s.Close)
+	require.Eventually(t, s.Ready, time.Second, 10*time.Millisecond)
+
+	return "unix:" + sock
+}
+
+// insertServerDatabases inserts the rows of the _Server Database table recorded by
+// `ovsdb-client transact '["_Server",{"op":"select","table":"Database","where":[]}]'`
+func insertServerDatabases(t *testing.T, c client.Client, fixture string) {
+	data, err := os.ReadFile(fixture)
+	require.NoError(t, err)
+	var results []ovsdbapi.OperationResult
+	require.NoError(t, json.Unmarshal(data, &results))
+	require.Len(t, results, 1)
+
+	ops := make([]ovsdbapi.Operation, 0, len(results[0].Rows))
+	for _, row := range results[0].Rows {
+		delete(row, "_uuid")
+		ops = append(ops, ovsdbapi.Operation{Op: ovsdbapi.OperationInsert, Table: serverdb.DatabaseTable, Row: row})
+	}
+	_, err = c.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+}
+
+func newTestExporter(t *testing.T, enableSBTableRows bool) *Exporter {
+	nbModel, err := ovnnb.FullDatabaseModel()
+	require.NoError(t, err)
+	sbModel, err := ovnsb.FullDatabaseModel()
+	require.NoError(t, err)
+
+	e := NewExporter(&Configuration{PollTimeout: 2, EnableSBTableRows: enableSBTableRows})
+	e.Client = ovsdb.NewOvnClient()
+	e.Client.Database.Northbound.Socket.Remote = newOVSDBServer(t, nbModel, ovnnb.Schema())
+	e.Client.Database.Southbound.Socket.Remote = newOVSDBServer(t, sbModel, ovnsb.Schema())
+	require.NoError(t, e.connectOvsdb())
+	t.Cleanup(func() {
+		for _, c := range e.serverClients {
+			c.Close()
+		}
+		for _, c := range e.dbClients {
+			c.Close()
+		}
+	})
+	return e
+}
+
+func TestServerDatabase(t *testing.T) {
+	e := newTestExporter(t, false)
+
+	_, err := e.getServerDatabase(nbDBName)
+	require.Error(t, err)
+	_, err = e.getClusterEnableState(nbDBName)
+	require.Error(t, err)
+
+	insertServerDatabases(t, e.serverClients[nbDBName], "testdata/server-database-nb.json")
+	require.Eventually(t, func() bool {
+		_, err := e.getServerDatabase(nbDBName)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	db, err := e.getServerDatabase(nbDBName)
+	require.NoError(t, err)
+	require.Equal(t, nbDBName, db.Name)
+	require.Equal(t, serverdb.DatabaseModelClustered, db.Model)
+	require.True(t, db.Connected)
+	require.False(t, db.Leader)
+	require.NotNil(t, db.Index)
+	require.Equal(t, 1108, *db.Index)
+	require.Equal(t, 2, dbStatus(db))
+
+	clustered, err := e.getClusterEnableState(nbDBName)
+	require.NoError(t, err)
+	require.True(t, clustered)
+
+	c := &OVNDBClusterStatus{}
+	setClusterServerInfo(c, db)
+	require.Equal(t, &OVNDBClusterStatus{
+		cid:       "45ef51b9-9401-46e7-810d-6db0fc344ea2",
+		sid:       "8d5e6a1b-6b6d-4b6e-9f3b-2d3c3e7a9c11",
+		connected: true,
+		index:     1108,
+	}, c)
+
+	_, err = e.getServerDatabase(sbDBName)
+	require.Error(t, err)
+}
+
+func TestDBStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		db   serverdb.Database
+		want int
+	}{
+		{"leader", serverdb.Database{Model: serverdb.DatabaseModelClustered, Connected: true, Leader: true}, 1},
+		{"follower", serverdb.Database{Model: serverdb.DatabaseModelClustered, Connected: true}, 2},
+		{"disconnected", serverdb.Database{Model: serverdb.DatabaseModelClustered, Leader: true}, 0},
+		{"standalone", serverdb.Database{Model: serverdb.DatabaseModelStandalone, Connected: true, Leader: true}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, dbStatus(&tt.db))
+		})
+	}
+}
+
+func TestCountTableRows(t *testing.T) {
+	e := newTestExporter(t, true)
+
+	// the logical switch port is not a root table and must be referenced by a logical switch
+	lsp := &ovnnb.LogicalSwitchPort{UUID: ovsclient.NamedUUID(), Name: "lsp1"}
+	nbClient := e.dbClients[nbDBName]
+	ops, err := nbClient.Create(
+		lsp,
+		&ovnnb.LogicalSwitch{Name: "ls1", Ports: []string{lsp.UUID}},
+		&ovnnb.LogicalSwitch{Name: "ls2"},
+		&ovnnb.LogicalRouter{Name: "lr1"},
+	)
+	require.NoError(t, err)
+	_, err = nbClient.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+
+	sbClient := e.dbClients[sbDBName]
+	ops, err = sbClient.Create(&ovnsb.Chassis{Name: "chassis1", Hostname: "node1"})
+	require.NoError(t, err)
+	_, err = sbClient.Transact(context.Background(), ops...)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		rows, err := e.countTableRows(nbDBName)
+		return err == nil && rows[ovnnb.LogicalSwitchTable] == 2
+	}, time.Second, 10*time.Millisecond)
+
+	rows, err := e.countTableRows(nbDBName)
+	require.NoError(t, err)
+	require.Len(t, rows, len(countedTables[nbDBName]))
+	require.Equal(t, 2, rows[ovnnb.LogicalSwitchTable])
+	require.Equal(t, 1, rows[ovnnb.LogicalSwitchPortTable])
+	require.Equal(t, 1, rows[ovnnb.LogicalRouterTable])
+	require.Zero(t, rows[ovnnb.ACLTable])
+
+	require.Eventually(t, func() bool {
+		rows, err := e.countTableRows(sbDBName)
+		return err == nil && rows[ovnsb.ChassisTable] == 1
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = e.countTableRows("OVN_IC_Northbound")
+	require.Error(t, err)
+}
+
+func TestCountTableRowsSBDisabled(t *testing.T) {
+	e := newTestExporter(t, false)
+
+	require.Equal(t, []string{nbDBName}, e.countedDatabases())
+	require.NotNil(t, e.serverClients[sbDBName])
+	require.Nil(t, e.dbClients[sbDBName])
+	_, err := e.countTableRows(sbDBName)
+	require.Error(t, err)
+
+	require.Eventually(t, func() bool {
+		_, err := e.countTableRows(nbDBName)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+}
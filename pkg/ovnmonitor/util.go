@@ -9,7 +9,10 @@ import (
 	"sync/atomic"
 
 	"github.com/greenpau/ovsdb"
+	"github.com/ovn-org/libovsdb/ovsdb/serverdb"
 	"k8s.io/klog/v2"
+
+	"github.com/kubeovn/kube-ovn/pkg/util"
 )
 
 // IncrementErrorCounter increases the counter of failed queries to OVN server.
@@ -22,25 +25,16 @@ func (e *Exporter) IncrementErrorCounter() {
 func (e *Exporter) getOvnStatus() map[string]int {
 	result := make(map[string]int)
 
-	// get ovn-northbound status
-	cmdstr := "ovs-appctl -t /var/run/ovn/ovnnb_db.ctl cluster/status OVN_Northbound"
-	cmd := exec.Command("sh", "-c", cmdstr)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		klog.Errorf("get ovn-northbound status failed, err %v", err)
-		result["ovsdb-server-northbound"] = 0
-	}
-	result["ovsdb-server-northbound"] = parseDbStatus(string(output))
-
-	// get ovn-southbound status
-	cmdstr = "ovs-appctl -t /var/run/ovn/ovnsb_db.ctl cluster/status OVN_Southbound"
-	cmd = exec.Command("sh", "-c", cmdstr)
-	output, err = cmd.CombinedOutput()
-	if err != nil {
-		klog.Errorf("get ovn-southbound status failed, err %v", err)
-		result["ovsdb-server-southbound"] = 0
+	// get ovn-northbound and ovn-southbound status
+	for component, database := range map[string]string{"ovsdb-server-northbound": nbDBName, "ovsdb-server-southbound": sbDBName} {
+		db, err := e.getServerDatabase(database)
+		if err != nil {
+			klog.Errorf("get %s status failed, err %v", component, err)
+			result[component] = 0
+			continue
+		}
+		result[component] = dbStatus(db)
 	}
-	result["ovsdb-server-southbound"] = parseDbStatus(string(output))
 
 	// get ovn-northd status
 	pid, err := os.ReadFile("/var/run/ovn/ovn-northd.pid")
@@ -71,15 +65,59 @@ func (e *Exporter) getOvnStatus() map[string]int {
 	return result
 }
 
-func getClusterEnableState(dbName string) (bool, error) {
-	cmdstr := fmt.Sprintf("ovsdb-tool db-is-clustered %s", dbName)
-	cmd := exec.Command("sh", "-c", cmdstr)
-	_, err := cmd.CombinedOutput()
+// northdAppctl runs the appctl command against the ovn-northd process
+func northdAppctl(command string) (string, error) {
+	pid, err := os.ReadFile("/var/run/ovn/ovn-northd.pid")
+	if err != nil {
+		return "", fmt.Errorf("failed to read ovn-northd pid: %v", err)
+	}
+	cmdstr := fmt.Sprintf("ovs-appctl -t /var/run/ovn/ovn-northd.%s.ctl %s", strings.TrimSpace(string(pid)), command)
+	klog.V(3).Infof("cmd is %v", cmdstr)
+	output, err := exec.Command("sh", "-c", cmdstr).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %q: %v, %q", cmdstr, err, output)
+	}
+	return string(output), nil
+}
+
+func (e *Exporter) setNorthdStopwatchMetric() {
+	output, err := northdAppctl("stopwatch/show")
+	if err != nil {
+		klog.Errorf("failed to get ovn-northd stopwatch statistics: %v", err)
+		e.IncrementErrorCounter()
+		return
+	}
+	for name, stats := range util.ParseStopwatchStats(output) {
+		metricNorthdStopwatchSamples.WithLabelValues(e.Client.System.Hostname, name).Set(stats.Samples)
+		metricNorthdStopwatchDuration.WithLabelValues(e.Client.System.Hostname, name, "max").Set(stats.Maximum)
+		metricNorthdStopwatchDuration.WithLabelValues(e.Client.System.Hostname, name, "min").Set(stats.Minimum)
+		metricNorthdStopwatchDuration.WithLabelValues(e.Client.System.Hostname, name, "p95").Set(stats.P95)
+		metricNorthdStopwatchDuration.WithLabelValues(e.Client.System.Hostname, name, "short_term_avg").Set(stats.ShortTermAvg)
+		metricNorthdStopwatchDuration.WithLabelValues(e.Client.System.Hostname, name, "long_term_avg").Set(stats.LongTermAvg)
+	}
+}
+
+func (e *Exporter) setNorthdIncEngineMetric() {
+	output, err := northdAppctl("inc-engine/show-stats")
+	if err != nil {
+		klog.Errorf("failed to get ovn-northd incremental processing engine statistics: %v", err)
+		e.IncrementErrorCounter()
+		return
+	}
+	for node, counters := range util.ParseIncEngineStats(output) {
+		for stat, value := range counters {
+			metricNorthdIncEngineStats.WithLabelValues(e.Client.System.Hostname, node, stat).Set(value)
+		}
+	}
+}
+
+func (e *Exporter) getClusterEnableState(dbName string) (bool, error) {
+	db, err := e.getServerDatabase(dbName)
 	if err != nil {
 		klog.Error(err)
 		return false, err
 	}
-	return true, nil
+	return db.Model == serverdb.DatabaseModelClustered, nil
 }
 
 func (e *Exporter) setLogicalSwitchInfoMetric() {
@@ -146,28 +184,25 @@ func (e *Exporter) setLogicalSwitchPortInfoMetric() {
 	}
 }
 
+// getClusterInfo gets the raft status which is not available in the _Server database, such as the term and the log
 func getClusterInfo(direction, dbName string) (*OVNDBClusterStatus, error) {
-	clusterStatus := &OVNDBClusterStatus{}
-	var err error
-
 	cmdstr := fmt.Sprintf("ovs-appctl -t /var/run/ovn/ovn%s_db.ctl cluster/status %s", direction, dbName)
 	cmd := exec.Command("sh", "-c", cmdstr)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve cluster/status info for database %s: %v", dbName, err)
 	}
+	return parseClusterStatus(string(output)), nil
+}
 
-	for _, line := range strings.Split(string(output), "\n") {
+func parseClusterStatus(output string) *OVNDBClusterStatus {
+	clusterStatus := &OVNDBClusterStatus{}
+	for _, line := range strings.Split(output, "\n") {
 		idx := strings.Index(line, ":")
 		if idx == -1 {
 			continue
 		}
 		switch line[:idx] {
-		case "Cluster ID":
-			// the value is of the format `45ef (45ef51b9-9401-46e7-810d-6db0fc344ea2)`
-			clusterStatus.cid = strings.Trim(strings.Fields(line[idx+2:])[1], "()")
-		case "Server ID":
-			clusterStatus.sid = strings.Trim(strings.Fields(line[idx+2:])[1], "()")
 		case "Status":
 			clusterStatus.status = line[idx+2:]
 		case "Role":
@@ -176,8 +211,6 @@ func getClusterInfo(direction, dbName string) (*OVNDBClusterStatus, error) {
 			if value, err := strconv.ParseFloat(line[idx+2:], 64); err == nil {
 				clusterStatus.term = value
 			}
-		case "Leader":
-			clusterStatus.leader = line[idx+2:]
 		case "Vote":
 			clusterStatus.vote = line[idx+2:]
 		case "Election timer":
@@ -226,7 +259,22 @@ func getClusterInfo(direction, dbName string) (*OVNDBClusterStatus, error) {
 		}
 	}
 
-	return clusterStatus, nil
+	return clusterStatus
+}
+
+// setClusterServerInfo sets the fields of the cluster status which are available in the _Server database
+func setClusterServerInfo(c *OVNDBClusterStatus, db *serverdb.Database) {
+	if db.Cid != nil {
+		c.cid = *db.Cid
+	}
+	if db.Sid != nil {
+		c.sid = *db.Sid
+	}
+	c.leader = db.Leader
+	c.connected = db.Connected
+	if db.Index != nil {
+		c.index = float64(*db.Index)
+	}
 }
 
 func (e *Exporter) setOvnClusterInfoMetric(c *OVNDBClusterStatus, dbName string) {
@@ -234,7 +282,7 @@ func (e *Exporter) setOvnClusterInfoMetric(c *OVNDBClusterStatus, dbName string)
 	metricClusterStatus.WithLabelValues(e.Client.System.Hostname, dbName, c.sid, c.cid, c.status).Set(1)
 	metricClusterTerm.WithLabelValues(e.Client.System.Hostname, dbName, c.sid, c.cid).Set(c.term)
 
-	if c.leader == "self" {
+	if c.leader {
 		metricClusterLeaderSelf.WithLabelValues(e.Client.System.Hostname, dbName, c.sid, c.cid).Set(1)
 	} else {
 		metricClusterLeaderSelf.WithLabelValues(e.Client.System.Hostname, dbName, c.sid, c.cid).Set(0)
@@ -245,6 +293,13 @@ func (e *Exporter) setOvnClusterInfoMetric(c *OVNDBClusterStatus, dbName string)
 		metricClusterVoteSelf.WithLabelValues(e.Client.System.Hostname, dbName, c.sid, c.cid).Set(0)
 	}
 
+	if c.connected {
+		metricClusterConnected.WithLabelValues(e.Client.System.Hostname, dbName, c.sid, c.cid).Set(1)
+	} else {
+		metricClusterConnected.WithLabelValues(e.Client.System.Hostname, dbName, c.sid, c.cid).Set(0)
+	}
+	metricClusterIndex.WithLabelValues(e.Client.System.Hostname, dbName, c.sid, c.cid).Set(c.index)
+
 	metricClusterElectionTimer.WithLabelValues(e.Client.System.Hostname, dbName, c.sid, c.cid).Set(c.electionTimer)
 	metricClusterNotCommittedEntryCount.WithLabelValues(e.Client.System.Hostname, dbName, c.sid, c.cid).Set(c.logNotCommitted)
 	metricClusterNotAppliedEntryCount.WithLabelValues(e.Client.System.Hostname, dbName, c.sid, c.cid).Set(c.logNotApplied)
@@ -257,28 +312,6 @@ func (e *Exporter) setOvnClusterInfoMetric(c *OVNDBClusterStatus, dbName string)
 	metricClusterOutConnErrTotal.WithLabelValues(e.Client.System.Hostname, dbName, c.sid, c.cid).Set(c.connOutErr)
 }
 
-func parseDbStatus(output string) int {
-	var status string
-	var result int
-	lines := strings.Split(output, "\n")
-	for _, line := range lines {
-		if strings.Contains(line, "Role:") {
-			status = strings.TrimSpace(strings.Split(line, ":")[1])
-			break
-		}
-	}
-
-	switch status {
-	case "leader":
-		result = 1
-	case "follower":
-		result = 2
-	default:
-		result = 0
-	}
-	return result
-}
-
 func getDBStatus(dbName string) (bool, error) {
 	var cmdstr string
 	var result bool
@@ -329,6 +362,8 @@ func resetOvnClusterMetrics() {
 	metricClusterTerm.Reset()
 	metricClusterLeaderSelf.Reset()
 	metricClusterVoteSelf.Reset()
+	metricClusterConnected.Reset()
+	metricClusterIndex.Reset()
 
 	metricClusterElectionTimer.Reset()
 	metricClusterNotCommittedEntryCount.Reset()
@@ -341,3 +376,9 @@ func resetOvnClusterMetrics() {
 	metricClusterInConnErrTotal.Reset()
 	metricClusterOutConnErrTotal.Reset()
 }
+
+func resetNorthdPerformanceMetrics() {
+	metricNorthdStopwatchSamples.Reset()
+	metricNorthdStopwatchDuration.Reset()
+	metricNorthdIncEngineStats.Reset()
+}
@@ -0,0 +1,38 @@
+package ovnmonitor
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ovn-org/libovsdb/ovsdb/serverdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseClusterStatus(t *testing.T) {
+	output, err := os.ReadFile("testdata/cluster-status-nb.txt")
+	require.NoError(t, err)
+
+	require.Equal(t, &OVNDBClusterStatus{
+		status:          "cluster member",
+		role:            "follower",
+		vote:            "46ac",
+		term:            3,
+		electionTimer:   5000,
+		logIndexStart:   2,
+		logIndexNext:    1108,
+		logNotCommitted: 0,
+		logNotApplied:   1,
+		connIn:          2,
+		connOut:         1,
+		connInErr:       0,
+		connOutErr:      1,
+	}, parseClusterStatus(string(output)))
+	require.Equal(t, &OVNDBClusterStatus{}, parseClusterStatus(""))
+}
+
+func TestSetClusterServerInfo(t *testing.T) {
+	cid, sid, index := "45ef51b9-9401-46e7-810d-6db0fc344ea2", "8d5e6a1b-6b6d-4b6e-9f3b-2d3c3e7a9c11", 1108
+	c := &OVNDBClusterStatus{role: "leader"}
+	setClusterServerInfo(c, &serverdb.Database{Cid: &cid, Sid: &sid, Index: &index, Leader: true, Connected: true})
+	require.Equal(t, &OVNDBClusterStatus{cid: cid, sid: sid, role: "leader", leader: true, connected: true, index: 1108}, c)
+}
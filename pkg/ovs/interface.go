@@ -6,6 +6,8 @@ import (
 	"github.com/ovn-org/libovsdb/ovsdb"
 
 	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnicnb"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnicsb"
 	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
 	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnsb"
 	"github.com/kubeovn/kube-ovn/pkg/util"
@@ -15,7 +17,7 @@ type NBGlobal interface {
 	UpdateNbGlobal(nbGlobal *ovnnb.NBGlobal, fields ...interface{}) error
 	SetAzName(azName string) error
 	SetUseCtInvMatch() error
-	SetICAutoRoute(enable bool, blackList []string) error
+	SetICAutoRoute(enable, learn bool, blackList []string) error
 	SetLsDnatModDlDst(enabled bool) error
 	SetLsCtSkipDstLportIPs(enabled bool) error
 	GetNbGlobal() (*ovnnb.NBGlobal, error)
@@ -35,6 +37,7 @@ type LogicalRouterPort interface {
 	CreateLogicalRouterPort(lrName, lrpName, mac string, networks []string) error
 	UpdateLogicalRouterPortRA(lrpName, ipv6RAConfigsStr string, enableIPv6RA bool) error
 	UpdateLogicalRouterPortOptions(lrpName string, options map[string]string) error
+	UpdateLogicalRouterPortNetworks(lrpName string, networks []string) error
 	DeleteLogicalRouterPort(lrpName string) error
 	DeleteLogicalRouterPorts(externalIDs map[string]string, filter func(lrp *ovnnb.LogicalRouterPort) bool) error
 	GetLogicalRouterPort(lrpName string, ignoreNotFound bool) (*ovnnb.LogicalRouterPort, error)
@@ -80,6 +83,8 @@ type LogicalSwitchPort interface {
 	SetLogicalSwitchPortSecurity(portSecurity bool, lspName, mac, ips, vips string) error
 	SetLogicalSwitchPortVirtualParents(lsName, parents string, ips ...string) error
 	SetLogicalSwitchPortArpProxy(lspName string, enableArpProxy bool) error
+	SetLogicalSwitchPortMigrateOptions(lspName, srcChassis, targetChassis string) error
+	ResetLogicalSwitchPortMigrateOptions(lspName string) error
 	SetLogicalSwitchPortExternalIDs(lspName string, externalIDs map[string]string) error
 	SetLogicalSwitchPortVlanTag(lspName string, vlanID int) error
 	SetLogicalSwitchPortsSecurityGroup(sgName, op string) error
@@ -97,9 +102,12 @@ type LoadBalancer interface {
 	CreateLoadBalancer(lbName, protocol, selectFields string) error
 	LoadBalancerAddVip(lbName, vip string, backends ...string) error
 	LoadBalancerDeleteVip(lbName, vip string, ignoreHealthCheck bool) error
+	LoadBalancerAddVips(lbName string, vips map[string]string) error
+	LoadBalancerDeleteVips(lbName string, vips []string) error
 	LoadBalancerAddIPPortMapping(lbName, vip string, ipPortMappings map[string]string) error
 	LoadBalancerUpdateIPPortMapping(lbName, vip string, ipPortMappings map[string]string) error
 	LoadBalancerDeleteIPPortMapping(lbName, vip string) error
+	LoadBalancerDeleteIPPortMappingByIPs(lbName string, ips ...string) error
 	LoadBalancerAddHealthCheck(lbName, vip string, ignoreHealthCheck bool, ipPortMapping, externals map[string]string) error
 	LoadBalancerDeleteHealthCheck(lbName, uuid string) error
 	SetLoadBalancerAffinityTimeout(lbName string, timeout int) error
@@ -141,6 +149,7 @@ type ACL interface {
 	UpdateLogicalSwitchACL(lsName, cidrBlock string, subnetAcls []kubeovnv1.ACL, allowEWTraffic bool) error
 	SetACLLog(pgName, protocol string, logEnable, isIngress bool) error
 	SetLogicalSwitchPrivate(lsName, cidrBlock, nodeSwitchCIDR string, allowSubnets []string) error
+	ListAcls(direction string, externalIDs map[string]string) ([]ovnnb.ACL, error)
 	DeleteAcls(parentName, parentType, direction string, externalIDs map[string]string) error
 	DeleteAclsOps(parentName, parentType, direction string, externalIDs map[string]string) ([]ovsdb.Operation, error)
 }
@@ -154,6 +163,7 @@ type AddressSet interface {
 }
 
 type LogicalRouterStaticRoute interface {
+	CreateLogicalRouterStaticRoutes(lrName string, routes ...*ovnnb.LogicalRouterStaticRoute) error
 	AddLogicalRouterStaticRoute(lrName, routeTable, policy, ipPrefix string, bfdID *string, nexthops ...string) error
 	ClearLogicalRouterStaticRoute(lrName string) error
 	DeleteLogicalRouterStaticRoute(lrName string, routeTable, policy *string, ipPrefix, nextHop string) error
@@ -192,6 +202,20 @@ type DHCPOptions interface {
 	ListDHCPOptions(needVendorFilter bool, externalIDs map[string]string) ([]ovnnb.DHCPOptions, error)
 }
 
+type QoS interface {
+	AddQoS(lsName, direction string, priority int, match string, action, bandwidth map[string]int, externalIDs map[string]string) error
+	DeleteQoSs(lsName string, externalIDs map[string]string, filter func(qos *ovnnb.QoS) bool) error
+	ListQoSs(lsName string, externalIDs map[string]string) ([]*ovnnb.QoS, error)
+}
+
+type Mirror interface {
+	CreateOrUpdateMirror(name, filter, sink, mirrorType string, index int, externalIDs map[string]string) error
+	DeleteMirror(name string) error
+	GetMirror(name string, ignoreNotFound bool) (*ovnnb.Mirror, error)
+	ListMirrors(externalIDs map[string]string) ([]ovnnb.Mirror, error)
+	LogicalSwitchPortUpdateMirrors(lspName string, op ovsdb.Mutator, mirrorNames ...string) error
+}
+
 type NbClient interface {
 	ACL
 	AddressSet
@@ -206,9 +230,11 @@ type NbClient interface {
 	LogicalRouter
 	LogicalSwitchPort
 	LogicalSwitch
+	Mirror
 	NAT
 	NBGlobal
 	PortGroup
+	QoS
 	CreateGatewayLogicalSwitch(lsName, lrName, provider, ip, mac string, vlanID int, chassises ...string) error
 	CreateLogicalPatchPort(lsName, lrName, lspName, lrpName, ip, mac string, chassises ...string) error
 	RemoveLogicalPatchPort(lspName, lrpName string) error
@@ -222,6 +248,18 @@ type SbClient interface {
 	Common
 }
 
+type IcNbClient interface {
+	TransitSwitch
+	Common
+	Close()
+}
+
+type IcSbClient interface {
+	AvailabilityZone
+	Common
+	Close()
+}
+
 type Common interface {
 	Transact(method string, operations []ovsdb.Operation) error
 	GetEntityInfo(entity interface{}) error
@@ -238,3 +276,21 @@ type Chassis interface {
 	UpdateChassis(chassis *ovnsb.Chassis, fields ...interface{}) error
 	ListChassis() (*[]ovnsb.Chassis, error)
 }
+
+type TransitSwitch interface {
+	CreateTransitSwitch(tsName string, externalIDs map[string]string) error
+	DeleteTransitSwitch(tsName string) error
+	GetTransitSwitch(tsName string, ignoreNotFound bool) (*ovnicnb.TransitSwitch, error)
+	ListTransitSwitches(filter func(ts *ovnicnb.TransitSwitch) bool) ([]ovnicnb.TransitSwitch, error)
+	SetTransitSwitchExternalIDs(tsName string, externalIDs map[string]string) error
+	RemoveTransitSwitchExternalIDs(tsName string, keys ...string) error
+}
+
+type AvailabilityZone interface {
+	GetAvailabilityZone(azName string, ignoreNotFound bool) (*ovnicsb.AvailabilityZone, error)
+	ListAvailabilityZones() ([]ovnicsb.AvailabilityZone, error)
+	ListGateways(azUUID string) ([]ovnicsb.Gateway, error)
+	ListRoutes(azUUID string) ([]ovnicsb.Route, error)
+	ListPortBindings(azUUID string) ([]ovnicsb.PortBinding, error)
+	DeleteAvailabilityZone(azName string) error
+}
@@ -0,0 +1,158 @@
+package ovs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnicnb"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// CreateTransitSwitch create transit switch with the external ids if it does not exist
+func (c *OVNIcNbClient) CreateTransitSwitch(tsName string, externalIDs map[string]string) error {
+	ts, err := c.GetTransitSwitch(tsName, true)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if ts != nil {
+		return nil
+	}
+
+	ts = &ovnicnb.TransitSwitch{
+		Name:        tsName,
+		ExternalIDs: map[string]string{"vendor": util.CniTypeName},
+	}
+	for k, v := range externalIDs {
+		ts.ExternalIDs[k] = v
+	}
+
+	op, err := c.ovsDbClient.Create(ts)
+	if err != nil {
+		klog.Error(err)
+		return fmt.Errorf("generate operations for creating transit switch %s: %v", tsName, err)
+	}
+	if err = c.Transact("ts-add", op); err != nil {
+		klog.Error(err)
+		return fmt.Errorf("create transit switch %s: %v", tsName, err)
+	}
+	return nil
+}
+
+// DeleteTransitSwitch delete transit switch, the logical switches in the availability zones are removed by ovn-ic
+func (c *OVNIcNbClient) DeleteTransitSwitch(tsName string) error {
+	ts, err := c.GetTransitSwitch(tsName, true)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if ts == nil {
+		return nil
+	}
+
+	op, err := c.ovsDbClient.Where(ts).Delete()
+	if err != nil {
+		klog.Error(err)
+		return fmt.Errorf("generate operations for deleting transit switch %s: %v", tsName, err)
+	}
+	if err = c.Transact("ts-del", op); err != nil {
+		klog.Error(err)
+		return fmt.Errorf("delete transit switch %s: %v", tsName, err)
+	}
+	return nil
+}
+
+// GetTransitSwitch get transit switch by name
+func (c *OVNIcNbClient) GetTransitSwitch(tsName string, ignoreNotFound bool) (*ovnicnb.TransitSwitch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	ts := &ovnicnb.TransitSwitch{Name: tsName}
+	if err := c.ovsDbClient.Get(ctx, ts); err != nil {
+		if ignoreNotFound && err == client.ErrNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get transit switch %s: %w", tsName, err)
+	}
+	return ts, nil
+}
+
+// ListTransitSwitches list transit switches which match the filter
+func (c *OVNIcNbClient) ListTransitSwitches(filter func(ts *ovnicnb.TransitSwitch) bool) ([]ovnicnb.TransitSwitch, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	tsList := make([]ovnicnb.TransitSwitch, 0)
+	if err := c.ovsDbClient.WhereCache(func(ts *ovnicnb.TransitSwitch) bool {
+		return filter == nil || filter(ts)
+	}).List(ctx, &tsList); err != nil {
+		return nil, fmt.Errorf("list transit switch: %v", err)
+	}
+	return tsList, nil
+}
+
+// SetTransitSwitchExternalIDs set the external ids of transit switch,
+// the other keys are kept since the transit switch is shared by the availability zones
+func (c *OVNIcNbClient) SetTransitSwitchExternalIDs(tsName string, externalIDs map[string]string) error {
+	if len(externalIDs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(externalIDs))
+	for k := range externalIDs {
+		keys = append(keys, k)
+	}
+	ops, err := c.transitSwitchUpdateExternalIDsOp(tsName, func(ts *ovnicnb.TransitSwitch) []model.Mutation {
+		return []model.Mutation{
+			{Field: &ts.ExternalIDs, Value: keys, Mutator: ovsdb.MutateOperationDelete},
+			{Field: &ts.ExternalIDs, Value: externalIDs, Mutator: ovsdb.MutateOperationInsert},
+		}
+	})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if err = c.Transact("ts-set-external-ids", ops); err != nil {
+		klog.Error(err)
+		return fmt.Errorf("set external ids of transit switch %s: %v", tsName, err)
+	}
+	return nil
+}
+
+// RemoveTransitSwitchExternalIDs remove the keys from the external ids of transit switch
+func (c *OVNIcNbClient) RemoveTransitSwitchExternalIDs(tsName string, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	ops, err := c.transitSwitchUpdateExternalIDsOp(tsName, func(ts *ovnicnb.TransitSwitch) []model.Mutation {
+		return []model.Mutation{{Field: &ts.ExternalIDs, Value: keys, Mutator: ovsdb.MutateOperationDelete}}
+	})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if err = c.Transact("ts-remove-external-ids", ops); err != nil {
+		klog.Error(err)
+		return fmt.Errorf("remove external ids of transit switch %s: %v", tsName, err)
+	}
+	return nil
+}
+
+func (c *OVNIcNbClient) transitSwitchUpdateExternalIDsOp(tsName string, mutationsFunc func(ts *ovnicnb.TransitSwitch) []model.Mutation) ([]ovsdb.Operation, error) {
+	ts, err := c.GetTransitSwitch(tsName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	ops, err := c.ovsDbClient.Where(ts).Mutate(ts, mutationsFunc(ts)...)
+	if err != nil {
+		return nil, fmt.Errorf("generate operations for mutating transit switch %s: %v", tsName, err)
+	}
+	return ops, nil
+}
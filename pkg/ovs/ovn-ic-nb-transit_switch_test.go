@@ -0,0 +1,129 @@
+package ovs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnicnb"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+func (suite *OvnClientTestSuite) testCreateTransitSwitch() {
+	t := suite.T()
+	t.Parallel()
+
+	ovnClient := suite.ovnIcNbClient
+	tsName := "test-create-ts"
+
+	err := ovnClient.CreateTransitSwitch(tsName, map[string]string{"subnet": "169.254.100.0/24"})
+	require.NoError(t, err)
+
+	ts, err := ovnClient.GetTransitSwitch(tsName, false)
+	require.NoError(t, err)
+	require.Equal(t, tsName, ts.Name)
+	require.Equal(t, "169.254.100.0/24", ts.ExternalIDs["subnet"])
+	require.Equal(t, util.CniTypeName, ts.ExternalIDs["vendor"])
+
+	t.Run("create existing transit switch", func(t *testing.T) {
+		err := ovnClient.CreateTransitSwitch(tsName, map[string]string{"subnet": "169.254.200.0/24"})
+		require.NoError(t, err)
+
+		ts, err := ovnClient.GetTransitSwitch(tsName, false)
+		require.NoError(t, err)
+		require.Equal(t, "169.254.100.0/24", ts.ExternalIDs["subnet"])
+	})
+}
+
+func (suite *OvnClientTestSuite) testDeleteTransitSwitch() {
+	t := suite.T()
+	t.Parallel()
+
+	ovnClient := suite.ovnIcNbClient
+	tsName := "test-delete-ts"
+
+	err := ovnClient.CreateTransitSwitch(tsName, nil)
+	require.NoError(t, err)
+
+	err = ovnClient.DeleteTransitSwitch(tsName)
+	require.NoError(t, err)
+
+	ts, err := ovnClient.GetTransitSwitch(tsName, true)
+	require.NoError(t, err)
+	require.Nil(t, ts)
+
+	_, err = ovnClient.GetTransitSwitch(tsName, false)
+	require.True(t, errors.Is(err, client.ErrNotFound))
+
+	t.Run("delete non-existent transit switch", func(t *testing.T) {
+		err := ovnClient.DeleteTransitSwitch(tsName)
+		require.NoError(t, err)
+	})
+}
+
+func (suite *OvnClientTestSuite) testListTransitSwitches() {
+	t := suite.T()
+	t.Parallel()
+
+	ovnClient := suite.ovnIcNbClient
+	prefix := "test-list-ts"
+
+	for _, name := range []string{prefix + "-1", prefix + "-2"} {
+		err := ovnClient.CreateTransitSwitch(name, nil)
+		require.NoError(t, err)
+	}
+
+	tss, err := ovnClient.ListTransitSwitches(func(ts *ovnicnb.TransitSwitch) bool {
+		return strings.HasPrefix(ts.Name, prefix)
+	})
+	require.NoError(t, err)
+	require.Len(t, tss, 2)
+
+	tss, err = ovnClient.ListTransitSwitches(nil)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(tss), 2)
+}
+
+func (suite *OvnClientTestSuite) testTransitSwitchExternalIDs() {
+	t := suite.T()
+	t.Parallel()
+
+	ovnClient := suite.ovnIcNbClient
+	tsName := "test-ts-external-ids"
+
+	err := ovnClient.CreateTransitSwitch(tsName, map[string]string{"subnet": "169.254.128.0/30"})
+	require.NoError(t, err)
+
+	t.Run("set external ids", func(t *testing.T) {
+		err := ovnClient.SetTransitSwitchExternalIDs(tsName, map[string]string{"vpc.az1": "vpc1", "prefixes.az1": "10.0.0.0/24,10.0.1.0/24"})
+		require.NoError(t, err)
+
+		err = ovnClient.SetTransitSwitchExternalIDs(tsName, map[string]string{"vpc.az1": "vpc2"})
+		require.NoError(t, err)
+
+		ts, err := ovnClient.GetTransitSwitch(tsName, false)
+		require.NoError(t, err)
+		require.Equal(t, "vpc2", ts.ExternalIDs["vpc.az1"])
+		require.Equal(t, "10.0.0.0/24,10.0.1.0/24", ts.ExternalIDs["prefixes.az1"])
+		require.Equal(t, "169.254.128.0/30", ts.ExternalIDs["subnet"])
+	})
+
+	t.Run("remove external ids", func(t *testing.T) {
+		err := ovnClient.RemoveTransitSwitchExternalIDs(tsName, "vpc.az1", "prefixes.az1")
+		require.NoError(t, err)
+
+		ts, err := ovnClient.GetTransitSwitch(tsName, false)
+		require.NoError(t, err)
+		require.NotContains(t, ts.ExternalIDs, "vpc.az1")
+		require.NotContains(t, ts.ExternalIDs, "prefixes.az1")
+		require.Equal(t, "169.254.128.0/30", ts.ExternalIDs["subnet"])
+	})
+
+	t.Run("set external ids of non-existent transit switch", func(t *testing.T) {
+		err := ovnClient.SetTransitSwitchExternalIDs("test-ts-non-existent", map[string]string{"vpc.az1": "vpc1"})
+		require.ErrorContains(t, err, "not found")
+	})
+}
@@ -0,0 +1,143 @@
+package ovs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"k8s.io/klog/v2"
+
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnicsb"
+)
+
+// GetAvailabilityZone get availability zone by name
+func (c *OVNIcSbClient) GetAvailabilityZone(azName string, ignoreNotFound bool) (*ovnicsb.AvailabilityZone, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	az := &ovnicsb.AvailabilityZone{Name: azName}
+	if err := c.ovsDbClient.Get(ctx, az); err != nil {
+		if ignoreNotFound && err == client.ErrNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get availability zone %s: %w", azName, err)
+	}
+	return az, nil
+}
+
+// ListAvailabilityZones list all the availability zones
+func (c *OVNIcSbClient) ListAvailabilityZones() ([]ovnicsb.AvailabilityZone, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	azs := make([]ovnicsb.AvailabilityZone, 0)
+	if err := c.ovsDbClient.List(ctx, &azs); err != nil {
+		return nil, fmt.Errorf("list availability zones: %v", err)
+	}
+	return azs, nil
+}
+
+// ListGateways list the gateways in the availability zone
+func (c *OVNIcSbClient) ListGateways(azUUID string) ([]ovnicsb.Gateway, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	gateways := make([]ovnicsb.Gateway, 0)
+	if err := c.ovsDbClient.WhereCache(func(gw *ovnicsb.Gateway) bool {
+		return gw.AvailabilityZone == azUUID
+	}).List(ctx, &gateways); err != nil {
+		return nil, fmt.Errorf("list gateways in availability zone %s: %v", azUUID, err)
+	}
+	return gateways, nil
+}
+
+// ListRoutes list the routes advertised by the availability zone
+func (c *OVNIcSbClient) ListRoutes(azUUID string) ([]ovnicsb.Route, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	routes := make([]ovnicsb.Route, 0)
+	if err := c.ovsDbClient.WhereCache(func(route *ovnicsb.Route) bool {
+		return route.AvailabilityZone == azUUID
+	}).List(ctx, &routes); err != nil {
+		return nil, fmt.Errorf("list routes in availability zone %s: %v", azUUID, err)
+	}
+	return routes, nil
+}
+
+// ListPortBindings list the port bindings in the availability zone
+func (c *OVNIcSbClient) ListPortBindings(azUUID string) ([]ovnicsb.PortBinding, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	portBindings := make([]ovnicsb.PortBinding, 0)
+	if err := c.ovsDbClient.WhereCache(func(pb *ovnicsb.PortBinding) bool {
+		return pb.AvailabilityZone == azUUID
+	}).List(ctx, &portBindings); err != nil {
+		return nil, fmt.Errorf("list port bindings in availability zone %s: %v", azUUID, err)
+	}
+	return portBindings, nil
+}
+
+// DeleteAvailabilityZone delete the availability zone with its port bindings, gateways and routes in one transaction
+func (c *OVNIcSbClient) DeleteAvailabilityZone(azName string) error {
+	az, err := c.GetAvailabilityZone(azName, true)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if az == nil {
+		return nil
+	}
+
+	portBindings, err := c.ListPortBindings(az.UUID)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	gateways, err := c.ListGateways(az.UUID)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	routes, err := c.ListRoutes(az.UUID)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	ops := make([]ovsdb.Operation, 0, len(portBindings)+len(gateways)+len(routes)+1)
+	for i := range portBindings {
+		op, err := c.ovsDbClient.Where(&portBindings[i]).Delete()
+		if err != nil {
+			return fmt.Errorf("generate operations for deleting port binding %s: %v", portBindings[i].LogicalPort, err)
+		}
+		ops = append(ops, op...)
+	}
+	for i := range gateways {
+		op, err := c.ovsDbClient.Where(&gateways[i]).Delete()
+		if err != nil {
+			return fmt.Errorf("generate operations for deleting gateway %s: %v", gateways[i].Name, err)
+		}
+		ops = append(ops, op...)
+	}
+	for i := range routes {
+		op, err := c.ovsDbClient.Where(&routes[i]).Delete()
+		if err != nil {
+			return fmt.Errorf("generate operations for deleting route %s: %v", routes[i].IPPrefix, err)
+		}
+		ops = append(ops, op...)
+	}
+	op, err := c.ovsDbClient.Where(az).Delete()
+	if err != nil {
+		return fmt.Errorf("generate operations for deleting availability zone %s: %v", azName, err)
+	}
+	ops = append(ops, op...)
+
+	if err = c.Transact("az-del", ops); err != nil {
+		klog.Error(err)
+		return fmt.Errorf("delete availability zone %s: %v", azName, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package ovs
+
+import (
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/require"
+
+	ovsclient "github.com/kubeovn/kube-ovn/pkg/ovsdb/client"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnicsb"
+)
+
+// createAvailabilityZone creates the availability zone with a gateway, a route and a port binding
+func (suite *OvnClientTestSuite) createAvailabilityZone(azName, ip string) {
+	t := suite.T()
+	ovnClient := suite.ovnIcSbClient
+
+	az := &ovnicsb.AvailabilityZone{UUID: ovsclient.NamedUUID(), Name: azName}
+	encap := &ovnicsb.Encap{UUID: ovsclient.NamedUUID(), Type: ovnicsb.EncapTypeGeneve, IP: ip, GatewayName: azName + "-gw"}
+	gw := &ovnicsb.Gateway{Name: azName + "-gw", AvailabilityZone: az.UUID, Hostname: azName + "-gw", Encaps: []string{encap.UUID}}
+	route := &ovnicsb.Route{TransitSwitch: "ts", AvailabilityZone: az.UUID, IPPrefix: "10.0.0.0/24", Nexthop: "169.254.100.1", Origin: ovnicsb.RouteOriginConnected}
+	pb := &ovnicsb.PortBinding{TransitSwitch: "ts", LogicalPort: "ts-" + azName, AvailabilityZone: az.UUID, TunnelKey: len(azName)}
+
+	var ops []ovsdb.Operation
+	for _, m := range []interface{}{az, encap, gw, route, pb} {
+		op, err := ovnClient.Create(m)
+		require.NoError(t, err)
+		ops = append(ops, op...)
+	}
+	require.NoError(t, ovnClient.Transact("az-add", ops))
+}
+
+func (suite *OvnClientTestSuite) testDeleteAvailabilityZone() {
+	t := suite.T()
+	t.Parallel()
+
+	ovnClient := suite.ovnIcSbClient
+	suite.createAvailabilityZone("az-del", "192.168.0.1")
+	suite.createAvailabilityZone("az-keep", "192.168.0.2")
+
+	az, err := ovnClient.GetAvailabilityZone("az-del", false)
+	require.NoError(t, err)
+	gateways, err := ovnClient.ListGateways(az.UUID)
+	require.NoError(t, err)
+	require.Len(t, gateways, 1)
+	routes, err := ovnClient.ListRoutes(az.UUID)
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	portBindings, err := ovnClient.ListPortBindings(az.UUID)
+	require.NoError(t, err)
+	require.Len(t, portBindings, 1)
+
+	err = ovnClient.DeleteAvailabilityZone("az-del")
+	require.NoError(t, err)
+
+	deleted, err := ovnClient.GetAvailabilityZone("az-del", true)
+	require.NoError(t, err)
+	require.Nil(t, deleted)
+	gateways, err = ovnClient.ListGateways(az.UUID)
+	require.NoError(t, err)
+	require.Empty(t, gateways)
+	routes, err = ovnClient.ListRoutes(az.UUID)
+	require.NoError(t, err)
+	require.Empty(t, routes)
+	portBindings, err = ovnClient.ListPortBindings(az.UUID)
+	require.NoError(t, err)
+	require.Empty(t, portBindings)
+
+	kept, err := ovnClient.GetAvailabilityZone("az-keep", false)
+	require.NoError(t, err)
+	azs, err := ovnClient.ListAvailabilityZones()
+	require.NoError(t, err)
+	require.Contains(t, azs, *kept)
+	require.NotContains(t, azs, *az)
+	gateways, err = ovnClient.ListGateways(kept.UUID)
+	require.NoError(t, err)
+	require.Len(t, gateways, 1)
+
+	// deleting non-existent availability zone is a no-op
+	err = ovnClient.DeleteAvailabilityZone("az-del")
+	require.NoError(t, err)
+}
@@ -192,6 +192,91 @@ func (c *OVNNbClient) LoadBalancerDeleteVip(lbName, vipEndpoint string, ignoreHe
 	return nil
 }
 
+// LoadBalancerAddVips adds the vips to the load balancer in one transaction, the values of vips are
+// the comma separated backends which replace the current ones
+func (c *OVNNbClient) LoadBalancerAddVips(lbName string, vips map[string]string) error {
+	if len(vips) == 0 {
+		return nil
+	}
+
+	ops, err := c.LoadBalancerOp(
+		lbName,
+		func(lb *ovnnb.LoadBalancer) []model.Mutation {
+			stale := make(map[string]string)
+			added := make(map[string]string, len(vips))
+			for vip, backends := range vips {
+				if current, ok := lb.Vips[vip]; ok {
+					if current == backends {
+						continue
+					}
+					stale[vip] = current
+				}
+				added[vip] = backends
+			}
+
+			mutations := make([]model.Mutation, 0, 2)
+			if len(stale) != 0 {
+				mutations = append(mutations, model.Mutation{Field: &lb.Vips, Value: stale, Mutator: ovsdb.MutateOperationDelete})
+			}
+			if len(added) != 0 {
+				mutations = append(mutations, model.Mutation{Field: &lb.Vips, Value: added, Mutator: ovsdb.MutateOperationInsert})
+			}
+			return mutations
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to generate operations when adding %d vips to load balancer %s: %v", len(vips), lbName, err)
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+
+	if err = c.Transact("lb-add", ops); err != nil {
+		return fmt.Errorf("failed to add %d vips to load balancer %s: %v", len(vips), lbName, err)
+	}
+	return nil
+}
+
+// LoadBalancerDeleteVips deletes the vips from the load balancer in one transaction,
+// the health checks of the vips are not cleaned
+func (c *OVNNbClient) LoadBalancerDeleteVips(lbName string, vips []string) error {
+	lb, err := c.GetLoadBalancer(lbName, true)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if lb == nil || len(lb.Vips) == 0 || len(vips) == 0 {
+		return nil
+	}
+
+	ops, err := c.LoadBalancerOp(
+		lbName,
+		func(lb *ovnnb.LoadBalancer) []model.Mutation {
+			deleted := make(map[string]string, len(vips))
+			for _, vip := range vips {
+				if backends, ok := lb.Vips[vip]; ok {
+					deleted[vip] = backends
+				}
+			}
+			if len(deleted) == 0 {
+				return nil
+			}
+			return []model.Mutation{{Field: &lb.Vips, Value: deleted, Mutator: ovsdb.MutateOperationDelete}}
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to generate operations when deleting %d vips from load balancer %s: %v", len(vips), lbName, err)
+	}
+	if len(ops) == 0 {
+		return nil
+	}
+
+	if err = c.Transact("lb-del", ops); err != nil {
+		return fmt.Errorf("failed to delete %d vips from load balancer %s: %v", len(vips), lbName, err)
+	}
+	return nil
+}
+
 // SetLoadBalancerAffinityTimeout sets the LB's affinity timeout in seconds
 func (c *OVNNbClient) SetLoadBalancerAffinityTimeout(lbName string, timeout int) error {
 	var (
@@ -484,6 +569,53 @@ func (c *OVNNbClient) LoadBalancerDeleteIPPortMapping(lbName, vipEndpoint string
 	return nil
 }
 
+// LoadBalancerDeleteIPPortMappingByIPs delete load balancer ip port mappings of the backend ips
+func (c *OVNNbClient) LoadBalancerDeleteIPPortMappingByIPs(lbName string, ips ...string) error {
+	var (
+		ops      []ovsdb.Operation
+		lb       *ovnnb.LoadBalancer
+		mappings = make(map[string]string, len(ips))
+		err      error
+	)
+
+	if lb, err = c.GetLoadBalancer(lbName, true); err != nil {
+		klog.Errorf("failed to get lb %s: %v", lbName, err)
+		return err
+	}
+	if lb == nil {
+		klog.Infof("lb %s already deleted", lbName)
+		return nil
+	}
+
+	for _, ip := range ips {
+		if mapping, ok := lb.IPPortMappings[ip]; ok {
+			mappings[ip] = mapping
+		}
+	}
+	if len(mappings) == 0 {
+		return nil
+	}
+
+	if ops, err = c.LoadBalancerOp(
+		lbName,
+		func(lb *ovnnb.LoadBalancer) []model.Mutation {
+			return []model.Mutation{
+				{
+					Field:   &lb.IPPortMappings,
+					Value:   mappings,
+					Mutator: ovsdb.MutateOperationDelete,
+				},
+			}
+		},
+	); err != nil {
+		return fmt.Errorf("failed to generate operations when deleting ip port mappings of %v from load balancer %s: %v", ips, lbName, err)
+	}
+	if err = c.Transact("lb-del", ops); err != nil {
+		return fmt.Errorf("failed to delete ip port mappings of %v from load balancer %s: %v", ips, lbName, err)
+	}
+	return nil
+}
+
 // LoadBalancerUpdateIPPortMapping update load balancer ip port mapping
 func (c *OVNNbClient) LoadBalancerUpdateIPPortMapping(lbName, vipEndpoint string, ipPortMappings map[string]string) error {
 	if len(ipPortMappings) != 0 {
@@ -436,6 +436,78 @@ func (suite *OvnClientTestSuite) testLoadBalancerDeleteVip() {
 	require.Equal(t, vips, lb.Vips)
 }
 
+func (suite *OvnClientTestSuite) testLoadBalancerAddVips() {
+	t := suite.T()
+	t.Parallel()
+
+	ovnClient := suite.ovnClient
+	lbName := "test-lb-add-vips"
+
+	err := ovnClient.CreateLoadBalancer(lbName, "tcp", "")
+	require.NoError(t, err)
+
+	vips := map[string]string{
+		"172.18.0.10:8000":    "10.0.1.5:9000",
+		"172.18.0.10:8001":    "10.0.1.5:9001",
+		"[fc00::10]:8000":     "[fd00::5]:9000",
+		"172.18.0.10:8002":    "10.0.1.5:9002",
+		"172.18.0.10:8003":    "10.0.1.5:9003",
+		"[fc00::10]:8001":     "[fd00::5]:9001",
+		"172.18.0.11:443":     "10.0.1.6:6443",
+		"[fc00::11]:443":      "[fd00::6]:6443",
+		"172.18.0.11:80":      "10.0.1.6:8080,10.0.1.7:8080",
+		"[fc00::11]:80":       "[fd00::6]:8080,[fd00::7]:8080",
+		"172.18.0.12:53":      "10.0.1.8:53",
+		"[fc00:0:0:1::12]:53": "[fd00::8]:53",
+	}
+	err = ovnClient.LoadBalancerAddVips(lbName, vips)
+	require.NoError(t, err)
+
+	lb, err := ovnClient.GetLoadBalancer(lbName, false)
+	require.NoError(t, err)
+	require.Equal(t, vips, lb.Vips)
+
+	t.Run("update backends of existing vips", func(t *testing.T) {
+		updated := map[string]string{
+			"172.18.0.10:8000": "10.0.1.15:9000",
+			"172.18.0.10:8001": "10.0.1.5:9001",
+			"172.18.0.13:8000": "10.0.1.9:9000",
+		}
+		err = ovnClient.LoadBalancerAddVips(lbName, updated)
+		require.NoError(t, err)
+		for vip, backends := range updated {
+			vips[vip] = backends
+		}
+
+		lb, err = ovnClient.GetLoadBalancer(lbName, false)
+		require.NoError(t, err)
+		require.Equal(t, vips, lb.Vips)
+	})
+
+	t.Run("delete vips", func(t *testing.T) {
+		deleted := []string{"172.18.0.10:8000", "[fc00::10]:8000", "172.18.0.100:80"}
+		err = ovnClient.LoadBalancerDeleteVips(lbName, deleted)
+		require.NoError(t, err)
+		for _, vip := range deleted {
+			delete(vips, vip)
+		}
+
+		lb, err = ovnClient.GetLoadBalancer(lbName, false)
+		require.NoError(t, err)
+		require.Equal(t, vips, lb.Vips)
+	})
+
+	t.Run("delete vips from non-existent load balancer", func(t *testing.T) {
+		err = ovnClient.LoadBalancerDeleteVips("test-lb-non-existent", []string{"172.18.0.10:8001"})
+		require.NoError(t, err)
+	})
+
+	t.Run("add vips to non-existent load balancer", func(t *testing.T) {
+		err = ovnClient.LoadBalancerAddVips("test-lb-non-existent", map[string]string{"172.18.0.10:8001": "10.0.1.5:9001"})
+		require.Error(t, err)
+	})
+}
+
 func (suite *OvnClientTestSuite) testLoadBalancerAddIPPortMapping() {
 	t := suite.T()
 	t.Parallel()
@@ -653,6 +725,39 @@ func (suite *OvnClientTestSuite) testLoadBalancerDeleteIPPortMapping() {
 	)
 }
 
+func (suite *OvnClientTestSuite) testLoadBalancerDeleteIPPortMappingByIPs() {
+	t := suite.T()
+	t.Parallel()
+
+	ovnClient := suite.ovnClient
+	lbName := "test-lb-del-ip-port-mapping-by-ips"
+
+	err := ovnClient.CreateLoadBalancer(lbName, "tcp", "")
+	require.NoError(t, err)
+
+	mappings := map[string]string{
+		"10.244.0.15": "pod-1.default:10.244.0.100",
+		"10.244.0.16": "pod-2.default:10.244.0.100",
+		"10.244.0.17": "pod-3.default:10.244.0.100",
+	}
+	err = ovnClient.LoadBalancerAddIPPortMapping(lbName, "10.96.0.10:80", mappings)
+	require.NoError(t, err)
+
+	t.Run("delete ip port mappings of backend ips", func(t *testing.T) {
+		err = ovnClient.LoadBalancerDeleteIPPortMappingByIPs(lbName, "10.244.0.15", "10.244.0.16", "10.244.0.18")
+		require.NoError(t, err)
+
+		lb, err := ovnClient.GetLoadBalancer(lbName, false)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"10.244.0.17": "pod-3.default:10.244.0.100"}, lb.IPPortMappings)
+	})
+
+	t.Run("delete ip port mappings from non-existent load balancer", func(t *testing.T) {
+		err = ovnClient.LoadBalancerDeleteIPPortMappingByIPs("test-lb-non-existent", "10.244.0.17")
+		require.NoError(t, err)
+	})
+}
+
 func (suite *OvnClientTestSuite) testLoadBalancerWithHealthCheck() {
 	t := suite.T()
 	t.Parallel()
@@ -3,6 +3,7 @@ package ovs
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/ovn-org/libovsdb/client"
@@ -107,6 +108,29 @@ func (c *OVNNbClient) UpdateLogicalRouterPortOptions(lrpName string, options map
 	return c.UpdateLogicalRouterPort(lrp, &lrp.Options)
 }
 
+// UpdateLogicalRouterPortNetworks update the networks of logical router port if they are changed
+func (c *OVNNbClient) UpdateLogicalRouterPortNetworks(lrpName string, networks []string) error {
+	if len(networks) == 0 {
+		return nil
+	}
+
+	lrp, err := c.GetLogicalRouterPort(lrpName, false)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	current, desired := slices.Clone(lrp.Networks), slices.Clone(networks)
+	slices.Sort(current)
+	slices.Sort(desired)
+	if slices.Equal(current, desired) {
+		return nil
+	}
+
+	lrp.Networks = networks
+	return c.UpdateLogicalRouterPort(lrp, &lrp.Networks)
+}
+
 // UpdateLogicalRouterPort update logical router port
 func (c *OVNNbClient) UpdateLogicalRouterPort(lrp *ovnnb.LogicalRouterPort, fields ...interface{}) error {
 	if lrp == nil {
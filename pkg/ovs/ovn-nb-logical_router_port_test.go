@@ -188,6 +188,45 @@ func (suite *OvnClientTestSuite) testUpdateLogicalRouterPortOptions() {
 	})
 }
 
+func (suite *OvnClientTestSuite) testUpdateLogicalRouterPortNetworks() {
+	t := suite.T()
+	t.Parallel()
+
+	ovnClient := suite.ovnClient
+	lrpName := "test-update-lrp-networks"
+	lrName := "test-update-lrp-networks-lr"
+
+	err := ovnClient.CreateLogicalRouter(lrName)
+	require.NoError(t, err)
+
+	err = ovnClient.CreateLogicalRouterPort(lrName, lrpName, "00:11:22:37:af:63", []string{"192.168.123.2/24"})
+	require.NoError(t, err)
+
+	t.Run("add ipv6 network", func(t *testing.T) {
+		networks := []string{"192.168.123.2/24", "fd00::c0a8:7b02/120"}
+		err := ovnClient.UpdateLogicalRouterPortNetworks(lrpName, networks)
+		require.NoError(t, err)
+
+		lrp, err := ovnClient.GetLogicalRouterPort(lrpName, false)
+		require.NoError(t, err)
+		require.ElementsMatch(t, networks, lrp.Networks)
+	})
+
+	t.Run("empty networks", func(t *testing.T) {
+		err := ovnClient.UpdateLogicalRouterPortNetworks(lrpName, nil)
+		require.NoError(t, err)
+
+		lrp, err := ovnClient.GetLogicalRouterPort(lrpName, false)
+		require.NoError(t, err)
+		require.Len(t, lrp.Networks, 2)
+	})
+
+	t.Run("non-existent logical router port", func(t *testing.T) {
+		err := ovnClient.UpdateLogicalRouterPortNetworks("test-non-existent-lrp", []string{"192.168.124.2/24"})
+		require.Error(t, err)
+	})
+}
+
 func (suite *OvnClientTestSuite) testCreateLogicalRouterPort() {
 	t := suite.T()
 	t.Parallel()
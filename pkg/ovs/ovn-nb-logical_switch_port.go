@@ -350,6 +350,62 @@ func (c *OVNNbClient) SetLogicalSwitchPortArpProxy(lspName string, enableArpProx
 	return nil
 }
 
+// SetLogicalSwitchPortMigrateOptions binds the logical switch port to both the source and the target chassis during
+// live migration, the port on the target chassis is activated by the first RARP packet sent from it
+func (c *OVNNbClient) SetLogicalSwitchPortMigrateOptions(lspName, srcChassis, targetChassis string) error {
+	if srcChassis == "" || targetChassis == "" {
+		return fmt.Errorf("source chassis and target chassis of logical switch port %s must be specified", lspName)
+	}
+
+	lsp, err := c.GetLogicalSwitchPort(lspName, false)
+	if err != nil {
+		klog.Error(err)
+		return fmt.Errorf("get logical switch port %s: %v", lspName, err)
+	}
+
+	requestedChassis := srcChassis + "," + targetChassis
+	if lsp.Options != nil && lsp.Options["requested-chassis"] == requestedChassis && lsp.Options["activation-strategy"] == "rarp" {
+		return nil
+	}
+
+	if lsp.Options == nil {
+		lsp.Options = make(map[string]string)
+	}
+	lsp.Options["requested-chassis"] = requestedChassis
+	lsp.Options["activation-strategy"] = "rarp"
+
+	if err := c.UpdateLogicalSwitchPort(lsp, &lsp.Options); err != nil {
+		return fmt.Errorf("set migrate options of logical switch port %s: %v", lspName, err)
+	}
+
+	return nil
+}
+
+// ResetLogicalSwitchPortMigrateOptions removes the requested chassis and the activation strategy of the logical switch
+// port once the migration succeeds or fails, so that the port is bound to the chassis where the vm is running as usual
+func (c *OVNNbClient) ResetLogicalSwitchPortMigrateOptions(lspName string) error {
+	lsp, err := c.GetLogicalSwitchPort(lspName, true)
+	if err != nil {
+		klog.Error(err)
+		return fmt.Errorf("get logical switch port %s: %v", lspName, err)
+	}
+	if lsp == nil || lsp.Options == nil {
+		return nil
+	}
+	if _, ok := lsp.Options["activation-strategy"]; !ok {
+		return nil
+	}
+
+	delete(lsp.Options, "requested-chassis")
+	delete(lsp.Options, "activation-strategy")
+
+	if err := c.UpdateLogicalSwitchPort(lsp, &lsp.Options); err != nil {
+		return fmt.Errorf("reset migrate options of logical switch port %s: %v", lspName, err)
+	}
+
+	return nil
+}
+
 // SetLogicalSwitchPortSecurity set logical switch port port_security
 func (c *OVNNbClient) SetLogicalSwitchPortSecurity(portSecurity bool, lspName, mac, ips, vips string) error {
 	lsp, err := c.GetLogicalSwitchPort(lspName, false)
@@ -378,6 +378,74 @@ func (suite *OvnClientTestSuite) testSetLogicalSwitchPortArpProxy() {
 	})
 }
 
+func (suite *OvnClientTestSuite) testSetLogicalSwitchPortMigrateOptions() {
+	t := suite.T()
+	t.Parallel()
+
+	ovnClient := suite.ovnClient
+	lsName := "test-set-lsp-migrate-options-ls"
+	lspName := "test-set-lsp-migrate-options-lsp"
+	srcChassis := "test-src-chassis"
+	targetChassis := "test-target-chassis"
+
+	err := ovnClient.CreateBareLogicalSwitch(lsName)
+	require.NoError(t, err)
+
+	err = ovnClient.CreateBareLogicalSwitchPort(lsName, lspName, "unknown", "")
+	require.NoError(t, err)
+
+	t.Run("not migrating", func(t *testing.T) {
+		lsp, err := ovnClient.GetLogicalSwitchPort(lspName, false)
+		require.NoError(t, err)
+		require.NotContains(t, lsp.Options, "requested-chassis")
+		require.NotContains(t, lsp.Options, "activation-strategy")
+	})
+
+	t.Run("set migrate options", func(t *testing.T) {
+		err = ovnClient.SetLogicalSwitchPortMigrateOptions(lspName, srcChassis, targetChassis)
+		require.NoError(t, err)
+
+		lsp, err := ovnClient.GetLogicalSwitchPort(lspName, false)
+		require.NoError(t, err)
+		require.Equal(t, srcChassis+","+targetChassis, lsp.Options["requested-chassis"])
+		require.Equal(t, "rarp", lsp.Options["activation-strategy"])
+	})
+
+	t.Run("reset migrate options after failed migration", func(t *testing.T) {
+		err = ovnClient.ResetLogicalSwitchPortMigrateOptions(lspName)
+		require.NoError(t, err)
+
+		lsp, err := ovnClient.GetLogicalSwitchPort(lspName, false)
+		require.NoError(t, err)
+		require.NotContains(t, lsp.Options, "requested-chassis")
+		require.NotContains(t, lsp.Options, "activation-strategy")
+	})
+
+	t.Run("reset migrate options after successful migration", func(t *testing.T) {
+		err = ovnClient.SetLogicalSwitchPortMigrateOptions(lspName, srcChassis, targetChassis)
+		require.NoError(t, err)
+		err = ovnClient.ResetLogicalSwitchPortMigrateOptions(lspName)
+		require.NoError(t, err)
+
+		lsp, err := ovnClient.GetLogicalSwitchPort(lspName, false)
+		require.NoError(t, err)
+		require.NotContains(t, lsp.Options, "requested-chassis")
+		require.NotContains(t, lsp.Options, "activation-strategy")
+	})
+
+	t.Run("should fail without chassis", func(t *testing.T) {
+		err = ovnClient.SetLogicalSwitchPortMigrateOptions(lspName, srcChassis, "")
+		require.Error(t, err)
+	})
+
+	t.Run("should fail for non-existent port", func(t *testing.T) {
+		err = ovnClient.SetLogicalSwitchPortMigrateOptions("test-non-existent-lsp", srcChassis, targetChassis)
+		require.Error(t, err)
+		err = ovnClient.ResetLogicalSwitchPortMigrateOptions("test-non-existent-lsp")
+		require.NoError(t, err)
+	})
+}
+
 func (suite *OvnClientTestSuite) testSetLogicalSwitchPortSecurity() {
 	t := suite.T()
 	t.Parallel()
@@ -0,0 +1,193 @@
+package ovs
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"k8s.io/klog/v2"
+
+	ovsclient "github.com/kubeovn/kube-ovn/pkg/ovsdb/client"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+)
+
+// CreateOrUpdateMirror create a mirror to the remote sink, the filter, sink, type, index and external ids are
+// updated if the mirror already exists
+func (c *OVNNbClient) CreateOrUpdateMirror(name, filter, sink, mirrorType string, index int, externalIDs map[string]string) error {
+	mirror, err := c.GetMirror(name, true)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	if mirror != nil {
+		if mirror.Filter == filter && mirror.Sink == sink && mirror.Type == mirrorType && mirror.Index == index && maps.Equal(mirror.ExternalIDs, externalIDs) {
+			return nil
+		}
+		mirror.Filter = filter
+		mirror.Sink = sink
+		mirror.Type = mirrorType
+		mirror.Index = index
+		mirror.ExternalIDs = externalIDs
+		ops, err := c.Where(mirror).Update(mirror, &mirror.Filter, &mirror.Sink, &mirror.Type, &mirror.Index, &mirror.ExternalIDs)
+		if err != nil {
+			klog.Error(err)
+			return fmt.Errorf("generate operations for updating mirror %s: %v", name, err)
+		}
+		if err = c.Transact("mirror-update", ops); err != nil {
+			return fmt.Errorf("update mirror %s: %v", name, err)
+		}
+		return nil
+	}
+
+	mirror = &ovnnb.Mirror{
+		UUID:        ovsclient.NamedUUID(),
+		Name:        name,
+		Filter:      filter,
+		Sink:        sink,
+		Type:        mirrorType,
+		Index:       index,
+		ExternalIDs: externalIDs,
+	}
+	ops, err := c.ovsDbClient.Create(mirror)
+	if err != nil {
+		klog.Error(err)
+		return fmt.Errorf("generate operations for creating mirror %s: %v", name, err)
+	}
+	if err = c.Transact("mirror-add", ops); err != nil {
+		return fmt.Errorf("create mirror %s: %v", name, err)
+	}
+	return nil
+}
+
+// DeleteMirror delete the mirror and remove it from the logical switch ports
+func (c *OVNNbClient) DeleteMirror(name string) error {
+	mirror, err := c.GetMirror(name, true)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if mirror == nil {
+		return nil
+	}
+
+	lspList, err := c.ListLogicalSwitchPorts(false, nil, func(lsp *ovnnb.LogicalSwitchPort) bool {
+		return slices.Contains(lsp.MirrorRules, mirror.UUID)
+	})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	var ops []ovsdb.Operation
+	for _, lsp := range lspList {
+		lspOps, err := c.logicalSwitchPortUpdateMirrorOp(lsp.Name, []string{mirror.UUID}, ovsdb.MutateOperationDelete)
+		if err != nil {
+			klog.Error(err)
+			return fmt.Errorf("generate operations for removing mirror %s from logical switch port %s: %v", name, lsp.Name, err)
+		}
+		ops = append(ops, lspOps...)
+	}
+	deleteOps, err := c.Where(mirror).Delete()
+	if err != nil {
+		klog.Error(err)
+		return fmt.Errorf("generate operations for deleting mirror %s: %v", name, err)
+	}
+	ops = append(ops, deleteOps...)
+	if err = c.Transact("mirror-del", ops); err != nil {
+		return fmt.Errorf("delete mirror %s: %v", name, err)
+	}
+	return nil
+}
+
+// GetMirror get mirror by name
+func (c *OVNNbClient) GetMirror(name string, ignoreNotFound bool) (*ovnnb.Mirror, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	mirror := &ovnnb.Mirror{Name: name}
+	if err := c.Get(ctx, mirror); err != nil {
+		if ignoreNotFound && err == client.ErrNotFound {
+			return nil, nil
+		}
+		klog.Error(err)
+		return nil, fmt.Errorf("get mirror %s: %v", name, err)
+	}
+	return mirror, nil
+}
+
+// ListMirrors list the mirrors which match the given externalIDs
+func (c *OVNNbClient) ListMirrors(externalIDs map[string]string) ([]ovnnb.Mirror, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	var mirrors []ovnnb.Mirror
+	if err := c.WhereCache(func(mirror *ovnnb.Mirror) bool {
+		for k, v := range externalIDs {
+			if mirror.ExternalIDs[k] != v {
+				return false
+			}
+		}
+		return true
+	}).List(ctx, &mirrors); err != nil {
+		klog.Error(err)
+		return nil, fmt.Errorf("list mirrors: %v", err)
+	}
+	return mirrors, nil
+}
+
+// LogicalSwitchPortUpdateMirrors add mirrors to or remove mirrors from logical switch port
+func (c *OVNNbClient) LogicalSwitchPortUpdateMirrors(lspName string, op ovsdb.Mutator, mirrorNames ...string) error {
+	if len(mirrorNames) == 0 {
+		return nil
+	}
+
+	mirrorUUIDs := make([]string, 0, len(mirrorNames))
+	for _, name := range mirrorNames {
+		mirror, err := c.GetMirror(name, true)
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		// ignore non-existent object
+		if mirror != nil {
+			mirrorUUIDs = append(mirrorUUIDs, mirror.UUID)
+		}
+	}
+	if len(mirrorUUIDs) == 0 {
+		return nil
+	}
+
+	ops, err := c.logicalSwitchPortUpdateMirrorOp(lspName, mirrorUUIDs, op)
+	if err != nil {
+		klog.Error(err)
+		return fmt.Errorf("generate operations for logical switch port %s update mirrors %v: %v", lspName, mirrorNames, err)
+	}
+	if err = c.Transact("lsp-mirror-update", ops); err != nil {
+		return fmt.Errorf("logical switch port %s update mirrors %v: %v", lspName, mirrorNames, err)
+	}
+	return nil
+}
+
+// logicalSwitchPortUpdateMirrorOp create operations add mirrors to or delete mirrors from logical switch port
+func (c *OVNNbClient) logicalSwitchPortUpdateMirrorOp(lspName string, mirrorUUIDs []string, op ovsdb.Mutator) ([]ovsdb.Operation, error) {
+	lsp, err := c.GetLogicalSwitchPort(lspName, false)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	ops, err := c.Where(lsp).Mutate(lsp, model.Mutation{
+		Field:   &lsp.MirrorRules,
+		Value:   mirrorUUIDs,
+		Mutator: op,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generate operations for mutating mirror rules of logical switch port %s: %v", lspName, err)
+	}
+	return ops, nil
+}
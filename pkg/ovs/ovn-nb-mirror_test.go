@@ -0,0 +1,111 @@
+package ovs
+
+import (
+	"testing"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+)
+
+func (suite *OvnClientTestSuite) testCreateOrUpdateMirror() {
+	t := suite.T()
+	t.Parallel()
+
+	ovnClient := suite.ovnClient
+	name := "test-create-mirror"
+	externalIDs := map[string]string{"traffic-mirror": "test-create-mirror"}
+
+	t.Run("create mirror", func(t *testing.T) {
+		err := ovnClient.CreateOrUpdateMirror(name, ovnnb.MirrorFilterFromLport, "192.168.1.10", ovnnb.MirrorTypeErspan, 1, externalIDs)
+		require.NoError(t, err)
+
+		mirror, err := ovnClient.GetMirror(name, false)
+		require.NoError(t, err)
+		require.Equal(t, ovnnb.MirrorFilterFromLport, mirror.Filter)
+		require.Equal(t, "192.168.1.10", mirror.Sink)
+		require.Equal(t, ovnnb.MirrorTypeErspan, mirror.Type)
+		require.Equal(t, 1, mirror.Index)
+		require.Equal(t, externalIDs, mirror.ExternalIDs)
+	})
+
+	t.Run("update mirror", func(t *testing.T) {
+		err := ovnClient.CreateOrUpdateMirror(name, ovnnb.MirrorFilterToLport, "192.168.1.11", ovnnb.MirrorTypeGre, 2, externalIDs)
+		require.NoError(t, err)
+
+		mirror, err := ovnClient.GetMirror(name, false)
+		require.NoError(t, err)
+		require.Equal(t, ovnnb.MirrorFilterToLport, mirror.Filter)
+		require.Equal(t, "192.168.1.11", mirror.Sink)
+		require.Equal(t, ovnnb.MirrorTypeGre, mirror.Type)
+		require.Equal(t, 2, mirror.Index)
+
+		mirrors, err := ovnClient.ListMirrors(externalIDs)
+		require.NoError(t, err)
+		require.Len(t, mirrors, 1)
+	})
+}
+
+func (suite *OvnClientTestSuite) testLogicalSwitchPortUpdateMirrors() {
+	t := suite.T()
+	t.Parallel()
+
+	ovnClient := suite.ovnClient
+	lsName := "test-mirror-ls"
+	lspName := "test-mirror-lsp"
+	name := "test-lsp-mirror"
+
+	err := ovnClient.CreateBareLogicalSwitch(lsName)
+	require.NoError(t, err)
+	err = ovnClient.CreateBareLogicalSwitchPort(lsName, lspName, "unknown", "")
+	require.NoError(t, err)
+	err = ovnClient.CreateOrUpdateMirror(name, ovnnb.MirrorFilterFromLport, "192.168.1.10", ovnnb.MirrorTypeGre, 1, nil)
+	require.NoError(t, err)
+	mirror, err := ovnClient.GetMirror(name, false)
+	require.NoError(t, err)
+
+	err = ovnClient.LogicalSwitchPortUpdateMirrors(lspName, ovsdb.MutateOperationInsert, name, "test-mirror-non-existent")
+	require.NoError(t, err)
+	lsp, err := ovnClient.GetLogicalSwitchPort(lspName, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{mirror.UUID}, lsp.MirrorRules)
+
+	err = ovnClient.LogicalSwitchPortUpdateMirrors(lspName, ovsdb.MutateOperationDelete, name)
+	require.NoError(t, err)
+	lsp, err = ovnClient.GetLogicalSwitchPort(lspName, false)
+	require.NoError(t, err)
+	require.Empty(t, lsp.MirrorRules)
+}
+
+func (suite *OvnClientTestSuite) testDeleteMirror() {
+	t := suite.T()
+	t.Parallel()
+
+	ovnClient := suite.ovnClient
+	lsName := "test-del-mirror-ls"
+	lspName := "test-del-mirror-lsp"
+	name := "test-del-mirror"
+
+	err := ovnClient.CreateBareLogicalSwitch(lsName)
+	require.NoError(t, err)
+	err = ovnClient.CreateBareLogicalSwitchPort(lsName, lspName, "unknown", "")
+	require.NoError(t, err)
+	err = ovnClient.CreateOrUpdateMirror(name, ovnnb.MirrorFilterToLport, "192.168.1.10", ovnnb.MirrorTypeErspan, 1, nil)
+	require.NoError(t, err)
+	err = ovnClient.LogicalSwitchPortUpdateMirrors(lspName, ovsdb.MutateOperationInsert, name)
+	require.NoError(t, err)
+
+	err = ovnClient.DeleteMirror(name)
+	require.NoError(t, err)
+	mirror, err := ovnClient.GetMirror(name, true)
+	require.NoError(t, err)
+	require.Nil(t, mirror)
+	lsp, err := ovnClient.GetLogicalSwitchPort(lspName, false)
+	require.NoError(t, err)
+	require.Empty(t, lsp.MirrorRules)
+
+	// delete non-existent mirror
+	err = ovnClient.DeleteMirror(name)
+	require.NoError(t, err)
+}
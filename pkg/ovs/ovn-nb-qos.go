@@ -0,0 +1,193 @@
+package ovs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"k8s.io/klog/v2"
+
+	ovsclient "github.com/kubeovn/kube-ovn/pkg/ovsdb/client"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+)
+
+// AddQoS add a qos rule to logical switch, the action, bandwidth and external ids are updated
+// if the rule with the same direction, priority and match already exists and has the same owner,
+// an error is returned if the rule is owned by another qos policy or by the pod bandwidth
+func (c *OVNNbClient) AddQoS(lsName, direction string, priority int, match string, action, bandwidth map[string]int, externalIDs map[string]string) error {
+	qosList, err := c.listQoSsByFilter(lsName, func(qos *ovnnb.QoS) bool {
+		return qos.Direction == direction && qos.Priority == priority && qos.Match == match
+	})
+	if err != nil {
+		klog.Error(err)
+		return fmt.Errorf("get qos direction %s priority %d match %s in logical switch %s: %v", direction, priority, match, lsName, err)
+	}
+
+	if len(qosList) != 0 {
+		qos := qosList[0]
+		if !qosOwnedBySame(qos, externalIDs) {
+			err = fmt.Errorf("qos direction %s priority %d match %s in logical switch %s is owned by %v", direction, priority, match, lsName, qos.ExternalIDs)
+			klog.Error(err)
+			return err
+		}
+		if maps.Equal(qos.Action, action) && maps.Equal(qos.Bandwidth, bandwidth) && maps.Equal(qos.ExternalIDs, externalIDs) {
+			return nil
+		}
+		qos.Action = action
+		qos.Bandwidth = bandwidth
+		qos.ExternalIDs = externalIDs
+		ops, err := c.Where(qos).Update(qos, &qos.Action, &qos.Bandwidth, &qos.ExternalIDs)
+		if err != nil {
+			klog.Error(err)
+			return fmt.Errorf("generate operations for updating qos %s: %v", qos.UUID, err)
+		}
+		if err = c.Transact("qos-update", ops); err != nil {
+			return fmt.Errorf("update qos %s in logical switch %s: %v", qos.UUID, lsName, err)
+		}
+		return nil
+	}
+
+	qos := &ovnnb.QoS{
+		UUID:        ovsclient.NamedUUID(),
+		Direction:   direction,
+		Priority:    priority,
+		Match:       match,
+		Action:      action,
+		Bandwidth:   bandwidth,
+		ExternalIDs: externalIDs,
+	}
+	createOps, err := c.ovsDbClient.Create(qos)
+	if err != nil {
+		klog.Error(err)
+		return fmt.Errorf("generate operations for creating qos: %v", err)
+	}
+	qosAddOps, err := c.logicalSwitchUpdateQoSOp(lsName, []string{qos.UUID}, ovsdb.MutateOperationInsert)
+	if err != nil {
+		klog.Error(err)
+		return fmt.Errorf("generate operations for adding qos to logical switch %s: %v", lsName, err)
+	}
+
+	ops := make([]ovsdb.Operation, 0, len(createOps)+len(qosAddOps))
+	ops = append(ops, createOps...)
+	ops = append(ops, qosAddOps...)
+	if err = c.Transact("qos-add", ops); err != nil {
+		return fmt.Errorf("add qos to logical switch %s: %v", lsName, err)
+	}
+
+	return nil
+}
+
+// qosOwnedBySame returns whether the qos rule belongs to the same qos policy or pod bandwidth as the external ids
+func qosOwnedBySame(qos *ovnnb.QoS, externalIDs map[string]string) bool {
+	for _, key := range []string{qosPolicyKey, podBandwidthQoSKey} {
+		if qos.ExternalIDs[key] != externalIDs[key] {
+			return false
+		}
+	}
+	return true
+}
+
+// DeleteQoSs delete the qos rules which match the given externalIDs and filter from logical switch
+func (c *OVNNbClient) DeleteQoSs(lsName string, externalIDs map[string]string, filter func(qos *ovnnb.QoS) bool) error {
+	qosList, err := c.listQoSsByFilter(lsName, func(qos *ovnnb.QoS) bool {
+		return qosMatchExternalIDs(qos, externalIDs) && (filter == nil || filter(qos))
+	})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if len(qosList) == 0 {
+		return nil
+	}
+
+	qosUUIDs := make([]string, 0, len(qosList))
+	for _, qos := range qosList {
+		qosUUIDs = append(qosUUIDs, qos.UUID)
+	}
+	ops, err := c.logicalSwitchUpdateQoSOp(lsName, qosUUIDs, ovsdb.MutateOperationDelete)
+	if err != nil {
+		klog.Error(err)
+		return fmt.Errorf("generate operations for removing qos %v from logical switch %s: %v", qosUUIDs, lsName, err)
+	}
+	if err = c.Transact("qos-del", ops); err != nil {
+		return fmt.Errorf("delete qos %v from logical switch %s: %v", qosUUIDs, lsName, err)
+	}
+	return nil
+}
+
+// ListQoSs list the qos rules of logical switch which match the given externalIDs
+func (c *OVNNbClient) ListQoSs(lsName string, externalIDs map[string]string) ([]*ovnnb.QoS, error) {
+	return c.listQoSsByFilter(lsName, func(qos *ovnnb.QoS) bool {
+		return qosMatchExternalIDs(qos, externalIDs)
+	})
+}
+
+func qosMatchExternalIDs(qos *ovnnb.QoS, externalIDs map[string]string) bool {
+	for k, v := range externalIDs {
+		if qos.ExternalIDs[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// GetQoSByUUID get qos by UUID
+func (c *OVNNbClient) GetQoSByUUID(uuid string) (*ovnnb.QoS, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	qos := &ovnnb.QoS{UUID: uuid}
+	if err := c.Get(ctx, qos); err != nil {
+		return nil, err
+	}
+
+	return qos, nil
+}
+
+func (c *OVNNbClient) listQoSsByFilter(lsName string, filter func(qos *ovnnb.QoS) bool) ([]*ovnnb.QoS, error) {
+	ls, err := c.GetLogicalSwitch(lsName, false)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	qosList := make([]*ovnnb.QoS, 0, len(ls.QOSRules))
+	for _, uuid := range ls.QOSRules {
+		qos, err := c.GetQoSByUUID(uuid)
+		if err != nil {
+			if errors.Is(err, client.ErrNotFound) {
+				continue
+			}
+			klog.Error(err)
+			return nil, err
+		}
+		if filter == nil || filter(qos) {
+			qosList = append(qosList, qos)
+		}
+	}
+
+	return qosList, nil
+}
+
+// logicalSwitchUpdateQoSOp create operations add qos to or delete qos from logical switch
+func (c *OVNNbClient) logicalSwitchUpdateQoSOp(lsName string, qosUUIDs []string, op ovsdb.Mutator) ([]ovsdb.Operation, error) {
+	if len(qosUUIDs) == 0 {
+		return nil, nil
+	}
+
+	mutation := func(ls *ovnnb.LogicalSwitch) *model.Mutation {
+		mutation := &model.Mutation{
+			Field:   &ls.QOSRules,
+			Value:   qosUUIDs,
+			Mutator: op,
+		}
+
+		return mutation
+	}
+
+	return c.LogicalSwitchOp(lsName, mutation)
+}
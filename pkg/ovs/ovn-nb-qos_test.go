@@ -0,0 +1,131 @@
+package ovs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+)
+
+func (suite *OvnClientTestSuite) testAddQoS() {
+	t := suite.T()
+	t.Parallel()
+
+	ovnClient := suite.ovnClient
+	lsName := "test-add-qos-ls"
+	match := `inport == "test-add-qos-lsp"`
+	externalIDs := map[string]string{"qos-policy": "test-add-qos"}
+
+	err := ovnClient.CreateBareLogicalSwitch(lsName)
+	require.NoError(t, err)
+
+	t.Run("create qos", func(t *testing.T) {
+		err = ovnClient.AddQoS(lsName, ovnnb.QoSDirectionFromLport, 1000, match, nil, map[string]int{ovnnb.QoSBandwidthRate: 1000}, externalIDs)
+		require.NoError(t, err)
+
+		qosList, err := ovnClient.ListQoSs(lsName, externalIDs)
+		require.NoError(t, err)
+		require.Len(t, qosList, 1)
+		require.Equal(t, ovnnb.QoSDirectionFromLport, qosList[0].Direction)
+		require.Equal(t, match, qosList[0].Match)
+		require.Equal(t, map[string]int{ovnnb.QoSBandwidthRate: 1000}, qosList[0].Bandwidth)
+	})
+
+	t.Run("update qos bandwidth", func(t *testing.T) {
+		bandwidth := map[string]int{ovnnb.QoSBandwidthRate: 2000, ovnnb.QoSBandwidthBurst: 4000}
+		err = ovnClient.AddQoS(lsName, ovnnb.QoSDirectionFromLport, 1000, match, nil, bandwidth, externalIDs)
+		require.NoError(t, err)
+
+		qosList, err := ovnClient.ListQoSs(lsName, externalIDs)
+		require.NoError(t, err)
+		require.Len(t, qosList, 1)
+		require.Equal(t, bandwidth, qosList[0].Bandwidth)
+	})
+
+	t.Run("update qos with dscp action", func(t *testing.T) {
+		action := map[string]int{ovnnb.QoSActionDSCP: 46}
+		err = ovnClient.AddQoS(lsName, ovnnb.QoSDirectionFromLport, 1000, match, action, nil, externalIDs)
+		require.NoError(t, err)
+
+		qosList, err := ovnClient.ListQoSs(lsName, externalIDs)
+		require.NoError(t, err)
+		require.Len(t, qosList, 1)
+		require.Equal(t, action, qosList[0].Action)
+		require.Empty(t, qosList[0].Bandwidth)
+	})
+
+	t.Run("create qos with different direction", func(t *testing.T) {
+		err = ovnClient.AddQoS(lsName, ovnnb.QoSDirectionToLport, 1000, `outport == "test-add-qos-lsp"`, nil, map[string]int{ovnnb.QoSBandwidthRate: 1000}, externalIDs)
+		require.NoError(t, err)
+
+		qosList, err := ovnClient.ListQoSs(lsName, externalIDs)
+		require.NoError(t, err)
+		require.Len(t, qosList, 2)
+	})
+
+	t.Run("should fail for qos owned by another qos policy", func(t *testing.T) {
+		err = ovnClient.AddQoS(lsName, ovnnb.QoSDirectionFromLport, 1000, match, nil, map[string]int{ovnnb.QoSBandwidthRate: 3000}, map[string]string{"qos-policy": "test-add-qos-other"})
+		require.ErrorContains(t, err, "is owned by")
+
+		qosList, err := ovnClient.ListQoSs(lsName, externalIDs)
+		require.NoError(t, err)
+		require.Len(t, qosList, 2)
+		for _, qos := range qosList {
+			require.NotEqual(t, 3000, qos.Bandwidth[ovnnb.QoSBandwidthRate])
+		}
+	})
+
+	t.Run("should fail for qos owned by pod bandwidth", func(t *testing.T) {
+		err = ovnClient.AddQoS(lsName, ovnnb.QoSDirectionFromLport, 1000, match, nil, map[string]int{ovnnb.QoSBandwidthRate: 3000}, map[string]string{"pod-bandwidth": "true", "lsp": "test-add-qos-lsp"})
+		require.ErrorContains(t, err, "is owned by")
+	})
+
+	t.Run("should fail for non-existent logical switch", func(t *testing.T) {
+		err = ovnClient.AddQoS("test-non-existent-ls", ovnnb.QoSDirectionFromLport, 1000, match, nil, map[string]int{ovnnb.QoSBandwidthRate: 1000}, externalIDs)
+		require.Error(t, err)
+	})
+}
+
+func (suite *OvnClientTestSuite) testDeleteQoSs() {
+	t := suite.T()
+	t.Parallel()
+
+	ovnClient := suite.ovnClient
+	lsName := "test-del-qos-ls"
+
+	err := ovnClient.CreateBareLogicalSwitch(lsName)
+	require.NoError(t, err)
+
+	for _, port := range []string{"test-del-qos-lsp1", "test-del-qos-lsp2"} {
+		err = ovnClient.AddQoS(lsName, ovnnb.QoSDirectionFromLport, 1000, `inport == "`+port+`"`, nil, map[string]int{ovnnb.QoSBandwidthRate: 1000}, map[string]string{"qos-policy": "test-del-qos", "lsp": port})
+		require.NoError(t, err)
+	}
+
+	t.Run("delete qos of one port", func(t *testing.T) {
+		err = ovnClient.DeleteQoSs(lsName, map[string]string{"qos-policy": "test-del-qos", "lsp": "test-del-qos-lsp1"}, nil)
+		require.NoError(t, err)
+
+		qosList, err := ovnClient.ListQoSs(lsName, map[string]string{"qos-policy": "test-del-qos"})
+		require.NoError(t, err)
+		require.Len(t, qosList, 1)
+		require.Equal(t, "test-del-qos-lsp2", qosList[0].ExternalIDs["lsp"])
+	})
+
+	t.Run("delete qos by filter and all qos of policy", func(t *testing.T) {
+		err = ovnClient.DeleteQoSs(lsName, map[string]string{"qos-policy": "test-del-qos"}, func(qos *ovnnb.QoS) bool {
+			return qos.Direction == ovnnb.QoSDirectionToLport
+		})
+		require.NoError(t, err)
+		qosList, err := ovnClient.ListQoSs(lsName, map[string]string{"qos-policy": "test-del-qos"})
+		require.NoError(t, err)
+		require.Len(t, qosList, 1)
+
+		err = ovnClient.DeleteQoSs(lsName, map[string]string{"qos-policy": "test-del-qos"}, nil)
+		require.NoError(t, err)
+
+		ls, err := ovnClient.GetLogicalSwitch(lsName, false)
+		require.NoError(t, err)
+		require.Empty(t, ls.QOSRules)
+	})
+}
@@ -21,12 +21,18 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnicnb"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnicsb"
 	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/vswitch"
 )
 
 type OvnClientTestSuite struct {
 	suite.Suite
-	ovnClient *OVNNbClient
+	ovnClient     *OVNNbClient
+	ovnIcNbClient *OVNIcNbClient
+	ovnIcSbClient *OVNIcSbClient
+	ovsClient     *OVSClient
 }
 
 func (suite *OvnClientTestSuite) SetupSuite() {
@@ -43,6 +49,41 @@ func (suite *OvnClientTestSuite) SetupSuite() {
 	require.NoError(suite.T(), err)
 
 	suite.ovnClient = ovnClient
+
+	icNbDBModel, err := ovnicnb.FullDatabaseModel()
+	require.NoError(suite.T(), err)
+	_, icNbSock := newOVSDBServer(suite.T(), icNbDBModel, ovnicnb.Schema())
+	icNbClient, err := newTestClient(fmt.Sprintf("unix:%s", icNbSock), 10, icNbDBModel, client.WithTable(&ovnicnb.TransitSwitch{}))
+	require.NoError(suite.T(), err)
+	suite.ovnIcNbClient = &OVNIcNbClient{ovsDbClient: ovsDbClient{Client: icNbClient, Timeout: 10 * time.Second}}
+
+	icSbDBModel, err := ovnicsb.FullDatabaseModel()
+	require.NoError(suite.T(), err)
+	_, icSbSock := newOVSDBServer(suite.T(), icSbDBModel, ovnicsb.Schema())
+	icSbClient, err := newTestClient(fmt.Sprintf("unix:%s", icSbSock), 10, icSbDBModel,
+		client.WithTable(&ovnicsb.AvailabilityZone{}),
+		client.WithTable(&ovnicsb.Gateway{}),
+		client.WithTable(&ovnicsb.PortBinding{}),
+		client.WithTable(&ovnicsb.Route{}),
+	)
+	require.NoError(suite.T(), err)
+	suite.ovnIcSbClient = &OVNIcSbClient{ovsDbClient: ovsDbClient{Client: icSbClient, Timeout: 10 * time.Second}}
+
+	ovsDBModel, err := vswitch.FullDatabaseModel()
+	require.NoError(suite.T(), err)
+	_, ovsSock := newOVSDBServer(suite.T(), ovsDBModel, vswitch.Schema())
+	ovsClient, err := newTestClient(fmt.Sprintf("unix:%s", ovsSock), 10, ovsDBModel,
+		client.WithTable(&vswitch.OpenvSwitch{}),
+		client.WithTable(&vswitch.Bridge{}),
+		client.WithTable(&vswitch.Port{}),
+		client.WithTable(&vswitch.Interface{}),
+		client.WithTable(&vswitch.QoS{}),
+		client.WithTable(&vswitch.Queue{}),
+		client.WithTable(&vswitch.Mirror{}),
+	)
+	require.NoError(suite.T(), err)
+	suite.ovsClient = &OVSClient{ovsDbClient: ovsDbClient{Client: ovsClient, Timeout: 10 * time.Second}}
+	suite.createBridge("br-int")
 }
 
 // In order for 'go test' to run this suite, we need to create
@@ -146,6 +187,10 @@ func (suite *OvnClientTestSuite) Test_SetLogicalSwitchPortArpProxy() {
 	suite.testSetLogicalSwitchPortArpProxy()
 }
 
+func (suite *OvnClientTestSuite) Test_SetLogicalSwitchPortMigrateOptions() {
+	suite.testSetLogicalSwitchPortMigrateOptions()
+}
+
 func (suite *OvnClientTestSuite) Test_SetLogicalSwitchPortSecurity() {
 	suite.testSetLogicalSwitchPortSecurity()
 }
@@ -256,6 +301,10 @@ func (suite *OvnClientTestSuite) Test_UpdateLogicalRouterPortOptions() {
 	suite.testUpdateLogicalRouterPortOptions()
 }
 
+func (suite *OvnClientTestSuite) Test_UpdateLogicalRouterPortNetworks() {
+	suite.testUpdateLogicalRouterPortNetworks()
+}
+
 func (suite *OvnClientTestSuite) Test_CreateLogicalRouterPort() {
 	suite.testCreateLogicalRouterPort()
 }
@@ -346,6 +395,10 @@ func (suite *OvnClientTestSuite) Test_LoadBalancerAddVip() {
 	suite.testLoadBalancerAddVip()
 }
 
+func (suite *OvnClientTestSuite) Test_LoadBalancerAddVips() {
+	suite.testLoadBalancerAddVips()
+}
+
 func (suite *OvnClientTestSuite) Test_DeleteLoadBalancerOp() {
 	suite.testDeleteLoadBalancerOp()
 }
@@ -362,6 +415,10 @@ func (suite *OvnClientTestSuite) Test_LoadBalancerDeleteIPPortMapping() {
 	suite.testLoadBalancerDeleteIPPortMapping()
 }
 
+func (suite *OvnClientTestSuite) Test_LoadBalancerDeleteIPPortMappingByIPs() {
+	suite.testLoadBalancerDeleteIPPortMappingByIPs()
+}
+
 func (suite *OvnClientTestSuite) Test_LoadBalancerWithHealthCheck() {
 	suite.testLoadBalancerWithHealthCheck()
 }
@@ -662,6 +719,28 @@ func (suite *OvnClientTestSuite) Test_dhcpOptionsFilter() {
 	suite.testDhcpOptionsFilter()
 }
 
+/* qos unit test */
+func (suite *OvnClientTestSuite) Test_AddQoS() {
+	suite.testAddQoS()
+}
+
+func (suite *OvnClientTestSuite) Test_DeleteQoSs() {
+	suite.testDeleteQoSs()
+}
+
+/* mirror unit test */
+func (suite *OvnClientTestSuite) Test_CreateOrUpdateMirror() {
+	suite.testCreateOrUpdateMirror()
+}
+
+func (suite *OvnClientTestSuite) Test_LogicalSwitchPortUpdateMirrors() {
+	suite.testLogicalSwitchPortUpdateMirrors()
+}
+
+func (suite *OvnClientTestSuite) Test_DeleteMirror() {
+	suite.testDeleteMirror()
+}
+
 /* mixed operations unit test */
 func (suite *OvnClientTestSuite) Test_CreateGatewayLogicalSwitch() {
 	suite.testCreateGatewayLogicalSwitch()
@@ -697,6 +776,41 @@ func Test_scratch(t *testing.T) {
 	require.NoError(t, err)
 }
 
+/* ovn-ic nb transit switch unit test */
+func (suite *OvnClientTestSuite) Test_CreateTransitSwitch() {
+	suite.testCreateTransitSwitch()
+}
+
+func (suite *OvnClientTestSuite) Test_DeleteTransitSwitch() {
+	suite.testDeleteTransitSwitch()
+}
+
+func (suite *OvnClientTestSuite) Test_ListTransitSwitches() {
+	suite.testListTransitSwitches()
+}
+
+func (suite *OvnClientTestSuite) Test_TransitSwitchExternalIDs() {
+	suite.testTransitSwitchExternalIDs()
+}
+
+/* ovn-ic sb availability zone unit test */
+func (suite *OvnClientTestSuite) Test_DeleteAvailabilityZone() {
+	suite.testDeleteAvailabilityZone()
+}
+
+/* ovs unit test */
+func (suite *OvnClientTestSuite) Test_OVSAddPort() {
+	suite.testOVSAddPort()
+}
+
+func (suite *OvnClientTestSuite) Test_OVSDeletePort() {
+	suite.testOVSDeletePort()
+}
+
+func (suite *OvnClientTestSuite) Test_OVSConfigInterfaceMirror() {
+	suite.testOVSConfigInterfaceMirror()
+}
+
 func newOVSDBServer(t *testing.T, dbModel model.ClientDBModel, schema ovsdb.DatabaseSchema) (*server.OvsdbServer, string) {
 	serverDBModel, err := serverdb.FullDatabaseModel()
 	require.NoError(t, err)
@@ -789,9 +903,11 @@ func newNbClient(addr string, timeout int) (client.Client, error) {
 		client.WithTable(&ovnnb.LogicalRouter{}),
 		client.WithTable(&ovnnb.LogicalSwitchPort{}),
 		client.WithTable(&ovnnb.LogicalSwitch{}),
+		client.WithTable(&ovnnb.Mirror{}),
 		client.WithTable(&ovnnb.NAT{}),
 		client.WithTable(&ovnnb.NBGlobal{}),
 		client.WithTable(&ovnnb.PortGroup{}),
+		client.WithTable(&ovnnb.QoS{}),
 	}
 	if _, err = c.Monitor(context.TODO(), c.NewMonitor(monitorOpts...)); err != nil {
 		return nil, err
@@ -799,3 +915,28 @@ func newNbClient(addr string, timeout int) (client.Client, error) {
 
 	return c, nil
 }
+
+func newTestClient(addr string, timeout int, dbModel model.ClientDBModel, monitorOpts ...client.MonitorOption) (client.Client, error) {
+	logger := stdr.New(log.New(os.Stderr, "", log.LstdFlags)).
+		WithName("libovsdb").
+		WithValues("database", dbModel.Name())
+
+	options := []client.Option{
+		client.WithReconnect(time.Duration(timeout)*time.Second, &backoff.ZeroBackOff{}),
+		client.WithLeaderOnly(false),
+		client.WithLogger(&logger),
+		client.WithEndpoint(addr),
+	}
+
+	c, err := client.NewOVSDBClient(dbModel, options...)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.Connect(context.TODO()); err != nil {
+		return nil, err
+	}
+	if _, err = c.Monitor(context.TODO(), c.NewMonitor(monitorOpts...)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
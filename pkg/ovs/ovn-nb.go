@@ -20,6 +20,8 @@ const (
 	associatedSgKeyPrefix = "associated_sg_"
 	sgsKey                = "security_groups"
 	sgKey                 = "sg"
+	qosPolicyKey          = "qos-policy"
+	podBandwidthQoSKey    = "pod-bandwidth"
 )
 
 // CreateGatewayLogicalSwitch create gateway switch connect external networks
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"k8s.io/klog/v2"
@@ -115,7 +116,9 @@ func (c *OVNNbClient) SetUseCtInvMatch() error {
 	return c.SetNbGlobalOptions("use_ct_inv_match", false)
 }
 
-func (c *OVNNbClient) SetICAutoRoute(enable bool, blackList []string) error {
+// SetICAutoRoute enables the route advertisement of ovn-ic, the routes are learned by ovn-ic
+// only if learn is true, otherwise the learned routes are managed by kube-ovn
+func (c *OVNNbClient) SetICAutoRoute(enable, learn bool, blackList []string) error {
 	nbGlobal, err := c.GetNbGlobal()
 	if err != nil {
 		return fmt.Errorf("get nb global: %v", err)
@@ -127,7 +130,7 @@ func (c *OVNNbClient) SetICAutoRoute(enable bool, blackList []string) error {
 	}
 	if enable {
 		options["ic-route-adv"] = "true"
-		options["ic-route-learn"] = "true"
+		options["ic-route-learn"] = strconv.FormatBool(learn)
 		options["ic-route-blacklist"] = strings.Join(blackList, ",")
 	} else {
 		delete(options, "ic-route-adv")
@@ -148,7 +148,7 @@ func (suite *OvnClientTestSuite) testSetICAutoRoute() {
 	require.NoError(t, err)
 
 	t.Run("enable ovn-ic auto route", func(t *testing.T) {
-		err = ovnClient.SetICAutoRoute(true, []string{"1.1.1.1", "2.2.2.2"})
+		err = ovnClient.SetICAutoRoute(true, true, []string{"1.1.1.1", "2.2.2.2"})
 		require.NoError(t, err)
 
 		out, err := ovnClient.GetNbGlobal()
@@ -158,8 +158,19 @@ func (suite *OvnClientTestSuite) testSetICAutoRoute() {
 		require.Equal(t, "1.1.1.1,2.2.2.2", out.Options["ic-route-blacklist"])
 	})
 
+	t.Run("enable ovn-ic auto route without learning", func(t *testing.T) {
+		err = ovnClient.SetICAutoRoute(true, false, []string{"1.1.1.1"})
+		require.NoError(t, err)
+
+		out, err := ovnClient.GetNbGlobal()
+		require.NoError(t, err)
+		require.Equal(t, "true", out.Options["ic-route-adv"])
+		require.Equal(t, "false", out.Options["ic-route-learn"])
+		require.Equal(t, "1.1.1.1", out.Options["ic-route-blacklist"])
+	})
+
 	t.Run("disable ovn-ic auto route", func(t *testing.T) {
-		err = ovnClient.SetICAutoRoute(false, []string{"1.1.1.1", "2.2.2.2"})
+		err = ovnClient.SetICAutoRoute(false, false, []string{"1.1.1.1", "2.2.2.2"})
 		require.NoError(t, err)
 
 		out, err := ovnClient.GetNbGlobal()
@@ -11,15 +11,15 @@ import (
 	"k8s.io/klog/v2"
 
 	ovsclient "github.com/kubeovn/kube-ovn/pkg/ovsdb/client"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnicnb"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnicsb"
 	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnnb"
 	"github.com/kubeovn/kube-ovn/pkg/ovsdb/ovnsb"
 )
 
 // LegacyClient is the legacy ovn client
 type LegacyClient struct {
-	OvnTimeout     int
-	OvnICNbAddress string
-	OvnICSbAddress string
+	OvnTimeout int
 }
 
 type OVNNbClient struct {
@@ -31,17 +31,23 @@ type OVNSbClient struct {
 	ovsDbClient
 }
 
+type OVNIcNbClient struct {
+	ovsDbClient
+}
+
+type OVNIcSbClient struct {
+	ovsDbClient
+}
+
 type ovsDbClient struct {
 	client.Client
 	Timeout time.Duration
 }
 
 const (
-	OVNIcNbCtl = "ovn-ic-nbctl"
-	OVNIcSbCtl = "ovn-ic-sbctl"
-	OvsVsCtl   = "ovs-vsctl"
-	MayExist   = "--may-exist"
-	IfExists   = "--if-exists"
+	OvsVsCtl = "ovs-vsctl"
+	MayExist = "--may-exist"
+	IfExists = "--if-exists"
 
 	OVSDBWaitTimeout = 0
 )
@@ -74,9 +80,11 @@ func NewOvnNbClient(ovnNbAddr string, ovnNbTimeout int) (*OVNNbClient, error) {
 		client.WithTable(&ovnnb.LogicalRouter{}),
 		client.WithTable(&ovnnb.LogicalSwitchPort{}),
 		client.WithTable(&ovnnb.LogicalSwitch{}),
+		client.WithTable(&ovnnb.Mirror{}),
 		client.WithTable(&ovnnb.NAT{}),
 		client.WithTable(&ovnnb.NBGlobal{}),
 		client.WithTable(&ovnnb.PortGroup{}),
+		client.WithTable(&ovnnb.QoS{}),
 	}
 	nbClient, err := ovsclient.NewOvsDbClient(ovsclient.NBDB, ovnNbAddr, dbModel, monitors)
 	if err != nil {
@@ -119,7 +127,58 @@ func NewOvnSbClient(ovnSbAddr string, ovnSbTimeout int) (*OVNSbClient, error) {
 	return c, nil
 }
 
-// TODO: support ic-nb ic-sb client
+func NewOvnIcNbClient(ovnIcNbAddr string, ovnIcNbTimeout int) (*OVNIcNbClient, error) {
+	dbModel, err := ovnicnb.FullDatabaseModel()
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	monitors := []client.MonitorOption{
+		client.WithTable(&ovnicnb.TransitSwitch{}),
+	}
+	icNbClient, err := ovsclient.NewOvsDbClient(ovsclient.ICNBDB, ovnIcNbAddr, dbModel, monitors)
+	if err != nil {
+		klog.Errorf("failed to create OVN IC NB client: %v", err)
+		return nil, err
+	}
+
+	c := &OVNIcNbClient{
+		ovsDbClient: ovsDbClient{
+			Client:  icNbClient,
+			Timeout: time.Duration(ovnIcNbTimeout) * time.Second,
+		},
+	}
+	return c, nil
+}
+
+func NewOvnIcSbClient(ovnIcSbAddr string, ovnIcSbTimeout int) (*OVNIcSbClient, error) {
+	dbModel, err := ovnicsb.FullDatabaseModel()
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	monitors := []client.MonitorOption{
+		client.WithTable(&ovnicsb.AvailabilityZone{}),
+		client.WithTable(&ovnicsb.Gateway{}),
+		client.WithTable(&ovnicsb.PortBinding{}),
+		client.WithTable(&ovnicsb.Route{}),
+	}
+	icSbClient, err := ovsclient.NewOvsDbClient(ovsclient.ICSBDB, ovnIcSbAddr, dbModel, monitors)
+	if err != nil {
+		klog.Errorf("failed to create OVN IC SB client: %v", err)
+		return nil, err
+	}
+
+	c := &OVNIcSbClient{
+		ovsDbClient: ovsDbClient{
+			Client:  icSbClient,
+			Timeout: time.Duration(ovnIcSbTimeout) * time.Second,
+		},
+	}
+	return c, nil
+}
 
 func ConstructWaitForNameNotExistsOperation(name, table string) ovsdb.Operation {
 	return ConstructWaitForUniqueOperation(table, "name", name)
@@ -157,6 +216,12 @@ func (c *ovsDbClient) Transact(method string, operations []ovsdb.Operation) erro
 		dbType = "ovn-nb"
 	case "OVN_Southbound":
 		dbType = "ovn-sb"
+	case "OVN_IC_Northbound":
+		dbType = "ovn-ic-nb"
+	case "OVN_IC_Southbound":
+		dbType = "ovn-ic-sb"
+	case "Open_vSwitch":
+		dbType = "ovsdb"
 	}
 
 	code := "0"
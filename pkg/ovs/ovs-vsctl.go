@@ -465,3 +465,113 @@ func ListQosQueueIDs() (map[string]string, error) {
 	}
 	return result, nil
 }
+
+// trafficMirrorPortName returns the name of the tunnel port to the collector of the traffic mirror,
+// which is hashed since the name of a netdev is limited to 15 characters
+func trafficMirrorPortName(name string) string {
+	return "tm" + util.Sha256Hash([]byte(name))[:12]
+}
+
+func trafficMirrorName(name string) string {
+	return "tm-" + name
+}
+
+// SetTrafficMirror mirrors the traffic from (egress) or to (ingress) the ports of the interfaces with the iface-ids
+// on br-int to the remote collector through a tunnel port, the tunnel port and the mirror are created if not exist
+func SetTrafficMirror(name, tunnelType, remote string, key int, egress, ingress bool, ifaceIDs []string) error {
+	var portUUIDs []string
+	for _, ifaceID := range ifaceIDs {
+		ifNames, err := ovsFind("interface", "name", fmt.Sprintf("external-ids:iface-id=%s", ifaceID))
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		for _, ifName := range ifNames {
+			uuids, err := ovsFind("port", "_uuid", fmt.Sprintf("name=%s", ifName))
+			if err != nil {
+				klog.Error(err)
+				return err
+			}
+			portUUIDs = append(portUUIDs, uuids...)
+		}
+	}
+	srcPorts, dstPorts := "[]", "[]"
+	if egress {
+		srcPorts = "[" + strings.Join(portUUIDs, ",") + "]"
+	}
+	if ingress {
+		dstPorts = "[" + strings.Join(portUUIDs, ",") + "]"
+	}
+
+	portName, mirrorName := trafficMirrorPortName(name), trafficMirrorName(name)
+	args := []string{
+		MayExist, "add-port", "br-int", portName, "--",
+		"set", "interface", portName, "type=" + tunnelType, "options:remote_ip=" + remote, fmt.Sprintf("options:key=%d", key),
+	}
+	if tunnelType == "erspan" {
+		args = append(args, "options:erspan_ver=1")
+	}
+	args = append(args, "external_ids:vendor="+util.CniTypeName, "external_ids:traffic-mirror="+name, "--",
+		"--id=@p", "get", "port", portName, "--")
+
+	mirrors, err := ovsFind("mirror", "_uuid", fmt.Sprintf("name=%s", mirrorName))
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if len(mirrors) == 0 {
+		args = append(args, "--id=@m", "create", "mirror", "name="+mirrorName,
+			"select_src_port="+srcPorts, "select_dst_port="+dstPorts, "output_port=@p",
+			"external_ids:vendor="+util.CniTypeName, "external_ids:traffic-mirror="+name, "--",
+			"add", "bridge", "br-int", "mirrors", "@m")
+	} else {
+		args = append(args, "set", "mirror", mirrorName, "select_src_port="+srcPorts, "select_dst_port="+dstPorts, "output_port=@p")
+	}
+	if _, err = Exec(args...); err != nil {
+		klog.Error(err)
+		return fmt.Errorf("failed to set traffic mirror %s: %v", name, err)
+	}
+	return nil
+}
+
+// DeleteTrafficMirror deletes the mirror and the tunnel port of the traffic mirror from br-int
+func DeleteTrafficMirror(name string) error {
+	portName, mirrorName := trafficMirrorPortName(name), trafficMirrorName(name)
+	mirrors, err := ovsFind("mirror", "_uuid", fmt.Sprintf("name=%s", mirrorName))
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	var args []string
+	for _, mirror := range mirrors {
+		args = append(args, "remove", "bridge", "br-int", "mirrors", mirror, "--")
+	}
+	args = append(args, IfExists, "--with-iface", "del-port", "br-int", portName)
+	if _, err = Exec(args...); err != nil {
+		klog.Error(err)
+		return fmt.Errorf("failed to delete traffic mirror %s: %v", name, err)
+	}
+	return nil
+}
+
+// ListTrafficMirrors returns the names of the traffic mirrors configured on br-int
+func ListTrafficMirrors() ([]string, error) {
+	mirrors, err := ovsFind("mirror", "name", "external_ids:traffic-mirror!=[]")
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	names := make([]string, 0, len(mirrors))
+	for _, mirror := range mirrors {
+		name, err := ovsGet("mirror", mirror, "external_ids", "traffic-mirror")
+		if err != nil {
+			klog.Error(err)
+			return nil, err
+		}
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			name = unquoted
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
@@ -0,0 +1,306 @@
+package ovs
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/ovn-org/libovsdb/client"
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"k8s.io/klog/v2"
+
+	ovsclient "github.com/kubeovn/kube-ovn/pkg/ovsdb/client"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/vswitch"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// OVSClient is a long-lived client of the local Open_vSwitch database, which configures ovs in batched
+// transactions against the monitored cache instead of running ovs-vsctl for each operation
+type OVSClient struct {
+	ovsDbClient
+}
+
+func NewOVSClient(ovsAddr string, ovsTimeout int) (*OVSClient, error) {
+	dbModel, err := vswitch.FullDatabaseModel()
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	monitors := []client.MonitorOption{
+		client.WithTable(&vswitch.OpenvSwitch{}),
+		client.WithTable(&vswitch.Bridge{}),
+		client.WithTable(&vswitch.Port{}),
+		client.WithTable(&vswitch.Interface{}),
+		client.WithTable(&vswitch.QoS{}),
+		client.WithTable(&vswitch.Queue{}),
+		client.WithTable(&vswitch.Mirror{}),
+	}
+	ovsClient, err := ovsclient.NewOvsDbClient(ovsclient.VSWITCHDB, ovsAddr, dbModel, monitors)
+	if err != nil {
+		klog.Errorf("failed to create OVS client: %v", err)
+		return nil, err
+	}
+
+	c := &OVSClient{
+		ovsDbClient: ovsDbClient{
+			Client:  ovsClient,
+			Timeout: time.Duration(ovsTimeout) * time.Second,
+		},
+	}
+	return c, nil
+}
+
+// GetBridge returns the bridge by name
+func (c *OVSClient) GetBridge(name string) (*vswitch.Bridge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	bridge := &vswitch.Bridge{Name: name}
+	if err := c.Get(ctx, bridge); err != nil {
+		klog.Error(err)
+		return nil, fmt.Errorf("get bridge %s: %v", name, err)
+	}
+	return bridge, nil
+}
+
+// GetPort returns the port by name, nil is returned if the port does not exist
+func (c *OVSClient) GetPort(name string) (*vswitch.Port, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	port := &vswitch.Port{Name: name}
+	if err := c.Get(ctx, port); err != nil {
+		if err == client.ErrNotFound {
+			return nil, nil
+		}
+		klog.Error(err)
+		return nil, fmt.Errorf("get port %s: %v", name, err)
+	}
+	return port, nil
+}
+
+// ListInterfacesByIfaceID returns the interfaces whose external_ids:iface-id is the given id
+func (c *OVSClient) ListInterfacesByIfaceID(ifaceID string) ([]vswitch.Interface, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	var ifaces []vswitch.Interface
+	if err := c.WhereCache(func(iface *vswitch.Interface) bool {
+		return iface.ExternalIDs["iface-id"] == ifaceID
+	}).List(ctx, &ifaces); err != nil {
+		klog.Error(err)
+		return nil, fmt.Errorf("list interfaces with iface-id %s: %v", ifaceID, err)
+	}
+	return ifaces, nil
+}
+
+// AddPort adds the port with an interface of the same name to the bridge like ovs-vsctl --may-exist add-port,
+// the type and the external ids of the interface are updated if the port already exists. The iface-id in the
+// external ids is removed from the other interfaces in the same transaction, since only the latest sandbox of a
+// pod should own the iface-id.
+func (c *OVSClient) AddPort(bridgeName, portName, ifaceType string, externalIDs map[string]string) error {
+	var ops []ovsdb.Operation
+	if ifaceID := externalIDs["iface-id"]; ifaceID != "" {
+		ifaces, err := c.ListInterfacesByIfaceID(ifaceID)
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		for _, iface := range ifaces {
+			if iface.Name == portName {
+				continue
+			}
+			iface := iface
+			mutateOps, err := c.Where(&iface).Mutate(&iface, model.Mutation{
+				Field:   &iface.ExternalIDs,
+				Mutator: ovsdb.MutateOperationDelete,
+				Value:   []string{"iface-id"},
+			})
+			if err != nil {
+				klog.Error(err)
+				return fmt.Errorf("generate operations for clearing iface-id of interface %s: %v", iface.Name, err)
+			}
+			ops = append(ops, mutateOps...)
+		}
+	}
+
+	port, err := c.GetPort(portName)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if port != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+		defer cancel()
+		iface := &vswitch.Interface{Name: portName}
+		if err = c.Get(ctx, iface); err != nil {
+			klog.Error(err)
+			return fmt.Errorf("get interface %s: %v", portName, err)
+		}
+		if iface.Type != ifaceType || !maps.Equal(iface.ExternalIDs, mergeExternalIDs(iface.ExternalIDs, externalIDs)) {
+			iface.Type = ifaceType
+			iface.ExternalIDs = mergeExternalIDs(iface.ExternalIDs, externalIDs)
+			updateOps, err := c.Where(iface).Update(iface, &iface.Type, &iface.ExternalIDs)
+			if err != nil {
+				klog.Error(err)
+				return fmt.Errorf("generate operations for updating interface %s: %v", portName, err)
+			}
+			ops = append(ops, updateOps...)
+		}
+	} else {
+		bridge, err := c.GetBridge(bridgeName)
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		iface := &vswitch.Interface{
+			UUID:        ovsclient.NamedUUID(),
+			Name:        portName,
+			Type:        ifaceType,
+			ExternalIDs: externalIDs,
+		}
+		port := &vswitch.Port{
+			UUID:       ovsclient.NamedUUID(),
+			Name:       portName,
+			Interfaces: []string{iface.UUID},
+		}
+		createOps, err := c.Create(iface, port)
+		if err != nil {
+			klog.Error(err)
+			return fmt.Errorf("generate operations for creating port %s: %v", portName, err)
+		}
+		mutateOps, err := c.Where(bridge).Mutate(bridge, model.Mutation{
+			Field:   &bridge.Ports,
+			Mutator: ovsdb.MutateOperationInsert,
+			Value:   []string{port.UUID},
+		})
+		if err != nil {
+			klog.Error(err)
+			return fmt.Errorf("generate operations for adding port %s to bridge %s: %v", portName, bridgeName, err)
+		}
+		ops = append(ops, createOps...)
+		ops = append(ops, mutateOps...)
+	}
+
+	if err = c.Transact("add-port", ops); err != nil {
+		return fmt.Errorf("add port %s to bridge %s: %v", portName, bridgeName, err)
+	}
+	return nil
+}
+
+// DeletePort deletes the port and its interfaces from the bridge like ovs-vsctl --if-exists --with-iface del-port
+func (c *OVSClient) DeletePort(bridgeName, portName string) error {
+	port, err := c.GetPort(portName)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if port == nil {
+		return nil
+	}
+	bridge, err := c.GetBridge(bridgeName)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if !slices.Contains(bridge.Ports, port.UUID) {
+		return nil
+	}
+
+	// the port and its interfaces are garbage collected once they are not referenced by the bridge
+	ops, err := c.Where(bridge).Mutate(bridge, model.Mutation{
+		Field:   &bridge.Ports,
+		Mutator: ovsdb.MutateOperationDelete,
+		Value:   []string{port.UUID},
+	})
+	if err != nil {
+		klog.Error(err)
+		return fmt.Errorf("generate operations for deleting port %s from bridge %s: %v", portName, bridgeName, err)
+	}
+	if err = c.Transact("del-port", ops); err != nil {
+		return fmt.Errorf("delete port %s from bridge %s: %v", portName, bridgeName, err)
+	}
+	return nil
+}
+
+// ConfigInterfaceMirror adds the ports of the interfaces with the iface-id to the default mirror or removes them
+// from the mirror in one transaction, nothing is done if all ports are mirrored globally
+func (c *OVSClient) ConfigInterfaceMirror(globalMirror bool, open, ifaceID string) error {
+	if globalMirror {
+		return nil
+	}
+	ifaces, err := c.ListInterfacesByIfaceID(ifaceID)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if len(ifaces) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+	var mirrors []vswitch.Mirror
+	if err = c.WhereCache(func(mirror *vswitch.Mirror) bool {
+		return mirror.Name == util.MirrorDefaultName
+	}).List(ctx, &mirrors); err != nil {
+		klog.Error(err)
+		return fmt.Errorf("list mirror %s: %v", util.MirrorDefaultName, err)
+	}
+	if len(mirrors) == 0 {
+		return fmt.Errorf("find mirror failed, mirror name=%s", util.MirrorDefaultName)
+	}
+	if len(mirrors) > 1 {
+		return fmt.Errorf("repeated mirror data, mirror name=%s", util.MirrorDefaultName)
+	}
+	mirror := &mirrors[0]
+
+	portUUIDs := make([]string, 0, len(ifaces))
+	for _, iface := range ifaces {
+		port, err := c.GetPort(iface.Name)
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		if port == nil {
+			return fmt.Errorf("find port failed, portName=%s", iface.Name)
+		}
+		mirrored := slices.Contains(mirror.SelectDstPort, port.UUID)
+		if (open == "true") != mirrored {
+			portUUIDs = append(portUUIDs, port.UUID)
+		}
+	}
+	if len(portUUIDs) == 0 {
+		return nil
+	}
+
+	mutator := ovsdb.MutateOperationDelete
+	if open == "true" {
+		mutator = ovsdb.MutateOperationInsert
+	}
+	ops, err := c.Where(mirror).Mutate(mirror, model.Mutation{
+		Field:   &mirror.SelectDstPort,
+		Mutator: mutator,
+		Value:   portUUIDs,
+	})
+	if err != nil {
+		klog.Error(err)
+		return fmt.Errorf("generate operations for updating mirror %s: %v", util.MirrorDefaultName, err)
+	}
+	if err = c.Transact("mirror-update", ops); err != nil {
+		return fmt.Errorf("update ports %v of mirror %s: %v", portUUIDs, util.MirrorDefaultName, err)
+	}
+	return nil
+}
+
+// mergeExternalIDs returns a copy of the external ids updated by the given ones
+func mergeExternalIDs(externalIDs, updates map[string]string) map[string]string {
+	merged := make(map[string]string, len(externalIDs)+len(updates))
+	maps.Copy(merged, externalIDs)
+	maps.Copy(merged, updates)
+	return merged
+}
@@ -0,0 +1,217 @@
+package ovs
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"k8s.io/klog/v2"
+
+	ovsclient "github.com/kubeovn/kube-ovn/pkg/ovsdb/client"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/vswitch"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// SetInterfaceBandwidth configures the ingress policing and the egress htb qos of the interfaces with the iface-id
+// in one transaction, the qos and the queue are destroyed once neither bandwidth nor priority is configured
+func (c *OVSClient) SetInterfaceBandwidth(podName, podNamespace, ifaceID, ingress, egress string) error {
+	ingressMPS, _ := strconv.Atoi(ingress)
+	ingressKPS := ingressMPS * 1000
+	egressMPS, _ := strconv.Atoi(egress)
+	egressBPS := egressMPS * 1000 * 1000
+
+	ifaces, err := c.ListInterfacesByIfaceID(ifaceID)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if len(ifaces) == 0 {
+		return nil
+	}
+	qos, queue, err := c.getIfaceQoSQueue(ifaceID)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	var ops []ovsdb.Operation
+	appendOps := func(newOps []ovsdb.Operation, err error) error {
+		if err != nil {
+			klog.Error(err)
+			return fmt.Errorf("generate operations for bandwidth of iface %s: %v", ifaceID, err)
+		}
+		ops = append(ops, newOps...)
+		return nil
+	}
+
+	ports := make([]*vswitch.Port, 0, len(ifaces))
+	for _, iface := range ifaces {
+		port, err := c.GetPort(iface.Name)
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		if port != nil {
+			ports = append(ports, port)
+		}
+
+		// ingress_policing_rate is in Kbps
+		if iface.IngressPolicingRate == ingressKPS && iface.IngressPolicingBurst == ingressKPS*8/10 {
+			continue
+		}
+		iface := iface
+		iface.IngressPolicingRate, iface.IngressPolicingBurst = ingressKPS, ingressKPS*8/10
+		if err = appendOps(c.Where(&iface).Update(&iface, &iface.IngressPolicingRate, &iface.IngressPolicingBurst)); err != nil {
+			return err
+		}
+	}
+
+	externalIDs := map[string]string{"iface-id": ifaceID}
+	if podNamespace != "" && podName != "" {
+		externalIDs["pod"] = fmt.Sprintf("%s/%s", podNamespace, podName)
+	}
+
+	switch {
+	case egressBPS > 0:
+		maxRate := strconv.Itoa(egressBPS)
+		if queue == nil {
+			queue = &vswitch.Queue{
+				UUID:        ovsclient.NamedUUID(),
+				OtherConfig: map[string]string{"max-rate": maxRate},
+				ExternalIDs: externalIDs,
+			}
+			if err = appendOps(c.Create(queue)); err != nil {
+				return err
+			}
+		} else if queue.OtherConfig["max-rate"] != maxRate {
+			queue.OtherConfig = mergeExternalIDs(queue.OtherConfig, map[string]string{"max-rate": maxRate})
+			if err = appendOps(c.Where(queue).Update(queue, &queue.OtherConfig)); err != nil {
+				return err
+			}
+		}
+
+		if qos == nil {
+			qos = &vswitch.QoS{
+				UUID:        ovsclient.NamedUUID(),
+				Type:        util.HtbQos,
+				Queues:      map[int]string{0: queue.UUID},
+				ExternalIDs: externalIDs,
+			}
+			if err = appendOps(c.Create(qos)); err != nil {
+				return err
+			}
+		} else if qos.Type != util.HtbQos || qos.Queues[0] != queue.UUID {
+			if qos.Type != util.HtbQos {
+				klog.Errorf("netem qos exists for pod %s/%s, conflict with current qos, will be changed to htb qos", podNamespace, podName)
+			}
+			qos.Type = util.HtbQos
+			qos.Queues = map[int]string{0: queue.UUID}
+			if err = appendOps(c.Where(qos).Update(qos, &qos.Type, &qos.Queues)); err != nil {
+				return err
+			}
+		}
+		// the port may be re-added with the iface-id after the qos is created
+		for _, port := range ports {
+			if port.QOS != nil && *port.QOS == qos.UUID {
+				continue
+			}
+			port.QOS = &qos.UUID
+			if err = appendOps(c.Where(port).Update(port, &port.QOS)); err != nil {
+				return err
+			}
+		}
+	case qos != nil && qos.Type == util.HtbQos && queue != nil:
+		if _, ok := queue.OtherConfig["max-rate"]; !ok {
+			break
+		}
+		otherConfig := maps.Clone(queue.OtherConfig)
+		delete(otherConfig, "max-rate")
+		if len(otherConfig) != 0 {
+			queue.OtherConfig = otherConfig
+			if err = appendOps(c.Where(queue).Update(queue, &queue.OtherConfig)); err != nil {
+				return err
+			}
+			break
+		}
+
+		// neither bandwidth nor priority exists, delete the qos and the queue, the qos may still be referenced
+		// by the ports whose iface-id is taken over by a re-added port
+		qosPorts, err := c.listPortsByQoS(qos.UUID)
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		for _, port := range qosPorts {
+			port.QOS = nil
+			if err = appendOps(c.Where(port).Update(port, &port.QOS)); err != nil {
+				return err
+			}
+		}
+		if err = appendOps(c.Where(qos).Delete()); err != nil {
+			return err
+		}
+		if err = appendOps(c.Where(queue).Delete()); err != nil {
+			return err
+		}
+	}
+
+	if len(ops) == 0 {
+		return nil
+	}
+	if err = c.Transact("set-bandwidth", ops); err != nil {
+		return fmt.Errorf("set bandwidth of iface %s: %v", ifaceID, err)
+	}
+	return nil
+}
+
+// listPortsByQoS returns the ports referencing the qos
+func (c *OVSClient) listPortsByQoS(qosUUID string) ([]*vswitch.Port, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	var ports []vswitch.Port
+	if err := c.WhereCache(func(port *vswitch.Port) bool {
+		return port.QOS != nil && *port.QOS == qosUUID
+	}).List(ctx, &ports); err != nil {
+		klog.Error(err)
+		return nil, fmt.Errorf("list ports with qos %s: %v", qosUUID, err)
+	}
+	result := make([]*vswitch.Port, 0, len(ports))
+	for i := range ports {
+		result = append(result, &ports[i])
+	}
+	return result, nil
+}
+
+// getIfaceQoSQueue returns the qos and the queue created for the iface-id
+func (c *OVSClient) getIfaceQoSQueue(ifaceID string) (*vswitch.QoS, *vswitch.Queue, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	var qosList []vswitch.QoS
+	if err := c.WhereCache(func(qos *vswitch.QoS) bool {
+		return qos.ExternalIDs["iface-id"] == ifaceID
+	}).List(ctx, &qosList); err != nil {
+		klog.Error(err)
+		return nil, nil, fmt.Errorf("list qos with iface-id %s: %v", ifaceID, err)
+	}
+	var queues []vswitch.Queue
+	if err := c.WhereCache(func(queue *vswitch.Queue) bool {
+		return queue.ExternalIDs["iface-id"] == ifaceID
+	}).List(ctx, &queues); err != nil {
+		klog.Error(err)
+		return nil, nil, fmt.Errorf("list queues with iface-id %s: %v", ifaceID, err)
+	}
+
+	var qos *vswitch.QoS
+	var queue *vswitch.Queue
+	if len(qosList) != 0 {
+		qos = &qosList[0]
+	}
+	if len(queues) != 0 {
+		queue = &queues[0]
+	}
+	return qos, queue, nil
+}
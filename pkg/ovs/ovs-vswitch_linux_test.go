@@ -0,0 +1,167 @@
+package ovs
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+func (suite *OvnClientTestSuite) Test_OVSSetInterfaceBandwidth() {
+	suite.testOVSSetInterfaceBandwidth()
+}
+
+func (suite *OvnClientTestSuite) testOVSSetInterfaceBandwidth() {
+	t := suite.T()
+	t.Parallel()
+
+	ovsClient := suite.ovsClient
+	ifaceID := "bandwidth-pod.ns"
+	err := ovsClient.AddPort("br-int", "bandwidth-c1", "", map[string]string{"iface-id": ifaceID})
+	require.NoError(t, err)
+
+	t.Run("set ingress and egress", func(t *testing.T) {
+		err := ovsClient.SetInterfaceBandwidth("bandwidth-pod", "ns", ifaceID, "10", "20")
+		require.NoError(t, err)
+
+		ifaces, err := ovsClient.ListInterfacesByIfaceID(ifaceID)
+		require.NoError(t, err)
+		require.Len(t, ifaces, 1)
+		require.Equal(t, 10000, ifaces[0].IngressPolicingRate)
+		require.Equal(t, 8000, ifaces[0].IngressPolicingBurst)
+
+		qos, queue, err := ovsClient.getIfaceQoSQueue(ifaceID)
+		require.NoError(t, err)
+		require.NotNil(t, qos)
+		require.NotNil(t, queue)
+		require.Equal(t, util.HtbQos, qos.Type)
+		require.Equal(t, map[int]string{0: queue.UUID}, qos.Queues)
+		require.Equal(t, "20000000", queue.OtherConfig["max-rate"])
+		require.Equal(t, "ns/bandwidth-pod", queue.ExternalIDs["pod"])
+
+		port, err := ovsClient.GetPort("bandwidth-c1")
+		require.NoError(t, err)
+		require.NotNil(t, port.QOS)
+		require.Equal(t, qos.UUID, *port.QOS)
+	})
+
+	t.Run("update egress", func(t *testing.T) {
+		qos, queue, err := ovsClient.getIfaceQoSQueue(ifaceID)
+		require.NoError(t, err)
+
+		err = ovsClient.SetInterfaceBandwidth("bandwidth-pod", "ns", ifaceID, "10", "30")
+		require.NoError(t, err)
+
+		newQoS, newQueue, err := ovsClient.getIfaceQoSQueue(ifaceID)
+		require.NoError(t, err)
+		require.Equal(t, qos.UUID, newQoS.UUID)
+		require.Equal(t, queue.UUID, newQueue.UUID)
+		require.Equal(t, "30000000", newQueue.OtherConfig["max-rate"])
+	})
+
+	t.Run("re-add port with the iface-id", func(t *testing.T) {
+		qos, _, err := ovsClient.getIfaceQoSQueue(ifaceID)
+		require.NoError(t, err)
+
+		err = ovsClient.AddPort("br-int", "bandwidth-c2", "", map[string]string{"iface-id": ifaceID})
+		require.NoError(t, err)
+		err = ovsClient.SetInterfaceBandwidth("bandwidth-pod", "ns", ifaceID, "10", "30")
+		require.NoError(t, err)
+
+		newQoS, _, err := ovsClient.getIfaceQoSQueue(ifaceID)
+		require.NoError(t, err)
+		require.Equal(t, qos.UUID, newQoS.UUID)
+
+		// the iface-id is taken over by the re-added port
+		ifaces, err := ovsClient.ListInterfacesByIfaceID(ifaceID)
+		require.NoError(t, err)
+		require.Len(t, ifaces, 1)
+		require.Equal(t, "bandwidth-c2", ifaces[0].Name)
+		require.Equal(t, 10000, ifaces[0].IngressPolicingRate)
+
+		port, err := ovsClient.GetPort("bandwidth-c2")
+		require.NoError(t, err)
+		require.NotNil(t, port.QOS)
+		require.Equal(t, qos.UUID, *port.QOS)
+	})
+
+	t.Run("clear bandwidth", func(t *testing.T) {
+		err := ovsClient.SetInterfaceBandwidth("bandwidth-pod", "ns", ifaceID, "", "")
+		require.NoError(t, err)
+
+		ifaces, err := ovsClient.ListInterfacesByIfaceID(ifaceID)
+		require.NoError(t, err)
+		for _, iface := range ifaces {
+			require.Zero(t, iface.IngressPolicingRate)
+			require.Zero(t, iface.IngressPolicingBurst)
+		}
+
+		qos, queue, err := ovsClient.getIfaceQoSQueue(ifaceID)
+		require.NoError(t, err)
+		require.Nil(t, qos)
+		require.Nil(t, queue)
+
+		for _, portName := range []string{"bandwidth-c1", "bandwidth-c2"} {
+			port, err := ovsClient.GetPort(portName)
+			require.NoError(t, err)
+			require.Nil(t, port.QOS)
+		}
+	})
+
+	t.Run("interface not found", func(t *testing.T) {
+		err := ovsClient.SetInterfaceBandwidth("bandwidth-none", "ns", "bandwidth-none.ns", "10", "20")
+		require.NoError(t, err)
+	})
+}
+
+// BenchmarkPodPortSetup compares the latency of setting up the ovs port of a pod, including the port creation and
+// the bandwidth configuration, by libovsdb transactions and by ovs-vsctl commands. It runs against a real ovsdb
+// server specified by KUBE_OVN_BENCH_OVSDB, e.g. unix:/run/openvswitch/db.sock, on which br-int exists.
+func BenchmarkPodPortSetup(b *testing.B) {
+	endpoint := os.Getenv("KUBE_OVN_BENCH_OVSDB")
+	if endpoint == "" {
+		b.Skip("KUBE_OVN_BENCH_OVSDB is not set")
+	}
+	if _, err := exec.LookPath(OvsVsCtl); err != nil {
+		b.Skipf("%s not found", OvsVsCtl)
+	}
+
+	ovsClient, err := NewOVSClient(endpoint, 10)
+	require.NoError(b, err)
+	defer ovsClient.Close()
+
+	b.Run("ovs-vsctl", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			portName, ifaceID := fmt.Sprintf("bench-vsctl-%d", i), fmt.Sprintf("bench-vsctl-%d.ns", i)
+			CleanDuplicatePort(ifaceID, portName)
+			_, err := Exec(MayExist, "add-port", "br-int", portName, "--",
+				"set", "interface", portName, "type=internal", fmt.Sprintf("external_ids:iface-id=%s", ifaceID))
+			require.NoError(b, err)
+			require.NoError(b, SetInterfaceBandwidth("bench", "ns", ifaceID, "10", "20"))
+
+			b.StopTimer()
+			require.NoError(b, ClearPodBandwidth("bench", "ns", ifaceID))
+			require.NoError(b, ClearHtbQosQueue("bench", "ns", ifaceID))
+			_, err = Exec(IfExists, "--with-iface", "del-port", "br-int", portName)
+			require.NoError(b, err)
+			b.StartTimer()
+		}
+	})
+
+	b.Run("libovsdb", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			portName, ifaceID := fmt.Sprintf("bench-ovsdb-%d", i), fmt.Sprintf("bench-ovsdb-%d.ns", i)
+			require.NoError(b, ovsClient.AddPort("br-int", portName, "internal", map[string]string{"iface-id": ifaceID}))
+			require.NoError(b, ovsClient.SetInterfaceBandwidth("bench", "ns", ifaceID, "10", "20"))
+
+			b.StopTimer()
+			require.NoError(b, ovsClient.SetInterfaceBandwidth("bench", "ns", ifaceID, "", ""))
+			require.NoError(b, ovsClient.DeletePort("br-int", portName))
+			b.StartTimer()
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package ovs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ovn-org/libovsdb/model"
+	"github.com/ovn-org/libovsdb/ovsdb"
+	"github.com/stretchr/testify/require"
+
+	ovsclient "github.com/kubeovn/kube-ovn/pkg/ovsdb/client"
+	"github.com/kubeovn/kube-ovn/pkg/ovsdb/vswitch"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// createBridge creates the root Open_vSwitch record with the bridge
+func (suite *OvnClientTestSuite) createBridge(name string) {
+	t := suite.T()
+	ovsClient := suite.ovsClient
+
+	bridge := &vswitch.Bridge{UUID: ovsclient.NamedUUID(), Name: name}
+	ovs := &vswitch.OpenvSwitch{UUID: ovsclient.NamedUUID(), Bridges: []string{bridge.UUID}}
+	ops, err := ovsClient.Create(bridge, ovs)
+	require.NoError(t, err)
+	require.NoError(t, ovsClient.Transact("add-br", ops))
+}
+
+func (suite *OvnClientTestSuite) testOVSAddPort() {
+	t := suite.T()
+	t.Parallel()
+
+	ovsClient := suite.ovsClient
+	externalIDs := map[string]string{"iface-id": "add-port-pod.ns", "vendor": util.CniTypeName}
+
+	t.Run("add new port", func(t *testing.T) {
+		err := ovsClient.AddPort("br-int", "add-port-c1", "", externalIDs)
+		require.NoError(t, err)
+
+		port, err := ovsClient.GetPort("add-port-c1")
+		require.NoError(t, err)
+		require.NotNil(t, port)
+		require.Len(t, port.Interfaces, 1)
+
+		bridge, err := ovsClient.GetBridge("br-int")
+		require.NoError(t, err)
+		require.Contains(t, bridge.Ports, port.UUID)
+
+		ifaces, err := ovsClient.ListInterfacesByIfaceID("add-port-pod.ns")
+		require.NoError(t, err)
+		require.Len(t, ifaces, 1)
+		require.Equal(t, "add-port-c1", ifaces[0].Name)
+		require.Equal(t, util.CniTypeName, ifaces[0].ExternalIDs["vendor"])
+	})
+
+	t.Run("add existing port", func(t *testing.T) {
+		err := ovsClient.AddPort("br-int", "add-port-c1", "internal", map[string]string{"iface-id": "add-port-pod.ns", "ip": "10.16.0.2"})
+		require.NoError(t, err)
+
+		ifaces, err := ovsClient.ListInterfacesByIfaceID("add-port-pod.ns")
+		require.NoError(t, err)
+		require.Len(t, ifaces, 1)
+		require.Equal(t, "internal", ifaces[0].Type)
+		require.Equal(t, util.CniTypeName, ifaces[0].ExternalIDs["vendor"])
+		require.Equal(t, "10.16.0.2", ifaces[0].ExternalIDs["ip"])
+	})
+
+	t.Run("take over iface-id of duplicate port", func(t *testing.T) {
+		err := ovsClient.AddPort("br-int", "add-port-c2", "", externalIDs)
+		require.NoError(t, err)
+
+		ifaces, err := ovsClient.ListInterfacesByIfaceID("add-port-pod.ns")
+		require.NoError(t, err)
+		require.Len(t, ifaces, 1)
+		require.Equal(t, "add-port-c2", ifaces[0].Name)
+	})
+
+	t.Run("add port to non-existent bridge", func(t *testing.T) {
+		err := ovsClient.AddPort("br-none", "add-port-c3", "", nil)
+		require.Error(t, err)
+	})
+}
+
+func (suite *OvnClientTestSuite) testOVSDeletePort() {
+	t := suite.T()
+	t.Parallel()
+
+	ovsClient := suite.ovsClient
+
+	err := ovsClient.AddPort("br-int", "del-port-c1", "", map[string]string{"iface-id": "del-port-pod.ns"})
+	require.NoError(t, err)
+	port, err := ovsClient.GetPort("del-port-c1")
+	require.NoError(t, err)
+	require.NotNil(t, port)
+
+	err = ovsClient.DeletePort("br-int", "del-port-c1")
+	require.NoError(t, err)
+	bridge, err := ovsClient.GetBridge("br-int")
+	require.NoError(t, err)
+	require.NotContains(t, bridge.Ports, port.UUID)
+
+	// delete again
+	err = ovsClient.DeletePort("br-int", "del-port-c1")
+	require.NoError(t, err)
+
+	// delete non-existent port
+	err = ovsClient.DeletePort("br-int", "del-port-none")
+	require.NoError(t, err)
+}
+
+func (suite *OvnClientTestSuite) testOVSConfigInterfaceMirror() {
+	t := suite.T()
+	t.Parallel()
+
+	ovsClient := suite.ovsClient
+
+	bridge, err := ovsClient.GetBridge("br-int")
+	require.NoError(t, err)
+	mirror := &vswitch.Mirror{UUID: ovsclient.NamedUUID(), Name: util.MirrorDefaultName}
+	ops, err := ovsClient.Create(mirror)
+	require.NoError(t, err)
+	mutateOps, err := ovsClient.Where(bridge).Mutate(bridge, model.Mutation{
+		Field:   &bridge.Mirrors,
+		Mutator: ovsdb.MutateOperationInsert,
+		Value:   []string{mirror.UUID},
+	})
+	require.NoError(t, err)
+	require.NoError(t, ovsClient.Transact("mirror-add", append(ops, mutateOps...)))
+
+	err = ovsClient.AddPort("br-int", "mirror-c1", "", map[string]string{"iface-id": "mirror-pod.ns"})
+	require.NoError(t, err)
+	port, err := ovsClient.GetPort("mirror-c1")
+	require.NoError(t, err)
+
+	getMirror := func() *vswitch.Mirror {
+		var mirrors []vswitch.Mirror
+		require.NoError(t, ovsClient.WhereCache(func(m *vswitch.Mirror) bool {
+			return m.Name == util.MirrorDefaultName
+		}).List(context.Background(), &mirrors))
+		require.Len(t, mirrors, 1)
+		return &mirrors[0]
+	}
+
+	err = ovsClient.ConfigInterfaceMirror(false, "true", "mirror-pod.ns")
+	require.NoError(t, err)
+	require.Equal(t, []string{port.UUID}, getMirror().SelectDstPort)
+
+	// open again
+	err = ovsClient.ConfigInterfaceMirror(false, "true", "mirror-pod.ns")
+	require.NoError(t, err)
+	require.Equal(t, []string{port.UUID}, getMirror().SelectDstPort)
+
+	// global mirror is not changed
+	err = ovsClient.ConfigInterfaceMirror(true, "false", "mirror-pod.ns")
+	require.NoError(t, err)
+	require.Equal(t, []string{port.UUID}, getMirror().SelectDstPort)
+
+	err = ovsClient.ConfigInterfaceMirror(false, "false", "mirror-pod.ns")
+	require.NoError(t, err)
+	require.Empty(t, getMirror().SelectDstPort)
+
+	// interface not found
+	err = ovsClient.ConfigInterfaceMirror(false, "true", "mirror-none.ns")
+	require.NoError(t, err)
+}
@@ -24,6 +24,15 @@ const (
 	SBDB   = "sbdb"
 	ICNBDB = "icnbdb"
 	ICSBDB = "icsbdb"
+
+	// VSWITCHDB is the local Open_vSwitch database, which is not clustered
+	VSWITCHDB = "vswitchdb"
+
+	// SERVERDB is the _Server database of the local ovsdb-server, LOCALNBDB and LOCALSBDB are the
+	// NB and SB databases served by the local ovsdb-server, which are read whether it is the raft leader or not
+	SERVERDB  = "serverdb"
+	LOCALNBDB = "localnbdb"
+	LOCALSBDB = "localsbdb"
 )
 const timeout = 3 * time.Second
 
@@ -46,7 +55,7 @@ func NewOvsDbClient(db, addr string, dbModel model.ClientDBModel, monitors []cli
 	logger := klog.NewKlogr().WithName("libovsdb").WithValues("db", db)
 	options := []client.Option{
 		client.WithReconnect(timeout, &backoff.ConstantBackOff{Interval: time.Second}),
-		client.WithLeaderOnly(true),
+		client.WithLeaderOnly(db != VSWITCHDB && db != SERVERDB && db != LOCALNBDB && db != LOCALSBDB),
 		client.WithLogger(&logger),
 	}
 	klog.Infof("connecting to OVN %s server %s", db, addr)
@@ -0,0 +1,14 @@
+// Code generated by "libovsdb.modelgen"
+// DO NOT EDIT.
+
+package ovnicsb
+
+const DatapathBindingTable = "Datapath_Binding"
+
+// DatapathBinding defines an object in Datapath_Binding table
+type DatapathBinding struct {
+	UUID          string            `ovsdb:"_uuid"`
+	ExternalIDs   map[string]string `ovsdb:"external_ids"`
+	TransitSwitch string            `ovsdb:"transit_switch"`
+	TunnelKey     int               `ovsdb:"tunnel_key"`
+}
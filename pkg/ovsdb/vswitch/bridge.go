@@ -0,0 +1,28 @@
+// Code generated by "libovsdb.modelgen"
+// DO NOT EDIT.
+
+package vswitch
+
+const BridgeTable = "Bridge"
+
+type (
+	BridgeFailMode = string
+)
+
+var (
+	BridgeFailModeStandalone BridgeFailMode = "standalone"
+	BridgeFailModeSecure     BridgeFailMode = "secure"
+)
+
+// Bridge defines an object in Bridge table
+type Bridge struct {
+	UUID         string            `ovsdb:"_uuid"`
+	DatapathID   *string           `ovsdb:"datapath_id"`
+	DatapathType string            `ovsdb:"datapath_type"`
+	ExternalIDs  map[string]string `ovsdb:"external_ids"`
+	FailMode     *BridgeFailMode   `ovsdb:"fail_mode"`
+	Mirrors      []string          `ovsdb:"mirrors"`
+	Name         string            `ovsdb:"name"`
+	OtherConfig  map[string]string `ovsdb:"other_config"`
+	Ports        []string          `ovsdb:"ports"`
+}
@@ -0,0 +1,25 @@
+// Code generated by "libovsdb.modelgen"
+// DO NOT EDIT.
+
+package vswitch
+
+const InterfaceTable = "Interface"
+
+// Interface defines an object in Interface table
+type Interface struct {
+	UUID                 string            `ovsdb:"_uuid"`
+	Error                *string           `ovsdb:"error"`
+	ExternalIDs          map[string]string `ovsdb:"external_ids"`
+	IngressPolicingBurst int               `ovsdb:"ingress_policing_burst"`
+	IngressPolicingRate  int               `ovsdb:"ingress_policing_rate"`
+	MACInUse             *string           `ovsdb:"mac_in_use"`
+	MTU                  *int              `ovsdb:"mtu"`
+	MTURequest           *int              `ovsdb:"mtu_request"`
+	Name                 string            `ovsdb:"name"`
+	Ofport               *int              `ovsdb:"ofport"`
+	OfportRequest        *int              `ovsdb:"ofport_request"`
+	Options              map[string]string `ovsdb:"options"`
+	OtherConfig          map[string]string `ovsdb:"other_config"`
+	Status               map[string]string `ovsdb:"status"`
+	Type                 string            `ovsdb:"type"`
+}
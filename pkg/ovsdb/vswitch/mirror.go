@@ -0,0 +1,19 @@
+// Code generated by "libovsdb.modelgen"
+// DO NOT EDIT.
+
+package vswitch
+
+const MirrorTable = "Mirror"
+
+// Mirror defines an object in Mirror table
+type Mirror struct {
+	UUID          string            `ovsdb:"_uuid"`
+	ExternalIDs   map[string]string `ovsdb:"external_ids"`
+	Name          string            `ovsdb:"name"`
+	OutputPort    *string           `ovsdb:"output_port"`
+	OutputVLAN    *int              `ovsdb:"output_vlan"`
+	SelectAll     bool              `ovsdb:"select_all"`
+	SelectDstPort []string          `ovsdb:"select_dst_port"`
+	SelectSrcPort []string          `ovsdb:"select_src_port"`
+	SelectVLAN    []int             `ovsdb:"select_vlan"`
+}
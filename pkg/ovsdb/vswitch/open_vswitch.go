@@ -0,0 +1,19 @@
+// Code generated by "libovsdb.modelgen"
+// DO NOT EDIT.
+
+package vswitch
+
+const OpenvSwitchTable = "Open_vSwitch"
+
+// OpenvSwitch defines an object in Open_vSwitch table
+type OpenvSwitch struct {
+	UUID          string            `ovsdb:"_uuid"`
+	Bridges       []string          `ovsdb:"bridges"`
+	CurCfg        int               `ovsdb:"cur_cfg"`
+	DatapathTypes []string          `ovsdb:"datapath_types"`
+	ExternalIDs   map[string]string `ovsdb:"external_ids"`
+	IfaceTypes    []string          `ovsdb:"iface_types"`
+	NextCfg       int               `ovsdb:"next_cfg"`
+	OtherConfig   map[string]string `ovsdb:"other_config"`
+	OVSVersion    *string           `ovsdb:"ovs_version"`
+}
@@ -0,0 +1,17 @@
+// Code generated by "libovsdb.modelgen"
+// DO NOT EDIT.
+
+package vswitch
+
+const PortTable = "Port"
+
+// Port defines an object in Port table
+type Port struct {
+	UUID        string            `ovsdb:"_uuid"`
+	ExternalIDs map[string]string `ovsdb:"external_ids"`
+	Interfaces  []string          `ovsdb:"interfaces"`
+	Name        string            `ovsdb:"name"`
+	OtherConfig map[string]string `ovsdb:"other_config"`
+	QOS         *string           `ovsdb:"qos"`
+	Tag         *int              `ovsdb:"tag"`
+}
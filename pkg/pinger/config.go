@@ -13,12 +13,14 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 
+	clientset "github.com/kubeovn/kube-ovn/pkg/client/clientset/versioned"
 	"github.com/kubeovn/kube-ovn/pkg/util"
 )
 
 type Configuration struct {
 	KubeConfigFile     string
 	KubeClient         kubernetes.Interface
+	KubeOvnClient      clientset.Interface
 	Port               int
 	DaemonSetNamespace string
 	DaemonSetName      string
@@ -36,6 +38,11 @@ type Configuration struct {
 	NetworkMode        string
 	EnableMetrics      bool
 
+	EnableConnectivityCheck bool
+	EnablePMTUCheck         bool
+	PMTUCheckInterval       int
+	TunnelType              string
+
 	// Used for OVS Monitor
 	PollTimeout                     int
 	PollInterval                    int
@@ -77,6 +84,11 @@ func ParseFlags() (*Configuration, error) {
 		argNetworkMode        = pflag.String("network-mode", "kube-ovn", "The cni plugin current cluster used, default: kube-ovn")
 		argEnableMetrics      = pflag.Bool("enable-metrics", true, "Whether to support metrics query")
 
+		argEnableConnectivityCheck = pflag.Bool("enable-connectivity-check", true, "Whether to run the connectivity checks of the node in server mode")
+		argEnablePMTUCheck         = pflag.Bool("enable-pmtu-check", false, "Whether to measure the path mtu to the pods and nodes by do-not-fragment pings")
+		argPMTUCheckInterval       = pflag.Int("pmtu-check-interval", 300, "interval seconds between consecutive path mtu checks")
+		argTunnelType              = pflag.String("tunnel-type", util.NetworkTypeGeneve, "Tunnel encapsulation protocol in overlay networks")
+
 		argPollTimeout                     = pflag.Int("ovs.timeout", 2, "Timeout on JSON-RPC requests to OVS.")
 		argPollInterval                    = pflag.Int("ovs.poll-interval", 15, "The minimum interval (in seconds) between collections from OVS server.")
 		argSystemRunDir                    = pflag.String("system.run.dir", "/var/run/openvswitch", "OVS default run directory.")
@@ -129,6 +141,11 @@ func ParseFlags() (*Configuration, error) {
 		NetworkMode:        *argNetworkMode,
 		EnableMetrics:      *argEnableMetrics,
 
+		EnableConnectivityCheck: *argEnableConnectivityCheck,
+		EnablePMTUCheck:         *argEnablePMTUCheck,
+		PMTUCheckInterval:       *argPMTUCheckInterval,
+		TunnelType:              *argTunnelType,
+
 		EnableVerboseConnCheck: *argEnableVerboseConnCheck,
 		TCPConnCheckPort:       *argTCPConnectivityCheckPort,
 		UDPConnCheckPort:       *argUDPConnectivityCheckPort,
@@ -204,6 +221,14 @@ func (config *Configuration) initKubeClient() error {
 	cfg.Timeout = 15 * time.Second
 	cfg.QPS = 1000
 	cfg.Burst = 2000
+
+	kubeOvnClient, err := clientset.NewForConfig(cfg)
+	if err != nil {
+		klog.Errorf("init kubeovn client failed %v", err)
+		return err
+	}
+	config.KubeOvnClient = kubeOvnClient
+
 	cfg.ContentType = "application/vnd.kubernetes.protobuf"
 	cfg.AcceptContentTypes = "application/vnd.kubernetes.protobuf,application/json"
 	kubeClient, err := kubernetes.NewForConfig(cfg)
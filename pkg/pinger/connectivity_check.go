@@ -0,0 +1,649 @@
+package pinger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goping "github.com/prometheus-community/pro-bing"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
+	listerv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	kubeovninformer "github.com/kubeovn/kube-ovn/pkg/client/informers/externalversions"
+	kubeovnlister "github.com/kubeovn/kube-ovn/pkg/client/listers/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+const (
+	// connectivityCheckSyncInterval is the interval to look for the connectivity checks to run
+	connectivityCheckSyncInterval = 5 * time.Second
+	// connectivityCheckWindow is the number of the latest probes of a pair kept to calculate the loss and the latency
+	connectivityCheckWindow = 100
+	// connectivityCheckProbes is the number of probes sent to each destination address in a round
+	connectivityCheckProbes      = 3
+	connectivityCheckTimeout     = time.Second
+	connectivityCheckConcurrency = 16
+	// connectivityCheckMaxResults is the max number of the failing results reported by all the source nodes,
+	// which is divided among the nodes reporting to the status
+	connectivityCheckMaxResults = 200
+	// connectivityCheckCRDInterval is the interval to wait for the connectivity check crd to be installed
+	connectivityCheckCRDInterval = time.Minute
+)
+
+type connectivityCheckTarget struct {
+	// destination is the pod or service in the format of pod/namespace/name or service/namespace/name
+	destination string
+	ip          string
+	port        int
+}
+
+func (t connectivityCheckTarget) address() string {
+	if t.port == 0 {
+		return t.ip
+	}
+	return net.JoinHostPort(t.ip, strconv.Itoa(t.port))
+}
+
+func (t connectivityCheckTarget) key() string {
+	return t.destination + "@" + t.address()
+}
+
+type connectivityCheckSample struct {
+	success bool
+	latency time.Duration
+	message string
+}
+
+// connectivityCheckPair keeps the latest probes sent to a destination address
+type connectivityCheckPair struct {
+	target             connectivityCheckTarget
+	samples            []connectivityCheckSample
+	lastFailureTime    *metav1.Time
+	lastFailureMessage string
+}
+
+func (p *connectivityCheckPair) add(samples []connectivityCheckSample, now time.Time) {
+	for _, sample := range samples {
+		if !sample.success {
+			p.lastFailureTime = &metav1.Time{Time: now}
+			p.lastFailureMessage = sample.message
+		}
+	}
+	p.samples = append(p.samples, samples...)
+	if len(p.samples) > connectivityCheckWindow {
+		p.samples = p.samples[len(p.samples)-connectivityCheckWindow:]
+	}
+}
+
+// latencyPercentile returns the nearest-rank percentile of the sorted latencies
+func latencyPercentile(latencies []time.Duration, percentile int) time.Duration {
+	rank := (len(latencies)*percentile + 99) / 100
+	return latencies[max(rank, 1)-1]
+}
+
+func formatLatency(latency time.Duration) string {
+	return latency.Round(time.Microsecond).String()
+}
+
+func (p *connectivityCheckPair) result(slo kubeovnv1.ConnectivityCheckSLO) kubeovnv1.ConnectivityCheckResult {
+	result := kubeovnv1.ConnectivityCheckResult{
+		Destination:        p.target.destination,
+		Target:             p.target.address(),
+		Sent:               len(p.samples),
+		LastFailureTime:    p.lastFailureTime,
+		LastFailureMessage: p.lastFailureMessage,
+	}
+
+	latencies := make([]time.Duration, 0, len(p.samples))
+	for _, sample := range p.samples {
+		if sample.success {
+			result.Received++
+			if sample.latency != 0 {
+				latencies = append(latencies, sample.latency)
+			}
+		}
+	}
+	if result.Received == 0 {
+		return result
+	}
+
+	result.SLOMet = (result.Sent-result.Received)*100 <= slo.MaxLossPercent*result.Sent
+	if len(latencies) != 0 {
+		slices.Sort(latencies)
+		p99 := latencyPercentile(latencies, 99)
+		result.LatencyP50 = formatLatency(latencyPercentile(latencies, 50))
+		result.LatencyP90 = formatLatency(latencyPercentile(latencies, 90))
+		result.LatencyP99 = formatLatency(p99)
+		if slo.MaxLatency != 0 && p99 > time.Duration(slo.MaxLatency)*time.Millisecond {
+			result.SLOMet = false
+		}
+	}
+	return result
+}
+
+// connectivityChecker runs the connectivity checks whose sources select the node of the pinger,
+// and reports the results to the status of the connectivity checks under the key of the node
+type connectivityChecker struct {
+	config *Configuration
+	stopCh <-chan struct{}
+
+	kubeovnInformerFactory   kubeovninformer.SharedInformerFactory
+	nodeInformerFactory      kubeinformers.SharedInformerFactory
+	connectivityChecksLister kubeovnlister.ConnectivityCheckLister
+	connectivityChecksSynced cache.InformerSynced
+	nodesLister              listerv1.NodeLister
+	nodesSynced              cache.InformerSynced
+
+	// the pods, namespaces and services are cached once a connectivity check selects the node
+	podInformerFactory  kubeinformers.SharedInformerFactory
+	kubeInformerFactory kubeinformers.SharedInformerFactory
+	podsLister          listerv1.PodLister
+	podsSynced          cache.InformerSynced
+	namespacesLister    listerv1.NamespaceLister
+	namespacesSynced    cache.InformerSynced
+	servicesLister      listerv1.ServiceLister
+	servicesSynced      cache.InformerSynced
+	kubeInformersSynced bool
+
+	// the states are keyed by the names of the connectivity checks
+	generations map[string]int64
+	lastRun     map[string]time.Time
+	pairs       map[string]map[string]*connectivityCheckPair
+}
+
+func newConnectivityChecker(config *Configuration, stopCh <-chan struct{}) *connectivityChecker {
+	kubeovnInformerFactory := kubeovninformer.NewSharedInformerFactoryWithOptions(config.KubeOvnClient, 0,
+		kubeovninformer.WithTweakListOptions(func(listOption *metav1.ListOptions) {
+			listOption.AllowWatchBookmarks = true
+		}))
+	nodeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(config.KubeClient, 0,
+		kubeinformers.WithTweakListOptions(func(listOption *metav1.ListOptions) {
+			listOption.AllowWatchBookmarks = true
+			listOption.FieldSelector = fields.OneTermEqualSelector(metav1.ObjectNameField, config.NodeName).String()
+		}))
+	// only the running pods are probed
+	podInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(config.KubeClient, 0,
+		kubeinformers.WithTweakListOptions(func(listOption *metav1.ListOptions) {
+			listOption.AllowWatchBookmarks = true
+			listOption.FieldSelector = fields.OneTermEqualSelector("status.phase", string(v1.PodRunning)).String()
+		}))
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(config.KubeClient, 0,
+		kubeinformers.WithTweakListOptions(func(listOption *metav1.ListOptions) {
+			listOption.AllowWatchBookmarks = true
+		}))
+
+	connectivityCheckInformer := kubeovnInformerFactory.Kubeovn().V1().ConnectivityChecks()
+	nodeInformer := nodeInformerFactory.Core().V1().Nodes()
+	podInformer := podInformerFactory.Core().V1().Pods()
+	namespaceInformer := kubeInformerFactory.Core().V1().Namespaces()
+	serviceInformer := kubeInformerFactory.Core().V1().Services()
+
+	return &connectivityChecker{
+		config: config,
+		stopCh: stopCh,
+
+		kubeovnInformerFactory:   kubeovnInformerFactory,
+		nodeInformerFactory:      nodeInformerFactory,
+		connectivityChecksLister: connectivityCheckInformer.Lister(),
+		connectivityChecksSynced: connectivityCheckInformer.Informer().HasSynced,
+		nodesLister:              nodeInformer.Lister(),
+		nodesSynced:              nodeInformer.Informer().HasSynced,
+
+		podInformerFactory:  podInformerFactory,
+		kubeInformerFactory: kubeInformerFactory,
+		podsLister:          podInformer.Lister(),
+		podsSynced:          podInformer.Informer().HasSynced,
+		namespacesLister:    namespaceInformer.Lister(),
+		namespacesSynced:    namespaceInformer.Informer().HasSynced,
+		servicesLister:      serviceInformer.Lister(),
+		servicesSynced:      serviceInformer.Informer().HasSynced,
+
+		generations: make(map[string]int64),
+		lastRun:     make(map[string]time.Time),
+		pairs:       make(map[string]map[string]*connectivityCheckPair),
+	}
+}
+
+func StartConnectivityChecks(config *Configuration) {
+	// the informer of connectivity checks never syncs before the crd is installed
+	for {
+		_, err := config.KubeOvnClient.KubeovnV1().ConnectivityChecks().List(context.Background(), metav1.ListOptions{Limit: 1})
+		if err == nil {
+			break
+		}
+		if k8serrors.IsNotFound(err) {
+			klog.V(3).Infof("connectivity check crd is not installed")
+		} else {
+			klog.Errorf("failed to list connectivity checks: %v", err)
+		}
+		time.Sleep(connectivityCheckCRDInterval)
+	}
+
+	stopCh := make(chan struct{})
+	checker := newConnectivityChecker(config, stopCh)
+	checker.kubeovnInformerFactory.Start(stopCh)
+	checker.nodeInformerFactory.Start(stopCh)
+	if !cache.WaitForCacheSync(stopCh, checker.connectivityChecksSynced, checker.nodesSynced) {
+		util.LogFatalAndExit(nil, "failed to wait for caches to sync")
+	}
+	wait.Until(checker.sync, connectivityCheckSyncInterval, stopCh)
+}
+
+// startKubeInformers starts caching the pods, namespaces and services for the destinations
+func (c *connectivityChecker) startKubeInformers() bool {
+	if c.kubeInformersSynced {
+		return true
+	}
+	c.podInformerFactory.Start(c.stopCh)
+	c.kubeInformerFactory.Start(c.stopCh)
+	if !cache.WaitForCacheSync(c.stopCh, c.podsSynced, c.namespacesSynced, c.servicesSynced) {
+		klog.Error("failed to wait for caches of connectivity checks to sync")
+		return false
+	}
+	c.kubeInformersSynced = true
+	return true
+}
+
+func (c *connectivityChecker) reset(name string) {
+	delete(c.generations, name)
+	delete(c.lastRun, name)
+	delete(c.pairs, name)
+}
+
+func (c *connectivityChecker) sync() {
+	ccList, err := c.connectivityChecksLister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("failed to list connectivity checks: %v", err)
+		return
+	}
+	node, err := c.nodesLister.Get(c.config.NodeName)
+	if err != nil {
+		klog.Errorf("failed to get node %s: %v", c.config.NodeName, err)
+		return
+	}
+
+	names := make(map[string]bool, len(ccList))
+	for _, cc := range ccList {
+		names[cc.Name] = true
+		if err = util.ValidateConnectivityCheck(cc); err != nil {
+			klog.V(3).Infof("skip invalid connectivity check %s: %v", cc.Name, err)
+			c.reset(cc.Name)
+			continue
+		}
+		if cc.Spec.Source.NodeSelector != nil {
+			selector, _ := metav1.LabelSelectorAsSelector(cc.Spec.Source.NodeSelector)
+			if !selector.Matches(labels.Set(node.Labels)) {
+				c.reset(cc.Name)
+				continue
+			}
+		}
+		if c.generations[cc.Name] != cc.Generation {
+			c.reset(cc.Name)
+			c.generations[cc.Name] = cc.Generation
+		}
+
+		interval := cc.Spec.Interval
+		if interval == 0 {
+			interval = util.ConnectivityCheckDefaultInterval
+		}
+		if time.Since(c.lastRun[cc.Name]) < time.Duration(interval)*time.Second {
+			continue
+		}
+		if !c.startKubeInformers() {
+			return
+		}
+		c.lastRun[cc.Name] = time.Now()
+		if err = c.run(cc); err != nil {
+			klog.Errorf("failed to run connectivity check %s: %v", cc.Name, err)
+		}
+	}
+	for name := range c.generations {
+		if !names[name] {
+			c.reset(name)
+		}
+	}
+}
+
+func (c *connectivityChecker) run(cc *kubeovnv1.ConnectivityCheck) error {
+	klog.Infof("start to run connectivity check %s", cc.Name)
+	targets, err := c.resolveTargets(cc)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	samples := make([][]connectivityCheckSample, len(targets))
+	sem := make(chan struct{}, connectivityCheckConcurrency)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, target connectivityCheckTarget) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			samples[i] = probe(cc.Spec.Protocol, target)
+		}(i, target)
+	}
+	wg.Wait()
+
+	now := time.Now()
+	pairs := make(map[string]*connectivityCheckPair, len(targets))
+	results := make([]kubeovnv1.ConnectivityCheckResult, 0, len(targets))
+	var latencies []time.Duration
+	for i, target := range targets {
+		pair := c.pairs[cc.Name][target.key()]
+		if pair == nil {
+			pair = &connectivityCheckPair{target: target}
+		}
+		pair.add(samples[i], now)
+		pairs[target.key()] = pair
+		for _, sample := range pair.samples {
+			if sample.success && sample.latency != 0 {
+				latencies = append(latencies, sample.latency)
+			}
+		}
+		result := pair.result(cc.Spec.SLO)
+		if !result.SLOMet {
+			klog.Infof("connectivity check %s from node %s to %s %s violates the slo, sent %d, received %d, p99 latency %s",
+				cc.Name, c.config.NodeName, result.Destination, result.Target, result.Sent, result.Received, result.LatencyP99)
+		}
+		results = append(results, result)
+	}
+	c.pairs[cc.Name] = pairs
+
+	nodeResult := summarizeConnectivityCheckResults(results, latencies, connectivityCheckNodeMaxResults(cc, c.config.NodeName))
+	nodeResult.LastCheckTime = metav1.Time{Time: now}
+	return c.patchResults(cc.Name, nodeResult)
+}
+
+// connectivityCheckNodeMaxResults returns the max number of the failing results reported by the node, the nodes
+// reporting to the status share connectivityCheckMaxResults so that the size of the status does not grow with them
+func connectivityCheckNodeMaxResults(cc *kubeovnv1.ConnectivityCheck, nodeName string) int {
+	nodes := len(cc.Status.Nodes)
+	if _, ok := cc.Status.Nodes[nodeName]; !ok {
+		nodes++
+	}
+	return max(connectivityCheckMaxResults/nodes, 1)
+}
+
+// summarizeConnectivityCheckResults counts the results and calculates the latency percentiles of the node,
+// only at most maxResults of the failing results are kept to bound the size of the status
+func summarizeConnectivityCheckResults(results []kubeovnv1.ConnectivityCheckResult, latencies []time.Duration, maxResults int) kubeovnv1.ConnectivityCheckNodeResult {
+	nodeResult := kubeovnv1.ConnectivityCheckNodeResult{Total: len(results)}
+	failing := make([]kubeovnv1.ConnectivityCheckResult, 0, len(results))
+	for _, result := range results {
+		if !result.SLOMet {
+			failing = append(failing, result)
+		}
+	}
+	sort.Slice(failing, func(i, j int) bool {
+		if failing[i].Destination != failing[j].Destination {
+			return failing[i].Destination < failing[j].Destination
+		}
+		return failing[i].Target < failing[j].Target
+	})
+	nodeResult.Failing = len(failing)
+	nodeResult.Results = failing[:min(len(failing), maxResults)]
+
+	if len(latencies) != 0 {
+		slices.Sort(latencies)
+		nodeResult.LatencyP50 = formatLatency(latencyPercentile(latencies, 50))
+		nodeResult.LatencyP90 = formatLatency(latencyPercentile(latencies, 90))
+		nodeResult.LatencyP99 = formatLatency(latencyPercentile(latencies, 99))
+	}
+	return nodeResult
+}
+
+// patchResults patches the results of the node only, the results of other nodes are reported by their own pingers
+func (c *connectivityChecker) patchResults(name string, result kubeovnv1.ConnectivityCheckNodeResult) error {
+	patch := map[string]interface{}{
+		"status": map[string]interface{}{
+			"nodes": map[string]interface{}{c.config.NodeName: result},
+		},
+	}
+	bytes, err := json.Marshal(patch)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	if _, err = c.config.KubeOvnClient.KubeovnV1().ConnectivityChecks().Patch(context.Background(), name,
+		types.MergePatchType, bytes, metav1.PatchOptions{}, "status"); err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		klog.Errorf("failed to patch status of connectivity check %s: %v", name, err)
+		return err
+	}
+	return nil
+}
+
+func (c *connectivityChecker) resolveTargets(cc *kubeovnv1.ConnectivityCheck) ([]connectivityCheckTarget, error) {
+	var targets []connectivityCheckTarget
+	dst := cc.Spec.Destination
+	if dst.Selector != nil || len(dst.Subnets) != 0 {
+		pods, err := c.selectPods(dst)
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range pods {
+			for _, ip := range podAddresses(pod, dst.Subnets) {
+				if slices.Contains(c.config.PodProtocols, util.CheckProtocol(ip)) {
+					targets = append(targets, connectivityCheckTarget{
+						destination: fmt.Sprintf("pod/%s/%s", pod.Namespace, pod.Name),
+						ip:          ip,
+						port:        cc.Spec.Port,
+					})
+				}
+			}
+		}
+	}
+
+	for _, key := range dst.Services {
+		namespace, name, _ := strings.Cut(key, "/")
+		svc, err := c.servicesLister.Services(namespace).Get(name)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				klog.Warningf("service %s of connectivity check %s not found", key, cc.Name)
+				continue
+			}
+			klog.Errorf("failed to get service %s: %v", key, err)
+			return nil, err
+		}
+		for _, ip := range svc.Spec.ClusterIPs {
+			if ip == "" || ip == v1.ClusterIPNone || !slices.Contains(c.config.PodProtocols, util.CheckProtocol(ip)) {
+				continue
+			}
+			for _, port := range servicePorts(svc, cc.Spec.Protocol, cc.Spec.Port) {
+				targets = append(targets, connectivityCheckTarget{destination: "service/" + key, ip: ip, port: port})
+			}
+		}
+	}
+	return targets, nil
+}
+
+func (c *connectivityChecker) selectPods(dst kubeovnv1.ConnectivityCheckDestination) ([]*v1.Pod, error) {
+	var namespaces map[string]bool
+	if dst.NamespaceSelector != nil {
+		selector, _ := metav1.LabelSelectorAsSelector(dst.NamespaceSelector)
+		nsList, err := c.namespacesLister.List(selector)
+		if err != nil {
+			klog.Errorf("failed to list namespaces: %v", err)
+			return nil, err
+		}
+		namespaces = make(map[string]bool, len(nsList))
+		for _, ns := range nsList {
+			namespaces[ns.Name] = true
+		}
+	}
+
+	selector := labels.Everything()
+	if dst.Selector != nil {
+		selector, _ = metav1.LabelSelectorAsSelector(dst.Selector)
+	}
+	podList, err := c.podsLister.List(selector)
+	if err != nil {
+		klog.Errorf("failed to list pods: %v", err)
+		return nil, err
+	}
+
+	pods := make([]*v1.Pod, 0, len(podList))
+	for _, pod := range podList {
+		if namespaces != nil && !namespaces[pod.Namespace] {
+			continue
+		}
+		if pod.Spec.HostNetwork || pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+// podAddresses returns the addresses of the pod in the subnets, which may be allocated by the attachment networks,
+// or the addresses of the default network if no subnet is specified
+func podAddresses(pod *v1.Pod, subnets []string) []string {
+	if len(subnets) == 0 {
+		addresses := make([]string, 0, len(pod.Status.PodIPs))
+		for _, podIP := range pod.Status.PodIPs {
+			addresses = append(addresses, podIP.IP)
+		}
+		return addresses
+	}
+
+	var addresses []string
+	for key, value := range pod.Annotations {
+		provider, found := strings.CutSuffix(key, ".kubernetes.io/logical_switch")
+		if !found || !slices.Contains(subnets, value) {
+			continue
+		}
+		if ips := pod.Annotations[fmt.Sprintf(util.IPAddressAnnotationTemplate, provider)]; ips != "" {
+			addresses = append(addresses, strings.Split(ips, ",")...)
+		}
+	}
+	slices.Sort(addresses)
+	return addresses
+}
+
+// servicePorts returns the port if specified, or the service ports of the protocol
+func servicePorts(svc *v1.Service, protocol kubeovnv1.ConnectivityCheckProtocol, port int) []int {
+	if port != 0 {
+		return []int{port}
+	}
+	var ports []int
+	for _, p := range svc.Spec.Ports {
+		if strings.EqualFold(string(p.Protocol), string(protocol)) {
+			ports = append(ports, int(p.Port))
+		}
+	}
+	return ports
+}
+
+func probe(protocol kubeovnv1.ConnectivityCheckProtocol, target connectivityCheckTarget) []connectivityCheckSample {
+	switch protocol {
+	case kubeovnv1.ConnectivityCheckProtocolICMP:
+		return probeICMP(target.ip)
+	case kubeovnv1.ConnectivityCheckProtocolTCP:
+		return probeRepeatedly(func() (time.Duration, error) { return probeTCP(target.address()) })
+	default:
+		return probeRepeatedly(func() (time.Duration, error) { return probeUDP(target.address()) })
+	}
+}
+
+func probeRepeatedly(f func() (time.Duration, error)) []connectivityCheckSample {
+	samples := make([]connectivityCheckSample, 0, connectivityCheckProbes)
+	for i := 0; i < connectivityCheckProbes; i++ {
+		latency, err := f()
+		if err != nil {
+			samples = append(samples, connectivityCheckSample{message: err.Error()})
+			continue
+		}
+		samples = append(samples, connectivityCheckSample{success: true, latency: latency})
+	}
+	return samples
+}
+
+func probeICMP(ip string) []connectivityCheckSample {
+	failures := func(message string) []connectivityCheckSample {
+		samples := make([]connectivityCheckSample, connectivityCheckProbes)
+		for i := range samples {
+			samples[i].message = message
+		}
+		return samples
+	}
+
+	pinger, err := goping.NewPinger(ip)
+	if err != nil {
+		klog.Errorf("failed to init pinger, %v", err)
+		return failures(err.Error())
+	}
+	pinger.SetPrivileged(true)
+	pinger.Timeout = connectivityCheckProbes * connectivityCheckTimeout
+	pinger.Count = connectivityCheckProbes
+	pinger.Interval = 100 * time.Millisecond
+	if err = pinger.Run(); err != nil {
+		klog.Errorf("failed to run pinger for destination %s: %v", ip, err)
+		return failures(err.Error())
+	}
+
+	stats := pinger.Statistics()
+	samples := make([]connectivityCheckSample, 0, connectivityCheckProbes)
+	for _, rtt := range stats.Rtts {
+		samples = append(samples, connectivityCheckSample{success: true, latency: rtt})
+	}
+	for i := stats.PacketsRecv; i < connectivityCheckProbes; i++ {
+		samples = append(samples, connectivityCheckSample{message: "icmp echo reply timeout"})
+	}
+	return samples
+}
+
+func probeTCP(address string) (time.Duration, error) {
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", address, connectivityCheckTimeout)
+	if err != nil {
+		return 0, err
+	}
+	latency := time.Since(start)
+	_ = conn.Close()
+	return latency, nil
+}
+
+// probeUDP succeeds only if the destination replies, the same as the udp check against the pingers
+func probeUDP(address string) (time.Duration, error) {
+	conn, err := net.DialTimeout("udp", address, connectivityCheckTimeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	start := time.Now()
+	if err = conn.SetDeadline(start.Add(connectivityCheckTimeout)); err != nil {
+		return 0, err
+	}
+	if _, err = conn.Write([]byte("health check")); err != nil {
+		return 0, fmt.Errorf("send udp packet failed with err %w", err)
+	}
+	buffer := make([]byte, 1024)
+	if _, err = conn.Read(buffer); err != nil {
+		return 0, fmt.Errorf("read udp packet from remote failed %w", err)
+	}
+	return time.Since(start), nil
+}
@@ -0,0 +1,83 @@
+package pinger
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+)
+
+func Test_summarizeConnectivityCheckResults(t *testing.T) {
+	t.Parallel()
+
+	var results []kubeovnv1.ConnectivityCheckResult
+	for i := 0; i < 250; i++ {
+		results = append(results, kubeovnv1.ConnectivityCheckResult{
+			Destination: fmt.Sprintf("pod/ns1/pod%03d", 249-i),
+			Target:      fmt.Sprintf("10.16.0.%d", i),
+			SLOMet:      i%50 != 0,
+		})
+	}
+	var latencies []time.Duration
+	for i := 100; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	// only the failing results are reported
+	nodeResult := summarizeConnectivityCheckResults(results, latencies, 4)
+	require.Equal(t, 250, nodeResult.Total)
+	require.Equal(t, 5, nodeResult.Failing)
+	require.Equal(t, []string{"pod/ns1/pod049", "pod/ns1/pod099", "pod/ns1/pod149", "pod/ns1/pod199"}, destinations(nodeResult.Results))
+	require.Equal(t, "50ms", nodeResult.LatencyP50)
+	require.Equal(t, "90ms", nodeResult.LatencyP90)
+	require.Equal(t, "99ms", nodeResult.LatencyP99)
+
+	nodeResult = summarizeConnectivityCheckResults(nil, nil, 4)
+	require.Zero(t, nodeResult.Total)
+	require.Empty(t, nodeResult.Results)
+	require.Empty(t, nodeResult.LatencyP99)
+}
+
+func destinations(results []kubeovnv1.ConnectivityCheckResult) []string {
+	var destinations []string
+	for _, result := range results {
+		destinations = append(destinations, result.Destination)
+	}
+	return destinations
+}
+
+func Test_connectivityCheckNodeMaxResults(t *testing.T) {
+	t.Parallel()
+
+	nodes := func(n int) map[string]kubeovnv1.ConnectivityCheckNodeResult {
+		results := make(map[string]kubeovnv1.ConnectivityCheckNodeResult, n)
+		for i := 0; i < n; i++ {
+			results[fmt.Sprintf("node%d", i)] = kubeovnv1.ConnectivityCheckNodeResult{}
+		}
+		return results
+	}
+	tests := []struct {
+		name  string
+		nodes int
+		exp   int
+	}{
+		{"first node", 0, connectivityCheckMaxResults},
+		{"reported node", 2, connectivityCheckMaxResults / 2},
+		{"new node", 3, connectivityCheckMaxResults / 4},
+		{"at least one result", 1000, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cc := &kubeovnv1.ConnectivityCheck{Status: kubeovnv1.ConnectivityCheckStatus{Nodes: nodes(tt.nodes)}}
+			nodeName := "node1"
+			if tt.name == "new node" {
+				nodeName = "node10"
+			}
+			require.Equal(t, tt.exp, connectivityCheckNodeMaxResults(cc, nodeName))
+		})
+	}
+}
@@ -110,6 +110,8 @@ func (e *Exporter) ovsMetricsUpdate() {
 
 	e.exportOvsDpGauge()
 	e.exportOvsInterfaceGauge()
+
+	e.exportOvnControllerPerformanceGauge()
 }
 
 func (e *Exporter) exportOvsStatusGauge() {
@@ -195,3 +197,10 @@ func (e *Exporter) exportOvsInterfaceGauge() {
 		e.setOvsInterfaceMetric(intf)
 	}
 }
+
+func (e *Exporter) exportOvnControllerPerformanceGauge() {
+	resetOvnControllerPerformanceMetrics()
+	e.setOvnControllerStopwatchMetric()
+	e.setOvnControllerIncEngineMetric()
+	e.setOvnControllerOpenFlowCountMetric()
+}
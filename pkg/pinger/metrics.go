@@ -191,6 +191,85 @@ var (
 			"target_node_name",
 			"target_node_ip",
 		})
+	podPMTUGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pinger_pod_pmtu",
+			Help: "The path mtu measured by do-not-fragment pings for pod peer, which is capped by the mtu of the pinger pod",
+		},
+		[]string{
+			"src_node_name",
+			"src_node_ip",
+			"src_pod_ip",
+			"target_node_name",
+			"target_node_ip",
+			"target_pod_ip",
+		})
+	nodePMTUGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pinger_node_pmtu",
+			Help: "The path mtu measured by do-not-fragment pings for pod ping node, which is capped by the mtu of the pinger pod",
+		},
+		[]string{
+			"src_node_name",
+			"src_node_ip",
+			"src_pod_ip",
+			"target_node_name",
+			"target_node_ip",
+		})
+	podMTUGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pinger_pod_mtu",
+			Help: "The mtu of the pinger pod, which caps the measured path mtu",
+		},
+		[]string{
+			"nodeName",
+		})
+	encapOverheadGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pinger_encap_overhead_bytes",
+			Help: "The encapsulation overhead of the tunnel between the nodes, which is reserved by the pod mtu",
+		},
+		[]string{
+			"nodeName",
+			"tunnel_type",
+		})
+	tunnelPMTUGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pinger_tunnel_pmtu",
+			Help: "The min path mtu measured across the tunnels from this node",
+		},
+		[]string{
+			"nodeName",
+			"tunnel_type",
+		})
+	tunnelMTUInsufficientGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pinger_tunnel_mtu_insufficient",
+			Help: "If the underlay between the nodes can not carry the packets of the pod mtu with the encapsulation overhead",
+		},
+		[]string{
+			"src_node_name",
+			"target_node_name",
+			"tunnel_type",
+		})
+	subnetMTUGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pinger_subnet_mtu",
+			Help: "The mtu of the overlay subnet, which is the mtu of the pinger pod if not specified",
+		},
+		[]string{
+			"nodeName",
+			"subnet",
+		})
+	subnetMTUExceedsPMTUGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pinger_subnet_mtu_exceeds_pmtu",
+			Help: "If the mtu of the overlay subnet exceeds the path mtu measured across the tunnels from this node",
+		},
+		[]string{
+			"nodeName",
+			"subnet",
+		})
 	externalPingLatencyHistogram = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "pinger_external_ping_latency_ms",
@@ -226,6 +305,52 @@ var (
 			"component",
 		})
 
+	metricOvnControllerStopwatchSamples = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Name:      "ovn_controller_stopwatch_samples_total",
+			Help:      "The number of samples of the ovn-controller stopwatch.",
+		},
+		[]string{
+			"hostname",
+			"stopwatch",
+		})
+
+	metricOvnControllerStopwatchDuration = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Name:      "ovn_controller_stopwatch_duration_ms",
+			Help:      "The statistics of the ovn-controller stopwatch, such as the duration of flow computation and installation. The stat is one of max, min, p95, short_term_avg and long_term_avg. The unit is ms.",
+		},
+		[]string{
+			"hostname",
+			"stopwatch",
+			"stat",
+		})
+
+	metricOvnControllerIncEngineStats = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Name:      "ovn_controller_inc_engine_stats_total",
+			Help:      "The number of recompute, compute and cancel of the ovn-controller incremental processing engine node.",
+		},
+		[]string{
+			"hostname",
+			"node",
+			"stat",
+		})
+
+	metricOvnControllerOpenFlowCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Name:      "ovn_controller_openflow_count",
+			Help:      "The number of OpenFlow flows installed by ovn-controller in the bridge.",
+		},
+		[]string{
+			"hostname",
+			"bridge",
+		})
+
 	metricOvsInfo = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: metricNamespace,
@@ -659,12 +784,24 @@ func InitPingerMetrics() {
 	prometheus.MustRegister(nodePingLatencyHistogram)
 	prometheus.MustRegister(nodePingLostCounter)
 	prometheus.MustRegister(nodePingTotalCounter)
+	prometheus.MustRegister(podPMTUGauge)
+	prometheus.MustRegister(nodePMTUGauge)
+	prometheus.MustRegister(podMTUGauge)
+	prometheus.MustRegister(encapOverheadGauge)
+	prometheus.MustRegister(tunnelPMTUGauge)
+	prometheus.MustRegister(tunnelMTUInsufficientGauge)
+	prometheus.MustRegister(subnetMTUGauge)
+	prometheus.MustRegister(subnetMTUExceedsPMTUGauge)
 	prometheus.MustRegister(externalPingLatencyHistogram)
 	prometheus.MustRegister(externalPingLostCounter)
 
 	// ovs status metrics
 	prometheus.MustRegister(metricOvsHealthyStatus)
 	prometheus.MustRegister(metricOvsInfo)
+	prometheus.MustRegister(metricOvnControllerStopwatchSamples)
+	prometheus.MustRegister(metricOvnControllerStopwatchDuration)
+	prometheus.MustRegister(metricOvnControllerIncEngineStats)
+	prometheus.MustRegister(metricOvnControllerOpenFlowCount)
 	prometheus.MustRegister(metricRequestErrorNums)
 	prometheus.MustRegister(metricLogFileSize)
 	prometheus.MustRegister(metricDbFileSize)
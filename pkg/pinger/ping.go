@@ -81,6 +81,13 @@ func ping(config *Configuration) error {
 			errHappens = true
 		}
 	}
+
+	if config.EnablePMTUCheck && config.Mode != "server" {
+		// the path mtu is checked by StartPMTUChecks in server mode
+		if checkPMTU(config) != nil {
+			errHappens = true
+		}
+	}
 	if errHappens {
 		return fmt.Errorf("ping failed")
 	}
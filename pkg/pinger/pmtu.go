@@ -0,0 +1,293 @@
+package pinger
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	goping "github.com/prometheus-community/pro-bing"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+// pmtuCheckConcurrency is the max number of the path mtu measurements in flight
+const pmtuCheckConcurrency = 8
+
+// encapOverhead returns the bytes added by the tunnel encapsulation, which are reserved by kube-ovn-cni
+// when the mtu of the pods is not specified
+func encapOverhead(tunnelType string, ipv6 bool) int {
+	var overhead int
+	switch tunnelType {
+	case util.NetworkTypeVxlan:
+		overhead = util.VxlanHeaderLength
+	case util.NetworkTypeStt:
+		overhead = util.SttHeaderLength
+	default:
+		overhead = util.GeneveHeaderLength
+	}
+	if ipv6 {
+		// IPv6 header is 20 bytes larger than IPv4 header
+		overhead += 20
+	}
+	return overhead
+}
+
+func localInterfaceMTU(ip string) (int, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		klog.Errorf("failed to list interfaces: %v", err)
+		return 0, err
+	}
+	for _, iface := range interfaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			klog.Errorf("failed to list addresses of interface %s: %v", iface.Name, err)
+			return 0, err
+		}
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.String() == ip {
+				return iface.MTU, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("no interface found with address %s", ip)
+}
+
+// searchPMTU returns the largest size in [lo, hi] accepted by the probe, or 0 if the probe rejects lo
+func searchPMTU(lo, hi int, probe func(size int) bool) int {
+	lo = min(lo, hi)
+	if probe(hi) {
+		return hi
+	}
+	if !probe(lo) {
+		return 0
+	}
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if probe(mid) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// measurePMTU measures the path mtu to the address by do-not-fragment pings, up to the mtu of the pinger pod
+func measurePMTU(addr string, mtu int) int {
+	minMTU, ipHeaderLength := 576, 20
+	if util.CheckProtocol(addr) == kubeovnv1.ProtocolIPv6 {
+		minMTU, ipHeaderLength = 1280, 40
+	}
+	return searchPMTU(minMTU, mtu, func(size int) bool {
+		pinger, err := goping.NewPinger(addr)
+		if err != nil {
+			klog.Errorf("failed to init pinger, %v", err)
+			return false
+		}
+		pinger.SetPrivileged(true)
+		pinger.SetDoNotFragment(true)
+		// 8 bytes of icmp header
+		pinger.Size = size - ipHeaderLength - 8
+		pinger.Timeout = 1 * time.Second
+		pinger.Count = 2
+		pinger.Interval = 100 * time.Millisecond
+		if err = pinger.Run(); err != nil {
+			// the kernel refuses to send the packets larger than the cached path mtu
+			klog.V(3).Infof("failed to ping %s with size %d and do-not-fragment bit: %v", addr, size, err)
+			return false
+		}
+		return pinger.Statistics().PacketsRecv != 0
+	})
+}
+
+type pmtuTarget struct {
+	nodeName string
+	nodeIP   string
+	// podName is empty if the target is the node
+	podName string
+	ip      string
+}
+
+// measurePMTUs measures the path mtu to the targets with bounded concurrency, since each measurement is a sequence
+// of pings which may last several seconds
+func measurePMTUs(targets []pmtuTarget, mtu int, measure func(addr string, mtu int) int) []int {
+	results := make([]int, len(targets))
+	sem := make(chan struct{}, pmtuCheckConcurrency)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, target pmtuTarget) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = measure(target.ip, mtu)
+		}(i, target)
+	}
+	wg.Wait()
+	return results
+}
+
+// StartPMTUChecks checks the path mtu periodically in server mode, apart from the pings as a check lasts long
+func StartPMTUChecks(config *Configuration) {
+	wait.Until(func() {
+		_ = checkPMTU(config)
+	}, time.Duration(config.PMTUCheckInterval)*time.Second, wait.NeverStop)
+}
+
+func checkPMTU(config *Configuration) error {
+	klog.Infof("start to check path mtu")
+	podMTU, err := localInterfaceMTU(config.PodIP)
+	if err != nil {
+		klog.Errorf("failed to get mtu of pod %s: %v", config.PodName, err)
+		return err
+	}
+
+	ds, err := config.KubeClient.AppsV1().DaemonSets(config.DaemonSetNamespace).Get(context.Background(), config.DaemonSetName, metav1.GetOptions{})
+	if err != nil {
+		klog.Errorf("failed to get peer ds: %v", err)
+		return err
+	}
+	pods, err := config.KubeClient.CoreV1().Pods(config.DaemonSetNamespace).List(context.Background(), metav1.ListOptions{LabelSelector: labels.Set(ds.Spec.Selector.MatchLabels).String()})
+	if err != nil {
+		klog.Errorf("failed to list peer pods: %v", err)
+		return err
+	}
+	nodes, err := config.KubeClient.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		klog.Errorf("failed to list nodes, %v", err)
+		return err
+	}
+
+	var targets []pmtuTarget
+	for _, pod := range pods.Items {
+		if pod.Name == config.PodName {
+			continue
+		}
+		for _, podIP := range pod.Status.PodIPs {
+			if slices.Contains(config.PodProtocols, util.CheckProtocol(podIP.IP)) {
+				targets = append(targets, pmtuTarget{nodeName: pod.Spec.NodeName, nodeIP: pod.Status.HostIP, podName: pod.Name, ip: podIP.IP})
+			}
+		}
+	}
+	for _, node := range nodes.Items {
+		for _, addr := range node.Status.Addresses {
+			if addr.Type == v1.NodeInternalIP && slices.Contains(config.PodProtocols, util.CheckProtocol(addr.Address)) {
+				targets = append(targets, pmtuTarget{nodeName: node.Name, nodeIP: addr.Address, ip: addr.Address})
+			}
+		}
+	}
+	results := measurePMTUs(targets, podMTU, measurePMTU)
+
+	podPMTUGauge.Reset()
+	nodePMTUGauge.Reset()
+	tunnelPMTUGauge.Reset()
+	tunnelMTUInsufficientGauge.Reset()
+	subnetMTUGauge.Reset()
+	subnetMTUExceedsPMTUGauge.Reset()
+
+	overhead := encapOverhead(config.TunnelType, util.CheckProtocol(config.HostIP) == kubeovnv1.ProtocolIPv6)
+	podMTUGauge.WithLabelValues(config.NodeName).Set(float64(podMTU))
+	encapOverheadGauge.WithLabelValues(config.NodeName, config.TunnelType).Set(float64(overhead))
+
+	// pathMTU is the min path mtu to the pods on other nodes, which are reached through the tunnels
+	var pathMTU int
+	var checkErr error
+	insufficients := make(map[string]float64)
+	for i, target := range targets {
+		pmtu := results[i]
+		if target.podName == "" {
+			if pmtu == 0 {
+				klog.Errorf("failed to measure path mtu to node %s %s", target.nodeName, target.ip)
+				checkErr = fmt.Errorf("path mtu check failed")
+				continue
+			}
+			klog.Infof("path mtu to node %s %s is %d, pod mtu %d", target.nodeName, target.ip, pmtu, podMTU)
+			nodePMTUGauge.WithLabelValues(config.NodeName, config.HostIP, config.PodIP, target.nodeName, target.ip).Set(float64(pmtu))
+			continue
+		}
+
+		if pmtu == 0 {
+			klog.Errorf("failed to measure path mtu to pod %s %s", target.podName, target.ip)
+			checkErr = fmt.Errorf("path mtu check failed")
+			continue
+		}
+		klog.Infof("path mtu to pod %s %s on node %s is %d, pod mtu %d", target.podName, target.ip, target.nodeName, pmtu, podMTU)
+		podPMTUGauge.WithLabelValues(config.NodeName, config.HostIP, config.PodIP,
+			target.nodeName, target.nodeIP, target.ip).Set(float64(pmtu))
+		if target.nodeName == config.NodeName {
+			continue
+		}
+
+		if pathMTU == 0 || pmtu < pathMTU {
+			pathMTU = pmtu
+		}
+		insufficient := 0.0
+		if pmtu < podMTU {
+			klog.Warningf("the underlay between node %s and node %s can not carry packets of mtu %d with %s overhead %d bytes, path mtu is %d",
+				config.NodeName, target.nodeName, podMTU, config.TunnelType, overhead, pmtu)
+			insufficient = 1
+			checkErr = fmt.Errorf("path mtu check failed")
+		}
+		// the pods on the target node share the gauge, which is insufficient if any of them is
+		insufficients[target.nodeName] = max(insufficients[target.nodeName], insufficient)
+	}
+	for nodeName, insufficient := range insufficients {
+		tunnelMTUInsufficientGauge.WithLabelValues(config.NodeName, nodeName, config.TunnelType).Set(insufficient)
+	}
+
+	if pathMTU != 0 {
+		tunnelPMTUGauge.WithLabelValues(config.NodeName, config.TunnelType).Set(float64(pathMTU))
+		if err = checkSubnetMTU(config, podMTU, pathMTU); err != nil {
+			checkErr = err
+		}
+	}
+	return checkErr
+}
+
+// checkSubnetMTU compares the mtu of the overlay subnets with the path mtu across the tunnels. The path mtu is exact
+// only if it is less than the mtu of the pinger pod, otherwise the subnets with larger mtu can not be checked.
+// Subnets without mtu use the mtu of kube-ovn-cni, which is the same as the one of the pinger pod.
+func checkSubnetMTU(config *Configuration, podMTU, pathMTU int) error {
+	subnets, err := config.KubeOvnClient.KubeovnV1().Subnets().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		klog.Errorf("failed to list subnets: %v", err)
+		return err
+	}
+
+	var checkErr error
+	for _, subnet := range subnets.Items {
+		if subnet.Spec.Vlan != "" {
+			continue
+		}
+		mtu := podMTU
+		if subnet.Spec.Mtu != 0 {
+			mtu = int(subnet.Spec.Mtu)
+		}
+		exceeds := 0.0
+		if mtu > pathMTU {
+			if pathMTU == podMTU {
+				klog.V(3).Infof("skip checking mtu %d of subnet %s which is larger than the mtu %d of pinger pod", mtu, subnet.Name, podMTU)
+				continue
+			}
+			klog.Warningf("mtu %d of subnet %s exceeds the path mtu %d across the %s tunnels from node %s",
+				mtu, subnet.Name, pathMTU, config.TunnelType, config.NodeName)
+			exceeds = 1
+			checkErr = fmt.Errorf("subnet mtu check failed")
+		}
+		subnetMTUGauge.WithLabelValues(config.NodeName, subnet.Name).Set(float64(mtu))
+		subnetMTUExceedsPMTUGauge.WithLabelValues(config.NodeName, subnet.Name).Set(exceeds)
+	}
+	return checkErr
+}
@@ -0,0 +1,106 @@
+package pinger
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/kubeovn/kube-ovn/pkg/util"
+)
+
+func Test_searchPMTU(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		lo   int
+		hi   int
+		pmtu int
+		exp  int
+	}{
+		{"hi accepted", 576, 1400, 1500, 1400},
+		{"pmtu equals hi", 576, 1400, 1400, 1400},
+		{"pmtu in range", 576, 1500, 1450, 1450},
+		{"pmtu next to lo", 576, 1500, 577, 577},
+		{"pmtu equals lo", 576, 1500, 576, 576},
+		{"lo rejected", 576, 1500, 500, 0},
+		{"lo larger than hi", 1280, 1000, 1500, 1000},
+		{"lo larger than hi rejected", 1280, 1000, 900, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			probes := 0
+			pmtu := searchPMTU(tt.lo, tt.hi, func(size int) bool {
+				probes++
+				return size <= tt.pmtu
+			})
+			require.Equal(t, tt.exp, pmtu)
+			// binary search, besides the probes of hi and lo
+			require.LessOrEqual(t, probes, 13)
+		})
+	}
+}
+
+func Test_encapOverhead(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		tunnelType string
+		ipv6       bool
+		exp        int
+	}{
+		{"geneve", util.NetworkTypeGeneve, false, util.GeneveHeaderLength},
+		{"geneve ipv6", util.NetworkTypeGeneve, true, util.GeneveHeaderLength + 20},
+		{"vxlan", util.NetworkTypeVxlan, false, util.VxlanHeaderLength},
+		{"vxlan ipv6", util.NetworkTypeVxlan, true, util.VxlanHeaderLength + 20},
+		{"stt", util.NetworkTypeStt, false, util.SttHeaderLength},
+		{"stt ipv6", util.NetworkTypeStt, true, util.SttHeaderLength + 20},
+		{"default", "", false, util.GeneveHeaderLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.exp, encapOverhead(tt.tunnelType, tt.ipv6))
+		})
+	}
+}
+
+func Test_measurePMTUs(t *testing.T) {
+	t.Parallel()
+
+	var targets []pmtuTarget
+	expected := make(map[string]int)
+	for i := 0; i < 3*pmtuCheckConcurrency; i++ {
+		ip := fmt.Sprintf("10.16.0.%d", i+1)
+		targets = append(targets, pmtuTarget{ip: ip})
+		expected[ip] = 1000 + i
+	}
+
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	results := measurePMTUs(targets, 1400, func(addr string, mtu int) int {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		if mtu != 1400 {
+			return 0
+		}
+		return expected[addr]
+	})
+
+	require.Len(t, results, len(targets))
+	for i, target := range targets {
+		require.Equal(t, expected[target.ip], results[i])
+	}
+	require.LessOrEqual(t, maxInFlight, pmtuCheckConcurrency)
+}
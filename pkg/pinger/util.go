@@ -9,6 +9,8 @@ import (
 
 	"github.com/greenpau/ovsdb"
 	"k8s.io/klog/v2"
+
+	"github.com/kubeovn/kube-ovn/pkg/util"
 )
 
 // IncrementErrorCounter increases the counter of failed queries to OVN server.
@@ -275,3 +277,67 @@ func resetOvsInterfaceMetrics() {
 	interfaceStatTxErrorsTotal.Reset()
 	interfaceStatCollisions.Reset()
 }
+
+func (e *Exporter) ovnControllerAppctl(command string) (string, error) {
+	cmdstr := fmt.Sprintf("ovn-appctl -T %v -t ovn-controller %s", e.Client.Timeout, command)
+	output, err := exec.Command("sh", "-c", cmdstr).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("failed to get output of %s: %v, %q", command, err, output)
+	}
+	return string(output), nil
+}
+
+func (e *Exporter) setOvnControllerStopwatchMetric() {
+	output, err := e.ovnControllerAppctl("stopwatch/show")
+	if err != nil {
+		klog.Error(err)
+		e.IncrementErrorCounter()
+		return
+	}
+	for name, stats := range util.ParseStopwatchStats(output) {
+		metricOvnControllerStopwatchSamples.WithLabelValues(e.Client.System.Hostname, name).Set(stats.Samples)
+		metricOvnControllerStopwatchDuration.WithLabelValues(e.Client.System.Hostname, name, "max").Set(stats.Maximum)
+		metricOvnControllerStopwatchDuration.WithLabelValues(e.Client.System.Hostname, name, "min").Set(stats.Minimum)
+		metricOvnControllerStopwatchDuration.WithLabelValues(e.Client.System.Hostname, name, "p95").Set(stats.P95)
+		metricOvnControllerStopwatchDuration.WithLabelValues(e.Client.System.Hostname, name, "short_term_avg").Set(stats.ShortTermAvg)
+		metricOvnControllerStopwatchDuration.WithLabelValues(e.Client.System.Hostname, name, "long_term_avg").Set(stats.LongTermAvg)
+	}
+}
+
+func (e *Exporter) setOvnControllerIncEngineMetric() {
+	output, err := e.ovnControllerAppctl("inc-engine/show-stats")
+	if err != nil {
+		klog.Error(err)
+		e.IncrementErrorCounter()
+		return
+	}
+	for node, counters := range util.ParseIncEngineStats(output) {
+		for stat, value := range counters {
+			metricOvnControllerIncEngineStats.WithLabelValues(e.Client.System.Hostname, node, stat).Set(value)
+		}
+	}
+}
+
+func (e *Exporter) setOvnControllerOpenFlowCountMetric() {
+	bridge := "br-int"
+	cmdstr := fmt.Sprintf("ovs-ofctl -t %v dump-aggregate %s", e.Client.Timeout, bridge)
+	output, err := exec.Command("sh", "-c", cmdstr).CombinedOutput()
+	if err != nil {
+		klog.Errorf("failed to get output of ovs-ofctl dump-aggregate %s: %v, %q", bridge, err, output)
+		e.IncrementErrorCounter()
+		return
+	}
+	count, err := util.ParseOpenFlowCount(string(output))
+	if err != nil {
+		klog.Error(err)
+		return
+	}
+	metricOvnControllerOpenFlowCount.WithLabelValues(e.Client.System.Hostname, bridge).Set(float64(count))
+}
+
+func resetOvnControllerPerformanceMetrics() {
+	metricOvnControllerStopwatchSamples.Reset()
+	metricOvnControllerStopwatchDuration.Reset()
+	metricOvnControllerIncEngineStats.Reset()
+	metricOvnControllerOpenFlowCount.Reset()
+}
@@ -16,6 +16,8 @@ const (
 	BgpAnnotation        = "ovn.kubernetes.io/bgp"
 	SnatAnnotation       = "ovn.kubernetes.io/snat"
 	EipAnnotation        = "ovn.kubernetes.io/eip"
+	EipPoolAnnotation    = "ovn.kubernetes.io/eip_pool"
+	EipNatGwAnnotation   = "ovn.kubernetes.io/eip_nat_gw"
 	FipFinalizer         = "ovn.kubernetes.io/fip"
 	VipAnnotation        = "ovn.kubernetes.io/vip"
 	AAPsAnnotation       = "ovn.kubernetes.io/aaps"
@@ -28,25 +30,38 @@ const (
 	ExternalGatewayAnnotation    = "ovn.kubernetes.io/external_gateway"
 	ExternalGwPortNameAnnotation = "ovn.kubernetes.io/external_gw_port_name"
 
-	VpcNatGatewayAnnotation     = "ovn.kubernetes.io/vpc_nat_gw"
-	VpcNatGatewayInitAnnotation = "ovn.kubernetes.io/vpc_nat_gw_init"
-	VpcEipsAnnotation           = "ovn.kubernetes.io/vpc_eips"
-	VpcFloatingIPMd5Annotation  = "ovn.kubernetes.io/vpc_floating_ips"
-	VpcDnatMd5Annotation        = "ovn.kubernetes.io/vpc_dnat_md5"
-	VpcSnatMd5Annotation        = "ovn.kubernetes.io/vpc_snat_md5"
-	VpcCIDRsAnnotation          = "ovn.kubernetes.io/vpc_cidrs"
-	VpcLbAnnotation             = "ovn.kubernetes.io/vpc_lb"
-	VpcExternalLabel            = "ovn.kubernetes.io/vpc_external"
-	VpcEipAnnotation            = "ovn.kubernetes.io/vpc_eip"
-	VpcDnatEPortLabel           = "ovn.kubernetes.io/vpc_dnat_eport"
-	VpcNatAnnotation            = "ovn.kubernetes.io/vpc_nat"
-	OvnEipTypeLabel             = "ovn.kubernetes.io/ovn_eip_type"
-	EipV4IpLabel                = "ovn.kubernetes.io/eip_v4_ip"
+	VpcNatGatewayAnnotation            = "ovn.kubernetes.io/vpc_nat_gw"
+	VpcNatGatewayInitAnnotation        = "ovn.kubernetes.io/vpc_nat_gw_init"
+	VpcNatGatewayIgnoredEipsAnnotation = "ovn.kubernetes.io/vpc_nat_gw_ignored_eips"
+	VpcEipsAnnotation                  = "ovn.kubernetes.io/vpc_eips"
+	VpcFloatingIPMd5Annotation         = "ovn.kubernetes.io/vpc_floating_ips"
+	VpcDnatMd5Annotation               = "ovn.kubernetes.io/vpc_dnat_md5"
+	VpcSnatMd5Annotation               = "ovn.kubernetes.io/vpc_snat_md5"
+	VpcCIDRsAnnotation                 = "ovn.kubernetes.io/vpc_cidrs"
+	VpcLbAnnotation                    = "ovn.kubernetes.io/vpc_lb"
+	VpcExternalLabel                   = "ovn.kubernetes.io/vpc_external"
+	VpcEipAnnotation                   = "ovn.kubernetes.io/vpc_eip"
+	VpcDnatEPortLabel                  = "ovn.kubernetes.io/vpc_dnat_eport"
+	VpcNatAnnotation                   = "ovn.kubernetes.io/vpc_nat"
+	OvnEipTypeLabel                    = "ovn.kubernetes.io/ovn_eip_type"
+	OvnEipNameLabel                    = "ovn.kubernetes.io/ovn_eip"
+	EipV4IpLabel                       = "ovn.kubernetes.io/eip_v4_ip"
+	EipPoolLabel                       = "ovn.kubernetes.io/eip_pool"
+	AutoFipLabel                       = "ovn.kubernetes.io/auto_fip"
+	AutoFipOwnerAnnotation             = "ovn.kubernetes.io/auto_fip_owner"
 
 	SwitchLBRuleVipsAnnotation = "ovn.kubernetes.io/switch_lb_vip"
 	SwitchLBRuleVip            = "switch_lb_vip"
 	SwitchLBRuleSubnet         = "switch_lb_subnet"
 
+	LbHealthCheckTypeAnnotation    = "ovn.kubernetes.io/lb_health_check_type"
+	LbHealthCheckPortAnnotation    = "ovn.kubernetes.io/lb_health_check_port"
+	LbHealthCheckPathAnnotation    = "ovn.kubernetes.io/lb_health_check_path"
+	LbHealthCheckTimeoutAnnotation = "ovn.kubernetes.io/lb_health_check_timeout"
+	LbUnhealthyServicesAnnotation  = "ovn.kubernetes.io/lb_unhealthy_services"
+	LbHealthCheckTypeHTTP          = "http"
+	LbHealthCheckTypeGRPC          = "grpc"
+
 	LogicalRouterAnnotation = "ovn.kubernetes.io/logical_router"
 	VpcAnnotation           = "ovn.kubernetes.io/vpc"
 
@@ -96,6 +111,12 @@ const (
 
 	TunnelInterfaceAnnotation = "ovn.kubernetes.io/tunnel_interface"
 
+	ICRouteExportAnnotation = "ovn.kubernetes.io/ic_route_export"
+	ICRouteStatusAnnotation = "ovn.kubernetes.io/ic_route_status"
+	ICSupersededAnnotation  = "ovn.kubernetes.io/ic_superseded"
+
+	LiveMigrationPhaseAnnotation = "ovn.kubernetes.io/live_migration_phase"
+
 	OvsDpTypeLabel = "ovn.kubernetes.io/ovs_dp_type"
 
 	VpcNameLabel               = "ovn.kubernetes.io/vpc"
@@ -167,6 +188,7 @@ const (
 	InterconnectionConfig  = "ovn-ic-config"
 	ExternalGatewayConfig  = "ovn-external-gw-config"
 	InterconnectionSwitch  = "ts"
+	VpcICPeeringSwitch     = "vpc-peer"
 	ExternalGatewaySwitch  = "ovn-external"
 	VpcNatGatewayConfig    = "ovn-vpc-nat-gw-config"
 	VpcLbNetworkAttachment = "ovn-vpc-lb"
@@ -216,6 +238,8 @@ const (
 	VM         = "VirtualMachine"
 	VMInstance = "VirtualMachineInstance"
 
+	VMCreatedByLabel = "kubevirt.io/created-by"
+
 	StatefulSet = "StatefulSet"
 
 	MirrorControlAnnotation = "ovn.kubernetes.io/mirror"
@@ -244,6 +268,8 @@ const (
 	OvnICConnected = "connected"
 	OvnICStatic    = "static"
 	OvnICNone      = ""
+	// OvnICRouteAzKey marks the routes learned from the az by kube-ovn instead of ovn-ic
+	OvnICRouteAzKey = "ic-route-az"
 
 	MatchV4Src = "ip4.src"
 	MatchV4Dst = "ip4.dst"
@@ -286,8 +312,14 @@ const (
 	TProxyPreroutingMark = 0x90004
 	TProxyPreroutingMask = 0x90004
 
+	FirewallBackendIptables = "iptables"
+	FirewallBackendNftables = "nftables"
+
 	HealthCheckNamedVipTemplate = "%s:%s" // ip name, health check vip
 
 	ConsumptionKubevirt       = "kubevirt"
 	VhostUserSocketVolumeName = "vhostuser-sockets"
+
+	// ConnectivityCheckDefaultInterval is the default seconds between two rounds of a connectivity check
+	ConnectivityCheckDefaultInterval = 60
 )
@@ -0,0 +1,116 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// StopwatchStats is the statistics of a stopwatch reported by `ovn-appctl stopwatch/show`,
+// the durations are in milliseconds
+type StopwatchStats struct {
+	Samples      float64
+	Maximum      float64
+	Minimum      float64
+	P95          float64
+	ShortTermAvg float64
+	LongTermAvg  float64
+}
+
+var (
+	stopwatchNameRegex     = regexp.MustCompile(`^Statistics for '(.+)'$`)
+	stopwatchValueRegex    = regexp.MustCompile(`^([\w ]+):\s*([\d.]+)\s*(\w*)$`)
+	incEngineNodeRegex     = regexp.MustCompile(`^Node:\s*(\S+)`)
+	incEngineCounterRegex  = regexp.MustCompile(`-\s*([\w/]+):\s*(\d+)`)
+	openFlowFlowCountRegex = regexp.MustCompile(`flow_count=(\d+)`)
+)
+
+func stopwatchDurationInMs(value float64, unit string) float64 {
+	switch unit {
+	case "usec":
+		return value / 1e3
+	case "nsec":
+		return value / 1e6
+	default:
+		return value
+	}
+}
+
+// ParseStopwatchStats parses the output of `ovn-appctl stopwatch/show`
+func ParseStopwatchStats(output string) map[string]*StopwatchStats {
+	result := make(map[string]*StopwatchStats)
+	var stats *StopwatchStats
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if match := stopwatchNameRegex.FindStringSubmatch(line); match != nil {
+			stats = &StopwatchStats{}
+			result[match[1]] = stats
+			continue
+		}
+		if stats == nil {
+			continue
+		}
+		match := stopwatchValueRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		value, err := strconv.ParseFloat(match[2], 64)
+		if err != nil {
+			continue
+		}
+		switch match[1] {
+		case "Total samples":
+			stats.Samples = value
+		case "Maximum":
+			stats.Maximum = stopwatchDurationInMs(value, match[3])
+		case "Minimum":
+			stats.Minimum = stopwatchDurationInMs(value, match[3])
+		case "95th percentile":
+			stats.P95 = stopwatchDurationInMs(value, match[3])
+		case "Short term average":
+			stats.ShortTermAvg = stopwatchDurationInMs(value, match[3])
+		case "Long term average":
+			stats.LongTermAvg = stopwatchDurationInMs(value, match[3])
+		}
+	}
+	return result
+}
+
+// ParseIncEngineStats parses the output of `ovn-appctl inc-engine/show-stats`,
+// and returns the counters such as recompute, compute and cancel of each engine node
+func ParseIncEngineStats(output string) map[string]map[string]float64 {
+	result := make(map[string]map[string]float64)
+	var counters map[string]float64
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if match := incEngineNodeRegex.FindStringSubmatch(line); match != nil {
+			counters = make(map[string]float64)
+			result[match[1]] = counters
+		}
+		if counters == nil {
+			continue
+		}
+		// the counters may be printed in the same line with the node name
+		for _, match := range incEngineCounterRegex.FindAllStringSubmatch(line, -1) {
+			value, err := strconv.ParseFloat(match[2], 64)
+			if err != nil {
+				continue
+			}
+			counters[match[1]] = value
+		}
+	}
+	return result
+}
+
+// ParseOpenFlowCount parses the flow count from the output of `ovs-ofctl dump-aggregate`
+func ParseOpenFlowCount(output string) (int, error) {
+	match := openFlowFlowCountRegex.FindStringSubmatch(output)
+	if match == nil {
+		return 0, fmt.Errorf("flow_count not found in %q", output)
+	}
+	return strconv.Atoi(match[1])
+}
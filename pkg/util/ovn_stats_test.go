@@ -0,0 +1,59 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStopwatchStats(t *testing.T) {
+	output := `Statistics for 'ovnnorthd_loop'
+  Total samples: 12
+  Maximum: 21 msec
+  Minimum: 1 msec
+  95th percentile: 10.500000 msec
+  Short term average: 3.250000 msec
+  Long term average: 2.500000 msec
+Statistics for 'flow_installation'
+  Total samples: 3
+  Maximum: 500 usec
+  Minimum: 100 usec
+  95th percentile: 450.000000 usec
+  Short term average: 200.000000 usec
+  Long term average: 150.000000 usec
+`
+	stats := ParseStopwatchStats(output)
+	require.Len(t, stats, 2)
+	require.Equal(t, &StopwatchStats{Samples: 12, Maximum: 21, Minimum: 1, P95: 10.5, ShortTermAvg: 3.25, LongTermAvg: 2.5}, stats["ovnnorthd_loop"])
+	require.Equal(t, &StopwatchStats{Samples: 3, Maximum: 0.5, Minimum: 0.1, P95: 0.45, ShortTermAvg: 0.2, LongTermAvg: 0.15}, stats["flow_installation"])
+	require.Empty(t, ParseStopwatchStats(""))
+}
+
+func TestParseIncEngineStats(t *testing.T) {
+	output := `Node: northd
+- recompute:            5
+- compute:             10
+- cancel:               0
+Node: lflow
+- recompute:            2
+- compute:              0
+- cancel:               1
+Node: SB_chassis - recompute: 3 - compute: 4 - abort: 0
+`
+	stats := ParseIncEngineStats(output)
+	require.Equal(t, map[string]map[string]float64{
+		"northd":     {"recompute": 5, "compute": 10, "cancel": 0},
+		"lflow":      {"recompute": 2, "compute": 0, "cancel": 1},
+		"SB_chassis": {"recompute": 3, "compute": 4, "abort": 0},
+	}, stats)
+	require.Empty(t, ParseIncEngineStats(""))
+}
+
+func TestParseOpenFlowCount(t *testing.T) {
+	count, err := ParseOpenFlowCount("NXST_AGGREGATE reply (xid=0x4): packet_count=10 byte_count=1000 flow_count=1234\n")
+	require.NoError(t, err)
+	require.Equal(t, 1234, count)
+
+	_, err = ParseOpenFlowCount("ovs-ofctl: br-int is not a bridge or a socket")
+	require.Error(t, err)
+}
@@ -0,0 +1,99 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+	"k8s.io/klog/v2"
+)
+
+// PodPortForwardDialer returns a dial function connecting to the port of the pod through the port forward
+// subresource, so that the pods in custom vpcs which are unreachable from the host network can be accessed
+func PodPortForwardDialer(client kubernetes.Interface, cfg *rest.Config, namespace, podName string, port int) func(context.Context, string, string) (net.Conn, error) {
+	return func(context.Context, string, string) (net.Conn, error) {
+		transport, upgrader, err := spdy.RoundTripperFor(cfg)
+		if err != nil {
+			klog.Errorf("failed to create spdy round tripper: %v", err)
+			return nil, err
+		}
+		req := client.CoreV1().RESTClient().Post().
+			Resource("pods").
+			Namespace(namespace).
+			Name(podName).
+			SubResource("portforward")
+		dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+		streamConn, _, err := dialer.Dial(portforward.PortForwardProtocolV1Name)
+		if err != nil {
+			klog.Errorf("failed to dial port forward of pod %s/%s: %v", namespace, podName, err)
+			return nil, err
+		}
+
+		headers := http.Header{}
+		headers.Set(corev1.StreamType, corev1.StreamTypeError)
+		headers.Set(corev1.PortHeader, strconv.Itoa(port))
+		headers.Set(corev1.PortForwardRequestIDHeader, "0")
+		errorStream, err := streamConn.CreateStream(headers)
+		if err != nil {
+			klog.Errorf("failed to create error stream of pod %s/%s: %v", namespace, podName, err)
+			streamConn.Close()
+			return nil, err
+		}
+		// we're not writing to this stream
+		errorStream.Close()
+
+		headers.Set(corev1.StreamType, corev1.StreamTypeData)
+		dataStream, err := streamConn.CreateStream(headers)
+		if err != nil {
+			klog.Errorf("failed to create data stream of pod %s/%s: %v", namespace, podName, err)
+			streamConn.Close()
+			return nil, err
+		}
+
+		go func() {
+			if message, err := io.ReadAll(errorStream); err == nil && len(message) != 0 {
+				klog.Errorf("failed to forward port %d of pod %s/%s: %s", port, namespace, podName, string(message))
+				streamConn.Close()
+			}
+		}()
+		return &portForwardConn{stream: dataStream, conn: streamConn, remote: fmt.Sprintf("%s/%s:%d", namespace, podName, port)}, nil
+	}
+}
+
+// portForwardConn wraps the data stream of a port forward connection as a net.Conn
+type portForwardConn struct {
+	stream httpstream.Stream
+	conn   httpstream.Connection
+	remote string
+}
+
+type portForwardAddr string
+
+func (a portForwardAddr) Network() string { return "portforward" }
+func (a portForwardAddr) String() string  { return string(a) }
+
+func (c *portForwardConn) Read(b []byte) (int, error)  { return c.stream.Read(b) }
+func (c *portForwardConn) Write(b []byte) (int, error) { return c.stream.Write(b) }
+
+func (c *portForwardConn) Close() error {
+	c.stream.Close()
+	return c.conn.Close()
+}
+
+func (c *portForwardConn) LocalAddr() net.Addr  { return portForwardAddr("local") }
+func (c *portForwardConn) RemoteAddr() net.Addr { return portForwardAddr(c.remote) }
+
+// the deadlines are not supported by the spdy streams, the timeout of the http client takes effect instead
+func (c *portForwardConn) SetDeadline(time.Time) error      { return nil }
+func (c *portForwardConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *portForwardConn) SetWriteDeadline(time.Time) error { return nil }
@@ -0,0 +1,152 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DnatMaxPorts limits the ports of a dnat rule which are mapped one by one, since each of them takes a load
+// balancer vip of ovn or an iptables rule of the vpc nat gateway
+const DnatMaxPorts = 1024
+
+// PortRange is a range of ports, a single port has the same start and end
+type PortRange struct {
+	Start int
+	End   int
+}
+
+func (r PortRange) String() string {
+	if r.Start == r.End {
+		return strconv.Itoa(r.Start)
+	}
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+func (r PortRange) Size() int {
+	return r.End - r.Start + 1
+}
+
+func (r PortRange) Overlaps(o PortRange) bool {
+	return r.Start <= o.End && o.Start <= r.End
+}
+
+// PortMapping maps an external port range to an internal port range of the same size one by one
+type PortMapping struct {
+	External PortRange
+	Internal PortRange
+}
+
+// Ports splits the mapping into the mappings of single ports
+func (m PortMapping) Ports() []PortMapping {
+	ports := make([]PortMapping, 0, m.External.Size())
+	for i := 0; i < m.External.Size(); i++ {
+		external, internal := m.External.Start+i, m.Internal.Start+i
+		ports = append(ports, PortMapping{
+			External: PortRange{Start: external, End: external},
+			Internal: PortRange{Start: internal, End: internal},
+		})
+	}
+	return ports
+}
+
+func parsePort(port string) (int, error) {
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse port %q: %w", port, err)
+	}
+	if p < 1 || p > 65535 {
+		return 0, fmt.Errorf("%d is not a valid port", p)
+	}
+	return p, nil
+}
+
+// ParsePortRanges parses a comma separated list of ports and port ranges, e.g. 80,443,8000-8100
+func ParsePortRanges(ports string) ([]PortRange, error) {
+	if strings.TrimSpace(ports) == "" {
+		return nil, fmt.Errorf("empty port")
+	}
+	var ranges []PortRange
+	for _, s := range strings.Split(ports, ",") {
+		s = strings.TrimSpace(s)
+		start, end, isRange := strings.Cut(s, "-")
+		var r PortRange
+		var err error
+		if r.Start, err = parsePort(start); err != nil {
+			return nil, err
+		}
+		r.End = r.Start
+		if isRange {
+			if r.End, err = parsePort(end); err != nil {
+				return nil, err
+			}
+			if r.End < r.Start {
+				return nil, fmt.Errorf("invalid port range %q", s)
+			}
+		}
+		for _, o := range ranges {
+			if o.Overlaps(r) {
+				return nil, fmt.Errorf("port range %s overlaps with %s", r, o)
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// ParsePortMappings parses the external and internal ports of a dnat rule, both of them are lists
+// of ports and port ranges, and the ranges at the same position must be of the same size
+func ParsePortMappings(externalPorts, internalPorts string) ([]PortMapping, error) {
+	external, err := ParsePortRanges(externalPorts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid external port %q: %w", externalPorts, err)
+	}
+	internal, err := ParsePortRanges(internalPorts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid internal port %q: %w", internalPorts, err)
+	}
+	if len(external) != len(internal) {
+		return nil, fmt.Errorf("external port %q and internal port %q have different number of ranges", externalPorts, internalPorts)
+	}
+	mappings := make([]PortMapping, 0, len(external))
+	for i := range external {
+		if external[i].Size() != internal[i].Size() {
+			return nil, fmt.Errorf("external port range %s and internal port range %s have different size", external[i], internal[i])
+		}
+		mappings = append(mappings, PortMapping{External: external[i], Internal: internal[i]})
+	}
+	return mappings, nil
+}
+
+// PortMappingsSize returns the number of the ports mapped
+func PortMappingsSize(mappings []PortMapping) int {
+	var size int
+	for _, m := range mappings {
+		size += m.External.Size()
+	}
+	return size
+}
+
+// ShiftedPortsSize returns the number of the ports mapped to different internal ports, each of which takes
+// a rule of single port in the vpc nat gateway, while a range mapped to the same ports takes only one rule
+func ShiftedPortsSize(mappings []PortMapping) int {
+	var size int
+	for _, m := range mappings {
+		if m.External != m.Internal {
+			size += m.External.Size()
+		}
+	}
+	return size
+}
+
+// PortRangesOverlap returns the first range of a overlapping with b, or nil if none of them overlaps
+func PortRangesOverlap(a, b []PortRange) *PortRange {
+	for i := range a {
+		for _, r := range b {
+			if a[i].Overlaps(r) {
+				return &a[i]
+			}
+		}
+	}
+	return nil
+}
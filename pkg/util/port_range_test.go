@@ -0,0 +1,71 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePortRanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		ports   string
+		want    []PortRange
+		wantErr bool
+	}{
+		{"single port", "80", []PortRange{{80, 80}}, false},
+		{"range", "8000-8100", []PortRange{{8000, 8100}}, false},
+		{"list", "80, 443,8000-8100", []PortRange{{80, 80}, {443, 443}, {8000, 8100}}, false},
+		{"empty", "", nil, true},
+		{"invalid port", "http", nil, true},
+		{"out of range", "65536", nil, true},
+		{"zero port", "0", nil, true},
+		{"range from zero port", "0-100", nil, true},
+		{"reversed range", "8100-8000", nil, true},
+		{"overlapping", "8000-8100,8080", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePortRanges(tt.ports)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePortMappings(t *testing.T) {
+	mappings, err := ParsePortMappings("80,8000-8002", "8080,9000-9002")
+	require.NoError(t, err)
+	require.Equal(t, []PortMapping{
+		{External: PortRange{80, 80}, Internal: PortRange{8080, 8080}},
+		{External: PortRange{8000, 8002}, Internal: PortRange{9000, 9002}},
+	}, mappings)
+	require.Equal(t, []PortMapping{
+		{External: PortRange{8000, 8000}, Internal: PortRange{9000, 9000}},
+		{External: PortRange{8001, 8001}, Internal: PortRange{9001, 9001}},
+		{External: PortRange{8002, 8002}, Internal: PortRange{9002, 9002}},
+	}, mappings[1].Ports())
+	require.Equal(t, "8000-8002", mappings[1].External.String())
+
+	require.Equal(t, 4, PortMappingsSize(mappings))
+	require.Equal(t, 4, ShiftedPortsSize(mappings))
+
+	mappings, err = ParsePortMappings("80,8000-8100", "9080,8000-8100")
+	require.NoError(t, err)
+	require.Equal(t, 102, PortMappingsSize(mappings))
+	require.Equal(t, 1, ShiftedPortsSize(mappings))
+
+	_, err = ParsePortMappings("80,443", "80")
+	require.Error(t, err)
+	_, err = ParsePortMappings("8000-8100", "9000-9050")
+	require.Error(t, err)
+}
+
+func TestPortRangesOverlap(t *testing.T) {
+	require.Nil(t, PortRangesOverlap([]PortRange{{80, 80}, {8000, 8100}}, []PortRange{{443, 443}, {8101, 8200}}))
+	require.Equal(t, &PortRange{8000, 8100}, PortRangesOverlap([]PortRange{{80, 80}, {8000, 8100}}, []PortRange{{8100, 8200}}))
+}
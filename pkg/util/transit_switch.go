@@ -0,0 +1,44 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+)
+
+// GetTransitSwitchName returns the name of the nth transit switch connecting the cluster routers
+func GetTransitSwitchName(index int) string {
+	if index == 0 {
+		return InterconnectionSwitch
+	}
+	return fmt.Sprintf("%s%d", InterconnectionSwitch, index)
+}
+
+// IsTransitSwitchName reports whether the name is one of the transit switches connecting the cluster routers,
+// the transit switches of the vpc peerings are excluded
+func IsTransitSwitchName(name string) bool {
+	if name == InterconnectionSwitch {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(name, InterconnectionSwitch)
+	if !ok || suffix == "" {
+		return false
+	}
+	index, err := strconv.Atoi(suffix)
+	return err == nil && index > 0 && GetTransitSwitchName(index) == name
+}
+
+// GetTransitSwitchCIDR returns the subnet of the nth transit switch for the protocol
+func GetTransitSwitchCIDR(protocol string, index int) string {
+	switch protocol {
+	case kubeovnv1.ProtocolIPv4:
+		return fmt.Sprintf("169.254.%d.0/24", 100+index)
+	case kubeovnv1.ProtocolIPv6:
+		return fmt.Sprintf("fe80:a9fe:%02x::/112", 100+index)
+	case kubeovnv1.ProtocolDual:
+		return fmt.Sprintf("169.254.%d.0/24,fe80:a9fe:%02x::/112", 100+index, 100+index)
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package util
+
+import (
+	"testing"
+
+	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+)
+
+func TestGetTransitSwitchName(t *testing.T) {
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{0, "ts"},
+		{1, "ts1"},
+		{12, "ts12"},
+	}
+	for _, tt := range tests {
+		if got := GetTransitSwitchName(tt.index); got != tt.want {
+			t.Errorf("GetTransitSwitchName(%d) = %s, want %s", tt.index, got, tt.want)
+		}
+	}
+}
+
+func TestIsTransitSwitchName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"ts", true},
+		{"ts1", true},
+		{"ts12", true},
+		{"ts0", false},
+		{"ts01", false},
+		{"ts-1", false},
+		{"tsa", false},
+		{"vpc-peer-0123456789ab", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsTransitSwitchName(tt.name); got != tt.want {
+			t.Errorf("IsTransitSwitchName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetTransitSwitchCIDR(t *testing.T) {
+	tests := []struct {
+		protocol string
+		index    int
+		want     string
+	}{
+		{kubeovnv1.ProtocolIPv4, 0, "169.254.100.0/24"},
+		{kubeovnv1.ProtocolIPv4, 2, "169.254.102.0/24"},
+		{kubeovnv1.ProtocolIPv6, 1, "fe80:a9fe:65::/112"},
+		{kubeovnv1.ProtocolDual, 0, "169.254.100.0/24,fe80:a9fe:64::/112"},
+		{"", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := GetTransitSwitchCIDR(tt.protocol, tt.index); got != tt.want {
+			t.Errorf("GetTransitSwitchCIDR(%q, %d) = %s, want %s", tt.protocol, tt.index, got, tt.want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	kubeovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
@@ -357,3 +358,113 @@ func ValidateVpc(vpc *kubeovnv1.Vpc) error {
 
 	return nil
 }
+
+// MaxTransitSwitchCount is the max number of the transit switches, the subnet of the nth one is 169.254.(100+n).0/24
+const MaxTransitSwitchCount = 156
+
+func ValidateInterConnection(ic *kubeovnv1.InterConnection) error {
+	spec := ic.Spec
+	if spec.AzName == "" {
+		return fmt.Errorf("az name is required")
+	}
+
+	if len(spec.ICDBHosts) == 0 {
+		return fmt.Errorf("ovn-ic db hosts are required")
+	}
+	for _, host := range spec.ICDBHosts {
+		if ip := net.ParseIP(host); ip == nil {
+			return fmt.Errorf("invalid ovn-ic db host %s", host)
+		}
+	}
+	for _, port := range []int32{spec.ICNBPort, spec.ICSBPort} {
+		if port < 0 || port > 65535 {
+			return fmt.Errorf("invalid ovn-ic db port %d", port)
+		}
+	}
+
+	if len(spec.GatewayNodes) == 0 {
+		return fmt.Errorf("gateway nodes are required")
+	}
+	nodes := make(map[string]bool, len(spec.GatewayNodes))
+	for _, node := range spec.GatewayNodes {
+		if node == "" {
+			return fmt.Errorf("gateway node name can not be empty")
+		}
+		if nodes[node] {
+			return fmt.Errorf("duplicate gateway node %s", node)
+		}
+		nodes[node] = true
+	}
+
+	if spec.TSCount < 0 || spec.TSCount > MaxTransitSwitchCount {
+		return fmt.Errorf("transit switch count %d is out of range [0, %d]", spec.TSCount, MaxTransitSwitchCount)
+	}
+
+	for az, cidrs := range spec.RouteImports {
+		if az == spec.AzName {
+			return fmt.Errorf("can not import routes from the local az %s", az)
+		}
+		for _, cidr := range cidrs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return fmt.Errorf("invalid cidr %s imported from az %s: %w", cidr, az, err)
+			}
+		}
+	}
+	for az := range spec.RouteTables {
+		if az == spec.AzName {
+			return fmt.Errorf("can not set route table of the local az %s", az)
+		}
+	}
+
+	return nil
+}
+
+func ValidateConnectivityCheck(cc *kubeovnv1.ConnectivityCheck) error {
+	spec := cc.Spec
+	switch spec.Protocol {
+	case kubeovnv1.ConnectivityCheckProtocolICMP, kubeovnv1.ConnectivityCheckProtocolTCP, kubeovnv1.ConnectivityCheckProtocolUDP:
+	default:
+		return fmt.Errorf("invalid protocol %q, must be one of icmp, tcp and udp", spec.Protocol)
+	}
+	if spec.Port < 0 || spec.Port > 65535 {
+		return fmt.Errorf("invalid port %d", spec.Port)
+	}
+	if spec.Interval < 0 {
+		return fmt.Errorf("invalid interval %d", spec.Interval)
+	}
+	if spec.SLO.MaxLossPercent < 0 || spec.SLO.MaxLossPercent > 100 {
+		return fmt.Errorf("max loss percent %d is out of range [0, 100]", spec.SLO.MaxLossPercent)
+	}
+	if spec.SLO.MaxLatency < 0 {
+		return fmt.Errorf("invalid max latency %d", spec.SLO.MaxLatency)
+	}
+
+	for _, selector := range []*metav1.LabelSelector{spec.Source.NodeSelector, spec.Destination.NamespaceSelector, spec.Destination.Selector} {
+		if _, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+			return fmt.Errorf("invalid label selector: %w", err)
+		}
+	}
+
+	dst := spec.Destination
+	selectPods := dst.Selector != nil || len(dst.Subnets) != 0
+	if !selectPods && len(dst.Services) == 0 {
+		return fmt.Errorf("destination selector, subnets or services are required")
+	}
+	for _, svc := range dst.Services {
+		if ns, name, found := strings.Cut(svc, "/"); !found || ns == "" || name == "" {
+			return fmt.Errorf("invalid service %q, must be in the format of namespace/name", svc)
+		}
+	}
+	if spec.Protocol == kubeovnv1.ConnectivityCheckProtocolICMP {
+		if spec.Port != 0 {
+			return fmt.Errorf("port can not be set for icmp checks")
+		}
+		if len(dst.Services) != 0 {
+			return fmt.Errorf("services can not be checked by icmp")
+		}
+	} else if selectPods && spec.Port == 0 {
+		return fmt.Errorf("port is required for %s checks of pods", spec.Protocol)
+	}
+
+	return nil
+}
@@ -817,3 +817,200 @@ func TestValidateCidrConflict(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateInterConnection(t *testing.T) {
+	newIC := func(f func(spec *kubeovnv1.InterConnectionSpec)) *kubeovnv1.InterConnection {
+		ic := &kubeovnv1.InterConnection{
+			ObjectMeta: metav1.ObjectMeta{Name: "ovn-ic"},
+			Spec: kubeovnv1.InterConnectionSpec{
+				AzName:       "az1",
+				ICDBHosts:    []string{"192.168.0.1", "192.168.0.2"},
+				GatewayNodes: []string{"node1", "node2"},
+				TSCount:      3,
+				AutoRoute:    true,
+				RouteImports: map[string][]string{"az2": {"10.17.0.0/16"}},
+				RouteTables:  map[string]string{"az2": "rtb"},
+			},
+		}
+		if f != nil {
+			f(&ic.Spec)
+		}
+		return ic
+	}
+
+	tests := []struct {
+		name string
+		ic   *kubeovnv1.InterConnection
+		err  string
+	}{
+		{
+			name: "base",
+			ic:   newIC(nil),
+		},
+		{
+			name: "missing az name",
+			ic:   newIC(func(spec *kubeovnv1.InterConnectionSpec) { spec.AzName = "" }),
+			err:  "az name is required",
+		},
+		{
+			name: "missing ovn-ic db hosts",
+			ic:   newIC(func(spec *kubeovnv1.InterConnectionSpec) { spec.ICDBHosts = nil }),
+			err:  "ovn-ic db hosts are required",
+		},
+		{
+			name: "invalid ovn-ic db host",
+			ic:   newIC(func(spec *kubeovnv1.InterConnectionSpec) { spec.ICDBHosts = []string{"192.168.0.300"} }),
+			err:  "invalid ovn-ic db host 192.168.0.300",
+		},
+		{
+			name: "invalid ovn-ic db port",
+			ic:   newIC(func(spec *kubeovnv1.InterConnectionSpec) { spec.ICSBPort = 65536 }),
+			err:  "invalid ovn-ic db port 65536",
+		},
+		{
+			name: "missing gateway nodes",
+			ic:   newIC(func(spec *kubeovnv1.InterConnectionSpec) { spec.GatewayNodes = nil }),
+			err:  "gateway nodes are required",
+		},
+		{
+			name: "duplicate gateway nodes",
+			ic:   newIC(func(spec *kubeovnv1.InterConnectionSpec) { spec.GatewayNodes = []string{"node1", "node1"} }),
+			err:  "duplicate gateway node node1",
+		},
+		{
+			name: "too many transit switches",
+			ic:   newIC(func(spec *kubeovnv1.InterConnectionSpec) { spec.TSCount = MaxTransitSwitchCount + 1 }),
+			err:  "transit switch count 157 is out of range [0, 156]",
+		},
+		{
+			name: "import routes from local az",
+			ic: newIC(func(spec *kubeovnv1.InterConnectionSpec) {
+				spec.RouteImports = map[string][]string{"az1": {"10.16.0.0/16"}}
+			}),
+			err: "can not import routes from the local az az1",
+		},
+		{
+			name: "invalid imported cidr",
+			ic: newIC(func(spec *kubeovnv1.InterConnectionSpec) {
+				spec.RouteImports = map[string][]string{"az2": {"10.17.0.0"}}
+			}),
+			err: "invalid cidr 10.17.0.0 imported from az az2: invalid CIDR address: 10.17.0.0",
+		},
+		{
+			name: "route table of local az",
+			ic:   newIC(func(spec *kubeovnv1.InterConnectionSpec) { spec.RouteTables = map[string]string{"az1": "rtb"} }),
+			err:  "can not set route table of the local az az1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateInterConnection(tt.ic)
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateConnectivityCheck(t *testing.T) {
+	newCC := func(f func(spec *kubeovnv1.ConnectivityCheckSpec)) *kubeovnv1.ConnectivityCheck {
+		cc := &kubeovnv1.ConnectivityCheck{
+			ObjectMeta: metav1.ObjectMeta{Name: "cc1"},
+			Spec: kubeovnv1.ConnectivityCheckSpec{
+				Destination: kubeovnv1.ConnectivityCheckDestination{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+					Subnets:  []string{"vpc1-subnet1"},
+				},
+				Protocol: kubeovnv1.ConnectivityCheckProtocolTCP,
+				Port:     80,
+				Interval: 30,
+				SLO:      kubeovnv1.ConnectivityCheckSLO{MaxLossPercent: 1, MaxLatency: 10},
+			},
+		}
+		if f != nil {
+			f(&cc.Spec)
+		}
+		return cc
+	}
+
+	tests := []struct {
+		name string
+		cc   *kubeovnv1.ConnectivityCheck
+		err  string
+	}{
+		{
+			name: "base",
+			cc:   newCC(nil),
+		},
+		{
+			name: "icmp",
+			cc: newCC(func(spec *kubeovnv1.ConnectivityCheckSpec) {
+				spec.Protocol, spec.Port = kubeovnv1.ConnectivityCheckProtocolICMP, 0
+			}),
+		},
+		{
+			name: "udp services without port",
+			cc: newCC(func(spec *kubeovnv1.ConnectivityCheckSpec) {
+				spec.Protocol, spec.Port = kubeovnv1.ConnectivityCheckProtocolUDP, 0
+				spec.Destination = kubeovnv1.ConnectivityCheckDestination{Services: []string{"kube-system/kube-dns"}}
+			}),
+		},
+		{
+			name: "invalid protocol",
+			cc:   newCC(func(spec *kubeovnv1.ConnectivityCheckSpec) { spec.Protocol = "sctp" }),
+			err:  `invalid protocol "sctp", must be one of icmp, tcp and udp`,
+		},
+		{
+			name: "max loss percent out of range",
+			cc:   newCC(func(spec *kubeovnv1.ConnectivityCheckSpec) { spec.SLO.MaxLossPercent = 101 }),
+			err:  "max loss percent 101 is out of range [0, 100]",
+		},
+		{
+			name: "missing destination",
+			cc: newCC(func(spec *kubeovnv1.ConnectivityCheckSpec) {
+				spec.Destination = kubeovnv1.ConnectivityCheckDestination{}
+			}),
+			err: "destination selector, subnets or services are required",
+		},
+		{
+			name: "invalid service",
+			cc: newCC(func(spec *kubeovnv1.ConnectivityCheckSpec) {
+				spec.Destination.Services = []string{"kube-dns"}
+			}),
+			err: `invalid service "kube-dns", must be in the format of namespace/name`,
+		},
+		{
+			name: "icmp services",
+			cc: newCC(func(spec *kubeovnv1.ConnectivityCheckSpec) {
+				spec.Protocol, spec.Port = kubeovnv1.ConnectivityCheckProtocolICMP, 0
+				spec.Destination.Services = []string{"kube-system/kube-dns"}
+			}),
+			err: "services can not be checked by icmp",
+		},
+		{
+			name: "tcp pods without port",
+			cc:   newCC(func(spec *kubeovnv1.ConnectivityCheckSpec) { spec.Port = 0 }),
+			err:  "port is required for tcp checks of pods",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateConnectivityCheck(tt.cc)
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
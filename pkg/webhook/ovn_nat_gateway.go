@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net"
 	"net/http"
-	"strconv"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -50,7 +49,10 @@ func (v *ValidatingHook) ovnEipUpdateHook(ctx context.Context, req admission.Req
 		return ctrlwebhook.Errored(http.StatusBadRequest, err)
 	}
 
-	if eipOld.Spec != eipNew.Spec {
+	// the hairpin can be switched after the eip is ready
+	oldSpec := eipOld.Spec
+	oldSpec.Hairpin = eipNew.Spec.Hairpin
+	if oldSpec != eipNew.Spec {
 		if eipOld.Status.Ready {
 			err := fmt.Errorf("OvnEip not support change")
 			return ctrlwebhook.Errored(http.StatusBadRequest, err)
@@ -62,12 +64,16 @@ func (v *ValidatingHook) ovnEipUpdateHook(ctx context.Context, req admission.Req
 	return ctrlwebhook.Allowed("by pass")
 }
 
-func (v *ValidatingHook) isOvnEipInUse(ctx context.Context, eipV4IP string) (string, error) {
+func (v *ValidatingHook) isOvnEipInUse(ctx context.Context, eip *ovnv1.OvnEip) (string, error) {
 	var err error
 	dnatList := ovnv1.OvnDnatRuleList{}
 	fipList := ovnv1.OvnFipList{}
 	snatList := ovnv1.OvnSnatRuleList{}
-	opts := cli.MatchingLabels{util.EipV4IpLabel: eipV4IP}
+	// ipv6 addresses are not valid label values, ipv6 only eips are referenced by name
+	opts := cli.MatchingLabels{util.OvnEipNameLabel: eip.Name}
+	if eip.Spec.V4Ip != "" {
+		opts = cli.MatchingLabels{util.EipV4IpLabel: eip.Spec.V4Ip}
+	}
 	err = v.cache.List(ctx, &dnatList, opts)
 	if err != nil {
 		klog.Errorf("failed to list ovn dnat, %v", err)
@@ -103,7 +109,7 @@ func (v *ValidatingHook) ovnEipDeleteHook(ctx context.Context, req admission.Req
 
 	if eip.Status.Ready {
 		var err error
-		nat, err := v.isOvnEipInUse(ctx, eip.Spec.V4Ip)
+		nat, err := v.isOvnEipInUse(ctx, &eip)
 		if nat != "" {
 			err = fmt.Errorf("OvnEip %s is still using by ovn nat", eip.Name)
 			return ctrlwebhook.Errored(http.StatusBadRequest, err)
@@ -264,18 +270,27 @@ func (v *ValidatingHook) ValidateOvnEip(ctx context.Context, eip *ovnv1.OvnEip)
 }
 
 func (v *ValidatingHook) ValidateOvnDnat(ctx context.Context, dnat *ovnv1.OvnDnatRule) error {
-	if dnat.Spec.OvnEip == "" {
-		err := fmt.Errorf("should set spec ovnEip")
+	if dnat.Spec.OvnEip == "" && dnat.Spec.EipPool == "" {
+		err := fmt.Errorf("should set spec ovnEip or eipPool")
 		return err
 	}
-	if dnat.Spec.IPName == "" && dnat.Spec.V4Ip == "" {
-		err := fmt.Errorf("should set spec ipName or v4Ip")
+	if dnat.Spec.IPName == "" && dnat.Spec.V4Ip == "" && dnat.Spec.V6Ip == "" {
+		err := fmt.Errorf("should set spec ipName or v4Ip or v6Ip")
 		return err
 	}
-	eip := &ovnv1.OvnEip{}
-	key := types.NamespacedName{Name: dnat.Spec.OvnEip}
-	if err := v.cache.Get(ctx, key, eip); err != nil {
-		return err
+	if dnat.Spec.OvnEip == "" {
+		if err := v.validateEipPool(ctx, dnat.Spec.EipPool); err != nil {
+			return err
+		}
+	} else {
+		eip := &ovnv1.OvnEip{}
+		key := types.NamespacedName{Name: dnat.Spec.OvnEip}
+		if err := v.cache.Get(ctx, key, eip); err != nil {
+			return err
+		}
+		if err := validateOvnNatInternalIPs(eip, dnat.Spec.V4Ip, dnat.Spec.V6Ip); err != nil {
+			return err
+		}
 	}
 
 	if dnat.Spec.ExternalPort == "" {
@@ -288,19 +303,12 @@ func (v *ValidatingHook) ValidateOvnDnat(ctx context.Context, dnat *ovnv1.OvnDna
 		return err
 	}
 
-	if port, err := strconv.Atoi(dnat.Spec.ExternalPort); err != nil {
-		errMsg := fmt.Errorf("failed to parse spec externalPort %s: %v", dnat.Spec.ExternalPort, err)
-		return errMsg
-	} else if port < 0 || port > 65535 {
-		err := fmt.Errorf("spec externalPort %s is not a valid port", dnat.Spec.ExternalPort)
+	mappings, err := util.ParsePortMappings(dnat.Spec.ExternalPort, dnat.Spec.InternalPort)
+	if err != nil {
 		return err
 	}
-
-	if port, err := strconv.Atoi(dnat.Spec.InternalPort); err != nil {
-		errMsg := fmt.Errorf("failed to parse spec internalIP %s: %v", dnat.Spec.InternalPort, err)
-		return errMsg
-	} else if port < 0 || port > 65535 {
-		err := fmt.Errorf("spec internalIP %s is not a valid port", dnat.Spec.InternalPort)
+	if size := util.PortMappingsSize(mappings); size > util.DnatMaxPorts {
+		err := fmt.Errorf("externalPort %s has %d ports, more than the limit %d", dnat.Spec.ExternalPort, size, util.DnatMaxPorts)
 		return err
 	}
 
@@ -310,12 +318,26 @@ func (v *ValidatingHook) ValidateOvnDnat(ctx context.Context, dnat *ovnv1.OvnDna
 		return err
 	}
 
+	dnatList := ovnv1.OvnDnatRuleList{}
+	if err := v.cache.List(ctx, &dnatList); err != nil {
+		klog.Errorf("failed to list ovn dnat, %v", err)
+		return err
+	}
+	for _, d := range dnatList.Items {
+		if dnat.Spec.OvnEip == "" || d.Name == dnat.Name || d.Spec.OvnEip != dnat.Spec.OvnEip || !strings.EqualFold(d.Spec.Protocol, dnat.Spec.Protocol) {
+			continue
+		}
+		if err := checkDnatPortConflict(dnat.Spec.ExternalPort, d.Spec.ExternalPort, d.Name); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (v *ValidatingHook) ValidateOvnSnat(ctx context.Context, snat *ovnv1.OvnSnatRule) error {
-	if snat.Spec.OvnEip == "" {
-		err := fmt.Errorf("should set spec OvnEip")
+	if snat.Spec.OvnEip == "" && snat.Spec.EipPool == "" {
+		err := fmt.Errorf("should set spec OvnEip or eipPool")
 		return err
 	}
 
@@ -324,36 +346,88 @@ func (v *ValidatingHook) ValidateOvnSnat(ctx context.Context, snat *ovnv1.OvnSna
 		return err
 	}
 
-	if snat.Spec.Vpc != "" && snat.Spec.V4IpCidr == "" {
-		err := fmt.Errorf("should set spec v4IpCidr (subnet cidr or ip address) when spec vpc is set")
+	if snat.Spec.Vpc != "" && snat.Spec.V4IpCidr == "" && snat.Spec.V6IpCidr == "" {
+		err := fmt.Errorf("should set spec v4IpCidr or v6IpCidr (subnet cidr or ip address) when spec vpc is set")
+		return err
+	}
+
+	if snat.Spec.Vpc == "" && (snat.Spec.V4IpCidr != "" || snat.Spec.V6IpCidr != "") {
+		err := fmt.Errorf("should set spec vpc when spec v4IpCidr or v6IpCidr is set")
+		return err
+	}
+
+	if snat.Spec.V4IpCidr != "" && util.CheckProtocol(snat.Spec.V4IpCidr) != ovnv1.ProtocolIPv4 {
+		err := fmt.Errorf("spec v4IpCidr %s is not a valid ipv4 cidr or address", snat.Spec.V4IpCidr)
 		return err
 	}
 
-	if snat.Spec.Vpc == "" && snat.Spec.V4IpCidr != "" {
-		err := fmt.Errorf("should set spec vpc when spec v4IpCidr is set")
+	if snat.Spec.V6IpCidr != "" && util.CheckProtocol(snat.Spec.V6IpCidr) != ovnv1.ProtocolIPv6 {
+		err := fmt.Errorf("spec v6IpCidr %s is not a valid ipv6 cidr or address", snat.Spec.V6IpCidr)
 		return err
 	}
 
-	if snat.Spec.VpcSubnet == "" && snat.Spec.IPName == "" && snat.Spec.Vpc == "" && snat.Spec.V4IpCidr == "" {
-		err := fmt.Errorf("should set spec vpcSubnet or ipName or vpc and v4IpCidr at least")
+	if snat.Spec.VpcSubnet == "" && snat.Spec.IPName == "" && snat.Spec.Vpc == "" {
+		err := fmt.Errorf("should set spec vpcSubnet or ipName or vpc and v4IpCidr/v6IpCidr at least")
 		return err
 	}
 
+	if snat.Spec.OvnEip == "" {
+		return v.validateEipPool(ctx, snat.Spec.EipPool)
+	}
 	eip := &ovnv1.OvnEip{}
 	key := types.NamespacedName{Name: snat.Spec.OvnEip}
 	return v.cache.Get(ctx, key, eip)
 }
 
 func (v *ValidatingHook) ValidateOvnFip(ctx context.Context, fip *ovnv1.OvnFip) error {
-	if fip.Spec.OvnEip == "" {
-		err := fmt.Errorf("should set spec ovnEip")
+	if fip.Spec.OvnEip == "" && fip.Spec.EipPool == "" {
+		err := fmt.Errorf("should set spec ovnEip or eipPool")
 		return err
 	}
-	if fip.Spec.IPName == "" && fip.Spec.V4Ip == "" {
-		err := fmt.Errorf("should set spec ipName or v4Ip")
+	if fip.Spec.IPName == "" && fip.Spec.V4Ip == "" && fip.Spec.V6Ip == "" {
+		err := fmt.Errorf("should set spec ipName or v4Ip or v6Ip")
 		return err
 	}
+	if fip.Spec.OvnEip == "" {
+		return v.validateEipPool(ctx, fip.Spec.EipPool)
+	}
 	eip := &ovnv1.OvnEip{}
 	key := types.NamespacedName{Name: fip.Spec.OvnEip}
-	return v.cache.Get(ctx, key, eip)
+	if err := v.cache.Get(ctx, key, eip); err != nil {
+		return err
+	}
+	return validateOvnNatInternalIPs(eip, fip.Spec.V4Ip, fip.Spec.V6Ip)
+}
+
+// validateEipPool checks the eip pool to allocate the eip of the nat rule from exists
+func (v *ValidatingHook) validateEipPool(ctx context.Context, poolName string) error {
+	pool := &ovnv1.IPPool{}
+	key := types.NamespacedName{Name: poolName}
+	return v.cache.Get(ctx, key, pool)
+}
+
+// validateOvnNatInternalIPs checks the ip family of the internal ips and that the eip can serve them
+func validateOvnNatInternalIPs(eip *ovnv1.OvnEip, v4Ip, v6Ip string) error {
+	if v4Ip != "" && util.CheckProtocol(v4Ip) != ovnv1.ProtocolIPv4 {
+		err := fmt.Errorf("spec v4Ip %s is not a valid ipv4 address", v4Ip)
+		return err
+	}
+	if v6Ip != "" && util.CheckProtocol(v6Ip) != ovnv1.ProtocolIPv6 {
+		err := fmt.Errorf("spec v6Ip %s is not a valid ipv6 address", v6Ip)
+		return err
+	}
+
+	eipV4, eipV6 := eip.Spec.V4Ip, eip.Spec.V6Ip
+	if eipV4 == "" && eipV6 == "" {
+		eipV4, eipV6 = eip.Status.V4Ip, eip.Status.V6Ip
+	}
+	if eipV4 == "" && eipV6 == "" {
+		// eip not allocated yet, checked again by the controller
+		return nil
+	}
+	if (v4Ip == "" || eipV4 == "") && (v6Ip == "" || eipV6 == "") && (v4Ip != "" || v6Ip != "") {
+		err := fmt.Errorf("ovn eip %s has no ip of the same family as %s", eip.Name, util.GetStringIP(v4Ip, v6Ip))
+		return err
+	}
+	return nil
 }
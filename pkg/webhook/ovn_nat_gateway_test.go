@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ovnv1 "github.com/kubeovn/kube-ovn/pkg/apis/kubeovn/v1"
+)
+
+func Test_validateOvnNatInternalIPs(t *testing.T) {
+	t.Parallel()
+
+	newEip := func(specV4, specV6, statusV4, statusV6 string) *ovnv1.OvnEip {
+		return &ovnv1.OvnEip{
+			ObjectMeta: metav1.ObjectMeta{Name: "eip"},
+			Spec:       ovnv1.OvnEipSpec{V4Ip: specV4, V6Ip: specV6},
+			Status:     ovnv1.OvnEipStatus{V4Ip: statusV4, V6Ip: statusV6},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		eip     *ovnv1.OvnEip
+		v4Ip    string
+		v6Ip    string
+		wantErr bool
+	}{
+		{"v4 eip with v4 ip", newEip("172.18.0.10", "", "", ""), "10.0.1.5", "", false},
+		{"v6 eip with v6 ip", newEip("", "fc00::10", "", ""), "", "fd00::5", false},
+		{"dual stack eip with dual stack ip", newEip("172.18.0.10", "fc00::10", "", ""), "10.0.1.5", "fd00::5", false},
+		{"dual stack eip with v6 ip", newEip("172.18.0.10", "fc00::10", "", ""), "", "fd00::5", false},
+		{"v4 eip with dual stack ip", newEip("172.18.0.10", "", "", ""), "10.0.1.5", "fd00::5", false},
+		{"v4 eip with v6 ip", newEip("172.18.0.10", "", "", ""), "", "fd00::5", true},
+		{"v6 eip with v4 ip", newEip("", "fc00::10", "", ""), "10.0.1.5", "", true},
+		{"eip allocated in status", newEip("", "", "", "fc00::10"), "10.0.1.5", "", true},
+		{"eip not allocated", newEip("", "", "", ""), "10.0.1.5", "", false},
+		{"no internal ip", newEip("172.18.0.10", "", "", ""), "", "", false},
+		{"v6 address as v4 ip", newEip("172.18.0.10", "fc00::10", "", ""), "fd00::5", "", true},
+		{"v4 address as v6 ip", newEip("172.18.0.10", "fc00::10", "", ""), "", "10.0.1.5", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateOvnNatInternalIPs(tt.eip, tt.v4Ip, tt.v6Ip)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net"
 	"net/http"
-	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -94,7 +93,11 @@ func (v *ValidatingHook) iptablesEIPUpdateHook(ctx context.Context, req admissio
 		return ctrlwebhook.Errored(http.StatusBadRequest, err)
 	}
 
-	if eipOld.Spec != eipNew.Spec {
+	// the hairpin and the connection limit can be changed after the eip is ready
+	oldSpec := eipOld.Spec
+	oldSpec.Hairpin = eipNew.Spec.Hairpin
+	oldSpec.ConnLimit = eipNew.Spec.ConnLimit
+	if oldSpec != eipNew.Spec {
 		if eipOld.Status.Ready && eipNew.Status.Redo == eipOld.Status.Redo {
 			err := fmt.Errorf("IptablesEIP \"%s\" is ready,not support change", eipNew.Name)
 			return ctrlwebhook.Errored(http.StatusBadRequest, err)
@@ -351,6 +354,22 @@ func (v *ValidatingHook) ValidateVpcNatGW(ctx context.Context, gw *ovnv1.VpcNatG
 		return err
 	}
 
+	if gw.Spec.Replicas < 0 {
+		err := fmt.Errorf("replicas %d must not be negative", gw.Spec.Replicas)
+		return err
+	}
+	switch gw.Spec.Mode {
+	case "":
+		if gw.Spec.Replicas > 1 {
+			err := fmt.Errorf("parameter \"mode\" must be specified when replicas is greater than 1")
+			return err
+		}
+	case ovnv1.VpcNatGwModeActiveStandby, ovnv1.VpcNatGwModeECMP:
+	default:
+		err := fmt.Errorf("invalid mode: %s, supported params: \"%s\", \"%s\"", gw.Spec.Mode, ovnv1.VpcNatGwModeActiveStandby, ovnv1.VpcNatGwModeECMP)
+		return err
+	}
+
 	for _, t := range gw.Spec.Tolerations {
 		if t.Operator != corev1.TolerationOpExists &&
 			t.Operator != corev1.TolerationOpEqual {
@@ -438,13 +457,7 @@ func (v *ValidatingHook) ValidateIptablesEIP(ctx context.Context, eip *ovnv1.Ipt
 }
 
 func (v *ValidatingHook) ValidateIptablesDnat(ctx context.Context, dnat *ovnv1.IptablesDnatRule) error {
-	if dnat.Spec.EIP == "" {
-		err := fmt.Errorf("parameter \"eip\" cannot be empty")
-		return err
-	}
-	eip := &ovnv1.IptablesEIP{}
-	key := types.NamespacedName{Name: dnat.Spec.EIP}
-	if err := v.cache.Get(ctx, key, eip); err != nil {
+	if err := v.validateIptablesNatEip(ctx, dnat.Spec.EIP, dnat.Spec.EipPool, dnat.Spec.NatGwDp); err != nil {
 		return err
 	}
 
@@ -458,19 +471,12 @@ func (v *ValidatingHook) ValidateIptablesDnat(ctx context.Context, dnat *ovnv1.I
 		return err
 	}
 
-	if port, err := strconv.Atoi(dnat.Spec.ExternalPort); err != nil {
-		errMsg := fmt.Errorf("failed to parse externalPort %s: %v", dnat.Spec.ExternalPort, err)
-		return errMsg
-	} else if port < 0 || port > 65535 {
-		err := fmt.Errorf("externalPort %s is not a valid port", dnat.Spec.ExternalPort)
+	mappings, err := util.ParsePortMappings(dnat.Spec.ExternalPort, dnat.Spec.InternalPort)
+	if err != nil {
 		return err
 	}
-
-	if port, err := strconv.Atoi(dnat.Spec.InternalPort); err != nil {
-		errMsg := fmt.Errorf("failed to parse internalIP %s: %v", dnat.Spec.InternalPort, err)
-		return errMsg
-	} else if port < 0 || port > 65535 {
-		err := fmt.Errorf("internalIP %s is not a valid port", dnat.Spec.InternalPort)
+	if size := util.ShiftedPortsSize(mappings); size > util.DnatMaxPorts {
+		err := fmt.Errorf("externalPort %s has %d ports mapped to different internal ports, more than the limit %d", dnat.Spec.ExternalPort, size, util.DnatMaxPorts)
 		return err
 	}
 
@@ -485,17 +491,41 @@ func (v *ValidatingHook) ValidateIptablesDnat(ctx context.Context, dnat *ovnv1.I
 		return err
 	}
 
+	dnatList := ovnv1.IptablesDnatRuleList{}
+	if err := v.cache.List(ctx, &dnatList); err != nil {
+		return err
+	}
+	for _, d := range dnatList.Items {
+		if dnat.Spec.EIP == "" || d.Name == dnat.Name || d.Spec.EIP != dnat.Spec.EIP || !strings.EqualFold(d.Spec.Protocol, dnat.Spec.Protocol) {
+			continue
+		}
+		if err := checkDnatPortConflict(dnat.Spec.ExternalPort, d.Spec.ExternalPort, d.Name); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (v *ValidatingHook) ValidateIptablesSnat(ctx context.Context, snat *ovnv1.IptablesSnatRule) error {
-	if snat.Spec.EIP == "" {
-		err := fmt.Errorf("parameter \"eip\" cannot be empty")
+// checkDnatPortConflict checks whether the external ports overlap with the ones used by another dnat of the same eip
+func checkDnatPortConflict(externalPort, usedPort, usedBy string) error {
+	ports, err := util.ParsePortRanges(externalPort)
+	if err != nil {
 		return err
 	}
-	eip := &ovnv1.IptablesEIP{}
-	key := types.NamespacedName{Name: snat.Spec.EIP}
-	if err := v.cache.Get(ctx, key, eip); err != nil {
+	used, err := util.ParsePortRanges(usedPort)
+	if err != nil {
+		// invalid rules are not applied
+		return nil
+	}
+	if r := util.PortRangesOverlap(ports, used); r != nil {
+		return fmt.Errorf("externalPort %s conflicts with the ports %s of dnat %s on the same eip", r, usedPort, usedBy)
+	}
+	return nil
+}
+
+func (v *ValidatingHook) ValidateIptablesSnat(ctx context.Context, snat *ovnv1.IptablesSnatRule) error {
+	if err := v.validateIptablesNatEip(ctx, snat.Spec.EIP, snat.Spec.EipPool, snat.Spec.NatGwDp); err != nil {
 		return err
 	}
 
@@ -507,13 +537,7 @@ func (v *ValidatingHook) ValidateIptablesSnat(ctx context.Context, snat *ovnv1.I
 }
 
 func (v *ValidatingHook) ValidateIptablesFip(ctx context.Context, fip *ovnv1.IptablesFIPRule) error {
-	if fip.Spec.EIP == "" {
-		err := fmt.Errorf("parameter \"eip\" cannot be empty")
-		return err
-	}
-	eip := &ovnv1.IptablesEIP{}
-	key := types.NamespacedName{Name: fip.Spec.EIP}
-	if err := v.cache.Get(ctx, key, eip); err != nil {
+	if err := v.validateIptablesNatEip(ctx, fip.Spec.EIP, fip.Spec.EipPool, fip.Spec.NatGwDp); err != nil {
 		return err
 	}
 
@@ -524,3 +548,26 @@ func (v *ValidatingHook) ValidateIptablesFip(ctx context.Context, fip *ovnv1.Ipt
 
 	return nil
 }
+
+// validateIptablesNatEip checks the eip of the nat rule, or the eip pool and the nat gateway to allocate the eip from
+func (v *ValidatingHook) validateIptablesNatEip(ctx context.Context, eipName, poolName, natGwDp string) error {
+	if eipName != "" {
+		eip := &ovnv1.IptablesEIP{}
+		key := types.NamespacedName{Name: eipName}
+		return v.cache.Get(ctx, key, eip)
+	}
+	if poolName == "" {
+		err := fmt.Errorf("parameter \"eip\" or \"eipPool\" cannot be empty")
+		return err
+	}
+	if natGwDp == "" {
+		err := fmt.Errorf("parameter \"natGwDp\" cannot be empty when \"eipPool\" is set")
+		return err
+	}
+	gw := &ovnv1.VpcNatGateway{}
+	key := types.NamespacedName{Name: natGwDp}
+	if err := v.cache.Get(ctx, key, gw); err != nil {
+		return err
+	}
+	return v.validateEipPool(ctx, poolName)
+}
@@ -48,6 +48,9 @@ func NewValidatingHook(client client.Client, scheme *runtime.Scheme, cache cache
 	updateHooks[vpcGVK] = v.VpcUpdateHook
 	deleteHooks[vpcGVK] = v.VpcDeleteHook
 
+	createHooks[interConnectionGVK] = v.InterConnectionCreateHook
+	updateHooks[interConnectionGVK] = v.InterConnectionUpdateHook
+
 	createHooks[ipGVK] = v.IPCreateHook
 	updateHooks[ipGVK] = v.IPUpdateHook
 